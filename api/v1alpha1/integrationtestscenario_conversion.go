@@ -17,12 +17,26 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
 	"github.com/konflux-ci/integration-service/api/v1beta2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
+const (
+	// DroppedEnvironmentAnnotation is set on the converted IntegrationTestScenario when the deprecated
+	// v1alpha1 environment can't be represented in the Hub version. It contains the dropped environment
+	// serialized as JSON, so it can be restored when converting back to v1alpha1.
+	DroppedEnvironmentAnnotation = "test.appstudio.openshift.io/v1alpha1-dropped-environment"
+
+	// bundlesResolver is the name of the Tekton resolver used for the v1alpha1 pipeline and bundle
+	bundlesResolver = "bundles"
+)
+
 func (r *IntegrationTestScenario) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -32,13 +46,17 @@ func (r *IntegrationTestScenario) SetupWebhookWithManager(mgr ctrl.Manager) erro
 // ConvertTo converts this ITS to the Hub version (v1beta2).
 func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta2.IntegrationTestScenario)
-	dst.ObjectMeta = src.ObjectMeta
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
 	dst.Spec.Application = src.Spec.Application
 	dst.Status = v1beta2.IntegrationTestScenarioStatus{Conditions: make([]metav1.Condition, 0)}
 
 	if src.Spec.Params != nil {
 		for _, par := range src.Spec.Params {
-			dst.Spec.Params = append(dst.Spec.Params, v1beta2.PipelineParameter(par))
+			dst.Spec.Params = append(dst.Spec.Params, v1beta2.PipelineParameter{
+				Name:   par.Name,
+				Value:  par.Value,
+				Values: par.Values,
+			})
 		}
 	}
 	if src.Spec.Contexts != nil {
@@ -51,8 +69,27 @@ func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.Conditions = append(dst.Status.Conditions, src.Status.Conditions...)
 	}
 
+	if err := dst.RestoreDroppedSpec(); err != nil {
+		return err
+	}
+
+	// the Hub version has no environment, keep the dropped one in an annotation so the loss is visible
+	if !reflect.DeepEqual(src.Spec.Environment, TestEnvironment{}) {
+		environment, err := json.Marshal(src.Spec.Environment)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the environment of IntegrationTestScenario %s: %w", src.Name, err)
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[DroppedEnvironmentAnnotation] = string(environment)
+	}
+
+	if src.Spec.Bundle == "" && src.Spec.Pipeline == "" {
+		return nil
+	}
 	dst.Spec.ResolverRef = v1beta2.ResolverRef{
-		Resolver: "bundles",
+		Resolver: bundlesResolver,
 		Params: []v1beta2.ResolverParameter{
 			{
 				Name:  "bundle",
@@ -73,11 +110,21 @@ func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 
 func (dst *IntegrationTestScenario) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta2.IntegrationTestScenario)
-	dst.ObjectMeta = src.ObjectMeta
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
 	dst.Spec.Application = src.Spec.Application
+	// v1alpha1 can't represent most of the Hub spec, keep it in an annotation so an update through v1alpha1 doesn't wipe it.
+	// Only the bundles resolver maps to the v1alpha1 pipeline and bundle, the other resolvers are kept too.
+	representsResolverRef := src.Spec.ResolverRef.Resolver == "" || src.Spec.ResolverRef.Resolver == bundlesResolver
+	if err := src.SetDroppedSpecAnnotation(&dst.ObjectMeta, representsResolverRef); err != nil {
+		return err
+	}
 	if src.Spec.Params != nil {
 		for _, par := range src.Spec.Params {
-			dst.Spec.Params = append(dst.Spec.Params, PipelineParameter(par))
+			dst.Spec.Params = append(dst.Spec.Params, PipelineParameter{
+				Name:   par.Name,
+				Value:  par.Value,
+				Values: par.Values,
+			})
 		}
 	}
 	if src.Spec.Contexts != nil {
@@ -86,7 +133,21 @@ func (dst *IntegrationTestScenario) ConvertFrom(srcRaw conversion.Hub) error {
 		}
 	}
 
-	if src.Spec.ResolverRef.Resolver == "bundles" {
+	if src.Status.Conditions != nil {
+		dst.Status.Conditions = append(dst.Status.Conditions, src.Status.Conditions...)
+	}
+
+	if environment, ok := dst.Annotations[DroppedEnvironmentAnnotation]; ok {
+		if err := json.Unmarshal([]byte(environment), &dst.Spec.Environment); err != nil {
+			return fmt.Errorf("failed to unmarshal the dropped environment of IntegrationTestScenario %s: %w", src.Name, err)
+		}
+		delete(dst.Annotations, DroppedEnvironmentAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	if src.Spec.ResolverRef.Resolver == bundlesResolver {
 		for _, par := range src.Spec.ResolverRef.Params {
 			if par.Name == "bundle" {
 				dst.Spec.Bundle = par.Value
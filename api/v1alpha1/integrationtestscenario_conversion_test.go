@@ -0,0 +1,365 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("IntegrationTestScenario conversion", func() {
+	var scenario *IntegrationTestScenario
+
+	BeforeEach(func() {
+		scenario = &IntegrationTestScenario{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-pass",
+				Namespace: "default",
+				Labels: map[string]string{
+					"test.appstudio.openshift.io/optional": "false",
+				},
+			},
+			Spec: IntegrationTestScenarioSpec{
+				Application: "application-sample",
+				Bundle:      "quay.io/redhat-appstudio/example-tekton-bundle:integration-pipeline-pass",
+				Pipeline:    "integration-pipeline-pass",
+				Params: []PipelineParameter{
+					{Name: "string-param", Value: "value"},
+					{Name: "array-param", Values: []string{"first", "second"}},
+				},
+				Contexts: []TestContext{
+					{Name: "application", Description: "Application testing"},
+				},
+			},
+			Status: IntegrationTestScenarioStatus{
+				Conditions: []metav1.Condition{
+					{Type: "IntegrationTestScenarioValid", Status: metav1.ConditionTrue, Reason: "Valid"},
+				},
+			},
+		}
+	})
+
+	It("converts the pipeline and bundle to an equivalent bundles resolver", func() {
+		hub := &v1beta2.IntegrationTestScenario{}
+		Expect(scenario.ConvertTo(hub)).To(Succeed())
+
+		Expect(hub.Name).To(Equal(scenario.Name))
+		Expect(hub.Spec.Application).To(Equal("application-sample"))
+		Expect(hub.Spec.ResolverRef).To(Equal(v1beta2.ResolverRef{
+			Resolver: "bundles",
+			Params: []v1beta2.ResolverParameter{
+				{Name: "bundle", Value: "quay.io/redhat-appstudio/example-tekton-bundle:integration-pipeline-pass"},
+				{Name: "name", Value: "integration-pipeline-pass"},
+				{Name: "kind", Value: "pipeline"},
+			},
+		}))
+		Expect(hub.Spec.Params).To(Equal([]v1beta2.PipelineParameter{
+			{Name: "string-param", Value: "value"},
+			{Name: "array-param", Values: []string{"first", "second"}},
+		}))
+		Expect(hub.Spec.Contexts).To(Equal([]v1beta2.TestContext{
+			{Name: "application", Description: "Application testing"},
+		}))
+		Expect(hub.Status.Conditions).To(Equal(scenario.Status.Conditions))
+		Expect(hub.Annotations).NotTo(HaveKey(DroppedEnvironmentAnnotation))
+	})
+
+	It("doesn't set a resolver when neither the pipeline nor the bundle is set", func() {
+		scenario.Spec.Bundle = ""
+		scenario.Spec.Pipeline = ""
+
+		hub := &v1beta2.IntegrationTestScenario{}
+		Expect(scenario.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.ResolverRef).To(Equal(v1beta2.ResolverRef{}))
+
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec).To(Equal(scenario.Spec))
+	})
+
+	It("converts a scenario with only a bundle", func() {
+		scenario.Spec.Pipeline = ""
+
+		hub := &v1beta2.IntegrationTestScenario{}
+		Expect(scenario.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.ResolverRef.Resolver).To(Equal("bundles"))
+		Expect(hub.Spec.ResolverRef.Params).To(ContainElement(v1beta2.ResolverParameter{Name: "name", Value: ""}))
+
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec).To(Equal(scenario.Spec))
+	})
+
+	It("drops the environment with an annotation noting the loss", func() {
+		scenario.Spec.Environment = TestEnvironment{
+			Name: "envname",
+			Type: applicationapiv1alpha1.EnvironmentType_POC,
+			Configuration: &applicationapiv1alpha1.EnvironmentConfiguration{
+				Env: []applicationapiv1alpha1.EnvVarPair{{Name: "var_name", Value: "test"}},
+			},
+		}
+
+		hub := &v1beta2.IntegrationTestScenario{}
+		Expect(scenario.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).To(HaveKey(DroppedEnvironmentAnnotation))
+		Expect(hub.Annotations[DroppedEnvironmentAnnotation]).To(ContainSubstring(`"name":"envname"`))
+		Expect(scenario.Annotations).NotTo(HaveKey(DroppedEnvironmentAnnotation))
+
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.Environment).To(Equal(scenario.Spec.Environment))
+		Expect(converted.Annotations).To(BeNil())
+		Expect(hub.Annotations).To(HaveKey(DroppedEnvironmentAnnotation))
+	})
+
+	It("fails to convert a scenario with a malformed dropped environment annotation", func() {
+		hub := &v1beta2.IntegrationTestScenario{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "example-pass",
+				Annotations: map[string]string{DroppedEnvironmentAnnotation: "{"},
+			},
+		}
+
+		converted := &IntegrationTestScenario{}
+		err := converted.ConvertFrom(hub)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to unmarshal the dropped environment"))
+	})
+
+	It("round-trips the scenario through the Hub version", func() {
+		scenario.Spec.Environment = TestEnvironment{Name: "envname", Type: applicationapiv1alpha1.EnvironmentType_POC}
+
+		hub := &v1beta2.IntegrationTestScenario{}
+		Expect(scenario.ConvertTo(hub)).To(Succeed())
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+		Expect(converted.ObjectMeta).To(Equal(scenario.ObjectMeta))
+		Expect(converted.Spec).To(Equal(scenario.Spec))
+		Expect(converted.Status).To(Equal(scenario.Status))
+	})
+
+	Describe("the Hub spec fields v1alpha1 can't represent", func() {
+		var hub *v1beta2.IntegrationTestScenario
+
+		BeforeEach(func() {
+			hub = &v1beta2.IntegrationTestScenario{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-pass",
+					Namespace: "default",
+				},
+				Spec: v1beta2.IntegrationTestScenarioSpec{
+					Application: "application-sample",
+					ResolverRef: v1beta2.ResolverRef{
+						Resolver: "bundles",
+						Params: []v1beta2.ResolverParameter{
+							{Name: "bundle", Value: "quay.io/redhat-appstudio/example-tekton-bundle:integration-pipeline-pass"},
+							{Name: "name", Value: "integration-pipeline-pass"},
+							{Name: "kind", Value: "pipeline"},
+						},
+					},
+					Params: []v1beta2.PipelineParameter{
+						{Name: "string-param", Value: "value"},
+					},
+				},
+			}
+		})
+
+		DescribeTable("are kept in an annotation and restored when converting back",
+			func(setField func(spec *v1beta2.IntegrationTestScenarioSpec)) {
+				setField(&hub.Spec)
+
+				converted := &IntegrationTestScenario{}
+				Expect(converted.ConvertFrom(hub)).To(Succeed())
+				Expect(converted.Annotations).To(HaveKey(v1beta2.DroppedSpecAnnotation))
+				Expect(hub.Annotations).NotTo(HaveKey(v1beta2.DroppedSpecAnnotation))
+
+				restored := &v1beta2.IntegrationTestScenario{}
+				Expect(converted.ConvertTo(restored)).To(Succeed())
+				Expect(restored.Spec).To(Equal(hub.Spec))
+				Expect(restored.Annotations).To(BeNil())
+				Expect(converted.Annotations).To(HaveKey(v1beta2.DroppedSpecAnnotation))
+			},
+			Entry("timeouts", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Timeouts = &v1beta2.TestTimeouts{Pipeline: "2h", Tasks: "1h30m", Finally: "30m"}
+			}),
+			Entry("workspaces", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Workspaces = []v1beta2.TestWorkspace{
+					{Name: "cache", EmptyDir: true},
+					{Name: "credentials", Secret: &corev1.SecretVolumeSource{SecretName: "credentials"}},
+				}
+			}),
+			Entry("componentSelector", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.ComponentSelector = &v1beta2.ComponentSelector{
+					Names:         []string{"component-sample"},
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+				}
+			}),
+			Entry("dependsOn", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.DependsOn = []string{"example-build"}
+			}),
+			Entry("exportResults", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.ExportResults = []string{"IMAGE_URL"}
+			}),
+			Entry("suspend", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Suspend = true
+			}),
+			Entry("taskRunSpecs", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.TaskRunSpecs = []v1beta2.TaskRunSpec{
+					{
+						PipelineTaskName: "test",
+						ComputeResources: &corev1.ResourceRequirements{Claims: []corev1.ResourceClaim{{Name: "gpu"}}},
+						StepSpecs:        []v1beta2.TaskRunStepSpec{{Name: "run"}},
+					},
+				}
+			}),
+			Entry("pipelineRunTTL", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.PipelineRunTTL = "72h"
+			}),
+			Entry("watchdogDeadline", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.WatchdogDeadline = "48h"
+			}),
+			Entry("podTemplate", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				runAsNonRoot := true
+				spec.PodTemplate = &v1beta2.PodTemplate{
+					NodeSelector:    map[string]string{"node-type": "tests"},
+					Tolerations:     []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+					SecurityContext: &v1beta2.PodSecurityContext{RunAsNonRoot: &runAsNonRoot},
+				}
+			}),
+			Entry("schedule", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Schedule = "0 2 * * *"
+			}),
+			Entry("targetBranches", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.TargetBranches = []string{"main", "release-.*"}
+			}),
+			Entry("matrix", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Matrix = []v1beta2.MatrixParam{{Name: "arch", Values: []string{"amd64", "arm64"}}}
+			}),
+			Entry("the valueFrom of params", func(spec *v1beta2.IntegrationTestScenarioSpec) {
+				spec.Params = append(spec.Params, v1beta2.PipelineParameter{
+					Name: "token",
+					ValueFrom: &v1beta2.ParamValueSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "test-credentials"},
+							Key:                  "token",
+						},
+					},
+				})
+			}),
+		)
+
+		It("doesn't annotate the converted scenario when no field is dropped", func() {
+			converted := &IntegrationTestScenario{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted.Annotations).To(BeNil())
+		})
+
+		It("keeps the changes made through v1alpha1", func() {
+			hub.Spec.Suspend = true
+			hub.Spec.Params = append(hub.Spec.Params, v1beta2.PipelineParameter{
+				Name:      "token",
+				ValueFrom: &v1beta2.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "token"}},
+			})
+
+			converted := &IntegrationTestScenario{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			converted.Spec.Application = "application-other"
+			converted.Spec.Params[1].Value = "literal-token"
+
+			restored := &v1beta2.IntegrationTestScenario{}
+			Expect(converted.ConvertTo(restored)).To(Succeed())
+			Expect(restored.Spec.Application).To(Equal("application-other"))
+			Expect(restored.Spec.Suspend).To(BeTrue())
+			Expect(restored.Spec.Params).To(Equal([]v1beta2.PipelineParameter{
+				{Name: "string-param", Value: "value"},
+				{Name: "token", Value: "literal-token"},
+			}))
+		})
+
+		It("fails to convert a scenario with a malformed dropped spec annotation", func() {
+			scenario.Annotations = map[string]string{v1beta2.DroppedSpecAnnotation: "{"}
+
+			hub := &v1beta2.IntegrationTestScenario{}
+			err := scenario.ConvertTo(hub)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to unmarshal the dropped spec"))
+		})
+	})
+
+	It("doesn't convert the resolvers other than bundles to a pipeline and bundle", func() {
+		hub := &v1beta2.IntegrationTestScenario{
+			Spec: v1beta2.IntegrationTestScenarioSpec{
+				Application: "application-sample",
+				ResolverRef: v1beta2.ResolverRef{
+					Resolver: "git",
+					Params: []v1beta2.ResolverParameter{
+						{Name: "url", Value: "https://github.com/redhat-appstudio/integration-examples.git"},
+						{Name: "revision", Value: "main"},
+						{Name: "pathInRepo", Value: "pipelines/integration_resolver_pipeline_pass.yaml"},
+					},
+				},
+			},
+		}
+
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.Application).To(Equal("application-sample"))
+		Expect(converted.Spec.Bundle).To(BeEmpty())
+		Expect(converted.Spec.Pipeline).To(BeEmpty())
+	})
+
+	It("round-trips the resolvers other than bundles through v1alpha1", func() {
+		hub := &v1beta2.IntegrationTestScenario{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-pass",
+				Namespace: "default",
+			},
+			Spec: v1beta2.IntegrationTestScenarioSpec{
+				Application: "application-sample",
+				ResolverRef: v1beta2.ResolverRef{
+					Resolver: "git",
+					Params: []v1beta2.ResolverParameter{
+						{Name: "url", Value: "https://github.com/redhat-appstudio/integration-examples.git"},
+						{Name: "revision", Value: "main"},
+						{Name: "pathInRepo", Value: "pipelines/integration_resolver_pipeline_pass.yaml"},
+					},
+				},
+			},
+		}
+
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Annotations).To(HaveKey(v1beta2.DroppedSpecAnnotation))
+
+		restored := &v1beta2.IntegrationTestScenario{}
+		Expect(converted.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec).To(Equal(hub.Spec))
+		Expect(restored.Annotations).To(BeNil())
+
+		By("letting a bundle set through v1alpha1 replace the restored resolver")
+		converted.Spec.Bundle = "quay.io/redhat-appstudio/example-tekton-bundle:integration-pipeline-pass"
+		converted.Spec.Pipeline = "integration-pipeline-pass"
+		restored = &v1beta2.IntegrationTestScenario{}
+		Expect(converted.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec.ResolverRef.Resolver).To(Equal("bundles"))
+	})
+})
@@ -33,13 +33,17 @@ func (r *IntegrationTestScenario) SetupWebhookWithManager(mgr ctrl.Manager) erro
 // ConvertTo converts this ITS to the Hub version (v1beta2).
 func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta2.IntegrationTestScenario)
-	dst.ObjectMeta = src.ObjectMeta
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
 	dst.Spec.Application = src.Spec.Application
 	dst.Status = v1beta2.IntegrationTestScenarioStatus{Conditions: make([]metav1.Condition, 0)}
 
 	if src.Spec.Params != nil {
 		for _, par := range src.Spec.Params {
-			dst.Spec.Params = append(dst.Spec.Params, v1beta2.PipelineParameter(par))
+			dst.Spec.Params = append(dst.Spec.Params, v1beta2.PipelineParameter{
+				Name:   par.Name,
+				Value:  par.Value,
+				Values: par.Values,
+			})
 		}
 	}
 	if src.Spec.Contexts != nil {
@@ -52,6 +56,10 @@ func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.Conditions = append(dst.Status.Conditions, src.Status.Conditions...)
 	}
 
+	if err := dst.RestoreDroppedSpec(); err != nil {
+		return err
+	}
+
 	dst.Spec.ResolverRef = v1beta2.ResolverRef{
 		Resolver: src.Spec.ResolverRef.Resolver,
 	}
@@ -70,12 +78,20 @@ func (src *IntegrationTestScenario) ConvertTo(dstRaw conversion.Hub) error {
 
 func (dst *IntegrationTestScenario) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta2.IntegrationTestScenario)
-	dst.ObjectMeta = src.ObjectMeta
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
 	dst.Spec.Application = src.Spec.Application
+	// v1beta1 can't represent all the Hub spec, keep the rest in an annotation so an update through v1beta1 doesn't wipe it
+	if err := src.SetDroppedSpecAnnotation(&dst.ObjectMeta, true); err != nil {
+		return err
+	}
 
 	if src.Spec.Params != nil {
 		for _, par := range src.Spec.Params {
-			dst.Spec.Params = append(dst.Spec.Params, PipelineParameter(par))
+			dst.Spec.Params = append(dst.Spec.Params, PipelineParameter{
+				Name:   par.Name,
+				Value:  par.Value,
+				Values: par.Values,
+			})
 		}
 	}
 	if src.Spec.Contexts != nil {
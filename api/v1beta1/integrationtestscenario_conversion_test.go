@@ -0,0 +1,111 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("IntegrationTestScenario conversion", func() {
+	var hub *v1beta2.IntegrationTestScenario
+
+	BeforeEach(func() {
+		hub = &v1beta2.IntegrationTestScenario{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-pass",
+				Namespace: "default",
+				Labels: map[string]string{
+					"test.appstudio.openshift.io/optional": "false",
+				},
+			},
+			Spec: v1beta2.IntegrationTestScenarioSpec{
+				Application: "application-sample",
+				ResolverRef: v1beta2.ResolverRef{
+					Resolver: "git",
+					Params: []v1beta2.ResolverParameter{
+						{Name: "url", Value: "https://github.com/redhat-appstudio/integration-examples.git"},
+						{Name: "revision", Value: "main"},
+						{Name: "pathInRepo", Value: "pipelines/integration_resolver_pipeline_pass.yaml"},
+					},
+				},
+				Params: []v1beta2.PipelineParameter{
+					{Name: "string-param", Value: "value"},
+					{Name: "array-param", Values: []string{"first", "second"}},
+					{
+						Name: "token",
+						ValueFrom: &v1beta2.ParamValueSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-credentials"},
+								Key:                  "token",
+							},
+						},
+					},
+					{
+						Name: "endpoint",
+						ValueFrom: &v1beta2.ParamValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-config"},
+								Key:                  "endpoint",
+							},
+						},
+					},
+				},
+				Contexts: []v1beta2.TestContext{
+					{Name: "application", Description: "Application testing"},
+				},
+			},
+		}
+	})
+
+	It("round-trips the scenario and the valueFrom of its params through v1beta1", func() {
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.Params).To(Equal([]PipelineParameter{
+			{Name: "string-param", Value: "value"},
+			{Name: "array-param", Values: []string{"first", "second"}},
+			{Name: "token"},
+			{Name: "endpoint"},
+		}))
+		Expect(converted.Annotations).To(HaveKey(v1beta2.DroppedSpecAnnotation))
+
+		restored := &v1beta2.IntegrationTestScenario{}
+		Expect(converted.ConvertTo(restored)).To(Succeed())
+		Expect(restored.ObjectMeta).To(Equal(hub.ObjectMeta))
+		Expect(restored.Spec).To(Equal(hub.Spec))
+	})
+
+	It("doesn't restore the valueFrom of the params removed or given a value through v1beta1", func() {
+		converted := &IntegrationTestScenario{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		converted.Spec.Params = []PipelineParameter{
+			{Name: "string-param", Value: "value"},
+			{Name: "token", Value: "literal-token"},
+		}
+
+		restored := &v1beta2.IntegrationTestScenario{}
+		Expect(converted.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec.Params).To(Equal([]v1beta2.PipelineParameter{
+			{Name: "string-param", Value: "value"},
+			{Name: "token", Value: "literal-token"},
+		}))
+		Expect(restored.Annotations).NotTo(HaveKey(v1beta2.DroppedSpecAnnotation))
+	})
+})
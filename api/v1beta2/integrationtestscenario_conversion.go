@@ -16,5 +16,106 @@ limitations under the License.
 
 package v1beta2
 
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DroppedSpecAnnotation is set on the IntegrationTestScenarios converted from the Hub version to an older version
+// which can't represent all the fields of the spec. It contains the dropped fields serialized as JSON, so they can
+// be restored when converting back to the Hub version and an update through an older version doesn't wipe them.
+const DroppedSpecAnnotation = "test.appstudio.openshift.io/v1beta2-dropped-spec"
+
+// representableSpecFields are the JSON names of the fields of the spec which all the older versions can represent
+var representableSpecFields = []string{"application", "contexts"}
+
+// resolverRefSpecField is the JSON name of the resolverRef field of the spec, which only some of the older
+// versions can represent
+const resolverRefSpecField = "resolverRef"
+
 // Hub marks this type as a conversion hub.
 func (*IntegrationTestScenario) Hub() {}
+
+// SetDroppedSpecAnnotation keeps the fields of the spec of the scenario which the older versions can't represent,
+// including the valueFrom of the params, in the DroppedSpecAnnotation of the given metadata of the converted scenario.
+// The resolverRef is kept too unless the older version can represent it.
+func (r *IntegrationTestScenario) SetDroppedSpecAnnotation(dst *metav1.ObjectMeta, representsResolverRef bool) error {
+	droppedSpec := r.Spec.DeepCopy()
+	droppedSpec.Params = nil
+	for _, param := range r.Spec.Params {
+		if param.ValueFrom != nil {
+			droppedSpec.Params = append(droppedSpec.Params, PipelineParameter{Name: param.Name, ValueFrom: param.ValueFrom})
+		}
+	}
+
+	spec, err := json.Marshal(droppedSpec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the spec of IntegrationTestScenario %s: %w", r.Name, err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(spec, &fields); err != nil {
+		return fmt.Errorf("failed to unmarshal the spec of IntegrationTestScenario %s: %w", r.Name, err)
+	}
+	for _, field := range representableSpecFields {
+		delete(fields, field)
+	}
+	if representsResolverRef {
+		delete(fields, resolverRefSpecField)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	dropped, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the dropped spec of IntegrationTestScenario %s: %w", r.Name, err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[DroppedSpecAnnotation] = string(dropped)
+	return nil
+}
+
+// RestoreDroppedSpec restores the fields of the spec kept in the DroppedSpecAnnotation of the scenario converted
+// from an older version and removes the annotation. The valueFrom of a param is restored only when the param
+// still exists and has no value set through the older version, and the resolverRef only when it wasn't set
+// through the older version.
+func (r *IntegrationTestScenario) RestoreDroppedSpec() error {
+	dropped, ok := r.Annotations[DroppedSpecAnnotation]
+	if !ok {
+		return nil
+	}
+
+	droppedSpec := IntegrationTestScenarioSpec{}
+	if err := json.Unmarshal([]byte(dropped), &droppedSpec); err != nil {
+		return fmt.Errorf("failed to unmarshal the dropped spec of IntegrationTestScenario %s: %w", r.Name, err)
+	}
+	droppedSpec.Application = r.Spec.Application
+	if droppedSpec.ResolverRef.Resolver == "" || r.Spec.ResolverRef.Resolver != "" {
+		droppedSpec.ResolverRef = r.Spec.ResolverRef
+	}
+	droppedSpec.Contexts = r.Spec.Contexts
+
+	params := r.Spec.Params
+	for i, param := range params {
+		if param.Value != "" || len(param.Values) > 0 {
+			continue
+		}
+		for _, droppedParam := range droppedSpec.Params {
+			if droppedParam.Name == param.Name {
+				params[i].ValueFrom = droppedParam.ValueFrom
+			}
+		}
+	}
+	droppedSpec.Params = params
+	r.Spec = droppedSpec
+
+	delete(r.Annotations, DroppedSpecAnnotation)
+	if len(r.Annotations) == 0 {
+		r.Annotations = nil
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -33,11 +34,94 @@ type IntegrationTestScenarioSpec struct {
 	Params []PipelineParameter `json:"params,omitempty"`
 	// Contexts where this IntegrationTestScenario can be applied
 	Contexts []TestContext `json:"contexts,omitempty"`
+	// Timeouts for the integration test PipelineRuns created for this IntegrationTestScenario,
+	// overriding the default timeouts of the integration service
+	Timeouts *TestTimeouts `json:"timeouts,omitempty"`
+	// Workspaces to bind to the integration test PipelineRuns created for this IntegrationTestScenario
+	Workspaces []TestWorkspace `json:"workspaces,omitempty"`
+	// ComponentSelector restricts the IntegrationTestScenario to the Snapshots created for the selected components
+	ComponentSelector *ComponentSelector `json:"componentSelector,omitempty"`
+	// DependsOn lists the IntegrationTestScenarios of the Application which have to pass
+	// before the integration test PipelineRun of this IntegrationTestScenario is created
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// ExportResults lists the results of the integration test PipelineRuns of this IntegrationTestScenario
+	// which are exported to the Snapshot once they pass, so the params of the IntegrationTestScenarios depending
+	// on it can reference them as {{ results.<scenario>.<result> }}
+	ExportResults []string `json:"exportResults,omitempty"`
+	// Suspend stops the creation of integration test PipelineRuns for this IntegrationTestScenario,
+	// the Snapshots created while the scenario is suspended report it as skipped
+	Suspend bool `json:"suspend,omitempty"`
+	// TaskRunSpecs override the compute resources of the tasks of the integration test PipelineRuns
+	// created for this IntegrationTestScenario
+	TaskRunSpecs []TaskRunSpec `json:"taskRunSpecs,omitempty"`
+	// PipelineRunTTL is the time, defined as a Go duration string (e.g. "72h"), after which the finished integration
+	// test PipelineRuns of this IntegrationTestScenario are deleted once their status was reported,
+	// overriding the default TTL of the integration service
+	PipelineRunTTL string `json:"pipelineRunTTL,omitempty"`
+	// WatchdogDeadline is the time, defined as a Go duration string (e.g. "48h"), after which the integration tests
+	// of this IntegrationTestScenario still in progress are failed by the watchdog of the integration service unless
+	// their PipelineRun is actively progressing, overriding the default hard deadline of the integration service
+	WatchdogDeadline string `json:"watchdogDeadline,omitempty"`
+	// PodTemplate is applied to the pods of the integration test PipelineRuns created for this IntegrationTestScenario,
+	// e.g. to schedule them on tainted nodes
+	PodTemplate *PodTemplate `json:"podTemplate,omitempty"`
+	// Schedule is a cron expression (e.g. "0 2 * * *"), evaluated in UTC, which periodically re-runs this
+	// IntegrationTestScenario against the latest push Snapshot of the Application
+	Schedule string `json:"schedule,omitempty"`
+	// TargetBranches restricts the IntegrationTestScenario to the Snapshots built for the listed target branches,
+	// the entries are exact branch names or regular expressions matching the whole branch name
+	TargetBranches []string `json:"targetBranches,omitempty"`
+	// Matrix fans out the IntegrationTestScenario over the combinations of the values of the listed params,
+	// an integration test PipelineRun is created and reported separately for each combination
+	Matrix []MatrixParam `json:"matrix,omitempty"`
 }
 
 // IntegrationTestScenarioStatus defines the observed state of IntegrationTestScenario
 type IntegrationTestScenarioStatus struct {
 	Conditions []metav1.Condition `json:"conditions"`
+	// RecentRuns contains the most recent finished integration test PipelineRuns of the IntegrationTestScenario,
+	// ordered from the oldest to the newest and capped at MaxRecentRuns entries
+	RecentRuns []ScenarioRun `json:"recentRuns,omitempty"`
+	// LastScheduleTime is the time when the last scheduled run of the IntegrationTestScenario was triggered
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastResolvedRefCheckTime is the time when the resolution of the pipeline referenced by the
+	// IntegrationTestScenario was last checked
+	// +optional
+	LastResolvedRefCheckTime *metav1.Time `json:"lastResolvedRefCheckTime,omitempty"`
+	// LastRunTime is the completion time of the most recently finished integration test PipelineRun
+	// of the IntegrationTestScenario
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// LastRunSnapshot is the Snapshot tested by the most recently finished integration test PipelineRun
+	// +optional
+	LastRunSnapshot string `json:"lastRunSnapshot,omitempty"`
+	// LastRunOutcome is the integration test status of the most recently finished integration test PipelineRun,
+	// e.g. TestPassed or TestFail
+	// +optional
+	LastRunOutcome string `json:"lastRunOutcome,omitempty"`
+	// LastRunPipelineRun is the name of the most recently finished integration test PipelineRun
+	// +optional
+	LastRunPipelineRun string `json:"lastRunPipelineRun,omitempty"`
+}
+
+// MaxRecentRuns is the maximum number of runs kept in the status of the IntegrationTestScenario
+const MaxRecentRuns = 10
+
+// ScenarioRun contains the outcome of a finished integration test PipelineRun of the IntegrationTestScenario
+type ScenarioRun struct {
+	// Snapshot which was tested by the PipelineRun
+	Snapshot string `json:"snapshot"`
+	// PipelineRun is the name of the integration test PipelineRun
+	PipelineRun string `json:"pipelineRun"`
+	// Outcome is the integration test status of the PipelineRun, e.g. TestPassed or TestFail
+	Outcome string `json:"outcome"`
+	// CompletionTime is the time when the PipelineRun finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration is the time it took the PipelineRun to finish
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
 // PipelineParameter contains the name and values of a Tekton Pipeline parameter
@@ -45,6 +129,132 @@ type PipelineParameter struct {
 	Name   string   `json:"name"`
 	Value  string   `json:"value,omitempty"`
 	Values []string `json:"values,omitempty"`
+	// ValueFrom sources the value of the parameter from a Secret or a ConfigMap in the namespace
+	// of the IntegrationTestScenario, it can't be combined with Value or Values
+	// +optional
+	ValueFrom *ParamValueSource `json:"valueFrom,omitempty"`
+}
+
+// ParamValueSource references the source of the value of a Tekton Pipeline parameter,
+// exactly one of its fields has to be set
+type ParamValueSource struct {
+	// SecretKeyRef selects a key of a Secret
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// ConfigMapKeyRef selects a key of a ConfigMap
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// MaxMatrixCombinations is the maximum number of combinations of the matrix params of the IntegrationTestScenario
+const MaxMatrixCombinations = 16
+
+// MatrixParam contains the name of a Tekton Pipeline parameter and the list of its values the
+// IntegrationTestScenario is fanned out over
+type MatrixParam struct {
+	// Name of the pipeline parameter
+	// +required
+	Name string `json:"name"`
+	// Values of the pipeline parameter, each value is passed to a separate integration test PipelineRun
+	// +required
+	Values []string `json:"values"`
+}
+
+// TestTimeouts contains the Tekton PipelineRun timeouts, defined as Go duration strings (e.g. "1h30m")
+type TestTimeouts struct {
+	// Pipeline sets the maximum allowed duration for execution of the entire pipeline
+	Pipeline string `json:"pipeline,omitempty"`
+	// Tasks sets the maximum allowed duration of the pipeline's tasks
+	Tasks string `json:"tasks,omitempty"`
+	// Finally sets the maximum allowed duration of the pipeline's finally tasks
+	Finally string `json:"finally,omitempty"`
+}
+
+// TestWorkspace contains the name and the volume source of a workspace bound to the integration test PipelineRun.
+// Exactly one of the volume sources has to be specified.
+type TestWorkspace struct {
+	// Name of the workspace declared by the test pipeline
+	// +required
+	Name string `json:"name"`
+	// EmptyDir backs the workspace with an emptyDir volume shared by the tasks of the PipelineRun
+	EmptyDir bool `json:"emptyDir,omitempty"`
+	// VolumeClaimTemplate is a template for a PersistentVolumeClaim created for each PipelineRun
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	VolumeClaimTemplate *corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+	// Secret backs the workspace with a Secret
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+	// ConfigMap backs the workspace with a ConfigMap
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+}
+
+// TaskRunSpec overrides the compute resources of a pipeline task of the integration test PipelineRun,
+// mirroring a subset of Tekton's PipelineTaskRunSpec
+type TaskRunSpec struct {
+	// PipelineTaskName is the name of the pipeline task to override
+	// +required
+	PipelineTaskName string `json:"pipelineTaskName"`
+	// ComputeResources of the TaskRun of the pipeline task
+	// +optional
+	ComputeResources *corev1.ResourceRequirements `json:"computeResources,omitempty"`
+	// StepSpecs override the compute resources of the steps of the pipeline task
+	// +optional
+	StepSpecs []TaskRunStepSpec `json:"stepSpecs,omitempty"`
+}
+
+// TaskRunStepSpec overrides the compute resources of a step of a pipeline task
+type TaskRunStepSpec struct {
+	// Name of the step to override
+	// +required
+	Name string `json:"name"`
+	// ComputeResources of the step
+	ComputeResources corev1.ResourceRequirements `json:"computeResources"`
+}
+
+// PodTemplate configures the scheduling and security context of the pods of the integration test PipelineRun,
+// mirroring a subset of Tekton's pod template
+type PodTemplate struct {
+	// NodeSelector must match the labels of the nodes the pods are scheduled on
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the pods
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// SecurityContext of the pods
+	// +optional
+	SecurityContext *PodSecurityContext `json:"securityContext,omitempty"`
+}
+
+// PodSecurityContext contains the subset of the pod security context which can be set for the integration test PipelineRun
+type PodSecurityContext struct {
+	// RunAsNonRoot indicates that the containers must run as a non-root user
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+	// RunAsUser is the UID to run the entrypoint of the containers
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// RunAsGroup is the GID to run the entrypoint of the containers
+	// +optional
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+	// FSGroup is the supplemental group applied to the volumes of the pods
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+}
+
+// ComponentSelector selects the components by their names and/or labels, a component is selected
+// when it matches any of the criteria. A selector without any criteria selects all components.
+type ComponentSelector struct {
+	// Names of the selected components
+	Names []string `json:"names,omitempty"`
+	// LabelSelector selects the components by their labels
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 }
 
 // TestContext contains the name and values of a Test context
@@ -17,43 +17,549 @@ limitations under the License.
 package v1beta2
 
 import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/integration-service/pkg/schedule"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+const (
+	// componentContextPrefix prefixes the contexts which apply only to the Snapshots of the named component
+	componentContextPrefix = "component_"
+
+	// negatedContextPrefix prefixes the contexts which exclude the Snapshots the context applies to
+	negatedContextPrefix = "!"
+)
+
+// ReservedParams are the params injected into the integration PipelineRuns by the integration service, they describe
+// the Snapshot and the git event which triggered it. IntegrationTestScenarios can't define params with these names.
+var ReservedParams = []string{"SNAPSHOT", "COMPONENTS", "NAMESPACE",
+	"GIT_SOURCE_BRANCH", "GIT_TARGET_BRANCH", "EVENT_TYPE", "PULL_REQUEST_NUMBER"}
+
 func (r *IntegrationTestScenario) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&integrationTestScenarioValidator{client: mgr.GetAPIReader()}).
 		Complete()
 }
 
 //+kubebuilder:webhook:path=/validate-appstudio-redhat-com-v1beta2-integrationtestscenario,mutating=false,failurePolicy=fail,sideEffects=None,groups=appstudio.redhat.com,resources=integrationtestscenarios,verbs=create;update;delete,versions=v1beta2,name=vintegrationtestscenario.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &IntegrationTestScenario{}
+// integrationTestScenarioValidator validates the IntegrationTestScenarios, the client is used to read
+// the other IntegrationTestScenarios of the Application when validating the scenario dependencies
+type integrationTestScenarioValidator struct {
+	client client.Reader
+}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *IntegrationTestScenario) ValidateCreate() (warnings admission.Warnings, err error) {
-	// We use the DNS-1035 format for application names, so ensure it conforms to that specification
+var _ webhook.CustomValidator = &integrationTestScenarioValidator{}
 
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *integrationTestScenarioValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
+	r, ok := obj.(*IntegrationTestScenario)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IntegrationTestScenario but got a %T", obj))
+	}
+
+	// We use the DNS-1035 format for application names, so ensure it conforms to that specification
 	if len(validation.IsDNS1035Label(r.Name)) != 0 {
 		return nil, field.Invalid(field.NewPath("metadata").Child("name"), r.Name,
 			"an IntegrationTestScenario resource name must start with a lower case "+
 				"alphabetical character, be under 63 characters, and can only consist "+
 				"of lower case alphanumeric characters or ‘-’")
 	}
-	return nil, nil
+	if err := r.validateSpec(nil); err != nil {
+		return nil, err
+	}
+	if err := v.validateDependencies(ctx, r); err != nil {
+		return nil, err
+	}
+	return v.warnAboutMissingContextComponents(ctx, r), nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *IntegrationTestScenario) ValidateUpdate(old runtime.Object) (warnings admission.Warnings, err error) {
-	return nil, nil
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *integrationTestScenarioValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (warnings admission.Warnings, err error) {
+	r, ok := newObj.(*IntegrationTestScenario)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IntegrationTestScenario but got a %T", newObj))
+	}
+	old, ok := oldObj.(*IntegrationTestScenario)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IntegrationTestScenario but got a %T", oldObj))
+	}
+
+	// the scenarios being deleted and the metadata-only updates, e.g. removing the finalizer, aren't validated again,
+	// so the scenarios created before a validation was introduced can still be updated and deleted
+	if r.DeletionTimestamp != nil || equality.Semantic.DeepEqual(r.Spec, old.Spec) {
+		return nil, nil
+	}
+
+	if err := r.validateSpec(old); err != nil {
+		return nil, err
+	}
+	if err := v.validateDependencies(ctx, r); err != nil {
+		return nil, err
+	}
+	return v.warnAboutMissingContextComponents(ctx, r), nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *IntegrationTestScenario) ValidateDelete() (warnings admission.Warnings, err error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *integrationTestScenarioValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
 	return nil, nil
 }
+
+// validateDependencies ensures that the IntegrationTestScenario doesn't depend on itself,
+// neither directly nor through the other IntegrationTestScenarios of its Application
+func (v *integrationTestScenarioValidator) validateDependencies(ctx context.Context, r *IntegrationTestScenario) error {
+	if len(r.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	scenarios := &IntegrationTestScenarioList{}
+	if err := v.client.List(ctx, scenarios, client.InNamespace(r.Namespace)); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to list IntegrationTestScenarios: %w", err))
+	}
+	dependencies := map[string][]string{}
+	for _, scenario := range scenarios.Items {
+		if scenario.Spec.Application == r.Spec.Application {
+			dependencies[scenario.Name] = scenario.Spec.DependsOn
+		}
+	}
+	dependencies[r.Name] = r.Spec.DependsOn
+
+	if cycle := findDependencyCycle(r.Name, dependencies); cycle != nil {
+		return field.Invalid(field.NewPath("spec").Child("dependsOn"), r.Spec.DependsOn,
+			fmt.Sprintf("the scenario dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return nil
+}
+
+// warnAboutMissingContextComponents returns a warning for each component_<name> context of the IntegrationTestScenario
+// which references a component missing from its Application. The scenario isn't rejected since the component
+// can be created later.
+func (v *integrationTestScenarioValidator) warnAboutMissingContextComponents(ctx context.Context, r *IntegrationTestScenario) admission.Warnings {
+	var componentContexts []string
+	for _, testContext := range r.Spec.Contexts {
+		if strings.HasPrefix(strings.TrimPrefix(testContext.Name, negatedContextPrefix), componentContextPrefix) {
+			componentContexts = append(componentContexts, testContext.Name)
+		}
+	}
+	if len(componentContexts) == 0 {
+		return nil
+	}
+
+	components := &applicationapiv1alpha1.ComponentList{}
+	if err := v.client.List(ctx, components, client.InNamespace(r.Namespace)); err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to verify the components referenced by the contexts: %s", err)}
+	}
+	componentNames := map[string]bool{}
+	for _, component := range components.Items {
+		if component.Spec.Application == r.Spec.Application {
+			componentNames[component.Name] = true
+		}
+	}
+
+	var warnings admission.Warnings
+	for _, contextName := range componentContexts {
+		componentName := strings.TrimPrefix(strings.TrimPrefix(contextName, negatedContextPrefix), componentContextPrefix)
+		if !componentNames[componentName] {
+			warnings = append(warnings, fmt.Sprintf("context %s references component %s which doesn't exist in application %s",
+				contextName, componentName, r.Spec.Application))
+		}
+	}
+	return warnings
+}
+
+// findDependencyCycle returns the chain of dependencies leading from the given scenario back to it,
+// nil is returned when the scenario isn't part of any cycle
+func findDependencyCycle(name string, dependencies map[string][]string) []string {
+	visited := map[string]bool{}
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		for _, dependency := range dependencies[path[len(path)-1]] {
+			if dependency == name {
+				return append(slices.Clone(path), dependency)
+			}
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+			if cycle := visit(append(slices.Clone(path), dependency)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit([]string{name})
+}
+
+// validateSpec validates the parts of the IntegrationTestScenario spec which are used to build the integration PipelineRuns,
+// the previous version of the scenario is nil on creation
+func (r *IntegrationTestScenario) validateSpec(old *IntegrationTestScenario) error {
+	var errs field.ErrorList
+	errs = append(errs, r.validateContexts()...)
+	errs = append(errs, r.validateTimeouts()...)
+	errs = append(errs, r.validateWorkspaces()...)
+	errs = append(errs, r.validateComponentSelector()...)
+	errs = append(errs, r.validateTaskRunSpecs()...)
+	errs = append(errs, r.validatePipelineRunTTL()...)
+	errs = append(errs, r.validateWatchdogDeadline()...)
+	errs = append(errs, r.validateParams(old)...)
+	errs = append(errs, r.validatePodTemplate()...)
+	errs = append(errs, r.validateSchedule()...)
+	errs = append(errs, r.validateTargetBranches()...)
+	errs = append(errs, r.validateMatrix()...)
+	return errs.ToAggregate()
+}
+
+// validateContexts ensures that the negated contexts of the IntegrationTestScenario name a context
+// and that no context is both required and negated
+func (r *IntegrationTestScenario) validateContexts() field.ErrorList {
+	var errs field.ErrorList
+	contextsPath := field.NewPath("spec").Child("contexts")
+	contextNames := map[string]bool{}
+	for _, testContext := range r.Spec.Contexts {
+		contextNames[testContext.Name] = true
+	}
+	for i, testContext := range r.Spec.Contexts {
+		negatedContextName, negated := strings.CutPrefix(testContext.Name, negatedContextPrefix)
+		if !negated {
+			continue
+		}
+		switch {
+		case negatedContextName == "" || strings.HasPrefix(negatedContextName, negatedContextPrefix):
+			errs = append(errs, field.Invalid(contextsPath.Index(i).Child("name"), testContext.Name,
+				"a negated context must name the excluded context"))
+		case contextNames[negatedContextName]:
+			errs = append(errs, field.Invalid(contextsPath.Index(i).Child("name"), testContext.Name,
+				fmt.Sprintf("context %s can't be both required and negated", negatedContextName)))
+		}
+	}
+	return errs
+}
+
+// validateParams ensures that the IntegrationTestScenario doesn't add any of the params
+// injected into the integration PipelineRuns by the integration service and that the params
+// sourced from Secrets or ConfigMaps reference exactly one key. The reserved params already defined
+// by the previous version of the scenario are kept, the integration PipelineRuns ignore them.
+func (r *IntegrationTestScenario) validateParams(old *IntegrationTestScenario) field.ErrorList {
+	var errs field.ErrorList
+	paramsPath := field.NewPath("spec").Child("params")
+	for i, param := range r.Spec.Params {
+		paramPath := paramsPath.Index(i)
+		if slices.Contains(ReservedParams, param.Name) && !old.hasParam(param.Name) {
+			errs = append(errs, field.Invalid(paramPath.Child("name"), param.Name,
+				"the param is reserved, it is injected into the integration PipelineRun by the integration service"))
+		}
+		if param.ValueFrom == nil {
+			continue
+		}
+
+		valueFromPath := paramPath.Child("valueFrom")
+		if param.Value != "" || len(param.Values) > 0 {
+			errs = append(errs, field.Forbidden(valueFromPath, "valueFrom can't be combined with value or values"))
+		}
+		secretKeyRef, configMapKeyRef := param.ValueFrom.SecretKeyRef, param.ValueFrom.ConfigMapKeyRef
+		switch {
+		case secretKeyRef == nil && configMapKeyRef == nil:
+			errs = append(errs, field.Required(valueFromPath, "exactly one of secretKeyRef and configMapKeyRef has to be set"))
+		case secretKeyRef != nil && configMapKeyRef != nil:
+			errs = append(errs, field.Forbidden(valueFromPath, "exactly one of secretKeyRef and configMapKeyRef has to be set"))
+		case secretKeyRef != nil:
+			errs = append(errs, validateKeySelector(valueFromPath.Child("secretKeyRef"), secretKeyRef.Name, secretKeyRef.Key)...)
+		default:
+			errs = append(errs, validateKeySelector(valueFromPath.Child("configMapKeyRef"), configMapKeyRef.Name, configMapKeyRef.Key)...)
+		}
+	}
+	return errs
+}
+
+// hasParam returns true if the IntegrationTestScenario defines the param of the given name, a nil scenario defines none
+func (r *IntegrationTestScenario) hasParam(name string) bool {
+	if r == nil {
+		return false
+	}
+	return slices.ContainsFunc(r.Spec.Params, func(param PipelineParameter) bool {
+		return param.Name == name
+	})
+}
+
+// validateKeySelector ensures that the Secret or ConfigMap key selector names both the object and its key
+func validateKeySelector(selectorPath *field.Path, name, key string) field.ErrorList {
+	var errs field.ErrorList
+	if name == "" {
+		errs = append(errs, field.Required(selectorPath.Child("name"), ""))
+	}
+	if key == "" {
+		errs = append(errs, field.Required(selectorPath.Child("key"), ""))
+	}
+	return errs
+}
+
+// validateTimeouts ensures that the PipelineRun timeouts of the IntegrationTestScenario are valid non-negative durations
+// and that the tasks and finally timeouts fit into the pipeline timeout, as required by Tekton
+func (r *IntegrationTestScenario) validateTimeouts() field.ErrorList {
+	if r.Spec.Timeouts == nil {
+		return nil
+	}
+
+	timeoutsPath := field.NewPath("spec").Child("timeouts")
+	timeouts := []struct {
+		name  string
+		value string
+	}{
+		{"pipeline", r.Spec.Timeouts.Pipeline},
+		{"tasks", r.Spec.Timeouts.Tasks},
+		{"finally", r.Spec.Timeouts.Finally},
+	}
+	var errs field.ErrorList
+	durations := map[string]time.Duration{}
+	for _, timeout := range timeouts {
+		if timeout.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(timeout.value)
+		if err != nil {
+			errs = append(errs, field.Invalid(timeoutsPath.Child(timeout.name), timeout.value,
+				"the timeout must be a valid duration string, e.g. \"1h30m\""))
+		} else if duration < 0 {
+			errs = append(errs, field.Invalid(timeoutsPath.Child(timeout.name), timeout.value,
+				"the timeout must not be negative"))
+		} else {
+			durations[timeout.name] = duration
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// a zero pipeline timeout means no timeout, the tasks and finally timeouts aren't bounded then
+	pipelineTimeout, found := durations["pipeline"]
+	if !found || pipelineTimeout == 0 {
+		return nil
+	}
+	if durations["tasks"]+durations["finally"] > pipelineTimeout {
+		errs = append(errs, field.Invalid(timeoutsPath.Child("pipeline"), r.Spec.Timeouts.Pipeline,
+			"the pipeline timeout must not be shorter than the sum of the tasks and finally timeouts"))
+	}
+	return errs
+}
+
+// validateWorkspaces ensures that the workspaces of the IntegrationTestScenario have unique names
+// and exactly one volume source each
+func (r *IntegrationTestScenario) validateWorkspaces() field.ErrorList {
+	workspacesPath := field.NewPath("spec").Child("workspaces")
+	names := map[string]bool{}
+	var errs field.ErrorList
+	for i, workspace := range r.Spec.Workspaces {
+		workspacePath := workspacesPath.Index(i)
+		if names[workspace.Name] {
+			errs = append(errs, field.Duplicate(workspacePath.Child("name"), workspace.Name))
+		}
+		names[workspace.Name] = true
+
+		sources := 0
+		for _, isSet := range []bool{workspace.EmptyDir, workspace.VolumeClaimTemplate != nil,
+			workspace.Secret != nil, workspace.ConfigMap != nil} {
+			if isSet {
+				sources++
+			}
+		}
+		if sources != 1 {
+			errs = append(errs, field.Invalid(workspacePath, workspace.Name,
+				"exactly one of emptyDir, volumeClaimTemplate, secret or configMap has to be specified"))
+		}
+	}
+	return errs
+}
+
+// validateComponentSelector ensures that the label selector of the component selector can be parsed
+func (r *IntegrationTestScenario) validateComponentSelector() field.ErrorList {
+	if r.Spec.ComponentSelector == nil || r.Spec.ComponentSelector.LabelSelector == nil {
+		return nil
+	}
+
+	labelSelectorPath := field.NewPath("spec").Child("componentSelector").Child("labelSelector")
+	if _, err := metav1.LabelSelectorAsSelector(r.Spec.ComponentSelector.LabelSelector); err != nil {
+		return field.ErrorList{field.Invalid(labelSelectorPath, r.Spec.ComponentSelector.LabelSelector, err.Error())}
+	}
+	return nil
+}
+
+// validateTaskRunSpecs ensures that each pipeline task and each of its steps is overridden by at most
+// one named entry of the IntegrationTestScenario taskRunSpecs
+func (r *IntegrationTestScenario) validateTaskRunSpecs() field.ErrorList {
+	taskRunSpecsPath := field.NewPath("spec").Child("taskRunSpecs")
+	taskNames := map[string]bool{}
+	var errs field.ErrorList
+	for i, taskRunSpec := range r.Spec.TaskRunSpecs {
+		taskRunSpecPath := taskRunSpecsPath.Index(i)
+		if taskRunSpec.PipelineTaskName == "" {
+			errs = append(errs, field.Required(taskRunSpecPath.Child("pipelineTaskName"), "the pipeline task name must not be empty"))
+		} else if taskNames[taskRunSpec.PipelineTaskName] {
+			errs = append(errs, field.Duplicate(taskRunSpecPath.Child("pipelineTaskName"), taskRunSpec.PipelineTaskName))
+		}
+		taskNames[taskRunSpec.PipelineTaskName] = true
+
+		stepNames := map[string]bool{}
+		for j, stepSpec := range taskRunSpec.StepSpecs {
+			stepNamePath := taskRunSpecPath.Child("stepSpecs").Index(j).Child("name")
+			if stepSpec.Name == "" {
+				errs = append(errs, field.Required(stepNamePath, "the step name must not be empty"))
+			} else if stepNames[stepSpec.Name] {
+				errs = append(errs, field.Duplicate(stepNamePath, stepSpec.Name))
+			}
+			stepNames[stepSpec.Name] = true
+		}
+	}
+	return errs
+}
+
+// validatePodTemplate ensures that the tolerations of the IntegrationTestScenario pod template use a supported
+// operator and that the tolerations with the Exists operator don't match a value
+func (r *IntegrationTestScenario) validatePodTemplate() field.ErrorList {
+	if r.Spec.PodTemplate == nil {
+		return nil
+	}
+
+	tolerationsPath := field.NewPath("spec").Child("podTemplate").Child("tolerations")
+	var errs field.ErrorList
+	for i, toleration := range r.Spec.PodTemplate.Tolerations {
+		tolerationPath := tolerationsPath.Index(i)
+		switch toleration.Operator {
+		case corev1.TolerationOpEqual, "":
+			if toleration.Key == "" {
+				errs = append(errs, field.Invalid(tolerationPath.Child("operator"), toleration.Operator,
+					"the operator must be Exists when the key is empty"))
+			}
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				errs = append(errs, field.Invalid(tolerationPath.Child("value"), toleration.Value,
+					"the value must be empty when the operator is Exists"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(tolerationPath.Child("operator"), toleration.Operator,
+				[]string{string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists)}))
+		}
+	}
+	return errs
+}
+
+// validateSchedule ensures that the schedule of the IntegrationTestScenario is a valid cron expression
+func (r *IntegrationTestScenario) validateSchedule() field.ErrorList {
+	if r.Spec.Schedule == "" {
+		return nil
+	}
+
+	if _, err := schedule.Parse(r.Spec.Schedule); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("schedule"), r.Spec.Schedule, err.Error())}
+	}
+	return nil
+}
+
+// validateTargetBranches ensures that the target branches of the IntegrationTestScenario are non-empty
+// branch names or valid regular expressions
+func (r *IntegrationTestScenario) validateTargetBranches() field.ErrorList {
+	var errs field.ErrorList
+	targetBranchesPath := field.NewPath("spec").Child("targetBranches")
+	for i, targetBranch := range r.Spec.TargetBranches {
+		if targetBranch == "" {
+			errs = append(errs, field.Required(targetBranchesPath.Index(i), "target branch must not be empty"))
+			continue
+		}
+		if _, err := regexp.Compile(targetBranch); err != nil {
+			errs = append(errs, field.Invalid(targetBranchesPath.Index(i), targetBranch, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateMatrix ensures that the matrix params of the IntegrationTestScenario are unique, don't collide with
+// its params and that the number of their combinations doesn't exceed MaxMatrixCombinations
+func (r *IntegrationTestScenario) validateMatrix() field.ErrorList {
+	var errs field.ErrorList
+	matrixPath := field.NewPath("spec").Child("matrix")
+	combinations := 1
+	matrixParamNames := map[string]bool{}
+	for i, matrixParam := range r.Spec.Matrix {
+		matrixParamPath := matrixPath.Index(i)
+		switch {
+		case matrixParam.Name == "":
+			errs = append(errs, field.Required(matrixParamPath.Child("name"), ""))
+		case matrixParamNames[matrixParam.Name]:
+			errs = append(errs, field.Duplicate(matrixParamPath.Child("name"), matrixParam.Name))
+		case slices.Contains(ReservedParams, matrixParam.Name):
+			errs = append(errs, field.Invalid(matrixParamPath.Child("name"), matrixParam.Name,
+				"the param is reserved, it is injected into the integration PipelineRun by the integration service"))
+		case slices.ContainsFunc(r.Spec.Params, func(param PipelineParameter) bool { return param.Name == matrixParam.Name }):
+			errs = append(errs, field.Invalid(matrixParamPath.Child("name"), matrixParam.Name, "the param is already defined in spec.params"))
+		}
+		matrixParamNames[matrixParam.Name] = true
+
+		if len(matrixParam.Values) == 0 {
+			errs = append(errs, field.Required(matrixParamPath.Child("values"), "matrix param must have at least one value"))
+			continue
+		}
+		for j, value := range matrixParam.Values {
+			if slices.Contains(matrixParam.Values[:j], value) {
+				errs = append(errs, field.Duplicate(matrixParamPath.Child("values").Index(j), value))
+			}
+		}
+		combinations *= len(matrixParam.Values)
+	}
+	if combinations > MaxMatrixCombinations {
+		errs = append(errs, field.TooMany(matrixPath, combinations, MaxMatrixCombinations))
+	}
+	return errs
+}
+
+// validateWatchdogDeadline ensures that the watchdog deadline of the IntegrationTestScenario is a valid positive duration
+func (r *IntegrationTestScenario) validateWatchdogDeadline() field.ErrorList {
+	if r.Spec.WatchdogDeadline == "" {
+		return nil
+	}
+
+	deadlinePath := field.NewPath("spec").Child("watchdogDeadline")
+	deadline, err := time.ParseDuration(r.Spec.WatchdogDeadline)
+	if err != nil {
+		return field.ErrorList{field.Invalid(deadlinePath, r.Spec.WatchdogDeadline,
+			"the deadline must be a valid duration string, e.g. \"48h\"")}
+	}
+	if deadline <= 0 {
+		return field.ErrorList{field.Invalid(deadlinePath, r.Spec.WatchdogDeadline, "the deadline must be positive")}
+	}
+	return nil
+}
+
+// validatePipelineRunTTL ensures that the PipelineRun TTL of the IntegrationTestScenario is a valid non-negative duration
+func (r *IntegrationTestScenario) validatePipelineRunTTL() field.ErrorList {
+	if r.Spec.PipelineRunTTL == "" {
+		return nil
+	}
+
+	ttlPath := field.NewPath("spec").Child("pipelineRunTTL")
+	ttl, err := time.ParseDuration(r.Spec.PipelineRunTTL)
+	if err != nil {
+		return field.ErrorList{field.Invalid(ttlPath, r.Spec.PipelineRunTTL,
+			"the TTL must be a valid duration string, e.g. \"72h\"")}
+	}
+	if ttl < 0 {
+		return field.ErrorList{field.Invalid(ttlPath, r.Spec.PipelineRunTTL, "the TTL must not be negative")}
+	}
+	return nil
+}
@@ -17,9 +17,18 @@ limitations under the License.
 package v1beta2
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -82,4 +91,395 @@ var _ = Describe("IntegrationTestScenario webhook", func() {
 		integrationTestScenario.Name = "this-name-is-too-long-it-has-64-characters-and-we-allow-max-63ch"
 		Expect(k8sClient.Create(ctx, integrationTestScenario)).ShouldNot(Succeed())
 	})
+
+	It("should create scenario with valid timeouts", func() {
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Pipeline: "2h",
+			Tasks:    "1h30m",
+			Finally:  "30m",
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid timeouts", func() {
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Pipeline: "two hours",
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.timeouts.pipeline"))
+
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Finally: "-30m",
+		}
+		err = k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the timeout must not be negative"))
+	})
+
+	It("should fail to create scenario with tasks and finally timeouts exceeding the pipeline timeout", func() {
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Pipeline: "2h",
+			Tasks:    "1h30m",
+			Finally:  "1h",
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the pipeline timeout must not be shorter than the sum of the tasks and finally timeouts"))
+
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Pipeline: "30m",
+			Tasks:    "1h",
+		}
+		err = k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.timeouts.pipeline"))
+	})
+
+	It("should create scenario with tasks and finally timeouts when the pipeline timeout is disabled", func() {
+		integrationTestScenario.Spec.Timeouts = &TestTimeouts{
+			Pipeline: "0",
+			Tasks:    "1h30m",
+			Finally:  "1h",
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid pipelineRun TTL", func() {
+		integrationTestScenario.Spec.PipelineRunTTL = "three days"
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.pipelineRunTTL"))
+
+		integrationTestScenario.Spec.PipelineRunTTL = "-72h"
+		err = k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the TTL must not be negative"))
+
+		integrationTestScenario.Spec.PipelineRunTTL = "72h"
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with reserved params", func() {
+		integrationTestScenario.Spec.Params = []PipelineParameter{
+			{Name: "SNAPSHOT", Value: "custom snapshot"},
+			{Name: "NAMESPACE", Value: "custom namespace"},
+			{Name: "PULL_REQUEST_NUMBER", Value: "42"},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.params[0].name: Invalid value: \"SNAPSHOT\""))
+		Expect(err.Error()).To(ContainSubstring("spec.params[1].name: Invalid value: \"NAMESPACE\""))
+		Expect(err.Error()).To(ContainSubstring("spec.params[2].name: Invalid value: \"PULL_REQUEST_NUMBER\""))
+
+		integrationTestScenario.Spec.Params = []PipelineParameter{
+			{Name: "ADDITIONAL_PARAMETER", Value: "custom value"},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should keep accepting updates of scenarios which already define reserved params", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		validator := &integrationTestScenarioValidator{client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+		// the scenario was created before the reserved params were rejected
+		oldScenario := integrationTestScenario.DeepCopy()
+		oldScenario.Finalizers = []string{"test.appstudio.openshift.io/scenario"}
+		oldScenario.Spec.Params = []PipelineParameter{{Name: "SNAPSHOT", Value: "custom snapshot"}}
+
+		// removing the finalizer doesn't change the spec
+		newScenario := oldScenario.DeepCopy()
+		newScenario.Finalizers = nil
+		_, err := validator.ValidateUpdate(ctx, oldScenario, newScenario)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the scenario being deleted isn't validated
+		newScenario.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		newScenario.Spec.Timeouts = &TestTimeouts{Pipeline: "two hours"}
+		_, err = validator.ValidateUpdate(ctx, oldScenario, newScenario)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the reserved param already defined is kept when the spec changes
+		newScenario = oldScenario.DeepCopy()
+		newScenario.Spec.Params = append(newScenario.Spec.Params, PipelineParameter{Name: "ADDITIONAL_PARAMETER", Value: "custom value"})
+		_, err = validator.ValidateUpdate(ctx, oldScenario, newScenario)
+		Expect(err).NotTo(HaveOccurred())
+
+		// newly added reserved params are rejected
+		newScenario.Spec.Params = append(newScenario.Spec.Params, PipelineParameter{Name: "NAMESPACE", Value: "custom namespace"})
+		_, err = validator.ValidateUpdate(ctx, oldScenario, newScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.params[2].name: Invalid value: \"NAMESPACE\""))
+		Expect(err.Error()).NotTo(ContainSubstring("SNAPSHOT"))
+	})
+
+	It("should fail to create scenario with invalid param value sources", func() {
+		integrationTestScenario.Spec.Params = []PipelineParameter{
+			{Name: "no-ref", ValueFrom: &ParamValueSource{}},
+			{Name: "with-value", Value: "literal", ValueFrom: &ParamValueSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "test-farm"},
+					Key:                  "api-key",
+				},
+			}},
+			{Name: "without-name", ValueFrom: &ParamValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "region"},
+			}},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.params[0].valueFrom: Required value"))
+		Expect(err.Error()).To(ContainSubstring("spec.params[1].valueFrom: Forbidden: valueFrom can't be combined with value or values"))
+		Expect(err.Error()).To(ContainSubstring("spec.params[2].valueFrom.configMapKeyRef.name: Required value"))
+
+		integrationTestScenario.Spec.Params = []PipelineParameter{
+			{Name: "literal", Value: "literal"},
+			{Name: "api-key", ValueFrom: &ParamValueSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "test-farm"},
+					Key:                  "api-key",
+				},
+			}},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid pod template tolerations", func() {
+		integrationTestScenario.Spec.PodTemplate = &PodTemplate{
+			Tolerations: []corev1.Toleration{
+				{Key: "nvidia.com/gpu", Operator: "Matches"},
+				{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Value: "present"},
+				{Operator: corev1.TolerationOpEqual, Value: "present"},
+			},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.podTemplate.tolerations[0].operator: Unsupported value: \"Matches\""))
+		Expect(err.Error()).To(ContainSubstring("spec.podTemplate.tolerations[1].value: Invalid value: \"present\""))
+		Expect(err.Error()).To(ContainSubstring("spec.podTemplate.tolerations[2].operator: Invalid value: \"Equal\""))
+
+		integrationTestScenario.Spec.PodTemplate = &PodTemplate{
+			NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"},
+			Tolerations: []corev1.Toleration{
+				{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "gpu"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid schedule", func() {
+		integrationTestScenario.Spec.Schedule = "every night"
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.schedule: Invalid value: \"every night\""))
+
+		integrationTestScenario.Spec.Schedule = "0 2 * * *"
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid matrix", func() {
+		integrationTestScenario.Spec.Matrix = []MatrixParam{
+			{Name: "pipeline-param-name", Values: []string{"4.14"}},
+			{Name: "ocp", Values: []string{"4.14", "4.15", "4.14"}},
+			{Name: "ocp", Values: []string{}},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.matrix[0].name: Invalid value: \"pipeline-param-name\": the param is already defined in spec.params"))
+		Expect(err.Error()).To(ContainSubstring("spec.matrix[1].values[2]: Duplicate value: \"4.14\""))
+		Expect(err.Error()).To(ContainSubstring("spec.matrix[2].name: Duplicate value: \"ocp\""))
+		Expect(err.Error()).To(ContainSubstring("spec.matrix[2].values: Required value"))
+
+		integrationTestScenario.Spec.Matrix = []MatrixParam{
+			{Name: "ocp", Values: []string{"4.13", "4.14", "4.15", "4.16", "4.17"}},
+			{Name: "arch", Values: []string{"amd64", "arm64", "s390x", "ppc64le"}},
+		}
+		err = k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.matrix: Too many: 20: must have at most 16 items"))
+
+		integrationTestScenario.Spec.Matrix = []MatrixParam{
+			{Name: "ocp", Values: []string{"4.14", "4.15", "4.16", "4.17"}},
+			{Name: "arch", Values: []string{"amd64", "arm64", "s390x", "ppc64le"}},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with a context which is both required and negated", func() {
+		integrationTestScenario.Spec.Contexts = []TestContext{{Name: "push"}, {Name: "!push"}, {Name: "!"}}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.contexts[1].name: Invalid value: \"!push\": context push can't be both required and negated"))
+		Expect(err.Error()).To(ContainSubstring("spec.contexts[2].name: Invalid value: \"!\""))
+
+		integrationTestScenario.Spec.Contexts = []TestContext{{Name: "pull_request"}, {Name: "!component_frontend"}}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid target branch regex", func() {
+		integrationTestScenario.Spec.TargetBranches = []string{"main", "release-(1.*"}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.targetBranches[1]: Invalid value: \"release-(1.*\""))
+
+		integrationTestScenario.Spec.TargetBranches = []string{"main", "release-.*"}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with invalid watchdog deadline", func() {
+		integrationTestScenario.Spec.WatchdogDeadline = "two days"
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.watchdogDeadline"))
+
+		integrationTestScenario.Spec.WatchdogDeadline = "0s"
+		err = k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the deadline must be positive"))
+
+		integrationTestScenario.Spec.WatchdogDeadline = "48h"
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with duplicate workspaces", func() {
+		integrationTestScenario.Spec.Workspaces = []TestWorkspace{
+			{Name: "cache", EmptyDir: true},
+			{Name: "cache", Secret: &corev1.SecretVolumeSource{SecretName: "cache-secret"}},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.workspaces[1].name: Duplicate value"))
+	})
+
+	It("should fail to create scenario with a workspace without exactly one volume source", func() {
+		integrationTestScenario.Spec.Workspaces = []TestWorkspace{
+			{Name: "cache"},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).ShouldNot(Succeed())
+
+		integrationTestScenario.Spec.Workspaces = []TestWorkspace{
+			{Name: "cache", EmptyDir: true, Secret: &corev1.SecretVolumeSource{SecretName: "cache-secret"}},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).ShouldNot(Succeed())
+
+		integrationTestScenario.Spec.Workspaces = []TestWorkspace{
+			{Name: "cache", EmptyDir: true},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+	It("should fail to create scenario with invalid component label selector", func() {
+		integrationTestScenario.Spec.ComponentSelector = &ComponentSelector{
+			LabelSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Unknown"}},
+			},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.componentSelector.labelSelector"))
+	})
+	It("should create scenario with valid taskRunSpecs", func() {
+		integrationTestScenario.Spec.TaskRunSpecs = []TaskRunSpec{
+			{
+				PipelineTaskName: "build",
+				ComputeResources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+				},
+			},
+			{
+				PipelineTaskName: "test",
+				StepSpecs: []TaskRunStepSpec{
+					{Name: "run-tests", ComputeResources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should fail to create scenario with conflicting or unnamed taskRunSpecs", func() {
+		integrationTestScenario.Spec.TaskRunSpecs = []TaskRunSpec{
+			{PipelineTaskName: "build"},
+			{PipelineTaskName: "build"},
+			{PipelineTaskName: ""},
+			{
+				PipelineTaskName: "test",
+				StepSpecs: []TaskRunStepSpec{
+					{Name: "run-tests"},
+					{Name: "run-tests"},
+					{Name: ""},
+				},
+			},
+		}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.taskRunSpecs[1].pipelineTaskName: Duplicate value: \"build\""))
+		Expect(err.Error()).To(ContainSubstring("spec.taskRunSpecs[2].pipelineTaskName: Required value"))
+		Expect(err.Error()).To(ContainSubstring("spec.taskRunSpecs[3].stepSpecs[1].name: Duplicate value: \"run-tests\""))
+		Expect(err.Error()).To(ContainSubstring("spec.taskRunSpecs[3].stepSpecs[2].name: Required value"))
+	})
+
+	It("should fail to create scenario depending on itself", func() {
+		integrationTestScenario.Spec.DependsOn = []string{integrationTestScenario.Name}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the scenario dependencies form a cycle: integrationtestscenario -> integrationtestscenario"))
+	})
+
+	It("should fail to create scenario with cyclic dependencies and allow acyclic ones", func() {
+		smokeScenario := integrationTestScenario.DeepCopy()
+		smokeScenario.Name = "smoke-scenario"
+		smokeScenario.Spec.DependsOn = []string{integrationTestScenario.Name}
+		Expect(k8sClient.Create(ctx, smokeScenario)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, smokeScenario)).Should(Succeed())
+		}()
+
+		integrationTestScenario.Spec.DependsOn = []string{smokeScenario.Name}
+		err := k8sClient.Create(ctx, integrationTestScenario)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the scenario dependencies form a cycle: integrationtestscenario -> smoke-scenario -> integrationtestscenario"))
+
+		// scenarios of other applications aren't part of the dependency graph
+		integrationTestScenario.Spec.Application = "other-application"
+		Expect(k8sClient.Create(ctx, integrationTestScenario)).Should(Succeed())
+	})
+
+	It("should warn about component contexts referencing missing components", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		Expect(applicationapiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		components := []client.Object{
+			&applicationapiv1alpha1.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default"},
+				Spec:       applicationapiv1alpha1.ComponentSpec{ComponentName: "frontend", Application: "application-sample"},
+			},
+			&applicationapiv1alpha1.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+				Spec:       applicationapiv1alpha1.ComponentSpec{ComponentName: "backend", Application: "other-application"},
+			},
+		}
+		validator := &integrationTestScenarioValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(components...).Build(),
+		}
+
+		integrationTestScenario.Spec.Contexts = []TestContext{
+			{Name: "application"},
+			{Name: "component_frontend"},
+			{Name: "component_backend"},
+		}
+		warnings, err := validator.ValidateCreate(ctx, integrationTestScenario)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(admission.Warnings{
+			"context component_backend references component backend which doesn't exist in application application-sample",
+		}))
+
+		integrationTestScenario.Spec.Contexts = []TestContext{{Name: "component_frontend"}}
+		warnings, err = validator.ValidateUpdate(ctx, integrationTestScenario, integrationTestScenario)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
 })
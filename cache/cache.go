@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/gitops"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
 	releasev1alpha1 "github.com/konflux-ci/release-service/api/v1alpha1"
@@ -96,3 +98,16 @@ func SetupIntegrationTestScenarioCache(mgr ctrl.Manager) error {
 	return mgr.GetCache().IndexField(context.Background(), &v1beta2.IntegrationTestScenario{},
 		"spec.application", integrationTestScenariosIndexFunc)
 }
+
+// PacRepositoryURLIndexFunc returns the normalized URL of the given Pipelines as Code Repository, it's exported
+// so the fake clients of the tests can register the same index.
+func PacRepositoryURLIndexFunc(obj client.Object) []string {
+	return []string{gitops.NormalizePacRepositoryURL(obj.(*pacv1alpha1.Repository).Spec.URL)}
+}
+
+// SetupPacRepositoryCache adds a new index field to be able to search Pipelines as Code Repositories by their
+// normalized URL.
+func SetupPacRepositoryCache(mgr ctrl.Manager) error {
+	return mgr.GetCache().IndexField(context.Background(), &pacv1alpha1.Repository{},
+		gitops.PacRepositoryURLField, PacRepositoryURLIndexFunc)
+}
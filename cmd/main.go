@@ -20,8 +20,15 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
+	"github.com/konflux-ci/integration-service/cache"
 	"github.com/konflux-ci/integration-service/internal/controller"
+	"github.com/konflux-ci/integration-service/internal/controller/integrationpipeline"
+	"github.com/konflux-ci/integration-service/internal/controller/snapshot"
+	"github.com/konflux-ci/integration-service/internal/controller/statusreport"
+	"github.com/konflux-ci/integration-service/loader"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -70,12 +77,42 @@ func main() {
 	var enableHttp2 bool
 	var enableLeaderElection bool
 	var probeAddr string
+	var integrationPipelineRunTTL time.Duration
+	var maxConcurrentIntegrationPipelineRuns int
+	var integrationPipelineRunRecreationMaxAge time.Duration
+	var integrationPipelineRunCreationStagger time.Duration
+	var integrationPipelineRunCreationBatchSize int
+	var integrationTestWatchdogDeadline time.Duration
+	var snapshotListPageSize int64
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableHttp2, "enable-http2", false, "Enable HTTP/2 for the metrics and webhook servers.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&integrationPipelineRunTTL, "integration-pipelinerun-ttl", 0,
+		"The time after which the finished integration PipelineRuns are deleted once their status was reported, "+
+			"unless their IntegrationTestScenario sets its own pipelineRunTTL. Zero disables the cleanup.")
+	flag.IntVar(&maxConcurrentIntegrationPipelineRuns, "max-concurrent-integration-pipelineruns", 0,
+		"The maximum number of integration PipelineRuns running at the same time across all Snapshots, "+
+			"the tests beyond the cap are queued as Pending. Zero disables the cap.")
+	flag.DurationVar(&integrationPipelineRunRecreationMaxAge, "integration-pipelinerun-recreation-max-age",
+		statusreport.PipelineRunRecreationMaxAge,
+		"The time after the start of a test during which its integration PipelineRun, deleted before producing results, "+
+			"is recreated instead of marking the test as deleted. Zero disables the recreation.")
+	flag.DurationVar(&integrationPipelineRunCreationStagger, "integration-pipelinerun-creation-stagger",
+		snapshot.PipelineRunCreationStagger,
+		"The interval between the creation of the batches of integration PipelineRuns of a Snapshot. Zero disables the stagger.")
+	flag.IntVar(&integrationPipelineRunCreationBatchSize, "integration-pipelinerun-creation-batch-size",
+		snapshot.PipelineRunCreationBatchSize,
+		"The number of integration PipelineRuns of a Snapshot created at once when their creation is staggered.")
+	flag.DurationVar(&integrationTestWatchdogDeadline, "integration-test-watchdog-deadline",
+		statusreport.DefaultWatchdogDeadline,
+		"The hard deadline after which the tests still in progress, whose integration PipelineRun isn't progressing, "+
+			"are failed by the watchdog, unless their IntegrationTestScenario sets its own watchdogDeadline. Zero disables the watchdog.")
+	flag.Int64Var(&snapshotListPageSize, "snapshot-list-page-size", loader.SnapshotListPageSize,
+		"The number of Snapshots listed per page from the API server when looking up the earlier Snapshots of an Application. "+
+			"Zero lists all of them from the cache at once.")
 	opts := zap.Options{
 		Development: false,
 		TimeEncoder: zapcore.RFC3339TimeEncoder,
@@ -110,6 +147,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	integrationpipeline.DefaultPipelineRunTTL = integrationPipelineRunTTL
+	statusreport.PipelineRunRecreationMaxAge = integrationPipelineRunRecreationMaxAge
+	statusreport.DefaultWatchdogDeadline = integrationTestWatchdogDeadline
+	snapshot.PipelineRunCreationStagger = integrationPipelineRunCreationStagger
+	snapshot.PipelineRunCreationBatchSize = integrationPipelineRunCreationBatchSize
+	loader.SnapshotListPageSize = snapshotListPageSize
+	if maxConcurrentIntegrationPipelineRuns > 0 {
+		pipelinerunqueue.DefaultQueue = pipelinerunqueue.NewQueue(maxConcurrentIntegrationPipelineRuns)
+	}
+	// the Repositories are looked up by URL by the status reporters of several controllers
+	if err = cache.SetupPacRepositoryCache(mgr); err != nil {
+		setupLog.Error(err, "unable to setup the Repository cache")
+		os.Exit(1)
+	}
 	err = controllers.SetupControllers(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to setup controllers")
@@ -119,6 +170,15 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "IntegrationTestScenario")
 		os.Exit(1)
 	}
+	// the deprecated versions only register the conversion webhook for the CRD conversion strategy
+	if err = (&integrationv1alpha1.IntegrationTestScenario{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create conversion webhook", "webhook", "IntegrationTestScenario", "version", "v1alpha1")
+		os.Exit(1)
+	}
+	if err = (&integrationv1beta1.IntegrationTestScenario{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create conversion webhook", "webhook", "IntegrationTestScenario", "version", "v1beta1")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
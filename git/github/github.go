@@ -18,6 +18,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -56,9 +57,43 @@ type CommitStatusAdapter struct {
 	TargetURL   string
 }
 
+// DefaultSecondaryRateLimitRetryAfter is used when GitHub doesn't specify when a request limited
+// by the secondary rate limit can be retried.
+const DefaultSecondaryRateLimitRetryAfter = time.Minute
+
+// SecondaryRateLimitError is returned when a request was rejected by the GitHub secondary rate limit.
+// RetryAfter is the time which should pass before the request is retried.
+type SecondaryRateLimitError struct {
+	RetryAfter time.Duration
+	err        error
+}
+
+func (e *SecondaryRateLimitError) Error() string {
+	return fmt.Sprintf("secondary rate limit exceeded, retry after %s: %s", e.RetryAfter, e.err)
+}
+
+func (e *SecondaryRateLimitError) Unwrap() error {
+	return e.err
+}
+
+// wrapSecondaryRateLimitError returns SecondaryRateLimitError if the given error was caused
+// by the GitHub secondary rate limit, otherwise the error is returned unchanged.
+func wrapSecondaryRateLimitError(err error) error {
+	var abuseErr *ghapi.AbuseRateLimitError
+	if !errors.As(err, &abuseErr) {
+		return err
+	}
+
+	retryAfter := DefaultSecondaryRateLimitRetryAfter
+	if abuseErr.RetryAfter != nil {
+		retryAfter = *abuseErr.RetryAfter
+	}
+	return &SecondaryRateLimitError{RetryAfter: retryAfter, err: err}
+}
+
 // GetStatus returns the appropriate status based on conclusion and start time.
 func (s *CheckRunAdapter) GetStatus() string {
-	if s.Conclusion == "success" || s.Conclusion == "failure" {
+	if s.Conclusion == "success" || s.Conclusion == "failure" || s.Conclusion == "cancelled" {
 		return "completed"
 	} else if s.StartTime.IsZero() {
 		return "queued"
@@ -85,6 +120,11 @@ type IssuesService interface {
 	EditComment(ctx context.Context, owner string, repo string, id int64, comment *ghapi.IssueComment) (*ghapi.IssueComment, *ghapi.Response, error)
 }
 
+// PullRequestsService defines the methods used in the github PullRequests service.
+type PullRequestsService interface {
+	Get(ctx context.Context, owner string, repo string, number int) (*ghapi.PullRequest, *ghapi.Response, error)
+}
+
 // RepositoriesService defines the methods used in the github Repositories service.
 type RepositoriesService interface {
 	CreateStatus(ctx context.Context, owner string, repo string, ref string, status *ghapi.RepoStatus) (*ghapi.RepoStatus, *ghapi.Response, error)
@@ -107,6 +147,8 @@ type ClientInterface interface {
 	CommitStatusExists(res []*ghapi.RepoStatus, commitStatus *CommitStatusAdapter) (bool, error)
 	GetExistingCommentID(comments []*ghapi.IssueComment, snapshotName, scenarioName string) *int64
 	EditComment(ctx context.Context, owner string, repo string, commentID int64, body string) (int64, error)
+	IsPullRequestOpen(ctx context.Context, owner string, repo string, number int) (bool, error)
+	GetPullRequestState(ctx context.Context, owner string, repo string, number int) (string, error)
 }
 
 // Client is an abstraction around the API client.
@@ -116,6 +158,7 @@ type Client struct {
 	apps   AppsService
 	checks ChecksService
 	issues IssuesService
+	pulls  PullRequestsService
 	repos  RepositoriesService
 }
 
@@ -143,6 +186,14 @@ func (c *Client) GetIssuesService() IssuesService {
 	return c.issues
 }
 
+// GetPullRequestsService returns either the default or custom PullRequests service.
+func (c *Client) GetPullRequestsService() PullRequestsService {
+	if c.pulls == nil {
+		return c.gh.PullRequests
+	}
+	return c.pulls
+}
+
 // GetRepositoriesService returns either the default or custom Repositories service.
 func (c *Client) GetRepositoriesService() RepositoriesService {
 	if c.repos == nil {
@@ -175,6 +226,13 @@ func WithIssuesService(svc IssuesService) ClientOption {
 	}
 }
 
+// WithPullRequestsService is an option which allows for overriding the github client's default PullRequests service.
+func WithPullRequestsService(svc PullRequestsService) ClientOption {
+	return func(c *Client) {
+		c.pulls = svc
+	}
+}
+
 // WithRepositoriesService is an option which allows for overriding the github client's default Issues service.
 func WithRepositoriesService(svc RepositoriesService) ClientOption {
 	return func(c *Client) {
@@ -445,7 +503,7 @@ func (c *Client) CommitStatusExists(res []*ghapi.RepoStatus, commitStatus *Commi
 func (c *Client) CreateComment(ctx context.Context, owner string, repo string, issueNumber int, body string) (int64, error) {
 	comment, _, err := c.GetIssuesService().CreateComment(ctx, owner, repo, issueNumber, &ghapi.IssueComment{Body: &body})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create a comment for GitHub owner/repo/PR %s/%s/%d: %w", owner, repo, issueNumber, err)
+		return 0, fmt.Errorf("failed to create a comment for GitHub owner/repo/PR %s/%s/%d: %w", owner, repo, issueNumber, wrapSecondaryRateLimitError(err))
 	}
 
 	c.logger.Info("Created comment",
@@ -461,7 +519,7 @@ func (c *Client) CreateComment(ctx context.Context, owner string, repo string, i
 func (c *Client) EditComment(ctx context.Context, owner string, repo string, commentID int64, body string) (int64, error) {
 	comment, _, err := c.GetIssuesService().EditComment(ctx, owner, repo, commentID, &ghapi.IssueComment{Body: &body})
 	if err != nil {
-		return 0, fmt.Errorf("failed to edit an existing comment for GitHub owner/repo/comment %s/%s/%d: %w", owner, repo, commentID, err)
+		return 0, fmt.Errorf("failed to edit an existing comment for GitHub owner/repo/comment %s/%s/%d: %w", owner, repo, commentID, wrapSecondaryRateLimitError(err))
 	}
 
 	c.logger.Info("Edited comment",
@@ -473,6 +531,30 @@ func (c *Client) EditComment(ctx context.Context, owner string, repo string, com
 	return *comment.ID, nil
 }
 
+// IsPullRequestOpen returns true if the pull request is neither closed nor merged.
+func (c *Client) IsPullRequestOpen(ctx context.Context, owner string, repo string, number int) (bool, error) {
+	pr, _, err := c.GetPullRequestsService().Get(ctx, owner, repo, number)
+	if err != nil {
+		return false, fmt.Errorf("failed to get pull request for GitHub owner/repo/PR %s/%s/%d: %w", owner, repo, number, err)
+	}
+
+	return pr.GetState() == "open", nil
+}
+
+// GetPullRequestState returns the state of the pull request, which is "open", "closed" or "merged".
+// GitHub reports merged pull requests as closed, they are told apart by their merged flag.
+func (c *Client) GetPullRequestState(ctx context.Context, owner string, repo string, number int) (string, error) {
+	pr, _, err := c.GetPullRequestsService().Get(ctx, owner, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request for GitHub owner/repo/PR %s/%s/%d: %w", owner, repo, number, err)
+	}
+
+	if pr.GetMerged() {
+		return "merged", nil
+	}
+	return pr.GetState(), nil
+}
+
 // CreateCommitStatus creates a repository commit status via the GitHub API.
 func (c *Client) CreateCommitStatus(ctx context.Context, owner string, repo string, SHA string, state string, description string, statusContext string, targetURL string) (int64, error) {
 	repoStatus := ghapi.RepoStatus{
@@ -18,6 +18,11 @@ package github_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -125,6 +130,16 @@ func (MockIssuesService) EditComment(ctx context.Context, owner string, repo str
 	return &ghapi.IssueComment{ID: &number}, nil, nil
 }
 
+type MockPullRequestsService struct {
+	state  string
+	merged bool
+}
+
+// Get implements github.PullRequestsService
+func (s MockPullRequestsService) Get(ctx context.Context, owner string, repo string, number int) (*ghapi.PullRequest, *ghapi.Response, error) {
+	return &ghapi.PullRequest{Number: &number, State: &s.state, Merged: &s.merged}, nil, nil
+}
+
 type MockRepositoriesService struct{}
 
 // CreateStatus implements github.RepositoriesService
@@ -155,6 +170,8 @@ var _ = Describe("CheckRunAdapter", func() {
 		Expect(adapter.GetStatus()).To(Equal("completed"))
 		adapter.Conclusion = "failure"
 		Expect(adapter.GetStatus()).To(Equal("completed"))
+		adapter.Conclusion = "cancelled"
+		Expect(adapter.GetStatus()).To(Equal("completed"))
 		adapter.Conclusion = ""
 		Expect(adapter.GetStatus()).To(Equal("queued"))
 		adapter.StartTime = time.Now()
@@ -238,6 +255,57 @@ var _ = Describe("Client", func() {
 		Expect(id).To(Equal(int64(40)))
 	})
 
+	It("returns SecondaryRateLimitError when creating a comment hits the secondary rate limit", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Retry-After", "30")
+			rw.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(rw, `{"message": "You have exceeded a secondary rate limit.", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`)
+		}))
+		defer server.Close()
+
+		ghClient := ghapi.NewClient(nil)
+		ghClient.BaseURL, _ = url.Parse(server.URL + "/")
+		client = github.NewClient(logr.Discard(), github.WithIssuesService(ghClient.Issues))
+
+		_, err := client.CreateComment(context.TODO(), "example-owner", "example-repo", 1, "example-comment")
+		var rateLimitErr *github.SecondaryRateLimitError
+		Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+		Expect(rateLimitErr.RetryAfter).To(Equal(30 * time.Second))
+
+		_, err = client.EditComment(context.TODO(), "example-owner", "example-repo", 1, "example-comment")
+		Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+		Expect(rateLimitErr.RetryAfter).To(Equal(30 * time.Second))
+	})
+
+	It("can check if pull request is open", func() {
+		client = github.NewClient(logr.Discard(), github.WithPullRequestsService(MockPullRequestsService{state: "open"}))
+		open, err := client.IsPullRequestOpen(context.TODO(), "example-owner", "example-repo", 1)
+		Expect(err).To(BeNil())
+		Expect(open).To(BeTrue())
+
+		client = github.NewClient(logr.Discard(), github.WithPullRequestsService(MockPullRequestsService{state: "closed"}))
+		open, err = client.IsPullRequestOpen(context.TODO(), "example-owner", "example-repo", 1)
+		Expect(err).To(BeNil())
+		Expect(open).To(BeFalse())
+	})
+
+	It("can get the state of pull request", func() {
+		client = github.NewClient(logr.Discard(), github.WithPullRequestsService(MockPullRequestsService{state: "open"}))
+		state, err := client.GetPullRequestState(context.TODO(), "example-owner", "example-repo", 1)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal("open"))
+
+		client = github.NewClient(logr.Discard(), github.WithPullRequestsService(MockPullRequestsService{state: "closed"}))
+		state, err = client.GetPullRequestState(context.TODO(), "example-owner", "example-repo", 1)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal("closed"))
+
+		client = github.NewClient(logr.Discard(), github.WithPullRequestsService(MockPullRequestsService{state: "closed", merged: true}))
+		state, err = client.GetPullRequestState(context.TODO(), "example-owner", "example-repo", 1)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal("merged"))
+	})
+
 	It("can create commit statuses", func() {
 		id, err := client.CreateCommitStatus(context.TODO(), "", "", "", "", "", "", "")
 		Expect(err).To(BeNil())
@@ -18,15 +18,21 @@ package gitops
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/konflux-ci/integration-service/api/v1beta2"
 	"github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/metrics"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/integration-service/tekton"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
@@ -47,6 +53,18 @@ const (
 	// SnapshotIntegrationTestRun contains name of test we want to trigger run
 	SnapshotIntegrationTestRun = "test.appstudio.openshift.io/run"
 
+	// SnapshotIntegrationTestRunForceLabel contains "true" when the run requested by the SnapshotIntegrationTestRun label
+	// should cancel the pipelineRuns of the scenario tests which are still running instead of being refused
+	SnapshotIntegrationTestRunForceLabel = "test.appstudio.openshift.io/run-force"
+
+	// SnapshotRerunRefusedAnnotation contains the reason why the last run requested by the SnapshotIntegrationTestRun
+	// label was refused
+	SnapshotRerunRefusedAnnotation = "test.appstudio.openshift.io/rerun-refused"
+
+	// SnapshotScheduledRunAnnotation contains the name of the scenario whose run requested by the
+	// SnapshotIntegrationTestRun label was triggered by the schedule of the scenario
+	SnapshotScheduledRunAnnotation = "test.appstudio.openshift.io/scheduled-run"
+
 	// AppstudioLabelPrefix contains application, component, build-pipelinerun etc.
 	AppstudioLabelPrefix = "appstudio.openshift.io"
 
@@ -65,9 +83,98 @@ const (
 	// SnapshotStatusReportAnnotation contains metadata of tests related to status reporting to git provider
 	SnapshotStatusReportAnnotation = "test.appstudio.openshift.io/git-reporter-status"
 
+	// CheckPrefixAnnotation contains the prefix of the names of checks reported to git provider, it's propagated from Application to Snapshots
+	CheckPrefixAnnotation = "test.appstudio.openshift.io/check-prefix"
+
+	// MergeTrainCheckAnnotation enables the check of GitLab merge trains when a composite Snapshot fails, it's propagated from Application to Snapshots
+	MergeTrainCheckAnnotation = "test.appstudio.openshift.io/merge-train-check"
+
+	// ExternalStatusCheckAnnotation enables responding to GitLab external status checks of merge requests, it's propagated from Application to Snapshots
+	ExternalStatusCheckAnnotation = "test.appstudio.openshift.io/gitlab-external-status-check"
+
+	// AlsoReportToAnnotation contains the comma-separated mirrors of the repository which test results are reported to
+	// in addition to the git provider of the Snapshot, each as <git provider>=<repository URL>, e.g.
+	// github=https://github.com/org/repo. It's propagated from Application to Snapshots
+	AlsoReportToAnnotation = "test.appstudio.openshift.io/also-report-to"
+
+	// ReuseTestResultsAnnotation is the Application annotation enabling the reuse of the final test results
+	// of an earlier Snapshot with identical components instead of running the tests again
+	ReuseTestResultsAnnotation = "test.appstudio.openshift.io/reuse-test-results"
+
+	// SnapshotSupersededByAnnotation contains the name of the newer Snapshot which superseded the Snapshot before its tests finished
+	SnapshotSupersededByAnnotation = "test.appstudio.openshift.io/superseded-by"
+
+	// SnapshotObsoletedByOverrideAnnotation contains the name of the override Snapshot which obsoleted the pull request
+	// Snapshot before its tests finished
+	SnapshotObsoletedByOverrideAnnotation = "test.appstudio.openshift.io/obsoleted-by-override"
+
+	// SnapshotCanceledByPRMRAnnotation contains the state of the pull/merge request, closed or merged, which caused
+	// the unfinished tests of the pull request Snapshot to be canceled
+	SnapshotCanceledByPRMRAnnotation = "test.appstudio.openshift.io/canceled-by-prmr"
+
+	// SnapshotPRMRStateCheckedAnnotation contains the time when the state of the pull/merge request of the Snapshot
+	// was last checked, it throttles the checks of Snapshots with unfinished tests
+	SnapshotPRMRStateCheckedAnnotation = "test.appstudio.openshift.io/prmr-state-checked"
+
+	// CancelOnMergedPRMRAnnotation is the Application annotation enabling the cancellation of the unfinished tests
+	// of pull request Snapshots whose pull/merge request was merged, by default only closed ones are canceled
+	CancelOnMergedPRMRAnnotation = "test.appstudio.openshift.io/cancel-on-merged-prmr"
+
+	// SnapshotTimeInQueueAnnotation contains the duration the Snapshot waited from its creation until the start
+	// of its first integration PipelineRun, e.g. "1m30s"
+	SnapshotTimeInQueueAnnotation = "test.appstudio.openshift.io/time-in-queue"
+
+	// SnapshotPipelineRunsRecreationAnnotation contains the time when the recreation of the integration PipelineRuns
+	// of the Snapshot, which were deleted before producing results, was last requested
+	SnapshotPipelineRunsRecreationAnnotation = "test.appstudio.openshift.io/recreate-pipelineruns"
+
+	// SnapshotPipelineRunBatchTimeAnnotation contains the time when the last batch of the integration PipelineRuns
+	// of the Snapshot was created, while the creation of the rest of them is staggered
+	SnapshotPipelineRunBatchTimeAnnotation = "test.appstudio.openshift.io/pipelinerun-batch-time"
+
+	// SnapshotNoIntegrationTestsAnnotation contains the message reported to the git provider instead of the test results
+	// when the Application of the Snapshot has no IntegrationTestScenarios
+	SnapshotNoIntegrationTestsAnnotation = "test.appstudio.openshift.io/no-integration-tests"
+
+	// SlackNotifiedScenariosAnnotation contains the comma-separated names of failed scenarios of the Snapshot which were notified to Slack
+	SlackNotifiedScenariosAnnotation = "test.appstudio.openshift.io/slack-notified-scenarios"
+
+	// SnapshotTestResultsArtifactAnnotation contains the digest of the OCI artifact archiving the final test reports of the Snapshot,
+	// or TestResultsNotArchived when the Snapshot has no image the test reports could be archived next to
+	SnapshotTestResultsArtifactAnnotation = "test.appstudio.openshift.io/test-results-artifact"
+
+	// TestResultsNotArchived is recorded in the SnapshotTestResultsArtifactAnnotation when the Snapshot has no component image
+	// referenced by digest, so its final test reports aren't archived
+	TestResultsNotArchived = "none"
+
+	// SnapshotExportedResultsAnnotation contains the JSON encoded results exported by the passed tests of the Snapshot,
+	// keyed by the name of the test and the name of the result
+	SnapshotExportedResultsAnnotation = "test.appstudio.openshift.io/exported-results"
+
+	// SnapshotAutoReleasesAnnotation contains the comma-separated names of the Releases created for the Snapshot by
+	// the auto-release, or NoAutoReleasePlanMatched when no auto-release ReleasePlan matched the Snapshot
+	SnapshotAutoReleasesAnnotation = "test.appstudio.openshift.io/auto-releases"
+
+	// NoAutoReleasePlanMatched is the value of SnapshotAutoReleasesAnnotation when no Release was created for the Snapshot
+	NoAutoReleasePlanMatched = "none matched"
+
+	// SnapshotCandidateUpdateErrorsAnnotation contains the JSON encoded errors, keyed by the name of the Component,
+	// which prevented updating the global candidate list with the Components of the Snapshot
+	SnapshotCandidateUpdateErrorsAnnotation = "test.appstudio.openshift.io/candidate-update-errors"
+
+	// DebugKeepAnnotation can be set on an IntegrationTestScenario or a Snapshot to keep the failed integration
+	// PipelineRuns, and so their pods, for the given duration after their completion, e.g. "4h"
+	DebugKeepAnnotation = "test.appstudio.openshift.io/debug-keep"
+
 	// BuildPipelineRunPrefix contains the build pipeline run related labels and annotations
 	BuildPipelineRunPrefix = "build.appstudio"
 
+	// BuildCommitSHAAnnotation contains the full commit SHA which the build PipelineRun of the Snapshot was triggered for
+	BuildCommitSHAAnnotation = "build.appstudio.redhat.com/commit_sha"
+
+	// BuildTargetBranchLabel contains the target branch of the event which triggered the build PipelineRun of the Snapshot
+	BuildTargetBranchLabel = "build.appstudio.redhat.com/target_branch"
+
 	// BuildPipelineRunFinishTimeLabel contains the build PipelineRun finish time of the Snapshot.
 	BuildPipelineRunFinishTimeLabel = "test.appstudio.openshift.io/pipelinerunfinishtime"
 
@@ -83,6 +190,9 @@ const (
 	// SnapshotCompositeType is the type of Snapshot which was created for multiple components.
 	SnapshotCompositeType = "composite"
 
+	// SnapshotOverrideType is the type of Snapshot which was created manually to override the images of its components.
+	SnapshotOverrideType = "override"
+
 	// PipelineAsCodeEventTypeLabel is the type of event which triggered the pipelinerun in build service
 	PipelineAsCodeEventTypeLabel = PipelinesAsCodePrefix + "/event-type"
 
@@ -101,6 +211,9 @@ const (
 	// PipelineAsCodeURLRepositoryLabel is the git repository which triggered the pipelinerun in build service.
 	PipelineAsCodeURLRepositoryLabel = PipelinesAsCodePrefix + "/url-repository"
 
+	// PipelineAsCodeRepositoryLabel is the name of the Pipelines as Code Repository which triggered the pipelinerun in build service.
+	PipelineAsCodeRepositoryLabel = PipelinesAsCodePrefix + "/repository"
+
 	// PipelineAsCodeRepoURLAnnotation is the URL to the git repository which triggered the pipelinerun in build service.
 	PipelineAsCodeRepoURLAnnotation = PipelinesAsCodePrefix + "/repo-url"
 
@@ -110,6 +223,12 @@ const (
 	// PipelineAsCodePullRequestAnnotation is the git repository's pull request identifier
 	PipelineAsCodePullRequestAnnotation = PipelinesAsCodePrefix + "/pull-request"
 
+	// PipelineAsCodeSourceBranchAnnotation is the source branch of the pull/merge request which triggered the pipelinerun in build service
+	PipelineAsCodeSourceBranchAnnotation = PipelinesAsCodePrefix + "/source-branch"
+
+	// PipelineAsCodeBranchAnnotation is the target branch of the pull/merge request or the pushed branch
+	PipelineAsCodeBranchAnnotation = PipelinesAsCodePrefix + "/branch"
+
 	// PipelineAsCodeSourceProjectIDAnnotation is the source project ID for gitlab
 	PipelineAsCodeSourceProjectIDAnnotation = PipelinesAsCodePrefix + "/source-project-id"
 
@@ -134,12 +253,22 @@ const (
 	// PipelineAsCodeGitHubProviderType is the git provider type for a GitHub event which triggered the pipelinerun in build service.
 	PipelineAsCodeGitLabProviderType = "gitlab"
 
+	// PipelineAsCodeGiteaProviderType is the git provider type for a Gitea or Forgejo event which triggered the pipelinerun in build service.
+	PipelineAsCodeGiteaProviderType = "gitea"
+
+	// PipelineAsCodeAzureDevOpsProviderType is the git provider type for an Azure DevOps event which triggered the pipelinerun in build service.
+	PipelineAsCodeAzureDevOpsProviderType = "azure-devops"
+
 	//AppStudioTestSucceededCondition is the condition for marking if the AppStudio Tests succeeded for the Snapshot.
 	AppStudioTestSucceededCondition = "AppStudioTestSucceeded"
 
 	//LegacyTestSucceededCondition is the condition for marking if the AppStudio Tests succeeded for the Snapshot.
 	LegacyTestSucceededCondition = "HACBSStudioTestSucceeded"
 
+	// AppStudioTestsCompletedCondition is the condition summarizing if all the tests of the required
+	// IntegrationTestScenarios applying to the Snapshot reached a final state.
+	AppStudioTestsCompletedCondition = "AppStudioTestsCompleted"
+
 	// AppStudioIntegrationStatusCondition is the condition for marking the AppStudio integration status of the Snapshot.
 	AppStudioIntegrationStatusCondition = "AppStudioIntegrationStatus"
 
@@ -163,6 +292,14 @@ const (
 	// AppStudioTestSucceededConditionFailed is the reason that's set when the AppStudio tests fail.
 	AppStudioTestSucceededConditionFailed = "Failed"
 
+	// AppStudioTestSucceededConditionPRClosed is the reason that's set when the AppStudio tests are canceled
+	// because the pull/merge request was closed or merged.
+	AppStudioTestSucceededConditionPRClosed = "PRClosed"
+
+	// AppStudioTestsCompletedConditionInProgress is the reason that's set when some of the required AppStudio tests
+	// haven't reached a final state yet.
+	AppStudioTestsCompletedConditionInProgress = "InProgress"
+
 	// AppStudioIntegrationStatusInvalid is the reason that's set when the AppStudio integration gets into an invalid state.
 	AppStudioIntegrationStatusInvalid = "Invalid"
 
@@ -192,6 +329,39 @@ const (
 
 	//IntegrationTestStatusInProgressGithub is the status reported to github when integration test is in progress
 	IntegrationTestStatusInProgressGithub = "in_progress"
+
+	//IntegrationTestStatusCancelledGithub is the conclusion reported to github when integration test was cancelled before it could finish
+	IntegrationTestStatusCancelledGithub = "cancelled"
+
+	//IntegrationTestStatusSkippedGithub is the conclusion reported to github when integration test was skipped
+	IntegrationTestStatusSkippedGithub = "skipped"
+
+	//IntegrationTestStatusNeutralGithub is the conclusion reported to github when integration test was canceled because its snapshot was superseded
+	IntegrationTestStatusNeutralGithub = "neutral"
+
+	// ApplicationContext is the IntegrationTestScenario context which applies to all Snapshots of the Application
+	ApplicationContext = "application"
+
+	// ComponentContext is the IntegrationTestScenario context which applies to the Snapshots created for a single component build
+	ComponentContext = "component"
+
+	// ComponentContextPrefix prefixes the IntegrationTestScenario contexts which apply only to the Snapshots
+	// created for the build of the named component, e.g. component_frontend
+	ComponentContextPrefix = "component_"
+
+	// PullRequestContext is the IntegrationTestScenario context which applies to the Snapshots created for pull/merge requests
+	PullRequestContext = "pull_request"
+
+	// PushContext is the IntegrationTestScenario context which applies to the Snapshots created for pushes and manually
+	PushContext = "push"
+
+	// NegatedContextPrefix prefixes the IntegrationTestScenario contexts which exclude the Snapshots the context
+	// applies to, e.g. !push
+	NegatedContextPrefix = "!"
+
+	// MaxDebugKeepDuration is the longest duration for which the failed integration PipelineRuns are kept
+	// for debugging, longer durations requested by the DebugKeepAnnotation are shortened to it
+	MaxDebugKeepDuration = 72 * time.Hour
 )
 
 var (
@@ -266,6 +436,146 @@ func MarkSnapshotAsInvalid(ctx context.Context, adapterClient client.Client, sna
 	return nil
 }
 
+// IsSnapshotSuperseded returns true if the snapshot was superseded by a newer snapshot
+func IsSnapshotSuperseded(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotation(snapshot, SnapshotSupersededByAnnotation)
+}
+
+// IsSnapshotObsoletedByOverride returns true if the snapshot was obsoleted by an override snapshot
+func IsSnapshotObsoletedByOverride(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotation(snapshot, SnapshotObsoletedByOverrideAnnotation)
+}
+
+// IsSnapshotSupersededOrObsoleted returns true if the unfinished tests of the snapshot were canceled because
+// the snapshot was superseded by a newer snapshot or obsoleted by an override snapshot
+func IsSnapshotSupersededOrObsoleted(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return IsSnapshotSuperseded(snapshot) || IsSnapshotObsoletedByOverride(snapshot)
+}
+
+// IsSnapshotCanceledByPRMR returns true if the unfinished tests of the snapshot were canceled because
+// its pull/merge request was closed or merged
+func IsSnapshotCanceledByPRMR(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotation(snapshot, SnapshotCanceledByPRMRAnnotation)
+}
+
+// AreSnapshotTestsCanceled returns true if the unfinished tests of the snapshot were canceled because the snapshot
+// was superseded, obsoleted by an override snapshot or its pull/merge request was closed
+func AreSnapshotTestsCanceled(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return IsSnapshotSupersededOrObsoleted(snapshot) || IsSnapshotCanceledByPRMR(snapshot)
+}
+
+// GetPRMRStateCheckTime returns the time when the state of the pull/merge request of the snapshot was last checked,
+// false is returned if it wasn't checked yet
+func GetPRMRStateCheckTime(snapshot *applicationapiv1alpha1.Snapshot) (time.Time, bool) {
+	value, ok := snapshot.GetAnnotations()[SnapshotPRMRStateCheckedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	checkTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return checkTime, true
+}
+
+// MarkPRMRStateChecked records the time when the state of the pull/merge request of the snapshot was checked.
+// If the patch command fails, an error will be returned.
+func MarkPRMRStateChecked(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, checkTime time.Time) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	_ = metadata.SetAnnotation(&snapshot.ObjectMeta, SnapshotPRMRStateCheckedAnnotation, checkTime.UTC().Format(time.RFC3339))
+	return adapterClient.Patch(ctx, snapshot, patch)
+}
+
+// MarkPipelineRunBatchCreated records the time when the last batch of the integration pipelineRuns of the snapshot
+// was created. If the patch command fails, an error will be returned.
+func MarkPipelineRunBatchCreated(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, batchTime time.Time) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	_ = metadata.SetAnnotation(&snapshot.ObjectMeta, SnapshotPipelineRunBatchTimeAnnotation, batchTime.UTC().Format(time.RFC3339Nano))
+	return adapterClient.Patch(ctx, snapshot, patch)
+}
+
+// GetPipelineRunBatchCreationTime returns the time when the last batch of the integration pipelineRuns of the snapshot
+// was created, false is returned if it wasn't recorded or can't be parsed
+func GetPipelineRunBatchCreationTime(snapshot *applicationapiv1alpha1.Snapshot) (time.Time, bool) {
+	value, ok := snapshot.GetAnnotations()[SnapshotPipelineRunBatchTimeAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	batchTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return batchTime, true
+}
+
+// MarkSnapshotWithoutIntegrationTests records the message reported to the git provider for the snapshot instead of
+// the test results, because its application has no IntegrationTestScenarios.
+// If the patch command fails, an error will be returned.
+func MarkSnapshotWithoutIntegrationTests(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, message string) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	_ = metadata.SetAnnotation(&snapshot.ObjectMeta, SnapshotNoIntegrationTestsAnnotation, message)
+	return adapterClient.Patch(ctx, snapshot, patch)
+}
+
+// IsSnapshotWithoutIntegrationTests returns true if the snapshot was marked as having no integration tests
+// because its application has no IntegrationTestScenarios
+func IsSnapshotWithoutIntegrationTests(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotation(snapshot, SnapshotNoIntegrationTestsAnnotation)
+}
+
+// GetSnapshotTimeInQueue returns the duration the snapshot waited from its creation until the start of its first
+// integration pipelineRun, false is returned if it wasn't recorded yet
+func GetSnapshotTimeInQueue(snapshot *applicationapiv1alpha1.Snapshot) (time.Duration, bool) {
+	value, ok := snapshot.GetAnnotations()[SnapshotTimeInQueueAnnotation]
+	if !ok {
+		return 0, false
+	}
+	timeInQueue, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false
+	}
+	return timeInQueue, true
+}
+
+// MarkSnapshotTimeInQueue records the duration the snapshot waited from its creation until the given start time of
+// its first integration pipelineRun and registers it in the metrics. The patch fails on a conflict, so the pipelineRuns
+// starting in parallel don't overwrite the duration recorded by the first one. If the patch command fails, an error will be returned.
+func MarkSnapshotTimeInQueue(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, pipelineRunStartTime metav1.Time) error {
+	timeInQueue := pipelineRunStartTime.Sub(snapshot.GetCreationTimestamp().Time).Round(time.Second)
+	if timeInQueue < 0 {
+		timeInQueue = 0
+	}
+
+	patch := client.MergeFromWithOptions(snapshot.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	_ = metadata.SetAnnotation(&snapshot.ObjectMeta, SnapshotTimeInQueueAnnotation, timeInQueue.String())
+	if err := adapterClient.Patch(ctx, snapshot, patch); err != nil {
+		return err
+	}
+
+	go metrics.RegisterSnapshotTimeInQueue(snapshot.Namespace, timeInQueue)
+	return nil
+}
+
+// RequestPipelineRunsRecreation records the time when the recreation of the integration pipelineRuns of the snapshot,
+// which were deleted before producing results, was requested, so the snapshot controller creates them again.
+// If the patch command fails, an error will be returned.
+func RequestPipelineRunsRecreation(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, requestTime time.Time) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	_ = metadata.SetAnnotation(&snapshot.ObjectMeta, SnapshotPipelineRunsRecreationAnnotation, requestTime.UTC().Format(time.RFC3339Nano))
+	return adapterClient.Patch(ctx, snapshot, patch)
+}
+
+// IsCancelOnMergedPRMREnabled returns true if the Application opted in to cancel the unfinished tests
+// of pull request Snapshots whose pull/merge request was merged
+func IsCancelOnMergedPRMREnabled(application *applicationapiv1alpha1.Application) bool {
+	return metadata.HasAnnotationWithValue(application, CancelOnMergedPRMRAnnotation, "true")
+}
+
+// IsOverrideSnapshot returns true if the snapshot was created to override the images of its components
+func IsOverrideSnapshot(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasLabelWithValue(snapshot, SnapshotTypeLabel, SnapshotOverrideType)
+}
+
 // IsSnapshotMarkedAsInvalid returns true if snapshot is marked as failed
 func IsSnapshotMarkedAsInvalid(snapshot *applicationapiv1alpha1.Snapshot) bool {
 	return IsSnapshotStatusConditionSet(snapshot, AppStudioIntegrationStatusCondition, metav1.ConditionFalse, AppStudioIntegrationStatusInvalid)
@@ -468,6 +778,16 @@ func ValidateImageDigest(imageUrl string) error {
 	return err
 }
 
+// PacRepositoryURLField is the field index of the Pipelines as Code Repositories by their URL normalized
+// by NormalizePacRepositoryURL
+const PacRepositoryURLField = "spec.url"
+
+// NormalizePacRepositoryURL returns the given repository URL without its trailing slashes and .git suffix,
+// so the different spellings of the URL of a Pipelines as Code Repository match each other
+func NormalizePacRepositoryURL(url string) string {
+	return strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+}
+
 // HaveAppStudioTestsFinished checks if the AppStudio tests have finished by checking if the AppStudio Test Succeeded condition is set.
 func HaveAppStudioTestsFinished(snapshot *applicationapiv1alpha1.Snapshot) bool {
 	statusCondition := meta.FindStatusCondition(snapshot.Status.Conditions, AppStudioTestSucceededCondition)
@@ -478,6 +798,99 @@ func HaveAppStudioTestsFinished(snapshot *applicationapiv1alpha1.Snapshot) bool
 	return statusCondition != nil && statusCondition.Status != metav1.ConditionUnknown
 }
 
+// RequiredTestsCompletion summarizes the progress of the tests of the required IntegrationTestScenarios of a Snapshot,
+// including the tests of all matrix combinations of the scenarios
+type RequiredTestsCompletion struct {
+	// Total is the number of the required tests
+	Total int
+	// Finished is the number of the required tests which reached a final state
+	Finished int
+	// Passed is the number of the required tests which didn't fail
+	Passed int
+}
+
+// AllFinished returns true if all the required tests reached a final state
+func (c RequiredTestsCompletion) AllFinished() bool {
+	return c.Finished == c.Total
+}
+
+// AllPassed returns true if none of the required tests failed
+func (c RequiredTestsCompletion) AllPassed() bool {
+	return c.Passed == c.Total
+}
+
+// GetRequiredTestsCompletion returns the progress of the tests of the given required IntegrationTestScenarios which
+// apply to the context of the Snapshot. The tests which were skipped count as passed.
+func GetRequiredTestsCompletion(snapshot *applicationapiv1alpha1.Snapshot, requiredScenarios *[]v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) RequiredTestsCompletion {
+	applicableScenarios := []v1beta2.IntegrationTestScenario{}
+	for _, scenario := range *requiredScenarios {
+		scenario := scenario // G601
+		if IsScenarioApplicableToSnapshotsContext(&scenario, snapshot) {
+			applicableScenarios = append(applicableScenarios, scenario)
+		}
+	}
+
+	completion := RequiredTestsCompletion{}
+	for _, testName := range *helpers.GetScenarioTestNames(&applicableScenarios) {
+		completion.Total++
+		testDetails, ok := testStatuses.GetScenarioStatus(testName)
+		if ok && testDetails.Status.IsFinal() {
+			completion.Finished++
+		}
+		if !ok || testDetails.Status == intgteststat.IntegrationTestStatusTestPassed ||
+			testDetails.Status == intgteststat.IntegrationTestStatusSkipped {
+			completion.Passed++
+		}
+	}
+	return completion
+}
+
+// IsSnapshotTestsCompletedConditionSet returns true if the AppStudio tests completed condition of the Snapshot was set
+func IsSnapshotTestsCompletedConditionSet(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return meta.FindStatusCondition(snapshot.Status.Conditions, AppStudioTestsCompletedCondition) != nil
+}
+
+// HaveSnapshotTestsCompleted returns true if all the required tests of the Snapshot reached a final state, and whether
+// they passed. Snapshots without the AppStudio tests completed condition fall back to the AppStudio test succeeded condition.
+func HaveSnapshotTestsCompleted(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+	condition := meta.FindStatusCondition(snapshot.Status.Conditions, AppStudioTestsCompletedCondition)
+	if condition == nil {
+		return HaveAppStudioTestsFinished(snapshot), HaveAppStudioTestsSucceeded(snapshot)
+	}
+	completed := condition.Status == metav1.ConditionTrue
+	return completed, completed && condition.Reason == AppStudioTestSucceededConditionSatisfied
+}
+
+// MarkSnapshotTestsAsInProgress updates the AppStudio tests completed condition for the Snapshot to in progress.
+// If the patch command fails, an error will be returned.
+func MarkSnapshotTestsAsInProgress(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, message string) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+		Type:    AppStudioTestsCompletedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  AppStudioTestsCompletedConditionInProgress,
+		Message: message,
+	})
+	return adapterClient.Status().Patch(ctx, snapshot, patch)
+}
+
+// MarkSnapshotTestsAsCompleted updates the AppStudio tests completed condition for the Snapshot to completed,
+// with the Passed or Failed reason. If the patch command fails, an error will be returned.
+func MarkSnapshotTestsAsCompleted(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, passed bool, message string) error {
+	reason := AppStudioTestSucceededConditionFailed
+	if passed {
+		reason = AppStudioTestSucceededConditionSatisfied
+	}
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+		Type:    AppStudioTestsCompletedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	return adapterClient.Status().Patch(ctx, snapshot, patch)
+}
+
 // HaveAppStudioTestsSucceeded checks if the AppStudio tests have finished by checking if the AppStudio Test Succeeded condition is set.
 func HaveAppStudioTestsSucceeded(snapshot *applicationapiv1alpha1.Snapshot) bool {
 	if meta.FindStatusCondition(snapshot.Status.Conditions, AppStudioTestSucceededCondition) == nil {
@@ -512,11 +925,12 @@ func GetAppStudioTestsFinishedTime(snapshot *applicationapiv1alpha1.Snapshot) (m
 func CanSnapshotBePromoted(snapshot *applicationapiv1alpha1.Snapshot) (bool, []string) {
 	canBePromoted := true
 	reasons := make([]string, 0)
-	if !HaveAppStudioTestsFinished(snapshot) {
+	testsCompleted, testsPassed := HaveSnapshotTestsCompleted(snapshot)
+	if !testsCompleted {
 		canBePromoted = false
 		reasons = append(reasons, "the Snapshot has not yet finished testing")
 	} else {
-		if !HaveAppStudioTestsSucceeded(snapshot) {
+		if !testsPassed {
 			canBePromoted = false
 			reasons = append(reasons, "the Snapshot hasn't passed all required integration tests")
 		}
@@ -544,6 +958,23 @@ func NewSnapshot(application *applicationapiv1alpha1.Application, snapshotCompon
 			Components:  *snapshotComponents,
 		},
 	}
+
+	if checkPrefix, ok := application.GetAnnotations()[CheckPrefixAnnotation]; ok {
+		_ = metadata.SetAnnotation(&snapshot.ObjectMeta, CheckPrefixAnnotation, checkPrefix)
+	}
+
+	if mergeTrainCheck, ok := application.GetAnnotations()[MergeTrainCheckAnnotation]; ok {
+		_ = metadata.SetAnnotation(&snapshot.ObjectMeta, MergeTrainCheckAnnotation, mergeTrainCheck)
+	}
+
+	if externalStatusCheck, ok := application.GetAnnotations()[ExternalStatusCheckAnnotation]; ok {
+		_ = metadata.SetAnnotation(&snapshot.ObjectMeta, ExternalStatusCheckAnnotation, externalStatusCheck)
+	}
+
+	if alsoReportTo, ok := application.GetAnnotations()[AlsoReportToAnnotation]; ok {
+		_ = metadata.SetAnnotation(&snapshot.ObjectMeta, AlsoReportToAnnotation, alsoReportTo)
+	}
+
 	return snapshot
 }
 
@@ -599,6 +1030,98 @@ func IsSnapshotCreatedBySamePACEvent(snapshot1, snapshot2 *applicationapiv1alpha
 	return false
 }
 
+// IsContextValidForSnapshot checks if the given IntegrationTestScenario context applies to the Snapshot
+func IsContextValidForSnapshot(scenarioContextName string, snapshot *applicationapiv1alpha1.Snapshot) bool {
+	isComponentSnapshot := metadata.HasLabelWithValue(snapshot, SnapshotTypeLabel, SnapshotComponentType)
+	switch {
+	case scenarioContextName == ApplicationContext:
+		return true
+	case scenarioContextName == ComponentContext:
+		return isComponentSnapshot
+	case strings.HasPrefix(scenarioContextName, ComponentContextPrefix):
+		componentName := strings.TrimPrefix(scenarioContextName, ComponentContextPrefix)
+		return isComponentSnapshot && metadata.HasLabelWithValue(snapshot, SnapshotComponentLabel, componentName)
+	case scenarioContextName == PullRequestContext:
+		return !IsSnapshotCreatedByPACPushEvent(snapshot)
+	case scenarioContextName == PushContext:
+		return IsSnapshotCreatedByPACPushEvent(snapshot)
+	}
+	return false
+}
+
+// IsKnownContext checks if the given IntegrationTestScenario context, optionally negated, is one of the contexts
+// evaluated for Snapshots
+func IsKnownContext(scenarioContextName string) bool {
+	scenarioContextName = strings.TrimPrefix(scenarioContextName, NegatedContextPrefix)
+	switch {
+	case scenarioContextName == ApplicationContext, scenarioContextName == ComponentContext,
+		scenarioContextName == PullRequestContext, scenarioContextName == PushContext:
+		return true
+	case strings.HasPrefix(scenarioContextName, ComponentContextPrefix):
+		return strings.TrimPrefix(scenarioContextName, ComponentContextPrefix) != ""
+	}
+	return false
+}
+
+// GetUnknownContexts returns the names of the contexts of the IntegrationTestScenario which aren't evaluated for Snapshots
+func GetUnknownContexts(scenario *v1beta2.IntegrationTestScenario) []string {
+	unknownContexts := []string{}
+	for _, scenarioContext := range scenario.Spec.Contexts {
+		if !IsKnownContext(scenarioContext.Name) {
+			unknownContexts = append(unknownContexts, scenarioContext.Name)
+		}
+	}
+	return unknownContexts
+}
+
+// IsScenarioApplicableToSnapshotsContext checks if the contexts of the IntegrationTestScenario apply to the Snapshot.
+// The scenario applies when any of its contexts applies, or it has only negated contexts, and none of its negated
+// contexts, prefixed with "!", applies. Scenarios without contexts apply to all Snapshots. Unknown contexts apply
+// to all Snapshots and unknown negated contexts exclude none, so scenarios using them are never skipped because of them.
+func IsScenarioApplicableToSnapshotsContext(scenario *v1beta2.IntegrationTestScenario, snapshot *applicationapiv1alpha1.Snapshot) bool {
+	hasContexts, hasApplicableContext := false, false
+	for _, scenarioContext := range scenario.Spec.Contexts {
+		if negatedContextName, negated := strings.CutPrefix(scenarioContext.Name, NegatedContextPrefix); negated {
+			if IsKnownContext(negatedContextName) && IsContextValidForSnapshot(negatedContextName, snapshot) {
+				return false
+			}
+			continue
+		}
+		hasContexts = true
+		if !IsKnownContext(scenarioContext.Name) || IsContextValidForSnapshot(scenarioContext.Name, snapshot) {
+			hasApplicableContext = true
+		}
+	}
+	return !hasContexts || hasApplicableContext
+}
+
+// IsScenarioApplicableToSnapshotsTargetBranch checks if the target branch of the Snapshot matches any of the target
+// branches of the IntegrationTestScenario, either exactly or as a regular expression matching the whole branch name.
+// Scenarios without target branches apply to all Snapshots, Snapshots without a target branch match no target branches.
+func IsScenarioApplicableToSnapshotsTargetBranch(scenario *v1beta2.IntegrationTestScenario, snapshot *applicationapiv1alpha1.Snapshot) (bool, error) {
+	if len(scenario.Spec.TargetBranches) == 0 {
+		return true, nil
+	}
+	targetBranch, ok := snapshot.GetLabels()[BuildTargetBranchLabel]
+	if !ok || targetBranch == "" {
+		return false, nil
+	}
+
+	for _, scenarioTargetBranch := range scenario.Spec.TargetBranches {
+		if scenarioTargetBranch == targetBranch {
+			return true, nil
+		}
+		targetBranchRegex, err := regexp.Compile("^(?:" + scenarioTargetBranch + ")$")
+		if err != nil {
+			return false, fmt.Errorf("failed to compile the target branch %q of scenario %s: %w", scenarioTargetBranch, scenario.Name, err)
+		}
+		if targetBranchRegex.MatchString(targetBranch) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // HasSnapshotTestingChangedToFinished returns a boolean indicating whether the Snapshot testing status has
 // changed to finished. If the objects passed to this function are not Snapshots, the function will return false.
 func HasSnapshotTestingChangedToFinished(objectOld, objectNew client.Object) bool {
@@ -650,6 +1173,29 @@ func HasSnapshotRerunLabelChanged(objectOld, objectNew client.Object) bool {
 	return false
 }
 
+// HasSnapshotBeenMarkedWithoutIntegrationTests returns a boolean indicating whether the Snapshot was just marked as having
+// no integration tests. If the objects passed to this function are not Snapshots, the function will return false.
+func HasSnapshotBeenMarkedWithoutIntegrationTests(objectOld, objectNew client.Object) bool {
+	if oldSnapshot, ok := objectOld.(*applicationapiv1alpha1.Snapshot); ok {
+		if newSnapshot, ok := objectNew.(*applicationapiv1alpha1.Snapshot); ok {
+			return !IsSnapshotWithoutIntegrationTests(oldSnapshot) && IsSnapshotWithoutIntegrationTests(newSnapshot)
+		}
+	}
+	return false
+}
+
+// HasSnapshotPipelineRunsRecreationRequested returns a boolean indicating whether the recreation of the integration
+// pipelineRuns of the Snapshot was requested. If the objects passed to this function are not Snapshots, the function will return false.
+func HasSnapshotPipelineRunsRecreationRequested(objectOld, objectNew client.Object) bool {
+	if oldSnapshot, ok := objectOld.(*applicationapiv1alpha1.Snapshot); ok {
+		if newSnapshot, ok := objectNew.(*applicationapiv1alpha1.Snapshot); ok {
+			newValue, ok := newSnapshot.GetAnnotations()[SnapshotPipelineRunsRecreationAnnotation]
+			return ok && oldSnapshot.GetAnnotations()[SnapshotPipelineRunsRecreationAnnotation] != newValue
+		}
+	}
+	return false
+}
+
 // PrepareSnapshot prepares the Snapshot for a given application, components and the updated component (if any).
 // In case the Snapshot can't be created, an error will be returned.
 func PrepareSnapshot(ctx context.Context, adapterClient client.Client, application *applicationapiv1alpha1.Application, applicationComponents *[]applicationapiv1alpha1.Component, component *applicationapiv1alpha1.Component, newContainerImage string, newComponentSource *applicationapiv1alpha1.ComponentSource) (*applicationapiv1alpha1.Snapshot, error) {
@@ -715,6 +1261,40 @@ func FindMatchingSnapshot(application *applicationapiv1alpha1.Application, allSn
 	return nil
 }
 
+// IsTestResultsReuseEnabled returns true if the Application opted in to reuse the test results
+// of earlier Snapshots with identical components
+func IsTestResultsReuseEnabled(application *applicationapiv1alpha1.Application) bool {
+	return metadata.HasAnnotationWithValue(application, ReuseTestResultsAnnotation, "true")
+}
+
+// FindSnapshotWithReusableTestResults returns the most recent Snapshot created before the given Snapshot
+// with identical components and the same event context which finished testing and wasn't canceled,
+// nil is returned if there is none
+func FindSnapshotWithReusableTestResults(application *applicationapiv1alpha1.Application, allSnapshots *[]applicationapiv1alpha1.Snapshot, snapshot *applicationapiv1alpha1.Snapshot) *applicationapiv1alpha1.Snapshot {
+	candidates := []applicationapiv1alpha1.Snapshot{}
+	for _, candidate := range *allSnapshots {
+		candidate := candidate // G601
+		if candidate.Name == snapshot.Name || !candidate.CreationTimestamp.Before(&snapshot.CreationTimestamp) ||
+			!HaveAppStudioTestsFinished(&candidate) || AreSnapshotTestsCanceled(&candidate) ||
+			!haveSameEventContext(&candidate, snapshot) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	slices.SortStableFunc(candidates, func(a, b applicationapiv1alpha1.Snapshot) int {
+		return b.CreationTimestamp.Time.Compare(a.CreationTimestamp.Time)
+	})
+
+	return FindMatchingSnapshot(application, &candidates, snapshot)
+}
+
+// haveSameEventContext returns true if the given Snapshots were created for the same type of event and target branch,
+// the params of the IntegrationTestScenarios can depend on them
+func haveSameEventContext(snapshot, otherSnapshot *applicationapiv1alpha1.Snapshot) bool {
+	return snapshot.GetLabels()[PipelineAsCodeEventTypeLabel] == otherSnapshot.GetLabels()[PipelineAsCodeEventTypeLabel] &&
+		snapshot.GetAnnotations()[PipelineAsCodeBranchAnnotation] == otherSnapshot.GetAnnotations()[PipelineAsCodeBranchAnnotation]
+}
+
 // GetComponentSourceFromComponent gets the component source from the given Component as Revision
 // and set Component.Status.LastBuiltCommit as Component.Source.GitSource.Revision if it is defined.
 func GetComponentSourceFromComponent(component *applicationapiv1alpha1.Component) *applicationapiv1alpha1.ComponentSource {
@@ -732,12 +1312,23 @@ func GetIntegrationTestRunLabelValue(obj metav1.Object) (string, bool) {
 	return labelVal, ok
 }
 
-// RemoveIntegrationTestRerunLabel removes re-run label from snapshot
+// IsIntegrationTestRerunForced returns true if the re-run requested by the re-run label should cancel
+// the pipelineRuns of the scenario tests which are still running
+func IsIntegrationTestRerunForced(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasLabelWithValue(snapshot, SnapshotIntegrationTestRunForceLabel, "true")
+}
+
+// RemoveIntegrationTestRerunLabel removes re-run labels, the scheduled run annotation and the annotation
+// of the previously refused re-run from snapshot
 func RemoveIntegrationTestRerunLabel(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) error {
 	patch := client.MergeFrom(snapshot.DeepCopy())
-	err := metadata.DeleteLabel(snapshot, SnapshotIntegrationTestRun)
+	err := removeIntegrationTestRerunMetadata(snapshot)
+	if err != nil {
+		return err
+	}
+	err = metadata.DeleteAnnotation(snapshot, SnapshotRerunRefusedAnnotation)
 	if err != nil {
-		return fmt.Errorf("failed to delete label %s: %w", SnapshotIntegrationTestRun, err)
+		return fmt.Errorf("failed to delete annotation %s: %w", SnapshotRerunRefusedAnnotation, err)
 	}
 	err = adapterClient.Patch(ctx, snapshot, patch)
 	if err != nil {
@@ -747,6 +1338,39 @@ func RemoveIntegrationTestRerunLabel(ctx context.Context, adapterClient client.C
 	return nil
 }
 
+// RefuseIntegrationTestRerun removes re-run labels and the scheduled run annotation from snapshot
+// and annotates it with the reason why the re-run was refused
+func RefuseIntegrationTestRerun(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, reason string) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err := removeIntegrationTestRerunMetadata(snapshot)
+	if err != nil {
+		return err
+	}
+	err = metadata.SetAnnotation(snapshot, SnapshotRerunRefusedAnnotation, reason)
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SnapshotRerunRefusedAnnotation, err)
+	}
+	err = adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// removeIntegrationTestRerunMetadata removes re-run labels and the scheduled run annotation from snapshot without patching it
+func removeIntegrationTestRerunMetadata(snapshot *applicationapiv1alpha1.Snapshot) error {
+	for _, label := range []string{SnapshotIntegrationTestRun, SnapshotIntegrationTestRunForceLabel} {
+		if err := metadata.DeleteLabel(snapshot, label); err != nil {
+			return fmt.Errorf("failed to delete label %s: %w", label, err)
+		}
+	}
+	if err := metadata.DeleteAnnotation(snapshot, SnapshotScheduledRunAnnotation); err != nil {
+		return fmt.Errorf("failed to delete annotation %s: %w", SnapshotScheduledRunAnnotation, err)
+	}
+	return nil
+}
+
 // AddIntegrationTestRerunLabel adding re-run label to snapshot
 func AddIntegrationTestRerunLabel(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, integrationTestScenarioName string) error {
 	patch := client.MergeFrom(snapshot.DeepCopy())
@@ -764,6 +1388,216 @@ func AddIntegrationTestRerunLabel(ctx context.Context, adapterClient client.Clie
 	return nil
 }
 
+// AddScheduledIntegrationTestRunLabel adds the re-run label to snapshot and marks the run as triggered by the schedule of the scenario
+func AddScheduledIntegrationTestRunLabel(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, integrationTestScenarioName string) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err := metadata.SetLabel(snapshot, SnapshotIntegrationTestRun, integrationTestScenarioName)
+	if err != nil {
+		return fmt.Errorf("failed to add label %s: %w", SnapshotIntegrationTestRun, err)
+	}
+	err = metadata.SetAnnotation(snapshot, SnapshotScheduledRunAnnotation, integrationTestScenarioName)
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SnapshotScheduledRunAnnotation, err)
+	}
+	err = adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// IsScheduledIntegrationTestRun returns true if the run of the given scenario requested for the snapshot
+// was triggered by the schedule of the scenario
+func IsScheduledIntegrationTestRun(snapshot *applicationapiv1alpha1.Snapshot, integrationTestScenarioName string) bool {
+	return metadata.HasAnnotationWithValue(snapshot, SnapshotScheduledRunAnnotation, integrationTestScenarioName)
+}
+
+// GetLatestPushSnapshot returns the most recently created Snapshot among the given ones which was created
+// for a push event and wasn't superseded, or nil if there is no such Snapshot
+func GetLatestPushSnapshot(snapshots []applicationapiv1alpha1.Snapshot) *applicationapiv1alpha1.Snapshot {
+	var latestSnapshot *applicationapiv1alpha1.Snapshot
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		if !IsSnapshotCreatedByPACPushEvent(snapshot) || IsSnapshotSuperseded(snapshot) || IsSnapshotMarkedAsInvalid(snapshot) {
+			continue
+		}
+		if latestSnapshot == nil || latestSnapshot.CreationTimestamp.Before(&snapshot.CreationTimestamp) {
+			latestSnapshot = snapshot
+		}
+	}
+
+	return latestSnapshot
+}
+
+// GetSlackNotifiedScenarios returns the names of the scenarios of the Snapshot which were already notified to Slack
+func GetSlackNotifiedScenarios(snapshot *applicationapiv1alpha1.Snapshot) ([]string, error) {
+	value, ok := snapshot.GetAnnotations()[SlackNotifiedScenariosAnnotation]
+	if !ok || value == "" {
+		return []string{}, nil
+	}
+	return strings.Split(value, ","), nil
+}
+
+// SetSlackNotifiedScenarios records the names of the scenarios of the Snapshot which were notified to Slack,
+// the Snapshot isn't patched when the recorded scenarios didn't change
+func SetSlackNotifiedScenarios(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, scenarioNames []string) error {
+	scenarioNames = slices.Clone(scenarioNames)
+	slices.Sort(scenarioNames)
+	value := strings.Join(slices.Compact(scenarioNames), ",")
+	if snapshot.GetAnnotations()[SlackNotifiedScenariosAnnotation] == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err := metadata.SetAnnotation(snapshot, SlackNotifiedScenariosAnnotation, value)
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SlackNotifiedScenariosAnnotation, err)
+	}
+	err = adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// SetSnapshotAutoReleases records the names of the Releases created for the Snapshot by the auto-release,
+// the Snapshot isn't patched when the recorded Releases didn't change
+func SetSnapshotAutoReleases(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, releaseNames []string) error {
+	releaseNames = slices.Clone(releaseNames)
+	slices.Sort(releaseNames)
+	value := strings.Join(slices.Compact(releaseNames), ",")
+	if value == "" {
+		value = NoAutoReleasePlanMatched
+	}
+	if snapshot.GetAnnotations()[SnapshotAutoReleasesAnnotation] == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err := metadata.SetAnnotation(snapshot, SnapshotAutoReleasesAnnotation, value)
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SnapshotAutoReleasesAnnotation, err)
+	}
+	err = adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetSnapshotCandidateUpdateErrors returns the errors which prevented updating the global candidate list
+// with the Components of the Snapshot, keyed by the name of the Component
+func GetSnapshotCandidateUpdateErrors(snapshot *applicationapiv1alpha1.Snapshot) (map[string]string, error) {
+	candidateUpdateErrors := map[string]string{}
+	value, ok := snapshot.GetAnnotations()[SnapshotCandidateUpdateErrorsAnnotation]
+	if !ok || value == "" {
+		return candidateUpdateErrors, nil
+	}
+	if err := json.Unmarshal([]byte(value), &candidateUpdateErrors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotation %s: %w", SnapshotCandidateUpdateErrorsAnnotation, err)
+	}
+	return candidateUpdateErrors, nil
+}
+
+// SetSnapshotCandidateUpdateErrors records the errors which prevented updating the global candidate list with the
+// Components of the Snapshot, the annotation is removed when there are no errors. The Snapshot isn't patched when
+// the recorded errors didn't change
+func SetSnapshotCandidateUpdateErrors(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, candidateUpdateErrors map[string]string) error {
+	_, hasAnnotation := snapshot.GetAnnotations()[SnapshotCandidateUpdateErrorsAnnotation]
+	if len(candidateUpdateErrors) == 0 && !hasAnnotation {
+		return nil
+	}
+	if len(candidateUpdateErrors) > 0 {
+		recordedErrors, err := GetSnapshotCandidateUpdateErrors(snapshot)
+		if err == nil && reflect.DeepEqual(recordedErrors, candidateUpdateErrors) {
+			return nil
+		}
+	}
+
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	if len(candidateUpdateErrors) == 0 {
+		delete(snapshot.Annotations, SnapshotCandidateUpdateErrorsAnnotation)
+	} else {
+		value, err := json.Marshal(candidateUpdateErrors)
+		if err != nil {
+			return fmt.Errorf("failed to marshal candidate update errors into JSON: %w", err)
+		}
+		err = metadata.SetAnnotation(snapshot, SnapshotCandidateUpdateErrorsAnnotation, string(value))
+		if err != nil {
+			return fmt.Errorf("failed to add annotation %s: %w", SnapshotCandidateUpdateErrorsAnnotation, err)
+		}
+	}
+	err := adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// HasTestResultsArtifact returns true if the final test reports of the Snapshot were already archived,
+// or if it was recorded that they can't be archived
+func HasTestResultsArtifact(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return snapshot.GetAnnotations()[SnapshotTestResultsArtifactAnnotation] != ""
+}
+
+// SetTestResultsArtifact records the digest of the OCI artifact archiving the final test reports of the Snapshot
+func SetTestResultsArtifact(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, digest string) error {
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err := metadata.SetAnnotation(snapshot, SnapshotTestResultsArtifactAnnotation, digest)
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SnapshotTestResultsArtifactAnnotation, err)
+	}
+	err = adapterClient.Patch(ctx, snapshot, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetExportedResults returns the results exported by the passed tests of the Snapshot, keyed by the name of the test
+// and the name of the result
+func GetExportedResults(snapshot *applicationapiv1alpha1.Snapshot) (map[string]map[string]string, error) {
+	exportedResults := map[string]map[string]string{}
+	value, ok := snapshot.GetAnnotations()[SnapshotExportedResultsAnnotation]
+	if !ok || value == "" {
+		return exportedResults, nil
+	}
+	if err := json.Unmarshal([]byte(value), &exportedResults); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotation %s: %w", SnapshotExportedResultsAnnotation, err)
+	}
+	return exportedResults, nil
+}
+
+// SetExportedResults records the results exported by the given test of the Snapshot, replacing the results previously
+// exported by the test. The Snapshot isn't patched when the exported results didn't change
+func SetExportedResults(ctx context.Context, adapterClient client.Client, snapshot *applicationapiv1alpha1.Snapshot, testName string, results map[string]string) error {
+	exportedResults, err := GetExportedResults(snapshot)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(exportedResults[testName], results) {
+		return nil
+	}
+	exportedResults[testName] = results
+
+	value, err := json.Marshal(exportedResults)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exported results into JSON: %w", err)
+	}
+	patch := client.MergeFrom(snapshot.DeepCopy())
+	err = metadata.SetAnnotation(snapshot, SnapshotExportedResultsAnnotation, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to add annotation %s: %w", SnapshotExportedResultsAnnotation, err)
+	}
+	// don't return wrapped err, so we can use RetryOnConflict
+	return adapterClient.Patch(ctx, snapshot, patch)
+}
+
 // Deprecated
 func GetLatestUpdateTime(snapshot *applicationapiv1alpha1.Snapshot) (time.Time, error) {
 	latestUpdateTime := snapshot.GetAnnotations()[SnapshotPRLastUpdate]
@@ -793,6 +1627,12 @@ func ResetSnapshotStatusConditions(ctx context.Context, adapterClient client.Cli
 			Reason:  AppStudioIntegrationStatusInProgress,
 			Message: message,
 		})
+		meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+			Type:    AppStudioTestsCompletedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  AppStudioTestsCompletedConditionInProgress,
+			Message: message,
+		})
 
 		err := adapterClient.Status().Patch(ctx, snapshot, patch)
 		return err
@@ -830,3 +1670,21 @@ func CopySnapshotLabelsAndAnnotation(application *applicationapiv1alpha1.Applica
 	_ = metadata.CopyAnnotationsByPrefix(source, &snapshot.ObjectMeta, prefix)
 
 }
+
+// GetDebugKeepDuration returns the duration for which the failed integration PipelineRuns are kept for debugging
+// as requested by the DebugKeepAnnotation of the given object, bounded by MaxDebugKeepDuration. Zero is returned
+// if the annotation isn't set, an error is returned if its value isn't a positive duration.
+func GetDebugKeepDuration(object metav1.Object) (time.Duration, error) {
+	value, ok := object.GetAnnotations()[DebugKeepAnnotation]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse annotation %s: %w", DebugKeepAnnotation, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("annotation %s must be a positive duration, got %q", DebugKeepAnnotation, value)
+	}
+	return min(duration, MaxDebugKeepDuration), nil
+}
@@ -17,16 +17,77 @@ limitations under the License.
 package gitops
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/konflux-ci/integration-service/metrics"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// SnapshotTestsStatusCompressedPrefix marks the test status annotation whose JSON is gzip compressed and base64 encoded
+const SnapshotTestsStatusCompressedPrefix = "gzip+base64:"
+
+// SnapshotTestsStatusCompressionThreshold is the size in bytes of the JSON of the test statuses above which
+// the test status annotation is compressed, smaller annotations are kept as plain JSON to stay human-readable
+var SnapshotTestsStatusCompressionThreshold = 32 * 1024
+
+// EncodeIntegrationTestStatuses returns the value of the test status annotation for the given statuses,
+// the JSON is compressed only when it exceeds SnapshotTestsStatusCompressionThreshold
+func EncodeIntegrationTestStatuses(sts *intgteststat.SnapshotIntegrationTestStatuses) (string, error) {
+	value, err := json.Marshal(sts)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal test results into JSON: %w", err)
+	}
+	if len(value) <= SnapshotTestsStatusCompressionThreshold {
+		return string(value), nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(value); err != nil {
+		return "", fmt.Errorf("failed to compress test results: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress test results: %w", err)
+	}
+	return SnapshotTestsStatusCompressedPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeIntegrationTestStatusesAnnotation returns the JSON of the test status annotation, decompressing it
+// when the annotation is marked as compressed
+func decodeIntegrationTestStatusesAnnotation(statusAnnotation string) (string, error) {
+	encoded, compressed := strings.CutPrefix(statusAnnotation, SnapshotTestsStatusCompressedPrefix)
+	if !compressed {
+		return statusAnnotation, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode compressed test results: %w", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(decoded))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress test results: %w", err)
+	}
+	defer reader.Close()
+	value, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress test results: %w", err)
+	}
+	return string(value), nil
+}
+
 // NewSnapshotIntegrationTestStatusesFromSnapshot creates new SnapshotTestStatus struct from snapshot annotation
 func NewSnapshotIntegrationTestStatusesFromSnapshot(s *applicationapiv1alpha1.Snapshot) (*intgteststat.SnapshotIntegrationTestStatuses, error) {
 	annotations := map[string]string{}
@@ -37,6 +98,10 @@ func NewSnapshotIntegrationTestStatusesFromSnapshot(s *applicationapiv1alpha1.Sn
 	if !ok {
 		statusAnnotation = ""
 	}
+	statusAnnotation, err := decodeIntegrationTestStatusesAnnotation(statusAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get integration tests statuses from snapshot: %w", err)
+	}
 	sits, err := intgteststat.NewSnapshotIntegrationTestStatuses(statusAnnotation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get integration tests statuses from snapshot: %w", err)
@@ -54,12 +119,12 @@ func WriteIntegrationTestStatusesIntoSnapshot(ctx context.Context, s *applicatio
 	}
 	patch := client.MergeFrom(s.DeepCopy())
 
-	value, err := json.Marshal(sts)
+	value, err := EncodeIntegrationTestStatuses(sts)
 	if err != nil {
-		return fmt.Errorf("failed to marshal test results into JSON: %w", err)
+		return err
 	}
 
-	if err := metadata.SetAnnotation(&s.ObjectMeta, SnapshotTestsStatusAnnotation, string(value)); err != nil {
+	if err := metadata.SetAnnotation(&s.ObjectMeta, SnapshotTestsStatusAnnotation, value); err != nil {
 		return fmt.Errorf("failed to add annotations: %w", err)
 	}
 
@@ -71,3 +136,75 @@ func WriteIntegrationTestStatusesIntoSnapshot(ctx context.Context, s *applicatio
 	sts.ResetDirty()
 	return nil
 }
+
+// MarkSnapshotAsSuperseded annotates the snapshot as superseded by the given newer snapshot and cancels
+// its unfinished integration tests, so their final state can be reported to the git provider
+func MarkSnapshotAsSuperseded(ctx context.Context, c client.Client, s *applicationapiv1alpha1.Snapshot, supersedingSnapshotName string) error {
+	return cancelUnfinishedTests(ctx, c, s, SnapshotSupersededByAnnotation, supersedingSnapshotName,
+		fmt.Sprintf("Integration test was canceled because the snapshot was superseded by snapshot %s", supersedingSnapshotName))
+}
+
+// MarkSnapshotAsObsoletedByOverride annotates the pull request snapshot as obsoleted by the given override snapshot
+// and cancels its unfinished integration tests, so their final state can be reported to the git provider
+func MarkSnapshotAsObsoletedByOverride(ctx context.Context, c client.Client, s *applicationapiv1alpha1.Snapshot, overrideSnapshotName string) error {
+	return cancelUnfinishedTests(ctx, c, s, SnapshotObsoletedByOverrideAnnotation, overrideSnapshotName,
+		fmt.Sprintf("Integration test was canceled because the snapshot was obsoleted by override snapshot %s", overrideSnapshotName))
+}
+
+// MarkSnapshotAsCanceledByPRMR annotates the pull request snapshot as canceled because its pull/merge request
+// is in the given state, closed or merged, cancels its unfinished integration tests, so their final state can be
+// reported to the git provider, and marks the snapshot as failed
+func MarkSnapshotAsCanceledByPRMR(ctx context.Context, c client.Client, s *applicationapiv1alpha1.Snapshot, prmrState string) error {
+	err := cancelUnfinishedTests(ctx, c, s, SnapshotCanceledByPRMRAnnotation, prmrState,
+		fmt.Sprintf("Integration test was canceled because the pull request was %s", prmrState))
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(s.DeepCopy())
+	condition := metav1.Condition{
+		Type:    AppStudioTestSucceededCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  AppStudioTestSucceededConditionPRClosed,
+		Message: fmt.Sprintf("Integration tests were canceled because the pull request was %s", prmrState),
+	}
+	meta.SetStatusCondition(&s.Status.Conditions, condition)
+	err = c.Status().Patch(ctx, s, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot status: %w", err)
+	}
+
+	go metrics.RegisterCompletedSnapshot(condition.Type, condition.Reason, s.GetCreationTimestamp(), &metav1.Time{Time: time.Now()})
+	return nil
+}
+
+// cancelUnfinishedTests sets the given annotation on the snapshot and moves its unfinished integration tests
+// to the Deleted state with the given details
+func cancelUnfinishedTests(ctx context.Context, c client.Client, s *applicationapiv1alpha1.Snapshot, annotation, snapshotName, details string) error {
+	sts, err := NewSnapshotIntegrationTestStatusesFromSnapshot(s)
+	if err != nil {
+		return err
+	}
+	for _, detail := range sts.GetStatuses() {
+		if !detail.Status.IsFinal() {
+			sts.UpdateTestStatusIfChanged(detail.ScenarioName, intgteststat.IntegrationTestStatusDeleted, details)
+		}
+	}
+
+	patch := client.MergeFrom(s.DeepCopy())
+	_ = metadata.SetAnnotation(&s.ObjectMeta, annotation, snapshotName)
+	if sts.IsDirty() {
+		value, err := EncodeIntegrationTestStatuses(sts)
+		if err != nil {
+			return err
+		}
+		_ = metadata.SetAnnotation(&s.ObjectMeta, SnapshotTestsStatusAnnotation, value)
+	}
+
+	err = c.Patch(ctx, s, patch)
+	if err != nil {
+		return fmt.Errorf("failed to patch snapshot: %w", err)
+	}
+	sts.ResetDirty()
+	return nil
+}
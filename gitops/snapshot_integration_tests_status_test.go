@@ -121,6 +121,67 @@ var _ = Describe("Snapshot integration test statuses", func() {
 
 		})
 
+		When("Snapshot contains compressed test status annotation", func() {
+			BeforeEach(func() {
+				gitops.SnapshotTestsStatusCompressionThreshold = 0
+				sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestFail, testDetails)
+				testAnnotation, err := gitops.EncodeIntegrationTestStatuses(sits)
+				Expect(err).To(BeNil())
+				Expect(testAnnotation).To(HavePrefix(gitops.SnapshotTestsStatusCompressedPrefix))
+				err = metadata.SetAnnotation(snapshot, gitops.SnapshotTestsStatusAnnotation, testAnnotation)
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				gitops.SnapshotTestsStatusCompressionThreshold = 32 * 1024
+			})
+
+			It("Returns expected test statuses", func() {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				Expect(err).To(BeNil())
+				Expect(statuses.GetStatuses()).To(HaveLen(1))
+
+				statusDetail := statuses.GetStatuses()[0]
+				Expect(statusDetail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(statusDetail.ScenarioName).To(Equal(testScenarioName))
+				Expect(statusDetail.Details).To(Equal(testDetails))
+			})
+		})
+
+		When("Snapshot contains corrupted compressed test status annotation", func() {
+			BeforeEach(func() {
+				err := metadata.SetAnnotation(
+					snapshot, gitops.SnapshotTestsStatusAnnotation, gitops.SnapshotTestsStatusCompressedPrefix+"not-gzip")
+				Expect(err).To(BeNil())
+			})
+
+			It("Returns error", func() {
+				_, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
+		It("Compresses the test status annotation only above the threshold", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			plain, err := json.Marshal(sits)
+			Expect(err).To(BeNil())
+
+			value, err := gitops.EncodeIntegrationTestStatuses(sits)
+			Expect(err).To(BeNil())
+			Expect(value).To(MatchJSON(plain))
+
+			gitops.SnapshotTestsStatusCompressionThreshold = len(plain) - 1
+			defer func() { gitops.SnapshotTestsStatusCompressionThreshold = 32 * 1024 }()
+			value, err = gitops.EncodeIntegrationTestStatuses(sits)
+			Expect(err).To(BeNil())
+			Expect(value).To(HavePrefix(gitops.SnapshotTestsStatusCompressedPrefix))
+
+			Expect(metadata.SetAnnotation(snapshot, gitops.SnapshotTestsStatusAnnotation, value)).To(Succeed())
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+			Expect(err).To(BeNil())
+			Expect(json.Marshal(statuses)).To(MatchJSON(plain))
+		})
+
 		When("Snapshot contains invalid test status annotation", func() {
 			BeforeEach(func() {
 				err := metadata.SetAnnotation(
@@ -193,6 +254,63 @@ var _ = Describe("Snapshot integration test statuses", func() {
 				Expect(err).To(BeNil())
 				Expect(statuses.GetStatuses()).To(HaveLen(1))
 			})
+
+			It("Cancels unfinished tests when the snapshot is superseded", func() {
+				sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+				sits.UpdateTestStatusIfChanged("finished-scenario", intgteststat.IntegrationTestStatusTestPassed, testDetails)
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, snapshot, sits, k8sClient)).To(Succeed())
+
+				Expect(gitops.MarkSnapshotAsSuperseded(ctx, k8sClient, snapshot, "snapshot-newer")).To(Succeed())
+				Expect(gitops.IsSnapshotSuperseded(snapshot)).To(BeTrue())
+				Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotSupersededByAnnotation, "snapshot-newer"))
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				Expect(err).To(BeNil())
+				canceledDetail, ok := statuses.GetScenarioStatus(testScenarioName)
+				Expect(ok).To(BeTrue())
+				Expect(canceledDetail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+				Expect(canceledDetail.Details).To(ContainSubstring("superseded by snapshot snapshot-newer"))
+				finishedDetail, ok := statuses.GetScenarioStatus("finished-scenario")
+				Expect(ok).To(BeTrue())
+				Expect(finishedDetail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			})
+
+			It("Cancels unfinished tests when the snapshot is obsoleted by an override snapshot", func() {
+				sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, snapshot, sits, k8sClient)).To(Succeed())
+
+				Expect(gitops.MarkSnapshotAsObsoletedByOverride(ctx, k8sClient, snapshot, "snapshot-override")).To(Succeed())
+				Expect(gitops.IsSnapshotObsoletedByOverride(snapshot)).To(BeTrue())
+				Expect(gitops.IsSnapshotSuperseded(snapshot)).To(BeFalse())
+				Expect(gitops.IsSnapshotSupersededOrObsoleted(snapshot)).To(BeTrue())
+				Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotObsoletedByOverrideAnnotation, "snapshot-override"))
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				Expect(err).To(BeNil())
+				canceledDetail, ok := statuses.GetScenarioStatus(testScenarioName)
+				Expect(ok).To(BeTrue())
+				Expect(canceledDetail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+				Expect(canceledDetail.Details).To(ContainSubstring("obsoleted by override snapshot snapshot-override"))
+			})
+
+			It("Cancels unfinished tests and fails the snapshot when its pull request is closed", func() {
+				sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, snapshot, sits, k8sClient)).To(Succeed())
+
+				Expect(gitops.MarkSnapshotAsCanceledByPRMR(ctx, k8sClient, snapshot, "closed")).To(Succeed())
+				Expect(gitops.IsSnapshotCanceledByPRMR(snapshot)).To(BeTrue())
+				Expect(gitops.IsSnapshotSupersededOrObsoleted(snapshot)).To(BeFalse())
+				Expect(gitops.AreSnapshotTestsCanceled(snapshot)).To(BeTrue())
+				Expect(gitops.IsSnapshotStatusConditionSet(snapshot, gitops.AppStudioTestSucceededCondition,
+					metav1.ConditionFalse, gitops.AppStudioTestSucceededConditionPRClosed)).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				Expect(err).To(BeNil())
+				canceledDetail, ok := statuses.GetScenarioStatus(testScenarioName)
+				Expect(ok).To(BeTrue())
+				Expect(canceledDetail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+				Expect(canceledDetail.Details).To(Equal("Integration test was canceled because the pull request was closed"))
+			})
 		})
 
 	})
@@ -77,3 +77,41 @@ func SnapshotTestAnnotationChangePredicate() predicate.Predicate {
 		},
 	}
 }
+
+// SnapshotWithoutIntegrationTestsPredicate returns a predicate which filters out all objects except
+// when the Snapshot is marked as having no integration tests for update events.
+func SnapshotWithoutIntegrationTestsPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(genericEvent event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return HasSnapshotBeenMarkedWithoutIntegrationTests(e.ObjectOld, e.ObjectNew)
+		},
+	}
+}
+
+// SnapshotPipelineRunsRecreationPredicate returns a predicate which filters out all objects except
+// when the recreation of the integration PipelineRuns of the Snapshot is requested for update events.
+func SnapshotPipelineRunsRecreationPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(genericEvent event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return HasSnapshotPipelineRunsRecreationRequested(e.ObjectOld, e.ObjectNew)
+		},
+	}
+}
@@ -291,4 +291,74 @@ var _ = Describe("Predicates", Ordered, func() {
 		})
 
 	})
+
+	Context("testing SnapshotPipelineRunsRecreationPredicate predicate", func() {
+
+		var (
+			hasSnapshot                  *applicationapiv1alpha1.Snapshot
+			hasSnapshotRecreationAdded   *applicationapiv1alpha1.Snapshot
+			hasSnapshotRecreationUpdated *applicationapiv1alpha1.Snapshot
+		)
+
+		BeforeAll(func() {
+			hasSnapshot = &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        snapshotAnnotationOld,
+					Namespace:   namespace,
+					Annotations: map[string]string{},
+				},
+			}
+
+			hasSnapshotRecreationAdded = hasSnapshot.DeepCopy()
+			hasSnapshotRecreationAdded.Annotations[gitops.SnapshotPipelineRunsRecreationAnnotation] = "2024-05-06T10:00:00Z"
+
+			hasSnapshotRecreationUpdated = hasSnapshotRecreationAdded.DeepCopy()
+			hasSnapshotRecreationUpdated.Annotations[gitops.SnapshotPipelineRunsRecreationAnnotation] = "2024-05-06T10:05:00Z"
+		})
+		instance := gitops.SnapshotPipelineRunsRecreationPredicate()
+
+		It("returns true when the recreation is requested for the first time", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshot, ObjectNew: hasSnapshotRecreationAdded})).To(BeTrue())
+		})
+
+		It("returns true when the recreation is requested again", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshotRecreationAdded, ObjectNew: hasSnapshotRecreationUpdated})).To(BeTrue())
+		})
+
+		It("returns false when the recreation request didn't change", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshotRecreationAdded, ObjectNew: hasSnapshotRecreationAdded})).To(BeFalse())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshot, ObjectNew: hasSnapshot})).To(BeFalse())
+		})
+	})
+
+	Context("testing SnapshotWithoutIntegrationTestsPredicate predicate", func() {
+
+		var (
+			hasSnapshot             *applicationapiv1alpha1.Snapshot
+			hasSnapshotWithoutTests *applicationapiv1alpha1.Snapshot
+		)
+
+		BeforeAll(func() {
+			hasSnapshot = &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        snapshotAnnotationOld,
+					Namespace:   namespace,
+					Annotations: map[string]string{},
+				},
+			}
+
+			hasSnapshotWithoutTests = hasSnapshot.DeepCopy()
+			hasSnapshotWithoutTests.Annotations[gitops.SnapshotNoIntegrationTestsAnnotation] = "No IntegrationTestScenarios are configured for application application-sample"
+		})
+		instance := gitops.SnapshotWithoutIntegrationTestsPredicate()
+
+		It("returns true when the snapshot is marked as having no integration tests", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshot, ObjectNew: hasSnapshotWithoutTests})).To(BeTrue())
+		})
+
+		It("returns false when the snapshot was already marked as having no integration tests", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshotWithoutTests, ObjectNew: hasSnapshotWithoutTests})).To(BeFalse())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: hasSnapshot, ObjectNew: hasSnapshot})).To(BeFalse())
+		})
+	})
 })
@@ -23,7 +23,9 @@ import (
 
 	"time"
 
+	"github.com/konflux-ci/integration-service/api/v1beta2"
 	"github.com/konflux-ci/integration-service/gitops"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -322,6 +324,39 @@ var _ = Describe("Gitops functions for managing Snapshots", Ordered, func() {
 		snapshotComponents := []applicationapiv1alpha1.SnapshotComponent{}
 		createdSnapshot := gitops.NewSnapshot(hasApp, &snapshotComponents)
 		Expect(createdSnapshot).NotTo(BeNil())
+		Expect(createdSnapshot.Annotations).NotTo(HaveKey(gitops.CheckPrefixAnnotation))
+	})
+
+	It("ensures the check prefix is propagated from Application to new Snapshots", func() {
+		application := hasApp.DeepCopy()
+		application.Annotations = map[string]string{gitops.CheckPrefixAnnotation: "konflux-prod"}
+		snapshotComponents := []applicationapiv1alpha1.SnapshotComponent{}
+		createdSnapshot := gitops.NewSnapshot(application, &snapshotComponents)
+		Expect(createdSnapshot.Annotations).To(HaveKeyWithValue(gitops.CheckPrefixAnnotation, "konflux-prod"))
+	})
+
+	It("ensures the merge train check is propagated from Application to new Snapshots", func() {
+		application := hasApp.DeepCopy()
+		application.Annotations = map[string]string{gitops.MergeTrainCheckAnnotation: "true"}
+		snapshotComponents := []applicationapiv1alpha1.SnapshotComponent{}
+		createdSnapshot := gitops.NewSnapshot(application, &snapshotComponents)
+		Expect(createdSnapshot.Annotations).To(HaveKeyWithValue(gitops.MergeTrainCheckAnnotation, "true"))
+	})
+
+	It("ensures the external status check setting is propagated from Application to new Snapshots", func() {
+		application := hasApp.DeepCopy()
+		application.Annotations = map[string]string{gitops.ExternalStatusCheckAnnotation: "true"}
+		snapshotComponents := []applicationapiv1alpha1.SnapshotComponent{}
+		createdSnapshot := gitops.NewSnapshot(application, &snapshotComponents)
+		Expect(createdSnapshot.Annotations).To(HaveKeyWithValue(gitops.ExternalStatusCheckAnnotation, "true"))
+	})
+
+	It("ensures the secondary git providers are propagated from Application to new Snapshots", func() {
+		application := hasApp.DeepCopy()
+		application.Annotations = map[string]string{gitops.AlsoReportToAnnotation: "github=https://github.com/org/repo"}
+		snapshotComponents := []applicationapiv1alpha1.SnapshotComponent{}
+		createdSnapshot := gitops.NewSnapshot(application, &snapshotComponents)
+		Expect(createdSnapshot.Annotations).To(HaveKeyWithValue(gitops.AlsoReportToAnnotation, "github=https://github.com/org/repo"))
 	})
 
 	It("ensures the same Snapshots can be successfully compared", func() {
@@ -495,6 +530,59 @@ var _ = Describe("Gitops functions for managing Snapshots", Ordered, func() {
 		Expect(existingSnapshot.Name).To(Equal(hasSnapshot.Name))
 	})
 
+	It("ensures the most recent earlier snapshot with identical components and final test results is found", func() {
+		newSnapshot := func(name string, age time.Duration, finished bool) applicationapiv1alpha1.Snapshot {
+			snapshot := hasSnapshot.DeepCopy()
+			snapshot.Name = name
+			snapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+			snapshot.Status.Conditions = nil
+			if finished {
+				meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+					Type:   gitops.AppStudioTestSucceededCondition,
+					Status: metav1.ConditionTrue,
+					Reason: gitops.AppStudioTestSucceededConditionSatisfied,
+				})
+			}
+			return *snapshot
+		}
+		expectedSnapshot := newSnapshot("snapshot-new", 0, false)
+		olderSnapshot := newSnapshot("snapshot-older", 2*time.Hour, true)
+		recentSnapshot := newSnapshot("snapshot-recent", time.Hour, true)
+		unfinishedSnapshot := newSnapshot("snapshot-unfinished", time.Minute, false)
+		supersededSnapshot := newSnapshot("snapshot-superseded", time.Minute, true)
+		supersededSnapshot.Annotations = map[string]string{gitops.SnapshotSupersededByAnnotation: "snapshot-other"}
+		laterSnapshot := newSnapshot("snapshot-later", -time.Hour, true)
+		differentSnapshot := newSnapshot("snapshot-different", time.Minute, true)
+		differentSnapshot.Spec.Components[0].ContainerImage = "quay.io/redhat-appstudio/other-image@sha256:841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40227c1"
+		// the snapshots created for another event or target branch have identical components but another context
+		otherEventSnapshot := newSnapshot("snapshot-other-event", time.Minute, true)
+		otherEventSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = "other-event"
+		otherBranchSnapshot := newSnapshot("snapshot-other-branch", time.Minute, true)
+		if otherBranchSnapshot.Annotations == nil {
+			otherBranchSnapshot.Annotations = map[string]string{}
+		}
+		otherBranchSnapshot.Annotations[gitops.PipelineAsCodeBranchAnnotation] = "other-branch"
+
+		allSnapshots := &[]applicationapiv1alpha1.Snapshot{
+			olderSnapshot, recentSnapshot, unfinishedSnapshot, supersededSnapshot, laterSnapshot, differentSnapshot,
+			otherEventSnapshot, otherBranchSnapshot, expectedSnapshot,
+		}
+		foundSnapshot := gitops.FindSnapshotWithReusableTestResults(hasApp, allSnapshots, &expectedSnapshot)
+		Expect(foundSnapshot).NotTo(BeNil())
+		Expect(foundSnapshot.Name).To(Equal(recentSnapshot.Name))
+
+		allSnapshots = &[]applicationapiv1alpha1.Snapshot{unfinishedSnapshot, differentSnapshot, expectedSnapshot}
+		Expect(gitops.FindSnapshotWithReusableTestResults(hasApp, allSnapshots, &expectedSnapshot)).To(BeNil())
+	})
+
+	It("ensures the reuse of test results is enabled by the Application annotation", func() {
+		application := hasApp.DeepCopy()
+		application.Annotations = nil
+		Expect(gitops.IsTestResultsReuseEnabled(application)).To(BeFalse())
+		application.Annotations = map[string]string{gitops.ReuseTestResultsAnnotation: "true"}
+		Expect(gitops.IsTestResultsReuseEnabled(application)).To(BeTrue())
+	})
+
 	Context("GetIntegrationTestRunLabelValue tests", func() {
 
 		It("snapshot has no label defined", func() {
@@ -557,4 +645,351 @@ var _ = Describe("Gitops functions for managing Snapshots", Ordered, func() {
 
 	})
 
+	Context("Scheduled integration test runs", func() {
+
+		It("adds run label and scheduled run annotation to snapshot", func() {
+			testScenario := "test-scenario"
+			scheduledSnapshot := hasSnapshot.DeepCopy()
+			Expect(gitops.IsScheduledIntegrationTestRun(scheduledSnapshot, testScenario)).To(BeFalse())
+			err := gitops.AddScheduledIntegrationTestRunLabel(ctx, k8sClient, scheduledSnapshot, testScenario)
+			Expect(err).To(BeNil())
+			val, ok := gitops.GetIntegrationTestRunLabelValue(scheduledSnapshot)
+			Expect(ok).To(BeTrue())
+			Expect(val).To(Equal(testScenario))
+			Expect(gitops.IsScheduledIntegrationTestRun(scheduledSnapshot, testScenario)).To(BeTrue())
+
+			Expect(gitops.RemoveIntegrationTestRerunLabel(ctx, k8sClient, scheduledSnapshot)).To(Succeed())
+			Expect(gitops.IsScheduledIntegrationTestRun(scheduledSnapshot, testScenario)).To(BeFalse())
+		})
+
+		It("finds the latest push snapshot which wasn't superseded", func() {
+			now := metav1.Now()
+			newSnapshot := func(name, eventType string, created metav1.Time) applicationapiv1alpha1.Snapshot {
+				return applicationapiv1alpha1.Snapshot{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              name,
+						CreationTimestamp: created,
+						Labels: map[string]string{
+							gitops.PipelineAsCodeEventTypeLabel: eventType,
+						},
+					},
+				}
+			}
+			olderPush := newSnapshot("older-push", gitops.PipelineAsCodePushType, metav1.NewTime(now.Add(-2*time.Hour)))
+			latestPush := newSnapshot("latest-push", gitops.PipelineAsCodePushType, metav1.NewTime(now.Add(-time.Hour)))
+			pullRequest := newSnapshot("pull-request", gitops.PipelineAsCodePullRequestType, now)
+			supersededPush := newSnapshot("superseded-push", gitops.PipelineAsCodePushType, now)
+			supersededPush.Annotations = map[string]string{gitops.SnapshotSupersededByAnnotation: "newer-push"}
+
+			Expect(gitops.GetLatestPushSnapshot([]applicationapiv1alpha1.Snapshot{})).To(BeNil())
+			Expect(gitops.GetLatestPushSnapshot([]applicationapiv1alpha1.Snapshot{pullRequest})).To(BeNil())
+			latestSnapshot := gitops.GetLatestPushSnapshot([]applicationapiv1alpha1.Snapshot{olderPush, latestPush, pullRequest, supersededPush})
+			Expect(latestSnapshot).NotTo(BeNil())
+			Expect(latestSnapshot.Name).To(Equal(latestPush.Name))
+		})
+	})
+
+	Context("Results exported by the tests of the Snapshot", func() {
+
+		It("records the exported results of each test", func() {
+			exportingSnapshot := hasSnapshot.DeepCopy()
+			exportedResults, err := gitops.GetExportedResults(exportingSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exportedResults).To(BeEmpty())
+
+			Expect(gitops.SetExportedResults(ctx, k8sClient, exportingSnapshot, "provision-cluster",
+				map[string]string{"CLUSTER_URL": "https://api.cluster.example.com:6443"})).To(Succeed())
+			Expect(gitops.SetExportedResults(ctx, k8sClient, exportingSnapshot, "provision-registry",
+				map[string]string{"REGISTRY_URL": "registry.example.com"})).To(Succeed())
+			// the results of a re-run replace the results previously exported by the test
+			Expect(gitops.SetExportedResults(ctx, k8sClient, exportingSnapshot, "provision-cluster",
+				map[string]string{"CLUSTER_URL": "https://api.other-cluster.example.com:6443"})).To(Succeed())
+
+			exportedResults, err = gitops.GetExportedResults(exportingSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exportedResults).To(Equal(map[string]map[string]string{
+				"provision-cluster":  {"CLUSTER_URL": "https://api.other-cluster.example.com:6443"},
+				"provision-registry": {"REGISTRY_URL": "registry.example.com"},
+			}))
+		})
+
+		It("fails to parse malformed exported results", func() {
+			malformedSnapshot := hasSnapshot.DeepCopy()
+			malformedSnapshot.Annotations = map[string]string{gitops.SnapshotExportedResultsAnnotation: "not-json"}
+			_, err := gitops.GetExportedResults(malformedSnapshot)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Errors of the global candidate list updates", func() {
+
+		It("records and clears the components which failed to be updated", func() {
+			candidateSnapshot := hasSnapshot.DeepCopy()
+			Expect(gitops.SetSnapshotCandidateUpdateErrors(ctx, k8sClient, candidateSnapshot, map[string]string{})).To(Succeed())
+			Expect(candidateSnapshot.GetAnnotations()).NotTo(HaveKey(gitops.SnapshotCandidateUpdateErrorsAnnotation))
+
+			Expect(gitops.SetSnapshotCandidateUpdateErrors(ctx, k8sClient, candidateSnapshot,
+				map[string]string{"component-b": "failed to update .Spec.ContainerImage"})).To(Succeed())
+			candidateUpdateErrors, err := gitops.GetSnapshotCandidateUpdateErrors(candidateSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidateUpdateErrors).To(Equal(map[string]string{"component-b": "failed to update .Spec.ContainerImage"}))
+
+			Expect(gitops.SetSnapshotCandidateUpdateErrors(ctx, k8sClient, candidateSnapshot, nil)).To(Succeed())
+			Expect(candidateSnapshot.GetAnnotations()).NotTo(HaveKey(gitops.SnapshotCandidateUpdateErrorsAnnotation))
+			candidateUpdateErrors, err = gitops.GetSnapshotCandidateUpdateErrors(candidateSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidateUpdateErrors).To(BeEmpty())
+		})
+	})
+
+	Context("debug retention of failed integration PipelineRuns", func() {
+		newAnnotatedScenario := func(annotations map[string]string) *v1beta2.IntegrationTestScenario {
+			return &v1beta2.IntegrationTestScenario{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "debugged-scenario",
+					Namespace:   "default",
+					Annotations: annotations,
+				},
+			}
+		}
+
+		DescribeTable("reads the bounded debug retention",
+			func(annotations map[string]string, expected time.Duration) {
+				duration, err := gitops.GetDebugKeepDuration(newAnnotatedScenario(annotations))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(duration).To(Equal(expected))
+			},
+			Entry("annotation isn't set", nil, time.Duration(0)),
+			Entry("annotation is empty", map[string]string{gitops.DebugKeepAnnotation: ""}, time.Duration(0)),
+			Entry("retention within the bound", map[string]string{gitops.DebugKeepAnnotation: "4h"}, 4*time.Hour),
+			Entry("retention equal to the bound", map[string]string{gitops.DebugKeepAnnotation: "72h"}, gitops.MaxDebugKeepDuration),
+			Entry("retention beyond the bound", map[string]string{gitops.DebugKeepAnnotation: "720h"}, gitops.MaxDebugKeepDuration),
+		)
+
+		DescribeTable("rejects invalid debug retentions",
+			func(value string) {
+				duration, err := gitops.GetDebugKeepDuration(newAnnotatedScenario(map[string]string{gitops.DebugKeepAnnotation: value}))
+				Expect(err).To(HaveOccurred())
+				Expect(duration).To(BeZero())
+			},
+			Entry("not a duration", "forever"),
+			Entry("missing unit", "4"),
+			Entry("negative duration", "-4h"),
+			Entry("zero duration", "0s"),
+		)
+	})
+
+	Context("IntegrationTestScenario contexts are evaluated for Snapshots", func() {
+		newSnapshot := func(snapshotType, componentName, eventType string) *applicationapiv1alpha1.Snapshot {
+			snapshot := &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-sample",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel: snapshotType,
+					},
+				},
+			}
+			if componentName != "" {
+				snapshot.Labels[gitops.SnapshotComponentLabel] = componentName
+			}
+			if eventType != "" {
+				snapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = eventType
+			}
+			return snapshot
+		}
+
+		DescribeTable("checks if the scenario applies to the Snapshot",
+			func(contexts []string, snapshot *applicationapiv1alpha1.Snapshot, expected bool) {
+				scenario := &v1beta2.IntegrationTestScenario{}
+				for _, contextName := range contexts {
+					scenario.Spec.Contexts = append(scenario.Spec.Contexts, v1beta2.TestContext{Name: contextName})
+				}
+				Expect(gitops.IsScenarioApplicableToSnapshotsContext(scenario, snapshot)).To(Equal(expected))
+			},
+			Entry("scenario without contexts", nil, newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), true),
+			Entry("application context", []string{"application"}, newSnapshot(gitops.SnapshotCompositeType, "", ""), true),
+			Entry("component context with component snapshot", []string{"component"}, newSnapshot(gitops.SnapshotComponentType, "frontend", ""), true),
+			Entry("component context with composite snapshot", []string{"component"}, newSnapshot(gitops.SnapshotCompositeType, "", ""), false),
+			Entry("matching component_<name> context", []string{"component_frontend"}, newSnapshot(gitops.SnapshotComponentType, "frontend", ""), true),
+			Entry("other component_<name> context", []string{"component_backend"}, newSnapshot(gitops.SnapshotComponentType, "frontend", ""), false),
+			Entry("component_<name> context with composite snapshot", []string{"component_frontend"}, newSnapshot(gitops.SnapshotCompositeType, "frontend", ""), false),
+			Entry("pull_request context with pull request snapshot", []string{"pull_request"}, newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), true),
+			Entry("pull_request context with push snapshot", []string{"pull_request"}, newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), false),
+			Entry("push context with manual snapshot", []string{"push"}, newSnapshot(gitops.SnapshotCompositeType, "", ""), true),
+			Entry("unknown context", []string{"test-ctx"}, newSnapshot(gitops.SnapshotComponentType, "frontend", ""), true),
+			Entry("unknown context with non-matching context", []string{"group", "pull_request"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), true),
+			Entry("mixed contexts with matching component", []string{"pull_request", "component_backend", "component_frontend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), true),
+			Entry("mixed contexts with matching event", []string{"pull_request", "component_backend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), true),
+			Entry("mixed contexts without any match", []string{"pull_request", "component_backend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), false),
+			Entry("negated push context with push snapshot", []string{"!push"}, newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), false),
+			Entry("negated push context with pull request snapshot", []string{"!push"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), true),
+			Entry("negated push context with manual snapshot", []string{"!push"}, newSnapshot(gitops.SnapshotCompositeType, "", ""), false),
+			Entry("negated component_<name> context with other component", []string{"!component_backend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), true),
+			Entry("negated component_<name> context with matching component", []string{"!component_frontend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), false),
+			Entry("negated unknown context", []string{"!test-ctx"}, newSnapshot(gitops.SnapshotComponentType, "frontend", ""), true),
+			Entry("matching context and non-matching negated context", []string{"pull_request", "!component_backend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), true),
+			Entry("matching context and matching negated context", []string{"pull_request", "!component_frontend"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), false),
+			Entry("non-matching context and non-matching negated context", []string{"component_backend", "!push"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "pull_request"), false),
+			Entry("application context and negated component context", []string{"application", "!component"},
+				newSnapshot(gitops.SnapshotCompositeType, "", "push"), true),
+			Entry("application context and matching negated component context", []string{"application", "!component"},
+				newSnapshot(gitops.SnapshotComponentType, "frontend", "push"), false),
+		)
+
+		It("returns the unknown contexts of the scenario", func() {
+			scenario := &v1beta2.IntegrationTestScenario{
+				Spec: v1beta2.IntegrationTestScenarioSpec{
+					Contexts: []v1beta2.TestContext{
+						{Name: "application"}, {Name: "group"}, {Name: "!push"}, {Name: "!override"},
+						{Name: "component_frontend"}, {Name: "component_"},
+					},
+				},
+			}
+			Expect(gitops.GetUnknownContexts(scenario)).To(Equal([]string{"group", "!override", "component_"}))
+		})
+	})
+
+	Context("Completion of the required tests of Snapshots", func() {
+		var (
+			snapshot          *applicationapiv1alpha1.Snapshot
+			requiredScenarios *[]v1beta2.IntegrationTestScenario
+			testStatuses      *intgteststat.SnapshotIntegrationTestStatuses
+		)
+
+		BeforeEach(func() {
+			snapshot = &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-completion",
+					Namespace: namespace,
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotComponentType,
+						gitops.SnapshotComponentLabel:       componentName,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePushType,
+					},
+				},
+			}
+			pullRequestOnlyScenario := v1beta2.IntegrationTestScenario{
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-request-only"},
+				Spec: v1beta2.IntegrationTestScenarioSpec{
+					Contexts: []v1beta2.TestContext{{Name: "pull_request"}},
+				},
+			}
+			requiredScenarios = &[]v1beta2.IntegrationTestScenario{
+				{ObjectMeta: metav1.ObjectMeta{Name: "required-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "required-2"}},
+				pullRequestOnlyScenario,
+			}
+
+			var err error
+			testStatuses, err = intgteststat.NewSnapshotIntegrationTestStatuses("")
+			Expect(err).ToNot(HaveOccurred())
+			testStatuses.UpdateTestStatusIfChanged("required-1", intgteststat.IntegrationTestStatusTestPassed, "passed")
+			testStatuses.UpdateTestStatusIfChanged("required-2", intgteststat.IntegrationTestStatusInProgress, "running")
+			testStatuses.UpdateTestStatusIfChanged("optional", intgteststat.IntegrationTestStatusInProgress, "running")
+		})
+
+		It("counts the partial completion of the required tests", func() {
+			completion := gitops.GetRequiredTestsCompletion(snapshot, requiredScenarios, testStatuses)
+			Expect(completion.Total).To(Equal(2))
+			Expect(completion.Finished).To(Equal(1))
+			Expect(completion.AllFinished()).To(BeFalse())
+		})
+
+		It("completes when only the optional tests remain unfinished", func() {
+			testStatuses.UpdateTestStatusIfChanged("required-2", intgteststat.IntegrationTestStatusTestFail, "failed")
+
+			completion := gitops.GetRequiredTestsCompletion(snapshot, requiredScenarios, testStatuses)
+			Expect(completion.AllFinished()).To(BeTrue())
+			Expect(completion.AllPassed()).To(BeFalse())
+			Expect(completion.Passed).To(Equal(1))
+		})
+
+		It("completes and passes when all the required tests passed", func() {
+			testStatuses.UpdateTestStatusIfChanged("required-2", intgteststat.IntegrationTestStatusSkipped, "skipped")
+			testStatuses.UpdateTestStatusIfChanged("optional", intgteststat.IntegrationTestStatusTestFail, "failed")
+
+			completion := gitops.GetRequiredTestsCompletion(snapshot, requiredScenarios, testStatuses)
+			Expect(completion.AllFinished()).To(BeTrue())
+			Expect(completion.AllPassed()).To(BeTrue())
+		})
+
+		It("counts the required tests applying to the context of pull request Snapshots", func() {
+			snapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodePullRequestType
+
+			completion := gitops.GetRequiredTestsCompletion(snapshot, requiredScenarios, testStatuses)
+			Expect(completion.Total).To(Equal(3))
+			Expect(completion.Finished).To(Equal(1))
+		})
+
+		It("decides on the promotion of Snapshots based on the tests completed condition", func() {
+			meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:   gitops.AppStudioTestsCompletedCondition,
+				Status: metav1.ConditionFalse,
+				Reason: gitops.AppStudioTestsCompletedConditionInProgress,
+			})
+			completed, _ := gitops.HaveSnapshotTestsCompleted(snapshot)
+			Expect(completed).To(BeFalse())
+			canBePromoted, reasons := gitops.CanSnapshotBePromoted(snapshot)
+			Expect(canBePromoted).To(BeFalse())
+			Expect(reasons).To(ContainElement("the Snapshot has not yet finished testing"))
+
+			meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:   gitops.AppStudioTestsCompletedCondition,
+				Status: metav1.ConditionTrue,
+				Reason: gitops.AppStudioTestSucceededConditionFailed,
+			})
+			completed, passed := gitops.HaveSnapshotTestsCompleted(snapshot)
+			Expect(completed).To(BeTrue())
+			Expect(passed).To(BeFalse())
+			canBePromoted, reasons = gitops.CanSnapshotBePromoted(snapshot)
+			Expect(canBePromoted).To(BeFalse())
+			Expect(reasons).To(ContainElement("the Snapshot hasn't passed all required integration tests"))
+
+			meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:   gitops.AppStudioTestsCompletedCondition,
+				Status: metav1.ConditionTrue,
+				Reason: gitops.AppStudioTestSucceededConditionSatisfied,
+			})
+			canBePromoted, reasons = gitops.CanSnapshotBePromoted(snapshot)
+			Expect(canBePromoted).To(BeTrue())
+			Expect(reasons).To(BeEmpty())
+		})
+
+		It("falls back to the test succeeded condition when the tests completed condition isn't set", func() {
+			completed, _ := gitops.HaveSnapshotTestsCompleted(snapshot)
+			Expect(completed).To(BeFalse())
+
+			meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+				Type:   gitops.AppStudioTestSucceededCondition,
+				Status: metav1.ConditionTrue,
+				Reason: gitops.AppStudioTestSucceededConditionSatisfied,
+			})
+			completed, passed := gitops.HaveSnapshotTestsCompleted(snapshot)
+			Expect(completed).To(BeTrue())
+			Expect(passed).To(BeTrue())
+		})
+	})
+
+	DescribeTable("normalizes the URLs of the Pipelines as Code Repositories",
+		func(url, expected string) {
+			Expect(gitops.NormalizePacRepositoryURL(url)).To(Equal(expected))
+		},
+		Entry("plain URL", "https://github.com/org/repo", "https://github.com/org/repo"),
+		Entry("trailing slash", "https://github.com/org/repo/", "https://github.com/org/repo"),
+		Entry("several trailing slashes", "https://github.com/org/repo//", "https://github.com/org/repo"),
+		Entry(".git suffix", "https://github.com/org/repo.git", "https://github.com/org/repo"),
+		Entry(".git suffix and trailing slash", "https://github.com/org/repo.git/", "https://github.com/org/repo"),
+		Entry("empty URL", "", ""),
+	)
 })
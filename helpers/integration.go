@@ -22,9 +22,11 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -61,6 +63,22 @@ const (
 
 	// AppStudioTestOutputError is the result that's set when the AppStudio test produces an error.
 	AppStudioTestOutputError = "ERROR"
+
+	// InfrastructureFailurePrefix prefixes the details of the tests which failed because of the infrastructure
+	// running them rather than because of the tests themselves
+	InfrastructureFailurePrefix = "infrastructure failure:"
+
+	// InfrastructureFailureOOMKilled is the classification of the TaskRuns whose step was killed for running out of memory.
+	InfrastructureFailureOOMKilled = "OOMKilled"
+
+	// InfrastructureFailureImagePullBackOff is the classification of the TaskRuns whose step image couldn't be pulled.
+	InfrastructureFailureImagePullBackOff = "ImagePullBackOff"
+
+	// InfrastructureFailureEvicted is the classification of the TaskRuns whose pod was evicted from its node.
+	InfrastructureFailureEvicted = "Evicted"
+
+	// oomKilledExitCode is the exit code of the containers killed by the OOM killer
+	oomKilledExitCode = 137
 )
 
 // AppStudioTestResult matches AppStudio TaskRun result contract
@@ -154,6 +172,106 @@ func (t *TaskRun) GetDuration() time.Duration {
 	return end.Sub(start)
 }
 
+// GetPodName returns the name of the Pod which executed the TaskRun.
+// If the Pod is unknown, an empty string is returned.
+func (t *TaskRun) GetPodName() string {
+	return t.trStatus.PodName
+}
+
+// HasFailed returns true if the TaskRun finished unsuccessfully or reported a failing test result.
+// Only the final attempt of a retried TaskRun is considered, failures of the previous attempts are ignored.
+func (t *TaskRun) HasFailed() bool {
+	if t.trStatus.GetCondition(apis.ConditionSucceeded).IsFalse() {
+		return true
+	}
+	result, err := t.GetTestResult()
+	if err != nil || result == nil || result.TestOutput == nil {
+		return false
+	}
+	return result.TestOutput.Result == AppStudioTestOutputFailure || result.TestOutput.Result == AppStudioTestOutputError
+}
+
+// GetFailureReason returns the reason of the Succeeded condition of the failed TaskRun,
+// an empty string is returned when the TaskRun didn't fail.
+func (t *TaskRun) GetFailureReason() string {
+	condition := t.trStatus.GetCondition(apis.ConditionSucceeded)
+	if !condition.IsFalse() {
+		return ""
+	}
+	return condition.Reason
+}
+
+// HasTimedOut returns true if the TaskRun failed because it exceeded its timeout.
+func (t *TaskRun) HasTimedOut() bool {
+	condition := t.trStatus.GetCondition(apis.ConditionSucceeded)
+	return condition.IsFalse() && condition.Reason == tektonv1.TaskRunReasonTimedOut.String()
+}
+
+// GetFailedSteps returns the steps of the TaskRun which terminated with a non-zero exit code.
+func (t *TaskRun) GetFailedSteps() []tektonv1.StepState {
+	failedSteps := []tektonv1.StepState{}
+	for _, step := range t.trStatus.Steps {
+		if step.Terminated != nil && step.Terminated.ExitCode != 0 {
+			failedSteps = append(failedSteps, step)
+		}
+	}
+	return failedSteps
+}
+
+// GetInfrastructureFailure returns the classification of the infrastructure failure which made the TaskRun fail,
+// together with the name of the step it affected when it's known. Empty strings are returned when the TaskRun
+// didn't fail or failed because of the task itself.
+func (t *TaskRun) GetInfrastructureFailure() (string, string) {
+	condition := t.trStatus.GetCondition(apis.ConditionSucceeded)
+	if !condition.IsFalse() {
+		return "", ""
+	}
+	for _, step := range t.trStatus.Steps {
+		if step.Terminated != nil && (step.Terminated.Reason == InfrastructureFailureOOMKilled || step.Terminated.ExitCode == oomKilledExitCode) {
+			return InfrastructureFailureOOMKilled, step.Name
+		}
+		if step.Waiting != nil && (step.Waiting.Reason == InfrastructureFailureImagePullBackOff || step.Waiting.Reason == "ErrImagePull") {
+			return InfrastructureFailureImagePullBackOff, step.Name
+		}
+	}
+	if condition.Reason == tektonv1.TaskRunReasonImagePullFailed.String() {
+		return InfrastructureFailureImagePullBackOff, ""
+	}
+	// the message of the evicted pod is propagated to the TaskRun condition
+	if strings.Contains(condition.Message, InfrastructureFailureEvicted) || strings.Contains(condition.Message, "The node was low on resource") {
+		return InfrastructureFailureEvicted, ""
+	}
+	return "", ""
+}
+
+// GetInfrastructureFailure returns the description of the infrastructure failure of the earliest TaskRun which failed
+// because of its infrastructure, e.g. "OOMKilled (step run-tests of task e2e-tests)". An empty string is returned
+// when none of the TaskRuns failed because of its infrastructure. The taskRuns are expected to be sorted by their start time.
+func GetInfrastructureFailure(taskRuns []*TaskRun) string {
+	for _, taskRun := range taskRuns {
+		classification, stepName := taskRun.GetInfrastructureFailure()
+		if classification == "" {
+			continue
+		}
+		if stepName != "" {
+			return fmt.Sprintf("%s (step %s of task %s)", classification, stepName, taskRun.GetPipelineTaskName())
+		}
+		return fmt.Sprintf("%s (task %s)", classification, taskRun.GetPipelineTaskName())
+	}
+	return ""
+}
+
+// GetInfrastructureFailureFromDetails returns the description of the infrastructure failure recorded in the details
+// of a failed test and true, or false when the test didn't fail because of its infrastructure
+func GetInfrastructureFailureFromDetails(details string) (string, bool) {
+	failure, ok := strings.CutPrefix(details, InfrastructureFailurePrefix)
+	if !ok {
+		return "", false
+	}
+	failure, _, _ = strings.Cut(failure, ";")
+	return strings.TrimSpace(failure), true
+}
+
 // GetTestResult returns a IntegrationTestTaskResult if the TaskRun produced the result. It will return nil otherwise.
 func (t *TaskRun) GetTestResult() (*IntegrationTestTaskResult, error) {
 	// Check for an already parsed result.
@@ -168,6 +286,9 @@ func (t *TaskRun) GetTestResult() (*IntegrationTestTaskResult, error) {
 
 	for _, taskRunResult := range t.trStatus.TaskRunStatusFields.Results {
 		if taskRunResult.Name == LegacyTestOutputName || taskRunResult.Name == TestOutputName {
+			if t.isResultFromPreviousAttempt(taskRunResult) {
+				continue
+			}
 			var testOutput AppStudioTestResult
 			var testResult IntegrationTestTaskResult = IntegrationTestTaskResult{}
 			var v interface{}
@@ -188,6 +309,53 @@ func (t *TaskRun) GetTestResult() (*IntegrationTestTaskResult, error) {
 	return nil, nil
 }
 
+// GetRetries returns the number of failed attempts of the TaskRun which were retried before its final attempt.
+func (t *TaskRun) GetRetries() int {
+	return len(t.trStatus.RetriesStatus)
+}
+
+// isResultFromPreviousAttempt returns true if the result wasn't reported by the final attempt of a retried TaskRun
+// but carried over from the previous attempt. Tekton keeps the results of the previous attempt in the status
+// when the TaskRun is retried, they are only overridden when the final attempt reports the same result.
+// The TEST_OUTPUT result contains the timestamp of the test, so the final attempt doesn't report the same value.
+func (t *TaskRun) isResultFromPreviousAttempt(result tektonv1.TaskRunResult) bool {
+	if len(t.trStatus.RetriesStatus) == 0 {
+		return false
+	}
+	previousAttempt := t.trStatus.RetriesStatus[len(t.trStatus.RetriesStatus)-1]
+	for _, previousResult := range previousAttempt.Results {
+		if previousResult.Name == result.Name {
+			return reflect.DeepEqual(previousResult.Value, result.Value)
+		}
+	}
+	return false
+}
+
+// GetTaskResult returns the outcome of the TaskRun as reported in the snapshot test status.
+// TaskRuns without a valid TEST_OUTPUT result are described by the reason of their Succeeded condition.
+func (t *TaskRun) GetTaskResult() intgteststat.TaskResult {
+	taskResult := intgteststat.TaskResult{Name: t.GetPipelineTaskName(), Retries: t.GetRetries()}
+	testResult, err := t.GetTestResult()
+	if err == nil && testResult != nil && testResult.TestOutput != nil {
+		taskResult.Result = testResult.TestOutput.Result
+		taskResult.Successes = testResult.TestOutput.Successes
+		taskResult.Failures = testResult.TestOutput.Failures
+		taskResult.Warnings = testResult.TestOutput.Warnings
+		taskResult.Note = testResult.TestOutput.Note
+		return taskResult
+	}
+
+	if condition := t.trStatus.GetCondition(apis.ConditionSucceeded); condition != nil {
+		taskResult.Reason = condition.Reason
+	}
+	if err != nil {
+		taskResult.ValidationError = err.Error()
+	} else if testResult != nil && testResult.ValidationError != nil {
+		taskResult.ValidationError = testResult.ValidationError.Error()
+	}
+	return taskResult
+}
+
 // SortTaskRunsByStartTime can sort TaskRuns by their start time. It implements sort.Interface.
 type SortTaskRunsByStartTime []*TaskRun
 
@@ -229,19 +397,24 @@ func (ipro *IntegrationPipelineRunOutcome) HasPipelineRunValidTestOutputs() bool
 	return true
 }
 
-// GetValidationErrorsList returns validation error messages for each invalid task result in a list.
+// GetValidationErrorsList returns validation error messages for each invalid task result in a list sorted by task name.
 func (ipro *IntegrationPipelineRunOutcome) GetValidationErrorsList() []string {
 	var errors []string
-	for _, result := range ipro.results {
-		if result.ValidationError != nil {
+	for _, taskName := range getSortedTaskNames(ipro.results) {
+		if result := ipro.results[taskName]; result.ValidationError != nil {
 			errors = append(errors, fmt.Sprintf("Invalid result: %s", result.ValidationError))
 		}
 	}
 	return errors
 }
 
+// GetAggregatedTestResult returns the aggregation of the TEST_OUTPUT results of all the tasks of the pipeline.
+func (ipro *IntegrationPipelineRunOutcome) GetAggregatedTestResult() AggregatedTestResult {
+	return AggregateTestResults(ipro.results)
+}
+
 // HasPipelineRunPassedTesting returns general outcome
-// If any of the tasks with the TEST_OUTPUT result don't have the `result` field set to SUCCESS or SKIPPED, it returns false.
+// If any of the tasks with the TEST_OUTPUT result has the `result` field set to FAILURE or ERROR, it returns false.
 func (ipro *IntegrationPipelineRunOutcome) HasPipelineRunPassedTesting() bool {
 	if !ipro.HasPipelineRunSucceeded() {
 		return false
@@ -249,19 +422,74 @@ func (ipro *IntegrationPipelineRunOutcome) HasPipelineRunPassedTesting() bool {
 	if !ipro.HasPipelineRunValidTestOutputs() {
 		return false
 	}
-	for _, result := range ipro.results {
-		if result.TestOutput.Result != AppStudioTestOutputSuccess &&
-			result.TestOutput.Result != AppStudioTestOutputSkipped &&
-			result.TestOutput.Result != AppStudioTestOutputWarning {
-			return false
+	return !ipro.GetAggregatedTestResult().HasFailed()
+}
+
+// testOutputResultSeverities orders the results of TEST_OUTPUT from the least to the most severe one.
+var testOutputResultSeverities = map[string]int{
+	AppStudioTestOutputSkipped: 1,
+	AppStudioTestOutputSuccess: 2,
+	AppStudioTestOutputWarning: 3,
+	AppStudioTestOutputFailure: 4,
+	AppStudioTestOutputError:   5,
+}
+
+// AggregatedTestResult is the overall outcome of the TEST_OUTPUT results of all the tasks of a pipeline.
+type AggregatedTestResult struct {
+	// Result is the most severe result reported by the tasks, empty when no task reported a valid TEST_OUTPUT.
+	Result string
+	// Successes, Failures and Warnings are summed across the tasks.
+	Successes int
+	Failures  int
+	Warnings  int
+	// FailedTasks are the names of the tasks which reported the FAILURE or ERROR result sorted alphabetically.
+	FailedTasks []string
+}
+
+// HasFailed returns true if any of the tasks reported the FAILURE or ERROR result.
+func (r AggregatedTestResult) HasFailed() bool {
+	return r.Result == AppStudioTestOutputFailure || r.Result == AppStudioTestOutputError
+}
+
+// AggregateTestResults aggregates the TEST_OUTPUT results of the tasks mapped by their names. The overall result
+// is the most severe one (ERROR > FAILURE > WARNING > SUCCESS > SKIPPED) and the test counts are summed.
+// Invalid TEST_OUTPUT results are ignored.
+func AggregateTestResults(results map[string]*IntegrationTestTaskResult) AggregatedTestResult {
+	aggregated := AggregatedTestResult{}
+	for _, taskName := range getSortedTaskNames(results) {
+		result := results[taskName]
+		if result == nil || result.TestOutput == nil {
+			continue
+		}
+		testOutput := result.TestOutput
+		if testOutputResultSeverities[testOutput.Result] > testOutputResultSeverities[aggregated.Result] {
+			aggregated.Result = testOutput.Result
+		}
+		if testOutput.Result == AppStudioTestOutputFailure || testOutput.Result == AppStudioTestOutputError {
+			aggregated.FailedTasks = append(aggregated.FailedTasks, taskName)
 		}
+		aggregated.Successes += testOutput.Successes
+		aggregated.Failures += testOutput.Failures
+		aggregated.Warnings += testOutput.Warnings
 	}
-	return true
+	return aggregated
+}
+
+// getSortedTaskNames returns the names of the tasks of the results sorted alphabetically,
+// so the results are always processed in the same order
+func getSortedTaskNames(results map[string]*IntegrationTestTaskResult) []string {
+	taskNames := make([]string, 0, len(results))
+	for taskName := range results {
+		taskNames = append(taskNames, taskName)
+	}
+	sort.Strings(taskNames)
+	return taskNames
 }
 
 // LogResults writes tasks names with results into given logger, each task on separate line
 func (ipro *IntegrationPipelineRunOutcome) LogResults(logger logr.Logger) {
-	for k, v := range ipro.results {
+	for _, k := range getSortedTaskNames(ipro.results) {
+		v := ipro.results[k]
 		if v.TestOutput != nil {
 			logger.Info(fmt.Sprintf("Found task results for pipeline run %s", ipro.pipelineRun.Name),
 				"pipelineRun.Name", ipro.pipelineRun.Name,
@@ -333,6 +561,45 @@ func GetIntegrationTestTaskResultsFromPipelineRunWithChildReferences(ctx context
 	return results, nil
 }
 
+// GetTaskResultsFromPipelineRun returns the outcome of every child TaskRun of the PipelineRun sorted by start time
+func GetTaskResultsFromPipelineRun(ctx context.Context, adapterClient client.Client, pipelineRun *tektonv1.PipelineRun) ([]intgteststat.TaskResult, error) {
+	taskRuns, err := GetAllChildTaskRunsForPipelineRun(ctx, adapterClient, pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskResults []intgteststat.TaskResult
+	for _, tr := range taskRuns {
+		taskResults = append(taskResults, tr.GetTaskResult())
+	}
+	return taskResults, nil
+}
+
+// GetPipelineRunTimeout returns the duration after which the PipelineRun, or one of its child TaskRuns, timed out.
+// The second return value is false when neither the PipelineRun nor its child TaskRuns timed out.
+func GetPipelineRunTimeout(ctx context.Context, adapterClient client.Client, pipelineRun *tektonv1.PipelineRun) (time.Duration, bool, error) {
+	if pipelineRun.IsTimeoutConditionSet() {
+		if pipelineRun.Status.StartTime == nil || pipelineRun.Status.CompletionTime == nil {
+			return pipelineRun.PipelineTimeout(ctx), true, nil
+		}
+		return pipelineRun.Status.CompletionTime.Sub(pipelineRun.Status.StartTime.Time), true, nil
+	}
+	if !HasPipelineRunFinished(pipelineRun) || HasPipelineRunSucceeded(pipelineRun) {
+		return 0, false, nil
+	}
+
+	taskRuns, err := GetAllChildTaskRunsForPipelineRun(ctx, adapterClient, pipelineRun)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, tr := range taskRuns {
+		if tr.HasTimedOut() {
+			return tr.GetDuration(), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
 // GetAllChildTaskRunsForPipelineRun finds all Child TaskRuns for a given PipelineRun and
 // returns integration TaskRun wrappers for them sorted by start time.
 func GetAllChildTaskRunsForPipelineRun(ctx context.Context, adapterClient client.Client, pipelineRun *tektonv1.PipelineRun) ([]*TaskRun, error) {
@@ -460,6 +727,24 @@ func RemoveFinalizerFromPipelineRun(ctx context.Context, adapterClient client.Cl
 	return nil
 }
 
+// CancelPipelineRun cancels the PipelineRun, the finally tasks of the PipelineRun are still run.
+// If the PipelineRun was not cancelled successfully, a non-nil error is returned.
+func CancelPipelineRun(ctx context.Context, adapterClient client.Client, logger IntegrationLogger, pipelineRun *tektonv1.PipelineRun) error {
+	if pipelineRun.IsCancelled() || pipelineRun.Spec.Status == tektonv1.PipelineRunSpecStatusCancelledRunFinally {
+		return nil
+	}
+
+	patch := client.MergeFrom(pipelineRun.DeepCopy())
+	pipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusCancelledRunFinally
+	err := adapterClient.Patch(ctx, pipelineRun, patch)
+	if err != nil {
+		return fmt.Errorf("failed to cancel the pipelineRun %s: %w", pipelineRun.Name, err)
+	}
+
+	logger.LogAuditEvent("Cancelled the PipelineRun", pipelineRun, LogActionUpdate)
+	return nil
+}
+
 // AddFinalizerToPipelineRun adds the finalizer to the PipelineRun.
 // If finalizer was not added successfully, a non-nil error is returned.
 func AddFinalizerToPipelineRun(ctx context.Context, adapterClient client.Client, logger IntegrationLogger, pipelineRun *tektonv1.PipelineRun, finalizer string) error {
@@ -28,8 +28,10 @@ import (
 
 	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/tonglil/buflogr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -676,6 +678,12 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 		Expect(integrationTaskRun.GetTestResult()).To(BeNil())
 	})
 
+	It("can detect a failed Integration TaskRun", func() {
+		Expect(helpers.NewTaskRunFromTektonTaskRun("task-success", &successfulTaskRun.Status).HasFailed()).To(BeFalse())
+		Expect(helpers.NewTaskRunFromTektonTaskRun("task-fail", &failedTaskRun.Status).HasFailed()).To(BeTrue())
+		Expect(helpers.NewTaskRunFromTektonTaskRun("task-instant", &emptyTaskRun.Status).HasFailed()).To(BeFalse())
+	})
+
 	It("ensures multiple task pipelinerun outcome when AppStudio Tests succeeded", func() {
 		integrationPipelineRun.Status = tektonv1.PipelineRunStatus{
 			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
@@ -1022,6 +1030,350 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 		Expect(result3.TestOutput.Successes).To(Equal(0))
 	})
 
+	It("can get the results of all the tasks of a PipelineRun", func() {
+		integrationPipelineRun.Status = tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				ChildReferences: []tektonv1.ChildStatusReference{
+					{
+						Name:             successfulTaskRun.Name,
+						PipelineTaskName: "pipeline1-task1",
+					},
+					{
+						Name:             failedTaskRun.Name,
+						PipelineTaskName: "pipeline1-task2",
+					},
+					{
+						Name:             malformedTaskRun.Name,
+						PipelineTaskName: "pipeline1-task3",
+					},
+					{
+						Name:             emptyTaskRun.Name,
+						PipelineTaskName: "pipeline1-task4",
+					},
+				},
+			},
+		}
+
+		taskResults, err := helpers.GetTaskResultsFromPipelineRun(ctx, k8sClient, integrationPipelineRun)
+		Expect(err).To(BeNil())
+		Expect(taskResults).To(HaveLen(4))
+		Expect(taskResults).To(ContainElement(intgteststat.TaskResult{Name: "pipeline1-task1", Result: "SUCCESS", Successes: 10}))
+		Expect(taskResults).To(ContainElement(intgteststat.TaskResult{Name: "pipeline1-task2", Result: "FAILURE", Failures: 1}))
+		Expect(taskResults).To(ContainElement(intgteststat.TaskResult{Name: "pipeline1-task4"}))
+		Expect(taskResults).To(ContainElement(SatisfyAll(
+			HaveField("Name", "pipeline1-task3"),
+			HaveField("Result", BeEmpty()),
+			HaveField("ValidationError", ContainSubstring("error while mapping json data from task pipeline1-task3")),
+		)))
+	})
+
+	It("can detect a timed out Integration TaskRun", func() {
+		timedOutTaskRunStatus := &tektonv1.TaskRunStatus{
+			Status: v1.Status{
+				Conditions: v1.Conditions{
+					apis.Condition{
+						Reason: tektonv1.TaskRunReasonTimedOut.String(),
+						Status: "False",
+						Type:   apis.ConditionSucceeded,
+					},
+				},
+			},
+		}
+		Expect(helpers.NewTaskRunFromTektonTaskRun("task-timeout", timedOutTaskRunStatus).HasTimedOut()).To(BeTrue())
+		Expect(helpers.NewTaskRunFromTektonTaskRun("task-fail", &failedTaskRun.Status).HasTimedOut()).To(BeFalse())
+	})
+
+	It("can get the timeout of a timed out PipelineRun", func() {
+		integrationPipelineRun.Status = tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				StartTime:      &metav1.Time{Time: now},
+				CompletionTime: &metav1.Time{Time: now.Add(90 * time.Minute)},
+			},
+			Status: v1.Status{
+				Conditions: v1.Conditions{
+					apis.Condition{
+						Reason: tektonv1.PipelineRunReasonTimedOut.String(),
+						Status: "False",
+						Type:   apis.ConditionSucceeded,
+					},
+				},
+			},
+		}
+		timeout, timedOut, err := helpers.GetPipelineRunTimeout(ctx, k8sClient, integrationPipelineRun)
+		Expect(err).To(BeNil())
+		Expect(timedOut).To(BeTrue())
+		Expect(timeout).To(Equal(90 * time.Minute))
+
+		integrationPipelineRun.Status.ChildReferences = []tektonv1.ChildStatusReference{
+			{
+				Name:             failedTaskRun.Name,
+				PipelineTaskName: "pipeline1-task1",
+			},
+		}
+		integrationPipelineRun.Status.SetCondition(&apis.Condition{
+			Reason: "Failed",
+			Status: "False",
+			Type:   apis.ConditionSucceeded,
+		})
+		_, timedOut, err = helpers.GetPipelineRunTimeout(ctx, k8sClient, integrationPipelineRun)
+		Expect(err).To(BeNil())
+		Expect(timedOut).To(BeFalse())
+	})
+
+	It("describes the tasks without TEST_OUTPUT by the reason of their condition", func() {
+		taskRunStatus := &tektonv1.TaskRunStatus{
+			Status: v1.Status{
+				Conditions: v1.Conditions{
+					apis.Condition{
+						Reason: "TaskRunTimeout",
+						Status: "False",
+						Type:   apis.ConditionSucceeded,
+					},
+				},
+			},
+		}
+		taskResult := helpers.NewTaskRunFromTektonTaskRun("task-timeout", taskRunStatus).GetTaskResult()
+		Expect(taskResult).To(Equal(intgteststat.TaskResult{Name: "task-timeout", Reason: "TaskRunTimeout"}))
+	})
+
+	Context("with a retried Integration TaskRun", func() {
+		var failedAttempt tektonv1.TaskRunStatus
+
+		BeforeEach(func() {
+			failedAttempt = tektonv1.TaskRunStatus{
+				Status: v1.Status{
+					Conditions: v1.Conditions{
+						apis.Condition{
+							Reason: "Failed",
+							Status: "False",
+							Type:   apis.ConditionSucceeded,
+						},
+					},
+				},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+					Results: []tektonv1.TaskRunResult{
+						{
+							Name:  "TEST_OUTPUT",
+							Value: *tektonv1.NewStructuredValues(`{"result": "FAILURE", "timestamp": "1665405317", "failures": 1, "successes": 0, "warnings": 0}`),
+						},
+					},
+				},
+			}
+		})
+
+		It("reports the results of the final attempt whose first attempt failed", func() {
+			retriedTaskRunStatus := &tektonv1.TaskRunStatus{
+				Status: v1.Status{
+					Conditions: v1.Conditions{
+						apis.Condition{
+							Reason: "Succeeded",
+							Status: "True",
+							Type:   apis.ConditionSucceeded,
+						},
+					},
+				},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+					Results: []tektonv1.TaskRunResult{
+						{
+							Name:  "TEST_OUTPUT",
+							Value: *tektonv1.NewStructuredValues(`{"result": "SUCCESS", "timestamp": "1665405917", "failures": 0, "successes": 1, "warnings": 0}`),
+						},
+					},
+					RetriesStatus: []tektonv1.TaskRunStatus{failedAttempt},
+				},
+			}
+			integrationTaskRun := helpers.NewTaskRunFromTektonTaskRun("task-retried", retriedTaskRunStatus)
+			Expect(integrationTaskRun.HasFailed()).To(BeFalse())
+			Expect(integrationTaskRun.GetRetries()).To(Equal(1))
+
+			result, err := integrationTaskRun.GetTestResult()
+			Expect(err).To(BeNil())
+			Expect(result.TestOutput.Result).To(Equal(helpers.AppStudioTestOutputSuccess))
+			Expect(integrationTaskRun.GetTaskResult()).To(Equal(intgteststat.TaskResult{Name: "task-retried", Result: "SUCCESS", Successes: 1, Retries: 1}))
+		})
+
+		It("ignores the results carried over from the failed first attempt", func() {
+			retriedTaskRunStatus := &tektonv1.TaskRunStatus{
+				Status: v1.Status{
+					Conditions: v1.Conditions{
+						apis.Condition{
+							Reason: "Succeeded",
+							Status: "True",
+							Type:   apis.ConditionSucceeded,
+						},
+					},
+				},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+					Results:       failedAttempt.Results,
+					RetriesStatus: []tektonv1.TaskRunStatus{failedAttempt},
+				},
+			}
+			integrationTaskRun := helpers.NewTaskRunFromTektonTaskRun("task-retried", retriedTaskRunStatus)
+			Expect(integrationTaskRun.HasFailed()).To(BeFalse())
+
+			result, err := integrationTaskRun.GetTestResult()
+			Expect(err).To(BeNil())
+			Expect(result).To(BeNil())
+			Expect(integrationTaskRun.GetTaskResult()).To(Equal(intgteststat.TaskResult{Name: "task-retried", Reason: "Succeeded", Retries: 1}))
+		})
+
+		It("reports the failure of the final attempt", func() {
+			retriedTaskRunStatus := failedAttempt.DeepCopy()
+			retriedTaskRunStatus.Results = []tektonv1.TaskRunResult{
+				{
+					Name:  "TEST_OUTPUT",
+					Value: *tektonv1.NewStructuredValues(`{"result": "FAILURE", "timestamp": "1665405917", "failures": 2, "successes": 0, "warnings": 0}`),
+				},
+			}
+			retriedTaskRunStatus.RetriesStatus = []tektonv1.TaskRunStatus{failedAttempt}
+			integrationTaskRun := helpers.NewTaskRunFromTektonTaskRun("task-retried", retriedTaskRunStatus)
+			Expect(integrationTaskRun.HasFailed()).To(BeTrue())
+			Expect(integrationTaskRun.GetTaskResult()).To(Equal(intgteststat.TaskResult{Name: "task-retried", Result: "FAILURE", Failures: 2, Retries: 1}))
+		})
+	})
+
+	Context("when classifying the failures of Integration TaskRuns", func() {
+		taskRunStatus := func(conditionStatus, reason, message string, steps ...tektonv1.StepState) *tektonv1.TaskRunStatus {
+			return &tektonv1.TaskRunStatus{
+				Status: v1.Status{
+					Conditions: v1.Conditions{
+						apis.Condition{
+							Reason:  reason,
+							Message: message,
+							Status:  corev1.ConditionStatus(conditionStatus),
+							Type:    apis.ConditionSucceeded,
+						},
+					},
+				},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{Steps: steps},
+			}
+		}
+		terminatedStep := func(reason string, exitCode int32) tektonv1.StepState {
+			return tektonv1.StepState{
+				Name:           "run-tests",
+				ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode}},
+			}
+		}
+		waitingStep := func(reason string) tektonv1.StepState {
+			return tektonv1.StepState{
+				Name:           "run-tests",
+				ContainerState: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+			}
+		}
+
+		DescribeTable("classifies the infrastructure failures",
+			func(status *tektonv1.TaskRunStatus, expectedClassification, expectedStep string) {
+				classification, stepName := helpers.NewTaskRunFromTektonTaskRun("e2e-tests", status).GetInfrastructureFailure()
+				Expect(classification).To(Equal(expectedClassification))
+				Expect(stepName).To(Equal(expectedStep))
+			},
+			Entry("OOMKilled step", taskRunStatus("False", "Failed", "", terminatedStep("OOMKilled", 137)),
+				helpers.InfrastructureFailureOOMKilled, "run-tests"),
+			Entry("step killed with exit code 137", taskRunStatus("False", "Failed", "", terminatedStep("Error", 137)),
+				helpers.InfrastructureFailureOOMKilled, "run-tests"),
+			Entry("step image in back-off", taskRunStatus("False", "Failed", "", waitingStep("ImagePullBackOff")),
+				helpers.InfrastructureFailureImagePullBackOff, "run-tests"),
+			Entry("step image failed to pull", taskRunStatus("False", "Failed", "", waitingStep("ErrImagePull")),
+				helpers.InfrastructureFailureImagePullBackOff, "run-tests"),
+			Entry("TaskRun failed to pull image", taskRunStatus("False", tektonv1.TaskRunReasonImagePullFailed.String(),
+				`the step "run-tests" in TaskRun "e2e-tests" failed to pull the image ""`),
+				helpers.InfrastructureFailureImagePullBackOff, ""),
+			Entry("evicted pod", taskRunStatus("False", "Failed",
+				"The node was low on resource: memory. Threshold quantity: 100Mi, available: 12Mi."),
+				helpers.InfrastructureFailureEvicted, ""),
+			Entry("assertion failure", taskRunStatus("False", "Failed", "", terminatedStep("Error", 1)), "", ""),
+			Entry("succeeded TaskRun", taskRunStatus("True", "Succeeded", "", terminatedStep("Completed", 0)), "", ""),
+		)
+
+		It("describes the infrastructure failure of the earliest TaskRun which failed because of its infrastructure", func() {
+			taskRuns := []*helpers.TaskRun{
+				helpers.NewTaskRunFromTektonTaskRun("unit-tests", taskRunStatus("False", "Failed", "", terminatedStep("Error", 1))),
+				helpers.NewTaskRunFromTektonTaskRun("e2e-tests", taskRunStatus("False", "Failed", "", terminatedStep("OOMKilled", 137))),
+				helpers.NewTaskRunFromTektonTaskRun("perf-tests", taskRunStatus("False", "Failed", "pod was Evicted")),
+			}
+			Expect(helpers.GetInfrastructureFailure(taskRuns)).To(Equal("OOMKilled (step run-tests of task e2e-tests)"))
+			Expect(helpers.GetInfrastructureFailure(taskRuns[2:])).To(Equal("Evicted (task perf-tests)"))
+			Expect(helpers.GetInfrastructureFailure(taskRuns[:1])).To(BeEmpty())
+		})
+
+		It("reads the infrastructure failure from the details of the test status", func() {
+			failure, ok := helpers.GetInfrastructureFailureFromDetails("infrastructure failure: Evicted (task perf-tests); Integration test failed")
+			Expect(ok).To(BeTrue())
+			Expect(failure).To(Equal("Evicted (task perf-tests)"))
+
+			_, ok = helpers.GetInfrastructureFailureFromDetails("Integration test failed")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when aggregating TEST_OUTPUT results of multiple tasks", func() {
+		testResult := func(result string, successes, failures, warnings int) *helpers.IntegrationTestTaskResult {
+			return &helpers.IntegrationTestTaskResult{TestOutput: &helpers.AppStudioTestResult{
+				Result:    result,
+				Successes: successes,
+				Failures:  failures,
+				Warnings:  warnings,
+			}}
+		}
+
+		DescribeTable("takes the most severe result",
+			func(results map[string]*helpers.IntegrationTestTaskResult, expectedResult string, expectedFailed bool) {
+				aggregated := helpers.AggregateTestResults(results)
+				Expect(aggregated.Result).To(Equal(expectedResult))
+				Expect(aggregated.HasFailed()).To(Equal(expectedFailed))
+			},
+			Entry("no results", map[string]*helpers.IntegrationTestTaskResult{}, "", false),
+			Entry("SKIPPED only", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputSkipped, 0, 0, 0),
+			}, helpers.AppStudioTestOutputSkipped, false),
+			Entry("SUCCESS over SKIPPED", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputSkipped, 0, 0, 0),
+				"task-b": testResult(helpers.AppStudioTestOutputSuccess, 1, 0, 0),
+			}, helpers.AppStudioTestOutputSuccess, false),
+			Entry("WARNING over SUCCESS", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputWarning, 0, 0, 1),
+				"task-b": testResult(helpers.AppStudioTestOutputSuccess, 1, 0, 0),
+			}, helpers.AppStudioTestOutputWarning, false),
+			Entry("FAILURE over WARNING", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputWarning, 0, 0, 1),
+				"task-b": testResult(helpers.AppStudioTestOutputFailure, 0, 1, 0),
+			}, helpers.AppStudioTestOutputFailure, true),
+			Entry("ERROR over FAILURE", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputError, 0, 0, 0),
+				"task-b": testResult(helpers.AppStudioTestOutputFailure, 0, 1, 0),
+			}, helpers.AppStudioTestOutputError, true),
+			Entry("ERROR over all the other results", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": testResult(helpers.AppStudioTestOutputSkipped, 0, 0, 0),
+				"task-b": testResult(helpers.AppStudioTestOutputSuccess, 1, 0, 0),
+				"task-c": testResult(helpers.AppStudioTestOutputWarning, 0, 0, 1),
+				"task-d": testResult(helpers.AppStudioTestOutputFailure, 0, 1, 0),
+				"task-e": testResult(helpers.AppStudioTestOutputError, 0, 0, 0),
+			}, helpers.AppStudioTestOutputError, true),
+			Entry("invalid results are ignored", map[string]*helpers.IntegrationTestTaskResult{
+				"task-a": {ValidationError: fmt.Errorf("invalid TEST_OUTPUT")},
+				"task-b": testResult(helpers.AppStudioTestOutputSuccess, 1, 0, 0),
+			}, helpers.AppStudioTestOutputSuccess, false),
+		)
+
+		It("sums the test counts and names the failing tasks in order", func() {
+			results := map[string]*helpers.IntegrationTestTaskResult{
+				"task-c": testResult(helpers.AppStudioTestOutputFailure, 3, 2, 0),
+				"task-a": testResult(helpers.AppStudioTestOutputError, 0, 0, 0),
+				"task-b": testResult(helpers.AppStudioTestOutputWarning, 5, 0, 2),
+				"task-d": testResult(helpers.AppStudioTestOutputSuccess, 7, 0, 0),
+			}
+			for i := 0; i < 10; i++ {
+				Expect(helpers.AggregateTestResults(results)).To(Equal(helpers.AggregatedTestResult{
+					Result:      helpers.AppStudioTestOutputError,
+					Successes:   15,
+					Failures:    2,
+					Warnings:    2,
+					FailedTasks: []string{"task-a", "task-c"},
+				}))
+			}
+		})
+	})
+
 	It("can return nil for a PipelineRun with no childReferences", func() {
 		integrationPipelineRun.Status = tektonv1.PipelineRunStatus{
 			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{},
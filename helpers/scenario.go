@@ -17,8 +17,14 @@ limitations under the License.
 package helpers
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
 )
@@ -33,6 +39,19 @@ const (
 
 	// AppStudioIntegrationStatusValid is the reason that's set when the AppStudio integration gets into an valid state.
 	AppStudioIntegrationStatusValid = "Valid"
+
+	// IntegrationTestScenarioSuspended is the condition for marking the Scenario as suspended.
+	IntegrationTestScenarioSuspended = "Suspended"
+
+	// IntegrationTestScenarioSuspendedReason is the reason that's set when the Scenario is suspended.
+	IntegrationTestScenarioSuspendedReason = "Suspended"
+
+	// IntegrationTestScenarioActiveReason is the reason that's set when the Scenario isn't suspended.
+	IntegrationTestScenarioActiveReason = "Active"
+
+	// IntegrationTestScenarioResolvedRefValid is the condition reporting whether the pipeline referenced
+	// by the resolver reference of the Scenario can be resolved.
+	IntegrationTestScenarioResolvedRefValid = "ResolvedRefValid"
 )
 
 // SetScenarioIntegrationStatusAsInvalid sets the IntegrationTestScenarioValid status condition for the Scenario to invalid.
@@ -60,3 +79,145 @@ func IsScenarioValid(scenario *v1beta2.IntegrationTestScenario) bool {
 	statusCondition := meta.FindStatusCondition(scenario.Status.Conditions, IntegrationTestScenarioValid)
 	return statusCondition.Status != metav1.ConditionFalse
 }
+
+// SetScenarioSuspendedCondition sets the Suspended status condition of the Scenario according to its spec.
+// It returns true if the condition was changed.
+func SetScenarioSuspendedCondition(scenario *v1beta2.IntegrationTestScenario) bool {
+	condition := metav1.Condition{
+		Type:    IntegrationTestScenarioSuspended,
+		Status:  metav1.ConditionFalse,
+		Reason:  IntegrationTestScenarioActiveReason,
+		Message: "Integration test scenario is active.",
+	}
+	if scenario.Spec.Suspend {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = IntegrationTestScenarioSuspendedReason
+		condition.Message = "Integration test scenario is suspended, its tests are skipped for new snapshots."
+	}
+	return meta.SetStatusCondition(&scenario.Status.Conditions, condition)
+}
+
+// SetScenarioResolvedRefCondition sets the ResolvedRefValid status condition of the Scenario for its current generation.
+func SetScenarioResolvedRefCondition(scenario *v1beta2.IntegrationTestScenario, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
+		Type:               IntegrationTestScenarioResolvedRefValid,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: scenario.Generation,
+	})
+}
+
+// AddScenarioRecentRun appends the given run to the recent runs in the status of the Scenario, dropping the oldest
+// runs beyond v1beta2.MaxRecentRuns. It returns false if the run of the same PipelineRun was already recorded.
+func AddScenarioRecentRun(scenario *v1beta2.IntegrationTestScenario, run v1beta2.ScenarioRun) bool {
+	for _, recentRun := range scenario.Status.RecentRuns {
+		if recentRun.PipelineRun == run.PipelineRun {
+			return false
+		}
+	}
+	scenario.Status.RecentRuns = append(scenario.Status.RecentRuns, run)
+	if len(scenario.Status.RecentRuns) > v1beta2.MaxRecentRuns {
+		scenario.Status.RecentRuns = slices.Clone(scenario.Status.RecentRuns[len(scenario.Status.RecentRuns)-v1beta2.MaxRecentRuns:])
+	}
+	return true
+}
+
+// SetScenarioLastRun records the given run as the last run in the status of the Scenario unless a run which
+// finished later is already recorded there, e.g. when the PipelineRuns of multiple Snapshots finish at the same time.
+// It returns false if the last run in the status wasn't changed.
+func SetScenarioLastRun(scenario *v1beta2.IntegrationTestScenario, run v1beta2.ScenarioRun) bool {
+	status := &scenario.Status
+	if status.LastRunPipelineRun == run.PipelineRun && status.LastRunOutcome == run.Outcome {
+		return false
+	}
+	if status.LastRunTime != nil && run.CompletionTime != nil && run.CompletionTime.Before(status.LastRunTime) {
+		return false
+	}
+	status.LastRunTime = run.CompletionTime
+	status.LastRunSnapshot = run.Snapshot
+	status.LastRunOutcome = run.Outcome
+	status.LastRunPipelineRun = run.PipelineRun
+	return true
+}
+
+// IsComponentSelectedByScenario returns true if the component selector of the Scenario selects the given component
+// by its name or labels. Scenarios without a component selector select all components.
+func IsComponentSelectedByScenario(scenario *v1beta2.IntegrationTestScenario, component *applicationapiv1alpha1.Component) (bool, error) {
+	selector := scenario.Spec.ComponentSelector
+	if selector == nil || (len(selector.Names) == 0 && selector.LabelSelector == nil) {
+		return true, nil
+	}
+	if slices.Contains(selector.Names, component.Name) {
+		return true, nil
+	}
+	if selector.LabelSelector == nil {
+		return false, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the component label selector of scenario %s: %w", scenario.Name, err)
+	}
+	return labelSelector.Matches(labels.Set(component.Labels)), nil
+}
+
+// ScenarioTest is a single integration test of the Scenario, Scenarios with a matrix have a test for
+// each combination of the values of their matrix params
+type ScenarioTest struct {
+	// Name is the name of the test status of the test in the Snapshot, e.g. scenario[ocp=4.15,arch=amd64]
+	// for matrix combinations or the name of the Scenario otherwise
+	Name string
+	// MatrixParams are the values of the matrix params of the combination passed to the integration PipelineRun
+	MatrixParams []v1beta2.PipelineParameter
+}
+
+// GetScenarioTests returns the integration tests of the Scenario, a single test named after the Scenario when
+// it doesn't define a matrix or a test for each combination of the matrix params values otherwise
+func GetScenarioTests(scenario *v1beta2.IntegrationTestScenario) []ScenarioTest {
+	if len(scenario.Spec.Matrix) == 0 {
+		return []ScenarioTest{{Name: scenario.Name}}
+	}
+
+	combinations := [][]v1beta2.PipelineParameter{{}}
+	for _, matrixParam := range scenario.Spec.Matrix {
+		expanded := make([][]v1beta2.PipelineParameter, 0, len(combinations)*len(matrixParam.Values))
+		for _, combination := range combinations {
+			for _, value := range matrixParam.Values {
+				expanded = append(expanded, append(slices.Clone(combination), v1beta2.PipelineParameter{Name: matrixParam.Name, Value: value}))
+			}
+		}
+		combinations = expanded
+	}
+
+	tests := make([]ScenarioTest, 0, len(combinations))
+	for _, combination := range combinations {
+		assignments := make([]string, 0, len(combination))
+		for _, param := range combination {
+			assignments = append(assignments, fmt.Sprintf("%s=%s", param.Name, param.Value))
+		}
+		tests = append(tests, ScenarioTest{
+			Name:         fmt.Sprintf("%s[%s]", scenario.Name, strings.Join(assignments, ",")),
+			MatrixParams: combination,
+		})
+	}
+	return tests
+}
+
+// GetScenarioTestNames returns the names of the test statuses of all integration tests of the Scenarios
+func GetScenarioTestNames(scenarios *[]v1beta2.IntegrationTestScenario) *[]string {
+	testNames := []string{}
+	for i := range *scenarios {
+		for _, test := range GetScenarioTests(&(*scenarios)[i]) {
+			testNames = append(testNames, test.Name)
+		}
+	}
+	return &testNames
+}
+
+// GetScenarioNameFromTestName returns the name of the Scenario of the test status, stripping the matrix
+// combination from the test names of Scenarios with a matrix
+func GetScenarioNameFromTestName(testName string) string {
+	scenarioName, _, _ := strings.Cut(testName, "[")
+	return scenarioName
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package helpers_test
 
 import (
+	"fmt"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -87,4 +91,164 @@ var _ = Describe("Gitops functions for managing Snapshots", Ordered, func() {
 			Expect(meta.IsStatusConditionTrue(integrationTestScenario.Status.Conditions, helpers.IntegrationTestScenarioValid)).To(BeTrue())
 		})
 	})
+	Context("IntegrationTestScenario records its recent runs", func() {
+		It("ensures the recent runs are capped and rotated", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			for i := 0; i < v1beta2.MaxRecentRuns+2; i++ {
+				Expect(helpers.AddScenarioRecentRun(scenario, v1beta2.ScenarioRun{
+					Snapshot:    fmt.Sprintf("snapshot-%d", i),
+					PipelineRun: fmt.Sprintf("pipelinerun-%d", i),
+					Outcome:     "TestPassed",
+				})).To(BeTrue())
+			}
+
+			Expect(scenario.Status.RecentRuns).To(HaveLen(v1beta2.MaxRecentRuns))
+			Expect(scenario.Status.RecentRuns[0].PipelineRun).To(Equal("pipelinerun-2"))
+			Expect(scenario.Status.RecentRuns[v1beta2.MaxRecentRuns-1].PipelineRun).To(Equal(fmt.Sprintf("pipelinerun-%d", v1beta2.MaxRecentRuns+1)))
+		})
+
+		It("ensures the run of the same pipelineRun is recorded only once", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			run := v1beta2.ScenarioRun{Snapshot: "snapshot-sample", PipelineRun: "pipelinerun-sample", Outcome: "TestFail"}
+			Expect(helpers.AddScenarioRecentRun(scenario, run)).To(BeTrue())
+			Expect(helpers.AddScenarioRecentRun(scenario, run)).To(BeFalse())
+			Expect(scenario.Status.RecentRuns).To(Equal([]v1beta2.ScenarioRun{run}))
+		})
+
+		It("ensures the last run is recorded with its summary", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			completionTime := metav1.NewTime(time.Now())
+			run := v1beta2.ScenarioRun{
+				Snapshot:       "snapshot-sample",
+				PipelineRun:    "pipelinerun-sample",
+				Outcome:        "TestFail",
+				CompletionTime: &completionTime,
+			}
+			Expect(helpers.SetScenarioLastRun(scenario, run)).To(BeTrue())
+			Expect(scenario.Status.LastRunTime).To(Equal(&completionTime))
+			Expect(scenario.Status.LastRunSnapshot).To(Equal("snapshot-sample"))
+			Expect(scenario.Status.LastRunOutcome).To(Equal("TestFail"))
+			Expect(scenario.Status.LastRunPipelineRun).To(Equal("pipelinerun-sample"))
+			Expect(helpers.SetScenarioLastRun(scenario, run)).To(BeFalse())
+		})
+
+		It("ensures the last run isn't replaced by a run which finished earlier", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			laterTime := metav1.NewTime(time.Now())
+			earlierTime := metav1.NewTime(laterTime.Add(-time.Minute))
+			Expect(helpers.SetScenarioLastRun(scenario, v1beta2.ScenarioRun{
+				Snapshot:       "snapshot-later",
+				PipelineRun:    "pipelinerun-later",
+				Outcome:        "TestPassed",
+				CompletionTime: &laterTime,
+			})).To(BeTrue())
+			Expect(helpers.SetScenarioLastRun(scenario, v1beta2.ScenarioRun{
+				Snapshot:       "snapshot-earlier",
+				PipelineRun:    "pipelinerun-earlier",
+				Outcome:        "TestFail",
+				CompletionTime: &earlierTime,
+			})).To(BeFalse())
+			Expect(scenario.Status.LastRunPipelineRun).To(Equal("pipelinerun-later"))
+			Expect(scenario.Status.LastRunOutcome).To(Equal("TestPassed"))
+		})
+	})
+	Context("IntegrationTestScenario can be suspended", func() {
+		It("ensures the Suspended condition follows the spec of the Scenario", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			scenario.Spec.Suspend = true
+			Expect(helpers.SetScenarioSuspendedCondition(scenario)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(scenario.Status.Conditions, helpers.IntegrationTestScenarioSuspended)).To(BeTrue())
+			Expect(helpers.SetScenarioSuspendedCondition(scenario)).To(BeFalse())
+
+			scenario.Spec.Suspend = false
+			Expect(helpers.SetScenarioSuspendedCondition(scenario)).To(BeTrue())
+			condition := meta.FindStatusCondition(scenario.Status.Conditions, helpers.IntegrationTestScenarioSuspended)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(helpers.IntegrationTestScenarioActiveReason))
+		})
+	})
+	Context("IntegrationTestScenario can select components", func() {
+		var component *applicationapiv1alpha1.Component
+
+		BeforeEach(func() {
+			component = &applicationapiv1alpha1.Component{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "frontend",
+					Namespace: "default",
+					Labels: map[string]string{
+						"tier": "ui",
+					},
+				},
+			}
+		})
+
+		DescribeTable("ensures the component selector of the Scenario is evaluated",
+			func(componentSelector *v1beta2.ComponentSelector, expectedSelected bool) {
+				scenario := integrationTestScenario.DeepCopy()
+				scenario.Spec.ComponentSelector = componentSelector
+				selected, err := helpers.IsComponentSelectedByScenario(scenario, component)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(selected).To(Equal(expectedSelected))
+			},
+			Entry("without component selector", nil, true),
+			Entry("with empty component selector", &v1beta2.ComponentSelector{}, true),
+			Entry("with matching name", &v1beta2.ComponentSelector{Names: []string{"backend", "frontend"}}, true),
+			Entry("with other names", &v1beta2.ComponentSelector{Names: []string{"backend"}}, false),
+			Entry("with matching labels", &v1beta2.ComponentSelector{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "ui"}},
+			}, true),
+			Entry("with other labels", &v1beta2.ComponentSelector{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "db"}},
+			}, false),
+			Entry("with other names and matching labels", &v1beta2.ComponentSelector{
+				Names:         []string{"backend"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "ui"}},
+			}, true),
+		)
+
+		It("ensures an invalid component label selector is reported", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			scenario.Spec.ComponentSelector = &v1beta2.ComponentSelector{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Unknown"}},
+				},
+			}
+			selected, err := helpers.IsComponentSelectedByScenario(scenario, component)
+			Expect(err).To(HaveOccurred())
+			Expect(selected).To(BeFalse())
+		})
+	})
+
+	Context("IntegrationTestScenario can fan out over a matrix", func() {
+		It("ensures the Scenario without matrix has a single test named after it", func() {
+			tests := helpers.GetScenarioTests(integrationTestScenario)
+			Expect(tests).To(Equal([]helpers.ScenarioTest{{Name: "example-pass"}}))
+			Expect(helpers.GetScenarioNameFromTestName(tests[0].Name)).To(Equal("example-pass"))
+		})
+
+		It("ensures the Scenario has a test for each combination of its matrix params", func() {
+			scenario := integrationTestScenario.DeepCopy()
+			scenario.Spec.Matrix = []v1beta2.MatrixParam{
+				{Name: "ocp", Values: []string{"4.14", "4.15", "4.16"}},
+				{Name: "arch", Values: []string{"amd64", "arm64"}},
+			}
+
+			tests := helpers.GetScenarioTests(scenario)
+			Expect(tests).To(HaveLen(6))
+			Expect(tests[0]).To(Equal(helpers.ScenarioTest{
+				Name: "example-pass[ocp=4.14,arch=amd64]",
+				MatrixParams: []v1beta2.PipelineParameter{
+					{Name: "ocp", Value: "4.14"},
+					{Name: "arch", Value: "amd64"},
+				},
+			}))
+			Expect(tests[5].Name).To(Equal("example-pass[ocp=4.16,arch=arm64]"))
+			Expect(helpers.GetScenarioNameFromTestName(tests[5].Name)).To(Equal("example-pass"))
+
+			testNames := helpers.GetScenarioTestNames(&[]v1beta2.IntegrationTestScenario{*integrationTestScenario, *scenario})
+			Expect(*testNames).To(HaveLen(7))
+			Expect(*testNames).To(ContainElements("example-pass", "example-pass[ocp=4.15,arch=arm64]"))
+		})
+	})
 })
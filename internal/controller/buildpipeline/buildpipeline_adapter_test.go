@@ -767,6 +767,51 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 			unexpectedLogEntry = "Created new Snapshot"
 			Expect(buf.String()).ShouldNot(ContainSubstring(unexpectedLogEntry))
 		})
+
+		It("annotates the build pipelineRun when its first read for the annotation conflicts", func() {
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+
+			conflictErr := k8serrors.NewConflict(tektonv1.Resource("pipelineruns"), buildPipelineRun.Name,
+				errors.New("the object has been modified"))
+			pipelineRunSequence := loader.NewMockSequence(
+				loader.MockResult{Err: conflictErr},
+				loader.MockResult{Resource: buildPipelineRun},
+			)
+			adapter = NewAdapter(ctx, buildPipelineRun, hasComp, hasApp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.GetPipelineRunContextKey,
+					Resource:   pipelineRunSequence,
+				},
+				{
+					ContextKey: loader.AllSnapshotsForBuildPipelineRunContextKey,
+					Resource:   []applicationapiv1alpha1.Snapshot{*hasSnapshot},
+				},
+			})
+
+			result, err := adapter.EnsureSnapshotExists()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			// the conflicting read is retried, then the final update of the pipelineRun reads it once more
+			Expect(pipelineRunSequence.Calls()).To(Equal(3))
+			Expect(buf.String()).Should(ContainSubstring("Updated build pipelineRun"))
+			Eventually(func() bool {
+				updatedPipelineRun := &tektonv1.PipelineRun{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Namespace: buildPipelineRun.Namespace, Name: buildPipelineRun.Name}, updatedPipelineRun)
+				return err == nil && updatedPipelineRun.Annotations[tekton.SnapshotNameLabel] == hasSnapshot.Name
+			}, time.Second*10).Should(BeTrue())
+		})
 	})
 
 	When("multiple succesfull build pipeline runs exists for the same component", func() {
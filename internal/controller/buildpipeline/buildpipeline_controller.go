@@ -67,6 +67,7 @@ func NewIntegrationReconciler(client client.Client, logger *logr.Logger, scheme
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("buildpipelineRun", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
 	loader := loader.NewLoader()
 
 	pipelineRun := &tektonv1.PipelineRun{}
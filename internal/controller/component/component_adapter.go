@@ -27,7 +27,6 @@ import (
 	"github.com/konflux-ci/integration-service/metrics"
 	"github.com/konflux-ci/operator-toolkit/controller"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -108,10 +107,10 @@ func (a *Adapter) EnsureComponentIsCleanedUp() (controller.OperationResult, erro
 		}
 	}
 	// STONEINTG-828: We are refreshing state of component to minimize race condition with updating finalizers
-	a.component, err = a.loader.GetComponent(a.context, a.client, a.component.Name, a.component.Namespace)
+	a.component, err = a.loader.GetComponent(loader.WithoutMemoization(a.context), a.client, a.component.Name, a.component.Namespace)
 
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if loader.IsResourceNotFound(err) {
 			return controller.ContinueProcessing()
 		} else {
 			a.logger.Error(err, "Failed to get component.")
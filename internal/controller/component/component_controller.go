@@ -56,6 +56,7 @@ func NewComponentReconciler(client client.Client, logger *logr.Logger, scheme *r
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("component", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
 	loader := loader.NewLoader()
 
 	component := &applicationapiv1alpha1.Component{}
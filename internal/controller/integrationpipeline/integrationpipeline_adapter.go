@@ -18,40 +18,78 @@ package integrationpipeline
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/konflux-ci/integration-service/api/v1beta2"
 	"github.com/konflux-ci/integration-service/gitops"
 	h "github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/loader"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
+	"github.com/konflux-ci/integration-service/status"
 	"github.com/konflux-ci/integration-service/tekton"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
 	"github.com/konflux-ci/operator-toolkit/controller"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// DefaultPipelineRunTTL is the time after which the finished integration PipelineRuns are deleted once their status
+// was reported, when their IntegrationTestScenario doesn't set its own pipelineRunTTL. Zero disables the cleanup.
+var DefaultPipelineRunTTL time.Duration
+
+// configurationErrorGuidance maps the reasons of the failed integration PipelineRuns, and of their TaskRuns, which
+// indicate a problem with the configuration of the integration test rather than a failure of the test itself
+// to the guidance reported to the users
+var configurationErrorGuidance = map[string]string{
+	tektonv1.PipelineRunReasonCouldntGetPipeline.String():      "the pipeline couldn't be resolved, check the resolverRef of the IntegrationTestScenario",
+	tektonv1.PipelineRunReasonCouldntGetTask.String():          "a task of the pipeline couldn't be resolved, check the task references of the pipeline",
+	tektonv1.PipelineRunReasonFailedValidation.String():        "the pipeline failed validation, check its definition",
+	tektonv1.PipelineRunReasonInvalidGraph.String():            "the tasks of the pipeline don't form a valid graph, check their runAfter and result references",
+	tektonv1.PipelineRunReasonParameterMissing.String():        "a param required by the pipeline is missing, check the params of the IntegrationTestScenario",
+	tektonv1.PipelineRunReasonParameterTypeMismatch.String():   "a param doesn't match its type declared by the pipeline, check the params of the IntegrationTestScenario",
+	tektonv1.PipelineRunReasonInvalidWorkspaceBinding.String(): "a workspace of the pipeline isn't bound, check the workspaces of the IntegrationTestScenario",
+	tektonv1.TaskRunReasonImagePullFailed.String():             "an image of a task couldn't be pulled, check the image references of the pipeline tasks",
+}
+
 // Adapter holds the objects needed to reconcile an integration PipelineRun.
 type Adapter struct {
-	pipelineRun *tektonv1.PipelineRun
-	application *applicationapiv1alpha1.Application
-	snapshot    *applicationapiv1alpha1.Snapshot
-	loader      loader.ObjectLoader
-	logger      h.IntegrationLogger
-	client      client.Client
-	context     context.Context
+	pipelineRun  *tektonv1.PipelineRun
+	application  *applicationapiv1alpha1.Application
+	snapshot     *applicationapiv1alpha1.Snapshot
+	loader       loader.ObjectLoader
+	logger       h.IntegrationLogger
+	client       client.Client
+	context      context.Context
+	podLogClient status.PodLogClient
+}
+
+// AdapterOption is used to extend Adapter with optional parameters.
+type AdapterOption = func(a *Adapter)
+
+// WithPodLogClient sets the client used to fetch the log excerpt of the first failed step of failed tests
+func WithPodLogClient(podLogClient status.PodLogClient) AdapterOption {
+	return func(a *Adapter) {
+		a.podLogClient = podLogClient
+	}
 }
 
 // NewAdapter creates and returns an Adapter instance.
 func NewAdapter(context context.Context, pipelineRun *tektonv1.PipelineRun, application *applicationapiv1alpha1.Application,
 	snapshot *applicationapiv1alpha1.Snapshot, logger h.IntegrationLogger, loader loader.ObjectLoader, client client.Client,
+	opts ...AdapterOption,
 ) *Adapter {
-	return &Adapter{
+	adapter := &Adapter{
 		pipelineRun: pipelineRun,
 		application: application,
 		snapshot:    snapshot,
@@ -60,6 +98,10 @@ func NewAdapter(context context.Context, pipelineRun *tektonv1.PipelineRun, appl
 		client:      client,
 		context:     context,
 	}
+	for _, opt := range opts {
+		opt(adapter)
+	}
+	return adapter
 }
 
 // EnsureStatusReportedInSnapshot will ensure that status of the integration test pipelines is reported to snapshot
@@ -68,6 +110,7 @@ func (a *Adapter) EnsureStatusReportedInSnapshot() (controller.OperationResult,
 	var pipelinerunStatus intgteststat.IntegrationTestStatus
 	var detail string
 	var err error
+	replacedByRerun := false
 
 	// pipelines run in parallel and have great potential to cause conflict on update
 	// thus `RetryOnConflict` is easy solution here, given the snapshot must be loaded specifically here
@@ -83,14 +126,74 @@ func (a *Adapter) EnsureStatusReportedInSnapshot() (controller.OperationResult,
 			return err
 		}
 
+		testStatusName := tekton.GetTestStatusName(a.pipelineRun)
+		// the pipelineRun was replaced by a re-run, e.g. it was cancelled by a forced re-run,
+		// its status must not overwrite the status of the re-run
+		if testStatus, ok := statuses.GetScenarioStatus(testStatusName); ok &&
+			testStatus.TestPipelineRunName != "" && testStatus.TestPipelineRunName != a.pipelineRun.Name {
+			a.logger.Info("The pipelineRun was replaced by a re-run, skipping the update of its status in snapshot",
+				"test.Name", testStatusName, "rerun.PipelineRun.Name", testStatus.TestPipelineRunName)
+			replacedByRerun = true
+			return nil
+		}
+		// the test was canceled when the snapshot was superseded, obsoleted by an override snapshot or its
+		// pull/merge request was closed, the outcome of its cancelled pipelineRun must not overwrite the cancellation
+		if testStatus, ok := statuses.GetScenarioStatus(testStatusName); ok &&
+			testStatus.Status == intgteststat.IntegrationTestStatusDeleted && gitops.AreSnapshotTestsCanceled(a.snapshot) {
+			a.logger.Info("The test was canceled together with the snapshot, skipping the update of its status in snapshot",
+				"test.Name", testStatusName)
+			return nil
+		}
+		// the test was failed by the watchdog timeout after being stuck in progress, the outcome of its
+		// cancelled pipelineRun must not overwrite the watchdog failure
+		if testStatus, ok := statuses.GetScenarioStatus(testStatusName); ok &&
+			testStatus.Status == intgteststat.IntegrationTestStatusTestFail && testStatus.WatchdogTimedOutAfter != "" {
+			a.logger.Info("The test was failed by the watchdog timeout, skipping the update of its status in snapshot",
+				"test.Name", testStatusName)
+			return nil
+		}
+
 		pipelinerunStatus, detail, err = a.GetIntegrationPipelineRunStatus(a.context, a.client, a.pipelineRun)
 		if err != nil {
 			return err
 		}
-		statuses.UpdateTestStatusIfChanged(a.pipelineRun.Labels[tekton.ScenarioNameLabel], pipelinerunStatus, detail)
-		if err = statuses.UpdateTestPipelineRunName(a.pipelineRun.Labels[tekton.ScenarioNameLabel], a.pipelineRun.Name); err != nil {
+		timedOutAfter := ""
+		if pipelinerunStatus == intgteststat.IntegrationTestStatusTestFail {
+			timeout, timedOut, err := h.GetPipelineRunTimeout(a.context, a.client, a.pipelineRun)
+			if err != nil {
+				return err
+			}
+			if timedOut {
+				timedOutAfter = timeout.String()
+				detail = fmt.Sprintf("Integration test timed out after %s", timedOutAfter)
+			}
+		}
+		statuses.UpdateTestStatusIfChanged(testStatusName, pipelinerunStatus, detail)
+		if err = statuses.UpdateTestPipelineRunName(testStatusName, a.pipelineRun.Name); err != nil {
 			return err
 		}
+		if err = statuses.UpdateTestTimedOutAfter(testStatusName, timedOutAfter); err != nil {
+			return err
+		}
+		if h.HasPipelineRunFinished(a.pipelineRun) {
+			taskResults, err := h.GetTaskResultsFromPipelineRun(a.context, a.client, a.pipelineRun)
+			if err != nil {
+				return err
+			}
+			if err = statuses.UpdateTaskResults(testStatusName, taskResults); err != nil {
+				return err
+			}
+		}
+		if pipelinerunStatus == intgteststat.IntegrationTestStatusTestFail && h.HasPipelineRunFinished(a.pipelineRun) {
+			if err = a.recordFailedStepLog(statuses, testStatusName); err != nil {
+				return err
+			}
+		}
+		if pipelinerunStatus == intgteststat.IntegrationTestStatusTestPassed && h.HasPipelineRunFinished(a.pipelineRun) {
+			if err = a.exportResults(testStatusName); err != nil {
+				return err
+			}
+		}
 
 		// don't return wrapped err for retries
 		err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, statuses, a.client)
@@ -101,9 +204,39 @@ func (a *Adapter) EnsureStatusReportedInSnapshot() (controller.OperationResult,
 		return controller.RequeueWithError(fmt.Errorf("failed to update test status in snapshot: %w", err))
 	}
 
-	// Remove the finalizer from Integration PLRs only if they are related to Snapshots created by Push event
-	// If they are related, then the statusreport controller removes the finalizers from these PLRs
-	if gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) && (h.HasPipelineRunFinished(a.pipelineRun) || pipelinerunStatus == intgteststat.IntegrationTestStatusDeleted) {
+	if !replacedByRerun {
+		a.recordSnapshotTimeInQueue()
+	}
+
+	// the slot of the cap on running integration pipelineRuns is held by the test until its pipelineRun finishes,
+	// running pipelineRuns are tracked so the slots are accounted for after a restart of the controller
+	if !replacedByRerun {
+		queueKey := pipelinerunqueue.TestKey(a.pipelineRun.Namespace, a.snapshot.Name, tekton.GetTestStatusName(a.pipelineRun))
+		if h.HasPipelineRunFinished(a.pipelineRun) || a.pipelineRun.GetDeletionTimestamp() != nil {
+			pipelinerunqueue.DefaultQueue.Release(queueKey)
+		} else {
+			pipelinerunqueue.DefaultQueue.Track(queueKey)
+		}
+	}
+
+	// Remove the finalizer from Integration PLRs once their outcome, or their deletion while they were running,
+	// is recorded in the Snapshot, the PLRs replaced by re-runs aren't part of the Snapshot statuses anymore.
+	// The statuses are reported to the git provider from the Snapshot, so the PLRs don't have to be kept
+	// until then and outages of the git provider don't block their deletion.
+	// The failed PLRs kept for debugging keep the finalizer until their retention elapses
+	if h.HasPipelineRunFinished(a.pipelineRun) || a.pipelineRun.GetDeletionTimestamp() != nil {
+		if a.pipelineRun.GetDeletionTimestamp() == nil {
+			_, debugKeepRemaining, err := a.getDebugKeep()
+			if err != nil {
+				a.logger.Error(err, "Failed to get the debug retention of the pipelineRun")
+				return controller.RequeueWithError(err)
+			}
+			if debugKeepRemaining > 0 {
+				a.logger.Info("The failed pipelineRun is kept for debugging, keeping its finalizer",
+					"pipelineRun.Name", a.pipelineRun.Name, "remaining", debugKeepRemaining.String())
+				return controller.ContinueProcessing()
+			}
+		}
 		err = h.RemoveFinalizerFromPipelineRun(a.context, a.client, a.logger, a.pipelineRun, h.IntegrationPipelineRunFinalizer)
 		if err != nil {
 			return controller.RequeueWithError(fmt.Errorf("failed to remove the finalizer: %w", err))
@@ -113,6 +246,354 @@ func (a *Adapter) EnsureStatusReportedInSnapshot() (controller.OperationResult,
 	return controller.ContinueProcessing()
 }
 
+// recordSnapshotTimeInQueue records how long the snapshot waited from its creation until the start of its first
+// integration pipelineRun. The pipelineRuns which haven't started yet, e.g. because of a quota, aren't recorded and
+// the duration is recorded only once. Failing to record it doesn't block the reporting of the test status
+func (a *Adapter) recordSnapshotTimeInQueue() {
+	if a.pipelineRun.Status.StartTime == nil {
+		return
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if _, recorded := gitops.GetSnapshotTimeInQueue(a.snapshot); recorded {
+			return nil
+		}
+		err := gitops.MarkSnapshotTimeInQueue(a.context, a.client, a.snapshot, *a.pipelineRun.Status.StartTime)
+		if errors.IsConflict(err) {
+			if snapshot, getErr := a.loader.GetSnapshotFromPipelineRun(a.context, a.client, a.pipelineRun); getErr == nil {
+				a.snapshot = snapshot
+			}
+		}
+		return err
+	})
+	if err != nil {
+		a.logger.Error(err, "Failed to record the time the snapshot waited until its first integration pipelineRun started",
+			"snapshot.Name", a.snapshot.Name)
+	}
+}
+
+// recordFailedStepLog keeps the log excerpt of the first failed step of the failed pipelineRun in the test status,
+// so it can still be reported once the pipelineRun is pruned. The excerpt is fetched only once, as the pod
+// running the step can be deleted at any time, and is kept in the test status until the test is re-run
+func (a *Adapter) recordFailedStepLog(statuses *intgteststat.SnapshotIntegrationTestStatuses, testStatusName string) error {
+	if a.podLogClient == nil {
+		return nil
+	}
+	if testStatus, ok := statuses.GetScenarioStatus(testStatusName); !ok || testStatus.FailedStepLog != "" {
+		return nil
+	}
+
+	taskRuns, err := h.GetAllChildTaskRunsForPipelineRun(a.context, a.client, a.pipelineRun)
+	if err != nil {
+		return err
+	}
+	failedStepLog := status.GetFailedStepLogExcerpt(a.context, a.podLogClient, taskRuns, a.pipelineRun.Namespace, a.logger.Logger)
+	if failedStepLog == "" {
+		return nil
+	}
+	return statuses.UpdateTestFailedStepLog(testStatusName, failedStepLog)
+}
+
+// exportResults records the results of the passed pipelineRun listed in the exportResults of its IntegrationTestScenario
+// in the snapshot, so the params of the scenarios depending on it can reference them. The results are recorded
+// before the test status, so the dependent scenarios never start without them
+func (a *Adapter) exportResults(testStatusName string) error {
+	scenarioName, ok := a.pipelineRun.Labels[tekton.ScenarioNameLabel]
+	if !ok {
+		return nil
+	}
+	scenario, err := a.loader.GetScenario(a.context, a.client, scenarioName, a.pipelineRun.Namespace)
+	if loader.IsResourceNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(scenario.Spec.ExportResults) == 0 {
+		return nil
+	}
+
+	results := map[string]string{}
+	for _, pipelineRunResult := range a.pipelineRun.Status.Results {
+		if !slices.Contains(scenario.Spec.ExportResults, pipelineRunResult.Name) {
+			continue
+		}
+		if pipelineRunResult.Value.Type == tektonv1.ParamTypeString {
+			results[pipelineRunResult.Name] = pipelineRunResult.Value.StringVal
+			continue
+		}
+		value, err := json.Marshal(pipelineRunResult.Value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the value of result %s: %w", pipelineRunResult.Name, err)
+		}
+		results[pipelineRunResult.Name] = string(value)
+	}
+	for _, resultName := range scenario.Spec.ExportResults {
+		if _, ok := results[resultName]; !ok {
+			a.logger.Info("The pipelineRun didn't produce a result listed in the exportResults of its IntegrationTestScenario",
+				"integrationTestScenario.Name", scenarioName, "result.Name", resultName)
+		}
+	}
+
+	return gitops.SetExportedResults(a.context, a.client, a.snapshot, testStatusName, results)
+}
+
+// EnsureScenarioRecentRunsRecorded will ensure that the finished integration test pipelineRun is recorded in the
+// recent runs of its IntegrationTestScenario and, unless a later run is already recorded, as its last run
+func (a *Adapter) EnsureScenarioRecentRunsRecorded() (controller.OperationResult, error) {
+	if !h.HasPipelineRunFinished(a.pipelineRun) {
+		return controller.ContinueProcessing()
+	}
+
+	scenarioName, ok := a.pipelineRun.Labels[tekton.ScenarioNameLabel]
+	if !ok {
+		return controller.ContinueProcessing()
+	}
+
+	statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		a.logger.Error(err, "Failed to get integration test statuses from snapshot")
+		return controller.RequeueWithError(err)
+	}
+	testStatus, ok := statuses.GetScenarioStatus(tekton.GetTestStatusName(a.pipelineRun))
+	if !ok || testStatus.TestPipelineRunName != a.pipelineRun.Name || !testStatus.Status.IsFinal() {
+		return controller.ContinueProcessing()
+	}
+
+	run := v1beta2.ScenarioRun{
+		Snapshot:       a.snapshot.Name,
+		PipelineRun:    a.pipelineRun.Name,
+		Outcome:        testStatus.Status.String(),
+		CompletionTime: a.pipelineRun.Status.CompletionTime,
+	}
+	if a.pipelineRun.Status.StartTime != nil && a.pipelineRun.Status.CompletionTime != nil {
+		run.Duration = &metav1.Duration{Duration: a.pipelineRun.Status.CompletionTime.Sub(a.pipelineRun.Status.StartTime.Time)}
+	}
+
+	// pipelineRuns of multiple snapshots can finish at the same time, the optimistic lock makes sure
+	// that the runs recorded by the other reconciles aren't overwritten
+	recorded := false
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scenario, err := a.loader.GetScenario(a.context, a.client, scenarioName, a.pipelineRun.Namespace)
+		if err != nil {
+			return err
+		}
+
+		patch := client.MergeFromWithOptions(scenario.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		addedToRecentRuns := h.AddScenarioRecentRun(scenario, run)
+		setAsLastRun := h.SetScenarioLastRun(scenario, run)
+		recorded = addedToRecentRuns || setAsLastRun
+		if !recorded {
+			return nil
+		}
+
+		// don't return wrapped err for retries
+		return a.client.Status().Patch(a.context, scenario, patch)
+	})
+	if errors.IsNotFound(err) {
+		a.logger.Info("IntegrationTestScenario of the pipelineRun was not found, skipping the recording of the run",
+			"integrationTestScenario.Name", scenarioName)
+		return controller.ContinueProcessing()
+	}
+	if err != nil {
+		a.logger.Error(err, "Failed to record the pipelineRun in the IntegrationTestScenario status",
+			"integrationTestScenario.Name", scenarioName)
+		return controller.RequeueWithError(fmt.Errorf("failed to record the run in scenario status: %w", err))
+	}
+	if recorded {
+		a.logger.Info("Recorded the pipelineRun in the recent runs of the IntegrationTestScenario",
+			"integrationTestScenario.Name", scenarioName,
+			"outcome", run.Outcome)
+	}
+
+	return controller.ContinueProcessing()
+}
+
+// EnsurePipelineRunIsCleanedUpAfterTTL will ensure that the finished integration pipelineRun is deleted once its
+// status was reported and the pipelineRun TTL of its IntegrationTestScenario elapsed
+func (a *Adapter) EnsurePipelineRunIsCleanedUpAfterTTL() (controller.OperationResult, error) {
+	if !h.HasPipelineRunFinished(a.pipelineRun) || a.pipelineRun.DeletionTimestamp != nil || a.pipelineRun.Status.CompletionTime == nil {
+		return controller.ContinueProcessing()
+	}
+
+	debugKeep, debugKeepRemaining, err := a.getDebugKeep()
+	if err != nil {
+		a.logger.Error(err, "Failed to get the debug retention of the pipelineRun")
+		return controller.RequeueWithError(err)
+	}
+	if debugKeepRemaining > 0 {
+		a.logger.Info("The failed pipelineRun is kept for debugging, postponing its cleanup",
+			"pipelineRun.Name", a.pipelineRun.Name, "remaining", debugKeepRemaining.String())
+		return controller.RequeueAfter(debugKeepRemaining, nil)
+	}
+	if debugKeep > 0 {
+		// the finalizer was kept while the failed pipelineRun was kept for debugging, normal cleanup resumes now
+		err = h.RemoveFinalizerFromPipelineRun(a.context, a.client, a.logger, a.pipelineRun, h.IntegrationPipelineRunFinalizer)
+		if err != nil {
+			return controller.RequeueWithError(fmt.Errorf("failed to remove the finalizer: %w", err))
+		}
+	}
+
+	ttl, err := a.getPipelineRunTTL()
+	if err != nil {
+		a.logger.Error(err, "Failed to get the pipelineRun TTL")
+		return controller.RequeueWithError(err)
+	}
+	if ttl <= 0 {
+		return controller.ContinueProcessing()
+	}
+
+	if remaining := ttl - time.Since(a.pipelineRun.Status.CompletionTime.Time); remaining > 0 {
+		return controller.RequeueAfter(remaining, nil)
+	}
+
+	reported, err := a.isPipelineRunStatusReported()
+	if err != nil {
+		a.logger.Error(err, "Failed to check if the status of the pipelineRun was reported")
+		return controller.RequeueWithError(err)
+	}
+	if !reported {
+		a.logger.Info("The TTL of the pipelineRun elapsed but its status wasn't reported yet, postponing its cleanup",
+			"pipelineRun.Name", a.pipelineRun.Name)
+		return controller.RequeueAfter(ttl, nil)
+	}
+
+	err = h.RemoveFinalizerFromPipelineRun(a.context, a.client, a.logger, a.pipelineRun, h.IntegrationPipelineRunFinalizer)
+	if err != nil {
+		return controller.RequeueWithError(fmt.Errorf("failed to remove the finalizer: %w", err))
+	}
+	err = a.client.Delete(a.context, a.pipelineRun)
+	if err != nil && !errors.IsNotFound(err) {
+		a.logger.Error(err, "Failed to delete the pipelineRun after its TTL elapsed", "pipelineRun.Name", a.pipelineRun.Name)
+		return controller.RequeueWithError(err)
+	}
+	a.logger.LogAuditEvent("Deleted the integration pipelineRun after its TTL elapsed", a.pipelineRun, h.LogActionDelete,
+		"ttl", ttl.String())
+
+	return controller.ContinueProcessing()
+}
+
+// getPipelineRunTTL returns the pipelineRun TTL of the IntegrationTestScenario of the pipelineRun, falling back
+// to the default TTL when the scenario doesn't set it or doesn't exist anymore
+func (a *Adapter) getPipelineRunTTL() (time.Duration, error) {
+	scenarioName, ok := a.pipelineRun.Labels[tekton.ScenarioNameLabel]
+	if !ok {
+		return DefaultPipelineRunTTL, nil
+	}
+
+	scenario, err := a.loader.GetScenario(a.context, a.client, scenarioName, a.pipelineRun.Namespace)
+	if err != nil {
+		if loader.IsResourceNotFound(err) {
+			return DefaultPipelineRunTTL, nil
+		}
+		return 0, err
+	}
+	if scenario.Spec.PipelineRunTTL == "" {
+		return DefaultPipelineRunTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(scenario.Spec.PipelineRunTTL)
+	if err != nil {
+		a.logger.Error(err, "Failed to parse the pipelineRun TTL of the IntegrationTestScenario, using the default TTL",
+			"integrationTestScenario.Name", scenarioName)
+		return DefaultPipelineRunTTL, nil
+	}
+	return ttl, nil
+}
+
+// classifyConfigurationError returns the reason and the guidance of the configuration error which made the failed
+// pipelineRun fail, either reported by the pipelineRun itself or by the earliest of its taskRuns which failed because
+// of it. Empty strings are returned when the pipelineRun didn't fail because of its configuration.
+func classifyConfigurationError(pipelineRun *tektonv1.PipelineRun, taskRuns []*h.TaskRun) (string, string) {
+	condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded)
+	if condition.IsFalse() {
+		if guidance, ok := configurationErrorGuidance[condition.Reason]; ok {
+			return condition.Reason, guidance
+		}
+	}
+	for _, taskRun := range taskRuns {
+		reason := taskRun.GetFailureReason()
+		if guidance, ok := configurationErrorGuidance[reason]; ok {
+			return reason, fmt.Sprintf("%s (task %s)", guidance, taskRun.GetPipelineTaskName())
+		}
+	}
+	return "", ""
+}
+
+// getDebugKeep returns for how long the failed pipelineRun is kept for debugging after its completion and how much
+// of that retention remains, as requested by the debug-keep annotation of its IntegrationTestScenario or its Snapshot,
+// the longer of the two durations applies. Zero is returned when the pipelineRun isn't kept, e.g. when its failure
+// wasn't recorded in the Snapshot yet, invalid annotation values are ignored.
+func (a *Adapter) getDebugKeep() (keep, remaining time.Duration, err error) {
+	if !h.HasPipelineRunFinished(a.pipelineRun) || a.pipelineRun.Status.CompletionTime == nil {
+		return 0, 0, nil
+	}
+	statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return 0, 0, err
+	}
+	testStatus, ok := statuses.GetScenarioStatus(tekton.GetTestStatusName(a.pipelineRun))
+	if !ok || testStatus.TestPipelineRunName != a.pipelineRun.Name || (testStatus.Status != intgteststat.IntegrationTestStatusTestFail && testStatus.Status != intgteststat.IntegrationTestStatusTestError) {
+		return 0, 0, nil
+	}
+
+	keep, err = gitops.GetDebugKeepDuration(a.snapshot)
+	if err != nil {
+		a.logger.Error(err, "Failed to parse the debug retention of the Snapshot, ignoring it",
+			"snapshot.Name", a.snapshot.Name)
+	}
+	if scenarioName, ok := a.pipelineRun.Labels[tekton.ScenarioNameLabel]; ok {
+		scenario, err := a.loader.GetScenario(a.context, a.client, scenarioName, a.pipelineRun.Namespace)
+		if err != nil && !loader.IsResourceNotFound(err) {
+			return 0, 0, err
+		}
+		if err == nil {
+			scenarioKeep, err := gitops.GetDebugKeepDuration(scenario)
+			if err != nil {
+				a.logger.Error(err, "Failed to parse the debug retention of the IntegrationTestScenario, ignoring it",
+					"integrationTestScenario.Name", scenarioName)
+			}
+			keep = max(keep, scenarioKeep)
+		}
+	}
+
+	if keep == 0 {
+		return 0, 0, nil
+	}
+	return keep, max(keep-time.Since(a.pipelineRun.Status.CompletionTime.Time), 0), nil
+}
+
+// isPipelineRunStatusReported returns true if the final status of the pipelineRun was written into the Snapshot
+// and, for Snapshots created for pull requests, reported to the git provider. Deleting such pipelineRun doesn't
+// lose any of the results visible to the users.
+func (a *Adapter) isPipelineRunStatusReported() (bool, error) {
+	testStatusName := tekton.GetTestStatusName(a.pipelineRun)
+	statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return false, err
+	}
+	testStatus, ok := statuses.GetScenarioStatus(testStatusName)
+	if !ok {
+		return false, nil
+	}
+	// the pipelineRun was replaced by a re-run, its results aren't part of the Snapshot anymore
+	if testStatus.TestPipelineRunName != a.pipelineRun.Name {
+		return true, nil
+	}
+	if !testStatus.Status.IsFinal() {
+		return false, nil
+	}
+	if gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) {
+		return true, nil
+	}
+
+	reportStatus, err := status.NewSnapshotReportStatusFromSnapshot(a.snapshot)
+	if err != nil {
+		return false, err
+	}
+	return !reportStatus.IsNewer(testStatusName, testStatus.LastUpdateTime), nil
+}
+
 // EnsureEphemeralEnvironmentsCleanedUp will ensure that ephemeral environment(s) associated with the
 // integration PipelineRun are cleaned up.
 func (a *Adapter) EnsureEphemeralEnvironmentsCleanedUp() (controller.OperationResult, error) {
@@ -121,7 +602,7 @@ func (a *Adapter) EnsureEphemeralEnvironmentsCleanedUp() (controller.OperationRe
 	}
 
 	testEnvironment, err := a.loader.GetEnvironmentFromIntegrationPipelineRun(a.context, a.client, a.pipelineRun)
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil && !loader.IsResourceNotFound(err) {
 		a.logger.Error(err, "Failed to find the environment for the pipelineRun")
 		return controller.RequeueWithError(err)
 	}
@@ -187,10 +668,28 @@ func (a *Adapter) GetIntegrationPipelineRunStatus(ctx context.Context, adapterCl
 	}
 
 	if !outcome.HasPipelineRunPassedTesting() {
+		detail := "Integration test failed"
 		if !outcome.HasPipelineRunValidTestOutputs() {
-			return intgteststat.IntegrationTestStatusTestFail, strings.Join(outcome.GetValidationErrorsList(), "; "), nil
+			detail = strings.Join(outcome.GetValidationErrorsList(), "; ")
+		} else if aggregated := outcome.GetAggregatedTestResult(); aggregated.HasFailed() {
+			detail = fmt.Sprintf("Integration test failed (%s): failing tasks %s, %d success(es), %d failure(s), %d warning(s)",
+				aggregated.Result, strings.Join(aggregated.FailedTasks, ", "), aggregated.Successes, aggregated.Failures, aggregated.Warnings)
+		}
+
+		// distinguish the tests which failed because of their configuration or of the infrastructure running them
+		// from genuine test failures
+		childTaskRuns, err := h.GetAllChildTaskRunsForPipelineRun(ctx, adapterClient, pipelineRun)
+		if err != nil {
+			return intgteststat.IntegrationTestStatusTestFail, "", fmt.Errorf("failed to get the taskRuns of the pipelineRun: %w", err)
+		}
+		if reason, guidance := classifyConfigurationError(pipelineRun, childTaskRuns); reason != "" {
+			return intgteststat.IntegrationTestStatusTestError,
+				fmt.Sprintf("Integration test couldn't run because of a configuration error (%s): %s", reason, guidance), nil
+		}
+		if infrastructureFailure := h.GetInfrastructureFailure(childTaskRuns); infrastructureFailure != "" {
+			detail = fmt.Sprintf("%s %s; %s", h.InfrastructureFailurePrefix, infrastructureFailure, detail)
 		}
-		return intgteststat.IntegrationTestStatusTestFail, "Integration test failed", nil
+		return intgteststat.IntegrationTestStatusTestFail, detail, nil
 	}
 
 	return intgteststat.IntegrationTestStatusTestPassed, "Integration test passed", nil
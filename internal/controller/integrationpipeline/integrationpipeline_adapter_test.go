@@ -18,6 +18,7 @@ package integrationpipeline
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/loader"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/tekton"
 	toolkit "github.com/konflux-ci/operator-toolkit/loader"
 
 	"knative.dev/pkg/apis"
@@ -34,9 +36,13 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
@@ -45,6 +51,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// mockPodLogClient returns predefined logs per container
+type mockPodLogClient struct {
+	logs map[string]string
+}
+
+func (c *mockPodLogClient) GetContainerLogs(ctx context.Context, namespace, podName, containerName string, tailLines, limitBytes int64) ([]byte, error) {
+	log, ok := c.logs[fmt.Sprintf("%s/%s", podName, containerName)]
+	if !ok {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, podName)
+	}
+	return []byte(log), nil
+}
+
 var _ = Describe("Pipeline Adapter", Ordered, func() {
 	var (
 		adapter *Adapter
@@ -266,6 +285,16 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 			TaskRunStatusFields: tektonv1.TaskRunStatusFields{
 				StartTime:      &metav1.Time{Time: now},
 				CompletionTime: &metav1.Time{Time: now.Add(5 * time.Minute)},
+				PodName:        "test-taskrun-fail-pod",
+				Steps: []tektonv1.StepState{
+					{
+						Name:      "run-tests",
+						Container: "step-run-tests",
+						ContainerState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+						},
+					},
+				},
 				Results: []tektonv1.TaskRunResult{
 					{
 						Name: "TEST_OUTPUT",
@@ -451,8 +480,233 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
 			Expect(ok).To(BeTrue())
 			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			Expect(detail.TaskResults).To(ConsistOf(SatisfyAll(
+				HaveField("Name", "task1"),
+				HaveField("Result", "SUCCESS"),
+			)))
+		})
+
+		It("ensures test status of the matrix combination in snapshot is updated to passed", func() {
+			matrixPipelineRun := integrationPipelineRunComponent.DeepCopy()
+			matrixPipelineRun.Annotations = map[string]string{
+				tekton.TestStatusNameAnnotation: integrationTestScenario.Name + "[ocp=4.16]",
+			}
+			adapter.pipelineRun = matrixPipelineRun
+			result, err := adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name + "[ocp=4.16]")
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			Expect(detail.TestPipelineRunName).To(Equal(matrixPipelineRun.Name))
+		})
+
+		It("ensures the time the snapshot waited until its first pipelineRun started is recorded only once", func() {
+			// the pipelineRun which hasn't started yet, e.g. because of a quota, isn't recorded
+			integrationPipelineRunComponent.Status.StartTime = nil
+			result, err := adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			_, recorded := gitops.GetSnapshotTimeInQueue(hasSnapshot)
+			Expect(recorded).To(BeFalse())
+
+			integrationPipelineRunComponent.Status.StartTime = &metav1.Time{Time: hasSnapshot.CreationTimestamp.Add(90 * time.Second)}
+			result, err = adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			Expect(hasSnapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotTimeInQueueAnnotation, "1m30s"))
+
+			laterPipelineRun := integrationPipelineRunComponent.DeepCopy()
+			laterPipelineRun.Annotations = map[string]string{
+				tekton.TestStatusNameAnnotation: integrationTestScenario.Name + "[ocp=4.16]",
+			}
+			laterPipelineRun.Status.StartTime = &metav1.Time{Time: hasSnapshot.CreationTimestamp.Add(5 * time.Minute)}
+			adapter.pipelineRun = laterPipelineRun
+			result, err = adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			timeInQueue, recorded := gitops.GetSnapshotTimeInQueue(hasSnapshot)
+			Expect(recorded).To(BeTrue())
+			Expect(timeInQueue).To(Equal(90 * time.Second))
+		})
+
+		It("ensures the results listed in the exportResults of the scenario are exported to snapshot", func() {
+			exportingScenario := integrationTestScenario.DeepCopy()
+			exportingScenario.Spec.ExportResults = []string{"CLUSTER_URL", "NODES"}
+			exportingPipelineRun := integrationPipelineRunComponent.DeepCopy()
+			exportingPipelineRun.Status.Results = []tektonv1.PipelineRunResult{
+				{Name: "CLUSTER_URL", Value: *tektonv1.NewStructuredValues("https://api.cluster.example.com:6443")},
+				{Name: "NODES", Value: *tektonv1.NewStructuredValues("node-1", "node-2")},
+				{Name: "KUBEADMIN_PASSWORD", Value: *tektonv1.NewStructuredValues("secret")},
+			}
+			adapter.pipelineRun = exportingPipelineRun
+			adapter.context = toolkit.GetMockedContext(adapter.context, []toolkit.MockData{
+				{
+					ContextKey: loader.GetScenarioContextKey,
+					Resource:   exportingScenario,
+				},
+			})
+
+			result, err := adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+			exportedResults, err := gitops.GetExportedResults(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exportedResults).To(Equal(map[string]map[string]string{
+				integrationTestScenario.Name: {
+					"CLUSTER_URL": "https://api.cluster.example.com:6443",
+					"NODES":       `["node-1","node-2"]`,
+				},
+			}))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
 		})
 
+		It("ensures the finished pipelineRun is recorded in the recent runs of the scenario", func() {
+			completionTime := integrationPipelineRunComponent.Status.CompletionTime
+			integrationPipelineRunComponent.Status.StartTime = &metav1.Time{Time: completionTime.Add(-5 * time.Minute)}
+
+			result, err := adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			result, err = adapter.EnsureScenarioRecentRunsRecorded()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+			scenario := &v1beta2.IntegrationTestScenario{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      integrationTestScenario.Name,
+				Namespace: integrationTestScenario.Namespace,
+			}, scenario)).To(Succeed())
+			Expect(scenario.Status.RecentRuns).To(HaveLen(1))
+			run := scenario.Status.RecentRuns[0]
+			Expect(run.Snapshot).To(Equal(hasSnapshot.Name))
+			Expect(run.PipelineRun).To(Equal(integrationPipelineRunComponent.Name))
+			Expect(run.Outcome).To(Equal(intgteststat.IntegrationTestStatusTestPassed.String()))
+			Expect(run.CompletionTime).NotTo(BeNil())
+			Expect(run.Duration).To(Equal(&metav1.Duration{Duration: 5 * time.Minute}))
+			Expect(scenario.Status.LastRunTime).To(Equal(run.CompletionTime))
+			Expect(scenario.Status.LastRunSnapshot).To(Equal(hasSnapshot.Name))
+			Expect(scenario.Status.LastRunOutcome).To(Equal(intgteststat.IntegrationTestStatusTestPassed.String()))
+			Expect(scenario.Status.LastRunPipelineRun).To(Equal(integrationPipelineRunComponent.Name))
+
+			// the run is recorded only once when the pipelineRun is reconciled again
+			result, err = adapter.EnsureScenarioRecentRunsRecorded()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      integrationTestScenario.Name,
+				Namespace: integrationTestScenario.Namespace,
+			}, scenario)).To(Succeed())
+			Expect(scenario.Status.RecentRuns).To(HaveLen(1))
+		})
+
+		It("ensures the last run recorded concurrently by a later pipelineRun isn't overwritten", func() {
+			scenario := &v1beta2.IntegrationTestScenario{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      integrationTestScenario.Name,
+				Namespace: integrationTestScenario.Namespace,
+			}, scenario)).To(Succeed())
+			patch := client.MergeFrom(scenario.DeepCopy())
+			laterTime := metav1.NewTime(integrationPipelineRunComponent.Status.CompletionTime.Add(time.Minute))
+			scenario.Status.LastRunTime = &laterTime
+			scenario.Status.LastRunSnapshot = "snapshot-concurrent"
+			scenario.Status.LastRunOutcome = intgteststat.IntegrationTestStatusTestFail.String()
+			scenario.Status.LastRunPipelineRun = "pipelinerun-concurrent"
+			Expect(k8sClient.Status().Patch(ctx, scenario, patch)).To(Succeed())
+
+			result, err := adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			result, err = adapter.EnsureScenarioRecentRunsRecorded()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      integrationTestScenario.Name,
+				Namespace: integrationTestScenario.Namespace,
+			}, scenario)).To(Succeed())
+			Expect(scenario.Status.RecentRuns).To(ContainElement(HaveField("PipelineRun", integrationPipelineRunComponent.Name)))
+			Expect(scenario.Status.LastRunPipelineRun).To(Equal("pipelinerun-concurrent"))
+			Expect(scenario.Status.LastRunSnapshot).To(Equal("snapshot-concurrent"))
+			Expect(scenario.Status.LastRunOutcome).To(Equal(intgteststat.IntegrationTestStatusTestFail.String()))
+		})
+
+		It("ensures the finished pipelineRun is cleaned up only after its TTL elapsed and its status was reported", func() {
+			DefaultPipelineRunTTL = time.Hour
+			defer func() { DefaultPipelineRunTTL = 0 }()
+
+			// the TTL didn't elapse yet
+			result, err := adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">", 59*time.Minute))
+
+			// the TTL elapsed but the status wasn't reported in the snapshot yet
+			integrationPipelineRunComponent.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			result, err = adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(time.Hour))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      integrationPipelineRunComponent.Name,
+				Namespace: integrationPipelineRunComponent.Namespace,
+			}, &tektonv1.PipelineRun{})).To(Succeed())
+
+			result, err = adapter.EnsureStatusReportedInSnapshot()
+			Expect(!result.CancelRequest && err == nil).To(BeTrue())
+			result, err = adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+			Expect(!result.CancelRequest && !result.RequeueRequest && err == nil).To(BeTrue())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{
+					Name:      integrationPipelineRunComponent.Name,
+					Namespace: integrationPipelineRunComponent.Namespace,
+				}, &tektonv1.PipelineRun{})
+				return k8serrors.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+
+		It("ensures the finished pipelineRun is kept when the pipelineRun TTL is disabled", func() {
+			integrationPipelineRunComponent.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			result, err := adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+			Expect(!result.CancelRequest && !result.RequeueRequest && err == nil).To(BeTrue())
+		})
+
+		DescribeTable("classifies the configuration errors of the failed pipelineRuns",
+			func(pipelineRunReason, taskRunReason, expectedReason string) {
+				pipelineRun := &tektonv1.PipelineRun{}
+				pipelineRun.Status.SetCondition(&apis.Condition{
+					Type:   apis.ConditionSucceeded,
+					Status: "False",
+					Reason: pipelineRunReason,
+				})
+				taskRunStatus := &tektonv1.TaskRunStatus{}
+				taskRunStatus.SetCondition(&apis.Condition{
+					Type:   apis.ConditionSucceeded,
+					Status: "False",
+					Reason: taskRunReason,
+				})
+				taskRuns := []*helpers.TaskRun{helpers.NewTaskRunFromTektonTaskRun("e2e-tests", taskRunStatus)}
+
+				reason, guidance := classifyConfigurationError(pipelineRun, taskRuns)
+				Expect(reason).To(Equal(expectedReason))
+				switch {
+				case expectedReason == "":
+					Expect(guidance).To(BeEmpty())
+				case expectedReason == taskRunReason:
+					Expect(guidance).To(Equal(configurationErrorGuidance[expectedReason] + " (task e2e-tests)"))
+				default:
+					Expect(guidance).To(Equal(configurationErrorGuidance[expectedReason]))
+				}
+			},
+			Entry("pipeline couldn't be resolved", "CouldntGetPipeline", "", "CouldntGetPipeline"),
+			Entry("task couldn't be resolved", "CouldntGetTask", "", "CouldntGetTask"),
+			Entry("pipeline validation failed", "PipelineValidationFailed", "", "PipelineValidationFailed"),
+			Entry("pipeline param missing", "ParameterMissing", "", "ParameterMissing"),
+			Entry("image of a task couldn't be pulled", "Failed", "TaskRunImagePullFailed", "TaskRunImagePullFailed"),
+			Entry("genuine test failure", "Failed", "Failed", ""),
+			Entry("timed out pipelineRun", "PipelineRunTimeout", "TaskRunTimeout", ""),
+		)
+
 		When("integration pipeline failed", func() {
 
 			BeforeEach(func() {
@@ -601,11 +855,225 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
 				Expect(err).ToNot(HaveOccurred())
 
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(detail.TestPipelineRunName).To(Equal(integrationPipelineRunComponentFailed.Name))
+				Expect(detail.Details).To(Equal("Integration test failed"))
+				Expect(detail.TimedOutAfter).To(BeEmpty())
+
+			})
+
+			It("ensures the test which couldn't run because of its configuration is marked as errored", func() {
+				integrationPipelineRunComponentFailed.Status.Conditions = v1.Conditions{
+					apis.Condition{
+						Reason:  tektonv1.PipelineRunReasonCouldntGetPipeline.String(),
+						Message: "Error retrieving pipeline for pipelinerun",
+						Status:  "False",
+						Type:    apis.ConditionSucceeded,
+					},
+				}
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestError))
+				Expect(detail.Details).To(Equal("Integration test couldn't run because of a configuration error (CouldntGetPipeline): " +
+					"the pipeline couldn't be resolved, check the resolverRef of the IntegrationTestScenario"))
+				Expect(detail.CompletionTime).NotTo(BeNil())
+			})
+
+			It("ensures the failed pipelineRun and its finalizer are kept for debugging until the bounded retention elapsed", func() {
+				DefaultPipelineRunTTL = time.Minute
+				defer func() { DefaultPipelineRunTTL = 0 }()
+
+				// the retention requested by the scenario is longer than the bound
+				integrationTestScenarioFailed.Annotations = map[string]string{gitops.DebugKeepAnnotation: "100h"}
+				Expect(k8sClient.Update(ctx, integrationTestScenarioFailed)).Should(Succeed())
+
+				controllerutil.AddFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)
+				integrationPipelineRunComponentFailed.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+				Expect(controllerutil.ContainsFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)).To(BeTrue())
+
+				result, err = adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueRequest).To(BeTrue())
+				Expect(result.RequeueDelay).To(BeNumerically("~", gitops.MaxDebugKeepDuration-time.Hour, time.Minute))
+				Expect(controllerutil.ContainsFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)).To(BeTrue())
+				Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name:      integrationPipelineRunComponentFailed.Name,
+					Namespace: integrationPipelineRunComponentFailed.Namespace,
+				}, &tektonv1.PipelineRun{})).To(Succeed())
+
+				// the retention elapsed, the finalizer is released and the normal cleanup resumes
+				DefaultPipelineRunTTL = 0
+				integrationPipelineRunComponentFailed.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-gitops.MaxDebugKeepDuration - time.Minute)}
+				result, err = adapter.EnsurePipelineRunIsCleanedUpAfterTTL()
+				Expect(!result.CancelRequest && !result.RequeueRequest && err == nil).To(BeTrue())
+				Expect(controllerutil.ContainsFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)).To(BeFalse())
+			})
+
+			It("ensures the invalid debug retention of the scenario is ignored", func() {
+				integrationTestScenarioFailed.Annotations = map[string]string{gitops.DebugKeepAnnotation: "forever"}
+				Expect(k8sClient.Update(ctx, integrationTestScenarioFailed)).Should(Succeed())
+
+				controllerutil.AddFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+				Expect(controllerutil.ContainsFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)).To(BeFalse())
+			})
+
+			It("ensures the infrastructure failure of the pipelineRun is classified in the test status details", func() {
+				oomKilledTaskRun := failedTaskRun.DeepCopy()
+				oomKilledTaskRun.ObjectMeta = metav1.ObjectMeta{
+					Name:      "test-taskrun-oomkilled",
+					Namespace: "default",
+				}
+				oomKilledTaskRun.Status = tektonv1.TaskRunStatus{}
+				Expect(k8sClient.Create(ctx, oomKilledTaskRun)).Should(Succeed())
+				defer func() {
+					err := k8sClient.Delete(ctx, oomKilledTaskRun)
+					Expect(err == nil || k8serrors.IsNotFound(err)).To(BeTrue())
+				}()
+				oomKilledTaskRun.Status = tektonv1.TaskRunStatus{
+					Status: v1.Status{
+						Conditions: v1.Conditions{
+							apis.Condition{
+								Reason: "Failed",
+								Status: "False",
+								Type:   apis.ConditionSucceeded,
+							},
+						},
+					},
+					TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+						StartTime:      &metav1.Time{Time: time.Now()},
+						CompletionTime: &metav1.Time{Time: time.Now().Add(time.Minute)},
+						PodName:        "test-taskrun-oomkilled-pod",
+						Steps: []tektonv1.StepState{
+							{
+								Name:      "run-tests",
+								Container: "step-run-tests",
+								ContainerState: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+								},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, oomKilledTaskRun)).Should(Succeed())
+
+				oomKilledPipelineRun := integrationPipelineRunComponentFailed.DeepCopy()
+				oomKilledPipelineRun.Status.ChildReferences = []tektonv1.ChildStatusReference{
+					{
+						Name:             oomKilledTaskRun.Name,
+						PipelineTaskName: "e2e-tests",
+					},
+				}
+				adapter.context = toolkit.GetMockedContext(adapter.context, []toolkit.MockData{
+					{
+						ContextKey: loader.AllTaskRunsWithMatchingPipelineRunLabelContextKey,
+						Resource:   []tektonv1.TaskRun{*oomKilledTaskRun},
+					},
+				})
+
+				status, detail, err := adapter.GetIntegrationPipelineRunStatus(adapter.context, adapter.client, oomKilledPipelineRun)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(detail).To(Equal("infrastructure failure: OOMKilled (step run-tests of task e2e-tests); Integration test failed"))
+			})
+
+			It("ensures the status of the finished pipelineRun deleted before it was recorded still lands in snapshot", func() {
+				Expect(controllerutil.AddFinalizer(integrationPipelineRunComponentFailed, helpers.IntegrationPipelineRunFinalizer)).To(BeTrue())
+				Expect(k8sClient.Update(ctx, integrationPipelineRunComponentFailed)).Should(Succeed())
+				Expect(k8sClient.Delete(ctx, integrationPipelineRunComponentFailed)).Should(Succeed())
+
+				// the finalizer keeps the deleted pipelineRun until its status is recorded
+				deletedPipelineRun := &tektonv1.PipelineRun{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Namespace: integrationPipelineRunComponentFailed.Namespace,
+					Name:      integrationPipelineRunComponentFailed.Name,
+				}, deletedPipelineRun)).To(Succeed())
+				Expect(deletedPipelineRun.GetDeletionTimestamp()).NotTo(BeNil())
+
+				adapter.pipelineRun = deletedPipelineRun
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).ToNot(HaveOccurred())
 				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
 				Expect(ok).To(BeTrue())
 				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
 				Expect(detail.TestPipelineRunName).To(Equal(integrationPipelineRunComponentFailed.Name))
 
+				// the finalizer was released once the status was recorded
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, types.NamespacedName{
+						Namespace: integrationPipelineRunComponentFailed.Namespace,
+						Name:      integrationPipelineRunComponentFailed.Name,
+					}, deletedPipelineRun)
+					return k8serrors.IsNotFound(err)
+				}, time.Second*10).Should(BeTrue())
+			})
+
+			It("ensures the log excerpt of the first failed step is recorded in the test status", func() {
+				adapter.podLogClient = &mockPodLogClient{logs: map[string]string{
+					"test-taskrun-fail-pod/step-run-tests": "--- FAIL: TestSomething\n",
+				}}
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(detail.FailedStepLog).To(Equal("<details>\n<summary>Logs of failed step <b>run-tests</b> in task <b>task1</b></summary>\n\n```\n--- FAIL: TestSomething\n```\n\n</details>"))
+			})
+
+			It("ensures test status in snapshot is updated to failed when the pod of the failed step is gone", func() {
+				adapter.podLogClient = &mockPodLogClient{logs: map[string]string{}}
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(detail.FailedStepLog).To(BeEmpty())
+			})
+
+			It("ensures test status in snapshot is updated to failed with the timeout of the pipelineRun", func() {
+				startTime := time.Now().Add(-2 * time.Hour)
+				integrationPipelineRunComponentFailed.Status.StartTime = &metav1.Time{Time: startTime}
+				integrationPipelineRunComponentFailed.Status.CompletionTime = &metav1.Time{Time: startTime.Add(2 * time.Hour)}
+				integrationPipelineRunComponentFailed.Status.SetCondition(&apis.Condition{
+					Reason:  tektonv1.PipelineRunReasonTimedOut.String(),
+					Status:  "False",
+					Type:    apis.ConditionSucceeded,
+					Message: "PipelineRun \"pipelinerun-component-sample-failed\" failed to finish within \"2h0m0s\"",
+				})
+				Expect(k8sClient.Status().Update(ctx, integrationPipelineRunComponentFailed)).Should(Succeed())
+
+				result, err := adapter.EnsureStatusReportedInSnapshot()
+				Expect(!result.CancelRequest && err == nil).To(BeTrue())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenarioFailed.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+				Expect(detail.Details).To(Equal("Integration test timed out after 2h0m0s"))
+				Expect(detail.TimedOutAfter).To(Equal("2h0m0s"))
 			})
 		})
 
@@ -903,7 +1371,7 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
 			Expect(detail.TestPipelineRunName).To(Equal(integrationPipelineRunComponentFailed.Name))
 
-			Expect(integrationPipelineRunComponentFailed.Finalizers).To(ContainElement(ContainSubstring("test.appstudio.openshift.io/pipelinerun")))
+			Expect(integrationPipelineRunComponentFailed.Finalizers).NotTo(ContainElement(ContainSubstring("test.appstudio.openshift.io/pipelinerun")))
 		})
 	})
 
@@ -1119,4 +1587,33 @@ var _ = Describe("Pipeline Adapter", Ordered, func() {
 			Expect(detail).To(ContainSubstring("Integration test passed"))
 		})
 	})
+
+	When("GetIntegrationPipelineRunStatus is called with a succeeded PLR with multiple TEST_OUTPUT results", func() {
+		BeforeEach(func() {
+			integrationPipelineRunComponent.Status.ChildReferences = []tektonv1.ChildStatusReference{
+				{
+					Name:             successfulTaskRun.Name,
+					PipelineTaskName: "task1",
+				},
+				{
+					Name:             failedTaskRun.Name,
+					PipelineTaskName: "task2",
+				},
+			}
+			adapter = NewAdapter(ctx, integrationPipelineRunComponent, hasApp, hasSnapshot, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllTaskRunsWithMatchingPipelineRunLabelContextKey,
+					Resource:   []tektonv1.TaskRun{*successfulTaskRun, *failedTaskRun},
+				},
+			})
+		})
+
+		It("ensures the test fails with the aggregated results naming the failing tasks", func() {
+			status, detail, err := adapter.GetIntegrationPipelineRunStatus(adapter.context, adapter.client, integrationPipelineRunComponent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+			Expect(detail).To(Equal("Integration test failed (FAILURE): failing tasks task2, 10 success(es), 1 failure(s), 0 warning(s)"))
+		})
+	})
 })
@@ -27,11 +27,14 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/loader"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
+	"github.com/konflux-ci/integration-service/status"
 	"github.com/konflux-ci/integration-service/tekton"
 	"github.com/konflux-ci/operator-toolkit/controller"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -40,8 +43,9 @@ import (
 // Reconciler reconciles an integration PipelineRun object
 type Reconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	PodLogClient status.PodLogClient
 }
 
 // NewIntegrationReconciler creates and returns a Reconciler.
@@ -57,11 +61,13 @@ func NewIntegrationReconciler(client client.Client, logger *logr.Logger, scheme
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=deploymenttargets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=environments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=environments/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=integrationtestscenarios/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch;create;update;patch;delete;deletecollection
 //+kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns/finalizers,verbs=update
 //+kubebuilder:rbac:groups=tekton.dev,resources=taskruns,verbs=get;list;watch
 //+kubebuilder:rbac:groups=tekton.dev,resources=taskruns/status,verbs=get
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications/finalizers,verbs=update
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=environments/finalizers,verbs=update
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications,verbs=get;list;watch
@@ -73,6 +79,7 @@ func NewIntegrationReconciler(client client.Client, logger *logr.Logger, scheme
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("pipelineRun", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
 	loader := loader.NewLoader()
 
 	pipelineRun := &tektonv1.PipelineRun{}
@@ -96,6 +103,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			if err := helpers.RemoveFinalizerFromPipelineRun(ctx, r.Client, logger, pipelineRun, helpers.IntegrationPipelineRunFinalizer); err != nil {
 				return ctrl.Result{}, err
 			}
+			// the test of a Snapshot deleted while it was running doesn't hold its slot of the queue anymore
+			if snapshotName, found := pipelineRun.GetLabels()[tekton.SnapshotNameLabel]; found {
+				pipelinerunqueue.DefaultQueue.Release(pipelinerunqueue.TestKey(pipelineRun.Namespace, snapshotName, tekton.GetTestStatusName(pipelineRun)))
+			}
 		}
 		return helpers.HandleLoaderError(logger, err, "Snapshot", "PipelineRun")
 	}
@@ -114,23 +125,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	logger = logger.WithApp(*application)
 
-	adapter := NewAdapter(ctx, pipelineRun, application, snapshot, logger, loader, r.Client)
+	adapterOpts := []AdapterOption{}
+	if r.PodLogClient != nil {
+		adapterOpts = append(adapterOpts, WithPodLogClient(r.PodLogClient))
+	}
+
+	adapter := NewAdapter(ctx, pipelineRun, application, snapshot, logger, loader, r.Client, adapterOpts...)
 
 	return controller.ReconcileHandler([]controller.Operation{
 		adapter.EnsureStatusReportedInSnapshot,
+		adapter.EnsureScenarioRecentRunsRecorded,
 		adapter.EnsureEphemeralEnvironmentsCleanedUp,
+		adapter.EnsurePipelineRunIsCleanedUpAfterTTL,
 	})
 }
 
 // AdapterInterface is an interface defining all the operations that should be defined in an Integration adapter.
 type AdapterInterface interface {
 	EnsureStatusReportedInSnapshot() (controller.OperationResult, error)
+	EnsureScenarioRecentRunsRecorded() (controller.OperationResult, error)
 	EnsureEphemeralEnvironmentsCleanedUp() (controller.OperationResult, error)
+	EnsurePipelineRunIsCleanedUpAfterTTL() (controller.OperationResult, error)
 }
 
 // SetupController creates a new Integration controller and adds it to the Manager.
 func SetupController(manager ctrl.Manager, log *logr.Logger) error {
-	return setupControllerWithManager(manager, NewIntegrationReconciler(manager.GetClient(), log, manager.GetScheme()))
+	reconciler := NewIntegrationReconciler(manager.GetClient(), log, manager.GetScheme())
+
+	// pod logs are not served by the controller-runtime client, a clientset is needed to fetch them
+	clientset, err := kubernetes.NewForConfig(manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler.PodLogClient = status.NewPodLogClient(clientset)
+
+	return setupControllerWithManager(manager, reconciler)
 }
 
 // setupCache indexes fields for each of the resources used in the pipeline adapter in those cases where filtering by
@@ -35,6 +35,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
+	"github.com/konflux-ci/integration-service/tekton"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -323,9 +325,17 @@ var _ = Describe("Integration PipelineController", func() {
 
 	It("Does not return an error if the snapshot cannot be found", func() {
 		controllerutil.AddFinalizer(integrationPipelineRun, helpers.IntegrationPipelineRunFinalizer)
+		integrationPipelineRun.Labels[tekton.ScenarioNameLabel] = "example-pass"
 		err := k8sClient.Update(ctx, integrationPipelineRun)
 		Expect(err).To(BeNil())
 
+		pipelinerunqueue.DefaultQueue = pipelinerunqueue.NewQueue(1)
+		DeferCleanup(func() {
+			pipelinerunqueue.DefaultQueue = nil
+		})
+		pipelinerunqueue.DefaultQueue.Track(pipelinerunqueue.TestKey(integrationPipelineRun.Namespace, hasSnapshot.Name, "example-pass"))
+		Expect(pipelinerunqueue.DefaultQueue.Running()).To(Equal(1))
+
 		err = k8sClient.Delete(ctx, hasSnapshot)
 		Eventually(func() bool {
 			err := k8sClient.Get(ctx, types.NamespacedName{
@@ -347,6 +357,8 @@ var _ = Describe("Integration PipelineController", func() {
 			}, integrationPipelineRun)
 			return err == nil && !controllerutil.ContainsFinalizer(integrationPipelineRun, helpers.IntegrationPipelineRunFinalizer)
 		}, time.Second*20).Should(BeTrue())
+		// the slot held by the test of the deleted snapshot is released
+		Expect(pipelinerunqueue.DefaultQueue.Running()).To(Equal(0))
 	})
 
 	When("pipelinerun has no component", func() {
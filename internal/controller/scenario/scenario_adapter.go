@@ -18,18 +18,36 @@ package scenario
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"reflect"
+	"time"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/gitops"
 	h "github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/loader"
+	"github.com/konflux-ci/integration-service/pkg/resolverref"
+	"github.com/konflux-ci/integration-service/pkg/schedule"
 	"github.com/konflux-ci/operator-toolkit/controller"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// ResolvedRefCheckEnvVar enables checking that the pipelines referenced by the IntegrationTestScenarios
+	// can be resolved when set to "true"
+	ResolvedRefCheckEnvVar = "SCENARIO_RESOLVED_REF_CHECK"
+
+	// ResolvedRefRecheckInterval is the interval after which the resolution of the pipeline referenced by
+	// an unchanged IntegrationTestScenario is checked again
+	ResolvedRefRecheckInterval = 24 * time.Hour
+)
+
 // Adapter holds the objects needed to reconcile a Release.
 type Adapter struct {
 	application *applicationapiv1alpha1.Application
@@ -38,11 +56,18 @@ type Adapter struct {
 	loader      loader.ObjectLoader
 	client      client.Client
 	context     context.Context
+	clock       clock.PassiveClock
+	refChecker  resolverref.Checker
 }
 
 // NewAdapter creates and returns an Adapter instance.
 func NewAdapter(context context.Context, application *applicationapiv1alpha1.Application, scenario *v1beta2.IntegrationTestScenario, logger h.IntegrationLogger, loader loader.ObjectLoader, client client.Client,
 ) *Adapter {
+	var refChecker resolverref.Checker
+	if os.Getenv(ResolvedRefCheckEnvVar) == "true" {
+		refChecker = resolverref.NewHTTPChecker()
+	}
+
 	return &Adapter{
 		application: application,
 		scenario:    scenario,
@@ -50,6 +75,8 @@ func NewAdapter(context context.Context, application *applicationapiv1alpha1.App
 		loader:      loader,
 		client:      client,
 		context:     context,
+		clock:       clock.RealClock{},
+		refChecker:  refChecker,
 	}
 }
 
@@ -112,6 +139,28 @@ func (a *Adapter) EnsureCreatedScenarioIsValid() (controller.OperationResult, er
 	return controller.ContinueProcessing()
 }
 
+// EnsureSuspendedConditionIsUpToDate is an operation that ensures the Suspended status condition
+// of the IntegrationTestScenario reflects whether the scenario is suspended
+func (a *Adapter) EnsureSuspendedConditionIsUpToDate() (controller.OperationResult, error) {
+	if a.scenario.DeletionTimestamp != nil {
+		return controller.ContinueProcessing()
+	}
+
+	patch := client.MergeFrom(a.scenario.DeepCopy())
+	if !h.SetScenarioSuspendedCondition(a.scenario) {
+		return controller.ContinueProcessing()
+	}
+	err := a.client.Status().Patch(a.context, a.scenario, patch)
+	if err != nil {
+		a.logger.Error(err, "Failed to update Scenario")
+		return controller.RequeueWithError(err)
+	}
+	a.logger.LogAuditEvent("IntegrationTestScenario Suspended condition updated", a.scenario, h.LogActionUpdate,
+		"suspend", a.scenario.Spec.Suspend)
+
+	return controller.ContinueProcessing()
+}
+
 // EnsureDeletedScenarioResourcesAreCleanedUp is an operation that ensures that all resources related to the
 // deleted IntegrationTestScenario are cleaned up.
 func (a *Adapter) EnsureDeletedScenarioResourcesAreCleanedUp() (controller.OperationResult, error) {
@@ -148,3 +197,108 @@ func (a *Adapter) EnsureDeletedScenarioResourcesAreCleanedUp() (controller.Opera
 	}
 	return controller.ContinueProcessing()
 }
+
+// EnsureResolvedRefIsChecked is an operation that ensures that the ResolvedRefValid condition of the
+// IntegrationTestScenario reflects whether the pipeline it references can be resolved. The check runs when
+// enabled by the SCENARIO_RESOLVED_REF_CHECK environment variable, it is repeated when the spec of the
+// IntegrationTestScenario changes and on the first reconciliation after the ResolvedRefRecheckInterval.
+func (a *Adapter) EnsureResolvedRefIsChecked() (controller.OperationResult, error) {
+	if a.refChecker == nil || a.scenario.DeletionTimestamp != nil {
+		return controller.ContinueProcessing()
+	}
+
+	now := a.clock.Now()
+	condition := meta.FindStatusCondition(a.scenario.Status.Conditions, h.IntegrationTestScenarioResolvedRefValid)
+	lastCheckTime := a.scenario.Status.LastResolvedRefCheckTime
+	if condition != nil && condition.ObservedGeneration == a.scenario.Generation &&
+		lastCheckTime != nil && now.Sub(lastCheckTime.Time) < ResolvedRefRecheckInterval {
+		return controller.ContinueProcessing()
+	}
+
+	result := a.refChecker.Check(a.context, a.scenario.Spec.ResolverRef)
+	patch := client.MergeFrom(a.scenario.DeepCopy())
+	h.SetScenarioResolvedRefCondition(a.scenario, result.Status, result.Reason, result.Message)
+	a.scenario.Status.LastResolvedRefCheckTime = &metav1.Time{Time: now}
+	err := a.client.Status().Patch(a.context, a.scenario, patch)
+	if err != nil {
+		a.logger.Error(err, "Failed to update the ResolvedRefValid condition of the Scenario")
+		return controller.RequeueWithError(err)
+	}
+	a.logger.LogAuditEvent("Checked the resolution of the IntegrationTestScenario pipeline", a.scenario, h.LogActionUpdate,
+		"status", result.Status, "reason", result.Reason, "message", result.Message)
+
+	return controller.ContinueProcessing()
+}
+
+// EnsureScheduledRunIsTriggered is an operation that ensures that the scheduled runs of the IntegrationTestScenario
+// are triggered against the latest push Snapshot of the Application. The ticks of the schedule missed while
+// the controller was down are not backfilled, a single run is triggered for all of them.
+func (a *Adapter) EnsureScheduledRunIsTriggered() (controller.OperationResult, error) {
+	if a.scenario.Spec.Schedule == "" || a.scenario.DeletionTimestamp != nil || a.application == nil {
+		return controller.ContinueProcessing()
+	}
+
+	cronSchedule, err := schedule.Parse(a.scenario.Spec.Schedule)
+	if err != nil {
+		a.logger.Error(err, "Failed to parse the schedule of the IntegrationTestScenario")
+		return controller.ContinueProcessing()
+	}
+
+	now := a.clock.Now()
+	lastScheduleTime := a.scenario.CreationTimestamp.Time
+	if a.scenario.Status.LastScheduleTime != nil {
+		lastScheduleTime = a.scenario.Status.LastScheduleTime.Time
+	}
+	nextScheduleTime := cronSchedule.Next(lastScheduleTime)
+	if nextScheduleTime.IsZero() {
+		return controller.ContinueProcessing()
+	}
+	if nextScheduleTime.After(now) {
+		return controller.RequeueAfter(nextScheduleTime.Sub(now), nil)
+	}
+
+	if a.scenario.Spec.Suspend {
+		a.logger.Info("IntegrationTestScenario is suspended, skipping its scheduled run")
+	} else if err = a.triggerScheduledRun(); err != nil {
+		a.logger.Error(err, "Failed to trigger the scheduled run of the IntegrationTestScenario")
+		return controller.RequeueWithError(err)
+	}
+
+	patch := client.MergeFrom(a.scenario.DeepCopy())
+	a.scenario.Status.LastScheduleTime = &metav1.Time{Time: now}
+	err = a.client.Status().Patch(a.context, a.scenario, patch)
+	if err != nil {
+		a.logger.Error(err, "Failed to update the last schedule time of the Scenario")
+		return controller.RequeueWithError(err)
+	}
+
+	nextScheduleTime = cronSchedule.Next(now)
+	if nextScheduleTime.IsZero() {
+		return controller.ContinueProcessing()
+	}
+	return controller.RequeueAfter(nextScheduleTime.Sub(now), nil)
+}
+
+// triggerScheduledRun requests the run of the IntegrationTestScenario for the latest push Snapshot of the Application
+func (a *Adapter) triggerScheduledRun() error {
+	snapshots, err := a.loader.GetAllSnapshots(a.context, a.client, a.application)
+	if err != nil {
+		return fmt.Errorf("failed to get the snapshots of application %s: %w", a.application.Name, err)
+	}
+	snapshot := gitops.GetLatestPushSnapshot(*snapshots)
+	if snapshot == nil {
+		a.logger.Info("No push Snapshot of the Application found, skipping the scheduled run of the IntegrationTestScenario")
+		return nil
+	}
+
+	if scenarioName, ok := gitops.GetIntegrationTestRunLabelValue(snapshot); ok && scenarioName != a.scenario.Name {
+		return fmt.Errorf("snapshot %s has a pending run of scenario %s", snapshot.Name, scenarioName)
+	}
+	if err = gitops.AddScheduledIntegrationTestRunLabel(a.context, a.client, snapshot, a.scenario.Name); err != nil {
+		return err
+	}
+	a.logger.LogAuditEvent("Triggered the scheduled run of the IntegrationTestScenario", snapshot, h.LogActionUpdate,
+		"integrationTestScenario.Name", a.scenario.Name)
+
+	return nil
+}
@@ -18,11 +18,17 @@ package scenario
 
 import (
 	"bytes"
+	"context"
 	"reflect"
 	"time"
 
 	"github.com/konflux-ci/integration-service/loader"
+	"github.com/konflux-ci/integration-service/pkg/resolverref"
+	toolkit "github.com/konflux-ci/operator-toolkit/loader"
 	"github.com/tonglil/buflogr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -39,6 +45,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// fakeRefChecker returns the configured result of the check and counts the checks
+type fakeRefChecker struct {
+	result resolverref.Result
+	checks int
+}
+
+func (c *fakeRefChecker) Check(ctx context.Context, resolverRef v1beta2.ResolverRef) resolverref.Result {
+	c.checks++
+	return c.result
+}
+
 var _ = Describe("Scenario Adapter", Ordered, func() {
 	const (
 		DefaultNamespace = "default"
@@ -199,6 +216,21 @@ var _ = Describe("Scenario Adapter", Ordered, func() {
 
 	})
 
+	It("ensures the Suspended condition of the scenario is kept up to date", func() {
+		suspendedScenario := integrationTestScenario.DeepCopy()
+		suspendedScenario.Spec.Suspend = true
+		a := NewAdapter(ctx, hasApp, suspendedScenario, logger, loader.NewMockLoader(), k8sClient)
+
+		result, err := a.EnsureSuspendedConditionIsUpToDate()
+		Expect(!result.CancelRequest && err == nil).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(suspendedScenario.Status.Conditions, helpers.IntegrationTestScenarioSuspended)).To(BeTrue())
+
+		suspendedScenario.Spec.Suspend = false
+		result, err = a.EnsureSuspendedConditionIsUpToDate()
+		Expect(!result.CancelRequest && err == nil).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(suspendedScenario.Status.Conditions, helpers.IntegrationTestScenarioSuspended)).To(BeTrue())
+	})
+
 	It("ensures the integrationTestPipelines are created", func() {
 		Eventually(func() bool {
 			result, err := adapter.EnsureCreatedScenarioIsValid()
@@ -206,6 +238,175 @@ var _ = Describe("Scenario Adapter", Ordered, func() {
 		}, time.Second*20).Should(BeTrue())
 	})
 
+	When("the resolution of the pipeline referenced by the IntegrationTestScenario is checked", func() {
+		var (
+			checkedScenario *v1beta2.IntegrationTestScenario
+			refChecker      *fakeRefChecker
+			fakeClock       *clocktesting.FakePassiveClock
+			checkAdapter    *Adapter
+		)
+
+		BeforeEach(func() {
+			checkedScenario = integrationTestScenario.DeepCopy()
+			checkedScenario.ObjectMeta = metav1.ObjectMeta{
+				Name:      "example-checked",
+				Namespace: "default",
+			}
+			Expect(k8sClient.Create(ctx, checkedScenario)).Should(Succeed())
+
+			refChecker = &fakeRefChecker{result: resolverref.Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  resolverref.ReasonNotFound,
+				Message: "The file pipelineruns/integration_pipelinerun_pass.yaml was not found.",
+			}}
+			fakeClock = clocktesting.NewFakePassiveClock(time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC))
+			checkAdapter = NewAdapter(ctx, hasApp, checkedScenario, logger, loader.NewMockLoader(), k8sClient)
+			checkAdapter.clock = fakeClock
+			checkAdapter.refChecker = refChecker
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(ctx, checkedScenario)
+			Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("sets the ResolvedRefValid condition according to the result of the check", func() {
+			result, err := checkAdapter.EnsureResolvedRefIsChecked()
+			Expect(!result.CancelRequest && !result.RequeueRequest && err == nil).To(BeTrue())
+			Expect(refChecker.checks).To(Equal(1))
+
+			condition := meta.FindStatusCondition(checkedScenario.Status.Conditions, helpers.IntegrationTestScenarioResolvedRefValid)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(resolverref.ReasonNotFound))
+			Expect(condition.ObservedGeneration).To(Equal(checkedScenario.Generation))
+			Expect(checkedScenario.Status.LastResolvedRefCheckTime.Time.Equal(fakeClock.Now())).To(BeTrue())
+		})
+
+		It("checks the resolution again only after the spec changes or the recheck interval passes", func() {
+			_, err := checkAdapter.EnsureResolvedRefIsChecked()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refChecker.checks).To(Equal(1))
+
+			fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+			_, err = checkAdapter.EnsureResolvedRefIsChecked()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refChecker.checks).To(Equal(1))
+
+			refChecker.result = resolverref.Result{Status: metav1.ConditionUnknown, Reason: resolverref.ReasonUnavailable, Message: "network failure"}
+			fakeClock.SetTime(fakeClock.Now().Add(ResolvedRefRecheckInterval))
+			_, err = checkAdapter.EnsureResolvedRefIsChecked()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refChecker.checks).To(Equal(2))
+			Expect(meta.FindStatusCondition(checkedScenario.Status.Conditions, helpers.IntegrationTestScenarioResolvedRefValid).Status).
+				To(Equal(metav1.ConditionUnknown))
+
+			checkedScenario.Generation++
+			_, err = checkAdapter.EnsureResolvedRefIsChecked()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refChecker.checks).To(Equal(3))
+		})
+
+		It("doesn't check the resolution when the check isn't enabled", func() {
+			a := NewAdapter(ctx, hasApp, checkedScenario, logger, loader.NewMockLoader(), k8sClient)
+			Expect(a.refChecker).To(BeNil())
+			result, err := a.EnsureResolvedRefIsChecked()
+			Expect(!result.CancelRequest && !result.RequeueRequest && err == nil).To(BeTrue())
+			Expect(meta.FindStatusCondition(checkedScenario.Status.Conditions, helpers.IntegrationTestScenarioResolvedRefValid)).To(BeNil())
+		})
+	})
+
+	When("IntegrationTestScenario has a schedule", func() {
+		var (
+			scheduledScenario *v1beta2.IntegrationTestScenario
+			pushSnapshot      *applicationapiv1alpha1.Snapshot
+			fakeClock         *clocktesting.FakePassiveClock
+			scheduledAdapter  *Adapter
+		)
+
+		BeforeEach(func() {
+			scheduledScenario = integrationTestScenario.DeepCopy()
+			scheduledScenario.ObjectMeta = metav1.ObjectMeta{
+				Name:      "example-scheduled",
+				Namespace: "default",
+			}
+			scheduledScenario.Spec.Schedule = "0 2 * * *"
+			Expect(k8sClient.Create(ctx, scheduledScenario)).Should(Succeed())
+
+			pushSnapshot = &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-push-sample",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotComponentType,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePushType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{
+							Name:           "component-sample",
+							ContainerImage: "quay.io/redhat-appstudio/sample-image@sha256:841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40227c1",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pushSnapshot)).Should(Succeed())
+
+			fakeClock = clocktesting.NewFakePassiveClock(time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC))
+			scheduledAdapter = NewAdapter(ctx, hasApp, scheduledScenario, logger, loader.NewMockLoader(), k8sClient)
+			scheduledAdapter.clock = fakeClock
+			scheduledAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllSnapshotsContextKey,
+					Resource:   []applicationapiv1alpha1.Snapshot{*pushSnapshot},
+				},
+			})
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(ctx, scheduledScenario)
+			Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Delete(ctx, pushSnapshot)
+			Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("waits for the next tick of the schedule", func() {
+			scheduledScenario.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2024, time.March, 15, 2, 0, 0, 0, time.UTC)}
+
+			result, err := scheduledAdapter.EnsureScheduledRunIsTriggered()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(15*time.Hour + 30*time.Minute))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: pushSnapshot.Namespace, Name: pushSnapshot.Name}, pushSnapshot)).To(Succeed())
+			Expect(pushSnapshot.Labels).NotTo(HaveKey(gitops.SnapshotIntegrationTestRun))
+		})
+
+		It("triggers a single run against the latest push snapshot for the missed ticks", func() {
+			scheduledScenario.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2024, time.March, 12, 2, 0, 0, 0, time.UTC)}
+
+			result, err := scheduledAdapter.EnsureScheduledRunIsTriggered()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(15*time.Hour + 30*time.Minute))
+			Expect(scheduledScenario.Status.LastScheduleTime.Time.Equal(fakeClock.Now())).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: pushSnapshot.Namespace, Name: pushSnapshot.Name}, pushSnapshot)).To(Succeed())
+			Expect(pushSnapshot.Labels).To(HaveKeyWithValue(gitops.SnapshotIntegrationTestRun, scheduledScenario.Name))
+			Expect(gitops.IsScheduledIntegrationTestRun(pushSnapshot, scheduledScenario.Name)).To(BeTrue())
+
+			// the missed ticks are not backfilled
+			Expect(gitops.RemoveIntegrationTestRerunLabel(ctx, k8sClient, pushSnapshot)).To(Succeed())
+			result, err = scheduledAdapter.EnsureScheduledRunIsTriggered()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueDelay).To(Equal(15*time.Hour + 30*time.Minute))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: pushSnapshot.Namespace, Name: pushSnapshot.Name}, pushSnapshot)).To(Succeed())
+			Expect(pushSnapshot.Labels).NotTo(HaveKey(gitops.SnapshotIntegrationTestRun))
+		})
+	})
+
 	When("IntegrationTestScenario is deleted while environment resources are still on the cluster", func() {
 		var (
 			ephemeralEnvironment  *applicationapiv1alpha1.Environment
@@ -38,6 +38,8 @@ type Reconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// APIReader lists the Snapshots of an Application from the API server in pages, if set
+	APIReader client.Reader
 }
 
 // NewScenarioReconciler creates and returns a Reconciler.
@@ -55,11 +57,16 @@ func NewScenarioReconciler(client client.Client, logger *logr.Logger, scheme *ru
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=environments/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications/status,verbs=get
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=snapshots,verbs=get;list;watch;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("integrationTestScenario", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
+	if r.APIReader != nil {
+		ctx = loader.WithAPIReader(ctx, r.APIReader)
+	}
 	loader := loader.NewLoader()
 
 	scenario := &v1beta2.IntegrationTestScenario{}
@@ -82,7 +89,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	return controller.ReconcileHandler([]controller.Operation{
 		adapter.EnsureCreatedScenarioIsValid,
+		adapter.EnsureSuspendedConditionIsUpToDate,
 		adapter.EnsureDeletedScenarioResourcesAreCleanedUp,
+		adapter.EnsureResolvedRefIsChecked,
+		adapter.EnsureScheduledRunIsTriggered,
 	})
 }
 
@@ -105,12 +115,17 @@ func (r *Reconciler) getApplicationFromScenario(context context.Context, scenari
 // AdapterInterface is an interface defining all the operations that should be defined in an Integration adapter.
 type AdapterInterface interface {
 	EnsureCreatedScenarioIsValid() (controller.OperationResult, error)
+	EnsureSuspendedConditionIsUpToDate() (controller.OperationResult, error)
 	EnsureDeletedScenarioResourcesAreCleanedUp() (controller.OperationResult, error)
+	EnsureResolvedRefIsChecked() (controller.OperationResult, error)
+	EnsureScheduledRunIsTriggered() (controller.OperationResult, error)
 }
 
 // SetupController creates a new Integration controller and adds it to the Manager.
 func SetupController(manager ctrl.Manager, log *logr.Logger) error {
-	return setupControllerWithManager(manager, NewScenarioReconciler(manager.GetClient(), log, manager.GetScheme()))
+	reconciler := NewScenarioReconciler(manager.GetClient(), log, manager.GetScheme())
+	reconciler.APIReader = manager.GetAPIReader()
+	return setupControllerWithManager(manager, reconciler)
 }
 
 func setupControllerWithManager(manager ctrl.Manager, controller *Reconciler) error {
@@ -21,11 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	clienterrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
@@ -33,6 +35,7 @@ import (
 	h "github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/metrics"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
 	"github.com/konflux-ci/integration-service/release"
 	"github.com/konflux-ci/integration-service/tekton"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
@@ -41,12 +44,55 @@ import (
 	"github.com/konflux-ci/operator-toolkit/controller"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	releasev1alpha1 "github.com/konflux-ci/release-service/api/v1alpha1"
+	releasemetadata "github.com/konflux-ci/release-service/metadata"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const SnapshotRetryTimeout = time.Duration(3 * time.Hour)
 
+// ScenarioDependenciesRequeueDelay is the delay after which the snapshot is reconciled again
+// to re-evaluate the scenarios waiting for their dependencies to pass
+const ScenarioDependenciesRequeueDelay = time.Duration(30 * time.Second)
+
+// PipelineRunQueueRequeueDelay is the delay after which the snapshot is reconciled again
+// to retry the creation of the pipelineRuns queued because of the cap on running integration pipelineRuns
+const PipelineRunQueueRequeueDelay = time.Duration(15 * time.Second)
+
+// PipelineRunCreationStagger is the interval between the creation of the batches of integration pipelineRuns
+// of the Snapshot, so the resolvers and the registry aren't hit by all of them at once. Zero disables the stagger.
+var PipelineRunCreationStagger = time.Duration(2 * time.Second)
+
+// PipelineRunCreationBatchSize is the number of integration pipelineRuns of the Snapshot created at once,
+// the creation of the rest is staggered by PipelineRunCreationStagger
+var PipelineRunCreationBatchSize = 5
+
+// PipelineRunCreationRetryBaseDelay is the delay after which the creation of an integration pipelineRun is retried
+// after its first transient failure, the delay doubles with each consecutive failure
+const PipelineRunCreationRetryBaseDelay = time.Duration(10 * time.Second)
+
+// PipelineRunCreationRetryMaxDelay caps the delay between the retries of the creation of an integration pipelineRun
+const PipelineRunCreationRetryMaxDelay = time.Duration(5 * time.Minute)
+
+// PipelineRunCreationMaxFailures is the number of consecutive transient failures after which the creation
+// of an integration pipelineRun isn't retried anymore and the test is marked as invalid
+const PipelineRunCreationMaxFailures = 10
+
+// scenarioDependenciesState describes whether the IntegrationTestScenarios a scenario depends on have passed
+type scenarioDependenciesState int
+
+const (
+	// scenarioDependenciesPassed is the state when all dependencies of the scenario have passed
+	scenarioDependenciesPassed scenarioDependenciesState = iota
+	// scenarioDependenciesPending is the state when some dependencies of the scenario haven't finished yet
+	scenarioDependenciesPending
+	// scenarioDependenciesFailed is the state when some dependency of the scenario can't pass anymore
+	scenarioDependenciesFailed
+	// scenarioDependenciesSkipped is the state when some dependency of the scenario was skipped and none failed
+	scenarioDependenciesSkipped
+)
+
 // configuration options for scenario
 type ScenarioOptions struct {
 	IsReRun bool
@@ -61,6 +107,7 @@ type Adapter struct {
 	loader      loader.ObjectLoader
 	client      client.Client
 	context     context.Context
+	clock       clock.PassiveClock
 }
 
 // NewAdapter creates and returns an Adapter instance.
@@ -74,16 +121,338 @@ func NewAdapter(context context.Context, snapshot *applicationapiv1alpha1.Snapsh
 		loader:      loader,
 		client:      client,
 		context:     context,
+		clock:       clock.RealClock{},
+	}
+}
+
+// getScenarioDependenciesState returns the state of the dependencies of the given scenario according to the test statuses
+// of the snapshot, together with the details describing the state. The dependencies with a matrix pass when all
+// tests of their matrix combinations passed. A failed dependency takes precedence over the skipped and pending ones.
+func getScenarioDependenciesState(integrationTestScenario *v1beta2.IntegrationTestScenario, integrationTestScenarios *[]v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) (scenarioDependenciesState, string) {
+	var pendingDependencies []string
+	skippedDependency := ""
+	for _, dependency := range integrationTestScenario.Spec.DependsOn {
+		dependencyIndex := slices.IndexFunc(*integrationTestScenarios, func(scenario v1beta2.IntegrationTestScenario) bool {
+			return scenario.Name == dependency
+		})
+		if dependencyIndex < 0 {
+			return scenarioDependenciesFailed, fmt.Sprintf("IntegrationTestScenario '%s' can't run because it depends on IntegrationTestScenario '%s' which doesn't exist",
+				integrationTestScenario.Name, dependency)
+		}
+		dependencyPending := false
+		for _, dependencyTest := range h.GetScenarioTests(&(*integrationTestScenarios)[dependencyIndex]) {
+			dependencyStatus, ok := testStatuses.GetScenarioStatus(dependencyTest.Name)
+			if !ok || !dependencyStatus.Status.IsFinal() {
+				dependencyPending = true
+				continue
+			}
+			if dependencyStatus.Status == intgteststat.IntegrationTestStatusSkipped {
+				if skippedDependency == "" {
+					skippedDependency = dependencyTest.Name
+				}
+				continue
+			}
+			if dependencyStatus.Status != intgteststat.IntegrationTestStatusTestPassed {
+				return scenarioDependenciesFailed, fmt.Sprintf("IntegrationTestScenario '%s' can't run because IntegrationTestScenario '%s' it depends on didn't pass (%s)",
+					integrationTestScenario.Name, dependencyTest.Name, dependencyStatus.Status)
+			}
+		}
+		if dependencyPending {
+			pendingDependencies = append(pendingDependencies, dependency)
+		}
+	}
+
+	if len(pendingDependencies) > 0 {
+		return scenarioDependenciesPending, fmt.Sprintf("IntegrationTestScenario '%s' is waiting for IntegrationTestScenarios '%s' to pass",
+			integrationTestScenario.Name, strings.Join(pendingDependencies, "', '"))
+	}
+	if skippedDependency != "" {
+		return scenarioDependenciesSkipped, fmt.Sprintf("IntegrationTestScenario '%s' was skipped because IntegrationTestScenario '%s' it depends on was skipped",
+			integrationTestScenario.Name, skippedDependency)
+	}
+	return scenarioDependenciesPassed, ""
+}
+
+// getUntriggeredScenarioTests returns the tests of the scenario which have no integration pipelineRun registered
+// in the test statuses of the snapshot
+func getUntriggeredScenarioTests(scenarioTests []h.ScenarioTest, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) []h.ScenarioTest {
+	var untriggeredTests []h.ScenarioTest
+	for _, scenarioTest := range scenarioTests {
+		testStatus, ok := testStatuses.GetScenarioStatus(scenarioTest.Name)
+		if !ok || testStatus.TestPipelineRunName == "" {
+			untriggeredTests = append(untriggeredTests, scenarioTest)
+		}
+	}
+	return untriggeredTests
+}
+
+// getPipelineRunQueuedDetails returns the details of the tests of the scenario waiting for a free slot of the cap
+// on running integration pipelineRuns
+func getPipelineRunQueuedDetails(scenarioName string) string {
+	return fmt.Sprintf("IntegrationTestScenario '%s' is queued until the number of running integration pipelineRuns drops below the cap", scenarioName)
+}
+
+// hasUntriggeredTestsOnly returns true if some tests of the given scenarios which haven't finished have no pipelineRun
+// created yet and none of the tests of the Snapshot is in progress
+func (a *Adapter) hasUntriggeredTestsOnly(integrationTestScenarios *[]v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) bool {
+	for _, testStatus := range testStatuses.GetStatuses() {
+		if testStatus.Status == intgteststat.IntegrationTestStatusInProgress {
+			return false
+		}
+	}
+	for _, integrationTestScenario := range *integrationTestScenarios {
+		integrationTestScenario := integrationTestScenario //G601
+		for _, scenarioTest := range getUntriggeredScenarioTests(h.GetScenarioTests(&integrationTestScenario), testStatuses) {
+			if testStatus, ok := testStatuses.GetScenarioStatus(scenarioTest.Name); !ok || !testStatus.Status.IsFinal() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getMissingSnapshotComponents returns the names of the components of the Snapshot which no longer exist,
+// the components missing only optional data like their last built commit are resolved fine
+func (a *Adapter) getMissingSnapshotComponents() ([]string, error) {
+	var missingComponents []string
+	for _, snapshotComponent := range a.snapshot.Spec.Components {
+		_, err := a.loader.GetComponent(a.context, a.client, snapshotComponent.Name, a.snapshot.Namespace)
+		if err != nil {
+			if loader.IsResourceNotFound(err) {
+				missingComponents = append(missingComponents, snapshotComponent.Name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get the component %s: %w", snapshotComponent.Name, err)
+		}
+	}
+	return missingComponents, nil
+}
+
+// invalidateSnapshotWithMissingComponents reports the tests which haven't started as errors explaining which components
+// of the Snapshot no longer exist and marks the Snapshot as invalid, the pipelineRuns aren't created for it
+func (a *Adapter) invalidateSnapshotWithMissingComponents(missingComponents []string, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) (controller.OperationResult, error) {
+	message := fmt.Sprintf("The components %s of the snapshot no longer exist, try again after recreating them",
+		strings.Join(missingComponents, ", "))
+	a.logger.Info("Components of the Snapshot no longer exist, will not create pipelineRuns for it",
+		"missingComponents", missingComponents)
+
+	for _, testStatus := range testStatuses.GetStatuses() {
+		if testStatus.Status.IsFinal() || testStatus.TestPipelineRunName != "" {
+			continue
+		}
+		testStatuses.UpdateTestStatusIfChanged(testStatus.ScenarioName, intgteststat.IntegrationTestStatusTestError, message)
+	}
+	err := gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client)
+	if err != nil {
+		a.logger.Error(err, "Failed to update test status in snapshot annotation")
+		return controller.RequeueWithError(err)
+	}
+
+	if !gitops.IsSnapshotMarkedAsInvalid(a.snapshot) {
+		err = gitops.MarkSnapshotAsInvalid(a.context, a.client, a.snapshot, message)
+		if err != nil {
+			a.logger.Error(err, "Failed to update the status to Invalid for the snapshot")
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Snapshot integration status marked as Invalid. Components of the Snapshot no longer exist",
+			a.snapshot, h.LogActionUpdate, "missingComponents", missingComponents)
+	}
+
+	return controller.ContinueProcessing()
+}
+
+// getSnapshotWithReusableTestResults returns the earlier Snapshot with identical components and final test results
+// together with its test statuses when the Application opted in to reuse them, nil is returned otherwise.
+// Finding the Snapshot is best effort, the tests run fresh when it fails
+func (a *Adapter) getSnapshotWithReusableTestResults() (*applicationapiv1alpha1.Snapshot, *intgteststat.SnapshotIntegrationTestStatuses) {
+	if !gitops.IsTestResultsReuseEnabled(a.application) {
+		return nil, nil
+	}
+
+	allSnapshots, err := a.loader.GetAllSnapshots(a.context, a.client, a.application)
+	if err != nil {
+		a.logger.Error(err, "Failed to get the Snapshots of the application, the test results won't be reused")
+		return nil, nil
+	}
+	reusedSnapshot := gitops.FindSnapshotWithReusableTestResults(a.application, allSnapshots, a.snapshot)
+	if reusedSnapshot == nil {
+		return nil, nil
+	}
+	reusedTestStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(reusedSnapshot)
+	if err != nil {
+		a.logger.Error(err, "Failed to get the test statuses of the Snapshot with identical components, the test results won't be reused",
+			"reusedSnapshot.Name", reusedSnapshot.Name)
+		return nil, nil
+	}
+
+	return reusedSnapshot, reusedTestStatuses
+}
+
+// reuseTestResult copies the final result of the test from the Snapshot with identical components into the test statuses,
+// the failed results of the required scenarios aren't reused so they run fresh. It returns true when the result was reused
+func (a *Adapter) reuseTestResult(integrationTestScenario *v1beta2.IntegrationTestScenario, testName string,
+	testStatuses *intgteststat.SnapshotIntegrationTestStatuses, reusedSnapshot *applicationapiv1alpha1.Snapshot,
+	reusedTestStatuses *intgteststat.SnapshotIntegrationTestStatuses) bool {
+	if reusedSnapshot == nil {
+		return false
+	}
+	reusedTestStatus, ok := reusedTestStatuses.GetScenarioStatus(testName)
+	if !ok || reusedTestStatus.TestPipelineRunName == "" {
+		return false
+	}
+	// the result of a changed scenario, e.g. with another pipeline or params, isn't reused
+	if reusedTestStatus.ScenarioGeneration == 0 || reusedTestStatus.ScenarioGeneration != integrationTestScenario.Generation {
+		a.logger.Info("The IntegrationTestScenario changed since the test of the Snapshot with identical components, not reusing its result",
+			"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", testName, "reusedSnapshot.Name", reusedSnapshot.Name)
+		return false
+	}
+	switch reusedTestStatus.Status {
+	case intgteststat.IntegrationTestStatusTestPassed:
+	case intgteststat.IntegrationTestStatusTestFail:
+		if !metadata.HasLabelWithValue(integrationTestScenario, tekton.OptionalLabel, "true") {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if err := testStatuses.ReuseTestResult(testName, reusedTestStatus, reusedSnapshot.Name); err != nil {
+		a.logger.Error(err, "Failed to reuse the test result of the Snapshot with identical components",
+			"test.Name", testName, "reusedSnapshot.Name", reusedSnapshot.Name)
+		return false
+	}
+	a.logger.Info("Reused the test result of the Snapshot with identical components, will not create pipelineRun for it",
+		"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", testName,
+		"reusedSnapshot.Name", reusedSnapshot.Name, "test.Status", reusedTestStatus.Status)
+	return true
+}
+
+// EnsurePullRequestSnapshotsObsoletedByOverride is an operation that will ensure that the pull request Snapshots
+// of the components of an override Snapshot, which were created before it and haven't finished testing, are obsoleted
+// and their running integration pipelineRuns are canceled
+func (a *Adapter) EnsurePullRequestSnapshotsObsoletedByOverride() (controller.OperationResult, error) {
+	if !gitops.IsOverrideSnapshot(a.snapshot) {
+		return controller.ContinueProcessing()
+	}
+
+	for _, snapshotComponent := range a.snapshot.Spec.Components {
+		pullRequestSnapshots, err := a.loader.GetAllPullRequestSnapshotsForComponent(a.context, a.client, a.snapshot.Namespace, snapshotComponent.Name)
+		if err != nil {
+			a.logger.Error(err, "Failed to get the pull request Snapshots of the component", "component.Name", snapshotComponent.Name)
+			return controller.RequeueWithError(err)
+		}
+
+		for _, pullRequestSnapshot := range *pullRequestSnapshots {
+			pullRequestSnapshot := pullRequestSnapshot // G601
+			if pullRequestSnapshot.Spec.Application != a.snapshot.Spec.Application ||
+				a.snapshot.CreationTimestamp.Before(&pullRequestSnapshot.CreationTimestamp) {
+				continue
+			}
+			// the snapshots obsoleted by this override are processed again, in case cancelling their pipelineRuns failed
+			obsoletedByOverride := pullRequestSnapshot.GetAnnotations()[gitops.SnapshotObsoletedByOverrideAnnotation] == a.snapshot.Name
+			if !obsoletedByOverride && (gitops.AreSnapshotTestsCanceled(&pullRequestSnapshot) || gitops.HaveAppStudioTestsFinished(&pullRequestSnapshot)) {
+				continue
+			}
+
+			err = a.obsoleteSnapshotByOverride(&pullRequestSnapshot, !obsoletedByOverride)
+			if err != nil {
+				a.logger.Error(err, "Failed to obsolete the pull request Snapshot", "obsoletedSnapshot.Name", pullRequestSnapshot.Name)
+				return controller.RequeueWithError(err)
+			}
+		}
+	}
+
+	return controller.ContinueProcessing()
+}
+
+// obsoleteSnapshotByOverride marks the given pull request Snapshot as obsoleted by the override Snapshot,
+// which cancels its unfinished tests, and cancels the pipelineRuns of the canceled tests which are still running
+func (a *Adapter) obsoleteSnapshotByOverride(snapshot *applicationapiv1alpha1.Snapshot, mark bool) error {
+	if mark {
+		err := gitops.MarkSnapshotAsObsoletedByOverride(a.context, a.client, snapshot, a.snapshot.Name)
+		if err != nil {
+			return err
+		}
+		a.logger.LogAuditEvent("Snapshot was obsoleted by the override Snapshot, its unfinished tests were canceled",
+			snapshot, h.LogActionUpdate, "override.Snapshot.Name", a.snapshot.Name)
+	}
+
+	return a.cancelPipelineRunsOfCanceledTests(snapshot)
+}
+
+// EnsureOlderPullRequestSnapshotsSuperseded is an operation that will ensure that the Snapshots created for earlier
+// commits of the same pull request and component, which haven't finished testing, are superseded by the Snapshot
+// and their running integration pipelineRuns are canceled
+func (a *Adapter) EnsureOlderPullRequestSnapshotsSuperseded() (controller.OperationResult, error) {
+	pullRequest, found := a.snapshot.GetAnnotations()[gitops.PipelineAsCodePullRequestAnnotation]
+	if !found || a.component == nil || gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) || gitops.IsOverrideSnapshot(a.snapshot) ||
+		gitops.AreSnapshotTestsCanceled(a.snapshot) || gitops.HaveAppStudioTestsFinished(a.snapshot) {
+		return controller.ContinueProcessing()
 	}
+
+	pullRequestSnapshots, err := a.loader.GetAllPullRequestSnapshotsForComponent(a.context, a.client, a.snapshot.Namespace, a.component.Name)
+	if err != nil {
+		a.logger.Error(err, "Failed to get the pull request Snapshots of the component", "component.Name", a.component.Name)
+		return controller.RequeueWithError(err)
+	}
+
+	for _, pullRequestSnapshot := range *pullRequestSnapshots {
+		pullRequestSnapshot := pullRequestSnapshot // G601
+		if pullRequestSnapshot.Name == a.snapshot.Name || pullRequestSnapshot.Spec.Application != a.snapshot.Spec.Application ||
+			pullRequestSnapshot.GetAnnotations()[gitops.PipelineAsCodePullRequestAnnotation] != pullRequest ||
+			!pullRequestSnapshot.CreationTimestamp.Before(&a.snapshot.CreationTimestamp) {
+			continue
+		}
+		// the snapshots superseded by this snapshot are processed again, in case cancelling their pipelineRuns failed
+		supersededBySnapshot := pullRequestSnapshot.GetAnnotations()[gitops.SnapshotSupersededByAnnotation] == a.snapshot.Name
+		if !supersededBySnapshot && (gitops.AreSnapshotTestsCanceled(&pullRequestSnapshot) || gitops.HaveAppStudioTestsFinished(&pullRequestSnapshot)) {
+			continue
+		}
+
+		if !supersededBySnapshot {
+			err = gitops.MarkSnapshotAsSuperseded(a.context, a.client, &pullRequestSnapshot, a.snapshot.Name)
+			if err != nil {
+				a.logger.Error(err, "Failed to supersede the pull request Snapshot", "supersededSnapshot.Name", pullRequestSnapshot.Name)
+				return controller.RequeueWithError(err)
+			}
+			a.logger.LogAuditEvent("Snapshot was superseded by a newer Snapshot of its pull request, its unfinished tests were canceled",
+				&pullRequestSnapshot, h.LogActionUpdate, "superseding.Snapshot.Name", a.snapshot.Name)
+		}
+
+		err = a.cancelPipelineRunsOfCanceledTests(&pullRequestSnapshot)
+		if err != nil {
+			a.logger.Error(err, "Failed to cancel the pipelineRuns of the superseded Snapshot", "supersededSnapshot.Name", pullRequestSnapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+	}
+
+	return controller.ContinueProcessing()
 }
 
-func scenariosNamesToList(integrationTestScenarios *[]v1beta2.IntegrationTestScenario) *[]string {
-	// transform list of structs into list of strings
-	result := make([]string, 0, len(*integrationTestScenarios))
-	for _, v := range *integrationTestScenarios {
-		result = append(result, v.Name)
+// cancelPipelineRunsOfCanceledTests cancels the pipelineRuns of the canceled tests of the given Snapshot
+// which are still running
+func (a *Adapter) cancelPipelineRunsOfCanceledTests(snapshot *applicationapiv1alpha1.Snapshot) error {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	for _, testStatus := range testStatuses.GetStatuses() {
+		if testStatus.Status != intgteststat.IntegrationTestStatusDeleted || testStatus.TestPipelineRunName == "" {
+			continue
+		}
+		runningPipelineRun, err := a.getRunningPipelineRun(testStatus.TestPipelineRunName)
+		if err != nil {
+			return err
+		}
+		if runningPipelineRun == nil {
+			continue
+		}
+		if err = h.CancelPipelineRun(a.context, a.client, a.logger, runningPipelineRun); err != nil {
+			return err
+		}
 	}
-	return &result
+
+	return nil
 }
 
 // EnsureRerunPipelineRunsExist is responsible for recreating integration test pipelines triggered by users
@@ -97,7 +466,7 @@ func (a *Adapter) EnsureRerunPipelineRunsExist() (controller.OperationResult, er
 	integrationTestScenario, err := a.loader.GetScenario(a.context, a.client, scenarioName, a.application.Namespace)
 
 	if err != nil {
-		if clienterrors.IsNotFound(err) {
+		if loader.IsResourceNotFound(err) {
 			a.logger.Error(err, "scenario for integration test re-run not found", "scenario", scenarioName)
 			// scenario doesn't exist just remove label and continue
 			if err = gitops.RemoveIntegrationTestRerunLabel(a.context, a.client, a.snapshot); err != nil {
@@ -108,6 +477,14 @@ func (a *Adapter) EnsureRerunPipelineRunsExist() (controller.OperationResult, er
 		return controller.RequeueWithError(fmt.Errorf("failed to fetch requested scenario %s: %w", scenarioName, err))
 	}
 
+	if integrationTestScenario.Spec.Suspend {
+		a.logger.Info("IntegrationTestScenario is suspended, skipping the re-run of its integration test", "scenario", scenarioName)
+		if err = gitops.RemoveIntegrationTestRerunLabel(a.context, a.client, a.snapshot); err != nil {
+			return controller.RequeueWithError(err)
+		}
+		return controller.ContinueProcessing()
+	}
+
 	a.logger.Info("Re-running integration test for scenario", "scenario", scenarioName)
 
 	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
@@ -115,37 +492,103 @@ func (a *Adapter) EnsureRerunPipelineRunsExist() (controller.OperationResult, er
 		return controller.RequeueWithError(err)
 	}
 
-	integrationTestScenarioStatus, ok := testStatuses.GetScenarioStatus(integrationTestScenario.Name)
-	if ok && (integrationTestScenarioStatus.Status == intgteststat.IntegrationTestStatusInProgress ||
-		integrationTestScenarioStatus.Status == intgteststat.IntegrationTestStatusPending) {
-		a.logger.Info(fmt.Sprintf("Found existing test in %s status, skipping re-run", integrationTestScenarioStatus.Status),
-			"integrationTestScenario.Name", integrationTestScenario.Name)
-		if err = gitops.RemoveIntegrationTestRerunLabel(a.context, a.client, a.snapshot); err != nil {
-			return controller.RequeueWithError(err)
+	// the tests of the matrix combinations of the scenario are re-run independently of each other,
+	// the tests which are still running are only re-run when the re-run is forced, after cancelling their pipelineRuns
+	forced := gitops.IsIntegrationTestRerunForced(a.snapshot)
+	rerunTriggered := false
+	// tests waiting for a free slot of the cap on running pipelineRuns, the re-run label is kept to retry them
+	hasQueuedTests := false
+	var refusedTests []string
+	for _, scenarioTest := range h.GetScenarioTests(integrationTestScenario) {
+		integrationTestScenarioStatus, ok := testStatuses.GetScenarioStatus(scenarioTest.Name)
+		if retryAfter := getPipelineRunCreationBackoff(testStatuses, scenarioTest.Name, a.clock.Now()); retryAfter > 0 {
+			a.logger.Info("The creation of the pipelineRun failed recently, backing off the re-run",
+				"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name,
+				"retryAfter", retryAfter)
+			return controller.RequeueAfter(retryAfter, nil)
 		}
-		return controller.ContinueProcessing()
-	}
-	testStatuses.ResetStatus(scenarioName)
+		// the tests pending after a transient failure to create their pipelineRun or queued for a free slot are retried
+		if ok && integrationTestScenarioStatus.Status == intgteststat.IntegrationTestStatusPending && integrationTestScenarioStatus.CreationFailures == 0 &&
+			integrationTestScenarioStatus.Details != getPipelineRunQueuedDetails(integrationTestScenario.Name) {
+			a.logger.Info("Found existing test in Pending status, skipping re-run",
+				"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name)
+			continue
+		}
+		if ok && integrationTestScenarioStatus.Status == intgteststat.IntegrationTestStatusInProgress {
+			runningPipelineRun, err := a.getRunningPipelineRun(integrationTestScenarioStatus.TestPipelineRunName)
+			if err != nil {
+				return controller.RequeueWithError(err)
+			}
+			if runningPipelineRun != nil {
+				if !forced {
+					a.logger.Info("Found existing test in InProgress status, refusing re-run",
+						"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name,
+						"pipelineRun.Name", runningPipelineRun.Name)
+					refusedTests = append(refusedTests, fmt.Sprintf("%s (pipelineRun %s)", scenarioTest.Name, runningPipelineRun.Name))
+					continue
+				}
+				if err = h.CancelPipelineRun(a.context, a.client, a.logger, runningPipelineRun); err != nil {
+					return controller.RequeueWithError(err)
+				}
+			}
+		}
+		testStatuses.ResetStatus(scenarioTest.Name)
 
-	pipelineRun, err := a.createIntegrationPipelineRun(a.application, integrationTestScenario, a.snapshot)
-	if err != nil {
-		return a.HandlePipelineCreationError(err, integrationTestScenario, testStatuses)
+		queueKey := pipelinerunqueue.TestKey(a.snapshot.Namespace, a.snapshot.Name, scenarioTest.Name)
+		if !pipelinerunqueue.DefaultQueue.TryAcquire(queueKey, a.snapshot.CreationTimestamp.Time) {
+			a.logger.Info("The cap on running integration pipelineRuns was reached, queueing the re-run",
+				"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name)
+			testStatuses.UpdateTestStatusIfChanged(scenarioTest.Name, intgteststat.IntegrationTestStatusPending,
+				getPipelineRunQueuedDetails(integrationTestScenario.Name))
+			hasQueuedTests = true
+			continue
+		}
+		pipelineRun, err := a.createIntegrationPipelineRun(a.application, integrationTestScenario, scenarioTest, a.snapshot)
+		if err != nil {
+			pipelinerunqueue.DefaultQueue.Release(queueKey)
+			return a.HandlePipelineCreationError(err, scenarioTest, testStatuses)
+		}
+		details := fmt.Sprintf("IntegrationTestScenario pipeline '%s' has been created", pipelineRun.Name)
+		scheduled := gitops.IsScheduledIntegrationTestRun(a.snapshot, integrationTestScenario.Name)
+		if scheduled {
+			details = fmt.Sprintf("IntegrationTestScenario pipeline '%s' has been created for the scheduled run", pipelineRun.Name)
+		}
+		testStatuses.UpdateTestStatusIfChanged(scenarioTest.Name, intgteststat.IntegrationTestStatusInProgress, details)
+		if err = testStatuses.UpdateTestPipelineRunName(scenarioTest.Name, pipelineRun.Name); err != nil {
+			// it doesn't make sense to restart reconciliation here, it will be eventually updated by integrationpipeline adapter
+			a.logger.Error(err, "Failed to update pipelinerun name in test status")
+		}
+		if err = testStatuses.UpdateTestScheduled(scenarioTest.Name, scheduled); err != nil {
+			a.logger.Error(err, "Failed to mark the scheduled run in test status")
+		}
+		if err = testStatuses.UpdateTestScenarioGeneration(scenarioTest.Name, integrationTestScenario.Generation); err != nil {
+			a.logger.Error(err, "Failed to update the scenario generation in test status")
+		}
+		rerunTriggered = true
 	}
-	testStatuses.UpdateTestStatusIfChanged(
-		integrationTestScenario.Name, intgteststat.IntegrationTestStatusInProgress,
-		fmt.Sprintf("IntegrationTestScenario pipeline '%s' has been created", pipelineRun.Name))
-	if err = testStatuses.UpdateTestPipelineRunName(integrationTestScenario.Name, pipelineRun.Name); err != nil {
-		// it doesn't make sense to restart reconciliation here, it will be eventually updated by integrationpipeline adapter
-		a.logger.Error(err, "Failed to update pipelinerun name in test status")
+	if rerunTriggered || hasQueuedTests {
+		if err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client); err != nil {
+			return controller.RequeueWithError(err)
+		}
+
+		if err = gitops.ResetSnapshotStatusConditions(a.context, a.client, a.snapshot, "Integration test is being rerun for snapshot"); err != nil {
+			a.logger.Error(err, "Failed to reset snapshot status conditions")
+			return controller.RequeueWithError(err)
+		}
 	}
 
-	if err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client); err != nil {
-		return controller.RequeueWithError(err)
+	if hasQueuedTests {
+		return controller.RequeueAfter(PipelineRunQueueRequeueDelay, nil)
 	}
 
-	if err = gitops.ResetSnapshotStatusConditions(a.context, a.client, a.snapshot, "Integration test is being rerun for snapshot"); err != nil {
-		a.logger.Error(err, "Failed to reset snapshot status conditions")
-		return controller.RequeueWithError(err)
+	if len(refusedTests) > 0 {
+		reason := fmt.Sprintf("The re-run of the tests %s was refused because they are still running, "+
+			"add the label %s: \"true\" along with the label %s to cancel them and re-run",
+			strings.Join(refusedTests, ", "), gitops.SnapshotIntegrationTestRunForceLabel, gitops.SnapshotIntegrationTestRun)
+		if err = gitops.RefuseIntegrationTestRerun(a.context, a.client, a.snapshot, reason); err != nil {
+			return controller.RequeueWithError(err)
+		}
+		return controller.ContinueProcessing()
 	}
 
 	if err = gitops.RemoveIntegrationTestRerunLabel(a.context, a.client, a.snapshot); err != nil {
@@ -155,6 +598,25 @@ func (a *Adapter) EnsureRerunPipelineRunsExist() (controller.OperationResult, er
 	return controller.ContinueProcessing()
 }
 
+// getRunningPipelineRun returns the integration pipelineRun of the given name if it still exists and hasn't finished yet,
+// nil is returned otherwise
+func (a *Adapter) getRunningPipelineRun(pipelineRunName string) (*tektonv1.PipelineRun, error) {
+	if pipelineRunName == "" {
+		return nil, nil
+	}
+	pipelineRun, err := a.loader.GetPipelineRun(a.context, a.client, pipelineRunName, a.snapshot.Namespace)
+	if err != nil {
+		if loader.IsResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch the pipelineRun %s: %w", pipelineRunName, err)
+	}
+	if h.HasPipelineRunFinished(pipelineRun) {
+		return nil, nil
+	}
+	return pipelineRun, nil
+}
+
 // EnsureIntegrationPipelineRunsExist is an operation that will ensure that all Integration pipeline runs
 // associated with the Snapshot and the Application's IntegrationTestScenarios exist.
 func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResult, error) {
@@ -162,6 +624,10 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 		a.logger.Info("The Snapshot has finished testing.")
 		return controller.ContinueProcessing()
 	}
+	if gitops.AreSnapshotTestsCanceled(a.snapshot) {
+		a.logger.Info("The tests of the Snapshot were canceled, not creating pipelineRuns for it.")
+		return controller.ContinueProcessing()
+	}
 
 	integrationTestScenarios, err := a.loader.GetAllIntegrationTestScenariosForApplication(a.context, a.client, a.application)
 	if err != nil {
@@ -169,6 +635,16 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 			"Application.Namespace", a.application.Namespace)
 	}
 
+	// scenarios waiting for their dependencies to pass, the snapshot is requeued to create their pipelineRuns later
+	hasDeferredScenarios := false
+	// tests waiting for a free slot of the cap on running pipelineRuns, the snapshot is requeued to retry their creation
+	hasQueuedTests := false
+	// the shortest backoff of the tests whose pipelineRun creation failed with a transient error
+	var creationRetryAfter time.Duration
+	// tests whose pipelineRun creation was staggered to a later batch, and the delay until the next batch can be created
+	hasStaggeredTests := false
+	staggerAfter, batchCapacity := a.getPipelineRunBatchCapacity()
+	createdPipelineRuns := 0
 	if integrationTestScenarios != nil {
 		a.logger.Info(
 			fmt.Sprintf("Found %d IntegrationTestScenarios for application", len(*integrationTestScenarios)),
@@ -181,7 +657,7 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 		if err != nil {
 			return controller.RequeueWithError(err)
 		}
-		testStatuses.InitStatuses(scenariosNamesToList(integrationTestScenarios))
+		testStatuses.InitStatuses(h.GetScenarioTestNames(integrationTestScenarios))
 		err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client)
 		if err != nil {
 			return controller.RequeueWithError(err)
@@ -198,47 +674,150 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 			}
 		}()
 
+		// the components may have been deleted since the snapshot was created, the pipelineRuns which are still
+		// to be created would fail testing them. The snapshot isn't invalidated once some of its tests are running.
+		if a.hasUntriggeredTestsOnly(integrationTestScenarios, testStatuses) {
+			missingComponents, err := a.getMissingSnapshotComponents()
+			if err != nil {
+				a.logger.Error(err, "Failed to get the components of the Snapshot")
+				return controller.RequeueWithError(err)
+			}
+			if len(missingComponents) > 0 {
+				return a.invalidateSnapshotWithMissingComponents(missingComponents, testStatuses)
+			}
+		}
+
+		// the earlier snapshot with identical components whose final test results are reused, if the application opted in
+		reusedSnapshot, reusedTestStatuses := a.getSnapshotWithReusableTestResults()
+
+		// the pipelineRuns are created in the order of the scenario names, so the staggered batches are deterministic
+		sortedScenarios := slices.Clone(*integrationTestScenarios)
+		slices.SortFunc(sortedScenarios, func(a, b v1beta2.IntegrationTestScenario) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
 		var errsForPLRCreation error
-		for _, integrationTestScenario := range *integrationTestScenarios {
+		for _, integrationTestScenario := range sortedScenarios {
 			integrationTestScenario := integrationTestScenario //G601
+			// scenarios with a matrix have a separate test status for each combination of the matrix params
+			scenarioTests := h.GetScenarioTests(&integrationTestScenario)
+			updateScenarioTestStatuses := func(status intgteststat.IntegrationTestStatus, details string) {
+				for _, scenarioTest := range scenarioTests {
+					testStatuses.UpdateTestStatusIfChanged(scenarioTest.Name, status, details)
+				}
+			}
 			if !h.IsScenarioValid(&integrationTestScenario) {
 				a.logger.Info("IntegrationTestScenario is invalid, will not create pipelineRun for it",
 					"integrationTestScenario.Name", integrationTestScenario.Name)
 				scenarioStatusCondition := meta.FindStatusCondition(integrationTestScenario.Status.Conditions, h.IntegrationTestScenarioValid)
-				testStatuses.UpdateTestStatusIfChanged(
-					integrationTestScenario.Name, intgteststat.IntegrationTestStatusTestInvalid,
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusTestInvalid,
 					fmt.Sprintf("IntegrationTestScenario '%s' is invalid: %s", integrationTestScenario.Name, scenarioStatusCondition.Message))
 				continue
 			}
 			// Check if an existing integration pipelineRun is registered in the Snapshot's status
 			// We rely on this because the actual pipelineRun CR may have been pruned by this point
-			integrationTestScenarioStatus, ok := testStatuses.GetScenarioStatus(integrationTestScenario.Name)
-			if ok && integrationTestScenarioStatus.TestPipelineRunName != "" {
+			integrationTestScenarioStatus, ok := testStatuses.GetScenarioStatus(scenarioTests[0].Name)
+			untriggeredTests := getUntriggeredScenarioTests(scenarioTests, testStatuses)
+			if len(untriggeredTests) == 0 {
 				a.logger.Info("Found existing integrationPipelineRun",
 					"integrationTestScenario.Name", integrationTestScenario.Name,
 					"pipelineRun.Name", integrationTestScenarioStatus.TestPipelineRunName)
+			} else if ok && integrationTestScenarioStatus.Status == intgteststat.IntegrationTestStatusSkipped {
+				// the skipped scenarios aren't re-evaluated, e.g. unsuspending a scenario doesn't affect the snapshots
+				// which were created while it was suspended
+				a.logger.Info("IntegrationTestScenario was skipped for the snapshot",
+					"integrationTestScenario.Name", integrationTestScenario.Name,
+					"details", integrationTestScenarioStatus.Details)
+			} else if integrationTestScenario.Spec.Suspend {
+				a.logger.Info("IntegrationTestScenario is suspended, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusSkipped,
+					fmt.Sprintf("IntegrationTestScenario '%s' was skipped because it is suspended", integrationTestScenario.Name))
+			} else if !a.isSnapshotContextSelectedByScenario(&integrationTestScenario) {
+				a.logger.Info("IntegrationTestScenario isn't applicable to the context of the snapshot, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name,
+					"integrationTestScenario.Spec.Contexts", integrationTestScenario.Spec.Contexts)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusSkipped,
+					fmt.Sprintf("IntegrationTestScenario '%s' was skipped because none of its contexts apply to the snapshot", integrationTestScenario.Name))
+			} else if !a.isSnapshotComponentSelectedByScenario(&integrationTestScenario) {
+				a.logger.Info("IntegrationTestScenario doesn't select the component of the snapshot, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name,
+					"component.Name", a.component.Name)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusSkipped,
+					fmt.Sprintf("IntegrationTestScenario '%s' doesn't select the component '%s' of the snapshot", integrationTestScenario.Name, a.component.Name))
+			} else if !a.isSnapshotTargetBranchSelectedByScenario(&integrationTestScenario) {
+				a.logger.Info("IntegrationTestScenario doesn't apply to the target branch of the snapshot, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name,
+					"integrationTestScenario.Spec.TargetBranches", integrationTestScenario.Spec.TargetBranches)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusSkipped,
+					fmt.Sprintf("IntegrationTestScenario '%s' was skipped because the target branch '%s' of the snapshot doesn't match its target branches",
+						integrationTestScenario.Name, a.snapshot.GetLabels()[gitops.BuildTargetBranchLabel]))
+			} else if state, details := getScenarioDependenciesState(&integrationTestScenario, integrationTestScenarios, testStatuses); state == scenarioDependenciesFailed {
+				// the test can't pass without running, it must not count as passed like the skipped tests do
+				a.logger.Info("IntegrationTestScenario dependency can't pass, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name, "details", details)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusTestError, details)
+			} else if state == scenarioDependenciesSkipped {
+				a.logger.Info("IntegrationTestScenario dependency was skipped, will not create pipelineRun for it",
+					"integrationTestScenario.Name", integrationTestScenario.Name, "details", details)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusSkipped, details)
+			} else if state == scenarioDependenciesPending {
+				a.logger.Info("IntegrationTestScenario dependencies haven't passed yet, deferring the creation of its pipelineRun",
+					"integrationTestScenario.Name", integrationTestScenario.Name, "details", details)
+				updateScenarioTestStatuses(intgteststat.IntegrationTestStatusPending, details)
+				hasDeferredScenarios = true
 			} else {
-				pipelineRun, err := a.createIntegrationPipelineRun(a.application, &integrationTestScenario, a.snapshot)
-				if err != nil {
-					a.logger.Error(err, "Failed to create pipelineRun for snapshot and scenario",
-						"integrationScenario.Name", integrationTestScenario.Name)
-
+				for _, scenarioTest := range untriggeredTests {
+					if a.reuseTestResult(&integrationTestScenario, scenarioTest.Name, testStatuses, reusedSnapshot, reusedTestStatuses) {
+						continue
+					}
+					if retryAfter := getPipelineRunCreationBackoff(testStatuses, scenarioTest.Name, a.clock.Now()); retryAfter > 0 {
+						a.logger.Info("The creation of the pipelineRun failed recently, backing off the retry",
+							"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name,
+							"retryAfter", retryAfter)
+						creationRetryAfter = shortestRequeueDelay(creationRetryAfter, retryAfter)
+						continue
+					}
+					if batchCapacity >= 0 && createdPipelineRuns >= batchCapacity {
+						a.logger.Info("The creation of the pipelineRun is staggered to the next batch",
+							"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name)
+						testStatuses.UpdateTestStatusIfChanged(
+							scenarioTest.Name, intgteststat.IntegrationTestStatusPending,
+							fmt.Sprintf("IntegrationTestScenario '%s' is waiting for the staggered creation of its pipelineRun",
+								integrationTestScenario.Name))
+						hasStaggeredTests = true
+						continue
+					}
+					queueKey := pipelinerunqueue.TestKey(a.snapshot.Namespace, a.snapshot.Name, scenarioTest.Name)
+					if !pipelinerunqueue.DefaultQueue.TryAcquire(queueKey, a.snapshot.CreationTimestamp.Time) {
+						a.logger.Info("The cap on running integration pipelineRuns was reached, queueing the creation of the pipelineRun",
+							"integrationTestScenario.Name", integrationTestScenario.Name, "test.Name", scenarioTest.Name)
+						testStatuses.UpdateTestStatusIfChanged(
+							scenarioTest.Name, intgteststat.IntegrationTestStatusPending,
+							getPipelineRunQueuedDetails(integrationTestScenario.Name))
+						hasQueuedTests = true
+						continue
+					}
+					pipelineRun, err := a.createIntegrationPipelineRun(a.application, &integrationTestScenario, scenarioTest, a.snapshot)
+					if err != nil {
+						pipelinerunqueue.DefaultQueue.Release(queueKey)
+						if retryAfter := a.recordPipelineRunCreationFailure(err, scenarioTest, testStatuses); retryAfter > 0 {
+							creationRetryAfter = shortestRequeueDelay(creationRetryAfter, retryAfter)
+						}
+						continue
+					}
+					createdPipelineRuns++
+					gitops.PrepareToRegisterIntegrationPipelineRunStarted(a.snapshot) // don't count re-runs
 					testStatuses.UpdateTestStatusIfChanged(
-						integrationTestScenario.Name, intgteststat.IntegrationTestStatusTestInvalid,
-						fmt.Sprintf("Creation of pipelineRun failed during creation due to: %s.", err))
-
-					if !clienterrors.IsInvalid(err) {
-						errsForPLRCreation = errors.Join(errsForPLRCreation, err)
+						scenarioTest.Name, intgteststat.IntegrationTestStatusInProgress,
+						fmt.Sprintf("IntegrationTestScenario pipeline '%s' has been created", pipelineRun.Name))
+					if err = testStatuses.UpdateTestPipelineRunName(scenarioTest.Name, pipelineRun.Name); err != nil {
+						// it doesn't make sense to restart reconciliation here, it will be eventually updated by integrationpipeline adapter
+						a.logger.Error(err, "Failed to update pipelinerun name in test status")
+					}
+					if err = testStatuses.UpdateTestScenarioGeneration(scenarioTest.Name, integrationTestScenario.Generation); err != nil {
+						a.logger.Error(err, "Failed to update the scenario generation in test status")
 					}
-					continue
-				}
-				gitops.PrepareToRegisterIntegrationPipelineRunStarted(a.snapshot) // don't count re-runs
-				testStatuses.UpdateTestStatusIfChanged(
-					integrationTestScenario.Name, intgteststat.IntegrationTestStatusInProgress,
-					fmt.Sprintf("IntegrationTestScenario pipeline '%s' has been created", pipelineRun.Name))
-				if err = testStatuses.UpdateTestPipelineRunName(integrationTestScenario.Name, pipelineRun.Name); err != nil {
-					// it doesn't make sense to restart reconciliation here, it will be eventually updated by integrationpipeline adapter
-					a.logger.Error(err, "Failed to update pipelinerun name in test status")
 				}
 			}
 		}
@@ -252,6 +831,15 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 		if errsForPLRCreation != nil {
 			return controller.RequeueWithError(errsForPLRCreation)
 		}
+
+		// the next batch is created once the stagger passes since the creation of this one
+		if hasStaggeredTests && createdPipelineRuns > 0 {
+			staggerAfter = PipelineRunCreationStagger
+			if err = gitops.MarkPipelineRunBatchCreated(a.context, a.client, a.snapshot, a.clock.Now()); err != nil {
+				a.logger.Error(err, "Failed to record the creation time of the batch of integration pipelineRuns")
+				return controller.RequeueWithError(err)
+			}
+		}
 	}
 
 	requiredIntegrationTestScenarios, err := a.loader.GetRequiredIntegrationTestScenariosForApplication(a.context, a.client, a.application)
@@ -273,15 +861,72 @@ func (a *Adapter) EnsureIntegrationPipelineRunsExist() (controller.OperationResu
 			a.snapshot, h.LogActionUpdate,
 			"snapshot.Status", a.snapshot.Status)
 	}
+	// make it visible on the pull/merge request that nothing was tested, the scenarios skipped for the snapshot
+	// are reported on their own
+	if integrationTestScenarios != nil && len(*integrationTestScenarios) == 0 &&
+		!gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) && !gitops.IsSnapshotWithoutIntegrationTests(a.snapshot) {
+		err := gitops.MarkSnapshotWithoutIntegrationTests(a.context, a.client, a.snapshot,
+			fmt.Sprintf("No IntegrationTestScenarios are configured for application %s", a.application.Name))
+		if err != nil {
+			a.logger.Error(err, "Failed to mark the Snapshot as having no integration tests")
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Snapshot marked as having no integration tests, it will be reported to the git provider",
+			a.snapshot, h.LogActionUpdate)
+	}
+	if err = a.ensureTestsCompletedConditionInitialized(requiredIntegrationTestScenarios); err != nil {
+		a.logger.Error(err, "Failed to update the AppStudio tests completed condition of the Snapshot")
+		return controller.RequeueWithError(err)
+	}
 
+	requeueAfter := creationRetryAfter
+	if hasQueuedTests {
+		requeueAfter = shortestRequeueDelay(requeueAfter, PipelineRunQueueRequeueDelay)
+	}
+	if hasDeferredScenarios {
+		requeueAfter = shortestRequeueDelay(requeueAfter, ScenarioDependenciesRequeueDelay)
+	}
+	if hasStaggeredTests {
+		requeueAfter = shortestRequeueDelay(requeueAfter, staggerAfter)
+	}
+	if requeueAfter > 0 {
+		return controller.RequeueAfter(requeueAfter, nil)
+	}
 	return controller.ContinueProcessing()
 }
 
+// ensureTestsCompletedConditionInitialized sets the AppStudio tests completed condition of the Snapshot to in progress
+// once a pipelineRun was created for its unfinished required tests, or to passed when the application has no required
+// IntegrationTestScenarios. The statusreport controller completes the condition once all required tests finished.
+func (a *Adapter) ensureTestsCompletedConditionInitialized(requiredIntegrationTestScenarios *[]v1beta2.IntegrationTestScenario) error {
+	if gitops.IsSnapshotTestsCompletedConditionSet(a.snapshot) {
+		return nil
+	}
+	if len(*requiredIntegrationTestScenarios) == 0 {
+		return gitops.MarkSnapshotTestsAsCompleted(a.context, a.client, a.snapshot, true, "No required IntegrationTestScenarios found, skipped testing")
+	}
+
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return err
+	}
+	if gitops.GetRequiredTestsCompletion(a.snapshot, requiredIntegrationTestScenarios, testStatuses).AllFinished() {
+		return nil
+	}
+	for _, testDetails := range testStatuses.GetStatuses() {
+		if testDetails.TestPipelineRunName != "" {
+			return gitops.MarkSnapshotTestsAsInProgress(a.context, a.client, a.snapshot, "Some required integration tests haven't finished yet")
+		}
+	}
+	return nil
+}
+
 // EnsureGlobalCandidateImageUpdated is an operation that ensure the ContainerImage in the Global Candidate List
-// being updated when the Snapshot passed all the integration tests
+// being updated when the Snapshot passed all the integration tests. Each Component is updated independently,
+// the failures are recorded in the Snapshot and the operation is requeued only when all the Components failed
 func (a *Adapter) EnsureGlobalCandidateImageUpdated() (controller.OperationResult, error) {
-	if a.component == nil || !gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) {
-		a.logger.Info("The Snapshot wasn't created for a single component push event, not updating the global candidate list.")
+	if !gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) {
+		a.logger.Info("The Snapshot wasn't created for a push event, not updating the global candidate list.")
 		return controller.ContinueProcessing()
 	}
 	if !gitops.HaveAppStudioTestsSucceeded(a.snapshot) {
@@ -293,39 +938,51 @@ func (a *Adapter) EnsureGlobalCandidateImageUpdated() (controller.OperationResul
 		return controller.ContinueProcessing()
 	}
 
-	for _, component := range a.snapshot.Spec.Components {
-		if component.Name == a.component.Name {
-			patch := client.MergeFrom(a.component.DeepCopy())
-			a.component.Spec.ContainerImage = component.ContainerImage
-			err := a.client.Patch(a.context, a.component, patch)
-			if err != nil {
-				a.logger.Error(err, "Failed to update .Spec.ContainerImage of Global Candidate for the Component",
-					"component.Name", a.component.Name)
-				return controller.RequeueWithError(err)
-			}
-			a.logger.LogAuditEvent("Updated .Spec.ContainerImage of Global Candidate for the Component",
-				a.component, h.LogActionUpdate,
-				"containerImage", component.ContainerImage)
-			if reflect.ValueOf(component.Source).IsValid() && component.Source.GitSource != nil && component.Source.GitSource.Revision != "" {
-				patch := client.MergeFrom(a.component.DeepCopy())
-				a.component.Status.LastBuiltCommit = component.Source.GitSource.Revision
-				err = a.client.Status().Patch(a.context, a.component, patch)
-				if err != nil {
-					a.logger.Error(err, "Failed to update .Status.LastBuiltCommit of Global Candidate for the Component",
-						"component.Name", a.component.Name)
-					return controller.RequeueWithError(err)
-				}
-				a.logger.LogAuditEvent("Updated .Status.LastBuiltCommit of Global Candidate for the Component",
-					a.component, h.LogActionUpdate,
-					"lastBuildCommit", a.component.Status.LastBuiltCommit)
-			}
-			break
+	// the Snapshot of a single component build updates only its component, the other Components of the Snapshot
+	// could have been updated since the Snapshot was created
+	candidateUpdateErrors := map[string]string{}
+	var errs error
+	attempted := 0
+	for _, snapshotComponent := range a.snapshot.Spec.Components {
+		component := a.component
+		if component != nil && component.Name != snapshotComponent.Name {
+			continue
+		}
+		attempted++
+
+		var err error
+		if component == nil {
+			// the Component is updated with the new candidate, it must not be a copy loaded earlier in the reconcile
+			component, err = a.loader.GetComponent(loader.WithoutMemoization(a.context), a.client, snapshotComponent.Name, a.snapshot.Namespace)
+		}
+		if err == nil {
+			err = a.updateGlobalCandidate(component, snapshotComponent)
 		}
+		if err != nil {
+			a.logger.Error(err, "Failed to update the Global Candidate for the Component",
+				"component.Name", snapshotComponent.Name)
+			candidateUpdateErrors[snapshotComponent.Name] = err.Error()
+			errs = errors.Join(errs, fmt.Errorf("failed to update the global candidate of component %s: %w", snapshotComponent.Name, err))
+		}
+	}
+
+	err := gitops.SetSnapshotCandidateUpdateErrors(a.context, a.client, a.snapshot, candidateUpdateErrors)
+	if err != nil {
+		a.logger.Error(err, "Failed to record the global candidate update errors in the Snapshot")
+		return controller.RequeueWithError(errors.Join(errs, err))
+	}
+	if attempted > 0 && len(candidateUpdateErrors) == attempted {
+		return controller.RequeueWithError(errs)
 	}
 
 	// Mark the Snapshot as already added to global candidate list to prevent it from getting added again when the Snapshot
 	// gets reconciled at a later time
-	err := gitops.MarkSnapshotAsAddedToGlobalCandidateList(a.context, a.client, a.snapshot, "The Snapshot's component was added to the global candidate list")
+	message := "The Snapshot's component was added to the global candidate list"
+	if len(candidateUpdateErrors) > 0 {
+		message = fmt.Sprintf("The Snapshot's components were added to the global candidate list, except for %d component(s) "+
+			"recorded in the %s annotation", len(candidateUpdateErrors), gitops.SnapshotCandidateUpdateErrorsAnnotation)
+	}
+	err = gitops.MarkSnapshotAsAddedToGlobalCandidateList(a.context, a.client, a.snapshot, message)
 	if err != nil {
 		a.logger.Error(err, "Failed to update the Snapshot's status to AddedToGlobalCandidateList")
 		return controller.RequeueWithError(err)
@@ -334,6 +991,49 @@ func (a *Adapter) EnsureGlobalCandidateImageUpdated() (controller.OperationResul
 	return controller.ContinueProcessing()
 }
 
+// updateGlobalCandidate updates the ContainerImage and the LastBuiltCommit of the given Component
+// with the ones of the Snapshot component
+func (a *Adapter) updateGlobalCandidate(component *applicationapiv1alpha1.Component, snapshotComponent applicationapiv1alpha1.SnapshotComponent) error {
+	patch := client.MergeFrom(component.DeepCopy())
+	component.Spec.ContainerImage = snapshotComponent.ContainerImage
+	err := a.client.Patch(a.context, component, patch)
+	if err != nil {
+		return fmt.Errorf("failed to update .Spec.ContainerImage: %w", err)
+	}
+	a.logger.LogAuditEvent("Updated .Spec.ContainerImage of Global Candidate for the Component",
+		component, h.LogActionUpdate,
+		"containerImage", snapshotComponent.ContainerImage)
+
+	if !reflect.ValueOf(snapshotComponent.Source).IsValid() || snapshotComponent.Source.GitSource == nil || snapshotComponent.Source.GitSource.Revision == "" {
+		return nil
+	}
+
+	// the build service updates the status of the Component as well, the optimistic lock makes sure
+	// that its changes aren't overwritten
+	firstAttempt := true
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !firstAttempt {
+			err := a.client.Get(a.context, client.ObjectKeyFromObject(component), component)
+			if err != nil {
+				return err
+			}
+		}
+		firstAttempt = false
+
+		patch := client.MergeFromWithOptions(component.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		component.Status.LastBuiltCommit = snapshotComponent.Source.GitSource.Revision
+		return a.client.Status().Patch(a.context, component, patch)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update .Status.LastBuiltCommit: %w", err)
+	}
+	a.logger.LogAuditEvent("Updated .Status.LastBuiltCommit of Global Candidate for the Component",
+		component, h.LogActionUpdate,
+		"lastBuildCommit", component.Status.LastBuiltCommit)
+
+	return nil
+}
+
 // EnsureAllReleasesExist is an operation that will ensure that all pipeline Releases associated
 // to the Snapshot and the Application's ReleasePlans exist.
 // Otherwise, it will create new Releases for each ReleasePlan.
@@ -365,7 +1065,17 @@ func (a *Adapter) EnsureAllReleasesExist() (controller.OperationResult, error) {
 		return a.RequeueIfYoungerThanThreshold(err)
 	}
 
-	err = a.createMissingReleasesForReleasePlans(a.application, releasePlans, a.snapshot)
+	releaseNames, err := a.createMissingReleasesForReleasePlans(a.application, releasePlans, a.snapshot)
+	// record the Releases even when some of them couldn't be created, the missing ones are created on requeue;
+	// "none matched" is recorded only when no ReleasePlan matched the Snapshot
+	if len(releaseNames) > 0 || err == nil {
+		if er := gitops.SetSnapshotAutoReleases(a.context, a.client, a.snapshot, releaseNames); er != nil {
+			a.logger.Error(er, "Failed to record the auto-released Releases in the Snapshot")
+			if err == nil {
+				return controller.RequeueWithError(er)
+			}
+		}
+	}
 	if err != nil {
 		a.logger.Error(err, "Failed to create new Releases")
 		patch := client.MergeFrom(a.snapshot.DeepCopy())
@@ -393,23 +1103,45 @@ func (a *Adapter) EnsureAllReleasesExist() (controller.OperationResult, error) {
 }
 
 // createMissingReleasesForReleasePlans checks if there's existing Releases for a given list of ReleasePlans and creates
-// new ones if they are missing. In case the Releases can't be created, an error will be returned.
-func (a *Adapter) createMissingReleasesForReleasePlans(application *applicationapiv1alpha1.Application, releasePlans *[]releasev1alpha1.ReleasePlan, snapshot *applicationapiv1alpha1.Snapshot) error {
+// new ones if they are missing. ReleasePlans of other Applications and ReleasePlans without a target namespace are skipped.
+// The failure to create a Release doesn't prevent creating the others, the names of the existing and created Releases
+// are returned along with the joined errors.
+func (a *Adapter) createMissingReleasesForReleasePlans(application *applicationapiv1alpha1.Application, releasePlans *[]releasev1alpha1.ReleasePlan, snapshot *applicationapiv1alpha1.Snapshot) ([]string, error) {
 	releases, err := a.loader.GetReleasesWithSnapshot(a.context, a.client, a.snapshot)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	firstRelease := true
+	releaseNames := []string{}
+	var errs error
 
 	for _, releasePlan := range *releasePlans {
 		releasePlan := releasePlan // G601
+		if releasePlan.Spec.Application != application.Name {
+			a.logger.Info("ReleasePlan doesn't target the Application of the Snapshot, skipping it",
+				"releasePlan.Name", releasePlan.Name, "releasePlan.Application", releasePlan.Spec.Application)
+			continue
+		}
+		if releasePlan.Spec.Target == "" {
+			a.logger.Info("ReleasePlan doesn't have a target namespace, skipping it", "releasePlan.Name", releasePlan.Name)
+			continue
+		}
+
 		existingRelease := release.FindMatchingReleaseWithReleasePlan(releases, releasePlan)
 		if existingRelease != nil {
 			a.logger.Info("Found existing Release",
 				"snapshot.Name", snapshot.Name,
 				"releasePlan.Name", releasePlan.Name,
 				"release.Name", existingRelease.Name)
+			// the Release could have been created by a previous reconcile which failed to mark it as automated
+			if metadata.HasLabelWithValue(existingRelease, releasemetadata.AutomatedLabel, "true") && !existingRelease.IsAutomated() {
+				if err := a.markReleaseAsAutomated(existingRelease); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("failed to mark Release %s as automated: %w", existingRelease.Name, err))
+					continue
+				}
+			}
+			releaseNames = append(releaseNames, existingRelease.Name)
 		} else {
 			newRelease := release.NewReleaseForReleasePlan(&releasePlan, snapshot)
 			// Propagate PAC annotations/labels from Snapshot to Release
@@ -422,22 +1154,22 @@ func (a *Adapter) createMissingReleasesForReleasePlans(application *applicationa
 
 			err := ctrl.SetControllerReference(application, newRelease, a.client.Scheme())
 			if err != nil {
-				return err
+				errs = errors.Join(errs, fmt.Errorf("failed to create Release for ReleasePlan %s: %w", releasePlan.Name, err))
+				continue
 			}
 			err = a.client.Create(a.context, newRelease)
 			if err != nil {
-				return err
+				errs = errors.Join(errs, fmt.Errorf("failed to create Release for ReleasePlan %s: %w", releasePlan.Name, err))
+				continue
 			}
 			a.logger.LogAuditEvent("Created a new Release", newRelease, h.LogActionAdd,
 				"releasePlan.Name", releasePlan.Name)
+			releaseNames = append(releaseNames, newRelease.Name)
 
-			patch := client.MergeFrom(newRelease.DeepCopy())
-			newRelease.SetAutomated()
-			err = a.client.Status().Patch(a.context, newRelease, patch)
-			if err != nil {
-				return err
+			if err = a.markReleaseAsAutomated(newRelease); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to mark Release %s as automated: %w", newRelease.Name, err))
+				continue
 			}
-			a.logger.Info("Marked Release status automated", "release.Name", newRelease.Name)
 		}
 		// Register the first release time for metrics calculation
 		if firstRelease {
@@ -448,24 +1180,58 @@ func (a *Adapter) createMissingReleasesForReleasePlans(application *applicationa
 			}
 		}
 	}
+	return releaseNames, errs
+}
+
+// markReleaseAsAutomated marks the status of the Release created by the auto-release as automated
+func (a *Adapter) markReleaseAsAutomated(newRelease *releasev1alpha1.Release) error {
+	patch := client.MergeFrom(newRelease.DeepCopy())
+	newRelease.SetAutomated()
+	err := a.client.Status().Patch(a.context, newRelease, patch)
+	if err != nil {
+		return err
+	}
+	a.logger.Info("Marked Release status automated", "release.Name", newRelease.Name)
 	return nil
 }
 
 // createIntegrationPipelineRun creates and returns a new integration PipelineRun. The Pipeline information and the parameters to it
 // will be extracted from the given integrationScenario. The integration's Snapshot will also be passed to the integration PipelineRun.
-func (a *Adapter) createIntegrationPipelineRun(application *applicationapiv1alpha1.Application, integrationTestScenario *v1beta2.IntegrationTestScenario, snapshot *applicationapiv1alpha1.Snapshot) (*tektonv1.PipelineRun, error) {
+func (a *Adapter) createIntegrationPipelineRun(application *applicationapiv1alpha1.Application, integrationTestScenario *v1beta2.IntegrationTestScenario, scenarioTest h.ScenarioTest, snapshot *applicationapiv1alpha1.Snapshot) (*tektonv1.PipelineRun, error) {
 	a.logger.Info("Creating new pipelinerun for integrationTestscenario",
 		"integrationTestScenario.Name", integrationTestScenario.Name)
 
-	pipelineRun := tekton.NewIntegrationPipelineRun(snapshot.Name, application.Namespace, *integrationTestScenario).
+	paramTemplateData, err := newParamTemplateData(application, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	params, err := tekton.ExpandParamTemplates(integrationTestScenario.Spec.Params, paramTemplateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand the params of integrationTestScenario %s: %w", integrationTestScenario.Name, err)
+	}
+	params, err = tekton.ResolveParamValueSources(a.context, a.client, integrationTestScenario.Namespace, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the params of integrationTestScenario %s: %w", integrationTestScenario.Name, err)
+	}
+
+	pipelineRunBuilder := tekton.NewIntegrationPipelineRun(snapshot.Name, application.Namespace, *integrationTestScenario).
 		WithSnapshot(snapshot).
+		WithGitEventParams(snapshot).
 		WithIntegrationLabels(integrationTestScenario).
 		WithIntegrationAnnotations(integrationTestScenario).
 		WithApplicationAndComponent(a.application, a.component).
-		WithExtraParams(integrationTestScenario.Spec.Params).
-		WithFinalizer(h.IntegrationPipelineRunFinalizer).
+		WithPropagatedLabels(integrationTestScenario, a.logger.Logger).
+		WithExtraParams(params, a.logger.Logger).
+		WithScenarioTest(scenarioTest).
+		WithWorkspaces(integrationTestScenario).
+		WithTaskRunSpecs(integrationTestScenario).
+		WithPodTemplate(integrationTestScenario).
 		WithDefaultIntegrationTimeouts(a.logger.Logger).
-		AsPipelineRun()
+		WithIntegrationTimeouts(integrationTestScenario, a.logger.Logger)
+	if gitops.IsScheduledIntegrationTestRun(snapshot, integrationTestScenario.Name) {
+		pipelineRunBuilder.WithScheduledRunLabel()
+	}
+	pipelineRun := pipelineRunBuilder.AsPipelineRun()
 	// copy PipelineRun PAC annotations/labels from snapshot to integration test PipelineRuns
 	_ = metadata.CopyAnnotationsByPrefix(&snapshot.ObjectMeta, &pipelineRun.ObjectMeta, gitops.PipelinesAsCodePrefix)
 	_ = metadata.CopyLabelsByPrefix(&snapshot.ObjectMeta, &pipelineRun.ObjectMeta, gitops.PipelinesAsCodePrefix)
@@ -474,7 +1240,7 @@ func (a *Adapter) createIntegrationPipelineRun(application *applicationapiv1alph
 	_ = metadata.CopyLabelsByPrefix(&snapshot.ObjectMeta, &pipelineRun.ObjectMeta, gitops.BuildPipelineRunPrefix)
 	_ = metadata.CopyAnnotationsByPrefix(&snapshot.ObjectMeta, &pipelineRun.ObjectMeta, gitops.BuildPipelineRunPrefix)
 
-	err := ctrl.SetControllerReference(snapshot, pipelineRun, a.client.Scheme())
+	err = ctrl.SetControllerReference(snapshot, pipelineRun, a.client.Scheme())
 	if err != nil {
 		return nil, fmt.Errorf("failed to set snapshot %s as ControllerReference of pipelineRun: %w", snapshot.Name, err)
 	}
@@ -499,6 +1265,69 @@ func (a *Adapter) createIntegrationPipelineRun(application *applicationapiv1alph
 	return pipelineRun, nil
 }
 
+// isSnapshotContextSelectedByScenario returns false if none of the contexts of the given scenario apply to the
+// snapshot. The unknown contexts of the scenario are logged, they don't cause the scenario to be skipped.
+func (a *Adapter) isSnapshotContextSelectedByScenario(integrationTestScenario *v1beta2.IntegrationTestScenario) bool {
+	if unknownContexts := gitops.GetUnknownContexts(integrationTestScenario); len(unknownContexts) > 0 {
+		a.logger.Info("IntegrationTestScenario has unknown contexts, they are treated as applicable to the snapshot",
+			"integrationTestScenario.Name", integrationTestScenario.Name,
+			"unknownContexts", unknownContexts)
+	}
+	return gitops.IsScenarioApplicableToSnapshotsContext(integrationTestScenario, a.snapshot)
+}
+
+// isSnapshotComponentSelectedByScenario returns false if the snapshot was created for a component which isn't
+// selected by the component selector of the given scenario. Snapshots which weren't created for a single component
+// are tested by all scenarios.
+func (a *Adapter) isSnapshotComponentSelectedByScenario(integrationTestScenario *v1beta2.IntegrationTestScenario) bool {
+	if a.component == nil {
+		return true
+	}
+
+	selected, err := h.IsComponentSelectedByScenario(integrationTestScenario, a.component)
+	if err != nil {
+		// don't skip the tests of the snapshot when the component selector can't be evaluated
+		a.logger.Error(err, "Failed to evaluate the component selector of the IntegrationTestScenario",
+			"integrationTestScenario.Name", integrationTestScenario.Name)
+		return true
+	}
+	return selected
+}
+
+// isSnapshotTargetBranchSelectedByScenario returns false if the target branch of the snapshot doesn't match
+// the target branches of the given scenario.
+func (a *Adapter) isSnapshotTargetBranchSelectedByScenario(integrationTestScenario *v1beta2.IntegrationTestScenario) bool {
+	selected, err := gitops.IsScenarioApplicableToSnapshotsTargetBranch(integrationTestScenario, a.snapshot)
+	if err != nil {
+		// don't skip the tests of the snapshot when the target branches can't be evaluated
+		a.logger.Error(err, "Failed to evaluate the target branches of the IntegrationTestScenario",
+			"integrationTestScenario.Name", integrationTestScenario.Name)
+		return true
+	}
+	return selected
+}
+
+// newParamTemplateData returns the values which can be referenced by the placeholders in the params of
+// the IntegrationTestScenarios tested for the given snapshot, including the results exported by its passed tests.
+func newParamTemplateData(application *applicationapiv1alpha1.Application, snapshot *applicationapiv1alpha1.Snapshot) (*tekton.ParamTemplateData, error) {
+	exportedResults, err := gitops.GetExportedResults(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &tekton.ParamTemplateData{
+		Snapshot:    snapshot,
+		Application: application,
+		Event: tekton.ParamTemplateEvent{
+			Type:         snapshot.GetLabels()[gitops.PipelineAsCodeEventTypeLabel],
+			TargetBranch: snapshot.GetAnnotations()[gitops.PipelineAsCodeBranchAnnotation],
+			SourceBranch: snapshot.GetAnnotations()[gitops.PipelineAsCodeSourceBranchAnnotation],
+			SHA:          snapshot.GetLabels()[gitops.PipelineAsCodeSHALabel],
+			PullRequest:  snapshot.GetAnnotations()[gitops.PipelineAsCodePullRequestAnnotation],
+		},
+		Results: exportedResults,
+	}, nil
+}
+
 // RequeueIfYoungerThanThreshold checks if the adapter' snapshot is younger than the threshold defined
 // in the function.  If it is, the function returns an operation result instructing the reconciler
 // to requeue the object and the error message passed to the function.  If not, the function returns
@@ -510,21 +1339,105 @@ func (a *Adapter) RequeueIfYoungerThanThreshold(retErr error) (controller.Operat
 	return controller.ContinueProcessing()
 }
 
-func (a *Adapter) HandlePipelineCreationError(err error, integrationTestScenario *v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) (controller.OperationResult, error) {
-	a.logger.Error(err, "Failed to create pipelineRun for snapshot and scenario",
-		"integrationScenario.Name", integrationTestScenario.Name)
-	testStatuses.UpdateTestStatusIfChanged(
-		integrationTestScenario.Name, intgteststat.IntegrationTestStatusTestInvalid,
-		fmt.Sprintf("Creation of pipelineRun failed during creation due to: %s.", err))
+func (a *Adapter) HandlePipelineCreationError(err error, scenarioTest h.ScenarioTest, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) (controller.OperationResult, error) {
+	retryAfter := a.recordPipelineRunCreationFailure(err, scenarioTest, testStatuses)
 	itsErr := gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client)
 	if itsErr != nil {
 		a.logger.Error(err, "Failed to write Test Status into Snapshot")
 		return controller.RequeueWithError(itsErr)
 	}
 
-	if clienterrors.IsInvalid(err) {
+	if retryAfter == 0 {
 		return controller.StopProcessing()
 	}
 
-	return controller.RequeueWithError(err)
+	return controller.RequeueAfter(retryAfter, nil)
+}
+
+// recordPipelineRunCreationFailure records the failure to create the pipelineRun of the test in its status and returns
+// the delay after which the creation should be retried. The permanent failures mark the test as TestInvalid and
+// a zero delay is returned, the transient failures keep the test Pending and are retried with an exponential backoff
+// until PipelineRunCreationMaxFailures is reached or the snapshot is older than SnapshotRetryTimeout
+func (a *Adapter) recordPipelineRunCreationFailure(err error, scenarioTest h.ScenarioTest, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) time.Duration {
+	a.logger.Error(err, "Failed to create pipelineRun for snapshot and scenario",
+		"integrationScenario.Name", h.GetScenarioNameFromTestName(scenarioTest.Name), "test.Name", scenarioTest.Name)
+	if isPermanentPipelineRunCreationError(err) {
+		testStatuses.UpdateTestStatusIfChanged(
+			scenarioTest.Name, intgteststat.IntegrationTestStatusTestInvalid,
+			fmt.Sprintf("Creation of pipelineRun failed during creation due to: %s.", err))
+		return 0
+	}
+
+	failures, recordErr := testStatuses.RecordTestCreationFailure(scenarioTest.Name, a.clock.Now())
+	if recordErr != nil {
+		a.logger.Error(recordErr, "Failed to record the pipelineRun creation failure in test status")
+		failures = 1
+	}
+	if failures >= PipelineRunCreationMaxFailures || !h.IsObjectYoungerThanThreshold(a.snapshot, SnapshotRetryTimeout) {
+		testStatuses.UpdateTestStatusIfChanged(
+			scenarioTest.Name, intgteststat.IntegrationTestStatusTestInvalid,
+			fmt.Sprintf("Creation of pipelineRun failed due to a transient error, giving up after %d attempts: %s.", failures, err))
+		return 0
+	}
+	retryAfter := getPipelineRunCreationRetryDelay(failures)
+	testStatuses.UpdateTestStatusIfChanged(
+		scenarioTest.Name, intgteststat.IntegrationTestStatusPending,
+		fmt.Sprintf("Creation of pipelineRun failed due to a transient error, retrying in %s (attempt %d): %s.", retryAfter, failures, err))
+	return retryAfter
+}
+
+// isPermanentPipelineRunCreationError returns true when retrying the creation of the pipelineRun can't succeed,
+// i.e. the pipelineRun was rejected by the validation or the creation is forbidden. Other errors, such as timeouts,
+// throttling or internal errors of the API server and its webhooks, are transient
+func isPermanentPipelineRunCreationError(err error) bool {
+	return clienterrors.IsInvalid(err) || clienterrors.IsBadRequest(err) || clienterrors.IsForbidden(err) ||
+		tekton.IsParamTemplateError(err) || tekton.IsParamValueSourceError(err)
+}
+
+// getPipelineRunCreationRetryDelay returns the delay after which the creation of the pipelineRun is retried
+// after the given number of consecutive transient failures
+func getPipelineRunCreationRetryDelay(failures int) time.Duration {
+	delay := PipelineRunCreationRetryBaseDelay
+	for i := 1; i < failures && delay < PipelineRunCreationRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, PipelineRunCreationRetryMaxDelay)
+}
+
+// getPipelineRunCreationBackoff returns the remaining time before the creation of the pipelineRun of the test
+// can be retried. It is derived from the failures recorded in the test status, so the backoff survives
+// the restarts of the controller
+func getPipelineRunCreationBackoff(testStatuses *intgteststat.SnapshotIntegrationTestStatuses, testName string, now time.Time) time.Duration {
+	detail, ok := testStatuses.GetScenarioStatus(testName)
+	if !ok || detail.CreationFailures == 0 || detail.LastCreationFailureTime == nil {
+		return 0
+	}
+	retryTime := detail.LastCreationFailureTime.Add(getPipelineRunCreationRetryDelay(detail.CreationFailures))
+	if !now.Before(retryTime) {
+		return 0
+	}
+	return retryTime.Sub(now)
+}
+
+// getPipelineRunBatchCapacity returns the number of integration pipelineRuns which can be created for the Snapshot now
+// and the delay until the next batch can be created when none can be created now. A negative capacity means
+// the creation of the pipelineRuns isn't staggered.
+func (a *Adapter) getPipelineRunBatchCapacity() (time.Duration, int) {
+	if PipelineRunCreationStagger <= 0 || PipelineRunCreationBatchSize <= 0 {
+		return 0, -1
+	}
+	if lastBatchTime, ok := gitops.GetPipelineRunBatchCreationTime(a.snapshot); ok {
+		if remaining := lastBatchTime.Add(PipelineRunCreationStagger).Sub(a.clock.Now()); remaining > 0 {
+			return remaining, 0
+		}
+	}
+	return 0, PipelineRunCreationBatchSize
+}
+
+// shortestRequeueDelay returns the shorter of the two delays, the zero delay means no requeue is needed yet
+func shortestRequeueDelay(current, delay time.Duration) time.Duration {
+	if current == 0 {
+		return delay
+	}
+	return min(current, delay)
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/tonglil/buflogr"
@@ -42,9 +43,13 @@ import (
 	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
+	"github.com/konflux-ci/integration-service/tekton"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -213,6 +218,7 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 					gitops.SnapshotTypeLabel:            "component",
 					gitops.SnapshotComponentLabel:       "component-sample",
 					gitops.PipelineAsCodeEventTypeLabel: "pull_request",
+					gitops.BuildTargetBranchLabel:       "release-1.0",
 				},
 				Annotations: map[string]string{
 					gitops.PipelineAsCodeInstallationIDAnnotation: "123",
@@ -318,6 +324,8 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
 			Expect(ok).To(BeTrue())
 			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(gitops.IsSnapshotStatusConditionSet(hasSnapshot, gitops.AppStudioTestsCompletedCondition,
+				metav1.ConditionFalse, gitops.AppStudioTestsCompletedConditionInProgress)).To(BeTrue())
 
 			integrationPipelineRuns := []tektonv1.PipelineRun{}
 			Eventually(func() error {
@@ -344,6 +352,1286 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("ensures the integrationTestPipelines aren't created for scenarios which don't select the snapshot component", func() {
+			skippedScenario := integrationTestScenario.DeepCopy()
+			skippedScenario.Name = "example-skipped"
+			skippedScenario.Spec.ComponentSelector = &v1beta2.ComponentSelector{
+				Names: []string{"other-component"},
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "ui"},
+				},
+			}
+			selectingScenario := integrationTestScenario.DeepCopy()
+			selectingScenario.Spec.ComponentSelector = &v1beta2.ComponentSelector{
+				Names: []string{hasComp.Name},
+			}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.SnapshotContextKey,
+					Resource:   hasSnapshot,
+				},
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*selectingScenario, *skippedScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*selectingScenario, *skippedScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario doesn't select the component of the snapshot"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(skippedScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			Expect(detail.CompletionTime).NotTo(BeNil())
+			detail, ok = statuses.GetScenarioStatus(selectingScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).NotTo(Equal(intgteststat.IntegrationTestStatusSkipped))
+		})
+
+		It("queues the integrationTestPipelines beyond the cap on running pipelineRuns as pending", func() {
+			firstScenario := integrationTestScenario.DeepCopy()
+			firstScenario.Name = "queued-first"
+			secondScenario := integrationTestScenario.DeepCopy()
+			secondScenario.Name = "queued-second"
+
+			pipelinerunqueue.DefaultQueue = pipelinerunqueue.NewQueue(1)
+			defer func() {
+				pipelinerunqueue.DefaultQueue = nil
+			}()
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*firstScenario, *secondScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*firstScenario, *secondScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(PipelineRunQueueRequeueDelay))
+			Expect(buf.String()).Should(ContainSubstring("The cap on running integration pipelineRuns was reached"))
+			Expect(pipelinerunqueue.DefaultQueue.Running()).To(Equal(1))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(firstScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+			detail, ok = statuses.GetScenarioStatus(secondScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			Expect(detail.Details).To(ContainSubstring("is queued until the number of running integration pipelineRuns drops below the cap"))
+
+			// the queued test is promoted once the running one releases its slot
+			pipelinerunqueue.DefaultQueue.Release(pipelinerunqueue.TestKey(hasSnapshot.Namespace, hasSnapshot.Name, firstScenario.Name))
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(secondScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+		})
+
+		It("queues the re-run beyond the cap on running pipelineRuns without removing the re-run label", func() {
+			pipelinerunqueue.DefaultQueue = pipelinerunqueue.NewQueue(1)
+			defer func() {
+				pipelinerunqueue.DefaultQueue = nil
+			}()
+			// the only slot is held by the test of another snapshot
+			otherTestKey := pipelinerunqueue.TestKey(hasSnapshot.Namespace, "other-snapshot", integrationTestScenario.Name)
+			pipelinerunqueue.DefaultQueue.Track(otherTestKey)
+
+			hasSnapshot.Labels[gitops.SnapshotIntegrationTestRun] = integrationTestScenario.Name
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.GetScenarioContextKey,
+					Resource:   integrationTestScenario,
+				},
+			})
+
+			result, err := adapter.EnsureRerunPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(PipelineRunQueueRequeueDelay))
+			Expect(buf.String()).Should(ContainSubstring("The cap on running integration pipelineRuns was reached, queueing the re-run"))
+			Expect(pipelinerunqueue.DefaultQueue.Running()).To(Equal(1))
+			Expect(hasSnapshot.Labels).To(HaveKeyWithValue(gitops.SnapshotIntegrationTestRun, integrationTestScenario.Name))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			Expect(detail.Details).To(ContainSubstring("is queued until the number of running integration pipelineRuns drops below the cap"))
+
+			// the queued re-run is retried once the slot is released
+			pipelinerunqueue.DefaultQueue.Release(otherTestKey)
+			result, err = adapter.EnsureRerunPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(pipelinerunqueue.DefaultQueue.Running()).To(Equal(1))
+			Expect(hasSnapshot.Labels).NotTo(HaveKey(gitops.SnapshotIntegrationTestRun))
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+		})
+
+		It("ensures the integrationTestPipelines of dependent scenarios are created after their dependencies pass", func() {
+			smokeScenario := integrationTestScenario.DeepCopy()
+			smokeScenario.Name = "dependency-smoke"
+			perfScenario := integrationTestScenario.DeepCopy()
+			perfScenario.Name = "dependency-perf"
+			perfScenario.Spec.DependsOn = []string{smokeScenario.Name}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*perfScenario, *smokeScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*perfScenario, *smokeScenario},
+				},
+			})
+
+			// the first reconcile creates only the pipelineRun of the dependency
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(ScenarioDependenciesRequeueDelay))
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario dependencies haven't passed yet"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(perfScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(detail.Details).To(Equal("IntegrationTestScenario 'dependency-perf' is waiting for IntegrationTestScenarios 'dependency-smoke' to pass"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			detail, ok = statuses.GetScenarioStatus(smokeScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+
+			// the dependent scenario keeps waiting while its dependency is in progress
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+
+			// the pipelineRun of the dependent scenario is created once its dependency passes
+			statuses.UpdateTestStatusIfChanged(smokeScenario.Name, intgteststat.IntegrationTestStatusTestPassed, "Integration test passed")
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, hasSnapshot, statuses, k8sClient)).To(Succeed())
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(perfScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+		})
+
+		It("ensures the results exported by the dependencies are passed to the dependent scenarios", func() {
+			provisionScenario := integrationTestScenario.DeepCopy()
+			provisionScenario.Name = "dependency-provision"
+			provisionScenario.Spec.ExportResults = []string{"CLUSTER_URL"}
+			e2eScenario := integrationTestScenario.DeepCopy()
+			e2eScenario.Name = "dependency-e2e"
+			e2eScenario.Spec.DependsOn = []string{provisionScenario.Name}
+			e2eScenario.Spec.Params = []v1beta2.PipelineParameter{
+				{Name: "cluster-url", Value: "{{ results.dependency-provision.CLUSTER_URL }}"},
+			}
+			upgradeScenario := integrationTestScenario.DeepCopy()
+			upgradeScenario.Name = "dependency-upgrade"
+			upgradeScenario.Spec.DependsOn = []string{provisionScenario.Name}
+			upgradeScenario.Spec.Params = []v1beta2.PipelineParameter{
+				{Name: "kubeconfig", Value: "{{ results.dependency-provision.KUBECONFIG }}"},
+			}
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			statuses.UpdateTestStatusIfChanged(provisionScenario.Name, intgteststat.IntegrationTestStatusTestPassed, "Integration test passed")
+			Expect(statuses.UpdateTestPipelineRunName(provisionScenario.Name, "dependency-provision-plr")).To(Succeed())
+			Expect(gitops.SetExportedResults(ctx, k8sClient, hasSnapshot, provisionScenario.Name,
+				map[string]string{"CLUSTER_URL": "https://api.cluster.example.com:6443"})).To(Succeed())
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, hasSnapshot, statuses, k8sClient)).To(Succeed())
+
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*e2eScenario, *upgradeScenario, *provisionScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*e2eScenario, *upgradeScenario, *provisionScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(e2eScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			pipelineRun := &tektonv1.PipelineRun{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: hasSnapshot.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)).To(Succeed())
+			Expect(pipelineRun.Spec.Params).To(ContainElement(SatisfyAll(
+				HaveField("Name", "cluster-url"),
+				HaveField("Value.StringVal", "https://api.cluster.example.com:6443"),
+			)))
+
+			// the dependent scenario referencing a result which wasn't exported is invalid
+			detail, ok = statuses.GetScenarioStatus(upgradeScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestInvalid))
+			Expect(detail.Details).To(ContainSubstring("result KUBECONFIG wasn't exported by scenario dependency-provision"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+		})
+
+		It("backs off the creation of the pipelineRun after transient failures", func() {
+			backoffScenario := integrationTestScenario.DeepCopy()
+			backoffScenario.Name = "creation-backoff"
+			backoffSnapshot := hasSnapshot.DeepCopy()
+			backoffSnapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      "snapshot-creation-backoff",
+				Namespace: "default",
+				Labels:    hasSnapshot.Labels,
+			}
+			backoffSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+			Expect(k8sClient.Create(ctx, backoffSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, backoffSnapshot)).Should(Succeed())
+			}()
+
+			failingClient := &failingPipelineRunClient{Client: k8sClient, err: errors.NewTimeoutError("failed calling webhook", 1)}
+			// the failure times are recorded with a second precision
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now().Truncate(time.Second))
+			newBackoffAdapter := func(c client.Client) *Adapter {
+				backoffAdapter := NewAdapter(ctx, backoffSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), c)
+				backoffAdapter.clock = fakeClock
+				backoffAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+					{
+						ContextKey: loader.AllIntegrationTestScenariosContextKey,
+						Resource:   []v1beta2.IntegrationTestScenario{*backoffScenario},
+					},
+					{
+						ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+						Resource:   []v1beta2.IntegrationTestScenario{*backoffScenario},
+					},
+				})
+				return backoffAdapter
+			}
+			getDetail := func() *intgteststat.IntegrationTestStatusDetail {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(backoffSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				detail, ok := statuses.GetScenarioStatus(backoffScenario.Name)
+				Expect(ok).To(BeTrue())
+				return detail
+			}
+			// simulates the passing of the backoff, the state is read from the snapshot as after a restart
+			expireBackoff := func() {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(backoffSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				detail, ok := statuses.GetScenarioStatus(backoffScenario.Name)
+				Expect(ok).To(BeTrue())
+				expired := detail.LastCreationFailureTime.Add(-time.Hour)
+				detail.LastCreationFailureTime = &expired
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, backoffSnapshot, statuses, k8sClient)).To(Succeed())
+			}
+
+			result, err := newBackoffAdapter(failingClient).EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(PipelineRunCreationRetryBaseDelay))
+			Expect(failingClient.attempts).To(Equal(1))
+			detail := getDetail()
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(detail.Details).To(ContainSubstring("transient error"))
+			Expect(detail.CreationFailures).To(Equal(1))
+
+			// the creation isn't retried before the backoff passes
+			fakeClock.SetTime(fakeClock.Now().Add(time.Second))
+			result, err = newBackoffAdapter(failingClient).EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(PipelineRunCreationRetryBaseDelay - time.Second))
+			Expect(failingClient.attempts).To(Equal(1))
+
+			// the backoff doubles with each consecutive failure
+			expireBackoff()
+			result, err = newBackoffAdapter(failingClient).EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueDelay).To(Equal(2 * PipelineRunCreationRetryBaseDelay))
+			Expect(failingClient.attempts).To(Equal(2))
+			Expect(getDetail().CreationFailures).To(Equal(2))
+
+			expireBackoff()
+			result, err = newBackoffAdapter(k8sClient).EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			detail = getDetail()
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+			Expect(detail.CreationFailures).To(BeZero())
+			Expect(detail.LastCreationFailureTime).To(BeNil())
+		})
+
+		It("gives up the creation of the pipelineRun after too many transient failures", func() {
+			giveUpScenario := integrationTestScenario.DeepCopy()
+			giveUpScenario.Name = "creation-give-up"
+			giveUpSnapshot := hasSnapshot.DeepCopy()
+			giveUpSnapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      "snapshot-creation-give-up",
+				Namespace: "default",
+				Labels:    hasSnapshot.Labels,
+			}
+			giveUpSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+			Expect(k8sClient.Create(ctx, giveUpSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, giveUpSnapshot)).Should(Succeed())
+			}()
+
+			// the previous attempts failed and their backoff passed
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(giveUpSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			statuses.UpdateTestStatusIfChanged(giveUpScenario.Name, intgteststat.IntegrationTestStatusPending, "Pending")
+			detail, ok := statuses.GetScenarioStatus(giveUpScenario.Name)
+			Expect(ok).To(BeTrue())
+			lastFailureTime := time.Now().Add(-time.Hour)
+			detail.CreationFailures = PipelineRunCreationMaxFailures - 1
+			detail.LastCreationFailureTime = &lastFailureTime
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, giveUpSnapshot, statuses, k8sClient)).To(Succeed())
+
+			failingClient := &failingPipelineRunClient{Client: k8sClient, err: errors.NewInternalError(fmt.Errorf("failed calling webhook"))}
+			giveUpAdapter := NewAdapter(ctx, giveUpSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), failingClient)
+			giveUpAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*giveUpScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*giveUpScenario},
+				},
+			})
+
+			result, err := giveUpAdapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(failingClient.attempts).To(Equal(1))
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(giveUpSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(giveUpScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestInvalid))
+			Expect(detail.Details).To(ContainSubstring(fmt.Sprintf("giving up after %d attempts", PipelineRunCreationMaxFailures)))
+		})
+
+		It("marks the snapshot as invalid when its component no longer exists", func() {
+			missingSnapshot := hasSnapshot.DeepCopy()
+			missingSnapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      "snapshot-missing-component",
+				Namespace: "default",
+				Labels:    hasSnapshot.Labels,
+			}
+			missingSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+			Expect(k8sClient.Create(ctx, missingSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, missingSnapshot)).Should(Succeed())
+			}()
+
+			missingAdapter := NewAdapter(ctx, missingSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), k8sClient)
+			missingAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*integrationTestScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*integrationTestScenario},
+				},
+				{
+					ContextKey: loader.GetComponentContextKey,
+					Err:        errors.NewNotFound(applicationapiv1alpha1.GroupVersion.WithResource("components").GroupResource(), hasComp.Name),
+				},
+			})
+
+			result, err := missingAdapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Expect(gitops.IsSnapshotMarkedAsInvalid(missingSnapshot)).To(BeTrue())
+			condition := meta.FindStatusCondition(missingSnapshot.Status.Conditions, gitops.AppStudioIntegrationStatusCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Message).To(ContainSubstring(hasComp.Name))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(missingSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestError))
+			Expect(detail.Details).To(ContainSubstring(fmt.Sprintf("components %s of the snapshot no longer exist", hasComp.Name)))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+
+			pipelineRuns, err := getAllIntegrationPipelineRunsForSnapshot(ctx, missingSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pipelineRuns).To(BeEmpty())
+		})
+
+		It("doesn't invalidate the snapshot when its component is deleted after the pipelineRuns exist", func() {
+			runningSnapshot := hasSnapshot.DeepCopy()
+			runningSnapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      "snapshot-component-deleted-later",
+				Namespace: "default",
+				Labels:    hasSnapshot.Labels,
+			}
+			runningSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+			Expect(k8sClient.Create(ctx, runningSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, runningSnapshot)).Should(Succeed())
+			}()
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(runningSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			statuses.UpdateTestStatusIfChanged(integrationTestScenario.Name, intgteststat.IntegrationTestStatusInProgress, "Integration test is running")
+			Expect(statuses.UpdateTestPipelineRunName(integrationTestScenario.Name, "component-deleted-later-plr")).To(Succeed())
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, runningSnapshot, statuses, k8sClient)).To(Succeed())
+
+			runningAdapter := NewAdapter(ctx, runningSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), k8sClient)
+			runningAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*integrationTestScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*integrationTestScenario},
+				},
+				{
+					ContextKey: loader.GetComponentContextKey,
+					Err:        errors.NewNotFound(applicationapiv1alpha1.GroupVersion.WithResource("components").GroupResource(), hasComp.Name),
+				},
+			})
+
+			result, err := runningAdapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+
+			Expect(gitops.IsSnapshotMarkedAsInvalid(runningSnapshot)).To(BeFalse())
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(runningSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).To(Equal("component-deleted-later-plr"))
+		})
+
+		It("marks the test as invalid when the creation of the pipelineRun fails permanently", func() {
+			forbiddenScenario := integrationTestScenario.DeepCopy()
+			forbiddenScenario.Name = "creation-forbidden"
+			forbiddenSnapshot := hasSnapshot.DeepCopy()
+			forbiddenSnapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      "snapshot-creation-forbidden",
+				Namespace: "default",
+				Labels:    hasSnapshot.Labels,
+			}
+			forbiddenSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+			Expect(k8sClient.Create(ctx, forbiddenSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, forbiddenSnapshot)).Should(Succeed())
+			}()
+
+			failingClient := &failingPipelineRunClient{
+				Client: k8sClient,
+				err: errors.NewForbidden(tektonv1.Resource("pipelineruns"), "",
+					fmt.Errorf("exceeded quota: pipelineruns-quota")),
+			}
+			forbiddenAdapter := NewAdapter(ctx, forbiddenSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), failingClient)
+			forbiddenAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*forbiddenScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*forbiddenScenario},
+				},
+			})
+
+			result, err := forbiddenAdapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(failingClient.attempts).To(Equal(1))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(forbiddenSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(forbiddenScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestInvalid))
+			Expect(detail.Details).To(ContainSubstring("exceeded quota: pipelineruns-quota"))
+			Expect(detail.CreationFailures).To(BeZero())
+		})
+
+		Context("when the test results of a snapshot with identical components can be reused", func() {
+			var (
+				reuseSnapshot, reusedSnapshot                                  *applicationapiv1alpha1.Snapshot
+				passedScenario, optionalFailedScenario, requiredFailedScenario *v1beta2.IntegrationTestScenario
+			)
+
+			BeforeEach(func() {
+				passedScenario = integrationTestScenario.DeepCopy()
+				passedScenario.Name = "reuse-passed"
+				optionalFailedScenario = integrationTestScenario.DeepCopy()
+				optionalFailedScenario.Name = "reuse-optional-failed"
+				optionalFailedScenario.Labels = map[string]string{tekton.OptionalLabel: "true"}
+				requiredFailedScenario = integrationTestScenario.DeepCopy()
+				requiredFailedScenario.Name = "reuse-required-failed"
+				for _, scenario := range []*v1beta2.IntegrationTestScenario{passedScenario, optionalFailedScenario, requiredFailedScenario} {
+					scenario.Generation = 2
+				}
+
+				reuseSnapshot = hasSnapshot.DeepCopy()
+				reuseSnapshot.ObjectMeta = metav1.ObjectMeta{
+					Name:      "snapshot-reuse",
+					Namespace: "default",
+					Labels:    hasSnapshot.Labels,
+				}
+				reuseSnapshot.Status = applicationapiv1alpha1.SnapshotStatus{}
+				Expect(k8sClient.Create(ctx, reuseSnapshot)).Should(Succeed())
+
+				// the earlier snapshot of another pull request with identical components which finished testing
+				reusedSnapshot = reuseSnapshot.DeepCopy()
+				reusedSnapshot.Name = "snapshot-reused"
+				reusedSnapshot.CreationTimestamp = metav1.NewTime(reuseSnapshot.CreationTimestamp.Add(-time.Hour))
+				meta.SetStatusCondition(&reusedSnapshot.Status.Conditions, metav1.Condition{
+					Type:   gitops.AppStudioTestSucceededCondition,
+					Status: metav1.ConditionFalse,
+					Reason: gitops.AppStudioTestSucceededConditionFailed,
+				})
+				reusedStatuses, err := intgteststat.NewSnapshotIntegrationTestStatuses("")
+				Expect(err).ToNot(HaveOccurred())
+				for scenarioName, status := range map[string]intgteststat.IntegrationTestStatus{
+					passedScenario.Name:         intgteststat.IntegrationTestStatusTestPassed,
+					optionalFailedScenario.Name: intgteststat.IntegrationTestStatusTestFail,
+					requiredFailedScenario.Name: intgteststat.IntegrationTestStatusTestFail,
+				} {
+					reusedStatuses.UpdateTestStatusIfChanged(scenarioName, status, "Integration test finished")
+					Expect(reusedStatuses.UpdateTestPipelineRunName(scenarioName, scenarioName+"-plr")).To(Succeed())
+					Expect(reusedStatuses.UpdateTestScenarioGeneration(scenarioName, 2)).To(Succeed())
+				}
+				value, err := gitops.EncodeIntegrationTestStatuses(reusedStatuses)
+				Expect(err).ToNot(HaveOccurred())
+				reusedSnapshot.Annotations = map[string]string{gitops.SnapshotTestsStatusAnnotation: value}
+			})
+
+			AfterEach(func() {
+				Expect(k8sClient.Delete(ctx, reuseSnapshot)).Should(Succeed())
+			})
+
+			newReuseAdapter := func(application *applicationapiv1alpha1.Application) *Adapter {
+				reuseAdapter := NewAdapter(ctx, reuseSnapshot, application, hasComp, logger, loader.NewMockLoader(), k8sClient)
+				scenarios := []v1beta2.IntegrationTestScenario{*passedScenario, *optionalFailedScenario, *requiredFailedScenario}
+				reuseAdapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+					{
+						ContextKey: loader.AllIntegrationTestScenariosContextKey,
+						Resource:   scenarios,
+					},
+					{
+						ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+						Resource:   []v1beta2.IntegrationTestScenario{*passedScenario, *requiredFailedScenario},
+					},
+					{
+						ContextKey: loader.AllSnapshotsContextKey,
+						Resource:   []applicationapiv1alpha1.Snapshot{*reusedSnapshot, *reuseSnapshot},
+					},
+				})
+				return reuseAdapter
+			}
+			getDetail := func(scenarioName string) *intgteststat.IntegrationTestStatusDetail {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(reuseSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				detail, ok := statuses.GetScenarioStatus(scenarioName)
+				Expect(ok).To(BeTrue())
+				return detail
+			}
+
+			It("reuses the passed and optional results and runs the failed required scenarios fresh", func() {
+				reuseApp := hasApp.DeepCopy()
+				reuseApp.Annotations = map[string]string{gitops.ReuseTestResultsAnnotation: "true"}
+
+				result, err := newReuseAdapter(reuseApp).EnsureIntegrationPipelineRunsExist()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.CancelRequest).To(BeFalse())
+
+				for _, scenarioName := range []string{passedScenario.Name, optionalFailedScenario.Name} {
+					detail := getDetail(scenarioName)
+					Expect(detail.ReusedFrom).To(Equal(reusedSnapshot.Name))
+					Expect(detail.TestPipelineRunName).To(Equal(scenarioName + "-plr"))
+					Expect(detail.Details).To(HavePrefix("Result reused from snapshot " + reusedSnapshot.Name))
+				}
+				Expect(getDetail(passedScenario.Name).Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+				Expect(getDetail(optionalFailedScenario.Name).Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+
+				detail := getDetail(requiredFailedScenario.Name)
+				Expect(detail.ReusedFrom).To(BeEmpty())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+				Expect(detail.TestPipelineRunName).NotTo(Equal(requiredFailedScenario.Name + "-plr"))
+				pipelineRun := &tektonv1.PipelineRun{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: reuseSnapshot.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, pipelineRun)).To(Succeed())
+			})
+
+			It("doesn't reuse the results of the scenarios which changed since", func() {
+				reuseApp := hasApp.DeepCopy()
+				reuseApp.Annotations = map[string]string{gitops.ReuseTestResultsAnnotation: "true"}
+				passedScenario.Generation = 3
+
+				result, err := newReuseAdapter(reuseApp).EnsureIntegrationPipelineRunsExist()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.CancelRequest).To(BeFalse())
+
+				Expect(getDetail(optionalFailedScenario.Name).ReusedFrom).To(Equal(reusedSnapshot.Name))
+				for _, scenarioName := range []string{passedScenario.Name, requiredFailedScenario.Name} {
+					detail := getDetail(scenarioName)
+					Expect(detail.ReusedFrom).To(BeEmpty())
+					Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+					pipelineRun := &tektonv1.PipelineRun{}
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: reuseSnapshot.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)).To(Succeed())
+					Expect(k8sClient.Delete(ctx, pipelineRun)).To(Succeed())
+				}
+				Expect(getDetail(passedScenario.Name).ScenarioGeneration).To(Equal(int64(3)))
+			})
+
+			It("doesn't reuse the results unless the application opted in", func() {
+				result, err := newReuseAdapter(hasApp).EnsureIntegrationPipelineRunsExist()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.CancelRequest).To(BeFalse())
+
+				for _, scenarioName := range []string{passedScenario.Name, optionalFailedScenario.Name, requiredFailedScenario.Name} {
+					detail := getDetail(scenarioName)
+					Expect(detail.ReusedFrom).To(BeEmpty())
+					Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+					pipelineRun := &tektonv1.PipelineRun{}
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: reuseSnapshot.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)).To(Succeed())
+					Expect(k8sClient.Delete(ctx, pipelineRun)).To(Succeed())
+				}
+			})
+		})
+
+		It("ensures the required scenarios depending on a failing optional scenario don't pass", func() {
+			failingScenario := integrationTestScenario.DeepCopy()
+			failingScenario.Name = "dependency-failing"
+			blockedScenario := integrationTestScenario.DeepCopy()
+			blockedScenario.Name = "dependency-blocked"
+			blockedScenario.Spec.DependsOn = []string{failingScenario.Name}
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			statuses.UpdateTestStatusIfChanged(failingScenario.Name, intgteststat.IntegrationTestStatusTestFail, "Integration test failed")
+			Expect(statuses.UpdateTestPipelineRunName(failingScenario.Name, "dependency-failing-plr")).To(Succeed())
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, hasSnapshot, statuses, k8sClient)).To(Succeed())
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*blockedScenario, *failingScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*blockedScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario dependency can't pass"))
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(blockedScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestError))
+			Expect(detail.Details).To(Equal("IntegrationTestScenario 'dependency-blocked' can't run because IntegrationTestScenario 'dependency-failing' it depends on didn't pass (TestFail)"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+
+			completion := gitops.GetRequiredTestsCompletion(hasSnapshot, &[]v1beta2.IntegrationTestScenario{*blockedScenario}, statuses)
+			Expect(completion.AllPassed()).To(BeFalse())
+		})
+
+		It("ensures the dependent scenarios are skipped when their dependency was skipped", func() {
+			suspendedScenario := integrationTestScenario.DeepCopy()
+			suspendedScenario.Name = "dependency-suspended"
+			skippedScenario := integrationTestScenario.DeepCopy()
+			skippedScenario.Name = "dependency-skipped"
+			skippedScenario.Spec.DependsOn = []string{suspendedScenario.Name}
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			statuses.UpdateTestStatusIfChanged(suspendedScenario.Name, intgteststat.IntegrationTestStatusSkipped, "IntegrationTestScenario 'dependency-suspended' was skipped because it is suspended")
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, hasSnapshot, statuses, k8sClient)).To(Succeed())
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*skippedScenario, *suspendedScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*skippedScenario, *suspendedScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario dependency was skipped"))
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(skippedScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.Details).To(Equal("IntegrationTestScenario 'dependency-skipped' was skipped because IntegrationTestScenario 'dependency-suspended' it depends on was skipped"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+		})
+
+		It("ensures the integrationTestPipelines are created only for scenarios with contexts applying to the snapshot", func() {
+			frontendScenario := integrationTestScenario.DeepCopy()
+			frontendScenario.Name = "context-frontend"
+			frontendScenario.Spec.Contexts = []v1beta2.TestContext{{Name: "component_frontend"}}
+			componentScenario := integrationTestScenario.DeepCopy()
+			componentScenario.Name = "context-component-sample"
+			componentScenario.Spec.Contexts = []v1beta2.TestContext{{Name: "pull_request"}, {Name: "component_component-sample"}}
+			unknownContextScenario := integrationTestScenario.DeepCopy()
+			unknownContextScenario.Name = "context-group"
+			unknownContextScenario.Spec.Contexts = []v1beta2.TestContext{{Name: "group"}}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*frontendScenario, *componentScenario, *unknownContextScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*frontendScenario, *componentScenario, *unknownContextScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario isn't applicable to the context of the snapshot"))
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario has unknown contexts, they are treated as applicable to the snapshot"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(frontendScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.Details).To(Equal("IntegrationTestScenario 'context-frontend' was skipped because none of its contexts apply to the snapshot"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			detail, ok = statuses.GetScenarioStatus(componentScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+			detail, ok = statuses.GetScenarioStatus(unknownContextScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+		})
+
+		It("ensures the integrationTestPipelines are not created for suspended scenarios", func() {
+			suspendedScenario := integrationTestScenario.DeepCopy()
+			suspendedScenario.Name = "suspended-scenario"
+			suspendedScenario.Spec.Suspend = true
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*suspendedScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*suspendedScenario},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario is suspended, will not create pipelineRun for it"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(suspendedScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.Details).To(Equal("IntegrationTestScenario 'suspended-scenario' was skipped because it is suspended"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+
+			// unsuspending the scenario doesn't retroactively run it for the snapshot
+			suspendedScenario.Spec.Suspend = false
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*suspendedScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*suspendedScenario},
+				},
+			})
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario was skipped for the snapshot"))
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(suspendedScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+		})
+
+		It("staggers the creation of the integrationTestPipelines in batches ordered by the scenario names", func() {
+			originalStagger, originalBatchSize := PipelineRunCreationStagger, PipelineRunCreationBatchSize
+			PipelineRunCreationStagger, PipelineRunCreationBatchSize = 2*time.Second, 3
+			DeferCleanup(func() {
+				PipelineRunCreationStagger, PipelineRunCreationBatchSize = originalStagger, originalBatchSize
+			})
+
+			scenarios := []v1beta2.IntegrationTestScenario{}
+			// listed in reverse order, the batches are created in the order of the names
+			for i := 7; i >= 1; i-- {
+				scenario := integrationTestScenario.DeepCopy()
+				scenario.Name = fmt.Sprintf("example-stagger-%d", i)
+				scenarios = append(scenarios, *scenario)
+			}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC))
+			adapter = NewAdapter(ctx, hasSnapshotPR, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.clock = fakeClock
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.SnapshotContextKey,
+					Resource:   hasSnapshotPR,
+				},
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+			})
+
+			expectCreatedScenarios := func(expected ...int) {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshotPR)
+				Expect(err).ToNot(HaveOccurred())
+				created := []int{}
+				for i := 1; i <= 7; i++ {
+					detail, ok := statuses.GetScenarioStatus(fmt.Sprintf("example-stagger-%d", i))
+					Expect(ok).To(BeTrue())
+					if detail.TestPipelineRunName != "" {
+						created = append(created, i)
+						continue
+					}
+					Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+					Expect(detail.Details).To(ContainSubstring("is waiting for the staggered creation of its pipelineRun"))
+				}
+				Expect(created).To(Equal(expected))
+			}
+
+			// the first batch is created right away
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(2 * time.Second))
+			expectCreatedScenarios(1, 2, 3)
+			batchTime, ok := gitops.GetPipelineRunBatchCreationTime(hasSnapshotPR)
+			Expect(ok).To(BeTrue())
+			Expect(batchTime.Equal(fakeClock.Now())).To(BeTrue())
+
+			// no batch is created before the stagger passes
+			fakeClock.SetTime(fakeClock.Now().Add(500 * time.Millisecond))
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(1500 * time.Millisecond))
+			expectCreatedScenarios(1, 2, 3)
+
+			fakeClock.SetTime(fakeClock.Now().Add(1500 * time.Millisecond))
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(2 * time.Second))
+			expectCreatedScenarios(1, 2, 3, 4, 5, 6)
+
+			// the last batch leaves nothing to stagger
+			fakeClock.SetTime(fakeClock.Now().Add(2 * time.Second))
+			result, err = adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			expectCreatedScenarios(1, 2, 3, 4, 5, 6, 7)
+		})
+
+		It("marks the snapshots of an application without IntegrationTestScenarios as passed and reports it only for pull requests", func() {
+			for _, snapshot := range []*applicationapiv1alpha1.Snapshot{hasSnapshotPR, hasSnapshot} {
+				var buf bytes.Buffer
+				log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+				adapter = NewAdapter(ctx, snapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+				adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+					{
+						ContextKey: loader.ApplicationContextKey,
+						Resource:   hasApp,
+					},
+					{
+						ContextKey: loader.ComponentContextKey,
+						Resource:   hasComp,
+					},
+					{
+						ContextKey: loader.SnapshotContextKey,
+						Resource:   snapshot,
+					},
+					{
+						ContextKey: loader.AllIntegrationTestScenariosContextKey,
+						Resource:   []v1beta2.IntegrationTestScenario{},
+					},
+					{
+						ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+						Resource:   []v1beta2.IntegrationTestScenario{},
+					},
+				})
+
+				result, err := adapter.EnsureIntegrationPipelineRunsExist()
+				Expect(result.CancelRequest).To(BeFalse())
+				Expect(result.RequeueRequest).To(BeFalse())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(gitops.IsSnapshotMarkedAsPassed(snapshot)).To(BeTrue())
+			}
+
+			Expect(gitops.IsSnapshotWithoutIntegrationTests(hasSnapshotPR)).To(BeTrue())
+			Expect(hasSnapshotPR.Annotations[gitops.SnapshotNoIntegrationTestsAnnotation]).To(
+				Equal(fmt.Sprintf("No IntegrationTestScenarios are configured for application %s", hasApp.Name)))
+			// the tests of push snapshots aren't reported to the git provider
+			Expect(gitops.IsSnapshotWithoutIntegrationTests(hasSnapshot)).To(BeFalse())
+		})
+
+		It("doesn't report missing integration tests when the scenarios of the application were skipped for the snapshot", func() {
+			pushScenario := integrationTestScenario.DeepCopy()
+			pushScenario.Spec.Contexts = []v1beta2.TestContext{{Name: gitops.PushContext}}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshotPR, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.SnapshotContextKey,
+					Resource:   hasSnapshotPR,
+				},
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*pushScenario},
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{},
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gitops.IsSnapshotWithoutIntegrationTests(hasSnapshotPR)).To(BeFalse())
+
+			// the skipped scenario is reported on its own
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshotPR)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(pushScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+		})
+
+		It("ensures the integrationTestPipelines are created only for scenarios matching the target branch of the snapshot", func() {
+			exactMatchScenario := integrationTestScenario.DeepCopy()
+			exactMatchScenario.Name = "example-exact-branch"
+			exactMatchScenario.Spec.TargetBranches = []string{"main", "release-1.0"}
+			regexMatchScenario := integrationTestScenario.DeepCopy()
+			regexMatchScenario.Name = "example-regex-branch"
+			regexMatchScenario.Spec.TargetBranches = []string{`release-\d+\.\d+`}
+			noMatchScenario := integrationTestScenario.DeepCopy()
+			noMatchScenario.Name = "example-other-branch"
+			noMatchScenario.Spec.TargetBranches = []string{"main", "release"}
+			scenarios := []v1beta2.IntegrationTestScenario{*exactMatchScenario, *regexMatchScenario, *noMatchScenario}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshotPR, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.SnapshotContextKey,
+					Resource:   hasSnapshotPR,
+				},
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).Should(ContainSubstring("IntegrationTestScenario doesn't apply to the target branch of the snapshot"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshotPR)
+			Expect(err).ToNot(HaveOccurred())
+			for _, scenario := range []*v1beta2.IntegrationTestScenario{exactMatchScenario, regexMatchScenario} {
+				detail, ok := statuses.GetScenarioStatus(scenario.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+				Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+			}
+			detail, ok := statuses.GetScenarioStatus(noMatchScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.Details).To(ContainSubstring("target branch 'release-1.0'"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+		})
+
+		It("ensures the integrationTestPipelines aren't created for scenarios with target branches when the snapshot has no target branch", func() {
+			branchScenario := integrationTestScenario.DeepCopy()
+			branchScenario.Name = "example-release-branch"
+			branchScenario.Spec.TargetBranches = []string{"release-.*"}
+			scenarios := []v1beta2.IntegrationTestScenario{*integrationTestScenario, *branchScenario}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.ApplicationContextKey,
+					Resource:   hasApp,
+				},
+				{
+					ContextKey: loader.ComponentContextKey,
+					Resource:   hasComp,
+				},
+				{
+					ContextKey: loader.SnapshotContextKey,
+					Resource:   hasSnapshot,
+				},
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(branchScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusSkipped))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			detail, ok = statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).NotTo(Equal(intgteststat.IntegrationTestStatusSkipped))
+		})
+
+		It("ensures an integrationTestPipeline is created and re-run for each matrix combination of the scenario", func() {
+			matrixScenario := integrationTestScenario.DeepCopy()
+			matrixScenario.Name = "example-matrix"
+			matrixScenario.Spec.Matrix = []v1beta2.MatrixParam{
+				{Name: "ocp", Values: []string{"4.15", "4.16"}},
+				{Name: "arch", Values: []string{"amd64", "arm64"}},
+			}
+			scenarios := []v1beta2.IntegrationTestScenario{*matrixScenario}
+
+			var buf bytes.Buffer
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter = NewAdapter(ctx, hasSnapshotPR, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.GetScenarioContextKey,
+					Resource:   matrixScenario,
+				},
+			})
+
+			result, err := adapter.EnsureIntegrationPipelineRunsExist()
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshotPR)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses.GetStatuses()).To(HaveLen(4))
+			_, ok := statuses.GetScenarioStatus(matrixScenario.Name)
+			Expect(ok).To(BeFalse())
+			pipelineRunNames := map[string]bool{}
+			for _, testName := range []string{"example-matrix[ocp=4.15,arch=amd64]", "example-matrix[ocp=4.15,arch=arm64]",
+				"example-matrix[ocp=4.16,arch=amd64]", "example-matrix[ocp=4.16,arch=arm64]"} {
+				detail, ok := statuses.GetScenarioStatus(testName)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+				Expect(detail.TestPipelineRunName).NotTo(BeEmpty())
+				pipelineRunNames[detail.TestPipelineRunName] = true
+			}
+			Expect(pipelineRunNames).To(HaveLen(4))
+
+			detail, _ := statuses.GetScenarioStatus("example-matrix[ocp=4.16,arch=arm64]")
+			pipelineRun := &tektonv1.PipelineRun{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: hasSnapshotPR.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)).To(Succeed())
+			Expect(pipelineRun.Labels["test.appstudio.openshift.io/scenario"]).To(Equal(matrixScenario.Name))
+			Expect(pipelineRun.Annotations["test.appstudio.openshift.io/test-status-name"]).To(Equal("example-matrix[ocp=4.16,arch=arm64]"))
+			Expect(pipelineRun.Spec.Params).To(ContainElements(
+				tektonv1.Param{Name: "ocp", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "4.16"}},
+				tektonv1.Param{Name: "arch", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "arm64"}},
+			))
+
+			// only the finished combination is re-run
+			detail, _ = statuses.GetScenarioStatus("example-matrix[ocp=4.15,arch=amd64]")
+			failedPipelineRunName := detail.TestPipelineRunName
+			statuses.UpdateTestStatusIfChanged("example-matrix[ocp=4.15,arch=amd64]", intgteststat.IntegrationTestStatusTestFail, "failed")
+			Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, hasSnapshotPR, statuses, k8sClient)).To(Succeed())
+			Expect(gitops.AddIntegrationTestRerunLabel(ctx, k8sClient, hasSnapshotPR, matrixScenario.Name)).To(Succeed())
+
+			result, err = adapter.EnsureRerunPipelineRunsExist()
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).Should(ContainSubstring("Found existing test in InProgress status, skipping re-run"))
+
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshotPR)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus("example-matrix[ocp=4.15,arch=amd64]")
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+			Expect(detail.TestPipelineRunName).NotTo(Equal(failedPipelineRunName))
+			Expect(pipelineRunNames).NotTo(HaveKey(detail.TestPipelineRunName))
+			for _, testName := range []string{"example-matrix[ocp=4.15,arch=arm64]", "example-matrix[ocp=4.16,arch=amd64]", "example-matrix[ocp=4.16,arch=arm64]"} {
+				detail, ok := statuses.GetScenarioStatus(testName)
+				Expect(ok).To(BeTrue())
+				Expect(pipelineRunNames).To(HaveKey(detail.TestPipelineRunName))
+			}
+			_, ok = hasSnapshotPR.GetLabels()[gitops.SnapshotIntegrationTestRun]
+			Expect(ok).To(BeFalse())
+		})
+
 		It("ensures global Component Image will not be updated in the PR context", func() {
 			err := gitops.MarkSnapshotAsPassed(ctx, k8sClient, hasSnapshotPR, "test passed")
 			Expect(err).To(Succeed())
@@ -402,6 +1690,116 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			Expect(buf.String()).Should(ContainSubstring(expectedLogEntry))
 		})
 
+		It("updates the global candidates of each component independently and records the failures", func() {
+			candidateComp := hasComp.DeepCopy()
+			candidateComp.ObjectMeta = metav1.ObjectMeta{
+				Name:      "component-candidate",
+				Namespace: "default",
+			}
+			candidateComp.Spec.ComponentName = "component-candidate"
+			candidateComp.Spec.ContainerImage = ""
+			candidateComp.Status = applicationapiv1alpha1.ComponentStatus{}
+			Expect(k8sClient.Create(ctx, candidateComp)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, candidateComp)).Should(Succeed())
+			}()
+
+			candidateSnapshot := &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-candidates",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotCompositeType,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePushType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{
+							Name:           candidateComp.Name,
+							ContainerImage: sample_image + "@sha256:12345",
+							Source: applicationapiv1alpha1.ComponentSource{
+								ComponentSourceUnion: applicationapiv1alpha1.ComponentSourceUnion{
+									GitSource: &applicationapiv1alpha1.GitSource{
+										Revision: sample_revision,
+									},
+								},
+							},
+						},
+						{
+							Name:           "component-missing",
+							ContainerImage: sample_image + "@sha256:67890",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, candidateSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, candidateSnapshot)).Should(Succeed())
+			}()
+			Expect(gitops.MarkSnapshotAsPassed(ctx, k8sClient, candidateSnapshot, "test passed")).To(Succeed())
+
+			// the status patch of the first component conflicts once with a concurrent update
+			conflictingClient := &conflictingComponentStatusClient{Client: k8sClient, conflicts: 1}
+			candidateAdapter := NewAdapter(ctx, candidateSnapshot, hasApp, nil, logger, loader.NewMockLoader(), conflictingClient)
+
+			result, err := candidateAdapter.EnsureGlobalCandidateImageUpdated()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(conflictingClient.conflicts).To(BeZero())
+
+			updatedComp := &applicationapiv1alpha1.Component{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(candidateComp), updatedComp)).To(Succeed())
+			Expect(updatedComp.Spec.ContainerImage).To(Equal(sample_image + "@sha256:12345"))
+			Expect(updatedComp.Status.LastBuiltCommit).To(Equal(sample_revision))
+
+			Expect(gitops.IsSnapshotMarkedAsAddedToGlobalCandidateList(candidateSnapshot)).To(BeTrue())
+			candidateUpdateErrors, err := gitops.GetSnapshotCandidateUpdateErrors(candidateSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidateUpdateErrors).To(HaveLen(1))
+			Expect(candidateUpdateErrors).To(HaveKey("component-missing"))
+		})
+
+		It("requeues the global candidate update when all the components failed", func() {
+			failingSnapshot := &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-candidates-failing",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotCompositeType,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePushType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{Name: "component-missing", ContainerImage: sample_image + "@sha256:67890"},
+						{Name: "component-missing-too", ContainerImage: sample_image + "@sha256:13579"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, failingSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, failingSnapshot)).Should(Succeed())
+			}()
+			Expect(gitops.MarkSnapshotAsPassed(ctx, k8sClient, failingSnapshot, "test passed")).To(Succeed())
+
+			failingAdapter := NewAdapter(ctx, failingSnapshot, hasApp, nil, logger, loader.NewMockLoader(), k8sClient)
+			result, err := failingAdapter.EnsureGlobalCandidateImageUpdated()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("component-missing"))
+			Expect(err.Error()).To(ContainSubstring("component-missing-too"))
+			Expect(result.RequeueRequest).To(BeTrue())
+
+			Expect(gitops.IsSnapshotMarkedAsAddedToGlobalCandidateList(failingSnapshot)).To(BeFalse())
+			candidateUpdateErrors, err := gitops.GetSnapshotCandidateUpdateErrors(failingSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidateUpdateErrors).To(HaveKey("component-missing"))
+			Expect(candidateUpdateErrors).To(HaveKey("component-missing-too"))
+		})
+
 		It("ensures Release created successfully", func() {
 			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
 
@@ -458,30 +1856,144 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			Expect(buf.String()).Should(ContainSubstring(expectedLogEntry))
 		})
 
-		It("ensures Snapshot labels/annotations prefixed with 'appstudio.openshift.io' are propagated to the release", func() {
-			releasePlans := []releasev1alpha1.ReleasePlan{*testReleasePlan}
-			releaseList := &releasev1alpha1.ReleaseList{}
-			err := adapter.createMissingReleasesForReleasePlans(hasApp, &releasePlans, hasSnapshot)
+		It("ensures Snapshot labels/annotations prefixed with 'appstudio.openshift.io' are propagated to the release", func() {
+			releasePlans := []releasev1alpha1.ReleasePlan{*testReleasePlan}
+			releaseList := &releasev1alpha1.ReleaseList{}
+			_, err := adapter.createMissingReleasesForReleasePlans(hasApp, &releasePlans, hasSnapshot)
+
+			Expect(err).To(BeNil())
+
+			opts := []client.ListOption{
+				client.InNamespace(hasApp.Namespace),
+				client.MatchingLabels{
+					"appstudio.openshift.io/component": hasComp.Name,
+					//"appstudio.openshift.io/application": hasApp.Name,
+				},
+			}
+
+			Eventually(func() error {
+				if err := k8sClient.List(adapter.context, releaseList, opts...); err != nil {
+					return err
+				}
+				if len(releaseList.Items) > 0 {
+					Expect(releaseList.Items[0].ObjectMeta.Labels["appstudio.openshift.io/component"]).To(Equal(hasComp.Name))
+				}
+				return nil
+			}, time.Second*10).Should(BeNil())
+		})
+
+		It("creates Releases only for the matched ReleasePlans and records them on the Snapshot", func() {
+			releaseSnapshot := &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-auto-release",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel: gitops.SnapshotComponentType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+				},
+			}
+			Expect(k8sClient.Create(ctx, releaseSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, releaseSnapshot)).Should(Succeed())
+			}()
+			Expect(gitops.MarkSnapshotIntegrationStatusAsFinished(ctx, k8sClient, releaseSnapshot, "all tests finished")).To(Succeed())
+			Expect(gitops.MarkSnapshotAsPassed(ctx, k8sClient, releaseSnapshot, "test passed")).To(Succeed())
+
+			newReleasePlan := func(name, application, target string) releasev1alpha1.ReleasePlan {
+				return releasev1alpha1.ReleasePlan{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec:       releasev1alpha1.ReleasePlanSpec{Application: application, Target: target},
+				}
+			}
+			releasePlans := []releasev1alpha1.ReleasePlan{
+				newReleasePlan("release-plan-1", hasApp.Name, "default"),
+				newReleasePlan("release-plan-failing", hasApp.Name, "default"),
+				newReleasePlan("release-plan-2", hasApp.Name, "default"),
+				newReleasePlan("release-plan-other-app", "other-application", "default"),
+				newReleasePlan("release-plan-no-target", hasApp.Name, ""),
+			}
+			failingClient := &failingReleaseClient{Client: k8sClient, failingReleasePlan: "release-plan-failing"}
+
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			newAutoReleaseAdapter := func(c client.Client) *Adapter {
+				adapter := NewAdapter(ctx, releaseSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), c)
+				adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+					{
+						ContextKey: loader.AutoReleasePlansContextKey,
+						Resource:   releasePlans,
+					},
+				})
+				return adapter
+			}
+			listReleases := func() []releasev1alpha1.Release {
+				releases := &releasev1alpha1.ReleaseList{}
+				Expect(k8sClient.List(ctx, releases, client.InNamespace("default"),
+					client.MatchingFields{"spec.snapshot": releaseSnapshot.Name})).To(Succeed())
+				return releases.Items
+			}
+
+			// the failure to create the Release of one ReleasePlan doesn't prevent creating the others
+			result, err := newAutoReleaseAdapter(failingClient).EnsureAllReleasesExist()
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(gitops.IsSnapshotMarkedAsAutoReleased(releaseSnapshot)).To(BeFalse())
+			Eventually(listReleases, time.Second*10).Should(HaveLen(2))
+			releaseNames := []string{}
+			for _, release := range listReleases() {
+				Expect(release.Spec.ReleasePlan).To(BeElementOf("release-plan-1", "release-plan-2"))
+				releaseNames = append(releaseNames, release.Name)
+			}
+			Expect(strings.Split(releaseSnapshot.GetAnnotations()[gitops.SnapshotAutoReleasesAnnotation], ",")).To(ConsistOf(releaseNames))
 
-			Expect(err).To(BeNil())
+			// the requeue creates only the missing Release
+			result, err = newAutoReleaseAdapter(k8sClient).EnsureAllReleasesExist()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(gitops.IsSnapshotMarkedAsAutoReleased(releaseSnapshot)).To(BeTrue())
+			Eventually(listReleases, time.Second*10).Should(HaveLen(3))
+			releaseNames = []string{}
+			for _, release := range listReleases() {
+				releaseNames = append(releaseNames, release.Name)
+				Expect(k8sClient.Delete(ctx, &release)).To(Succeed())
+			}
+			Expect(strings.Split(releaseSnapshot.GetAnnotations()[gitops.SnapshotAutoReleasesAnnotation], ",")).To(ConsistOf(releaseNames))
+		})
 
-			opts := []client.ListOption{
-				client.InNamespace(hasApp.Namespace),
-				client.MatchingLabels{
-					"appstudio.openshift.io/component": hasComp.Name,
-					//"appstudio.openshift.io/application": hasApp.Name,
+		It("records on the Snapshot that no ReleasePlan matched", func() {
+			releaseSnapshot := &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-no-auto-release",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel: gitops.SnapshotComponentType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
 				},
 			}
+			Expect(k8sClient.Create(ctx, releaseSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, releaseSnapshot)).Should(Succeed())
+			}()
+			Expect(gitops.MarkSnapshotIntegrationStatusAsFinished(ctx, k8sClient, releaseSnapshot, "all tests finished")).To(Succeed())
+			Expect(gitops.MarkSnapshotAsPassed(ctx, k8sClient, releaseSnapshot, "test passed")).To(Succeed())
 
-			Eventually(func() error {
-				if err := k8sClient.List(adapter.context, releaseList, opts...); err != nil {
-					return err
-				}
-				if len(releaseList.Items) > 0 {
-					Expect(releaseList.Items[0].ObjectMeta.Labels["appstudio.openshift.io/component"]).To(Equal(hasComp.Name))
-				}
-				return nil
-			}, time.Second*10).Should(BeNil())
+			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+			adapter := NewAdapter(ctx, releaseSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AutoReleasePlansContextKey,
+					Resource:   []releasev1alpha1.ReleasePlan{},
+				},
+			})
+			result, err := adapter.EnsureAllReleasesExist()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(releaseSnapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotAutoReleasesAnnotation, gitops.NoAutoReleasePlanMatched))
 		})
 
 		It("no action when EnsureAllReleasesExist function runs when AppStudio Tests failed and the snapshot is invalid", func() {
@@ -513,7 +2025,7 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 		})
 
 		It("ensures build labels/annotations prefixed with 'build.appstudio' are propagated from snapshot to Integration test PLR", func() {
-			pipelineRun, err := adapter.createIntegrationPipelineRun(hasApp, integrationTestScenario, hasSnapshot)
+			pipelineRun, err := adapter.createIntegrationPipelineRun(hasApp, integrationTestScenario, helpers.ScenarioTest{Name: integrationTestScenario.Name}, hasSnapshot)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun).ToNot(BeNil())
 
@@ -527,7 +2039,7 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 		})
 
 		It("ensures build labels/annotations non-prefixed with 'build.appstudio' are NOT propagated from snapshot to Integration test PLR", func() {
-			pipelineRun, err := adapter.createIntegrationPipelineRun(hasApp, integrationTestScenario, hasSnapshot)
+			pipelineRun, err := adapter.createIntegrationPipelineRun(hasApp, integrationTestScenario, helpers.ScenarioTest{Name: integrationTestScenario.Name}, hasSnapshot)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun).ToNot(BeNil())
 
@@ -939,6 +2451,71 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			})
 		})
 
+		When("scheduled run of scenario is trigerred", func() {
+			BeforeEach(func() {
+				var (
+					buf bytes.Buffer
+				)
+
+				// add rerun label and scheduled run annotation
+				// we cannot update it into k8s DB via patch, it would trigger reconciliation in background
+				// and test wouldn't test anything
+				hasSnapshot.Labels[gitops.SnapshotIntegrationTestRun] = integrationTestScenario.Name
+				hasSnapshot.Annotations[gitops.SnapshotScheduledRunAnnotation] = integrationTestScenario.Name
+
+				log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
+				adapter = NewAdapter(ctx, hasSnapshot, hasApp, hasComp, log, loader.NewMockLoader(), k8sClient)
+				adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+					{
+						ContextKey: loader.ApplicationContextKey,
+						Resource:   hasApp,
+					},
+					{
+						ContextKey: loader.ComponentContextKey,
+						Resource:   hasComp,
+					},
+					{
+						ContextKey: loader.SnapshotContextKey,
+						Resource:   hasSnapshot,
+					},
+					{
+						ContextKey: loader.EnvironmentContextKey,
+						Resource:   env,
+					},
+					{
+						ContextKey: loader.SnapshotComponentsContextKey,
+						Resource:   []applicationapiv1alpha1.Component{*hasComp},
+					},
+					{
+						ContextKey: loader.GetScenarioContextKey,
+						Resource:   integrationTestScenario,
+					},
+				})
+			})
+
+			It("creates integration test labeled as scheduled run", func() {
+				result, err := adapter.EnsureRerunPipelineRunsExist()
+				Expect(err).To(Succeed())
+				Expect(result.CancelRequest).To(BeFalse())
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).To(Succeed())
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Scheduled).To(BeTrue())
+				Expect(detail.Details).To(ContainSubstring("for the scheduled run"))
+
+				pipelineRun := &tektonv1.PipelineRun{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Namespace: hasSnapshot.Namespace, Name: detail.TestPipelineRunName}, pipelineRun)
+				}, time.Second*10).Should(Succeed())
+				Expect(pipelineRun.Labels).To(HaveKeyWithValue("test.appstudio.openshift.io/scheduled-run", "true"))
+
+				Expect(hasSnapshot.GetLabels()).NotTo(HaveKey(gitops.SnapshotIntegrationTestRun))
+				Expect(hasSnapshot.GetAnnotations()).NotTo(HaveKey(gitops.SnapshotScheduledRunAnnotation))
+			})
+		})
+
 		When("manual re-run of scenario using ephemeral] env is trigerred", func() {
 			BeforeEach(func() {
 				var (
@@ -1046,10 +2623,24 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 				fakeDetails string = "Lorem ipsum sit dolor mit amet"
 			)
 			var (
-				buf bytes.Buffer
+				buf                bytes.Buffer
+				runningPipelineRun *tektonv1.PipelineRun
 			)
 
 			BeforeEach(func() {
+				runningPipelineRun = &tektonv1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fakePLRName,
+						Namespace: "default",
+					},
+					Spec: tektonv1.PipelineRunSpec{
+						PipelineRef: &tektonv1.PipelineRef{
+							Name: "integration-pipeline",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, runningPipelineRun)).Should(Succeed())
+
 				// mock that test for scenario is already in progress by setting it in annotation
 				statuses, err := intgteststat.NewSnapshotIntegrationTestStatuses("")
 				Expect(err).To(Succeed())
@@ -1105,16 +2696,303 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 				Expect(detail.Status).Should(Equal(intgteststat.IntegrationTestStatusInProgress))
 				Expect(detail.Details).Should(Equal(fakeDetails))
 				Expect(detail.TestPipelineRunName).Should(Equal(fakePLRName))
+				Expect(detail.Attempt).Should(Equal(1))
 
 				m := MatchKeys(IgnoreExtras, Keys{
 					gitops.SnapshotIntegrationTestRun: Equal(integrationTestScenario.Name),
 				})
 				Expect(hasSnapshot.GetLabels()).ShouldNot(m, "shouln't have re-run label after re-running scenario")
+				Expect(hasSnapshot.GetAnnotations()[gitops.SnapshotRerunRefusedAnnotation]).To(And(
+					ContainSubstring(fmt.Sprintf("%s (pipelineRun %s)", integrationTestScenario.Name, fakePLRName)),
+					ContainSubstring(gitops.SnapshotIntegrationTestRunForceLabel),
+				))
+
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: runningPipelineRun.Namespace, Name: fakePLRName}, runningPipelineRun)).To(Succeed())
+				Expect(runningPipelineRun.Spec.Status).To(BeEmpty())
+			})
+
+			It("cancels the running test and creates new test when the re-run is forced", func() {
+				hasSnapshot.Labels[gitops.SnapshotIntegrationTestRunForceLabel] = "true"
+
+				result, err := adapter.EnsureRerunPipelineRunsExist()
+				Expect(err).To(Succeed())
+				Expect(result.CancelRequest).To(BeFalse())
+
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: runningPipelineRun.Namespace, Name: fakePLRName}, runningPipelineRun)).To(Succeed())
+				Expect(runningPipelineRun.Spec.Status).To(Equal(tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelledRunFinally)))
+
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(hasSnapshot)
+				Expect(err).To(Succeed())
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				Expect(ok).To(BeTrue())
+				Expect(detail.Status).Should(Equal(intgteststat.IntegrationTestStatusInProgress))
+				Expect(detail.TestPipelineRunName).ShouldNot(Equal(fakePLRName))
+				Expect(detail.Attempt).Should(Equal(2))
+				// the attempt of the cancelled pipelineRun is kept in the history of the test
+				Expect(detail.Attempts).To(HaveLen(2))
+				Expect(detail.Attempts[0].TestPipelineRunName).To(Equal(fakePLRName))
+				Expect(detail.Attempts[0].Details).To(Equal(fakeDetails))
+				Expect(detail.Attempts[1].TestPipelineRunName).To(Equal(detail.TestPipelineRunName))
+				Expect(detail.Attempts[1].Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+
+				Expect(hasSnapshot.GetLabels()).NotTo(HaveKey(gitops.SnapshotIntegrationTestRun))
+				Expect(hasSnapshot.GetLabels()).NotTo(HaveKey(gitops.SnapshotIntegrationTestRunForceLabel))
+				Expect(hasSnapshot.GetAnnotations()).NotTo(HaveKey(gitops.SnapshotRerunRefusedAnnotation))
+			})
 
+			AfterEach(func() {
+				err := k8sClient.Delete(ctx, runningPipelineRun)
+				Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
 			})
 		})
 	})
 
+	Describe("EnsurePullRequestSnapshotsObsoletedByOverride", func() {
+		var (
+			obsoletedSnapshot, otherComponentSnapshot, overrideSnapshot *applicationapiv1alpha1.Snapshot
+			obsoletedPipelineRun                                        *tektonv1.PipelineRun
+		)
+
+		newPullRequestSnapshot := func(name, componentName string) *applicationapiv1alpha1.Snapshot {
+			return &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotComponentType,
+						gitops.SnapshotComponentLabel:       componentName,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePullRequestType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{Name: componentName, ContainerImage: sample_image + "@sha256:12345"},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			obsoletedPipelineRun = &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "obsoleted-pipelinerun",
+					Namespace: "default",
+				},
+				Spec: tektonv1.PipelineRunSpec{
+					PipelineRef: &tektonv1.PipelineRef{
+						Name: "integration-pipeline",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, obsoletedPipelineRun)).Should(Succeed())
+
+			obsoletedSnapshot = newPullRequestSnapshot("snapshot-pr-obsoleted", hasComp.Name)
+			otherComponentSnapshot = newPullRequestSnapshot("snapshot-pr-other-component", "component-other")
+			for _, pullRequestSnapshot := range []*applicationapiv1alpha1.Snapshot{obsoletedSnapshot, otherComponentSnapshot} {
+				Expect(k8sClient.Create(ctx, pullRequestSnapshot)).Should(Succeed())
+				statuses, err := intgteststat.NewSnapshotIntegrationTestStatuses("")
+				Expect(err).To(Succeed())
+				statuses.UpdateTestStatusIfChanged(integrationTestScenario.Name, intgteststat.IntegrationTestStatusInProgress, "running")
+				Expect(statuses.UpdateTestPipelineRunName(integrationTestScenario.Name, obsoletedPipelineRun.Name)).To(Succeed())
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, pullRequestSnapshot, statuses, k8sClient)).Should(Succeed())
+			}
+
+			overrideSnapshot = &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-override",
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel: gitops.SnapshotOverrideType,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{Name: hasComp.Name, ContainerImage: sample_image + "@sha256:67890"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, overrideSnapshot)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			for _, snapshot := range []*applicationapiv1alpha1.Snapshot{obsoletedSnapshot, otherComponentSnapshot, overrideSnapshot} {
+				err := k8sClient.Delete(ctx, snapshot)
+				Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+			}
+			err := k8sClient.Delete(ctx, obsoletedPipelineRun)
+			Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("obsoletes the unfinished pull request snapshots of the components of the override snapshot", func() {
+			overrideAdapter := NewAdapter(ctx, overrideSnapshot, hasApp, nil, logger, loader.NewMockLoader(), k8sClient)
+			result, err := overrideAdapter.EnsurePullRequestSnapshotsObsoletedByOverride()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obsoletedSnapshot), obsoletedSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotObsoletedByOverride(obsoletedSnapshot)).To(BeTrue())
+			Expect(obsoletedSnapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotObsoletedByOverrideAnnotation, overrideSnapshot.Name))
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(obsoletedSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+			Expect(detail.Details).To(ContainSubstring(overrideSnapshot.Name))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obsoletedPipelineRun), obsoletedPipelineRun)).To(Succeed())
+			Expect(obsoletedPipelineRun.Spec.Status).To(Equal(tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelledRunFinally)))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(otherComponentSnapshot), otherComponentSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotObsoletedByOverride(otherComponentSnapshot)).To(BeFalse())
+			statuses, err = gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(otherComponentSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok = statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+		})
+
+		It("doesn't obsolete override snapshots carrying stale pull request labels", func() {
+			staleOverrideSnapshot := newPullRequestSnapshot("snapshot-override-stale-pr", hasComp.Name)
+			staleOverrideSnapshot.Labels[gitops.SnapshotTypeLabel] = gitops.SnapshotOverrideType
+			Expect(k8sClient.Create(ctx, staleOverrideSnapshot)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, staleOverrideSnapshot)).Should(Succeed())
+			}()
+
+			// the override snapshot is seen as created after the stale one, which would be obsoleted otherwise
+			laterOverrideSnapshot := overrideSnapshot.DeepCopy()
+			laterOverrideSnapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Hour))
+			overrideAdapter := NewAdapter(ctx, laterOverrideSnapshot, hasApp, nil, logger, loader.NewMockLoader(), k8sClient)
+			result, err := overrideAdapter.EnsurePullRequestSnapshotsObsoletedByOverride()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(staleOverrideSnapshot), staleOverrideSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotObsoletedByOverride(staleOverrideSnapshot)).To(BeFalse())
+		})
+
+		It("doesn't obsolete pull request snapshots for snapshots which aren't override snapshots", func() {
+			pullRequestAdapter := NewAdapter(ctx, otherComponentSnapshot, hasApp, nil, logger, loader.NewMockLoader(), k8sClient)
+			result, err := pullRequestAdapter.EnsurePullRequestSnapshotsObsoletedByOverride()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obsoletedSnapshot), obsoletedSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotObsoletedByOverride(obsoletedSnapshot)).To(BeFalse())
+		})
+	})
+
+	Describe("EnsureOlderPullRequestSnapshotsSuperseded", func() {
+		var (
+			supersededSnapshot, otherPullRequestSnapshot, supersedingSnapshot *applicationapiv1alpha1.Snapshot
+			supersededPipelineRun                                             *tektonv1.PipelineRun
+		)
+
+		newPullRequestSnapshot := func(name, pullRequest string) *applicationapiv1alpha1.Snapshot {
+			return &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels: map[string]string{
+						gitops.SnapshotTypeLabel:            gitops.SnapshotComponentType,
+						gitops.SnapshotComponentLabel:       hasComp.Name,
+						gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePullRequestType,
+					},
+					Annotations: map[string]string{
+						gitops.PipelineAsCodePullRequestAnnotation: pullRequest,
+					},
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: hasApp.Name,
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{Name: hasComp.Name, ContainerImage: sample_image + "@sha256:12345"},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			supersededPipelineRun = &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "superseded-pipelinerun",
+					Namespace: "default",
+				},
+				Spec: tektonv1.PipelineRunSpec{
+					PipelineRef: &tektonv1.PipelineRef{
+						Name: "integration-pipeline",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, supersededPipelineRun)).Should(Succeed())
+
+			supersededSnapshot = newPullRequestSnapshot("snapshot-pr-superseded", "1")
+			otherPullRequestSnapshot = newPullRequestSnapshot("snapshot-pr-other-pull-request", "2")
+			for _, pullRequestSnapshot := range []*applicationapiv1alpha1.Snapshot{supersededSnapshot, otherPullRequestSnapshot} {
+				Expect(k8sClient.Create(ctx, pullRequestSnapshot)).Should(Succeed())
+				statuses, err := intgteststat.NewSnapshotIntegrationTestStatuses("")
+				Expect(err).To(Succeed())
+				statuses.UpdateTestStatusIfChanged(integrationTestScenario.Name, intgteststat.IntegrationTestStatusInProgress, "running")
+				Expect(statuses.UpdateTestPipelineRunName(integrationTestScenario.Name, supersededPipelineRun.Name)).To(Succeed())
+				Expect(gitops.WriteIntegrationTestStatusesIntoSnapshot(ctx, pullRequestSnapshot, statuses, k8sClient)).Should(Succeed())
+			}
+
+			supersedingSnapshot = newPullRequestSnapshot("snapshot-pr-superseding", "1")
+			Expect(k8sClient.Create(ctx, supersedingSnapshot)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			for _, snapshot := range []*applicationapiv1alpha1.Snapshot{supersededSnapshot, otherPullRequestSnapshot, supersedingSnapshot} {
+				err := k8sClient.Delete(ctx, snapshot)
+				Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+			}
+			err := k8sClient.Delete(ctx, supersededPipelineRun)
+			Expect(err == nil || errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("supersedes the unfinished older snapshots of the same pull request", func() {
+			// the superseding snapshot is seen as created after the older ones, envtest timestamps have a second precision
+			laterSnapshot := supersedingSnapshot.DeepCopy()
+			laterSnapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Hour))
+			supersedingAdapter := NewAdapter(ctx, laterSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := supersedingAdapter.EnsureOlderPullRequestSnapshotsSuperseded()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(supersededSnapshot), supersededSnapshot)).To(Succeed())
+			Expect(supersededSnapshot.GetAnnotations()).To(HaveKeyWithValue(gitops.SnapshotSupersededByAnnotation, supersedingSnapshot.Name))
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(supersededSnapshot)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+			Expect(detail.Details).To(ContainSubstring(supersedingSnapshot.Name))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(supersededPipelineRun), supersededPipelineRun)).To(Succeed())
+			Expect(supersededPipelineRun.Spec.Status).To(Equal(tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelledRunFinally)))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(otherPullRequestSnapshot), otherPullRequestSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotSuperseded(otherPullRequestSnapshot)).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(supersedingSnapshot), supersedingSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotSuperseded(supersedingSnapshot)).To(BeFalse())
+		})
+
+		It("doesn't supersede newer snapshots of the same pull request", func() {
+			earlierSnapshot := supersedingSnapshot.DeepCopy()
+			earlierSnapshot.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			earlierAdapter := NewAdapter(ctx, earlierSnapshot, hasApp, hasComp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := earlierAdapter.EnsureOlderPullRequestSnapshotsSuperseded()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(supersededSnapshot), supersededSnapshot)).To(Succeed())
+			Expect(gitops.IsSnapshotSuperseded(supersededSnapshot)).To(BeFalse())
+		})
+	})
 })
 
 func getAllIntegrationPipelineRunsForSnapshot(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) ([]tektonv1.PipelineRun, error) {
@@ -1130,3 +3008,54 @@ func getAllIntegrationPipelineRunsForSnapshot(ctx context.Context, snapshot *app
 
 	return integrationPipelineRuns.Items, err
 }
+
+// failingReleaseClient fails to create the Releases of the given ReleasePlan
+type failingReleaseClient struct {
+	client.Client
+	failingReleasePlan string
+}
+
+func (c *failingReleaseClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if release, ok := obj.(*releasev1alpha1.Release); ok && release.Spec.ReleasePlan == c.failingReleasePlan {
+		return fmt.Errorf("failed to create release")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// conflictingComponentStatusClient fails the given number of Component status patches with a conflict
+type conflictingComponentStatusClient struct {
+	client.Client
+	conflicts int
+}
+
+func (c *conflictingComponentStatusClient) Status() client.SubResourceWriter {
+	return &conflictingComponentStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type conflictingComponentStatusWriter struct {
+	client.SubResourceWriter
+	client *conflictingComponentStatusClient
+}
+
+func (w *conflictingComponentStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if component, ok := obj.(*applicationapiv1alpha1.Component); ok && w.client.conflicts > 0 {
+		w.client.conflicts--
+		return errors.NewConflict(applicationapiv1alpha1.GroupVersion.WithResource("components").GroupResource(), component.Name, fmt.Errorf("the object has been modified"))
+	}
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// failingPipelineRunClient fails to create the PipelineRuns with the given error and counts the attempts
+type failingPipelineRunClient struct {
+	client.Client
+	err      error
+	attempts int
+}
+
+func (c *failingPipelineRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*tektonv1.PipelineRun); ok {
+		c.attempts++
+		return c.err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
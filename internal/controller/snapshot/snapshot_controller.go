@@ -44,6 +44,8 @@ type Reconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// APIReader lists the earlier Snapshots of an Application from the API server in pages, if set
+	APIReader client.Reader
 }
 
 // NewSnapshotReconciler creates and returns a Reconciler.
@@ -76,11 +78,17 @@ func NewSnapshotReconciler(client client.Client, logger *logr.Logger, scheme *ru
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=releases,verbs=create;delete;get;list;patch;update;watch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=releaseplans,verbs=get;list;watch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=releaseplans/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("snapshot", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
+	if r.APIReader != nil {
+		ctx = loader.WithAPIReader(ctx, r.APIReader)
+	}
 	loader := loader.NewLoader()
 
 	snapshot := &applicationapiv1alpha1.Snapshot{}
@@ -123,18 +131,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	logger = logger.WithApp(*application)
 
+	// the override snapshots aren't created for a single component
 	var component *applicationapiv1alpha1.Component
-	err = retry.OnError(retry.DefaultRetry, func(_ error) bool { return true }, func() error {
-		component, err = loader.GetComponentFromSnapshot(ctx, r.Client, snapshot)
-		return err
-	})
-	if err != nil {
-		return helpers.HandleLoaderError(logger, err, fmt.Sprintf("Component or '%s' label", tekton.ComponentNameLabel), "Snapshot")
+	if !gitops.IsOverrideSnapshot(snapshot) {
+		err = retry.OnError(retry.DefaultRetry, func(_ error) bool { return true }, func() error {
+			component, err = loader.GetComponentFromSnapshot(ctx, r.Client, snapshot)
+			return err
+		})
+		if err != nil {
+			return helpers.HandleLoaderError(logger, err, fmt.Sprintf("Component or '%s' label", tekton.ComponentNameLabel), "Snapshot")
+		}
 	}
 
 	adapter := NewAdapter(ctx, snapshot, application, component, logger, loader, r.Client)
 
 	return controller.ReconcileHandler([]controller.Operation{
+		adapter.EnsurePullRequestSnapshotsObsoletedByOverride,
+		adapter.EnsureOlderPullRequestSnapshotsSuperseded,
 		adapter.EnsureAllReleasesExist,
 		adapter.EnsureGlobalCandidateImageUpdated,
 		adapter.EnsureRerunPipelineRunsExist,
@@ -144,6 +157,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 // AdapterInterface is an interface defining all the operations that should be defined in an Integration adapter.
 type AdapterInterface interface {
+	EnsurePullRequestSnapshotsObsoletedByOverride() (controller.OperationResult, error)
+	EnsureOlderPullRequestSnapshotsSuperseded() (controller.OperationResult, error)
 	EnsureAllReleasesExist() (controller.OperationResult, error)
 	EnsureRerunPipelineRunsExist() (controller.OperationResult, error)
 	EnsureIntegrationPipelineRunsExist() (controller.OperationResult, error)
@@ -152,7 +167,9 @@ type AdapterInterface interface {
 
 // SetupController creates a new Integration controller and adds it to the Manager.
 func SetupController(manager ctrl.Manager, log *logr.Logger) error {
-	return setupControllerWithManager(manager, NewSnapshotReconciler(manager.GetClient(), log, manager.GetScheme()))
+	reconciler := NewSnapshotReconciler(manager.GetClient(), log, manager.GetScheme())
+	reconciler.APIReader = manager.GetAPIReader()
+	return setupControllerWithManager(manager, reconciler)
 }
 
 // setupCache indexes fields for each of the resources used in the release adapter in those cases where filtering by
@@ -188,6 +205,7 @@ func setupControllerWithManager(manager ctrl.Manager, controller *Reconciler) er
 				predicate.Or(
 					gitops.IntegrationSnapshotChangePredicate(),
 					gitops.SnapshotIntegrationTestRerunTriggerPredicate(),
+					gitops.SnapshotPipelineRunsRecreationPredicate(),
 				),
 			),
 		).
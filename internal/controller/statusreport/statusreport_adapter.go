@@ -18,6 +18,7 @@ package statusreport
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -32,16 +33,47 @@ import (
 	"github.com/konflux-ci/integration-service/loader"
 	"github.com/konflux-ci/integration-service/metrics"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/pkg/pipelinerunqueue"
 	"github.com/konflux-ci/integration-service/status"
 	"github.com/konflux-ci/integration-service/tekton"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	clienterrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
 )
 
 const SnapshotRetryTimeout = time.Duration(3 * time.Hour)
 
+// ReportRetryJitterFactor is the maximum fraction of the retry delay added to it when the failed reports are requeued
+const ReportRetryJitterFactor = 0.2
+
+// VanishedPipelineRunGracePeriod is the time after the start of a test during which a missing integration
+// PipelineRun is not considered vanished, so a freshly created PipelineRun missing from the cache isn't mistaken for it.
+const VanishedPipelineRunGracePeriod = time.Duration(2 * time.Minute)
+
+// PipelineRunRecreationMaxAge is the maximum time after the start of a test during which its integration PipelineRun,
+// deleted before producing results, is recreated instead of marking the test as deleted. Zero disables the recreation.
+var PipelineRunRecreationMaxAge = time.Duration(10 * time.Minute)
+
+// MaxPipelineRunRecreations is the maximum number of times the integration PipelineRun of a test is recreated
+// before the test is marked as deleted.
+const MaxPipelineRunRecreations = 2
+
+// PRMRStateCheckInterval is the minimum time between the checks of the state of the pull/merge request
+// of a Snapshot whose tests haven't finished yet.
+const PRMRStateCheckInterval = time.Duration(5 * time.Minute)
+
+// DefaultWatchdogDeadline is the hard deadline after which a test still in progress, whose integration PipelineRun
+// isn't progressing anymore, is failed by the watchdog. Scenarios can override it with spec.watchdogDeadline.
+// Zero disables the watchdog for the scenarios not overriding it.
+var DefaultWatchdogDeadline = time.Duration(24 * time.Hour)
+
+// WatchdogPipelineRunIdlePeriod is the time since the last change of the Succeeded condition of an unfinished
+// integration PipelineRun after which it isn't considered progressing anymore by the watchdog.
+const WatchdogPipelineRunIdlePeriod = time.Duration(1 * time.Hour)
+
 // Adapter holds the objects needed to reconcile a snapshot's test status report.
 type Adapter struct {
 	snapshot    *applicationapiv1alpha1.Snapshot
@@ -51,11 +83,14 @@ type Adapter struct {
 	client      client.Client
 	context     context.Context
 	status      status.StatusInterface
+
+	// deferredRequeueAfter is the shortest requeue delay asked for by the operations wrapped by deferRequeue
+	deferredRequeueAfter time.Duration
 }
 
 // NewAdapter creates and returns an Adapter instance.
 func NewAdapter(context context.Context, snapshot *applicationapiv1alpha1.Snapshot, application *applicationapiv1alpha1.Application,
-	logger helpers.IntegrationLogger, loader loader.ObjectLoader, client client.Client,
+	logger helpers.IntegrationLogger, loader loader.ObjectLoader, client client.Client, statusOpts ...status.StatusOption,
 ) *Adapter {
 	return &Adapter{
 		snapshot:    snapshot,
@@ -64,10 +99,47 @@ func NewAdapter(context context.Context, snapshot *applicationapiv1alpha1.Snapsh
 		loader:      loader,
 		client:      client,
 		context:     context,
-		status:      status.NewStatus(logger.Logger, client),
+		status:      status.NewStatus(logger.Logger, client, statusOpts...),
 	}
 }
 
+// deferRequeue wraps the given operation so a delayed requeue it asks for doesn't stop the operations after it,
+// the shortest of the deferred delays is returned by EnsureDeferredRequeue once all the operations ran
+func (a *Adapter) deferRequeue(operation controller.Operation) controller.Operation {
+	return func() (controller.OperationResult, error) {
+		result, err := operation()
+		if err != nil || !result.RequeueRequest || result.RequeueDelay <= 0 {
+			return result, err
+		}
+		if a.deferredRequeueAfter == 0 || result.RequeueDelay < a.deferredRequeueAfter {
+			a.deferredRequeueAfter = result.RequeueDelay
+		}
+		return controller.ContinueProcessing()
+	}
+}
+
+// EnsureDeferredRequeue is an operation that will ensure that the Snapshot is requeued after the shortest delay
+// asked for by the operations whose requeue was deferred, it must be the last operation.
+func (a *Adapter) EnsureDeferredRequeue() (controller.OperationResult, error) {
+	if a.deferredRequeueAfter > 0 {
+		return controller.RequeueAfter(a.deferredRequeueAfter, nil)
+	}
+	return controller.ContinueProcessing()
+}
+
+// EnsureTestStatusEventsEmitted is an operation that will ensure that the events about the transitions of the
+// integration tests of the snapshot are emitted. The events are emitted for all snapshots, including the ones
+// whose status isn't reported to the git provider.
+func (a *Adapter) EnsureTestStatusEventsEmitted() (controller.OperationResult, error) {
+	err := a.status.EmitTestStatusEvents(a.context, a.snapshot)
+	if err != nil {
+		// the events are best effort, they mustn't hold back the report to the git provider
+		a.logger.Error(err, "failed to emit the integration test events for snapshot",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+	}
+	return controller.ContinueProcessing()
+}
+
 // EnsureSnapshotTestStatusReportedToGitProvider will ensure that integration test status including env provision and snapshotEnvironmentBinding error is reported to the git provider
 // which (indirectly) triggered its execution.
 func (a *Adapter) EnsureSnapshotTestStatusReportedToGitProvider() (controller.OperationResult, error) {
@@ -75,21 +147,41 @@ func (a *Adapter) EnsureSnapshotTestStatusReportedToGitProvider() (controller.Op
 		return controller.ContinueProcessing()
 	}
 
-	reporter := a.status.GetReporter(a.snapshot)
-	if reporter == nil {
+	reporters := a.status.GetReporters(a.snapshot)
+	if len(reporters) == 0 {
 		a.logger.Info("No suitable reporter found, skipping report")
 		return controller.ContinueProcessing()
 	}
-	a.logger.Info(fmt.Sprintf("Detected reporter: %s", reporter.GetReporterName()))
+	a.logger.Info(fmt.Sprintf("Detected reporter: %s", reporters[0].GetReporterName()))
+	for _, reporter := range reporters[1:] {
+		a.logger.Info(fmt.Sprintf("Detected secondary reporter: %s", reporter.GetReporterName()))
+	}
 
-	err := a.status.ReportSnapshotStatus(a.context, reporter, a.snapshot)
+	err := a.status.ReportSnapshotStatusToReporters(a.context, reporters, a.snapshot)
 	if err != nil {
-		a.logger.Error(err, "failed to report test status to git provider for snapshot",
-			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
-		if helpers.IsObjectYoungerThanThreshold(a.snapshot, SnapshotRetryTimeout) {
+		// the reports of all scenarios were attempted, retry the failed ones after the backoff of the worst failure,
+		// jittered so the reports of many snapshots failing at once don't hit the git provider at the same time
+		retryAfter, rateLimited := status.GetRateLimitRetryAfter(err)
+		var retryErr *status.ReportRetryError
+		if errors.As(err, &retryErr) {
+			retryAfter = max(retryAfter, retryErr.RetryAfter)
+		}
+		if rateLimited {
+			a.logger.Info("rate limit of git provider exceeded, will retry the report later",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name, "error", err.Error())
+		} else {
+			a.logger.Error(err, "failed to report test status to git provider for snapshot",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+		}
+		if rateLimited || helpers.IsObjectYoungerThanThreshold(a.snapshot, SnapshotRetryTimeout) {
+			if retryAfter > 0 {
+				return controller.RequeueAfter(wait.Jitter(retryAfter, ReportRetryJitterFactor), nil)
+			}
 			return controller.RequeueWithError(err)
 		}
 	}
+	// the finalizer is released by the integration pipeline controller once the outcome of the PLR is recorded
+	// in the Snapshot, make sure it's not left behind on the PLRs of the finished tests
 	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
 	if err != nil {
 		return controller.RequeueWithError(err)
@@ -105,7 +197,7 @@ func (a *Adapter) EnsureSnapshotTestStatusReportedToGitProvider() (controller.Op
 
 			// if the PLR doesn't exist on cluster we continue the loop
 			if err != nil {
-				if !errors.IsNotFound(err) {
+				if !clienterrors.IsNotFound(err) {
 					return controller.RequeueWithError(err)
 				}
 				continue
@@ -120,6 +212,367 @@ func (a *Adapter) EnsureSnapshotTestStatusReportedToGitProvider() (controller.Op
 	return controller.ContinueProcessing()
 }
 
+// EnsureFailedRequiredTestsNotified is an operation that will ensure that failures of required integration tests
+// of push snapshots are notified to Slack when the Application references a Slack webhook Secret.
+// Failures to deliver the notification are only logged so they don't block the reconciliation.
+func (a *Adapter) EnsureFailedRequiredTestsNotified() (controller.OperationResult, error) {
+	if !gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) || !metadata.HasAnnotation(a.application, status.SlackWebhookSecretAnnotation) {
+		return controller.ContinueProcessing()
+	}
+
+	integrationTestScenarios, err := a.loader.GetRequiredIntegrationTestScenariosForApplication(a.context, a.client, a.application)
+	if err != nil {
+		a.logger.Error(err, "Failed to get required integration test scenarios, skipping slack notification")
+		return controller.ContinueProcessing()
+	}
+	requiredScenarios := []string{}
+	for _, integrationTestScenario := range *integrationTestScenarios {
+		requiredScenarios = append(requiredScenarios, integrationTestScenario.Name)
+	}
+
+	notifier := status.NewSlackNotifier(a.logger.Logger, a.client)
+	if err := notifier.NotifyFailedRequiredScenarios(a.context, a.application, a.snapshot, requiredScenarios); err != nil {
+		a.logger.Error(err, "Failed to notify slack about failed required integration tests",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+	}
+
+	return controller.ContinueProcessing()
+}
+
+// EnsureTestResultsArchived is an operation that will ensure that the final test reports of the Snapshot are archived
+// as an OCI artifact next to the tested images once all its tests finished, when enabled for the Application.
+// Rejected credentials of the namespace are only logged since retrying won't fix them.
+func (a *Adapter) EnsureTestResultsArchived() (controller.OperationResult, error) {
+	if !status.IsTestResultsArchiveEnabled(a.application) || !gitops.IsSnapshotIntegrationStatusMarkedAsFinished(a.snapshot) ||
+		gitops.HasTestResultsArtifact(a.snapshot) {
+		return controller.ContinueProcessing()
+	}
+
+	archiver := status.NewTestResultsArchiver(a.logger.Logger, a.client)
+	err := archiver.Archive(a.context, a.snapshot)
+	var authErr *status.RegistryAuthError
+	if errors.As(err, &authErr) {
+		a.logger.Error(err, "Container registry rejected the push secret, test results of the snapshot won't be archived",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+		return controller.ContinueProcessing()
+	}
+	if err != nil {
+		a.logger.Error(err, "Failed to archive test results of the snapshot",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+		if helpers.IsObjectYoungerThanThreshold(a.snapshot, SnapshotRetryTimeout) {
+			return controller.RequeueWithError(err)
+		}
+	}
+
+	return controller.ContinueProcessing()
+}
+
+// EnsureVanishedPipelineRunsMarkedAsDeleted is an operation that will ensure that the tests of the Snapshot
+// which are still in progress but whose integration PipelineRun doesn't exist anymore are marked as deleted,
+// so they don't stay in progress forever. The updated test status triggers a new report to the git provider.
+// Tests started less than PipelineRunRecreationMaxAge ago get their PipelineRun recreated by the snapshot controller
+// instead, at most MaxPipelineRunRecreations times. Only tests in progress qualify, the TTL cleanup deletes
+// only the PipelineRuns of finished tests.
+func (a *Adapter) EnsureVanishedPipelineRunsMarkedAsDeleted() (controller.OperationResult, error) {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return controller.RequeueWithError(err)
+	}
+
+	var requeueAfter time.Duration
+	recreationRequested := false
+	for _, testDetails := range testStatuses.GetStatuses() {
+		if testDetails.Status != intgteststat.IntegrationTestStatusInProgress || testDetails.TestPipelineRunName == "" {
+			continue
+		}
+
+		// don't race the creation of the PipelineRun, check it again once the grace period expires
+		if testDetails.StartTime != nil {
+			if remaining := VanishedPipelineRunGracePeriod - time.Since(*testDetails.StartTime); remaining > 0 {
+				if requeueAfter == 0 || remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+				continue
+			}
+		}
+
+		pipelineRun := &tektonv1.PipelineRun{}
+		err := a.client.Get(a.context, types.NamespacedName{
+			Namespace: a.snapshot.Namespace,
+			Name:      testDetails.TestPipelineRunName,
+		}, pipelineRun)
+		if err == nil {
+			continue
+		}
+		if !clienterrors.IsNotFound(err) {
+			return controller.RequeueWithError(err)
+		}
+
+		pipelinerunqueue.DefaultQueue.Release(pipelinerunqueue.TestKey(a.snapshot.Namespace, a.snapshot.Name, testDetails.ScenarioName))
+		if isPipelineRunRecreationAllowed(testDetails) {
+			pipelineRunName := testDetails.TestPipelineRunName
+			recreations, err := testStatuses.RecordTestPipelineRunRecreation(testDetails.ScenarioName,
+				fmt.Sprintf("Integration test which was running as pipeline run '%s' was deleted before producing results, recreating its pipeline run", pipelineRunName))
+			if err != nil {
+				return controller.RequeueWithError(err)
+			}
+			a.logger.Info("Integration PipelineRun of a test in progress doesn't exist anymore, recreating it",
+				"integrationTestScenario.Name", testDetails.ScenarioName, "pipelineRun.Name", pipelineRunName, "recreations", recreations)
+			recreationRequested = true
+			continue
+		}
+
+		a.logger.Info("Integration PipelineRun of a test in progress doesn't exist anymore, marking the test as deleted",
+			"integrationTestScenario.Name", testDetails.ScenarioName, "pipelineRun.Name", testDetails.TestPipelineRunName)
+		testStatuses.UpdateTestStatusIfChanged(testDetails.ScenarioName, intgteststat.IntegrationTestStatusDeleted,
+			fmt.Sprintf("Integration test which was running as pipeline run '%s' disappeared before it could finish", testDetails.TestPipelineRunName))
+	}
+
+	if testStatuses.IsDirty() {
+		err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client)
+		if err != nil {
+			a.logger.Error(err, "Failed to update the test status of the snapshot after its integration PipelineRuns vanished",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Tests whose integration PipelineRuns vanished were updated", a.snapshot, helpers.LogActionUpdate)
+	}
+
+	if recreationRequested {
+		err = gitops.RequestPipelineRunsRecreation(a.context, a.client, a.snapshot, time.Now())
+		if err != nil {
+			a.logger.Error(err, "Failed to request the recreation of the vanished integration PipelineRuns of the snapshot",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Recreation of the vanished integration PipelineRuns was requested", a.snapshot, helpers.LogActionUpdate)
+	}
+
+	if requeueAfter > 0 {
+		return controller.RequeueAfter(requeueAfter, nil)
+	}
+	return controller.ContinueProcessing()
+}
+
+// isPipelineRunRecreationAllowed returns true if the integration PipelineRun of the test, deleted before producing
+// results, can be recreated, the test must have started recently and must not have exhausted its recreations
+func isPipelineRunRecreationAllowed(testDetails *intgteststat.IntegrationTestStatusDetail) bool {
+	return PipelineRunRecreationMaxAge > 0 && testDetails.Recreations < MaxPipelineRunRecreations &&
+		testDetails.StartTime != nil && time.Since(*testDetails.StartTime) < PipelineRunRecreationMaxAge
+}
+
+// EnsureStuckTestsFailedByWatchdog is an operation that will ensure that the tests of the Snapshot which are
+// still in progress past the hard deadline of their scenario, whose integration PipelineRun isn't progressing
+// anymore, are failed by the watchdog timeout. Their PipelineRuns still running are canceled, the updated test
+// status triggers a new report to the git provider.
+func (a *Adapter) EnsureStuckTestsFailedByWatchdog() (controller.OperationResult, error) {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return controller.RequeueWithError(err)
+	}
+
+	var scenarios *[]v1beta2.IntegrationTestScenario
+	var requeueAfter time.Duration
+	for _, testDetails := range testStatuses.GetStatuses() {
+		if testDetails.Status != intgteststat.IntegrationTestStatusInProgress || testDetails.StartTime == nil {
+			continue
+		}
+
+		if scenarios == nil {
+			scenarios, err = a.loader.GetAllIntegrationTestScenariosForApplication(a.context, a.client, a.application)
+			if err != nil {
+				a.logger.Error(err, "Failed to get the integration test scenarios of the application", "application.Name", a.application.Name)
+				return controller.RequeueWithError(err)
+			}
+		}
+		deadline := getWatchdogDeadline(scenarios, testDetails.ScenarioName)
+		if deadline <= 0 {
+			continue
+		}
+		if remaining := deadline - time.Since(*testDetails.StartTime); remaining > 0 {
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		var pipelineRun *tektonv1.PipelineRun
+		if testDetails.TestPipelineRunName != "" {
+			pipelineRun, err = a.loader.GetPipelineRun(a.context, a.client, testDetails.TestPipelineRunName, a.snapshot.Namespace)
+			if loader.IsResourceNotFound(err) {
+				pipelineRun = nil
+			} else if err != nil {
+				return controller.RequeueWithError(err)
+			}
+		}
+		if pipelineRun != nil && !helpers.HasPipelineRunFinished(pipelineRun) {
+			if lastTransitionTime := pipelineRunLastTransitionTime(pipelineRun); time.Since(lastTransitionTime) < WatchdogPipelineRunIdlePeriod {
+				// the PipelineRun is still progressing, check it again once it could be idle
+				if remaining := WatchdogPipelineRunIdlePeriod - time.Since(lastTransitionTime); requeueAfter == 0 || remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+				continue
+			}
+		}
+
+		a.logger.Info("Integration test is stuck in progress past its hard deadline, failing it by the watchdog timeout",
+			"integrationTestScenario.Name", testDetails.ScenarioName, "pipelineRun.Name", testDetails.TestPipelineRunName, "deadline", deadline)
+		if err = testStatuses.MarkTestTimedOutByWatchdog(testDetails.ScenarioName, deadline); err != nil {
+			return controller.RequeueWithError(err)
+		}
+		pipelinerunqueue.DefaultQueue.Release(pipelinerunqueue.TestKey(a.snapshot.Namespace, a.snapshot.Name, testDetails.ScenarioName))
+		if pipelineRun != nil && !helpers.HasPipelineRunFinished(pipelineRun) {
+			if err = helpers.CancelPipelineRun(a.context, a.client, a.logger, pipelineRun); err != nil {
+				a.logger.Error(err, "Failed to cancel the integration PipelineRun of the test failed by the watchdog timeout",
+					"pipelineRun.Name", pipelineRun.Name)
+				return controller.RequeueWithError(err)
+			}
+		}
+	}
+
+	if testStatuses.IsDirty() {
+		err = gitops.WriteIntegrationTestStatusesIntoSnapshot(a.context, a.snapshot, testStatuses, a.client)
+		if err != nil {
+			a.logger.Error(err, "Failed to update the test status of the snapshot after failing its stuck tests",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Tests stuck in progress past their hard deadline were failed by the watchdog timeout", a.snapshot, helpers.LogActionUpdate)
+	}
+
+	if requeueAfter > 0 {
+		return controller.RequeueAfter(requeueAfter, nil)
+	}
+	return controller.ContinueProcessing()
+}
+
+// getWatchdogDeadline returns the hard deadline of the tests of the scenario, the scenario's own deadline
+// if it overrides DefaultWatchdogDeadline
+func getWatchdogDeadline(scenarios *[]v1beta2.IntegrationTestScenario, testName string) time.Duration {
+	scenarioName := helpers.GetScenarioNameFromTestName(testName)
+	for _, scenario := range *scenarios {
+		if scenario.Name != scenarioName || scenario.Spec.WatchdogDeadline == "" {
+			continue
+		}
+		// the webhook rejects invalid deadlines, fall back to the default if one got through anyway
+		if deadline, err := time.ParseDuration(scenario.Spec.WatchdogDeadline); err == nil && deadline > 0 {
+			return deadline
+		}
+	}
+	return DefaultWatchdogDeadline
+}
+
+// pipelineRunLastTransitionTime returns the time of the last change of the Succeeded condition of the PipelineRun,
+// its creation time if the condition isn't set yet
+func pipelineRunLastTransitionTime(pipelineRun *tektonv1.PipelineRun) time.Time {
+	condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded)
+	if condition == nil || condition.LastTransitionTime.Inner.IsZero() {
+		return pipelineRun.CreationTimestamp.Time
+	}
+	return condition.LastTransitionTime.Inner.Time
+}
+
+// isPRMRStateCheckNeeded returns true if the state of the pull/merge request of the Snapshot is checked
+// periodically, the Snapshot must belong to a pull/merge request and must have unfinished tests
+func (a *Adapter) isPRMRStateCheckNeeded(testStatuses *intgteststat.SnapshotIntegrationTestStatuses) bool {
+	// override snapshots can carry pull request labels copied from other snapshots, they don't belong to a pull request
+	if gitops.IsSnapshotCreatedByPACPushEvent(a.snapshot) || gitops.IsOverrideSnapshot(a.snapshot) ||
+		gitops.HaveAppStudioTestsFinished(a.snapshot) || gitops.AreSnapshotTestsCanceled(a.snapshot) {
+		return false
+	}
+	for _, testDetails := range testStatuses.GetStatuses() {
+		if !testDetails.Status.IsFinal() {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureTestingCanceledForClosedPRMR is an operation that will ensure that the unfinished tests of a pull request
+// Snapshot are canceled together with their running integration PipelineRuns once its pull/merge request is closed,
+// so they don't keep consuming test capacity. Merged pull/merge requests cancel the tests only when the Application
+// opted in, their results may still be wanted. The state is checked while the tests are running, at most once
+// per PRMRStateCheckInterval.
+func (a *Adapter) EnsureTestingCanceledForClosedPRMR() (controller.OperationResult, error) {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return controller.RequeueWithError(err)
+	}
+	if !a.isPRMRStateCheckNeeded(testStatuses) {
+		return controller.ContinueProcessing()
+	}
+
+	if checkTime, ok := gitops.GetPRMRStateCheckTime(a.snapshot); ok {
+		if remaining := PRMRStateCheckInterval - time.Since(checkTime); remaining > 0 {
+			return controller.RequeueAfter(remaining, nil)
+		}
+	}
+
+	prmrState, err := a.status.GetPRMRStateInSnapshot(a.context, a.snapshot)
+	if err != nil {
+		// the tests keep running when the state can't be determined, it's checked again later
+		a.logger.Error(err, "Failed to get the state of the pull/merge request of the snapshot, considering it opened",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+		prmrState = status.PRMRStateOpened
+	}
+
+	if prmrState == status.PRMRStateClosed || (prmrState == status.PRMRStateMerged && gitops.IsCancelOnMergedPRMREnabled(a.application)) {
+		err = gitops.MarkSnapshotAsCanceledByPRMR(a.context, a.client, a.snapshot, string(prmrState))
+		if err != nil {
+			a.logger.Error(err, "Failed to cancel the unfinished tests of the snapshot",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+		a.logger.LogAuditEvent("Snapshot testing was canceled because its pull/merge request isn't opened anymore",
+			a.snapshot, helpers.LogActionUpdate, "prmrState", prmrState)
+
+		if err = a.cancelRunningPipelineRuns(); err != nil {
+			a.logger.Error(err, "Failed to cancel the running integration PipelineRuns of the snapshot",
+				"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+			return controller.RequeueWithError(err)
+		}
+		return controller.ContinueProcessing()
+	}
+
+	if prmrState == status.PRMRStateMerged {
+		a.logger.Info("The pull/merge request of the snapshot was merged, letting its tests finish",
+			"snapshot.Namespace", a.snapshot.Namespace, "snapshot.Name", a.snapshot.Name)
+	}
+	err = gitops.MarkPRMRStateChecked(a.context, a.client, a.snapshot, time.Now())
+	if err != nil {
+		return controller.RequeueWithError(err)
+	}
+	return controller.RequeueAfter(PRMRStateCheckInterval, nil)
+}
+
+// cancelRunningPipelineRuns cancels the integration PipelineRuns of the canceled tests of the Snapshot
+// which are still running
+func (a *Adapter) cancelRunningPipelineRuns() error {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(a.snapshot)
+	if err != nil {
+		return err
+	}
+	for _, testDetails := range testStatuses.GetStatuses() {
+		if testDetails.Status != intgteststat.IntegrationTestStatusDeleted || testDetails.TestPipelineRunName == "" {
+			continue
+		}
+		pipelineRun, err := a.loader.GetPipelineRun(a.context, a.client, testDetails.TestPipelineRunName, a.snapshot.Namespace)
+		if err != nil {
+			if loader.IsResourceNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to fetch the pipelineRun %s: %w", testDetails.TestPipelineRunName, err)
+		}
+		if helpers.HasPipelineRunFinished(pipelineRun) {
+			continue
+		}
+		if err = helpers.CancelPipelineRun(a.context, a.client, a.logger, pipelineRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EnsureSnapshotFinishedAllTests is an operation that will ensure that a pipeline Snapshot
 // to the PipelineRun being processed finished and passed all tests for all defined required IntegrationTestScenarios.
 // If the Snapshot doesn't have the freshest state of components, a composite Snapshot will be created instead
@@ -138,15 +591,13 @@ func (a *Adapter) EnsureSnapshotFinishedAllTests() (controller.OperationResult,
 		return controller.RequeueWithError(err)
 	}
 
-	allIntegrationTestsFinished, allIntegrationTestsPassed := a.determineIfAllRequiredIntegrationTestsFinishedAndPassed(integrationTestScenarios, testStatuses)
-	if err != nil {
-		a.logger.Error(err, "Failed to determine outcomes for Integration Tests",
-			"snapshot.Name", a.snapshot.Name)
-		return controller.RequeueWithError(err)
-	}
+	completion := gitops.GetRequiredTestsCompletion(a.snapshot, integrationTestScenarios, testStatuses)
+	a.logger.Info(fmt.Sprintf("%[1]d out of %[3]d required integration tests finished, %[2]d out of %[3]d required integration tests passed",
+		completion.Finished, completion.Passed, completion.Total))
+	allIntegrationTestsPassed := completion.AllPassed()
 
 	// Skip doing anything if not all Integration tests were finished for all integrationTestScenarios
-	if !allIntegrationTestsFinished {
+	if !completion.AllFinished() {
 		a.logger.Info("Not all required Integration PipelineRuns finished",
 			"snapshot.Name", a.snapshot.Name)
 
@@ -214,7 +665,7 @@ func (a *Adapter) EnsureSnapshotFinishedAllTests() (controller.OperationResult,
 				a.logger.LogAuditEvent("Snapshot integration status condition marked as invalid, the global component list has changed in the meantime",
 					a.snapshot, helpers.LogActionUpdate)
 			}
-			return controller.ContinueProcessing()
+			return a.markSnapshotTestsAsCompleted(allIntegrationTestsPassed)
 		}
 	}
 
@@ -242,33 +693,29 @@ func (a *Adapter) EnsureSnapshotFinishedAllTests() (controller.OperationResult,
 		}
 	}
 
-	return controller.ContinueProcessing()
+	return a.markSnapshotTestsAsCompleted(allIntegrationTestsPassed)
 }
 
-// determineIfAllRequiredIntegrationTestsFinishedAndPassed checks if all Integration tests finished and passed for the given
-// list of integrationTestScenarios.
-func (a *Adapter) determineIfAllRequiredIntegrationTestsFinishedAndPassed(integrationTestScenarios *[]v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) (bool, bool) {
-	allIntegrationTestsFinished, allIntegrationTestsPassed := true, true
-	integrationTestsFinished := 0
-	integrationTestsPassed := 0
-
-	for _, integrationTestScenario := range *integrationTestScenarios {
-		integrationTestScenario := integrationTestScenario // G601
-		testDetails, ok := testStatuses.GetScenarioStatus(integrationTestScenario.Name)
-		if !ok || !testDetails.Status.IsFinal() {
-			allIntegrationTestsFinished = false
-		} else {
-			integrationTestsFinished++
-		}
-		if ok && testDetails.Status != intgteststat.IntegrationTestStatusTestPassed {
-			allIntegrationTestsPassed = false
-		} else {
-			integrationTestsPassed++
-		}
+// markSnapshotTestsAsCompleted sets the AppStudio tests completed condition of the Snapshot to completed with
+// the given outcome of its required tests, unless the condition already reflects it.
+func (a *Adapter) markSnapshotTestsAsCompleted(passed bool) (controller.OperationResult, error) {
+	if completed, completedPassed := gitops.HaveSnapshotTestsCompleted(a.snapshot); completed && completedPassed == passed &&
+		gitops.IsSnapshotTestsCompletedConditionSet(a.snapshot) {
+		return controller.ContinueProcessing()
+	}
 
+	message := "All required integration tests finished and passed"
+	if !passed {
+		message = "All required integration tests finished and some of them failed"
 	}
-	a.logger.Info(fmt.Sprintf("%[1]d out of %[3]d required integration tests finished, %[2]d out of %[3]d required integration tests passed", integrationTestsFinished, integrationTestsPassed, len(*integrationTestScenarios)))
-	return allIntegrationTestsFinished, allIntegrationTestsPassed
+	err := gitops.MarkSnapshotTestsAsCompleted(a.context, a.client, a.snapshot, passed, message)
+	if err != nil {
+		a.logger.Error(err, "Failed to update the AppStudio tests completed condition of the Snapshot")
+		return controller.RequeueWithError(err)
+	}
+	a.logger.LogAuditEvent(message, a.snapshot, helpers.LogActionUpdate)
+
+	return controller.ContinueProcessing()
 }
 
 // prepareCompositeSnapshot prepares the Composite Snapshot for a given application,
@@ -314,11 +761,16 @@ func (a *Adapter) createCompositeSnapshotsIfConflictExists(application *applicat
 
 	// Create the new composite snapshot if it doesn't exist already
 	if !gitops.CompareSnapshots(compositeSnapshot, testedSnapshot) {
-		allSnapshots, err := a.loader.GetAllSnapshots(a.context, a.client, application)
+		// only the first matching Snapshot is kept instead of copying all the Snapshots of the Application
+		matchingSnapshots, err := a.loader.GetFilteredSnapshots(a.context, a.client, application,
+			func(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+				matches := gitops.CompareSnapshots(compositeSnapshot, snapshot)
+				return matches, matches
+			})
 		if err != nil {
 			return nil, err
 		}
-		existingCompositeSnapshot := gitops.FindMatchingSnapshot(a.application, allSnapshots, compositeSnapshot)
+		existingCompositeSnapshot := gitops.FindMatchingSnapshot(a.application, matchingSnapshots, compositeSnapshot)
 
 		if existingCompositeSnapshot != nil {
 			a.logger.Info("Found existing composite Snapshot",
@@ -360,15 +812,16 @@ func (a *Adapter) getComponentSourceFromSnapshotComponent(snapshot *applicationa
 	return nil, fmt.Errorf("couldn't find the requested component source info in the given Snapshot")
 }
 
-// findUntriggeredIntegrationTestFromStatus returns name of integrationTestScenario that is not triggered yet.
+// findUntriggeredIntegrationTestFromStatus returns name of integrationTestScenario that is not triggered yet,
+// i.e. any of its tests is missing in the test statuses.
 func (a *Adapter) findUntriggeredIntegrationTestFromStatus(integrationTestScenarios *[]v1beta2.IntegrationTestScenario, testStatuses *intgteststat.SnapshotIntegrationTestStatuses) string {
 	for _, integrationTestScenario := range *integrationTestScenarios {
 		integrationTestScenario := integrationTestScenario // G601
-		_, ok := testStatuses.GetScenarioStatus(integrationTestScenario.Name)
-		if !ok {
-			return integrationTestScenario.Name
+		for _, scenarioTest := range helpers.GetScenarioTests(&integrationTestScenario) {
+			if _, ok := testStatuses.GetScenarioStatus(scenarioTest.Name); !ok {
+				return integrationTestScenario.Name
+			}
 		}
-
 	}
 	return ""
 }
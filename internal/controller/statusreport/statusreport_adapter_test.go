@@ -18,11 +18,13 @@ package statusreport
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/git/github"
 	"github.com/tonglil/buflogr"
 	"go.uber.org/mock/gomock"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -37,10 +39,14 @@ import (
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/pkg/apis"
+	v1 "knative.dev/pkg/apis/duck/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -249,9 +255,9 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 
 			mockReporter.EXPECT().GetReporterName().Return("mocked_reporter")
 
-			mockStatus.EXPECT().GetReporter(gomock.Any()).Return(mockReporter)
-			// ReportSnapshotStatus must be called once
-			mockStatus.EXPECT().ReportSnapshotStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+			mockStatus.EXPECT().GetReporters(gomock.Any()).Return([]status.ReporterInterface{mockReporter})
+			// ReportSnapshotStatusToReporters must be called once
+			mockStatus.EXPECT().ReportSnapshotStatusToReporters(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 
 			mockScenarios := []v1beta2.IntegrationTestScenario{}
 			adapter = NewAdapter(ctx, hasPRSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
@@ -275,6 +281,569 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 			fmt.Fprintf(GinkgoWriter, "-------result: %v\n", result)
 			Expect(!result.CancelRequest && err == nil).To(BeTrue())
 		})
+
+		It("ensures the test events are emitted for push snapshots", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockStatus := status.NewMockStatusInterface(ctrl)
+			mockStatus.EXPECT().EmitTestStatusEvents(gomock.Any(), hasSnapshot).Times(1)
+
+			adapter = NewAdapter(ctx, hasSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			result, err := adapter.EnsureTestStatusEventsEmitted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+		})
+
+		It("continues processing when the test events can't be emitted", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockStatus := status.NewMockStatusInterface(ctrl)
+			mockStatus.EXPECT().EmitTestStatusEvents(gomock.Any(), hasPRSnapshot).Return(fmt.Errorf("conflict")).Times(1)
+
+			adapter = NewAdapter(ctx, hasPRSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			result, err := adapter.EnsureTestStatusEventsEmitted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(result.CancelRequest).To(BeFalse())
+		})
+
+		It("continues processing when the failed required test can't be notified to slack", func() {
+			slackApp := hasApp.DeepCopy()
+			slackApp.Annotations = map[string]string{status.SlackWebhookSecretAnnotation: "missing-slack-webhook"}
+			failedSnapshot := hasSnapshot.DeepCopy()
+			failedSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[{\"scenario\":\"" + integrationTestScenario.Name +
+				"\",\"status\":\"TestFail\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-07-26T17:57:50+02:00\",\"details\":\"failed\"}]"
+
+			adapter = NewAdapter(ctx, failedSnapshot, slackApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.RequiredIntegrationTestScenariosContextKey,
+					Resource:   []v1beta2.IntegrationTestScenario{*integrationTestScenario},
+				},
+			})
+			result, err := adapter.EnsureFailedRequiredTestsNotified()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+		})
+
+		It("requeues the report after the delay requested by the git provider rate limit", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockReporter := status.NewMockReporterInterface(ctrl)
+			mockStatus := status.NewMockStatusInterface(ctrl)
+
+			mockReporter.EXPECT().GetReporterName().Return("mocked_reporter")
+
+			mockStatus.EXPECT().GetReporters(gomock.Any()).Return([]status.ReporterInterface{mockReporter})
+			mockStatus.EXPECT().ReportSnapshotStatusToReporters(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+				fmt.Errorf("failed to update status: %w", &github.SecondaryRateLimitError{RetryAfter: 30 * time.Second})).Times(1)
+
+			adapter = NewAdapter(ctx, hasPRSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			result, err := adapter.EnsureSnapshotTestStatusReportedToGitProvider()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">=", 30*time.Second))
+			Expect(result.RequeueDelay).To(BeNumerically("<=", time.Duration(float64(30*time.Second)*(1+ReportRetryJitterFactor))))
+		})
+
+		It("requeues the report after the delay requested by the GitLab rate limit", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockReporter := status.NewMockReporterInterface(ctrl)
+			mockStatus := status.NewMockStatusInterface(ctrl)
+
+			mockReporter.EXPECT().GetReporterName().Return("mocked_reporter")
+
+			mockStatus.EXPECT().GetReporters(gomock.Any()).Return([]status.ReporterInterface{mockReporter})
+			mockStatus.EXPECT().ReportSnapshotStatusToReporters(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+				fmt.Errorf("failed to update status: %w", &status.GitLabRateLimitError{StatusCode: 429, RetryAfter: 45 * time.Second})).Times(1)
+
+			adapter = NewAdapter(ctx, hasPRSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			result, err := adapter.EnsureSnapshotTestStatusReportedToGitProvider()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">=", 45*time.Second))
+			Expect(result.RequeueDelay).To(BeNumerically("<=", time.Duration(float64(45*time.Second)*(1+ReportRetryJitterFactor))))
+		})
+
+		It("requeues the failed reports with jittered backoff after reporting the rest of the scenarios", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockReporter := status.NewMockReporterInterface(ctrl)
+			mockReporter.EXPECT().GetReporterName().Return("mocked_reporter").AnyTimes()
+			mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+			reportedScenarios := []string{}
+			mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, report status.TestReport) error {
+				if report.ScenarioName == "failing-scenario" {
+					return fmt.Errorf("failed to update status: 502 Bad Gateway")
+				}
+				reportedScenarios = append(reportedScenarios, report.ScenarioName)
+				return nil
+			}).Times(3)
+			mockStatus := status.NewMockStatusInterface(ctrl)
+			mockStatus.EXPECT().GetReporters(gomock.Any()).Return([]status.ReporterInterface{mockReporter})
+			mockStatus.EXPECT().ReportSnapshotStatusToReporters(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, reporters []status.ReporterInterface, snapshot *applicationapiv1alpha1.Snapshot) error {
+					return status.NewStatus(logger.Logger, k8sClient).ReportSnapshotStatusToReporters(ctx, reporters, snapshot)
+				}).Times(1)
+
+			mixedSnapshot := hasPRSnapshot.DeepCopy()
+			testStatus := func(scenarioName string) string {
+				return "{\"scenario\":\"" + scenarioName + "\",\"status\":\"TestPassed\",\"startTime\":\"2023-07-26T16:57:49+02:00\"," +
+					"\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-07-26T17:57:50+02:00\",\"details\":\"passed\"}"
+			}
+			mixedSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" +
+				testStatus("failing-scenario") + "," + testStatus("scenario-1") + "," + testStatus("scenario-2") + "]"
+			Expect(k8sClient.Patch(ctx, mixedSnapshot, client.MergeFrom(hasPRSnapshot))).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Patch(ctx, hasPRSnapshot, client.MergeFrom(mixedSnapshot))).To(Succeed())
+			}()
+
+			adapter = NewAdapter(ctx, mixedSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			result, err := adapter.EnsureSnapshotTestStatusReportedToGitProvider()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">=", status.ReportRetryBaseDelay))
+			Expect(result.RequeueDelay).To(BeNumerically("<=", time.Duration(float64(status.ReportRetryBaseDelay)*(1+ReportRetryJitterFactor))))
+			Expect(reportedScenarios).To(ConsistOf("scenario-1", "scenario-2"))
+
+			srs, err := status.NewSnapshotReportStatusFromSnapshot(mixedSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(srs.Scenarios["failing-scenario"].FailedAttempts).To(Equal(1))
+			Expect(srs.Scenarios["failing-scenario"].LastAttemptTime).NotTo(BeNil())
+			Expect(srs.Scenarios["scenario-1"].LastUpdateTime).NotTo(BeNil())
+			Expect(srs.Scenarios["scenario-2"].LastUpdateTime).NotTo(BeNil())
+		})
+	})
+
+	When("the integration PipelineRun of a test in progress disappears", func() {
+		var inProgressStatus = func(startTime time.Time) string {
+			return fmt.Sprintf("[{\"scenario\":\"%s\",\"status\":\"InProgress\",\"testPipelineRunName\":\"vanished-pipelinerun\","+
+				"\"startTime\":\"%[2]s\",\"lastUpdateTime\":\"%[2]s\",\"details\":\"Integration test is running\"}]",
+				integrationTestScenario.Name, startTime.Format(time.RFC3339))
+		}
+		var recreatedInProgressStatus = func(startTime time.Time, recreations int) string {
+			return fmt.Sprintf("[{\"scenario\":\"%s\",\"status\":\"InProgress\",\"testPipelineRunName\":\"vanished-pipelinerun\","+
+				"\"startTime\":\"%[2]s\",\"lastUpdateTime\":\"%[2]s\",\"details\":\"Integration test is running\",\"recreations\":%[3]d}]",
+				integrationTestScenario.Name, startTime.Format(time.RFC3339), recreations)
+		}
+
+		It("marks the test as deleted and reports it to the git provider", func() {
+			vanishedSnapshot := hasSnapshot.DeepCopy()
+			vanishedSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodePullRequestType
+			vanishedSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = inProgressStatus(time.Now().Add(-10 * time.Minute))
+
+			adapter = NewAdapter(ctx, vanishedSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := adapter.EnsureVanishedPipelineRunsMarkedAsDeleted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Eventually(func() bool {
+				snapshot := &applicationapiv1alpha1.Snapshot{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(hasSnapshot), snapshot); err != nil {
+					return false
+				}
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				if err != nil {
+					return false
+				}
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				return ok && detail.Status == intgteststat.IntegrationTestStatusDeleted && detail.CompletionTime != nil &&
+					detail.Details == "Integration test which was running as pipeline run 'vanished-pipelinerun' disappeared before it could finish"
+			}, time.Second*10).Should(BeTrue())
+
+			ctrl := gomock.NewController(GinkgoT())
+			mockReporter := status.NewMockReporterInterface(ctrl)
+			mockStatus := status.NewMockStatusInterface(ctrl)
+			mockReporter.EXPECT().GetReporterName().Return("mocked_reporter")
+			mockStatus.EXPECT().GetReporters(gomock.Any()).Return([]status.ReporterInterface{mockReporter})
+			mockStatus.EXPECT().ReportSnapshotStatusToReporters(gomock.Any(), gomock.Any(), gomock.Cond(func(x any) bool {
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(x.(*applicationapiv1alpha1.Snapshot))
+				if err != nil {
+					return false
+				}
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				return ok && detail.Status == intgteststat.IntegrationTestStatusDeleted
+			})).Times(1)
+			adapter.status = mockStatus
+
+			result, err = adapter.EnsureSnapshotTestStatusReportedToGitProvider()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+		})
+
+		It("requeues the check until the grace period of a freshly started test expires", func() {
+			freshSnapshot := hasSnapshot.DeepCopy()
+			freshSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = inProgressStatus(time.Now())
+
+			adapter = NewAdapter(ctx, freshSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := adapter.EnsureVanishedPipelineRunsMarkedAsDeleted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">", 0))
+			Expect(result.RequeueDelay).To(BeNumerically("<=", VanishedPipelineRunGracePeriod))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(freshSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+		})
+
+		It("resets a recently started test and requests the recreation of its PipelineRun", func() {
+			recentSnapshot := hasSnapshot.DeepCopy()
+			recentSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = recreatedInProgressStatus(time.Now().Add(-3*time.Minute), 0)
+
+			adapter = NewAdapter(ctx, recentSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := adapter.EnsureVanishedPipelineRunsMarkedAsDeleted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Eventually(func() bool {
+				snapshot := &applicationapiv1alpha1.Snapshot{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(hasSnapshot), snapshot); err != nil {
+					return false
+				}
+				if snapshot.Annotations[gitops.SnapshotPipelineRunsRecreationAnnotation] == "" {
+					return false
+				}
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				if err != nil {
+					return false
+				}
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				return ok && detail.Status == intgteststat.IntegrationTestStatusPending && detail.TestPipelineRunName == "" &&
+					detail.Recreations == 1
+			}, time.Second*10).Should(BeTrue())
+		})
+
+		It("marks the test as deleted once its PipelineRun was recreated too many times", func() {
+			exhaustedSnapshot := hasSnapshot.DeepCopy()
+			exhaustedSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = recreatedInProgressStatus(time.Now().Add(-3*time.Minute), MaxPipelineRunRecreations)
+
+			adapter = NewAdapter(ctx, exhaustedSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := adapter.EnsureVanishedPipelineRunsMarkedAsDeleted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(exhaustedSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+			Expect(detail.Recreations).To(Equal(MaxPipelineRunRecreations))
+		})
+
+		It("doesn't recreate the PipelineRun of a finished test cleaned up after its TTL", func() {
+			finishedSnapshot := hasSnapshot.DeepCopy()
+			finishedSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = fmt.Sprintf("[{\"scenario\":\"%s\",\"status\":\"TestPassed\","+
+				"\"testPipelineRunName\":\"vanished-pipelinerun\",\"startTime\":\"%[2]s\",\"completionTime\":\"%[2]s\","+
+				"\"lastUpdateTime\":\"%[2]s\",\"details\":\"Integration test passed\"}]",
+				integrationTestScenario.Name, time.Now().Add(-3*time.Minute).Format(time.RFC3339))
+			delete(finishedSnapshot.Annotations, gitops.SnapshotPipelineRunsRecreationAnnotation)
+
+			adapter = NewAdapter(ctx, finishedSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			result, err := adapter.EnsureVanishedPipelineRunsMarkedAsDeleted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Expect(finishedSnapshot.Annotations).NotTo(HaveKey(gitops.SnapshotPipelineRunsRecreationAnnotation))
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(finishedSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			Expect(detail.Recreations).To(BeZero())
+		})
+	})
+
+	When("a test is stuck in progress past the watchdog deadline", func() {
+		var (
+			stuckSnapshot *applicationapiv1alpha1.Snapshot
+			scenarios     []v1beta2.IntegrationTestScenario
+		)
+		var runningPipelineRun = func(lastTransitionTime time.Time) *tektonv1.PipelineRun {
+			pipelineRun := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stuck-pipelinerun",
+					Namespace: "default",
+				},
+			}
+			pipelineRun.Status.Conditions = v1.Conditions{
+				apis.Condition{
+					Type:               apis.ConditionSucceeded,
+					Status:             "Unknown",
+					Reason:             "Running",
+					LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(lastTransitionTime)},
+				},
+			}
+			return pipelineRun
+		}
+
+		BeforeEach(func() {
+			stuckSnapshot = hasSnapshot.DeepCopy()
+			stuckSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = fmt.Sprintf(
+				"[{\"scenario\":\"%s\",\"status\":\"InProgress\",\"testPipelineRunName\":\"stuck-pipelinerun\","+
+					"\"startTime\":\"%[2]s\",\"lastUpdateTime\":\"%[2]s\",\"details\":\"Integration test is running\"}]",
+				integrationTestScenario.Name, time.Now().Add(-25*time.Hour).Format(time.RFC3339))
+			scenarios = []v1beta2.IntegrationTestScenario{*integrationTestScenario}
+		})
+
+		It("fails the test whose pipelineRun doesn't exist anymore by the watchdog timeout", func() {
+			adapter = NewAdapter(ctx, stuckSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.GetPipelineRunContextKey,
+					Err:        errors.NewNotFound(schema.GroupResource{}, "stuck-pipelinerun"),
+				},
+			})
+
+			result, err := adapter.EnsureStuckTestsFailedByWatchdog()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(stuckSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+			Expect(detail.Details).To(ContainSubstring("watchdog timeout"))
+			Expect(detail.WatchdogTimedOutAfter).To(Equal(DefaultWatchdogDeadline.String()))
+			Expect(detail.CompletionTime).NotTo(BeNil())
+		})
+
+		It("fails the test whose pipelineRun stopped progressing by the watchdog timeout and cancels the pipelineRun", func() {
+			stuckPipelineRun := runningPipelineRun(time.Now().Add(-20 * time.Hour))
+			stuckPipelineRun.Spec = tektonv1.PipelineRunSpec{
+				PipelineRef: &tektonv1.PipelineRef{Name: "stuck-pipeline"},
+			}
+			stuckCondition := stuckPipelineRun.Status.Status
+			Expect(k8sClient.Create(ctx, stuckPipelineRun)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, stuckPipelineRun)).Should(Succeed())
+			}()
+			stuckPipelineRun.Status.Status = stuckCondition
+
+			adapter = NewAdapter(ctx, stuckSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.GetPipelineRunContextKey,
+					Resource:   stuckPipelineRun,
+				},
+			})
+
+			result, err := adapter.EnsureStuckTestsFailedByWatchdog()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(stuckSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+			Expect(detail.WatchdogTimedOutAfter).To(Equal(DefaultWatchdogDeadline.String()))
+
+			Eventually(func() bool {
+				pipelineRun := &tektonv1.PipelineRun{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(stuckPipelineRun), pipelineRun); err != nil {
+					return false
+				}
+				return pipelineRun.Spec.Status == tektonv1.PipelineRunSpecStatusCancelledRunFinally
+			}, time.Second*10).Should(BeTrue())
+		})
+
+		It("doesn't fail the test whose pipelineRun is still progressing", func() {
+			adapter = NewAdapter(ctx, stuckSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+				{
+					ContextKey: loader.GetPipelineRunContextKey,
+					Resource:   runningPipelineRun(time.Now().Add(-10 * time.Minute)),
+				},
+			})
+
+			result, err := adapter.EnsureStuckTestsFailedByWatchdog()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">", 0))
+			Expect(result.RequeueDelay).To(BeNumerically("<=", WatchdogPipelineRunIdlePeriod))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(stuckSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+		})
+
+		It("requeues the check until the deadline overridden by the scenario passes", func() {
+			scenarios[0].Spec.WatchdogDeadline = "48h"
+			adapter = NewAdapter(ctx, stuckSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+			})
+
+			result, err := adapter.EnsureStuckTestsFailedByWatchdog()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically("~", 23*time.Hour, time.Minute))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(stuckSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+		})
+
+		It("defers the requeue of the check until the operations after it ran", func() {
+			scenarios[0].Spec.WatchdogDeadline = "48h"
+			stuckSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodePullRequestType
+			adapter = NewAdapter(ctx, stuckSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.AllIntegrationTestScenariosContextKey,
+					Resource:   scenarios,
+				},
+			})
+
+			result, err := adapter.deferRequeue(adapter.EnsureStuckTestsFailedByWatchdog)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			result, err = adapter.EnsureDeferredRequeue()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically("~", 23*time.Hour, time.Minute))
+		})
+	})
+
+	When("the pull request of a snapshot with unfinished tests is closed or merged", func() {
+		var (
+			prSnapshot *applicationapiv1alpha1.Snapshot
+			mockStatus *status.MockStatusInterface
+		)
+
+		BeforeEach(func() {
+			prSnapshot = hasSnapshot.DeepCopy()
+			prSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodePullRequestType
+			prSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = fmt.Sprintf(
+				"[{\"scenario\":\"%s\",\"status\":\"InProgress\",\"testPipelineRunName\":\"running-pipelinerun\","+
+					"\"startTime\":\"%[2]s\",\"lastUpdateTime\":\"%[2]s\",\"details\":\"Integration test is running\"}]",
+				integrationTestScenario.Name, time.Now().Add(-10*time.Minute).Format(time.RFC3339))
+
+			mockStatus = status.NewMockStatusInterface(gomock.NewController(GinkgoT()))
+			adapter = NewAdapter(ctx, prSnapshot, hasApp, logger, loader.NewMockLoader(), k8sClient)
+			adapter.status = mockStatus
+			adapter.context = toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: loader.GetPipelineRunContextKey,
+					Err:        errors.NewNotFound(schema.GroupResource{}, "running-pipelinerun"),
+				},
+			})
+		})
+
+		It("cancels the unfinished tests when the pull request is closed", func() {
+			mockStatus.EXPECT().GetPRMRStateInSnapshot(gomock.Any(), gomock.Any()).Return(status.PRMRStateClosed, nil)
+
+			result, err := adapter.EnsureTestingCanceledForClosedPRMR()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+
+			Eventually(func() bool {
+				snapshot := &applicationapiv1alpha1.Snapshot{}
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(hasSnapshot), snapshot); err != nil {
+					return false
+				}
+				statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+				if err != nil {
+					return false
+				}
+				detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+				return ok && detail.Status == intgteststat.IntegrationTestStatusDeleted &&
+					detail.Details == "Integration test was canceled because the pull request was closed" &&
+					gitops.IsSnapshotCanceledByPRMR(snapshot) &&
+					gitops.IsSnapshotStatusConditionSet(snapshot, gitops.AppStudioTestSucceededCondition, metav1.ConditionFalse, gitops.AppStudioTestSucceededConditionPRClosed)
+			}, time.Second*10).Should(BeTrue())
+		})
+
+		It("lets the tests finish when the pull request is merged", func() {
+			mockStatus.EXPECT().GetPRMRStateInSnapshot(gomock.Any(), gomock.Any()).Return(status.PRMRStateMerged, nil)
+
+			result, err := adapter.EnsureTestingCanceledForClosedPRMR()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(PRMRStateCheckInterval))
+			Expect(gitops.IsSnapshotCanceledByPRMR(prSnapshot)).To(BeFalse())
+			_, checked := gitops.GetPRMRStateCheckTime(prSnapshot)
+			Expect(checked).To(BeTrue())
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(prSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusInProgress))
+		})
+
+		It("cancels the unfinished tests when the pull request is merged and the application opted in", func() {
+			adapter.application = hasApp.DeepCopy()
+			adapter.application.Annotations = map[string]string{gitops.CancelOnMergedPRMRAnnotation: "true"}
+			mockStatus.EXPECT().GetPRMRStateInSnapshot(gomock.Any(), gomock.Any()).Return(status.PRMRStateMerged, nil)
+
+			result, err := adapter.EnsureTestingCanceledForClosedPRMR()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeFalse())
+			Expect(prSnapshot.GetAnnotations()[gitops.SnapshotCanceledByPRMRAnnotation]).To(Equal("merged"))
+
+			statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(prSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			detail, ok := statuses.GetScenarioStatus(integrationTestScenario.Name)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+		})
+
+		It("doesn't check the state of the pull request again before the check interval passes", func() {
+			prSnapshot.Annotations[gitops.SnapshotPRMRStateCheckedAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+			mockStatus.EXPECT().GetPRMRStateInSnapshot(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := adapter.EnsureTestingCanceledForClosedPRMR()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(BeNumerically(">", 0))
+			Expect(result.RequeueDelay).To(BeNumerically("<", PRMRStateCheckInterval))
+		})
+
+		It("doesn't check the state of the pull request of an override snapshot carrying stale pull request labels", func() {
+			prSnapshot.Labels[gitops.SnapshotTypeLabel] = gitops.SnapshotOverrideType
+			mockStatus.EXPECT().GetPRMRStateInSnapshot(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := adapter.EnsureTestingCanceledForClosedPRMR()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeFalse())
+			Expect(result.RequeueRequest).To(BeFalse())
+			_, checked := gitops.GetPRMRStateCheckTime(prSnapshot)
+			Expect(checked).To(BeFalse())
+		})
 	})
 
 	When("New Adapter is created for a push-type Snapshot that passed all tests", func() {
@@ -326,6 +895,8 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 
 			Expect(meta.FindStatusCondition(hasSnapshot.Status.Conditions, gitops.AppStudioTestSucceededCondition)).ToNot(BeNil())
 			Expect(meta.IsStatusConditionTrue(hasSnapshot.Status.Conditions, gitops.AppStudioTestSucceededCondition)).To(BeTrue())
+			Expect(gitops.IsSnapshotStatusConditionSet(hasSnapshot, gitops.AppStudioTestsCompletedCondition,
+				metav1.ConditionTrue, gitops.AppStudioTestSucceededConditionSatisfied)).To(BeTrue())
 
 			Expect(meta.FindStatusCondition(hasSnapshot.Status.Conditions, gitops.AppStudioIntegrationStatusCondition)).ToNot(BeNil())
 			Expect(meta.IsStatusConditionTrue(hasSnapshot.Status.Conditions, gitops.AppStudioIntegrationStatusCondition)).To(BeTrue())
@@ -437,6 +1008,8 @@ var _ = Describe("Snapshot Adapter", Ordered, func() {
 
 			Expect(meta.FindStatusCondition(hasSnapshot.Status.Conditions, gitops.AppStudioTestSucceededCondition)).ToNot(BeNil())
 			Expect(meta.IsStatusConditionFalse(hasSnapshot.Status.Conditions, gitops.AppStudioTestSucceededCondition)).To(BeTrue())
+			Expect(gitops.IsSnapshotStatusConditionSet(hasSnapshot, gitops.AppStudioTestsCompletedCondition,
+				metav1.ConditionTrue, gitops.AppStudioTestSucceededConditionFailed)).To(BeTrue())
 
 			Expect(meta.FindStatusCondition(hasSnapshot.Status.Conditions, gitops.AppStudioIntegrationStatusCondition)).ToNot(BeNil())
 			Expect(meta.IsStatusConditionTrue(hasSnapshot.Status.Conditions, gitops.AppStudioIntegrationStatusCondition)).To(BeTrue())
@@ -23,12 +23,14 @@ import (
 	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/integration-service/loader"
+	"github.com/konflux-ci/integration-service/status"
 	"github.com/konflux-ci/operator-toolkit/controller"
 	toolkitpredicates "github.com/konflux-ci/operator-toolkit/predicates"
 	toolkitutils "github.com/konflux-ci/operator-toolkit/utils"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -37,8 +39,12 @@ import (
 // Reconciler reconciles an Snapshot object
 type Reconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	PodLogClient status.PodLogClient
+	EventEmitter *status.EventEmitter
+	// APIReader lists the earlier Snapshots of an Application from the API server in pages, if set
+	APIReader client.Reader
 }
 
 // NewStatusReportReconciler creates and returns a Reconciler.
@@ -54,11 +60,17 @@ func NewStatusReportReconciler(client client.Client, logger *logr.Logger, scheme
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=snapshots/status,verbs=get
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications,verbs=get;list;watch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=applications/status,verbs=get
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := helpers.IntegrationLogger{Logger: r.Log.WithValues("snapshot", req.NamespacedName)}
+	ctx = loader.WithMemoization(ctx)
+	if r.APIReader != nil {
+		ctx = loader.WithAPIReader(ctx, r.APIReader)
+	}
 	loader := loader.NewLoader()
 
 	logger.Info("start to process snapshot test status since there is change in annotation test.appstudio.openshift.io/status", "snapshot", req.NamespacedName)
@@ -86,10 +98,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	logger = logger.WithApp(*application)
 
-	adapter := NewAdapter(ctx, snapshot, application, logger, loader, r.Client)
+	statusOpts := []status.StatusOption{status.WithDryRun(status.IsDryRunEnabled(application))}
+	if r.PodLogClient != nil {
+		statusOpts = append(statusOpts, status.WithPodLogClient(r.PodLogClient))
+	}
+	if r.EventEmitter != nil {
+		statusOpts = append(statusOpts, status.WithEventEmitter(r.EventEmitter))
+	}
+
+	adapter := NewAdapter(ctx, snapshot, application, logger, loader, r.Client, statusOpts...)
 	return controller.ReconcileHandler([]controller.Operation{
 		adapter.EnsureSnapshotFinishedAllTests,
+		// the tests updated by the recovery operations are reported in the same reconcile, their requeues
+		// are deferred so they don't stop the reporting
+		adapter.deferRequeue(adapter.EnsureVanishedPipelineRunsMarkedAsDeleted),
+		adapter.deferRequeue(adapter.EnsureStuckTestsFailedByWatchdog),
+		adapter.deferRequeue(adapter.EnsureTestingCanceledForClosedPRMR),
+		adapter.EnsureTestStatusEventsEmitted,
 		adapter.EnsureSnapshotTestStatusReportedToGitProvider,
+		adapter.EnsureFailedRequiredTestsNotified,
+		adapter.EnsureTestResultsArchived,
+		adapter.EnsureDeferredRequeue,
 	})
 }
 
@@ -97,11 +126,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 type AdapterInterface interface {
 	EnsureSnapshotTestStatusReportedToGitHub() (controller.OperationResult, error)
 	EnsureSnapshotFinishedAllTests() (controller.OperationResult, error)
+	EnsureVanishedPipelineRunsMarkedAsDeleted() (controller.OperationResult, error)
+	EnsureStuckTestsFailedByWatchdog() (controller.OperationResult, error)
+	EnsureTestingCanceledForClosedPRMR() (controller.OperationResult, error)
 }
 
 // SetupController creates a new Integration controller and adds it to the Manager.
 func SetupController(manager ctrl.Manager, log *logr.Logger) error {
-	return setupControllerWithManager(manager, NewStatusReportReconciler(manager.GetClient(), log, manager.GetScheme()))
+	reconciler := NewStatusReportReconciler(manager.GetClient(), log, manager.GetScheme())
+	reconciler.APIReader = manager.GetAPIReader()
+
+	// pod logs are not served by the controller-runtime client, a clientset is needed to fetch them
+	clientset, err := kubernetes.NewForConfig(manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler.PodLogClient = status.NewPodLogClient(clientset)
+
+	// events about integration test lifecycle transitions are emitted only when a sink is configured
+	if emitter := status.NewEventEmitterFromEnv(reconciler.Log); emitter != nil {
+		if err := manager.Add(emitter); err != nil {
+			return err
+		}
+		reconciler.EventEmitter = emitter
+	}
+
+	return setupControllerWithManager(manager, reconciler)
 }
 
 // setupControllerWithManager sets up the controller with the Manager which monitors new Snapshots
@@ -111,7 +161,10 @@ func setupControllerWithManager(manager ctrl.Manager, controller *Reconciler) er
 		WithEventFilter(
 			predicate.And(
 				toolkitpredicates.IgnoreBackups{},
-				gitops.SnapshotTestAnnotationChangePredicate(),
+				predicate.Or(
+					gitops.SnapshotTestAnnotationChangePredicate(),
+					gitops.SnapshotWithoutIntegrationTestsPredicate(),
+				),
 			)).
 		Complete(controller)
 }
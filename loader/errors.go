@@ -0,0 +1,39 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IsResourceNotFound returns true when the given error, returned by a loader getter, reports that the requested
+// resource doesn't exist in the cluster. The errors of the getters are wrapped, so they keep satisfying this
+// predicate regardless of the context added to them.
+func IsResourceNotFound(err error) bool {
+	return err != nil && errors.IsNotFound(err)
+}
+
+// wrapGetError adds the kind, namespace and name of the requested object to the error of a failed Get, nil is
+// returned when there is no error. The original error is wrapped, so IsResourceNotFound still matches it.
+func wrapGetError(err error, kind, namespace, name string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to get the %s %s/%s: %w", kind, namespace, name, err)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/tekton"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Loader NotFound errors", func() {
+	var (
+		loader     ObjectLoader
+		fakeClient client.Client
+	)
+
+	snapshot := &applicationapiv1alpha1.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "missing-snapshot",
+			Namespace: "default",
+			Labels:    map[string]string{gitops.SnapshotComponentLabel: "missing-component"},
+		},
+		Spec: applicationapiv1alpha1.SnapshotSpec{Application: "missing-application"},
+	}
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "missing-pipelinerun",
+			Namespace: "default",
+			Labels: map[string]string{
+				tekton.PipelineRunComponentLabel:   "missing-component",
+				tekton.PipelineRunApplicationLabel: "missing-application",
+				tekton.EnvironmentNameLabel:        "missing-environment",
+				tekton.SnapshotNameLabel:           "missing-snapshot",
+			},
+		},
+	}
+	component := &applicationapiv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing-component", Namespace: "default"},
+		Spec:       applicationapiv1alpha1.ComponentSpec{Application: "missing-application"},
+	}
+	environment := &applicationapiv1alpha1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing-environment", Namespace: "default"},
+		Spec: applicationapiv1alpha1.EnvironmentSpec{
+			Configuration: applicationapiv1alpha1.EnvironmentConfiguration{
+				Target: applicationapiv1alpha1.EnvironmentTarget{
+					DeploymentTargetClaim: applicationapiv1alpha1.DeploymentTargetClaimConfig{ClaimName: "missing-claim"},
+				},
+			},
+		},
+	}
+	deploymentTargetClaim := &applicationapiv1alpha1.DeploymentTargetClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing-claim", Namespace: "default"},
+		Spec:       applicationapiv1alpha1.DeploymentTargetClaimSpec{TargetName: "missing-target"},
+	}
+
+	BeforeEach(func() {
+		loader = NewLoader()
+		scheme := runtime.NewScheme()
+		Expect(applicationapiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+		Expect(v1beta2.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+	})
+
+	DescribeTable("keeps the NotFound errors of the missing objects",
+		func(get func(ctx context.Context) error, expectedName string) {
+			for _, ctx := range []context.Context{context.Background(), WithMemoization(context.Background())} {
+				err := get(ctx)
+				Expect(err).To(HaveOccurred())
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				Expect(IsResourceNotFound(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("default/%s", expectedName)))
+			}
+		},
+		Entry("GetApplicationFromSnapshot", func(ctx context.Context) error {
+			_, err := loader.GetApplicationFromSnapshot(ctx, fakeClient, snapshot)
+			return err
+		}, "missing-application"),
+		Entry("GetComponentFromSnapshot", func(ctx context.Context) error {
+			_, err := loader.GetComponentFromSnapshot(ctx, fakeClient, snapshot)
+			return err
+		}, "missing-component"),
+		Entry("GetComponentFromPipelineRun", func(ctx context.Context) error {
+			_, err := loader.GetComponentFromPipelineRun(ctx, fakeClient, pipelineRun)
+			return err
+		}, "missing-component"),
+		Entry("GetApplicationFromPipelineRun", func(ctx context.Context) error {
+			_, err := loader.GetApplicationFromPipelineRun(ctx, fakeClient, pipelineRun)
+			return err
+		}, "missing-application"),
+		Entry("GetApplicationFromComponent", func(ctx context.Context) error {
+			_, err := loader.GetApplicationFromComponent(ctx, fakeClient, component)
+			return err
+		}, "missing-application"),
+		Entry("GetEnvironmentFromIntegrationPipelineRun", func(ctx context.Context) error {
+			_, err := loader.GetEnvironmentFromIntegrationPipelineRun(ctx, fakeClient, pipelineRun)
+			return err
+		}, "missing-environment"),
+		Entry("GetSnapshotFromPipelineRun", func(ctx context.Context) error {
+			_, err := loader.GetSnapshotFromPipelineRun(ctx, fakeClient, pipelineRun)
+			return err
+		}, "missing-snapshot"),
+		Entry("GetDeploymentTargetClaimForEnvironment", func(ctx context.Context) error {
+			_, err := loader.GetDeploymentTargetClaimForEnvironment(ctx, fakeClient, environment)
+			return err
+		}, "missing-claim"),
+		Entry("GetDeploymentTargetForDeploymentTargetClaim", func(ctx context.Context) error {
+			_, err := loader.GetDeploymentTargetForDeploymentTargetClaim(ctx, fakeClient, deploymentTargetClaim)
+			return err
+		}, "missing-target"),
+		Entry("GetScenario", func(ctx context.Context) error {
+			_, err := loader.GetScenario(ctx, fakeClient, "missing-scenario", "default")
+			return err
+		}, "missing-scenario"),
+		Entry("GetPipelineRun", func(ctx context.Context) error {
+			_, err := loader.GetPipelineRun(ctx, fakeClient, "missing-pipelinerun", "default")
+			return err
+		}, "missing-pipelinerun"),
+		Entry("GetComponent", func(ctx context.Context) error {
+			_, err := loader.GetComponent(ctx, fakeClient, "missing-component", "default")
+			return err
+		}, "missing-component"),
+	)
+
+	It("doesn't report the other errors as NotFound", func() {
+		Expect(IsResourceNotFound(nil)).To(BeFalse())
+		Expect(IsResourceNotFound(fmt.Errorf("failed to get the Component: %w",
+			k8serrors.NewForbidden(applicationapiv1alpha1.GroupVersion.WithResource("components").GroupResource(), "component", nil)))).To(BeFalse())
+	})
+})
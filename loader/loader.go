@@ -20,6 +20,8 @@ package loader
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
 	"github.com/konflux-ci/integration-service/gitops"
@@ -50,11 +52,13 @@ type ObjectLoader interface {
 	GetSnapshotFromPipelineRun(ctx context.Context, c client.Client, pipelineRun *tektonv1.PipelineRun) (*applicationapiv1alpha1.Snapshot, error)
 	GetAllIntegrationTestScenariosForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]v1beta2.IntegrationTestScenario, error)
 	GetRequiredIntegrationTestScenariosForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]v1beta2.IntegrationTestScenario, error)
+	GetIntegrationTestScenariosForApplicationMatching(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, selector labels.Selector) (*[]v1beta2.IntegrationTestScenario, error)
 	GetDeploymentTargetClaimForEnvironment(ctx context.Context, c client.Client, environment *applicationapiv1alpha1.Environment) (*applicationapiv1alpha1.DeploymentTargetClaim, error)
 	GetDeploymentTargetForDeploymentTargetClaim(ctx context.Context, c client.Client, dtc *applicationapiv1alpha1.DeploymentTargetClaim) (*applicationapiv1alpha1.DeploymentTarget, error)
 	FindExistingSnapshotEnvironmentBinding(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, environment *applicationapiv1alpha1.Environment) (*applicationapiv1alpha1.SnapshotEnvironmentBinding, error)
 	GetAllPipelineRunsForSnapshotAndScenario(ctx context.Context, c client.Client, snapshot *applicationapiv1alpha1.Snapshot, integrationTestScenario *v1beta2.IntegrationTestScenario) (*[]tektonv1.PipelineRun, error)
 	GetAllSnapshots(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]applicationapiv1alpha1.Snapshot, error)
+	GetFilteredSnapshots(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, filter SnapshotFilter) (*[]applicationapiv1alpha1.Snapshot, error)
 	GetAutoReleasePlansForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]releasev1alpha1.ReleasePlan, error)
 	GetScenario(ctx context.Context, c client.Client, name, namespace string) (*v1beta2.IntegrationTestScenario, error)
 	GetAllEnvironmentsForScenario(ctx context.Context, c client.Client, integrationTestScenario *v1beta2.IntegrationTestScenario) (*[]applicationapiv1alpha1.Environment, error)
@@ -62,8 +66,13 @@ type ObjectLoader interface {
 	GetAllTaskRunsWithMatchingPipelineRunLabel(ctx context.Context, c client.Client, pipelineRun *tektonv1.PipelineRun) (*[]tektonv1.TaskRun, error)
 	GetPipelineRun(ctx context.Context, c client.Client, name, namespace string) (*tektonv1.PipelineRun, error)
 	GetComponent(ctx context.Context, c client.Client, name, namespace string) (*applicationapiv1alpha1.Component, error)
+	GetAllPullRequestSnapshotsForComponent(ctx context.Context, c client.Client, namespace, componentName string) (*[]applicationapiv1alpha1.Snapshot, error)
 }
 
+// SnapshotFilter is called for each listed Snapshot, it returns whether the Snapshot is kept and whether the
+// remaining Snapshots can be skipped
+type SnapshotFilter func(snapshot *applicationapiv1alpha1.Snapshot) (keep bool, stop bool)
+
 type loader struct{}
 
 func NewLoader() ObjectLoader {
@@ -122,7 +131,8 @@ func (l *loader) GetAllApplicationComponents(ctx context.Context, c client.Clien
 // If the Snapshot doesn't specify an Component or this is not found in the cluster, an error will be returned.
 func (l *loader) GetApplicationFromSnapshot(ctx context.Context, c client.Client, snapshot *applicationapiv1alpha1.Snapshot) (*applicationapiv1alpha1.Application, error) {
 	application := &applicationapiv1alpha1.Application{}
-	return application, toolkit.GetObject(snapshot.Spec.Application, snapshot.Namespace, c, ctx, application)
+	err := getMemoizedObject(ctx, c, snapshot.Spec.Application, snapshot.Namespace, application)
+	return application, wrapGetError(err, "Application", snapshot.Namespace, snapshot.Spec.Application)
 }
 
 // GetComponentFromSnapshot loads from the cluster the Component referenced in the given Snapshot.
@@ -130,13 +140,10 @@ func (l *loader) GetApplicationFromSnapshot(ctx context.Context, c client.Client
 func (l *loader) GetComponentFromSnapshot(ctx context.Context, c client.Client, snapshot *applicationapiv1alpha1.Snapshot) (*applicationapiv1alpha1.Component, error) {
 	if componentLabel, ok := snapshot.Labels[gitops.SnapshotComponentLabel]; ok {
 		component := &applicationapiv1alpha1.Component{}
-		err := c.Get(ctx, types.NamespacedName{
-			Namespace: snapshot.Namespace,
-			Name:      componentLabel,
-		}, component)
+		err := getMemoizedObject(ctx, c, componentLabel, snapshot.Namespace, component)
 
 		if err != nil {
-			return nil, err
+			return nil, wrapGetError(err, "Component", snapshot.Namespace, componentLabel)
 		}
 
 		return component, nil
@@ -151,13 +158,10 @@ func (l *loader) GetComponentFromSnapshot(ctx context.Context, c client.Client,
 func (l *loader) GetComponentFromPipelineRun(ctx context.Context, c client.Client, pipelineRun *tektonv1.PipelineRun) (*applicationapiv1alpha1.Component, error) {
 	if componentName, found := pipelineRun.Labels[tekton.PipelineRunComponentLabel]; found {
 		component := &applicationapiv1alpha1.Component{}
-		err := c.Get(ctx, types.NamespacedName{
-			Namespace: pipelineRun.Namespace,
-			Name:      componentName,
-		}, component)
+		err := getMemoizedObject(ctx, c, componentName, pipelineRun.Namespace, component)
 
 		if err != nil {
-			return nil, err
+			return nil, wrapGetError(err, "Component", pipelineRun.Namespace, componentName)
 		}
 
 		return component, nil
@@ -171,13 +175,10 @@ func (l *loader) GetComponentFromPipelineRun(ctx context.Context, c client.Clien
 func (l *loader) GetApplicationFromPipelineRun(ctx context.Context, c client.Client, pipelineRun *tektonv1.PipelineRun) (*applicationapiv1alpha1.Application, error) {
 	if applicationName, found := pipelineRun.Labels[tekton.PipelineRunApplicationLabel]; found {
 		application := &applicationapiv1alpha1.Application{}
-		err := c.Get(ctx, types.NamespacedName{
-			Namespace: pipelineRun.Namespace,
-			Name:      applicationName,
-		}, application)
+		err := getMemoizedObject(ctx, c, applicationName, pipelineRun.Namespace, application)
 
 		if err != nil {
-			return nil, err
+			return nil, wrapGetError(err, "Application", pipelineRun.Namespace, applicationName)
 		}
 
 		return application, nil
@@ -190,13 +191,10 @@ func (l *loader) GetApplicationFromPipelineRun(ctx context.Context, c client.Cli
 // specify an Application or this is not found in the cluster, an error will be returned.
 func (l *loader) GetApplicationFromComponent(ctx context.Context, c client.Client, component *applicationapiv1alpha1.Component) (*applicationapiv1alpha1.Application, error) {
 	application := &applicationapiv1alpha1.Application{}
-	err := c.Get(ctx, types.NamespacedName{
-		Namespace: component.Namespace,
-		Name:      component.Spec.Application,
-	}, application)
+	err := getMemoizedObject(ctx, c, component.Spec.Application, component.Namespace, application)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapGetError(err, "Application", component.Namespace, component.Spec.Application)
 	}
 
 	return application, nil
@@ -213,7 +211,7 @@ func (l *loader) GetEnvironmentFromIntegrationPipelineRun(ctx context.Context, c
 		}, environment)
 
 		if err != nil {
-			return nil, err
+			return nil, wrapGetError(err, "Environment", pipelineRun.Namespace, environmentLabel)
 		}
 
 		return environment, nil
@@ -233,7 +231,7 @@ func (l *loader) GetSnapshotFromPipelineRun(ctx context.Context, c client.Client
 		}, snapshot)
 
 		if err != nil {
-			return nil, err
+			return nil, wrapGetError(err, "Snapshot", pipelineRun.Namespace, snapshotName)
 		}
 
 		return snapshot, nil
@@ -244,39 +242,37 @@ func (l *loader) GetSnapshotFromPipelineRun(ctx context.Context, c client.Client
 
 // GetAllIntegrationTestScenariosForApplication returns all IntegrationTestScenarios used by the application being processed.
 func (l *loader) GetAllIntegrationTestScenariosForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]v1beta2.IntegrationTestScenario, error) {
-	integrationList := &v1beta2.IntegrationTestScenarioList{}
-
-	opts := &client.ListOptions{
-		Namespace:     application.Namespace,
-		FieldSelector: fields.OneTermEqualSelector("spec.application", application.Name),
-	}
-
-	err := c.List(ctx, integrationList, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	return &integrationList.Items, nil
+	return l.GetIntegrationTestScenariosForApplicationMatching(ctx, c, application, labels.Everything())
 }
 
 // GetRequiredIntegrationTestScenariosForApplication returns the IntegrationTestScenarios used by the application being processed.
 // An IntegrationTestScenarios will only be returned if it has the test.appstudio.openshift.io/optional
 // label not set to true or if it is missing the label entirely.
 func (l *loader) GetRequiredIntegrationTestScenariosForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]v1beta2.IntegrationTestScenario, error) {
-	integrationList := &v1beta2.IntegrationTestScenarioList{}
-	labelRequirement, err := labels.NewRequirement("test.appstudio.openshift.io/optional", selection.NotIn, []string{"true"})
+	labelRequirement, err := labels.NewRequirement(tekton.OptionalLabel, selection.NotIn, []string{"true"})
 	if err != nil {
 		return nil, err
 	}
-	labelSelector := labels.NewSelector().Add(*labelRequirement)
+
+	return l.GetIntegrationTestScenariosForApplicationMatching(ctx, c, application, labels.NewSelector().Add(*labelRequirement))
+}
+
+// GetIntegrationTestScenariosForApplicationMatching returns the IntegrationTestScenarios used by the application being
+// processed whose labels match the given selector. The selector is passed to the List call instead of filtering
+// the scenarios afterwards, a nil selector matches all the scenarios.
+func (l *loader) GetIntegrationTestScenariosForApplicationMatching(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, selector labels.Selector) (*[]v1beta2.IntegrationTestScenario, error) {
+	integrationList := &v1beta2.IntegrationTestScenarioList{}
+	if selector == nil {
+		selector = labels.Everything()
+	}
 
 	opts := &client.ListOptions{
 		Namespace:     application.Namespace,
 		FieldSelector: fields.OneTermEqualSelector("spec.application", application.Name),
-		LabelSelector: labelSelector,
+		LabelSelector: selector,
 	}
 
-	err = c.List(ctx, integrationList, opts)
+	err := c.List(ctx, integrationList, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +293,7 @@ func (l *loader) GetDeploymentTargetClaimForEnvironment(ctx context.Context, c c
 			}, deploymentTargetClaim)
 
 			if err != nil {
-				return nil, err
+				return nil, wrapGetError(err, "DeploymentTargetClaim", environment.Namespace, dtcName)
 			}
 
 			return deploymentTargetClaim, nil
@@ -322,7 +318,7 @@ func (l *loader) GetDeploymentTargetForDeploymentTargetClaim(ctx context.Context
 	}, deploymentTarget)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapGetError(err, "DeploymentTarget", dtc.Namespace, dtName)
 	}
 
 	return deploymentTarget, nil
@@ -375,23 +371,49 @@ func (l *loader) GetAllPipelineRunsForSnapshotAndScenario(ctx context.Context, a
 // GetAllSnapshots returns all Snapshots in the Application's namespace nil if it's not found.
 // In the case the List operation fails, an error will be returned.
 func (l *loader) GetAllSnapshots(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]applicationapiv1alpha1.Snapshot, error) {
-	snapshots := &applicationapiv1alpha1.SnapshotList{}
+	return l.GetFilteredSnapshots(ctx, c, application, nil)
+}
+
+// GetFilteredSnapshots returns the Snapshots of the Application kept by the filter, a nil filter keeps all of them.
+// The filter isn't called for the remaining Snapshots once it asks to stop. When the context carries an API reader,
+// the Snapshots are listed from the API server in pages of SnapshotListPageSize and no page is listed once the
+// filter asks to stop. In the case the List operation fails, an error will be returned.
+func (l *loader) GetFilteredSnapshots(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, filter SnapshotFilter) (*[]applicationapiv1alpha1.Snapshot, error) {
+	if reader, ok := ctx.Value(apiReaderContextKey{}).(client.Reader); ok && SnapshotListPageSize > 0 {
+		return getPagedSnapshots(ctx, reader, application, filter)
+	}
+
+	snapshotList := &applicationapiv1alpha1.SnapshotList{}
 	opts := []client.ListOption{
 		client.InNamespace(application.Namespace),
 		client.MatchingFields{"spec.application": application.Name},
 	}
 
-	err := c.List(ctx, snapshots, opts...)
+	err := c.List(ctx, snapshotList, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if filter == nil {
+		return &snapshotList.Items, nil
+	}
 
-	return &snapshots.Items, nil
+	snapshots := []applicationapiv1alpha1.Snapshot{}
+	for i := range snapshotList.Items {
+		keep, stop := filter(&snapshotList.Items[i])
+		if keep {
+			snapshots = append(snapshots, snapshotList.Items[i])
+		}
+		if stop {
+			break
+		}
+	}
+
+	return &snapshots, nil
 }
 
-// GetAutoReleasePlansForApplication returns the ReleasePlans used by the application being processed. If matching
-// ReleasePlans are not found, an error will be returned. A ReleasePlan will only be returned if it has the
-// release.appstudio.openshift.io/auto-release label set to true or if it is missing the label entirely.
+// GetAutoReleasePlansForApplication returns the ReleasePlans of the application being processed, sorted by name.
+// A ReleasePlan will only be returned if it has the release.appstudio.openshift.io/auto-release label set to true
+// or if it is missing the label entirely. In the case the List operation fails, an error will be returned.
 func (l *loader) GetAutoReleasePlansForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]releasev1alpha1.ReleasePlan, error) {
 	releasePlans := &releasev1alpha1.ReleasePlanList{}
 	labelRequirement, err := labels.NewRequirement("release.appstudio.openshift.io/auto-release", selection.NotIn, []string{"false"})
@@ -408,16 +430,22 @@ func (l *loader) GetAutoReleasePlansForApplication(ctx context.Context, c client
 
 	err = c.List(ctx, releasePlans, opts)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list the auto-release ReleasePlans of application %s: %w", application.Name, err)
 	}
 
+	// the Releases are created in a stable order regardless of the order the ReleasePlans are listed in
+	slices.SortFunc(releasePlans.Items, func(a, b releasev1alpha1.ReleasePlan) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
 	return &releasePlans.Items, nil
 }
 
 // GetScenario returns integration test scenario requested by name and namespace
 func (l *loader) GetScenario(ctx context.Context, c client.Client, name, namespace string) (*v1beta2.IntegrationTestScenario, error) {
 	scenario := &v1beta2.IntegrationTestScenario{}
-	return scenario, toolkit.GetObject(name, namespace, c, ctx, scenario)
+	err := toolkit.GetObject(name, namespace, c, ctx, scenario)
+	return scenario, wrapGetError(err, "IntegrationTestScenario", namespace, name)
 }
 
 // GetAllEnvironmentsForScenario returns all Environments for the associated integrationTestScenario.
@@ -478,11 +506,48 @@ func (l *loader) GetAllTaskRunsWithMatchingPipelineRunLabel(ctx context.Context,
 // GetPipelineRun returns Tekton pipelineRun requested by name and namespace
 func (l *loader) GetPipelineRun(ctx context.Context, c client.Client, name, namespace string) (*tektonv1.PipelineRun, error) {
 	pipelineRun := &tektonv1.PipelineRun{}
-	return pipelineRun, toolkit.GetObject(name, namespace, c, ctx, pipelineRun)
+	err := toolkit.GetObject(name, namespace, c, ctx, pipelineRun)
+	return pipelineRun, wrapGetError(err, "PipelineRun", namespace, name)
 }
 
 // GetComponent returns application component requested by name and namespace
 func (l *loader) GetComponent(ctx context.Context, c client.Client, name, namespace string) (*applicationapiv1alpha1.Component, error) {
 	component := &applicationapiv1alpha1.Component{}
-	return component, toolkit.GetObject(name, namespace, c, ctx, component)
+	err := getMemoizedObject(ctx, c, name, namespace, component)
+	return component, wrapGetError(err, "Component", namespace, name)
+}
+
+// GetAllPullRequestSnapshotsForComponent returns all Snapshots created for the pull request events of the given Component.
+// Override Snapshots are excluded even if they carry pull request labels copied from other Snapshots.
+// In the case the List operation fails, an error will be returned.
+func (l *loader) GetAllPullRequestSnapshotsForComponent(ctx context.Context, c client.Client, namespace, componentName string) (*[]applicationapiv1alpha1.Snapshot, error) {
+	eventTypeRequirement, err := labels.NewRequirement(gitops.PipelineAsCodeEventTypeLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	pullRequestRequirement, err := labels.NewRequirement(gitops.PipelineAsCodeEventTypeLabel, selection.NotIn,
+		[]string{gitops.PipelineAsCodePushType, gitops.PipelineAsCodeGLPushType})
+	if err != nil {
+		return nil, err
+	}
+	componentRequirement, err := labels.NewRequirement(gitops.SnapshotComponentLabel, selection.Equals, []string{componentName})
+	if err != nil {
+		return nil, err
+	}
+	nonOverrideRequirement, err := labels.NewRequirement(gitops.SnapshotTypeLabel, selection.NotIn, []string{gitops.SnapshotOverrideType})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := &applicationapiv1alpha1.SnapshotList{}
+	opts := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labels.NewSelector().Add(*componentRequirement, *eventTypeRequirement, *pullRequestRequirement, *nonOverrideRequirement),
+	}
+
+	err = c.List(ctx, snapshots, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshots.Items, nil
 }
@@ -18,12 +18,14 @@ package loader
 
 import (
 	"context"
+	"sync"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
 	toolkit "github.com/konflux-ci/operator-toolkit/loader"
 	releasev1alpha1 "github.com/konflux-ci/release-service/api/v1alpha1"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -55,6 +57,8 @@ const (
 	AllTaskRunsWithMatchingPipelineRunLabelContextKey
 	GetPipelineRunContextKey
 	GetComponentContextKey
+	AllPullRequestSnapshotsForComponentContextKey
+	IntegrationTestScenariosMatchingContextKey
 )
 
 func NewMockLoader() ObjectLoader {
@@ -63,12 +67,67 @@ func NewMockLoader() ObjectLoader {
 	}
 }
 
+// MockResult is one of the results returned in order by a MockSequence
+type MockResult struct {
+	Resource any
+	Err      error
+}
+
+// MockSequence is used as the Resource of a toolkit.MockData to make the mocked loader method return a different
+// result on each call, e.g. to fail once and then succeed. The results are consumed in order, one per call, and the
+// last result keeps being returned once all of them were consumed. The Err of the MockData is ignored.
+type MockSequence struct {
+	mutex   sync.Mutex
+	results []MockResult
+	calls   int
+}
+
+// NewMockSequence returns a MockSequence returning the given results in order
+func NewMockSequence(results ...MockResult) *MockSequence {
+	return &MockSequence{results: results}
+}
+
+// Calls returns the number of calls of the mocked loader method served by the sequence
+func (s *MockSequence) Calls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls
+}
+
+// next returns the result of the current call and moves the sequence to the following one
+func (s *MockSequence) next() MockResult {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.calls++
+	if len(s.results) == 0 {
+		return MockResult{}
+	}
+	return s.results[min(s.calls, len(s.results))-1]
+}
+
+// getMockedResourceAndErrorFromContext returns the mocked data found in the context like
+// toolkit.GetMockedResourceAndErrorFromContext does, the next result is consumed when the data is a MockSequence
+func getMockedResourceAndErrorFromContext[T any](ctx context.Context, contextKey toolkit.ContextKey, resourceType T) (T, error) {
+	data, _ := ctx.Value(contextKey).(toolkit.MockData)
+	sequence, ok := data.Resource.(*MockSequence)
+	if !ok {
+		return toolkit.GetMockedResourceAndErrorFromContext(ctx, contextKey, resourceType)
+	}
+
+	var resource T
+	result := sequence.next()
+	if result.Resource != nil {
+		resource = result.Resource.(T)
+	}
+	return resource, result.Err
+}
+
 // GetAllEnvironments returns the resource and error passed as values of the context.
 func (l *mockLoader) GetAllEnvironments(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]applicationapiv1alpha1.Environment, error) {
 	if ctx.Value(EnvironmentContextKey) == nil {
 		return l.loader.GetAllEnvironments(ctx, c, application)
 	}
-	environment, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, EnvironmentContextKey, &applicationapiv1alpha1.Environment{})
+	environment, err := getMockedResourceAndErrorFromContext(ctx, EnvironmentContextKey, &applicationapiv1alpha1.Environment{})
 	return &[]applicationapiv1alpha1.Environment{*environment}, err
 }
 
@@ -77,7 +136,7 @@ func (l *mockLoader) GetReleasesWithSnapshot(ctx context.Context, c client.Clien
 	if ctx.Value(ReleaseContextKey) == nil {
 		return l.loader.GetReleasesWithSnapshot(ctx, c, snapshot)
 	}
-	release, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, ReleaseContextKey, &releasev1alpha1.Release{})
+	release, err := getMockedResourceAndErrorFromContext(ctx, ReleaseContextKey, &releasev1alpha1.Release{})
 	return &[]releasev1alpha1.Release{*release}, err
 }
 
@@ -86,7 +145,7 @@ func (l *mockLoader) GetAllApplicationComponents(ctx context.Context, c client.C
 	if ctx.Value(ApplicationComponentsContextKey) == nil {
 		return l.loader.GetAllApplicationComponents(ctx, c, application)
 	}
-	components, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, ApplicationComponentsContextKey, []applicationapiv1alpha1.Component{})
+	components, err := getMockedResourceAndErrorFromContext(ctx, ApplicationComponentsContextKey, []applicationapiv1alpha1.Component{})
 	return &components, err
 }
 
@@ -95,7 +154,7 @@ func (l *mockLoader) GetApplicationFromSnapshot(ctx context.Context, c client.Cl
 	if ctx.Value(ApplicationContextKey) == nil {
 		return l.loader.GetApplicationFromSnapshot(ctx, c, snapshot)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
+	return getMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
 }
 
 // GetComponentFromSnapshot returns the resource and error passed as values of the context.
@@ -103,7 +162,7 @@ func (l *mockLoader) GetComponentFromSnapshot(ctx context.Context, c client.Clie
 	if ctx.Value(ComponentContextKey) == nil {
 		return l.loader.GetComponentFromSnapshot(ctx, c, snapshot)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, ComponentContextKey, &applicationapiv1alpha1.Component{})
+	return getMockedResourceAndErrorFromContext(ctx, ComponentContextKey, &applicationapiv1alpha1.Component{})
 }
 
 // GetComponentFromPipelineRun returns the resource and error passed as values of the context.
@@ -111,7 +170,7 @@ func (l *mockLoader) GetComponentFromPipelineRun(ctx context.Context, c client.C
 	if ctx.Value(ComponentContextKey) == nil {
 		return l.loader.GetComponentFromPipelineRun(ctx, c, pipelineRun)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, ComponentContextKey, &applicationapiv1alpha1.Component{})
+	return getMockedResourceAndErrorFromContext(ctx, ComponentContextKey, &applicationapiv1alpha1.Component{})
 }
 
 // GetApplicationFromPipelineRun returns the resource and error passed as values of the context.
@@ -119,7 +178,7 @@ func (l *mockLoader) GetApplicationFromPipelineRun(ctx context.Context, c client
 	if ctx.Value(ApplicationContextKey) == nil {
 		return l.loader.GetApplicationFromPipelineRun(ctx, c, pipelineRun)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
+	return getMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
 }
 
 // GetApplicationFromComponent returns the resource and error passed as values of the context.
@@ -127,7 +186,7 @@ func (l *mockLoader) GetApplicationFromComponent(ctx context.Context, c client.C
 	if ctx.Value(ApplicationContextKey) == nil {
 		return l.loader.GetApplicationFromComponent(ctx, c, component)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
+	return getMockedResourceAndErrorFromContext(ctx, ApplicationContextKey, &applicationapiv1alpha1.Application{})
 }
 
 // GetEnvironmentFromIntegrationPipelineRun returns the resource and error passed as values of the context.
@@ -135,7 +194,7 @@ func (l *mockLoader) GetEnvironmentFromIntegrationPipelineRun(ctx context.Contex
 	if ctx.Value(EnvironmentContextKey) == nil {
 		return l.loader.GetEnvironmentFromIntegrationPipelineRun(ctx, c, pipelineRun)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, EnvironmentContextKey, &applicationapiv1alpha1.Environment{})
+	return getMockedResourceAndErrorFromContext(ctx, EnvironmentContextKey, &applicationapiv1alpha1.Environment{})
 }
 
 // GetSnapshotFromPipelineRun returns the resource and error passed as values of the context.
@@ -143,7 +202,7 @@ func (l *mockLoader) GetSnapshotFromPipelineRun(ctx context.Context, c client.Cl
 	if ctx.Value(SnapshotContextKey) == nil {
 		return l.loader.GetSnapshotFromPipelineRun(ctx, c, pipelineRun)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, SnapshotContextKey, &applicationapiv1alpha1.Snapshot{})
+	return getMockedResourceAndErrorFromContext(ctx, SnapshotContextKey, &applicationapiv1alpha1.Snapshot{})
 }
 
 // GetAllIntegrationTestScenariosForApplication returns the resource and error passed as values of the context.
@@ -151,7 +210,7 @@ func (l *mockLoader) GetAllIntegrationTestScenariosForApplication(ctx context.Co
 	if ctx.Value(AllIntegrationTestScenariosContextKey) == nil {
 		return l.loader.GetAllIntegrationTestScenariosForApplication(ctx, c, application)
 	}
-	integrationTestScenarios, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AllIntegrationTestScenariosContextKey, []v1beta2.IntegrationTestScenario{})
+	integrationTestScenarios, err := getMockedResourceAndErrorFromContext(ctx, AllIntegrationTestScenariosContextKey, []v1beta2.IntegrationTestScenario{})
 	return &integrationTestScenarios, err
 }
 
@@ -160,16 +219,35 @@ func (l *mockLoader) GetRequiredIntegrationTestScenariosForApplication(ctx conte
 	if ctx.Value(RequiredIntegrationTestScenariosContextKey) == nil {
 		return l.loader.GetRequiredIntegrationTestScenariosForApplication(ctx, c, application)
 	}
-	integrationTestScenarios, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, RequiredIntegrationTestScenariosContextKey, []v1beta2.IntegrationTestScenario{})
+	integrationTestScenarios, err := getMockedResourceAndErrorFromContext(ctx, RequiredIntegrationTestScenariosContextKey, []v1beta2.IntegrationTestScenario{})
 	return &integrationTestScenarios, err
 }
 
+// GetIntegrationTestScenariosForApplicationMatching returns the resource and error passed as values of the context,
+// keeping only the scenarios whose labels match the selector, like the List call of the loader does.
+func (l *mockLoader) GetIntegrationTestScenariosForApplicationMatching(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, selector labels.Selector) (*[]v1beta2.IntegrationTestScenario, error) {
+	if ctx.Value(IntegrationTestScenariosMatchingContextKey) == nil {
+		return l.loader.GetIntegrationTestScenariosForApplicationMatching(ctx, c, application, selector)
+	}
+	integrationTestScenarios, err := getMockedResourceAndErrorFromContext(ctx, IntegrationTestScenariosMatchingContextKey, []v1beta2.IntegrationTestScenario{})
+	if selector == nil {
+		return &integrationTestScenarios, err
+	}
+	matchingScenarios := []v1beta2.IntegrationTestScenario{}
+	for _, scenario := range integrationTestScenarios {
+		if selector.Matches(labels.Set(scenario.GetLabels())) {
+			matchingScenarios = append(matchingScenarios, scenario)
+		}
+	}
+	return &matchingScenarios, err
+}
+
 // GetDeploymentTargetClaimForEnvironment returns the resource and error passed as values of the context.
 func (l *mockLoader) GetDeploymentTargetClaimForEnvironment(ctx context.Context, c client.Client, environment *applicationapiv1alpha1.Environment) (*applicationapiv1alpha1.DeploymentTargetClaim, error) {
 	if ctx.Value(DeploymentTargetClaimContextKey) == nil {
 		return l.loader.GetDeploymentTargetClaimForEnvironment(ctx, c, environment)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, DeploymentTargetClaimContextKey, &applicationapiv1alpha1.DeploymentTargetClaim{})
+	return getMockedResourceAndErrorFromContext(ctx, DeploymentTargetClaimContextKey, &applicationapiv1alpha1.DeploymentTargetClaim{})
 }
 
 // GetDeploymentTargetForDeploymentTargetClaim returns the resource and error passed as values of the context.
@@ -177,7 +255,7 @@ func (l *mockLoader) GetDeploymentTargetForDeploymentTargetClaim(ctx context.Con
 	if ctx.Value(DeploymentTargetContextKey) == nil {
 		return l.loader.GetDeploymentTargetForDeploymentTargetClaim(ctx, c, dtc)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, DeploymentTargetContextKey, &applicationapiv1alpha1.DeploymentTarget{})
+	return getMockedResourceAndErrorFromContext(ctx, DeploymentTargetContextKey, &applicationapiv1alpha1.DeploymentTarget{})
 }
 
 // FindExistingSnapshotEnvironmentBinding returns the resource and error passed as values of the context.
@@ -185,7 +263,7 @@ func (l *mockLoader) FindExistingSnapshotEnvironmentBinding(ctx context.Context,
 	if ctx.Value(SnapshotEnvironmentBindingContextKey) == nil {
 		return l.loader.FindExistingSnapshotEnvironmentBinding(ctx, c, application, environment)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, SnapshotEnvironmentBindingContextKey, &applicationapiv1alpha1.SnapshotEnvironmentBinding{})
+	return getMockedResourceAndErrorFromContext(ctx, SnapshotEnvironmentBindingContextKey, &applicationapiv1alpha1.SnapshotEnvironmentBinding{})
 }
 
 // GetAllPipelineRunsForSnapshotAndScenario returns the resource and error passed as values of the context.
@@ -193,7 +271,7 @@ func (l *mockLoader) GetAllPipelineRunsForSnapshotAndScenario(ctx context.Contex
 	if ctx.Value(PipelineRunsContextKey) == nil {
 		return l.loader.GetAllPipelineRunsForSnapshotAndScenario(ctx, c, snapshot, integrationTestScenario)
 	}
-	pipelineRuns, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, PipelineRunsContextKey, []tektonv1.PipelineRun{})
+	pipelineRuns, err := getMockedResourceAndErrorFromContext(ctx, PipelineRunsContextKey, []tektonv1.PipelineRun{})
 	return &pipelineRuns, err
 }
 
@@ -202,16 +280,39 @@ func (l *mockLoader) GetAllSnapshots(ctx context.Context, c client.Client, appli
 	if ctx.Value(AllSnapshotsContextKey) == nil {
 		return l.loader.GetAllSnapshots(ctx, c, application)
 	}
-	snapshots, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AllSnapshotsContextKey, []applicationapiv1alpha1.Snapshot{})
+	snapshots, err := getMockedResourceAndErrorFromContext(ctx, AllSnapshotsContextKey, []applicationapiv1alpha1.Snapshot{})
 	return &snapshots, err
 }
 
+// GetFilteredSnapshots returns the resource and error passed as values of the context for GetAllSnapshots,
+// keeping only the Snapshots kept by the filter until it stops the listing.
+func (l *mockLoader) GetFilteredSnapshots(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application, filter SnapshotFilter) (*[]applicationapiv1alpha1.Snapshot, error) {
+	if ctx.Value(AllSnapshotsContextKey) == nil {
+		return l.loader.GetFilteredSnapshots(ctx, c, application, filter)
+	}
+	snapshots, err := getMockedResourceAndErrorFromContext(ctx, AllSnapshotsContextKey, []applicationapiv1alpha1.Snapshot{})
+	if filter == nil {
+		return &snapshots, err
+	}
+	filteredSnapshots := []applicationapiv1alpha1.Snapshot{}
+	for i := range snapshots {
+		keep, stop := filter(&snapshots[i])
+		if keep {
+			filteredSnapshots = append(filteredSnapshots, snapshots[i])
+		}
+		if stop {
+			break
+		}
+	}
+	return &filteredSnapshots, err
+}
+
 // GetAutoReleasePlansForApplication returns the resource and error passed as values of the context.
 func (l *mockLoader) GetAutoReleasePlansForApplication(ctx context.Context, c client.Client, application *applicationapiv1alpha1.Application) (*[]releasev1alpha1.ReleasePlan, error) {
 	if ctx.Value(AutoReleasePlansContextKey) == nil {
 		return l.loader.GetAutoReleasePlansForApplication(ctx, c, application)
 	}
-	autoReleasePlans, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AutoReleasePlansContextKey, []releasev1alpha1.ReleasePlan{})
+	autoReleasePlans, err := getMockedResourceAndErrorFromContext(ctx, AutoReleasePlansContextKey, []releasev1alpha1.ReleasePlan{})
 	return &autoReleasePlans, err
 }
 
@@ -220,14 +321,14 @@ func (l *mockLoader) GetScenario(ctx context.Context, c client.Client, name, nam
 	if ctx.Value(GetScenarioContextKey) == nil {
 		return l.loader.GetScenario(ctx, c, name, namespace)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, GetScenarioContextKey, &v1beta2.IntegrationTestScenario{})
+	return getMockedResourceAndErrorFromContext(ctx, GetScenarioContextKey, &v1beta2.IntegrationTestScenario{})
 }
 
 func (l *mockLoader) GetAllEnvironmentsForScenario(ctx context.Context, c client.Client, integrationTestScenario *v1beta2.IntegrationTestScenario) (*[]applicationapiv1alpha1.Environment, error) {
 	if ctx.Value(AllEnvironmentsForScenarioContextKey) == nil {
 		return l.loader.GetAllEnvironmentsForScenario(ctx, c, integrationTestScenario)
 	}
-	environments, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AllEnvironmentsForScenarioContextKey, []applicationapiv1alpha1.Environment{})
+	environments, err := getMockedResourceAndErrorFromContext(ctx, AllEnvironmentsForScenarioContextKey, []applicationapiv1alpha1.Environment{})
 	return &environments, err
 }
 
@@ -235,7 +336,7 @@ func (l *mockLoader) GetAllSnapshotsForBuildPipelineRun(ctx context.Context, c c
 	if ctx.Value(AllSnapshotsForBuildPipelineRunContextKey) == nil {
 		return l.loader.GetAllSnapshotsForBuildPipelineRun(ctx, c, pipelineRun)
 	}
-	snapshots, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AllSnapshotsForBuildPipelineRunContextKey, []applicationapiv1alpha1.Snapshot{})
+	snapshots, err := getMockedResourceAndErrorFromContext(ctx, AllSnapshotsForBuildPipelineRunContextKey, []applicationapiv1alpha1.Snapshot{})
 	return &snapshots, err
 }
 
@@ -243,7 +344,7 @@ func (l *mockLoader) GetAllTaskRunsWithMatchingPipelineRunLabel(ctx context.Cont
 	if ctx.Value(AllTaskRunsWithMatchingPipelineRunLabelContextKey) == nil {
 		return l.loader.GetAllTaskRunsWithMatchingPipelineRunLabel(ctx, c, pipelineRun)
 	}
-	taskRuns, err := toolkit.GetMockedResourceAndErrorFromContext(ctx, AllTaskRunsWithMatchingPipelineRunLabelContextKey, []tektonv1.TaskRun{})
+	taskRuns, err := getMockedResourceAndErrorFromContext(ctx, AllTaskRunsWithMatchingPipelineRunLabelContextKey, []tektonv1.TaskRun{})
 	return &taskRuns, err
 }
 
@@ -252,7 +353,7 @@ func (l *mockLoader) GetPipelineRun(ctx context.Context, c client.Client, name,
 	if ctx.Value(GetPipelineRunContextKey) == nil {
 		return l.loader.GetPipelineRun(ctx, c, name, namespace)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, GetPipelineRunContextKey, &tektonv1.PipelineRun{})
+	return getMockedResourceAndErrorFromContext(ctx, GetPipelineRunContextKey, &tektonv1.PipelineRun{})
 }
 
 // GetComponent returns the resource and error passed as values of the context.
@@ -260,5 +361,14 @@ func (l *mockLoader) GetComponent(ctx context.Context, c client.Client, name, na
 	if ctx.Value(GetComponentContextKey) == nil {
 		return l.loader.GetComponent(ctx, c, name, namespace)
 	}
-	return toolkit.GetMockedResourceAndErrorFromContext(ctx, GetComponentContextKey, &applicationapiv1alpha1.Component{})
+	return getMockedResourceAndErrorFromContext(ctx, GetComponentContextKey, &applicationapiv1alpha1.Component{})
+}
+
+// GetAllPullRequestSnapshotsForComponent returns the resource and error passed as values of the context.
+func (l *mockLoader) GetAllPullRequestSnapshotsForComponent(ctx context.Context, c client.Client, namespace, componentName string) (*[]applicationapiv1alpha1.Snapshot, error) {
+	if ctx.Value(AllPullRequestSnapshotsForComponentContextKey) == nil {
+		return l.loader.GetAllPullRequestSnapshotsForComponent(ctx, c, namespace, componentName)
+	}
+	snapshots, err := getMockedResourceAndErrorFromContext(ctx, AllPullRequestSnapshotsForComponentContextKey, []applicationapiv1alpha1.Snapshot{})
+	return &snapshots, err
 }
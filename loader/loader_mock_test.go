@@ -17,6 +17,8 @@ limitations under the License.
 package loader
 
 import (
+	"fmt"
+
 	toolkit "github.com/konflux-ci/operator-toolkit/loader"
 
 	"github.com/konflux-ci/integration-service/api/v1beta2"
@@ -25,6 +27,9 @@ import (
 	. "github.com/onsi/gomega"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var _ = Describe("Release Adapter", Ordered, func() {
@@ -231,6 +236,31 @@ var _ = Describe("Release Adapter", Ordered, func() {
 		})
 	})
 
+	Context("When calling GetIntegrationTestScenariosForApplicationMatching", func() {
+		It("returns the integrationTestScenarios from the context matching the selector and error", func() {
+			scenarios := []v1beta2.IntegrationTestScenario{
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+			}
+			mockContext := toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: IntegrationTestScenariosMatchingContextKey,
+					Resource:   scenarios,
+				},
+			})
+			resource, err := loader.GetIntegrationTestScenariosForApplicationMatching(mockContext, nil, nil,
+				labels.SelectorFromSet(labels.Set{"team": "a"}))
+			Expect(err).To(BeNil())
+			Expect(*resource).To(HaveLen(1))
+			Expect((*resource)[0].Name).To(Equal("team-a"))
+
+			resource, err = loader.GetIntegrationTestScenariosForApplicationMatching(mockContext, nil, nil, nil)
+			Expect(err).To(BeNil())
+			Expect(resource).To(Equal(&scenarios))
+		})
+	})
+
 	Context("When calling GetDeploymentTargetClaimForEnvironment", func() {
 		It("returns deploymentTargetClaim and error from the context", func() {
 			dtc := &applicationapiv1alpha1.DeploymentTargetClaim{}
@@ -306,6 +336,30 @@ var _ = Describe("Release Adapter", Ordered, func() {
 		})
 	})
 
+	Context("When calling GetFilteredSnapshots", func() {
+		It("returns the snapshots kept by the filter and error from the context", func() {
+			snapshots := []applicationapiv1alpha1.Snapshot{
+				{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-b"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-c"}},
+			}
+			mockContext := toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: AllSnapshotsContextKey,
+					Resource:   snapshots,
+				},
+			})
+			resource, err := loader.GetFilteredSnapshots(mockContext, nil, nil,
+				func(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+					matches := snapshot.Name == "snapshot-b"
+					return matches, matches
+				})
+			Expect(*resource).To(HaveLen(1))
+			Expect((*resource)[0].Name).To(Equal("snapshot-b"))
+			Expect(err).To(BeNil())
+		})
+	})
+
 	Context("When calling GetAutoReleasePlansForApplication", func() {
 		It("returns snapshots and error from the context", func() {
 			releasePlans := []releasev1alpha1.ReleasePlan{}
@@ -380,4 +434,72 @@ var _ = Describe("Release Adapter", Ordered, func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("When calling GetAllPullRequestSnapshotsForComponent", func() {
+		It("returns resource and error from the context", func() {
+			snapshots := []applicationapiv1alpha1.Snapshot{}
+			mockContext := toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: AllPullRequestSnapshotsForComponentContextKey,
+					Resource:   snapshots,
+				},
+			})
+			resource, err := loader.GetAllPullRequestSnapshotsForComponent(mockContext, nil, "", "")
+			Expect(resource).To(Equal(&snapshots))
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When mocking a method with a sequence of results", func() {
+		It("returns the results in order and keeps returning the last one", func() {
+			firstComponent := &applicationapiv1alpha1.Component{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+			lastComponent := &applicationapiv1alpha1.Component{ObjectMeta: metav1.ObjectMeta{Name: "last"}}
+			conflictErr := errors.NewConflict(applicationapiv1alpha1.GroupVersion.WithResource("components").GroupResource(),
+				"first", fmt.Errorf("the object has been modified"))
+			sequence := NewMockSequence(
+				MockResult{Err: conflictErr},
+				MockResult{Resource: firstComponent},
+				MockResult{Resource: lastComponent},
+			)
+			mockContext := toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: GetComponentContextKey,
+					Resource:   sequence,
+				},
+			})
+
+			_, err := loader.GetComponent(mockContext, nil, "", "")
+			Expect(errors.IsConflict(err)).To(BeTrue())
+			resource, err := loader.GetComponent(mockContext, nil, "", "")
+			Expect(resource).To(Equal(firstComponent))
+			Expect(err).To(BeNil())
+			for i := 0; i < 2; i++ {
+				resource, err = loader.GetComponent(mockContext, nil, "", "")
+				Expect(resource).To(Equal(lastComponent))
+				Expect(err).To(BeNil())
+			}
+			Expect(sequence.Calls()).To(Equal(4))
+		})
+
+		It("returns the sequenced lists of resources", func() {
+			snapshots := []applicationapiv1alpha1.Snapshot{{ObjectMeta: metav1.ObjectMeta{Name: "snapshot"}}}
+			sequence := NewMockSequence(
+				MockResult{Err: fmt.Errorf("connection refused")},
+				MockResult{Resource: snapshots},
+			)
+			mockContext := toolkit.GetMockedContext(ctx, []toolkit.MockData{
+				{
+					ContextKey: AllSnapshotsForBuildPipelineRunContextKey,
+					Resource:   sequence,
+				},
+			})
+
+			_, err := loader.GetAllSnapshotsForBuildPipelineRun(mockContext, nil, nil)
+			Expect(err).To(HaveOccurred())
+			resource, err := loader.GetAllSnapshotsForBuildPipelineRun(mockContext, nil, nil)
+			Expect(resource).To(Equal(&snapshots))
+			Expect(err).To(BeNil())
+			Expect(sequence.Calls()).To(Equal(2))
+		})
+	})
 })
@@ -40,6 +40,7 @@ import (
 
 	integrationbeta2 "github.com/konflux-ci/integration-service/api/v1beta2"
 	releasev1alpha1 "github.com/konflux-ci/release-service/api/v1alpha1"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
@@ -97,6 +98,7 @@ var _ = BeforeSuite(func() {
 	Expect(tektonv1.AddToScheme(clientsetscheme.Scheme)).To(Succeed())
 	Expect(releasev1alpha1.AddToScheme(clientsetscheme.Scheme)).To(Succeed())
 	Expect(integrationbeta2.AddToScheme(clientsetscheme.Scheme)).To(Succeed())
+	Expect(pacv1alpha1.AddToScheme(clientsetscheme.Scheme)).To(Succeed())
 
 	k8sManager, _ := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: clientsetscheme.Scheme,
@@ -116,6 +118,7 @@ var _ = BeforeSuite(func() {
 		Expect(cache.SetupSnapshotCache(k8sManager)).To(Succeed())
 		Expect(cache.SetupBindingApplicationCache(k8sManager)).To(Succeed())
 		Expect(cache.SetupBindingEnvironmentCache(k8sManager)).To(Succeed())
+		Expect(cache.SetupPacRepositoryCache(k8sManager)).To(Succeed())
 		Expect(k8sManager.Start(ctx)).To(Succeed())
 	}()
 })
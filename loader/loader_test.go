@@ -17,6 +17,11 @@ limitations under the License.
 package loader
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,7 +32,12 @@ import (
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/konflux-ci/integration-service/gitops"
 	releasev1alpha1 "github.com/konflux-ci/release-service/api/v1alpha1"
@@ -559,6 +569,53 @@ var _ = Describe("Loader", Ordered, func() {
 		Expect((*snapshots)[0].Name).To(Equal(hasSnapshot.Name))
 	})
 
+	It("can fetch the pull request Snapshots of a Component", func() {
+		newEventSnapshot := func(name, componentName, eventType string) *applicationapiv1alpha1.Snapshot {
+			snapshot := hasSnapshot.DeepCopy()
+			snapshot.ObjectMeta = metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					gitops.SnapshotTypeLabel:            "component",
+					gitops.SnapshotComponentLabel:       componentName,
+					gitops.PipelineAsCodeEventTypeLabel: eventType,
+				},
+			}
+			Expect(k8sClient.Create(ctx, snapshot)).Should(Succeed())
+			return snapshot
+		}
+		pullRequestSnapshot := newEventSnapshot("snapshot-pr-sample", "component-sample", gitops.PipelineAsCodePullRequestType)
+		pushSnapshot := newEventSnapshot("snapshot-push-sample", "component-sample", gitops.PipelineAsCodePushType)
+		otherComponentSnapshot := newEventSnapshot("snapshot-pr-other", "component-other", gitops.PipelineAsCodePullRequestType)
+		// the override snapshot carries the pull request labels copied from the metadata of another snapshot
+		overrideSnapshot := hasSnapshot.DeepCopy()
+		overrideSnapshot.ObjectMeta = metav1.ObjectMeta{
+			Name:      "snapshot-override-stale-pr",
+			Namespace: "default",
+			Labels: map[string]string{
+				gitops.SnapshotTypeLabel:            gitops.SnapshotOverrideType,
+				gitops.SnapshotComponentLabel:       "component-sample",
+				gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePullRequestType,
+			},
+		}
+		Expect(k8sClient.Create(ctx, overrideSnapshot)).Should(Succeed())
+		defer func() {
+			for _, snapshot := range []*applicationapiv1alpha1.Snapshot{pullRequestSnapshot, pushSnapshot, otherComponentSnapshot, overrideSnapshot} {
+				Expect(k8sClient.Delete(ctx, snapshot)).Should(Succeed())
+			}
+		}()
+
+		Eventually(func() []string {
+			snapshots, err := loader.GetAllPullRequestSnapshotsForComponent(ctx, k8sClient, "default", "component-sample")
+			Expect(err).ToNot(HaveOccurred())
+			names := []string{}
+			for _, snapshot := range *snapshots {
+				names = append(names, snapshot.Name)
+			}
+			return names
+		}).Should(Equal([]string{pullRequestSnapshot.Name}))
+	})
+
 	It("ensures we can get the Environment from a Pipeline Run", func() {
 		env, err := loader.GetEnvironmentFromIntegrationPipelineRun(ctx, k8sClient, buildPipelineRun)
 		Expect(err).To(BeNil())
@@ -590,6 +647,53 @@ var _ = Describe("Loader", Ordered, func() {
 		Expect((*integrationTestScenarios)[0].Name).To(Equal(integrationTestScenario.Name))
 	})
 
+	It("can fetch the integrationTestScenarios for application matching a label selector", func() {
+		teamScenario := integrationTestScenario.DeepCopy()
+		teamScenario.ObjectMeta = metav1.ObjectMeta{
+			Name:      "example-team-a",
+			Namespace: "default",
+			Labels: map[string]string{
+				"test.appstudio.openshift.io/optional": "true",
+				"team":                                 "a",
+			},
+		}
+		otherTeamScenario := integrationTestScenario.DeepCopy()
+		otherTeamScenario.ObjectMeta = metav1.ObjectMeta{
+			Name:      "example-team-b",
+			Namespace: "default",
+			Labels: map[string]string{
+				"team": "b",
+			},
+		}
+		Expect(k8sClient.Create(ctx, teamScenario)).Should(Succeed())
+		Expect(k8sClient.Create(ctx, otherTeamScenario)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, teamScenario)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, otherTeamScenario)).Should(Succeed())
+		}()
+
+		Eventually(func() int {
+			integrationTestScenarios, err := loader.GetIntegrationTestScenariosForApplicationMatching(ctx, k8sClient, hasApp, nil)
+			if err != nil {
+				return 0
+			}
+			return len(*integrationTestScenarios)
+		}, time.Second*10).Should(Equal(3))
+
+		integrationTestScenarios, err := loader.GetIntegrationTestScenariosForApplicationMatching(ctx, k8sClient, hasApp,
+			labels.SelectorFromSet(labels.Set{"team": "a"}))
+		Expect(err).To(BeNil())
+		Expect(*integrationTestScenarios).To(HaveLen(1))
+		Expect((*integrationTestScenarios)[0].Name).To(Equal(teamScenario.Name))
+
+		integrationTestScenarios, err = loader.GetRequiredIntegrationTestScenariosForApplication(ctx, k8sClient, hasApp)
+		Expect(err).To(BeNil())
+		Expect(*integrationTestScenarios).To(HaveLen(2))
+		for _, scenario := range *integrationTestScenarios {
+			Expect(scenario.Name).NotTo(Equal(teamScenario.Name))
+		}
+	})
+
 	It("can fetch DeploymentTargetClaim for environment", func() {
 		dtcls, err := loader.GetDeploymentTargetClaimForEnvironment(ctx, k8sClient, hasEnv)
 		Expect(err).To(BeNil())
@@ -725,6 +829,56 @@ var _ = Describe("Loader", Ordered, func() {
 		})
 	})
 
+	When("release plans of several applications are created", func() {
+
+		var (
+			releasePlans []*releasev1alpha1.ReleasePlan
+		)
+
+		BeforeEach(func() {
+			releasePlans = []*releasev1alpha1.ReleasePlan{}
+			for _, plan := range []struct{ name, application, autoRelease string }{
+				{"test-release-plan-zulu", hasApp.Name, "true"},
+				{"test-release-plan-alpha", hasApp.Name, ""},
+				{"test-release-plan-opted-out", hasApp.Name, "false"},
+				{"test-release-plan-other-application", "other-application", "true"},
+			} {
+				releasePlan := &releasev1alpha1.ReleasePlan{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      plan.name,
+						Namespace: hasApp.Namespace,
+					},
+					Spec: releasev1alpha1.ReleasePlanSpec{
+						Application: plan.application,
+						Target:      "default",
+					},
+				}
+				if plan.autoRelease != "" {
+					releasePlan.Labels = map[string]string{
+						"release.appstudio.openshift.io/auto-release": plan.autoRelease,
+					}
+				}
+				createReleasePlan(releasePlan)
+				releasePlans = append(releasePlans, releasePlan)
+			}
+		})
+
+		AfterEach(func() {
+			for _, releasePlan := range releasePlans {
+				deleteReleasePlan(releasePlan)
+			}
+		})
+
+		It("ensures only the auto-release plans of the application are returned, sorted by name", func() {
+			autoReleasePlans, err := loader.GetAutoReleasePlansForApplication(ctx, k8sClient, hasApp)
+			Expect(err).To(BeNil())
+			Expect(autoReleasePlans).ToNot(BeNil())
+			Expect(*autoReleasePlans).To(HaveLen(2))
+			Expect((*autoReleasePlans)[0].Name).To(Equal("test-release-plan-alpha"))
+			Expect((*autoReleasePlans)[1].Name).To(Equal("test-release-plan-zulu"))
+		})
+	})
+
 	When("release plan with auto-release label is set to false", func() {
 
 		const (
@@ -790,3 +944,154 @@ var _ = Describe("Loader", Ordered, func() {
 		})
 	})
 })
+
+var _ = Describe("Loader Snapshot filter", func() {
+	var (
+		loader      ObjectLoader
+		application *applicationapiv1alpha1.Application
+		fakeClient  client.Client
+		listCalls   int
+	)
+
+	BeforeEach(func() {
+		loader = NewLoader()
+		listCalls = 0
+		application = &applicationapiv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "filtered-application", Namespace: "default"},
+		}
+
+		snapshots := []client.Object{}
+		for i := 0; i < 10; i++ {
+			snapshots = append(snapshots, &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("filtered-snapshot-%02d", i), Namespace: "default"},
+				Spec:       applicationapiv1alpha1.SnapshotSpec{Application: application.Name},
+			})
+		}
+		snapshots = append(snapshots, &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-application-snapshot", Namespace: "default"},
+			Spec:       applicationapiv1alpha1.SnapshotSpec{Application: "other-application"},
+		})
+
+		scheme := runtime.NewScheme()
+		Expect(applicationapiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(snapshots...).
+			WithIndex(&applicationapiv1alpha1.Snapshot{}, "spec.application", func(obj client.Object) []string {
+				return []string{obj.(*applicationapiv1alpha1.Snapshot).Spec.Application}
+			}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					listCalls++
+					return c.List(ctx, list, opts...)
+				},
+			}).Build()
+	})
+
+	It("lists all the Snapshots of the Application with a single List call", func() {
+		snapshots, err := loader.GetAllSnapshots(context.Background(), fakeClient, application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*snapshots).To(HaveLen(10))
+		Expect(listCalls).To(Equal(1))
+	})
+
+	It("keeps only the Snapshots kept by the filter", func() {
+		snapshots, err := loader.GetFilteredSnapshots(context.Background(), fakeClient, application,
+			func(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+				return strings.HasSuffix(snapshot.Name, "5") || strings.HasSuffix(snapshot.Name, "7"), false
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*snapshots).To(HaveLen(2))
+		Expect(listCalls).To(Equal(1))
+	})
+
+	It("stops calling the filter once it asks for it", func() {
+		filtered := 0
+		snapshots, err := loader.GetFilteredSnapshots(context.Background(), fakeClient, application,
+			func(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+				filtered++
+				matches := snapshot.Name == "filtered-snapshot-05"
+				return matches, matches
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*snapshots).To(HaveLen(1))
+		Expect((*snapshots)[0].Name).To(Equal("filtered-snapshot-05"))
+		Expect(filtered).To(BeNumerically("<", 10))
+	})
+
+	Context("when the context carries an API reader", func() {
+		var (
+			pagedReader client.Reader
+			ctx         context.Context
+			pageSize    int64
+		)
+
+		BeforeEach(func() {
+			pageSize = SnapshotListPageSize
+			SnapshotListPageSize = 4
+
+			// the fake client ignores the limit and continue options, the pages are cut by the interceptor
+			pagedReader = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					listOpts := &client.ListOptions{}
+					listOpts.ApplyOptions(opts)
+					Expect(listOpts.FieldSelector).To(BeNil())
+					if err := c.List(ctx, list, client.InNamespace(listOpts.Namespace)); err != nil {
+						return err
+					}
+					snapshotList := list.(*applicationapiv1alpha1.SnapshotList)
+					slices.SortFunc(snapshotList.Items, func(a, b applicationapiv1alpha1.Snapshot) int {
+						return strings.Compare(a.Name, b.Name)
+					})
+					offset := 0
+					if listOpts.Continue != "" {
+						var err error
+						offset, err = strconv.Atoi(listOpts.Continue)
+						Expect(err).NotTo(HaveOccurred())
+					}
+					end := min(offset+int(listOpts.Limit), len(snapshotList.Items))
+					snapshotList.Continue = ""
+					if end < len(snapshotList.Items) {
+						snapshotList.Continue = strconv.Itoa(end)
+					}
+					snapshotList.Items = snapshotList.Items[offset:end]
+					return nil
+				},
+			})
+			ctx = WithAPIReader(context.Background(), pagedReader)
+		})
+
+		AfterEach(func() {
+			SnapshotListPageSize = pageSize
+		})
+
+		It("lists all the Snapshots of the Application page by page", func() {
+			snapshots, err := loader.GetAllSnapshots(ctx, fakeClient, application)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*snapshots).To(HaveLen(10))
+			for _, snapshot := range *snapshots {
+				Expect(snapshot.Spec.Application).To(Equal(application.Name))
+			}
+			// 11 Snapshots in the namespace in pages of 4
+			Expect(listCalls).To(Equal(3))
+		})
+
+		It("doesn't list the remaining pages once the filter asks to stop", func() {
+			snapshots, err := loader.GetFilteredSnapshots(ctx, fakeClient, application,
+				func(snapshot *applicationapiv1alpha1.Snapshot) (bool, bool) {
+					matches := snapshot.Name == "filtered-snapshot-05"
+					return matches, matches
+				})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*snapshots).To(HaveLen(1))
+			Expect((*snapshots)[0].Name).To(Equal("filtered-snapshot-05"))
+			Expect(listCalls).To(Equal(2))
+		})
+
+		It("lists the Snapshots from the client at once when the page size isn't positive", func() {
+			SnapshotListPageSize = 0
+			snapshots, err := loader.GetAllSnapshots(ctx, fakeClient, application)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*snapshots).To(HaveLen(10))
+			Expect(listCalls).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// memoizationContextKey is the key of the context value holding the objects memoized during one reconcile
+type memoizationContextKey struct{}
+
+// memoizationBypassContextKey is the key of the context value disabling the memoization for the calls needing fresh data
+type memoizationBypassContextKey struct{}
+
+// memoizationKey identifies a memoized object by its kind, namespace and name
+type memoizationKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// memoizedObjects holds the objects loaded during one reconcile, the operations of an adapter can run concurrently
+type memoizedObjects struct {
+	mutex   sync.Mutex
+	objects map[memoizationKey]client.Object
+}
+
+// WithMemoization returns a copy of the context in which the Applications and Components loaded by the loader are
+// memoized, so repeated loads of the same object return the result of the first one without reading it again.
+// The context must only live for the duration of one reconcile, the memoized objects are never refreshed.
+func WithMemoization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoizationContextKey{}, &memoizedObjects{objects: map[memoizationKey]client.Object{}})
+}
+
+// WithoutMemoization returns a copy of the context in which the loader reads the objects again instead of returning
+// the memoized ones, e.g. to refresh an object after updating it. The fresh objects replace the memoized ones.
+func WithoutMemoization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoizationBypassContextKey{}, true)
+}
+
+// getMemoizedObject loads the object with the given name and namespace into the given object. When the context
+// memoizes the loaded objects, a copy of the memoized object is returned if it was already loaded. Failed loads
+// aren't memoized.
+func getMemoizedObject(ctx context.Context, c client.Client, name, namespace string, object client.Object) error {
+	memoized, ok := ctx.Value(memoizationContextKey{}).(*memoizedObjects)
+	if !ok {
+		return c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, object)
+	}
+	gvk, err := c.GroupVersionKindFor(object)
+	if err != nil {
+		return c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, object)
+	}
+	key := memoizationKey{gvk: gvk, namespace: namespace, name: name}
+
+	bypass, _ := ctx.Value(memoizationBypassContextKey{}).(bool)
+	if !bypass {
+		memoized.mutex.Lock()
+		memoizedObject, found := memoized.objects[key]
+		memoized.mutex.Unlock()
+		if found {
+			reflect.ValueOf(object).Elem().Set(reflect.ValueOf(memoizedObject.DeepCopyObject()).Elem())
+			return nil
+		}
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, object); err != nil {
+		return err
+	}
+	memoized.mutex.Lock()
+	memoized.objects[key] = object.DeepCopyObject().(client.Object)
+	memoized.mutex.Unlock()
+	return nil
+}
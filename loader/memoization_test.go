@@ -0,0 +1,149 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Loader memoization", func() {
+	var (
+		loader       ObjectLoader
+		countingGets map[string]int
+		fakeClient   client.Client
+		application  *applicationapiv1alpha1.Application
+		component    *applicationapiv1alpha1.Component
+		snapshot     *applicationapiv1alpha1.Snapshot
+	)
+
+	BeforeEach(func() {
+		loader = NewLoader()
+		application = &applicationapiv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "memoized-application", Namespace: "default"},
+		}
+		component = &applicationapiv1alpha1.Component{
+			ObjectMeta: metav1.ObjectMeta{Name: "memoized-component", Namespace: "default"},
+			Spec:       applicationapiv1alpha1.ComponentSpec{Application: application.Name},
+		}
+		snapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "memoized-snapshot",
+				Namespace: "default",
+				Labels:    map[string]string{gitops.SnapshotComponentLabel: component.Name},
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{Application: application.Name},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(applicationapiv1alpha1.AddToScheme(scheme)).To(Succeed())
+		countingGets = map[string]int{}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(application, component).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					countingGets[key.Name]++
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+	})
+
+	It("reads the Application and Component once per memoizing context", func() {
+		ctx := WithMemoization(context.Background())
+
+		for i := 0; i < 3; i++ {
+			loadedApplication, err := loader.GetApplicationFromSnapshot(ctx, fakeClient, snapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loadedApplication.Name).To(Equal(application.Name))
+			loadedApplication, err = loader.GetApplicationFromComponent(ctx, fakeClient, component)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loadedApplication.Name).To(Equal(application.Name))
+
+			loadedComponent, err := loader.GetComponentFromSnapshot(ctx, fakeClient, snapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loadedComponent.Spec.Application).To(Equal(application.Name))
+			loadedComponent, err = loader.GetComponent(ctx, fakeClient, component.Name, component.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loadedComponent.Name).To(Equal(component.Name))
+		}
+
+		Expect(countingGets[application.Name]).To(Equal(1))
+		Expect(countingGets[component.Name]).To(Equal(1))
+	})
+
+	It("returns copies of the memoized objects", func() {
+		ctx := WithMemoization(context.Background())
+
+		loadedComponent, err := loader.GetComponent(ctx, fakeClient, component.Name, component.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		loadedComponent.Spec.Application = "modified"
+
+		loadedComponent, err = loader.GetComponent(ctx, fakeClient, component.Name, component.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadedComponent.Spec.Application).To(Equal(application.Name))
+	})
+
+	It("reads the objects again when the memoization is bypassed", func() {
+		ctx := WithMemoization(context.Background())
+
+		_, err := loader.GetComponent(ctx, fakeClient, component.Name, component.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		updatedComponent := component.DeepCopy()
+		Expect(fakeClient.Get(context.Background(), client.ObjectKeyFromObject(component), updatedComponent)).To(Succeed())
+		updatedComponent.Spec.ContainerImage = "quay.io/redhat-appstudio/updated-image"
+		Expect(fakeClient.Update(context.Background(), updatedComponent)).To(Succeed())
+
+		loadedComponent, err := loader.GetComponent(WithoutMemoization(ctx), fakeClient, component.Name, component.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadedComponent.Spec.ContainerImage).To(Equal("quay.io/redhat-appstudio/updated-image"))
+
+		// the fresh object replaces the memoized one
+		loadedComponent, err = loader.GetComponent(ctx, fakeClient, component.Name, component.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadedComponent.Spec.ContainerImage).To(Equal("quay.io/redhat-appstudio/updated-image"))
+		Expect(countingGets[component.Name]).To(Equal(3))
+	})
+
+	It("reads the objects every time without a memoizing context", func() {
+		for i := 0; i < 2; i++ {
+			_, err := loader.GetApplicationFromComponent(context.Background(), fakeClient, component)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(countingGets[application.Name]).To(Equal(2))
+	})
+
+	It("doesn't memoize the failed reads", func() {
+		ctx := WithMemoization(context.Background())
+
+		for i := 0; i < 2; i++ {
+			_, err := loader.GetComponent(ctx, fakeClient, "missing-component", component.Namespace)
+			Expect(err).To(HaveOccurred())
+		}
+
+		Expect(countingGets["missing-component"]).To(Equal(2))
+	})
+})
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SnapshotListPageSize is the number of Snapshots listed per page from the API server when the context carries
+// an API reader, the Snapshots are listed from the cache at once when it isn't positive
+var SnapshotListPageSize int64 = 500
+
+// apiReaderContextKey is the key of the context value holding the reader listing the Snapshots from the API server
+type apiReaderContextKey struct{}
+
+// WithAPIReader returns a copy of the context in which the loader lists the Snapshots from the API server through
+// the given reader in pages of SnapshotListPageSize, instead of listing all of them from the cache at once
+func WithAPIReader(ctx context.Context, reader client.Reader) context.Context {
+	return context.WithValue(ctx, apiReaderContextKey{}, reader)
+}
+
+// getPagedSnapshots lists the Snapshots of the Application kept by the filter page by page through the reader,
+// a nil filter keeps all of them. The API server can't select the Snapshots by their application, the Snapshots
+// of the other applications in the namespace are skipped without being passed to the filter.
+func getPagedSnapshots(ctx context.Context, reader client.Reader, application *applicationapiv1alpha1.Application, filter SnapshotFilter) (*[]applicationapiv1alpha1.Snapshot, error) {
+	snapshots := []applicationapiv1alpha1.Snapshot{}
+	continueToken := ""
+	for {
+		snapshotList := &applicationapiv1alpha1.SnapshotList{}
+		opts := []client.ListOption{
+			client.InNamespace(application.Namespace),
+			client.Limit(SnapshotListPageSize),
+			client.Continue(continueToken),
+		}
+		if err := reader.List(ctx, snapshotList, opts...); err != nil {
+			return nil, err
+		}
+
+		for i := range snapshotList.Items {
+			if snapshotList.Items[i].Spec.Application != application.Name {
+				continue
+			}
+			if filter == nil {
+				snapshots = append(snapshots, snapshotList.Items[i])
+				continue
+			}
+			keep, stop := filter(&snapshotList.Items[i])
+			if keep {
+				snapshots = append(snapshots, snapshotList.Items[i])
+			}
+			if stop {
+				return &snapshots, nil
+			}
+		}
+
+		continueToken = snapshotList.Continue
+		if continueToken == "" {
+			return &snapshots, nil
+		}
+	}
+}
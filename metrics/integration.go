@@ -81,6 +81,37 @@ var (
 		[]string{"type", "reason"},
 	)
 
+	IntegrationTestEventsDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "integration_svc_integration_test_events_dropped_total",
+			Help: "Total number of integration test lifecycle events dropped because the delivery queue was full",
+		},
+	)
+
+	PipelineRunQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "integration_svc_pipelinerun_queue_depth",
+			Help: "Number of integration tests waiting for a free slot of the cap on concurrently running integration PipelineRuns",
+		},
+	)
+
+	PipelineRunQueueWaitSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "integration_svc_pipelinerun_queue_wait_seconds",
+			Help:    "Time duration integration tests waited for a free slot of the cap on concurrently running integration PipelineRuns",
+			Buckets: []float64{1, 5, 15, 30, 60, 150, 300, 600, 900, 1800, 3600},
+		},
+	)
+
+	SnapshotTimeInQueueSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "integration_svc_snapshot_time_in_queue_seconds",
+			Help:    "Time duration snapshots waited from their creation till their first integration pipelineRun started",
+			Buckets: []float64{1, 5, 15, 30, 60, 150, 300, 600, 900, 1800, 3600},
+		},
+		[]string{"namespace"},
+	)
+
 	ReleaseLatencySeconds = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "integration_svc_release_latency_seconds",
@@ -131,6 +162,22 @@ func RegisterReleaseLatency(startTime metav1.Time) {
 	ReleaseLatencySeconds.Observe(latency)
 }
 
+func RegisterDroppedEvent() {
+	IntegrationTestEventsDroppedTotal.Inc()
+}
+
+func RegisterPipelineRunQueueDepth(depth int) {
+	PipelineRunQueueDepth.Set(float64(depth))
+}
+
+func RegisterPipelineRunQueueWait(duration time.Duration) {
+	PipelineRunQueueWaitSeconds.Observe(duration.Seconds())
+}
+
+func RegisterSnapshotTimeInQueue(namespace string, duration time.Duration) {
+	SnapshotTimeInQueueSeconds.With(prometheus.Labels{"namespace": namespace}).Observe(duration.Seconds())
+}
+
 func init() {
 	metrics.Registry.MustRegister(
 		SnapshotCreatedToPipelineRunStartedStaticEnvSeconds,
@@ -141,5 +188,9 @@ func init() {
 		SnapshotDurationSeconds,
 		SnapshotTotal,
 		ReleaseLatencySeconds,
+		IntegrationTestEventsDroppedTotal,
+		PipelineRunQueueDepth,
+		PipelineRunQueueWaitSeconds,
+		SnapshotTimeInQueueSeconds,
 	)
 }
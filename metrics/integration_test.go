@@ -264,4 +264,15 @@ var _ = Describe("Metrics Integration", Ordered, func() {
 			Expect(testutil.CollectAndCount(ReleaseLatencySeconds)).To(Equal(1))
 		})
 	})
+
+	Context("When RegisterSnapshotTimeInQueue is called", func() {
+
+		It("adds an observation to SnapshotTimeInQueueSeconds labeled by the namespace", func() {
+			RegisterSnapshotTimeInQueue("tenant-a", 90*time.Second)
+			RegisterSnapshotTimeInQueue("tenant-a", 20*time.Minute)
+			RegisterSnapshotTimeInQueue("tenant-b", 3*time.Second)
+
+			Expect(testutil.CollectAndCount(SnapshotTimeInQueueSeconds)).To(Equal(2))
+		})
+	})
 })
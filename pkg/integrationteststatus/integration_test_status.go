@@ -19,6 +19,7 @@ package integrationteststatus
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/santhosh-tekuri/jsonschema/v5"
@@ -45,6 +46,10 @@ const (
 	IntegrationTestStatusTestPassed // TestPassed
 	// Integration PLR is invalid
 	IntegrationTestStatusTestInvalid // TestInvalid
+	// Integration PLR isn't created because the ITS doesn't select any component of the snapshot
+	IntegrationTestStatusSkipped // Skipped
+	// Integration PLR couldn't run the tests because of a configuration problem, e.g. its pipeline couldn't be resolved
+	IntegrationTestStatusTestError // TestError
 )
 
 const integrationTestStatusesSchema = `{
@@ -73,12 +78,82 @@ const integrationTestStatusesSchema = `{
         },
         "testPipelineRunName": {
           "type": "string"
+        },
+        "scheduled": {
+          "type": "boolean"
+        },
+        "attempt": {
+          "type": "integer",
+          "minimum": 0
+        },
+        "timedOutAfter": {
+          "type": "string"
+        },
+        "watchdogTimedOutAfter": {
+          "type": "string"
+        },
+        "failedStepLog": {
+          "type": "string"
+        },
+        "taskResults": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "name": {
+                "type": "string"
+              }
+            },
+            "required": ["name"]
+          }
+        },
+        "creationFailures": {
+          "type": "integer",
+          "minimum": 0
+        },
+        "lastCreationFailureTime": {
+          "type": "string"
+        },
+        "reusedFrom": {
+          "type": "string"
+        },
+        "recreations": {
+          "type": "integer",
+          "minimum": 0
+        },
+        "attempts": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "status": {
+                "type": "string"
+              },
+              "details": {
+                "type": "string"
+              },
+              "startTime": {
+                "type": "string"
+              },
+              "completionTime": {
+                "type": "string"
+              },
+              "testPipelineRunName": {
+                "type": "string"
+              }
+            },
+            "required": ["status", "testPipelineRunName"]
+          }
         }
       },
 	  "required": ["scenario", "status", "lastUpdateTime"]
 	}
   }`
 
+// MaxTestAttempts is the maximum number of attempts kept in the history of the test status,
+// it keeps the size of the snapshot annotation bounded when the test is re-run many times
+const MaxTestAttempts = 5
+
 // IntegrationTestStatusDetail contains metadata about the particular scenario testing status
 type IntegrationTestStatusDetail struct {
 	// ScenarioName name
@@ -95,6 +170,86 @@ type IntegrationTestStatusDetail struct {
 	CompletionTime *time.Time `json:"completionTime,omitempty"` // pointer to make omitempty work
 	// TestPipelineName name of testing pipelineRun
 	TestPipelineRunName string `json:"testPipelineRunName,omitempty"`
+	// Attempt is the number of testing pipelineRuns created for the test, including re-runs
+	Attempt int `json:"attempt,omitempty"`
+	// Scheduled is true when the test was triggered by the schedule of the scenario
+	Scheduled bool `json:"scheduled,omitempty"`
+	// TimedOutAfter is the duration after which the testing pipelineRun timed out, empty when it didn't time out
+	TimedOutAfter string `json:"timedOutAfter,omitempty"`
+	// WatchdogTimedOutAfter is the hard deadline after which the watchdog failed the test stuck in progress,
+	// empty when the watchdog didn't fail it
+	WatchdogTimedOutAfter string `json:"watchdogTimedOutAfter,omitempty"`
+	// FailedStepLog is the tail of the log of the first failed step of the testing pipelineRun,
+	// kept to be reported after the pipelineRun is pruned
+	FailedStepLog string `json:"failedStepLog,omitempty"`
+	// TaskResults contains the outcome of each task of the finished testing pipelineRun
+	TaskResults []TaskResult `json:"taskResults,omitempty"`
+	// CreationFailures is the number of consecutive transient failures to create the testing pipelineRun,
+	// it is reset once the testing pipelineRun is created
+	CreationFailures int `json:"creationFailures,omitempty"`
+	// LastCreationFailureTime is the time of the last transient failure to create the testing pipelineRun
+	LastCreationFailureTime *time.Time `json:"lastCreationFailureTime,omitempty"` // pointer to make omitempty work
+	// ReusedFrom is the name of the snapshot with identical components whose result of the test was reused,
+	// empty when the test ran for the snapshot
+	ReusedFrom string `json:"reusedFrom,omitempty"`
+	// ScenarioGeneration is the generation of the IntegrationTestScenario the testing pipelineRun was created from,
+	// the result of the test is reused only while the IntegrationTestScenario doesn't change
+	ScenarioGeneration int64 `json:"scenarioGeneration,omitempty"`
+	// Recreations is the number of times the testing pipelineRun was recreated because it was deleted before
+	// producing results, it is reset by re-runs of the test
+	Recreations int `json:"recreations,omitempty"`
+	// Attempts contains the history of the testing pipelineRuns of the test ordered from the oldest to the newest,
+	// capped at MaxTestAttempts entries, the last attempt reflects the status above
+	Attempts []TestAttempt `json:"attempts,omitempty"`
+}
+
+// TestAttempt contains the status of a single testing pipelineRun of the test
+type TestAttempt struct {
+	// The status of the attempt
+	Status IntegrationTestStatus `json:"status"`
+	// The details of the status of the attempt
+	Details string `json:"details,omitempty"`
+	// Startime when the attempt moved to inProgress
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// Completion time when the attempt failed or passed
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	// TestPipelineRunName name of the testing pipelineRun of the attempt
+	TestPipelineRunName string `json:"testPipelineRunName"`
+}
+
+// updateLatestAttempt copies the status of the test into its latest attempt, unless the test
+// was already reset for a re-run and the latest attempt belongs to the previous testing pipelineRun
+func (detail *IntegrationTestStatusDetail) updateLatestAttempt() {
+	if len(detail.Attempts) == 0 || detail.TestPipelineRunName == "" {
+		return
+	}
+	latest := &detail.Attempts[len(detail.Attempts)-1]
+	if latest.TestPipelineRunName != detail.TestPipelineRunName {
+		return
+	}
+	latest.Status = detail.Status
+	latest.Details = detail.Details
+	latest.StartTime = detail.StartTime
+	latest.CompletionTime = detail.CompletionTime
+}
+
+// TaskResult contains the outcome of a single task of the testing pipelineRun
+type TaskResult struct {
+	// Name of the pipeline task
+	Name string `json:"name"`
+	// Result reported in the TEST_OUTPUT of the task, empty when the task didn't report a valid TEST_OUTPUT
+	Result string `json:"result,omitempty"`
+	// Reason of the Succeeded condition of the TaskRun, set when the task didn't report a valid TEST_OUTPUT
+	Reason string `json:"reason,omitempty"`
+	// Successes, Failures, Warnings and Note as reported in the TEST_OUTPUT of the task
+	Successes int    `json:"successes,omitempty"`
+	Failures  int    `json:"failures,omitempty"`
+	Warnings  int    `json:"warnings,omitempty"`
+	Note      string `json:"note,omitempty"`
+	// ValidationError describes why the TEST_OUTPUT of the task couldn't be parsed
+	ValidationError string `json:"validationError,omitempty"`
+	// Retries is the number of failed attempts of the task which were retried, the outcome is the one of the final attempt
+	Retries int `json:"retries,omitempty"`
 }
 
 // SnapshotIntegrationTestStatuses type handles details about snapshot tests
@@ -114,7 +269,9 @@ func (sits *IntegrationTestStatus) IsFinal() bool {
 		IntegrationTestStatusEnvironmentProvisionError_Deprecated,
 		IntegrationTestStatusTestFail,
 		IntegrationTestStatusTestPassed,
-		IntegrationTestStatusTestInvalid:
+		IntegrationTestStatusTestInvalid,
+		IntegrationTestStatusSkipped,
+		IntegrationTestStatusTestError:
 		return true
 	}
 	return false
@@ -130,11 +287,24 @@ func (sits *SnapshotIntegrationTestStatuses) ResetDirty() {
 	sits.dirty = false
 }
 
-// ResetStatus reset status of test back to initial Pending status and removes invalidated values
+// ResetStatus reset status of test back to initial Pending status and removes invalidated values,
+// the previous attempts of the test are kept in its history
 func (sits *SnapshotIntegrationTestStatuses) ResetStatus(scenarioName string) {
+	if detail, ok := sits.statuses[scenarioName]; ok {
+		// detach the test from its previous pipelineRun first, so its attempt isn't reset too
+		detail.TestPipelineRunName = ""
+	}
 	sits.UpdateTestStatusIfChanged(scenarioName, IntegrationTestStatusPending, "Pending")
 	detail := sits.statuses[scenarioName]
 	detail.TestPipelineRunName = ""
+	detail.Scheduled = false
+	detail.TimedOutAfter = ""
+	detail.WatchdogTimedOutAfter = ""
+	detail.FailedStepLog = ""
+	detail.TaskResults = nil
+	detail.ReusedFrom = ""
+	detail.ScenarioGeneration = 0
+	detail.Recreations = 0
 	sits.dirty = true
 }
 
@@ -177,7 +347,9 @@ func (sits *SnapshotIntegrationTestStatuses) UpdateTestStatusIfChanged(scenarioN
 			IntegrationTestStatusDeleted,
 			IntegrationTestStatusTestFail,
 			IntegrationTestStatusTestPassed,
-			IntegrationTestStatusTestInvalid:
+			IntegrationTestStatusTestInvalid,
+			IntegrationTestStatusSkipped,
+			IntegrationTestStatusTestError:
 			detail.CompletionTime = &timestamp
 		}
 	}
@@ -188,9 +360,11 @@ func (sits *SnapshotIntegrationTestStatuses) UpdateTestStatusIfChanged(scenarioN
 		sits.dirty = true
 	}
 
+	detail.updateLatestAttempt()
 }
 
-// UpdateTestPipelineRunName updates TestPipelineRunName if changed
+// UpdateTestPipelineRunName updates TestPipelineRunName if changed, the attempt counter of the test
+// is incremented and a new attempt is added to its history each time a new testing pipelineRun is recorded
 // scenario must already exist in statuses
 func (sits *SnapshotIntegrationTestStatuses) UpdateTestPipelineRunName(scenarioName string, pipelineRunName string) error {
 	detail, ok := sits.GetScenarioStatus(scenarioName)
@@ -200,6 +374,185 @@ func (sits *SnapshotIntegrationTestStatuses) UpdateTestPipelineRunName(scenarioN
 
 	if detail.TestPipelineRunName != pipelineRunName {
 		detail.TestPipelineRunName = pipelineRunName
+		if pipelineRunName != "" {
+			detail.CreationFailures = 0
+			detail.LastCreationFailureTime = nil
+			detail.Attempt++
+			detail.Attempts = append(detail.Attempts, TestAttempt{TestPipelineRunName: pipelineRunName})
+			if len(detail.Attempts) > MaxTestAttempts {
+				detail.Attempts = slices.Clone(detail.Attempts[len(detail.Attempts)-MaxTestAttempts:])
+			}
+			detail.updateLatestAttempt()
+		}
+		sits.dirty = true
+	}
+
+	return nil
+}
+
+// RecordTestCreationFailure increments the number of consecutive transient failures to create the testing
+// pipelineRun of the test and records the time of the failure, the number of failures is returned
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) RecordTestCreationFailure(scenarioName string, timestamp time.Time) (int, error) {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return 0, fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	timestamp = timestamp.UTC()
+	detail.CreationFailures++
+	detail.LastCreationFailureTime = &timestamp
+	sits.dirty = true
+
+	return detail.CreationFailures, nil
+}
+
+// RecordTestPipelineRunRecreation records the testing pipelineRun of the test, which was deleted before producing
+// results, as deleted with the given details in the history of the test and resets the test to Pending, so a new
+// testing pipelineRun is created for it. The number of recreations of the test is returned
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) RecordTestPipelineRunRecreation(scenarioName string, details string) (int, error) {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return 0, fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	recreations := detail.Recreations + 1
+	sits.UpdateTestStatusIfChanged(scenarioName, IntegrationTestStatusDeleted, details)
+	sits.ResetStatus(scenarioName)
+	sits.UpdateTestStatusIfChanged(scenarioName, IntegrationTestStatusPending, details)
+	detail.Recreations = recreations
+
+	return recreations, nil
+}
+
+// ReuseTestResult copies the final result of the test of another snapshot with identical components into the test,
+// the test refers to the testing pipelineRun of the other snapshot and records the snapshot its result was reused from
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) ReuseTestResult(scenarioName string, source *IntegrationTestStatusDetail, sourceSnapshotName string) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+	if !source.Status.IsFinal() {
+		return fmt.Errorf("result %s of the scenario %s of snapshot %s isn't final and cannot be reused", source.Status, scenarioName, sourceSnapshotName)
+	}
+
+	detail.TestPipelineRunName = ""
+	sits.UpdateTestStatusIfChanged(scenarioName, source.Status, fmt.Sprintf("Result reused from snapshot %s: %s", sourceSnapshotName, source.Details))
+	detail.StartTime = copyTime(source.StartTime)
+	detail.CompletionTime = copyTime(source.CompletionTime)
+	detail.TestPipelineRunName = source.TestPipelineRunName
+	detail.TimedOutAfter = source.TimedOutAfter
+	detail.FailedStepLog = source.FailedStepLog
+	detail.TaskResults = slices.Clone(source.TaskResults)
+	detail.ReusedFrom = sourceSnapshotName
+	detail.ScenarioGeneration = source.ScenarioGeneration
+	sits.dirty = true
+
+	return nil
+}
+
+// copyTime returns a copy of the given time, nil is kept
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// UpdateTestScheduled updates the flag marking the test as triggered by the schedule of the scenario if changed
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) UpdateTestScheduled(scenarioName string, scheduled bool) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	if detail.Scheduled != scheduled {
+		detail.Scheduled = scheduled
+		sits.dirty = true
+	}
+
+	return nil
+}
+
+// UpdateTestScenarioGeneration updates the generation of the IntegrationTestScenario the testing pipelineRun
+// was created from if changed, scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) UpdateTestScenarioGeneration(scenarioName string, generation int64) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	if detail.ScenarioGeneration != generation {
+		detail.ScenarioGeneration = generation
+		sits.dirty = true
+	}
+
+	return nil
+}
+
+// UpdateTestTimedOutAfter updates the duration after which the testing pipelineRun timed out if changed
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) UpdateTestTimedOutAfter(scenarioName string, timedOutAfter string) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	if detail.TimedOutAfter != timedOutAfter {
+		detail.TimedOutAfter = timedOutAfter
+		sits.dirty = true
+	}
+
+	return nil
+}
+
+// MarkTestTimedOutByWatchdog fails the test stuck in progress longer than the hard deadline of the watchdog,
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) MarkTestTimedOutByWatchdog(scenarioName string, deadline time.Duration) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	sits.UpdateTestStatusIfChanged(scenarioName, IntegrationTestStatusTestFail,
+		fmt.Sprintf("Integration test was in progress longer than the hard deadline of %s without its pipeline run progressing, "+
+			"it was failed by the watchdog timeout", deadline))
+	detail.WatchdogTimedOutAfter = deadline.String()
+	sits.dirty = true
+
+	return nil
+}
+
+// UpdateTestFailedStepLog updates the log excerpt of the first failed step of the testing pipelineRun if changed
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) UpdateTestFailedStepLog(scenarioName string, failedStepLog string) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	if detail.FailedStepLog != failedStepLog {
+		detail.FailedStepLog = failedStepLog
+		sits.dirty = true
+	}
+
+	return nil
+}
+
+// UpdateTaskResults updates the per-task results of the testing pipelineRun if changed
+// scenario must already exist in statuses
+func (sits *SnapshotIntegrationTestStatuses) UpdateTaskResults(scenarioName string, taskResults []TaskResult) error {
+	detail, ok := sits.GetScenarioStatus(scenarioName)
+	if !ok {
+		return fmt.Errorf("scenario name %s not found within the SnapshotIntegrationTestStatus, and cannot be updated", scenarioName)
+	}
+
+	if !slices.Equal(detail.TaskResults, taskResults) {
+		detail.TaskResults = taskResults
 		sits.dirty = true
 	}
 
@@ -271,7 +624,21 @@ func (sits *SnapshotIntegrationTestStatuses) GetScenarioStatus(scenarioName stri
 //	    "details": "Failed ...",
 //	    "startTime": "2023-07-26T14:57:49+02:00",
 //	    "completionTime": "2023-07-26T16:57:49+02:00",
-//	    "testPipelineRunName": "pipeline-run-feedbeef"
+//	    "testPipelineRunName": "pipeline-run-feedbeef",
+//	    "attempt": 1,
+//	    "taskResults": [
+//	      {"name": "task-1", "result": "FAILURE", "successes": 3, "failures": 1},
+//	      {"name": "task-2", "reason": "Succeeded"}
+//	    ],
+//	    "attempts": [
+//	      {
+//	        "status": "EnvironmentProvisionError",
+//	        "details": "Failed ...",
+//	        "startTime": "2023-07-26T14:57:49+02:00",
+//	        "completionTime": "2023-07-26T16:57:49+02:00",
+//	        "testPipelineRunName": "pipeline-run-feedbeef"
+//	      }
+//	    ]
 //	  }
 //	]
 func (sits *SnapshotIntegrationTestStatuses) MarshalJSON() ([]byte, error) {
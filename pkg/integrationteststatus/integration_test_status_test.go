@@ -19,6 +19,7 @@ package integrationteststatus_test
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -46,6 +47,8 @@ var _ = Describe("integrationteststatus library unittests", func() {
 			Entry("When status is TestPass", intgteststat.IntegrationTestStatusTestPassed, "TestPassed"),
 			Entry("When status is Deleted", intgteststat.IntegrationTestStatusDeleted, "Deleted"),
 			Entry("When status is Invalid", intgteststat.IntegrationTestStatusTestInvalid, "TestInvalid"),
+			Entry("When status is Skipped", intgteststat.IntegrationTestStatusSkipped, "Skipped"),
+			Entry("When status is TestError", intgteststat.IntegrationTestStatusTestError, "TestError"),
 		)
 
 		DescribeTable("Status to JSON and vice versa",
@@ -67,6 +70,8 @@ var _ = Describe("integrationteststatus library unittests", func() {
 			Entry("When status is TestPass", intgteststat.IntegrationTestStatusTestPassed, "TestPassed"),
 			Entry("When status is Deleted", intgteststat.IntegrationTestStatusDeleted, "Deleted"),
 			Entry("When status is Invalid", intgteststat.IntegrationTestStatusTestInvalid, "TestInvalid"),
+			Entry("When status is Skipped", intgteststat.IntegrationTestStatusSkipped, "Skipped"),
+			Entry("When status is TestError", intgteststat.IntegrationTestStatusTestError, "TestError"),
 		)
 
 		DescribeTable("Check IsFinal logic",
@@ -80,6 +85,8 @@ var _ = Describe("integrationteststatus library unittests", func() {
 			Entry("When status is TestFail", intgteststat.IntegrationTestStatusTestFail, true),
 			Entry("When status is TestPass", intgteststat.IntegrationTestStatusTestPassed, true),
 			Entry("When status is Invalid", intgteststat.IntegrationTestStatusTestInvalid, true),
+			Entry("When status is Skipped", intgteststat.IntegrationTestStatusSkipped, true),
+			Entry("When status is TestError", intgteststat.IntegrationTestStatusTestError, true),
 			Entry("When status is Other", intgteststat.IntegrationTestStatusPending, false),
 		)
 
@@ -191,6 +198,8 @@ var _ = Describe("integrationteststatus library unittests", func() {
 			Entry("When status is TestPass", intgteststat.IntegrationTestStatusTestPassed, true),
 			Entry("When status is Deleted", intgteststat.IntegrationTestStatusDeleted, true),
 			Entry("When status is Invalid", intgteststat.IntegrationTestStatusTestInvalid, true),
+			Entry("When status is Skipped", intgteststat.IntegrationTestStatusSkipped, true),
+			Entry("When status is TestError", intgteststat.IntegrationTestStatusTestError, true),
 		)
 
 		It("Change back to InProgress updates timestamps accordingly", func() {
@@ -260,11 +269,368 @@ var _ = Describe("integrationteststatus library unittests", func() {
 			Expect(sits.IsDirty()).To(BeFalse())
 		})
 
+		It("counts the attempts of the test for each new pipeline run", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Attempt).To(Equal(1))
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.Attempt).To(Equal(1))
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, "pipeline-run-rerun")).To(Succeed())
+			Expect(detail.Attempt).To(Equal(2))
+		})
+
+		It("keeps the history of the attempts of the test across re-runs", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestFail, "failed")
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			firstCompletionTime := detail.CompletionTime
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.Attempts).To(HaveLen(1))
+			Expect(detail.Attempts[0].Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, "pipeline-run-rerun")).To(Succeed())
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestPassed, "passed")
+
+			Expect(detail.Attempts).To(HaveLen(2))
+			Expect(detail.Attempts[0]).To(Equal(intgteststat.TestAttempt{
+				Status:              intgteststat.IntegrationTestStatusTestFail,
+				Details:             "failed",
+				StartTime:           detail.Attempts[0].StartTime,
+				CompletionTime:      firstCompletionTime,
+				TestPipelineRunName: pipelineRunName,
+			}))
+			Expect(detail.Attempts[0].StartTime).NotTo(BeNil())
+			Expect(detail.Attempts[1]).To(Equal(intgteststat.TestAttempt{
+				Status:              intgteststat.IntegrationTestStatusTestPassed,
+				Details:             "passed",
+				StartTime:           detail.StartTime,
+				CompletionTime:      detail.CompletionTime,
+				TestPipelineRunName: "pipeline-run-rerun",
+			}))
+			// the top-level fields reflect the latest attempt
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			Expect(detail.TestPipelineRunName).To(Equal("pipeline-run-rerun"))
+		})
+
+		It("caps the history of the attempts of the test", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			for i := 0; i < intgteststat.MaxTestAttempts+2; i++ {
+				sits.ResetStatus(testScenarioName)
+				Expect(sits.UpdateTestPipelineRunName(testScenarioName, fmt.Sprintf("pipeline-run-%d", i))).To(Succeed())
+			}
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Attempt).To(Equal(intgteststat.MaxTestAttempts + 2))
+			Expect(detail.Attempts).To(HaveLen(intgteststat.MaxTestAttempts))
+			Expect(detail.Attempts[0].TestPipelineRunName).To(Equal("pipeline-run-2"))
+			Expect(detail.Attempts[intgteststat.MaxTestAttempts-1].TestPipelineRunName).To(
+				Equal(fmt.Sprintf("pipeline-run-%d", intgteststat.MaxTestAttempts+1)))
+		})
+
+		It("round-trips the history of the attempts through JSON", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestFail, "failed")
+			sits.ResetStatus(testScenarioName)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, "pipeline-run-rerun")).To(Succeed())
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaled, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			unmarshaledDetail, ok := unmarshaled.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.Attempts).To(HaveLen(2))
+			Expect(unmarshaledDetail.Attempts[0].Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+			Expect(unmarshaledDetail.Attempts[0].CompletionTime.Equal(*detail.Attempts[0].CompletionTime)).To(BeTrue())
+			Expect(unmarshaledDetail.Attempts[1].Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(unmarshaledDetail.Attempts[1].TestPipelineRunName).To(Equal("pipeline-run-rerun"))
+		})
+
+		It("reads the statuses written without the history of the attempts", func() {
+			legacy := `[{"scenario": "test-scenario", "status": "TestPassed", "lastUpdateTime": "2023-07-26T16:57:49+02:00",
+				"details": "passed", "testPipelineRunName": "pipeline-run-abcdf", "attempt": 1}]`
+			legacySits, err := intgteststat.NewSnapshotIntegrationTestStatuses(legacy)
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := legacySits.GetScenarioStatus("test-scenario")
+			Expect(ok).To(BeTrue())
+			Expect(detail.Attempts).To(BeEmpty())
+			Expect(detail.TestPipelineRunName).To(Equal("pipeline-run-abcdf"))
+		})
+
+		It("counts the transient creation failures of the test until its pipeline run is created", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusPending, "Pending")
+			sits.ResetDirty()
+			failureTime := time.Date(2023, 7, 26, 16, 57, 49, 0, time.UTC)
+
+			failures, err := sits.RecordTestCreationFailure(testScenarioName, failureTime)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failures).To(Equal(1))
+			failures, err = sits.RecordTestCreationFailure(testScenarioName, failureTime.Add(time.Minute))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failures).To(Equal(2))
+			Expect(sits.IsDirty()).To(BeTrue())
+
+			// the failures are kept in the annotation, so the backoff survives the restarts of the controller
+			marshaled, err := json.Marshal(sits)
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaledSits, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).ToNot(HaveOccurred())
+			detail, ok := unmarshaledSits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.CreationFailures).To(Equal(2))
+			Expect(detail.LastCreationFailureTime.Equal(failureTime.Add(time.Minute))).To(BeTrue())
+
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			detail, ok = sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.CreationFailures).To(BeZero())
+			Expect(detail.LastCreationFailureTime).To(BeNil())
+		})
+
+		It("fails to record a creation failure when testScenario doesn't exist", func() {
+			_, err := sits.RecordTestCreationFailure(testScenarioName, time.Now())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("records the recreations of the pipeline run deleted before producing results until the test is re-run", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)).To(Succeed())
+			sits.ResetDirty()
+
+			recreations, err := sits.RecordTestPipelineRunRecreation(testScenarioName, "deleted, recreating")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recreations).To(Equal(1))
+			Expect(sits.IsDirty()).To(BeTrue())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusPending))
+			Expect(detail.Details).To(Equal("deleted, recreating"))
+			Expect(detail.TestPipelineRunName).To(BeEmpty())
+			Expect(detail.Attempts).To(HaveLen(1))
+			Expect(detail.Attempts[0].Status).To(Equal(intgteststat.IntegrationTestStatusDeleted))
+			Expect(detail.Attempts[0].Details).To(Equal("deleted, recreating"))
+
+			Expect(sits.UpdateTestPipelineRunName(testScenarioName, "pipeline-run-recreated")).To(Succeed())
+			recreations, err = sits.RecordTestPipelineRunRecreation(testScenarioName, "deleted again, recreating")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recreations).To(Equal(2))
+			Expect(detail.Attempts).To(HaveLen(2))
+
+			// the recreations survive the round-trip through the annotation
+			marshaled, err := json.Marshal(sits)
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaledSits, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaledDetail, ok := unmarshaledSits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.Recreations).To(Equal(2))
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.Recreations).To(BeZero())
+		})
+
+		It("fails to record a recreation when testScenario doesn't exist", func() {
+			_, err := sits.RecordTestPipelineRunRecreation(testScenarioName, "deleted, recreating")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails the test stuck in progress by the watchdog until its status is reset", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.MarkTestTimedOutByWatchdog(testScenarioName, 24*time.Hour)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestFail))
+			Expect(detail.Details).To(ContainSubstring("watchdog timeout"))
+			Expect(detail.WatchdogTimedOutAfter).To(Equal("24h0m0s"))
+			Expect(detail.CompletionTime).ToNot(BeNil())
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.WatchdogTimedOutAfter).To(BeEmpty())
+		})
+
+		It("fails to fail the test by the watchdog when testScenario doesn't exist", func() {
+			Expect(sits.MarkTestTimedOutByWatchdog(testScenarioName, 24*time.Hour)).ToNot(Succeed())
+		})
+
+		It("reuses the final result of the test of another snapshot until its status is reset", func() {
+			startTime := time.Date(2023, 7, 26, 16, 57, 49, 0, time.UTC)
+			completionTime := startTime.Add(time.Hour)
+			source := &intgteststat.IntegrationTestStatusDetail{
+				ScenarioName:        testScenarioName,
+				Status:              intgteststat.IntegrationTestStatusTestPassed,
+				Details:             "test passed",
+				StartTime:           &startTime,
+				CompletionTime:      &completionTime,
+				TestPipelineRunName: pipelineRunName,
+				TaskResults:         []intgteststat.TaskResult{{Name: "task-1"}},
+				ScenarioGeneration:  3,
+			}
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusPending, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.ReuseTestResult(testScenarioName, source, "snapshot-reused")).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+			Expect(detail.Details).To(Equal("Result reused from snapshot snapshot-reused: test passed"))
+			Expect(detail.StartTime.Equal(startTime)).To(BeTrue())
+			Expect(detail.CompletionTime.Equal(completionTime)).To(BeTrue())
+			Expect(detail.TestPipelineRunName).To(Equal(pipelineRunName))
+			Expect(detail.TaskResults).To(Equal(source.TaskResults))
+			Expect(detail.ReusedFrom).To(Equal("snapshot-reused"))
+			Expect(detail.ScenarioGeneration).To(Equal(int64(3)))
+			// the test didn't create any pipelineRun itself
+			Expect(detail.Attempt).To(BeZero())
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaled, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).ToNot(HaveOccurred())
+			unmarshaledDetail, ok := unmarshaled.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.ReusedFrom).To(Equal("snapshot-reused"))
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.ReusedFrom).To(BeEmpty())
+		})
+
+		It("fails to reuse the result of the test which isn't final or doesn't exist", func() {
+			source := &intgteststat.IntegrationTestStatusDetail{
+				ScenarioName: testScenarioName,
+				Status:       intgteststat.IntegrationTestStatusInProgress,
+			}
+			Expect(sits.ReuseTestResult(testScenarioName, source, "snapshot-reused")).NotTo(Succeed())
+
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusPending, testDetails)
+			Expect(sits.ReuseTestResult(testScenarioName, source, "snapshot-reused")).NotTo(Succeed())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.ReusedFrom).To(BeEmpty())
+		})
+
 		It("fails to update details with pipeline run name when testScenario doesn't exist", func() {
 			err := sits.UpdateTestPipelineRunName(testScenarioName, pipelineRunName)
 			Expect(err).NotTo(BeNil())
 		})
 
+		It("marks the test as scheduled until its status is reset", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.UpdateTestScheduled(testScenarioName, true)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.Scheduled).To(BeTrue())
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).To(BeNil())
+			Expect(string(marshaled)).To(ContainSubstring(`"scheduled":true`))
+			unmarshaled, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).To(BeNil())
+			unmarshaledDetail, ok := unmarshaled.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.Scheduled).To(BeTrue())
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.Scheduled).To(BeFalse())
+			Expect(sits.UpdateTestScheduled("missing-scenario", true)).NotTo(Succeed())
+		})
+
+		It("records the generation of the scenario the pipelineRun was created from", func() {
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusInProgress, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.UpdateTestScenarioGeneration(testScenarioName, 2)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.ScenarioGeneration).To(Equal(int64(2)))
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).To(BeNil())
+			Expect(string(marshaled)).To(ContainSubstring(`"scenarioGeneration":2`))
+
+			sits.ResetDirty()
+			Expect(sits.UpdateTestScenarioGeneration(testScenarioName, 2)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeFalse())
+
+			sits.ResetStatus(testScenarioName)
+			Expect(detail.ScenarioGeneration).To(BeZero())
+			Expect(sits.UpdateTestScenarioGeneration("missing-scenario", 2)).NotTo(Succeed())
+		})
+
+		It("records the task results until the test status is reset", func() {
+			taskResults := []intgteststat.TaskResult{
+				{Name: "task-1", Result: "FAILURE", Successes: 3, Failures: 1},
+				{Name: "task-2", Reason: "Succeeded"},
+				{Name: "task-3", Reason: "Failed", ValidationError: "invalid TEST_OUTPUT"},
+			}
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestFail, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.UpdateTaskResults(testScenarioName, taskResults)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			sits.ResetDirty()
+			Expect(sits.UpdateTaskResults(testScenarioName, slices.Clone(taskResults))).To(Succeed())
+			Expect(sits.IsDirty()).To(BeFalse())
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).To(BeNil())
+			unmarshaled, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).To(BeNil())
+			unmarshaledDetail, ok := unmarshaled.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.TaskResults).To(Equal(taskResults))
+
+			sits.ResetStatus(testScenarioName)
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.TaskResults).To(BeEmpty())
+			Expect(sits.UpdateTaskResults("missing-scenario", taskResults)).NotTo(Succeed())
+		})
+
+		It("records the failed step log until the test status is reset", func() {
+			failedStepLog := "<details>\n<summary>Logs of failed step <b>run-tests</b> in task <b>task-1</b></summary>\n\n```\nFAIL\n```\n\n</details>"
+			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusTestFail, testDetails)
+			sits.ResetDirty()
+
+			Expect(sits.UpdateTestFailedStepLog(testScenarioName, failedStepLog)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeTrue())
+			sits.ResetDirty()
+			Expect(sits.UpdateTestFailedStepLog(testScenarioName, failedStepLog)).To(Succeed())
+			Expect(sits.IsDirty()).To(BeFalse())
+
+			marshaled, err := json.Marshal(sits)
+			Expect(err).To(BeNil())
+			unmarshaled, err := intgteststat.NewSnapshotIntegrationTestStatuses(string(marshaled))
+			Expect(err).To(BeNil())
+			unmarshaledDetail, ok := unmarshaled.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(unmarshaledDetail.FailedStepLog).To(Equal(failedStepLog))
+
+			sits.ResetStatus(testScenarioName)
+			detail, ok := sits.GetScenarioStatus(testScenarioName)
+			Expect(ok).To(BeTrue())
+			Expect(detail.FailedStepLog).To(BeEmpty())
+			Expect(sits.UpdateTestFailedStepLog("missing-scenario", failedStepLog)).NotTo(Succeed())
+		})
+
 		It("Can export valid JSON without start and completion time (Pending)", func() {
 			sits.UpdateTestStatusIfChanged(testScenarioName, intgteststat.IntegrationTestStatusPending, testDetails)
 			detail, ok := sits.GetScenarioStatus(testScenarioName)
@@ -379,12 +745,20 @@ var _ = Describe("integrationteststatus library unittests", func() {
 						"status": "Pending",
 						"lastUpdateTime": "%s",
 						"details": "%s",
-						"testPipelineRunName": "%s"
+						"testPipelineRunName": "%s",
+						"attempt": 1,
+						"attempts": [
+							{
+								"status": "Pending",
+								"details": "%s",
+								"testPipelineRunName": "%s"
+							}
+						]
 					}
 				]`
 			marshaledTime, err := detail.LastUpdateTime.MarshalText()
 			Expect(err).To(BeNil())
-			expectedStr := fmt.Sprintf(expectedFormatStr, testScenarioName, marshaledTime, testDetails, pipelineRunName)
+			expectedStr := fmt.Sprintf(expectedFormatStr, testScenarioName, marshaledTime, testDetails, pipelineRunName, testDetails, pipelineRunName)
 			expected := []byte(expectedStr)
 
 			Expect(json.Marshal(sits)).To(MatchJSON(expected))
@@ -515,6 +889,18 @@ var _ = Describe("integrationteststatus library unittests", func() {
 
 		})
 
+		When("JSON contains test status annotation without task results", func() {
+			It("Returns expected test statuses", func() {
+				statuses, err := intgteststat.NewSnapshotIntegrationTestStatuses(`[{"scenario":"scenario-1","status":"TestPassed",` +
+					`"lastUpdateTime":"2023-07-26T16:57:49+02:00","details":"Integration test passed"}]`)
+				Expect(err).To(BeNil())
+				statusDetail, ok := statuses.GetScenarioStatus("scenario-1")
+				Expect(ok).To(BeTrue())
+				Expect(statusDetail.Status).To(Equal(intgteststat.IntegrationTestStatusTestPassed))
+				Expect(statusDetail.TaskResults).To(BeNil())
+			})
+		})
+
 		When("JSON contains invalid attributes", func() {
 			It("Returns error", func() {
 				_, err := intgteststat.NewSnapshotIntegrationTestStatuses("[{\"invalid\":\"data\"}]")
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pipelinerunqueue caps the number of integration PipelineRuns running at the same time
+// across all the Snapshots reconciled by the controller
+package pipelinerunqueue
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/konflux-ci/integration-service/metrics"
+)
+
+// WaitingTestExpiration is the duration after which a waiting test which hasn't tried to acquire a slot again
+// is removed from the queue, e.g. because its Snapshot was deleted, so it doesn't block the tests behind it
+const WaitingTestExpiration = 5 * time.Minute
+
+// DefaultQueue is the queue shared by the controllers, it is nil when the number of running
+// integration PipelineRuns isn't capped
+var DefaultQueue *Queue
+
+// waitingTest is a test waiting in the queue for a free slot
+type waitingTest struct {
+	// snapshotCreationTime orders the waiting tests, the tests of older Snapshots are promoted first
+	snapshotCreationTime time.Time
+	// enqueueTime is the time when the test started waiting
+	enqueueTime time.Time
+	// lastSeenTime is the last time when the test tried to acquire a slot
+	lastSeenTime time.Time
+}
+
+// Queue is a counting semaphore limiting the number of integration PipelineRuns running at the same time.
+// The tests which can't acquire a slot wait in the queue and the free slots are granted to the waiting tests
+// in the order of the creation time of their Snapshots, so bursts of new Snapshots don't starve the older ones.
+// A nil Queue doesn't limit anything.
+type Queue struct {
+	mu       sync.Mutex
+	capacity int
+	running  map[string]struct{}
+	waiting  map[string]*waitingTest
+	now      func() time.Time
+}
+
+// NewQueue returns a new queue allowing at most capacity tests to run at the same time
+func NewQueue(capacity int) *Queue {
+	return &Queue{
+		capacity: capacity,
+		running:  map[string]struct{}{},
+		waiting:  map[string]*waitingTest{},
+		now:      time.Now,
+	}
+}
+
+// TestKey returns the key identifying the integration test of the given Snapshot in the queue
+func TestKey(namespace, snapshotName, testName string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, snapshotName, testName)
+}
+
+// TryAcquire tries to acquire a slot for the test with the given key. If no slot is free for the test,
+// it is added to the queue (or stays there) and false is returned, the caller is expected to try again later.
+// A test which already holds a slot acquires it again.
+func (q *Queue) TryAcquire(key string, snapshotCreationTime time.Time) bool {
+	if q == nil {
+		return true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.running[key]; ok {
+		return true
+	}
+
+	now := q.now()
+	waiting, ok := q.waiting[key]
+	if !ok {
+		waiting = &waitingTest{snapshotCreationTime: snapshotCreationTime, enqueueTime: now}
+		q.waiting[key] = waiting
+	}
+	waiting.lastSeenTime = now
+	q.dropExpired(now)
+
+	free := q.capacity - len(q.running)
+	if free <= 0 || q.position(key) >= free {
+		metrics.RegisterPipelineRunQueueDepth(len(q.waiting))
+		return false
+	}
+
+	delete(q.waiting, key)
+	q.running[key] = struct{}{}
+	metrics.RegisterPipelineRunQueueDepth(len(q.waiting))
+	metrics.RegisterPipelineRunQueueWait(now.Sub(waiting.enqueueTime))
+	return true
+}
+
+// Track marks the test with the given key as running without waiting for a free slot, it is used to account for
+// the PipelineRuns which are already running, e.g. after a restart of the controller
+func (q *Queue) Track(key string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running[key] = struct{}{}
+	if _, ok := q.waiting[key]; ok {
+		delete(q.waiting, key)
+		metrics.RegisterPipelineRunQueueDepth(len(q.waiting))
+	}
+}
+
+// Release frees the slot held by the test with the given key, it is a no-op if the test doesn't hold a slot
+func (q *Queue) Release(key string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.running, key)
+}
+
+// Running returns the number of tests holding a slot
+func (q *Queue) Running() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.running)
+}
+
+// Waiting returns the keys of the tests waiting for a free slot in the order in which they will be promoted
+func (q *Queue) Waiting() []string {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.orderedWaitingKeys()
+}
+
+// position returns the number of waiting tests which are promoted before the test with the given key
+func (q *Queue) position(key string) int {
+	for i, waitingKey := range q.orderedWaitingKeys() {
+		if waitingKey == key {
+			return i
+		}
+	}
+	return len(q.waiting)
+}
+
+// orderedWaitingKeys returns the keys of the waiting tests ordered by the creation time of their Snapshots,
+// then by the time when they started waiting
+func (q *Queue) orderedWaitingKeys() []string {
+	keys := make([]string, 0, len(q.waiting))
+	for key := range q.waiting {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := q.waiting[keys[i]], q.waiting[keys[j]]
+		if !a.snapshotCreationTime.Equal(b.snapshotCreationTime) {
+			return a.snapshotCreationTime.Before(b.snapshotCreationTime)
+		}
+		if !a.enqueueTime.Equal(b.enqueueTime) {
+			return a.enqueueTime.Before(b.enqueueTime)
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// dropExpired removes the waiting tests which haven't tried to acquire a slot for a while
+func (q *Queue) dropExpired(now time.Time) {
+	for key, waiting := range q.waiting {
+		if now.Sub(waiting.lastSeenTime) > WaitingTestExpiration {
+			delete(q.waiting, key)
+		}
+	}
+}
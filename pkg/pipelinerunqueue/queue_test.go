@@ -0,0 +1,131 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerunqueue
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		queue   *Queue
+		now     time.Time
+		older   time.Time
+		newer   time.Time
+		newest  time.Time
+		advance = func(d time.Duration) { now = now.Add(d) }
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		older = now.Add(-3 * time.Hour)
+		newer = now.Add(-2 * time.Hour)
+		newest = now.Add(-1 * time.Hour)
+		queue = NewQueue(2)
+		queue.now = func() time.Time { return now }
+	})
+
+	It("grants slots up to the cap and queues the tests beyond it", func() {
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/snapshot-a/test-2", older)).To(BeTrue())
+		Expect(queue.Running()).To(Equal(2))
+
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeFalse())
+		Expect(queue.Running()).To(Equal(2))
+		Expect(queue.Waiting()).To(Equal([]string{"ns/snapshot-b/test-1"}))
+
+		queue.Release("ns/snapshot-a/test-1")
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeTrue())
+		Expect(queue.Running()).To(Equal(2))
+		Expect(queue.Waiting()).To(BeEmpty())
+	})
+
+	It("acquires the slot again for a test which already holds it", func() {
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.Running()).To(Equal(1))
+	})
+
+	It("promotes the waiting tests in the order of the creation time of their snapshots", func() {
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/snapshot-a/test-2", older)).To(BeTrue())
+
+		Expect(queue.TryAcquire("ns/snapshot-c/test-1", newest)).To(BeFalse())
+		advance(time.Second)
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeFalse())
+		Expect(queue.Waiting()).To(Equal([]string{"ns/snapshot-b/test-1", "ns/snapshot-c/test-1"}))
+
+		// the newest snapshot retries first but the free slot is reserved for the older one
+		queue.Release("ns/snapshot-a/test-1")
+		Expect(queue.TryAcquire("ns/snapshot-c/test-1", newest)).To(BeFalse())
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeTrue())
+
+		queue.Release("ns/snapshot-a/test-2")
+		Expect(queue.TryAcquire("ns/snapshot-c/test-1", newest)).To(BeTrue())
+		Expect(queue.Waiting()).To(BeEmpty())
+	})
+
+	It("promotes the tests of the same snapshot in the order in which they started waiting", func() {
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/snapshot-a/test-2", older)).To(BeTrue())
+
+		Expect(queue.TryAcquire("ns/snapshot-b/test-2", newer)).To(BeFalse())
+		advance(time.Second)
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeFalse())
+		Expect(queue.Waiting()).To(Equal([]string{"ns/snapshot-b/test-2", "ns/snapshot-b/test-1"}))
+	})
+
+	It("drops the waiting tests which stopped retrying so they don't block the queue", func() {
+		Expect(queue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/snapshot-a/test-2", older)).To(BeTrue())
+		Expect(queue.TryAcquire("ns/deleted-snapshot/test-1", older)).To(BeFalse())
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeFalse())
+
+		queue.Release("ns/snapshot-a/test-1")
+		advance(WaitingTestExpiration + time.Second)
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeTrue())
+		Expect(queue.Waiting()).To(BeEmpty())
+	})
+
+	It("accounts for the tracked running tests", func() {
+		queue.Track("ns/snapshot-a/test-1")
+		queue.Track("ns/snapshot-a/test-2")
+		queue.Track("ns/snapshot-a/test-3")
+		Expect(queue.Running()).To(Equal(3))
+
+		queue.Release("ns/snapshot-a/test-1")
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeFalse())
+		queue.Release("ns/snapshot-a/test-2")
+		Expect(queue.TryAcquire("ns/snapshot-b/test-1", newer)).To(BeTrue())
+	})
+
+	It("doesn't limit anything when the queue is nil", func() {
+		var nilQueue *Queue
+		Expect(nilQueue.TryAcquire("ns/snapshot-a/test-1", older)).To(BeTrue())
+		nilQueue.Track("ns/snapshot-a/test-1")
+		nilQueue.Release("ns/snapshot-a/test-1")
+		Expect(nilQueue.Running()).To(BeZero())
+		Expect(nilQueue.Waiting()).To(BeEmpty())
+	})
+
+	It("builds the key of the test", func() {
+		Expect(TestKey("ns", "snapshot-a", "test-1")).To(Equal("ns/snapshot-a/test-1"))
+	})
+})
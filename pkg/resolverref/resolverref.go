@@ -0,0 +1,300 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolverref checks that the pipelines referenced by the resolver references of IntegrationTestScenarios
+// can be resolved, without running the Tekton resolvers themselves
+package resolverref
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReasonResolved is the reason of the check result when the referenced pipeline was found
+	ReasonResolved = "Resolved"
+
+	// ReasonNotFound is the reason of the check result when the referenced pipeline doesn't exist
+	ReasonNotFound = "NotFound"
+
+	// ReasonInvalidReference is the reason of the check result when the resolver reference is malformed
+	ReasonInvalidReference = "InvalidReference"
+
+	// ReasonUnsupported is the reason of the check result when the resolver reference can't be checked
+	ReasonUnsupported = "Unsupported"
+
+	// ReasonUnavailable is the reason of the check result when the referenced pipeline couldn't be reached,
+	// e.g. due to network failures or missing credentials
+	ReasonUnavailable = "Unavailable"
+
+	// GitHubRawBaseURL is the endpoint serving the raw files of github.com repositories
+	GitHubRawBaseURL = "https://raw.githubusercontent.com"
+
+	// checkRequestTimeout limits the duration of a single request of the check
+	checkRequestTimeout = 30 * time.Second
+)
+
+// manifestMediaTypes are the accepted media types of the manifests of Tekton bundles
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// Result is the outcome of the check of a resolver reference
+type Result struct {
+	// Status is True when the pipeline was found, False when it doesn't exist or the reference is invalid
+	// and Unknown when the check couldn't be completed
+	Status  metav1.ConditionStatus
+	Reason  string
+	Message string
+}
+
+// Checker checks that the pipelines referenced by resolver references can be resolved
+type Checker interface {
+	// Check returns the result of the check of the resolver reference
+	Check(ctx context.Context, resolverRef v1beta2.ResolverRef) Result
+}
+
+// HTTPChecker checks the git resolver references by fetching the raw file from the git forge
+// and the bundle resolver references by requesting the manifest from the container registry
+type HTTPChecker struct {
+	httpClient       *http.Client
+	gitHubRawBaseURL string
+}
+
+// check if interface has been correctly implemented
+var _ Checker = (*HTTPChecker)(nil)
+
+// HTTPCheckerOption is used to extend HTTPChecker with optional parameters.
+type HTTPCheckerOption = func(c *HTTPChecker)
+
+// WithHTTPClient sets the HTTP client used for the requests of the checks
+func WithHTTPClient(httpClient *http.Client) HTTPCheckerOption {
+	return func(c *HTTPChecker) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithGitHubRawBaseURL overrides the endpoint serving the raw files of github.com repositories
+func WithGitHubRawBaseURL(baseURL string) HTTPCheckerOption {
+	return func(c *HTTPChecker) {
+		c.gitHubRawBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// NewHTTPChecker returns a new HTTPChecker
+func NewHTTPChecker(opts ...HTTPCheckerOption) *HTTPChecker {
+	checker := &HTTPChecker{
+		httpClient:       &http.Client{Timeout: checkRequestTimeout},
+		gitHubRawBaseURL: GitHubRawBaseURL,
+	}
+	for _, opt := range opts {
+		opt(checker)
+	}
+	return checker
+}
+
+// Check returns the result of the check of the git or bundle resolver reference, references of other resolvers
+// aren't checked and yield the Unknown status
+func (c *HTTPChecker) Check(ctx context.Context, resolverRef v1beta2.ResolverRef) Result {
+	params := map[string]string{}
+	for _, param := range resolverRef.Params {
+		params[param.Name] = param.Value
+	}
+
+	switch resolverRef.Resolver {
+	case "git":
+		return c.checkGit(ctx, params)
+	case "bundles":
+		return c.checkBundle(ctx, params)
+	default:
+		return unknown(ReasonUnsupported, fmt.Sprintf("references of the %q resolver can't be checked", resolverRef.Resolver))
+	}
+}
+
+// checkGit fetches the raw file of the pipeline from the git forge, github.com repositories are fetched
+// from the GitHub raw endpoint and other repositories from the GitLab compatible "/-/raw/" endpoint
+func (c *HTTPChecker) checkGit(ctx context.Context, params map[string]string) Result {
+	repoURL, pathInRepo := params["url"], strings.TrimPrefix(params["pathInRepo"], "/")
+	if repoURL == "" {
+		return unknown(ReasonUnsupported, "only git resolver references with the url param can be checked")
+	}
+	if pathInRepo == "" {
+		return invalid("the git resolver reference doesn't define the pathInRepo param")
+	}
+	revision := params["revision"]
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil || parsedURL.Host == "" || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return invalid(fmt.Sprintf("the git repository url %q is invalid", repoURL))
+	}
+	repoPath := strings.TrimSuffix(strings.TrimSuffix(parsedURL.Path, "/"), ".git")
+
+	var fileURL string
+	if parsedURL.Host == "github.com" {
+		fileURL = fmt.Sprintf("%s%s/%s/%s", c.gitHubRawBaseURL, repoPath, revision, pathInRepo)
+	} else {
+		fileURL = fmt.Sprintf("%s://%s%s/-/raw/%s/%s", parsedURL.Scheme, parsedURL.Host, repoPath, revision, pathInRepo)
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return unknown(ReasonUnavailable, fmt.Sprintf("failed to fetch %s from %s: %s", pathInRepo, repoURL, err))
+	}
+	defer resp.Body.Close()
+
+	target := fmt.Sprintf("file %s in revision %s of %s", pathInRepo, revision, repoURL)
+	return resultFromStatusCode(resp.StatusCode, target)
+}
+
+// checkBundle requests the manifest of the bundle from the container registry, anonymous bearer tokens
+// are requested when the registry asks for them
+func (c *HTTPChecker) checkBundle(ctx context.Context, params map[string]string) Result {
+	bundle := params["bundle"]
+	if bundle == "" {
+		return invalid("the bundles resolver reference doesn't define the bundle param")
+	}
+	ref, err := name.ParseReference(bundle)
+	if err != nil {
+		return invalid(fmt.Sprintf("the bundle reference %q is invalid: %s", bundle, err))
+	}
+	repository := ref.Context()
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s",
+		repository.Registry.Scheme(), repository.RegistryStr(), repository.RepositoryStr(), ref.Identifier())
+	header := http.Header{"Accept": {strings.Join(manifestMediaTypes, ", ")}}
+
+	resp, err := c.send(ctx, http.MethodHead, manifestURL, header)
+	if err != nil {
+		return unknown(ReasonUnavailable, fmt.Sprintf("failed to fetch the manifest of bundle %s: %s", bundle, err))
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.fetchAnonymousToken(ctx, resp.Header.Get("WWW-Authenticate"), repository)
+		if err != nil {
+			return unknown(ReasonUnavailable, fmt.Sprintf("failed to authorize to the registry of bundle %s: %s", bundle, err))
+		}
+		header.Set("Authorization", "Bearer "+token)
+		resp, err = c.send(ctx, http.MethodHead, manifestURL, header)
+		if err != nil {
+			return unknown(ReasonUnavailable, fmt.Sprintf("failed to fetch the manifest of bundle %s: %s", bundle, err))
+		}
+		resp.Body.Close()
+	}
+
+	return resultFromStatusCode(resp.StatusCode, fmt.Sprintf("bundle %s", bundle))
+}
+
+// fetchAnonymousToken fetches the bearer token allowing to pull from the repository without credentials
+func (c *HTTPChecker) fetchAnonymousToken(ctx context.Context, challenge string, repository name.Repository) (string, error) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	if !strings.EqualFold(scheme, "bearer") {
+		return "", fmt.Errorf("the registry requires %q authentication", scheme)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(rest, ",") {
+		if key, value, found := strings.Cut(strings.TrimSpace(param), "="); found {
+			params[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("invalid realm of the registry token service: %q", params["realm"])
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", repository.Scope("pull"))
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.send(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the registry token service responded with status code %d", resp.StatusCode)
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode the registry token response: %w", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("the registry token service didn't return a token")
+}
+
+// send sends a single request of the check
+func (c *HTTPChecker) send(ctx context.Context, method, requestURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return c.httpClient.Do(req)
+}
+
+// resultFromStatusCode returns the result of the check according to the status code of the response
+// fetching the target, the status is only False when the target definitely doesn't exist
+func resultFromStatusCode(statusCode int, target string) Result {
+	switch {
+	case statusCode == http.StatusOK:
+		return Result{Status: metav1.ConditionTrue, Reason: ReasonResolved, Message: fmt.Sprintf("The %s was resolved.", target)}
+	case statusCode == http.StatusNotFound:
+		return Result{Status: metav1.ConditionFalse, Reason: ReasonNotFound, Message: fmt.Sprintf("The %s was not found.", target)}
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return unknown(ReasonUnavailable, fmt.Sprintf("The %s can't be checked without credentials, status code %d.", target, statusCode))
+	default:
+		return unknown(ReasonUnavailable, fmt.Sprintf("The %s couldn't be fetched, status code %d.", target, statusCode))
+	}
+}
+
+// invalid returns the False result for malformed resolver references
+func invalid(message string) Result {
+	return Result{Status: metav1.ConditionFalse, Reason: ReasonInvalidReference, Message: message}
+}
+
+// unknown returns the Unknown result for the checks which couldn't be completed
+func unknown(reason, message string) Result {
+	return Result{Status: metav1.ConditionUnknown, Reason: reason, Message: message}
+}
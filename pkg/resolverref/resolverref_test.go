@@ -0,0 +1,226 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolverref_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/pkg/resolverref"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("HTTPChecker", func() {
+
+	newResolverRef := func(resolver string, params map[string]string) v1beta2.ResolverRef {
+		resolverRef := v1beta2.ResolverRef{Resolver: resolver}
+		for key, value := range params {
+			resolverRef.Params = append(resolverRef.Params, v1beta2.ResolverParameter{Name: key, Value: value})
+		}
+		return resolverRef
+	}
+
+	Context("git resolver references", func() {
+		var (
+			server        *httptest.Server
+			requestedPath string
+		)
+
+		BeforeEach(func() {
+			requestedPath = ""
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestedPath = r.URL.Path
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/pipelines/integration.yaml"):
+					fmt.Fprint(w, "kind: Pipeline")
+				case strings.HasSuffix(r.URL.Path, "/private.yaml"):
+					w.WriteHeader(http.StatusForbidden)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("resolves the pipeline of a github.com repository from the raw endpoint", func() {
+			checker := resolverref.NewHTTPChecker(resolverref.WithGitHubRawBaseURL(server.URL))
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        "https://github.com/konflux-ci/integration-examples.git",
+				"revision":   "main",
+				"pathInRepo": "pipelines/integration.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionTrue))
+			Expect(result.Reason).To(Equal(resolverref.ReasonResolved))
+			Expect(requestedPath).To(Equal("/konflux-ci/integration-examples/main/pipelines/integration.yaml"))
+		})
+
+		It("reports the pipeline missing from the repository", func() {
+			checker := resolverref.NewHTTPChecker(resolverref.WithGitHubRawBaseURL(server.URL))
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        "https://github.com/konflux-ci/integration-examples",
+				"pathInRepo": "pipelines/integraton.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionFalse))
+			Expect(result.Reason).To(Equal(resolverref.ReasonNotFound))
+			Expect(result.Message).To(ContainSubstring("pipelines/integraton.yaml in revision HEAD"))
+		})
+
+		It("resolves the pipeline of other forges from the GitLab compatible raw endpoint", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        server.URL + "/group/project.git",
+				"revision":   "v1",
+				"pathInRepo": "/pipelines/integration.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionTrue))
+			Expect(requestedPath).To(Equal("/group/project/-/raw/v1/pipelines/integration.yaml"))
+		})
+
+		It("can't decide whether private pipelines exist", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        server.URL + "/group/project",
+				"pathInRepo": "private.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(result.Reason).To(Equal(resolverref.ReasonUnavailable))
+		})
+
+		It("can't decide whether the pipeline exists when the forge is unreachable", func() {
+			unreachableURL := server.URL
+			server.Close()
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        unreachableURL + "/group/project",
+				"pathInRepo": "pipelines/integration.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(result.Reason).To(Equal(resolverref.ReasonUnavailable))
+		})
+
+		It("rejects malformed git resolver references", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url": server.URL + "/group/project",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionFalse))
+			Expect(result.Reason).To(Equal(resolverref.ReasonInvalidReference))
+
+			result = checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"url":        "git@github.com:konflux-ci/integration-examples.git",
+				"pathInRepo": "pipelines/integration.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionFalse))
+			Expect(result.Reason).To(Equal(resolverref.ReasonInvalidReference))
+		})
+
+		It("doesn't check git resolver references using the SCM API", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("git", map[string]string{
+				"org":        "konflux-ci",
+				"repo":       "integration-examples",
+				"pathInRepo": "pipelines/integration.yaml",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(result.Reason).To(Equal(resolverref.ReasonUnsupported))
+		})
+	})
+
+	Context("bundles resolver references", func() {
+		var (
+			registry      *httptest.Server
+			registryHost  string
+			tokenRequests int
+		)
+
+		BeforeEach(func() {
+			tokenRequests = 0
+			registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					tokenRequests++
+					Expect(r.URL.Query().Get("scope")).To(Equal("repository:org/pipelines:pull"))
+					fmt.Fprint(w, `{"token": "anonymous"}`)
+					return
+				}
+				if r.Header.Get("Authorization") != "Bearer anonymous" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry"`, r.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				Expect(r.Method).To(Equal(http.MethodHead))
+				Expect(r.Header.Get("Accept")).To(ContainSubstring("application/vnd.oci.image.manifest.v1+json"))
+				if r.URL.Path == "/v2/org/pipelines/manifests/v1" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			registryHost = strings.TrimPrefix(registry.URL, "http://")
+		})
+
+		AfterEach(func() {
+			registry.Close()
+		})
+
+		It("resolves the bundle using an anonymous token", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("bundles", map[string]string{
+				"bundle": registryHost + "/org/pipelines:v1",
+				"name":   "integration",
+				"kind":   "pipeline",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionTrue))
+			Expect(result.Reason).To(Equal(resolverref.ReasonResolved))
+			Expect(tokenRequests).To(Equal(1))
+		})
+
+		It("reports the missing bundle", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("bundles", map[string]string{
+				"bundle": registryHost + "/org/pipelines:v2",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionFalse))
+			Expect(result.Reason).To(Equal(resolverref.ReasonNotFound))
+			Expect(result.Message).To(ContainSubstring("org/pipelines:v2"))
+		})
+
+		It("rejects malformed bundle references", func() {
+			checker := resolverref.NewHTTPChecker()
+			result := checker.Check(context.Background(), newResolverRef("bundles", map[string]string{
+				"bundle": "Not A Bundle",
+			}))
+			Expect(result.Status).To(Equal(metav1.ConditionFalse))
+			Expect(result.Reason).To(Equal(resolverref.ReasonInvalidReference))
+		})
+	})
+
+	It("doesn't check the references of other resolvers", func() {
+		checker := resolverref.NewHTTPChecker()
+		result := checker.Check(context.Background(), newResolverRef("cluster", map[string]string{"name": "integration"}))
+		Expect(result.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(result.Reason).To(Equal(resolverref.ReasonUnsupported))
+	})
+})
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule parses the standard cron expressions used to schedule the periodic runs
+// of IntegrationTestScenarios
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for the next activation, expressions like "0 0 30 2 *" never activate
+const maxSearchYears = 5
+
+// descriptors are the predefined schedules which can be used instead of the five cron fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the bounds and the accepted names of the values of a cron field
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// Schedule is a parsed cron expression. The expression is evaluated in UTC.
+type Schedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// daysOfMonthRestricted and daysOfWeekRestricted are true when the field isn't "*", a day matches
+	// the schedule if it matches any of the restricted day fields
+	daysOfMonthRestricted, daysOfWeekRestricted bool
+}
+
+// Parse parses the given cron expression. The expression either consists of the five standard fields
+// (minute, hour, day of month, month and day of week) or is one of the descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly. The fields support lists, ranges, steps and,
+// for months and days of week, three letter names.
+func Parse(expression string) (*Schedule, error) {
+	spec := strings.TrimSpace(expression)
+	if descriptor, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q, found %d", expression, len(fields))
+	}
+
+	schedule := &Schedule{
+		daysOfMonthRestricted: fields[2] != "*",
+		daysOfWeekRestricted:  fields[4] != "*",
+	}
+	var err error
+	if schedule.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// both 0 and 7 stand for Sunday
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+// Next returns the first activation time of the schedule strictly after the given time,
+// the zero time is returned if the schedule doesn't activate within the next years
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchesDay returns true if the day of the given time matches the day of month and day of week fields
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.daysOfMonthRestricted && s.daysOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+// parse returns the bitset of the values matched by the given comma separated list of the field
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		itemBits, err := f.parseItem(item)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", f.name, value, err)
+		}
+		bits |= itemBits
+	}
+	return bits, nil
+}
+
+// parseItem returns the bitset of the values matched by a single "*", value or range of the field,
+// optionally followed by a step
+func (f field) parseItem(item string) (uint64, error) {
+	rangeSpec, stepSpec, hasStep := strings.Cut(item, "/")
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepSpec)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q", stepSpec)
+		}
+	}
+
+	var start, end int
+	switch startSpec, endSpec, isRange := strings.Cut(rangeSpec, "-"); {
+	case rangeSpec == "*":
+		start, end = f.min, f.max
+	case isRange:
+		var err error
+		if start, err = f.parseValue(startSpec); err != nil {
+			return 0, err
+		}
+		if end, err = f.parseValue(endSpec); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("range start %d is greater than its end %d", start, end)
+		}
+	default:
+		var err error
+		if start, err = f.parseValue(rangeSpec); err != nil {
+			return 0, err
+		}
+		end = start
+		// a single value with a step, e.g. "5/15", stands for the range from the value to the field maximum
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+// parseValue parses a single number or name of the field and checks its bounds
+func (f field) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			return i + f.min, nil
+		}
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if number < f.min || number > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", number, f.min, f.max)
+	}
+	return number, nil
+}
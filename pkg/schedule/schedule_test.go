@@ -0,0 +1,78 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule_test
+
+import (
+	"time"
+
+	"github.com/konflux-ci/integration-service/pkg/schedule"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	// Friday
+	from := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC)
+
+	DescribeTable("finds the next activation of the schedule",
+		func(expression string, expected time.Time) {
+			parsed, err := schedule.Parse(expression)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Next(from)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)),
+		Entry("nightly", "0 2 * * *", time.Date(2024, time.March, 16, 2, 0, 0, 0, time.UTC)),
+		Entry("daily descriptor", "@daily", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("hourly descriptor", "@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)),
+		Entry("steps", "*/20 * * * *", time.Date(2024, time.March, 15, 10, 40, 0, 0, time.UTC)),
+		Entry("value with step", "5/20 * * * *", time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)),
+		Entry("lists and ranges", "0 8-9,18 * * *", time.Date(2024, time.March, 15, 18, 0, 0, 0, time.UTC)),
+		Entry("weekdays by name", "0 3 * * mon-fri", time.Date(2024, time.March, 18, 3, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 3 * * 7", time.Date(2024, time.March, 17, 3, 0, 0, 0, time.UTC)),
+		Entry("month by name", "0 0 1 jun *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 20 * sat", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)),
+	)
+
+	It("finds the activation strictly after the given time", func() {
+		parsed, err := schedule.Parse("0 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		activation := time.Date(2024, time.March, 16, 2, 0, 0, 0, time.UTC)
+		Expect(parsed.Next(activation)).To(Equal(activation.AddDate(0, 0, 1)))
+	})
+
+	It("returns the zero time when the schedule never activates", func() {
+		parsed, err := schedule.Parse("0 0 30 2 *")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Next(from).IsZero()).To(BeTrue())
+	})
+
+	DescribeTable("fails to parse invalid expressions",
+		func(expression, expectedError string) {
+			parsed, err := schedule.Parse(expression)
+			Expect(parsed).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("empty", "", "expected 5 fields"),
+		Entry("too many fields", "0 0 * * * *", "expected 5 fields"),
+		Entry("unknown descriptor", "@nightly", "expected 5 fields"),
+		Entry("out of range", "60 * * * *", "value 60 out of range [0, 59]"),
+		Entry("invalid value", "0 noon * * *", `invalid hour "noon"`),
+		Entry("reversed range", "0 0 * * fri-mon", "range start 5 is greater than its end 1"),
+		Entry("invalid step", "*/0 * * * *", `invalid step "0"`),
+	)
+})
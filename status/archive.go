@@ -0,0 +1,296 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/konflux-ci/operator-toolkit/metadata"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+)
+
+const (
+	// TestResultsArchiveAnnotation enables archiving of the final test reports of Snapshots as OCI artifacts
+	// when set to "true" on the Application
+	TestResultsArchiveAnnotation = "test.appstudio.openshift.io/archive-test-results"
+
+	// TestResultsArtifactType is the artifact type of the archived test reports
+	TestResultsArtifactType = "application/vnd.konflux-ci.integration-test-results.v1+json"
+
+	// TestResultsTagSuffix is the suffix of the tag of the archive, the tag is derived from the digest of the tested image
+	TestResultsTagSuffix = ".test-results"
+
+	// PushSecretServiceAccountEnvVar contains the name of the service account of the namespaces linked with
+	// the secrets allowing to push images, DefaultPushSecretServiceAccountName is used when it's not set
+	PushSecretServiceAccountEnvVar = "TEST_RESULTS_PUSH_SERVICE_ACCOUNT"
+
+	// DefaultPushSecretServiceAccountName is the default service account of the namespace linked with the secrets allowing to push images
+	DefaultPushSecretServiceAccountName = "appstudio-pipeline"
+)
+
+// TestResultsArchive is the content of the archived test reports
+type TestResultsArchive struct {
+	Snapshot    string                              `json:"snapshot"`
+	Namespace   string                              `json:"namespace"`
+	Application string                              `json:"application"`
+	Spec        applicationapiv1alpha1.SnapshotSpec `json:"spec"`
+	Reports     []TestReport                        `json:"reports"`
+}
+
+// dockerConfigJSON is the content of the kubernetes.io/dockerconfigjson secrets
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+}
+
+// IsTestResultsArchiveEnabled returns true if the final test reports of the Snapshots of the Application should be archived
+func IsTestResultsArchiveEnabled(application *applicationapiv1alpha1.Application) bool {
+	return application != nil && application.GetAnnotations()[TestResultsArchiveAnnotation] == "true"
+}
+
+// TestResultsArchiver archives the final test reports of Snapshots as OCI artifacts next to the tested images
+type TestResultsArchiver struct {
+	logger             logr.Logger
+	client             client.Client
+	registryClient     RegistryClient
+	serviceAccountName string
+}
+
+// TestResultsArchiverOption is used to extend TestResultsArchiver with optional parameters.
+type TestResultsArchiverOption = func(a *TestResultsArchiver)
+
+// WithRegistryClient sets the client used to push the archives to the container registry
+func WithRegistryClient(registryClient RegistryClient) TestResultsArchiverOption {
+	return func(a *TestResultsArchiver) {
+		a.registryClient = registryClient
+	}
+}
+
+// WithPushServiceAccountName sets the service account of the namespace linked with the secrets allowing to push images
+func WithPushServiceAccountName(serviceAccountName string) TestResultsArchiverOption {
+	return func(a *TestResultsArchiver) {
+		a.serviceAccountName = serviceAccountName
+	}
+}
+
+func NewTestResultsArchiver(logger logr.Logger, client client.Client, opts ...TestResultsArchiverOption) *TestResultsArchiver {
+	archiver := TestResultsArchiver{
+		logger:             logger,
+		client:             client,
+		registryClient:     NewRegistryClient(),
+		serviceAccountName: getPushServiceAccountName(),
+	}
+
+	for _, opt := range opts {
+		opt(&archiver)
+	}
+
+	return &archiver
+}
+
+// Archive pushes the final test reports of all scenarios together with the spec of the snapshot to the repository
+// of each tested component image under the sha256-<digest>.test-results tag and records the digest of the archive
+// in the snapshot annotation. Snapshots which were already archived or which have unfinished tests are skipped.
+// *RegistryAuthError is returned when the registry rejects the push secret of the namespace.
+func (a *TestResultsArchiver) Archive(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	if gitops.HasTestResultsArtifact(snapshot) {
+		return nil
+	}
+
+	content, err := a.generateArchive(ctx, snapshot)
+	if err != nil || content == nil {
+		return err
+	}
+
+	targets := getArchiveTargets(snapshot)
+	if len(targets) == 0 {
+		a.logger.Info("Snapshot contains no component images referenced by digest, skipping archive of test results",
+			"snapshot.Name", snapshot.Name)
+		return gitops.SetTestResultsArtifact(ctx, a.client, snapshot, gitops.TestResultsNotArchived)
+	}
+
+	artifact := &OCIArtifact{
+		ArtifactType:   TestResultsArtifactType,
+		LayerMediaType: "application/json",
+		LayerTitle:     "test-results.json",
+		Layer:          content,
+		Annotations: map[string]string{
+			"org.opencontainers.image.title": fmt.Sprintf("Integration test results of snapshot %s", snapshot.Name),
+		},
+	}
+
+	var errs error
+	artifactDigest := ""
+	for _, image := range targets {
+		tag := image.Context().Tag(strings.Replace(image.DigestStr(), ":", "-", 1) + TestResultsTagSuffix)
+		credentials, err := a.getPushCredentials(ctx, snapshot.Namespace, tag.Context().Name())
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		digest, err := a.registryClient.PushArtifact(ctx, tag, credentials, artifact)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to push test results to %s: %w", tag.Name(), err))
+			continue
+		}
+		a.logger.Info("Pushed test results of the snapshot", "snapshot.Name", snapshot.Name, "tag", tag.Name(), "digest", digest)
+		artifactDigest = digest
+	}
+	if errs != nil {
+		return errs
+	}
+
+	return gitops.SetTestResultsArtifact(ctx, a.client, snapshot, artifactDigest)
+}
+
+// generateArchive returns the JSON of the archive, nil is returned when not all tests of the snapshot finished
+func (a *TestResultsArchiver) generateArchive(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) ([]byte, error) {
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test statuses of snapshot %s: %w", snapshot.Name, err)
+	}
+
+	st := NewStatus(a.logger, a.client)
+	reports := []TestReport{}
+	for _, detail := range testStatuses.GetStatuses() {
+		if !detail.Status.IsFinal() {
+			a.logger.Info("Not all tests of the snapshot finished, skipping archive of test results",
+				"snapshot.Name", snapshot.Name, "scenario.Name", detail.ScenarioName, "status", detail.Status)
+			return nil, nil
+		}
+		report, err := st.generateTestReport(ctx, *detail, snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate test report for scenario %s: %w", detail.ScenarioName, err)
+		}
+		reports = append(reports, *report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ScenarioName < reports[j].ScenarioName
+	})
+
+	content, err := json.Marshal(TestResultsArchive{
+		Snapshot:    snapshot.Name,
+		Namespace:   snapshot.Namespace,
+		Application: snapshot.Spec.Application,
+		Spec:        snapshot.Spec,
+		Reports:     reports,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal test results of snapshot %s: %w", snapshot.Name, err)
+	}
+	return content, nil
+}
+
+// getArchiveTargets returns the images next to which the archive is pushed, only the image of the component
+// is used for component snapshots, images which aren't referenced by digest are skipped
+func getArchiveTargets(snapshot *applicationapiv1alpha1.Snapshot) []name.Digest {
+	componentName := ""
+	if metadata.HasLabelWithValue(snapshot, gitops.SnapshotTypeLabel, gitops.SnapshotComponentType) {
+		componentName = snapshot.GetLabels()[gitops.SnapshotComponentLabel]
+	}
+
+	targets := []name.Digest{}
+	for _, component := range snapshot.Spec.Components {
+		if componentName != "" && component.Name != componentName {
+			continue
+		}
+		image, err := name.NewDigest(component.ContainerImage)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, image)
+	}
+	return targets
+}
+
+// getPushServiceAccountName returns the name of the push service account set by the TEST_RESULTS_PUSH_SERVICE_ACCOUNT
+// environment variable, or the default one
+func getPushServiceAccountName() string {
+	if serviceAccountName := strings.TrimSpace(os.Getenv(PushSecretServiceAccountEnvVar)); serviceAccountName != "" {
+		return serviceAccountName
+	}
+	return DefaultPushSecretServiceAccountName
+}
+
+// getPushCredentials returns the credentials for the repository from the docker config secrets linked with
+// the push service account of the namespace, the most specific matching entry is used. No credentials
+// are returned when none of the secrets matches the repository.
+func (a *TestResultsArchiver) getPushCredentials(ctx context.Context, namespace, repository string) (*RegistryCredentials, error) {
+	serviceAccount := &v1.ServiceAccount{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: a.serviceAccountName}, serviceAccount); err != nil {
+		return nil, fmt.Errorf("failed to get service account %s/%s: %w", namespace, a.serviceAccountName, err)
+	}
+
+	secretNames := []string{}
+	for _, secret := range serviceAccount.Secrets {
+		secretNames = append(secretNames, secret.Name)
+	}
+	for _, secret := range serviceAccount.ImagePullSecrets {
+		secretNames = append(secretNames, secret.Name)
+	}
+
+	var credentials *RegistryCredentials
+	longestMatch := 0
+	for _, secretName := range secretNames {
+		secret := &v1.Secret{}
+		if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+			a.logger.Info("failed to get secret linked with the push service account, skipping it",
+				"secret.Name", secretName, "error", err.Error())
+			continue
+		}
+		if secret.Type != v1.SecretTypeDockerConfigJson {
+			continue
+		}
+
+		config := dockerConfigJSON{}
+		if err := json.Unmarshal(secret.Data[v1.DockerConfigJsonKey], &config); err != nil {
+			a.logger.Info("failed to parse docker config of the secret, skipping it", "secret.Name", secretName, "error", err.Error())
+			continue
+		}
+		for registry, auth := range config.Auths {
+			registry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://"), "/")
+			if (registry != repository && !strings.HasPrefix(repository, registry+"/")) || len(registry) <= longestMatch {
+				continue
+			}
+			username, password := auth.Username, auth.Password
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil && len(decoded) > 0 {
+				username, password, _ = strings.Cut(string(decoded), ":")
+			}
+			credentials = &RegistryCredentials{Username: username, Password: password}
+			longestMatch = len(registry)
+		}
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+// fakeRegistryClient records the pushed artifacts instead of pushing them
+type fakeRegistryClient struct {
+	tags        []string
+	credentials []*status.RegistryCredentials
+	artifacts   []*status.OCIArtifact
+	err         error
+}
+
+func (c *fakeRegistryClient) PushArtifact(ctx context.Context, tag name.Tag, credentials *status.RegistryCredentials, artifact *status.OCIArtifact) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	c.tags = append(c.tags, tag.Name())
+	c.credentials = append(c.credentials, credentials)
+	c.artifacts = append(c.artifacts, artifact)
+	return "sha256:0000000000000000000000000000000000000000000000000000000000000001", nil
+}
+
+var _ = Describe("TestResultsArchiver", func() {
+
+	const imageDigest = "sha256:841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40bc9ff"
+
+	var (
+		hasSnapshot        *applicationapiv1alpha1.Snapshot
+		mockK8sClient      *MockK8sClient
+		registryClient     *fakeRegistryClient
+		archiver           *status.TestResultsArchiver
+		patched            int
+		serviceAccountName string
+	)
+
+	dockerConfig := func(auths map[string]string) []byte {
+		config := map[string]map[string]map[string]string{"auths": {}}
+		for registry, credentials := range auths {
+			config["auths"][registry] = map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(credentials))}
+		}
+		data, _ := json.Marshal(config)
+		return data
+	}
+
+	BeforeEach(func() {
+		patched = 0
+		serviceAccountName = status.DefaultPushSecretServiceAccountName
+		hasSnapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot-sample",
+				Namespace: "default",
+				Labels: map[string]string{
+					gitops.SnapshotTypeLabel:      gitops.SnapshotComponentType,
+					gitops.SnapshotComponentLabel: "component-sample",
+				},
+				Annotations: map[string]string{
+					gitops.SnapshotTestsStatusAnnotation: `[{"scenario":"scenario2","status":"TestInvalid","lastUpdateTime":"2023-07-26T17:57:50+02:00","details":"invalid"},` +
+						`{"scenario":"scenario1","status":"Deleted","lastUpdateTime":"2023-07-26T17:57:50+02:00","details":"deleted"}]`,
+				},
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{
+				Application: "application-sample",
+				Components: []applicationapiv1alpha1.SnapshotComponent{
+					{Name: "component-sample", ContainerImage: "quay.io/redhat-appstudio/sample-image@" + imageDigest},
+					{Name: "other-component", ContainerImage: "quay.io/redhat-appstudio/other-image@" + imageDigest},
+				},
+			},
+		}
+
+		mockK8sClient = &MockK8sClient{
+			getInterceptor: func(key client.ObjectKey, obj client.Object) {
+				switch o := obj.(type) {
+				case *v1.ServiceAccount:
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "default", Name: serviceAccountName}))
+					o.Secrets = []v1.ObjectReference{{Name: "quay-push"}, {Name: "opaque"}}
+					o.ImagePullSecrets = []v1.LocalObjectReference{{Name: "org-push"}}
+				case *v1.Secret:
+					switch key.Name {
+					case "quay-push":
+						o.Type = v1.SecretTypeDockerConfigJson
+						o.Data = map[string][]byte{v1.DockerConfigJsonKey: dockerConfig(map[string]string{"https://quay.io": "robot:generic"})}
+					case "org-push":
+						o.Type = v1.SecretTypeDockerConfigJson
+						o.Data = map[string][]byte{v1.DockerConfigJsonKey: dockerConfig(map[string]string{"quay.io/redhat-appstudio/sample-image": "robot:specific"})}
+					default:
+						o.Type = v1.SecretTypeOpaque
+					}
+				}
+			},
+			genericInterceptor: func(obj client.Object) {
+				patched++
+			},
+		}
+
+		registryClient = &fakeRegistryClient{}
+		archiver = status.NewTestResultsArchiver(logr.Discard(), mockK8sClient, status.WithRegistryClient(registryClient))
+	})
+
+	It("is enabled by the application annotation", func() {
+		Expect(status.IsTestResultsArchiveEnabled(nil)).To(BeFalse())
+		application := &applicationapiv1alpha1.Application{}
+		Expect(status.IsTestResultsArchiveEnabled(application)).To(BeFalse())
+		application.Annotations = map[string]string{status.TestResultsArchiveAnnotation: "true"}
+		Expect(status.IsTestResultsArchiveEnabled(application)).To(BeTrue())
+	})
+
+	It("pushes the test results next to the image of the component", func() {
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+
+		Expect(registryClient.tags).To(Equal([]string{
+			"quay.io/redhat-appstudio/sample-image:sha256-841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40bc9ff.test-results",
+		}))
+		Expect(registryClient.credentials).To(Equal([]*status.RegistryCredentials{{Username: "robot", Password: "specific"}}))
+
+		artifact := registryClient.artifacts[0]
+		Expect(artifact.ArtifactType).To(Equal(status.TestResultsArtifactType))
+		archive := status.TestResultsArchive{}
+		Expect(json.Unmarshal(artifact.Layer, &archive)).To(Succeed())
+		Expect(archive.Snapshot).To(Equal("snapshot-sample"))
+		Expect(archive.Application).To(Equal("application-sample"))
+		Expect(archive.Spec).To(Equal(hasSnapshot.Spec))
+		Expect(archive.Reports).To(HaveLen(2))
+		Expect(archive.Reports[0].ScenarioName).To(Equal("scenario1"))
+		Expect(archive.Reports[1].ScenarioName).To(Equal("scenario2"))
+
+		Expect(hasSnapshot.Annotations).To(HaveKeyWithValue(gitops.SnapshotTestResultsArtifactAnnotation,
+			"sha256:0000000000000000000000000000000000000000000000000000000000000001"))
+		Expect(patched).To(Equal(1))
+	})
+
+	It("pushes the test results next to all images of composite snapshots", func() {
+		hasSnapshot.Labels[gitops.SnapshotTypeLabel] = gitops.SnapshotCompositeType
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(HaveLen(2))
+		Expect(registryClient.credentials[1]).To(Equal(&status.RegistryCredentials{Username: "robot", Password: "generic"}))
+	})
+
+	It("doesn't archive the test results again", func() {
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(HaveLen(1))
+		Expect(patched).To(Equal(1))
+	})
+
+	It("doesn't archive the test results before all tests finished", func() {
+		hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = `[{"scenario":"scenario1","status":"InProgress","lastUpdateTime":"2023-07-26T17:57:50+02:00","details":"running"}]`
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(BeEmpty())
+		Expect(hasSnapshot.Annotations).NotTo(HaveKey(gitops.SnapshotTestResultsArtifactAnnotation))
+	})
+
+	It("uses the configured push service account", func() {
+		serviceAccountName = "build-pipeline-component-sample"
+		archiver = status.NewTestResultsArchiver(logr.Discard(), mockK8sClient, status.WithRegistryClient(registryClient),
+			status.WithPushServiceAccountName(serviceAccountName))
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(HaveLen(1))
+	})
+
+	It("uses the push service account set by the environment variable", func() {
+		serviceAccountName = "konflux-integration-runner"
+		GinkgoT().Setenv(status.PushSecretServiceAccountEnvVar, serviceAccountName)
+		archiver = status.NewTestResultsArchiver(logr.Discard(), mockK8sClient, status.WithRegistryClient(registryClient))
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(HaveLen(1))
+	})
+
+	It("records that the test results can't be archived when no image is referenced by digest", func() {
+		for i := range hasSnapshot.Spec.Components {
+			hasSnapshot.Spec.Components[i].ContainerImage = "quay.io/redhat-appstudio/sample-image:latest"
+		}
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(registryClient.tags).To(BeEmpty())
+		Expect(hasSnapshot.Annotations).To(HaveKeyWithValue(gitops.SnapshotTestResultsArtifactAnnotation, gitops.TestResultsNotArchived))
+		Expect(patched).To(Equal(1))
+
+		// the snapshot isn't processed again
+		Expect(archiver.Archive(context.TODO(), hasSnapshot)).To(Succeed())
+		Expect(patched).To(Equal(1))
+	})
+
+	It("returns the auth error without recording the artifact when the registry rejects the credentials", func() {
+		registryClient.err = &status.RegistryAuthError{Repository: "quay.io/redhat-appstudio/sample-image", StatusCode: http.StatusUnauthorized}
+		err := archiver.Archive(context.TODO(), hasSnapshot)
+		var authErr *status.RegistryAuthError
+		Expect(errors.As(err, &authErr)).To(BeTrue())
+		Expect(hasSnapshot.Annotations).NotTo(HaveKey(gitops.SnapshotTestResultsArtifactAnnotation))
+		Expect(patched).To(BeZero())
+	})
+})
+
+var _ = Describe("RegistryClient", func() {
+
+	var (
+		server    *httptest.Server
+		manifests map[string][]byte
+		blobs     map[string][]byte
+		tag       name.Tag
+		artifact  *status.OCIArtifact
+	)
+
+	BeforeEach(func() {
+		manifests = map[string][]byte{}
+		blobs = map[string][]byte{}
+		artifact = &status.OCIArtifact{
+			ArtifactType:   status.TestResultsArtifactType,
+			LayerMediaType: "application/json",
+			LayerTitle:     "test-results.json",
+			Layer:          []byte(`{"reports":[]}`),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// registryHandler serves the distribution API of the org/repo repository
+	registryHandler := func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && strings.HasPrefix(r.URL.Path, "/v2/org/repo/blobs/"):
+			if _, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/org/repo/blobs/")]; ok {
+				rw.WriteHeader(http.StatusOK)
+				return
+			}
+			rw.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/org/repo/blobs/uploads/":
+			rw.Header().Set("Location", "/v2/org/repo/blobs/uploads/upload-1?state=abc")
+			rw.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/org/repo/blobs/uploads/upload-1":
+			Expect(r.URL.Query().Get("state")).To(Equal("abc"))
+			blobs[r.URL.Query().Get("digest")], _ = io.ReadAll(r.Body)
+			rw.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v2/org/repo/manifests/"):
+			Expect(r.Header.Get("Content-Type")).To(Equal(status.OCIManifestMediaType))
+			manifests[strings.TrimPrefix(r.URL.Path, "/v2/org/repo/manifests/")], _ = io.ReadAll(r.Body)
+			rw.WriteHeader(http.StatusCreated)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}
+
+	It("pushes the artifact using basic authentication", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); !ok || username != "robot" || password != "secret" {
+				rw.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			registryHandler(rw, r)
+		}))
+		tag, _ = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/org/repo:sha256-abc.test-results")
+
+		digest, err := status.NewRegistryClient().PushArtifact(context.TODO(), tag, &status.RegistryCredentials{Username: "robot", Password: "secret"}, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests).To(HaveKey("sha256-abc.test-results"))
+		Expect(digest).To(HavePrefix("sha256:"))
+		Expect(blobs).To(HaveLen(2))
+		Expect(blobs).To(ContainElement([]byte(`{"reports":[]}`)))
+
+		manifest := map[string]any{}
+		Expect(json.Unmarshal(manifests["sha256-abc.test-results"], &manifest)).To(Succeed())
+		Expect(manifest).To(HaveKeyWithValue("artifactType", status.TestResultsArtifactType))
+		Expect(manifest["layers"]).To(HaveLen(1))
+
+		// pushing the same artifact again doesn't upload the blobs again and results in the same digest
+		blobs["keep"] = nil
+		sameDigest, err := status.NewRegistryClient().PushArtifact(context.TODO(), tag, &status.RegistryCredentials{Username: "robot", Password: "secret"}, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sameDigest).To(Equal(digest))
+		Expect(blobs).To(HaveLen(3))
+	})
+
+	It("pushes the artifact using a bearer token", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				Expect(r.URL.Query().Get("service")).To(Equal("registry"))
+				Expect(r.URL.Query().Get("scope")).To(Equal("repository:org/repo:pull,push"))
+				fmt.Fprint(rw, `{"token": "registry-token"}`)
+				return
+			}
+			if r.Header.Get("Authorization") != "Bearer registry-token" {
+				rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			registryHandler(rw, r)
+		}))
+		tag, _ = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/org/repo:sha256-abc.test-results")
+
+		_, err := status.NewRegistryClient().PushArtifact(context.TODO(), tag, nil, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests).To(HaveKey("sha256-abc.test-results"))
+	})
+
+	It("returns the auth error when the registry rejects the credentials", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			rw.WriteHeader(http.StatusUnauthorized)
+		}))
+		tag, _ = name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/org/repo:sha256-abc.test-results")
+
+		_, err := status.NewRegistryClient().PushArtifact(context.TODO(), tag, &status.RegistryCredentials{Username: "robot", Password: "wrong"}, artifact)
+		var authErr *status.RegistryAuthError
+		Expect(errors.As(err, &authErr)).To(BeTrue())
+		Expect(authErr.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(manifests).To(BeEmpty())
+	})
+})
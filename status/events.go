@@ -0,0 +1,372 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/metrics"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+)
+
+const (
+	// EventsSinkURLEnvVar contains the URL of the sink receiving CloudEvents about integration test lifecycle
+	// transitions, no events are emitted when it's not set
+	EventsSinkURLEnvVar = "INTEGRATION_EVENTS_SINK_URL"
+
+	// EventSource is the source of all CloudEvents emitted by integration service
+	EventSource = "/konflux-ci/integration-service"
+
+	// CDEvents types of the emitted events
+	TestCaseRunStartedEventType  = "dev.cdevents.testcaserun.started.0.1.1"
+	TestCaseRunFinishedEventType = "dev.cdevents.testcaserun.finished.0.1.1"
+
+	// cdEventsSpecVersion is the version of the CDEvents specification the events conform to
+	cdEventsSpecVersion = "0.4.1"
+
+	// defaultEventQueueSize is the number of events which can wait for delivery before new events are dropped
+	defaultEventQueueSize = 1000
+
+	// eventRequestTimeout limits the duration of delivering a single event to the sink
+	eventRequestTimeout = 10 * time.Second
+
+	// eventsReporterName is the name under which the emitted transitions are tracked in the report status of the
+	// Snapshot, next to the ones reported by the secondary reporters
+	eventsReporterName = "events"
+)
+
+// CloudEvent is a CloudEvent in the structured JSON format
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            CDEvent   `json:"data"`
+}
+
+// CDEvent is the CDEvents payload of the emitted CloudEvents
+type CDEvent struct {
+	Context               CDEventContext    `json:"context"`
+	Subject               CDEventSubject    `json:"subject"`
+	CustomData            TestCaseRunDetail `json:"customData"`
+	CustomDataContentType string            `json:"customDataContentType"`
+}
+
+// CDEventContext is the context of the CDEvent, it duplicates the attributes of the CloudEvent envelope
+type CDEventContext struct {
+	Version   string    `json:"version"`
+	ID        string    `json:"id"`
+	Source    string    `json:"source"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CDEventSubject is the testCaseRun subject of the CDEvent
+type CDEventSubject struct {
+	ID      string             `json:"id"`
+	Source  string             `json:"source"`
+	Type    string             `json:"type"`
+	Content TestCaseRunContent `json:"content"`
+}
+
+// TestCaseRunContent is the content of the testCaseRun subject
+type TestCaseRunContent struct {
+	Outcome  string           `json:"outcome,omitempty"`
+	TestCase TestCaseIdentity `json:"testCase"`
+}
+
+// TestCaseIdentity identifies the test case, i.e. the integration test scenario
+type TestCaseIdentity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestCaseRunDetail contains the integration service specific details of the test run
+type TestCaseRunDetail struct {
+	Namespace   string `json:"namespace"`
+	Application string `json:"application"`
+	Component   string `json:"component,omitempty"`
+	Snapshot    string `json:"snapshot"`
+	Scenario    string `json:"scenario"`
+	Status      string `json:"status"`
+	PipelineRun string `json:"pipelineRun,omitempty"`
+	PipelineURL string `json:"pipelineURL,omitempty"`
+}
+
+// EventEmitter emits CloudEvents about integration test lifecycle transitions to a sink. Events are queued
+// and delivered in the background so emitting never blocks the reconciliation, events which don't fit
+// into the queue are dropped and counted. A nil EventEmitter is valid and doesn't emit anything.
+type EventEmitter struct {
+	logger     logr.Logger
+	sinkURL    string
+	httpClient *http.Client
+	queue      chan *CloudEvent
+	dropped    atomic.Int64
+}
+
+// EventEmitterOption is used to extend EventEmitter with optional parameters.
+type EventEmitterOption = func(e *EventEmitter)
+
+// WithEventQueueSize sets the number of events which can wait for delivery
+func WithEventQueueSize(size int) EventEmitterOption {
+	return func(e *EventEmitter) {
+		e.queue = make(chan *CloudEvent, size)
+	}
+}
+
+func NewEventEmitter(logger logr.Logger, sinkURL string, opts ...EventEmitterOption) *EventEmitter {
+	emitter := EventEmitter{
+		logger:     logger.WithName("events"),
+		sinkURL:    sinkURL,
+		httpClient: &http.Client{Timeout: eventRequestTimeout},
+		queue:      make(chan *CloudEvent, defaultEventQueueSize),
+	}
+
+	for _, opt := range opts {
+		opt(&emitter)
+	}
+
+	return &emitter
+}
+
+// NewEventEmitterFromEnv returns the emitter of events for the sink configured by the INTEGRATION_EVENTS_SINK_URL
+// environment variable, nil is returned when no sink is configured
+func NewEventEmitterFromEnv(logger logr.Logger) *EventEmitter {
+	sinkURL := strings.TrimSpace(os.Getenv(EventsSinkURLEnvVar))
+	if sinkURL == "" {
+		return nil
+	}
+	return NewEventEmitter(logger, sinkURL)
+}
+
+// Start delivers the queued events until the context is canceled, it implements manager.Runnable
+func (e *EventEmitter) Start(ctx context.Context) error {
+	e.logger.Info("Starting to emit integration test events", "sink", e.sinkURL)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-e.queue:
+			if err := e.send(ctx, event); err != nil {
+				e.logger.Error(err, "failed to deliver integration test event", "event.ID", event.ID, "event.Type", event.Type)
+			}
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full
+func (e *EventEmitter) Dropped() int64 {
+	if e == nil {
+		return 0
+	}
+	return e.dropped.Load()
+}
+
+// EmitTestReport queues the event about the transition of the integration test described by the report,
+// only transitions to InProgress and to final states are emitted
+func (e *EventEmitter) EmitTestReport(snapshot *applicationapiv1alpha1.Snapshot, report TestReport) {
+	if e == nil {
+		return
+	}
+
+	event := newTestCaseRunEvent(snapshot, report, e.logger)
+	if event == nil {
+		return
+	}
+
+	select {
+	case e.queue <- event:
+	default:
+		e.dropped.Add(1)
+		metrics.RegisterDroppedEvent()
+		e.logger.Info("Integration test event queue is full, dropping the event",
+			"snapshot.Name", snapshot.Name, "scenario.Name", report.ScenarioName, "event.Type", event.Type)
+	}
+}
+
+// EmitTestStatusEvents emits the events about the transitions of the integration tests of the snapshot which weren't
+// emitted yet. The emitted transitions are tracked in the report status of the snapshot, independently of reporting
+// them to the git provider, so each transition is emitted once even when its report fails or isn't sent at all.
+// The transitions are recorded before their events are queued, an event is never emitted twice when the report
+// status can't be written.
+func (s *Status) EmitTestStatusEvents(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	if s.eventEmitter == nil {
+		return nil
+	}
+
+	statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to get test status annotations from snapshot: %w", err)
+	}
+	// the report status is written back, so it mustn't be replaced by an empty one when it can't be read
+	srs, err := NewSnapshotReportStatusFromSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var reports []TestReport
+	for _, detail := range statuses.GetStatuses() {
+		if !srs.IsNewerForReporter(eventsReporterName, detail.ScenarioName, detail.LastUpdateTime) {
+			continue
+		}
+		reports = append(reports, TestReport{
+			ScenarioName:        detail.ScenarioName,
+			SnapshotName:        snapshot.Name,
+			ComponentName:       snapshot.Labels[gitops.SnapshotComponentLabel],
+			Status:              detail.Status,
+			StartTime:           detail.StartTime,
+			CompletionTime:      detail.CompletionTime,
+			TestPipelineRunName: detail.TestPipelineRunName,
+		})
+		srs.SetReporterLastUpdateTime(eventsReporterName, detail.ScenarioName, detail.LastUpdateTime)
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+
+	if err := WriteSnapshotReportStatus(ctx, s.client, snapshot, srs); err != nil {
+		return fmt.Errorf("failed to write snapshot report status metadata: %w", err)
+	}
+	for _, report := range reports {
+		s.eventEmitter.EmitTestReport(snapshot, report)
+	}
+	return nil
+}
+
+// newTestCaseRunEvent returns the testCaseRun event for the report, nil is returned for states which aren't emitted
+func newTestCaseRunEvent(snapshot *applicationapiv1alpha1.Snapshot, report TestReport, logger logr.Logger) *CloudEvent {
+	var eventType, outcome string
+	switch {
+	case report.Status == intgteststat.IntegrationTestStatusInProgress:
+		eventType = TestCaseRunStartedEventType
+	case report.Status == intgteststat.IntegrationTestStatusSkipped:
+		// the skipped tests were never started
+		return nil
+	case report.Status.IsFinal():
+		eventType = TestCaseRunFinishedEventType
+		outcome = getTestCaseRunOutcome(report.Status)
+	default:
+		return nil
+	}
+
+	id := string(uuid.NewUUID())
+	now := time.Now().UTC()
+	detail := TestCaseRunDetail{
+		Namespace:   snapshot.Namespace,
+		Application: snapshot.Spec.Application,
+		Component:   report.ComponentName,
+		Snapshot:    snapshot.Name,
+		Scenario:    report.ScenarioName,
+		Status:      report.Status.String(),
+		PipelineRun: report.TestPipelineRunName,
+	}
+	if report.TestPipelineRunName != "" {
+		detail.PipelineURL = FormatPipelineURL(report.TestPipelineRunName, snapshot.Namespace, logger)
+	}
+	// the test run is identified by the snapshot and the scenario, a scenario can be run repeatedly for the snapshot
+	subjectID := fmt.Sprintf("%s/%s/%s", snapshot.Namespace, snapshot.Name, report.ScenarioName)
+
+	return &CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          EventSource,
+		Type:            eventType,
+		Subject:         subjectID,
+		Time:            now,
+		DataContentType: "application/json",
+		Data: CDEvent{
+			Context: CDEventContext{
+				Version:   cdEventsSpecVersion,
+				ID:        id,
+				Source:    EventSource,
+				Type:      eventType,
+				Timestamp: now,
+			},
+			Subject: CDEventSubject{
+				ID:     subjectID,
+				Source: EventSource,
+				Type:   "testCaseRun",
+				Content: TestCaseRunContent{
+					Outcome: outcome,
+					TestCase: TestCaseIdentity{
+						ID:   fmt.Sprintf("%s/%s", snapshot.Namespace, report.ScenarioName),
+						Name: report.ScenarioName,
+					},
+				},
+			},
+			CustomData:            detail,
+			CustomDataContentType: "application/json",
+		},
+	}
+}
+
+// getTestCaseRunOutcome transforms the final integration test state into the CDEvents outcome of the test run
+func getTestCaseRunOutcome(state intgteststat.IntegrationTestStatus) string {
+	switch state {
+	case intgteststat.IntegrationTestStatusTestPassed:
+		return "pass"
+	case intgteststat.IntegrationTestStatusTestFail:
+		return "fail"
+	case intgteststat.IntegrationTestStatusDeleted:
+		return "cancel"
+	default:
+		return "error"
+	}
+}
+
+// send delivers the event to the sink in the structured content mode
+func (e *EventEmitter) send(ctx context.Context, event *CloudEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.sinkURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create event request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("event request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("event request failed with status code %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
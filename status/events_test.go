@@ -0,0 +1,242 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+var _ = Describe("EventEmitter", func() {
+
+	var (
+		hasSnapshot *applicationapiv1alpha1.Snapshot
+		server      *httptest.Server
+		mutex       sync.Mutex
+		events      []status.CloudEvent
+		cancel      context.CancelFunc
+	)
+
+	receivedEvents := func() []status.CloudEvent {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]status.CloudEvent{}, events...)
+	}
+
+	BeforeEach(func() {
+		events = []status.CloudEvent{}
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/cloudevents+json"))
+			event := status.CloudEvent{}
+			Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+			mutex.Lock()
+			events = append(events, event)
+			mutex.Unlock()
+			rw.WriteHeader(http.StatusAccepted)
+		}))
+
+		hasSnapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot-sample",
+				Namespace: "default",
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{
+				Application: "application-sample",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+			cancel = nil
+		}
+		server.Close()
+	})
+
+	startEmitter := func(opts ...status.EventEmitterOption) *status.EventEmitter {
+		emitter := status.NewEventEmitter(logr.Discard(), server.URL, opts...)
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func() {
+			_ = emitter.Start(ctx)
+		}()
+		return emitter
+	}
+
+	It("emits a started event when the test is in progress", func() {
+		emitter := startEmitter()
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{
+			ScenarioName:        "scenario1",
+			ComponentName:       "component-sample",
+			Status:              integrationteststatus.IntegrationTestStatusInProgress,
+			TestPipelineRunName: "test-pipelinerun",
+		})
+
+		Eventually(receivedEvents).Should(HaveLen(1))
+		event := receivedEvents()[0]
+		Expect(event.SpecVersion).To(Equal("1.0"))
+		Expect(event.ID).NotTo(BeEmpty())
+		Expect(event.Source).To(Equal(status.EventSource))
+		Expect(event.Type).To(Equal(status.TestCaseRunStartedEventType))
+		Expect(event.Subject).To(Equal("default/snapshot-sample/scenario1"))
+		Expect(event.Time.IsZero()).To(BeFalse())
+		Expect(event.DataContentType).To(Equal("application/json"))
+
+		Expect(event.Data.Context.ID).To(Equal(event.ID))
+		Expect(event.Data.Context.Type).To(Equal(event.Type))
+		Expect(event.Data.Subject.Type).To(Equal("testCaseRun"))
+		Expect(event.Data.Subject.Content.Outcome).To(BeEmpty())
+		Expect(event.Data.Subject.Content.TestCase.Name).To(Equal("scenario1"))
+		Expect(event.Data.CustomData).To(Equal(status.TestCaseRunDetail{
+			Namespace:   "default",
+			Application: "application-sample",
+			Component:   "component-sample",
+			Snapshot:    "snapshot-sample",
+			Scenario:    "scenario1",
+			Status:      "InProgress",
+			PipelineRun: "test-pipelinerun",
+			PipelineURL: status.FormatPipelineURL("test-pipelinerun", "default", logr.Discard()),
+		}))
+	})
+
+	DescribeTable("emits a finished event with the outcome of the test", func(testStatus integrationteststatus.IntegrationTestStatus, outcome string) {
+		emitter := startEmitter()
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario1", Status: testStatus})
+
+		Eventually(receivedEvents).Should(HaveLen(1))
+		event := receivedEvents()[0]
+		Expect(event.Type).To(Equal(status.TestCaseRunFinishedEventType))
+		Expect(event.Data.Subject.Content.Outcome).To(Equal(outcome))
+		Expect(event.Data.CustomData.Status).To(Equal(testStatus.String()))
+	},
+		Entry("TestPassed", integrationteststatus.IntegrationTestStatusTestPassed, "pass"),
+		Entry("TestFail", integrationteststatus.IntegrationTestStatusTestFail, "fail"),
+		Entry("TestInvalid", integrationteststatus.IntegrationTestStatusTestInvalid, "error"),
+		Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, "cancel"),
+	)
+
+	It("doesn't emit events for pending and skipped tests", func() {
+		emitter := startEmitter()
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario1", Status: integrationteststatus.IntegrationTestStatusPending})
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario3", Status: integrationteststatus.IntegrationTestStatusSkipped})
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario2", Status: integrationteststatus.IntegrationTestStatusTestPassed})
+
+		Eventually(receivedEvents).Should(HaveLen(1))
+		Consistently(receivedEvents).Should(HaveLen(1))
+		Expect(receivedEvents()[0].Data.CustomData.Scenario).To(Equal("scenario2"))
+	})
+
+	It("drops and counts the events which don't fit into the queue without blocking", func() {
+		// the emitter isn't started, so nothing is taken from the queue
+		emitter := status.NewEventEmitter(logr.Discard(), server.URL, status.WithEventQueueSize(2))
+		for i := 0; i < 5; i++ {
+			emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario1", Status: integrationteststatus.IntegrationTestStatusTestFail})
+		}
+		Expect(emitter.Dropped()).To(Equal(int64(3)))
+	})
+
+	It("is disabled when no sink is configured", func() {
+		os.Unsetenv(status.EventsSinkURLEnvVar)
+		emitter := status.NewEventEmitterFromEnv(logr.Discard())
+		Expect(emitter).To(BeNil())
+		// a disabled emitter can be used safely
+		emitter.EmitTestReport(hasSnapshot, status.TestReport{ScenarioName: "scenario1", Status: integrationteststatus.IntegrationTestStatusTestFail})
+		Expect(emitter.Dropped()).To(BeZero())
+
+		os.Setenv(status.EventsSinkURLEnvVar, server.URL)
+		defer os.Unsetenv(status.EventsSinkURLEnvVar)
+		Expect(status.NewEventEmitterFromEnv(logr.Discard())).NotTo(BeNil())
+	})
+
+	It("emits the events of the test status transitions once", func() {
+		emitter := startEmitter()
+		hasSnapshot.Annotations = map[string]string{
+			"test.appstudio.openshift.io/status": "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T16:57:50+02:00\",\"details\":\"Test in progress\"}]",
+		}
+		st := status.NewStatus(logr.Discard(), &MockK8sClient{}, status.WithEventEmitter(emitter))
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).To(Succeed())
+		Eventually(receivedEvents).Should(HaveLen(1))
+		Expect(receivedEvents()[0].Type).To(Equal(status.TestCaseRunStartedEventType))
+
+		// the transition was already emitted
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).To(Succeed())
+		Consistently(receivedEvents).Should(HaveLen(1))
+
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestPassed\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test passed\"}]"
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).To(Succeed())
+		Eventually(receivedEvents).Should(HaveLen(2))
+		Expect(receivedEvents()[1].Type).To(Equal(status.TestCaseRunFinishedEventType))
+	})
+
+	It("doesn't emit the events of the transitions which can't be recorded", func() {
+		emitter := startEmitter()
+		hasSnapshot.Annotations = map[string]string{
+			"test.appstudio.openshift.io/status": "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T16:57:50+02:00\",\"details\":\"Test in progress\"}]",
+		}
+		st := status.NewStatus(logr.Discard(), &MockK8sClient{err: fmt.Errorf("conflict")}, status.WithEventEmitter(emitter))
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).NotTo(Succeed())
+		Consistently(receivedEvents).Should(BeEmpty())
+	})
+
+	It("emits the events independently of reporting the statuses", func() {
+		emitter := startEmitter()
+		hasSnapshot.Annotations = map[string]string{
+			"test.appstudio.openshift.io/status": "[{\"scenario\":\"scenario1\",\"status\":\"TestPassed\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test passed\"}]",
+		}
+		mockReporter := status.NewMockReporterInterface(gomock.NewController(GinkgoT()))
+		mockReporter.EXPECT().GetReporterName().Return("mocked-reporter").AnyTimes()
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Return(fmt.Errorf("failed to report")).Times(1)
+		st := status.NewStatus(logr.Discard(), &MockK8sClient{}, status.WithEventEmitter(emitter))
+
+		// reporting the status doesn't emit events, even once the report succeeds
+		Expect(st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)).NotTo(Succeed())
+		Consistently(receivedEvents).Should(BeEmpty())
+
+		// the failed report doesn't prevent emitting the event
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).To(Succeed())
+		Eventually(receivedEvents).Should(HaveLen(1))
+		Expect(receivedEvents()[0].Type).To(Equal(status.TestCaseRunFinishedEventType))
+	})
+
+	It("doesn't track the transitions when no sink is configured", func() {
+		hasSnapshot.Annotations = map[string]string{
+			"test.appstudio.openshift.io/status": "[{\"scenario\":\"scenario1\",\"status\":\"TestPassed\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test passed\"}]",
+		}
+		st := status.NewStatus(logr.Discard(), &MockK8sClient{})
+		Expect(st.EmitTestStatusEvents(context.Background(), hasSnapshot)).To(Succeed())
+		Expect(hasSnapshot.Annotations).NotTo(HaveKey("test.appstudio.openshift.io/git-reporter-status"))
+	})
+})
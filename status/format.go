@@ -18,18 +18,26 @@ package status
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
 )
 
 const commentTemplate = `### {{ .Title }}
 
-{{ .Summary }}`
+{{ .Summary }}
+{{- if .Marker }}
+
+<!-- {{ .Marker }} -->
+{{- end }}`
 
 const summaryTemplate = `
 {{- $pipelineRunName := .PipelineRunName -}} {{ $namespace := .Namespace -}} {{ $logger := .Logger -}}
@@ -46,6 +54,51 @@ const summaryTemplate = `
 
 {{ formatFootnotes .TaskRuns }}`
 
+const commentHistoryTemplate = `
+
+<!-- {{ .Marker }} {{ .Data }} -->
+{{- if gt (len .Entries) 1 }}
+<details>
+<summary>History</summary>
+
+| Attempt | Time | Outcome | Pipelinerun |
+| --- | --- | --- | --- |
+{{- range $i, $entry := .Entries }}
+| {{ inc $i }} | {{ $entry.Time.UTC.Format "2006-01-02 15:04:05 MST" }} | {{ $entry.Outcome }} | {{ $entry.PipelineRunName }} |
+{{- end }}
+
+</details>
+{{- end }}`
+
+// commentReportMarkerPrefix starts the hidden marker identifying the comment of a scenario report
+const commentReportMarkerPrefix = "<!-- integration-test-report:"
+
+// commentReportMarkerFormat identifies the comment of a scenario report for a snapshot
+const commentReportMarkerFormat = "integration-test-report: snapshot=%s scenario=%s"
+
+// commentHistoryMarker identifies the HTML comment holding the structured history of the previous attempts
+const commentHistoryMarker = "integration-test-history:"
+
+// MaxCommentHistoryEntries is the maximum number of attempts kept in the history section of a comment
+const MaxCommentHistoryEntries = 5
+
+// commentHistoryRegex matches the HTML comment holding the structured history of the previous attempts
+var commentHistoryRegex = regexp.MustCompile(`<!-- ` + regexp.QuoteMeta(commentHistoryMarker) + ` (.*?) -->`)
+
+// CommentHistoryEntry holds details about a single test attempt listed in the history section of a comment
+type CommentHistoryEntry struct {
+	Time            time.Time `json:"time"`
+	Outcome         string    `json:"outcome"`
+	PipelineRunName string    `json:"pipelineRunName,omitempty"`
+}
+
+// CommentHistoryTemplateData holds the data necessary to construct the history section of a comment.
+type CommentHistoryTemplateData struct {
+	Marker  string
+	Data    string
+	Entries []CommentHistoryEntry
+}
+
 // SummaryTemplateData holds the data necessary to construct a PipelineRun summary.
 type SummaryTemplateData struct {
 	TaskRuns        []*helpers.TaskRun
@@ -65,6 +118,7 @@ type TaskLogTemplateData struct {
 type CommentTemplateData struct {
 	Title   string
 	Summary string
+	Marker  string
 }
 
 // FormatTestsSummary builds a markdown summary for a list of integration TaskRuns.
@@ -87,10 +141,19 @@ func FormatTestsSummary(taskRuns []*helpers.TaskRun, pipelineRunName string, nam
 	return buf.String(), nil
 }
 
-// FormatComment build a markdown comment with the details in text
-func FormatComment(title, text string) (string, error) {
+// FormatCommentMarker returns the hidden marker identifying the comment of the given scenario and snapshot.
+func FormatCommentMarker(snapshotName, scenarioName string) string {
+	return fmt.Sprintf("<!-- "+commentReportMarkerFormat+" -->", snapshotName, scenarioName)
+}
+
+// FormatComment build a markdown comment with the details in text. A hidden marker identifying the
+// scenario and snapshot is added when both names are given, so the comment can be found and updated later.
+func FormatComment(title, text, snapshotName, scenarioName string) (string, error) {
 	buf := bytes.Buffer{}
 	data := CommentTemplateData{Title: title, Summary: text}
+	if snapshotName != "" && scenarioName != "" {
+		data.Marker = fmt.Sprintf(commentReportMarkerFormat, snapshotName, scenarioName)
+	}
 	t := template.Must(template.New("").Parse(commentTemplate))
 	if err := t.Execute(&buf, data); err != nil {
 		return "", err
@@ -98,6 +161,105 @@ func FormatComment(title, text string) (string, error) {
 	return buf.String(), nil
 }
 
+// FormatRetestInstructions builds a markdown section explaining how to rerun all tests or one of the given scenarios.
+// An empty string is returned when no scenario names are given.
+func FormatRetestInstructions(scenarioNames []string) string {
+	if len(scenarioNames) == 0 {
+		return ""
+	}
+
+	if len(scenarioNames) == 1 {
+		return fmt.Sprintf("\n\n**Retest options:** `/retest` reruns all checks; to rerun only the scenario `%[2]s` add the label `%[1]s: %[2]s`",
+			gitops.SnapshotIntegrationTestRun, scenarioNames[0])
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n\n**Retest options:** `/retest` reruns all checks; to rerun a single scenario add the label `%s: <name>` with one of the scenario names:\n",
+		gitops.SnapshotIntegrationTestRun)
+	for _, scenarioName := range scenarioNames {
+		fmt.Fprintf(&sb, "\n- `%s`", scenarioName)
+	}
+	return sb.String()
+}
+
+// formatReportCommentText returns the text of the report to be used in a comment,
+// retest instructions are added for failed tests.
+func formatReportCommentText(report TestReport) string {
+	if !isFailedReport(report) {
+		return report.Text
+	}
+	return report.Text + FormatRetestInstructions(report.ScenarioNames)
+}
+
+// ParseCommentHistory returns the test attempts stored in the history section of the given comment.
+// No entries are returned when the comment doesn't contain the history section.
+func ParseCommentHistory(comment string) ([]CommentHistoryEntry, error) {
+	match := commentHistoryRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return []CommentHistoryEntry{}, nil
+	}
+
+	entries := []CommentHistoryEntry{}
+	if err := json.Unmarshal([]byte(match[1]), &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal comment history: %w", err)
+	}
+	return entries, nil
+}
+
+// FormatCommentHistory builds the structured marker used by ParseCommentHistory followed by a collapsed
+// markdown section listing the given test attempts. Only the last MaxCommentHistoryEntries attempts are kept.
+// The markdown section is rendered only when the test was attempted more than once.
+func FormatCommentHistory(entries []CommentHistoryEntry) (string, error) {
+	if len(entries) > MaxCommentHistoryEntries {
+		entries = entries[len(entries)-MaxCommentHistoryEntries:]
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal comment history: %w", err)
+	}
+
+	buf := bytes.Buffer{}
+	funcMap := template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}
+	t := template.Must(template.New("").Funcs(funcMap).Parse(commentHistoryTemplate))
+	templateData := CommentHistoryTemplateData{Marker: commentHistoryMarker, Data: string(data), Entries: entries}
+	if err := t.Execute(&buf, templateData); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// FormatCommentWithHistory builds a markdown comment for the given report, followed by the history of the test attempts.
+// The history is carried over from the previous comment of the same test, a malformed history is discarded.
+func FormatCommentWithHistory(report TestReport, previousComment string) (string, error) {
+	comment, err := FormatComment(report.Summary, formatReportCommentText(report), report.SnapshotName, report.ScenarioName)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := ParseCommentHistory(previousComment)
+	if err != nil {
+		entries = []CommentHistoryEntry{}
+	}
+	attemptTime := time.Now()
+	if report.CompletionTime != nil {
+		attemptTime = *report.CompletionTime
+	}
+	entries = append(entries, CommentHistoryEntry{
+		Time:            attemptTime,
+		Outcome:         report.Status.String(),
+		PipelineRunName: report.TestPipelineRunName,
+	})
+
+	history, err := FormatCommentHistory(entries)
+	if err != nil {
+		return "", err
+	}
+	return comment + history, nil
+}
+
 // FormatStatus accepts a TaskRun and returns a Markdown friendly representation of its overall status, if any.
 func FormatStatus(taskRun *helpers.TaskRun) (string, error) {
 	result, err := taskRun.GetTestResult()
@@ -240,7 +402,22 @@ func FormatTaskLogURL(taskRun *helpers.TaskRun, pipelinerun string, namespace st
 		return "https://CONSOLE_URL_TASKLOG_NOT_AVAILABLE"
 	}
 
-	taskName := taskRun.GetPipelineTaskName()
+	return formatTaskLogURL(consoleTaskLogURL, taskRun.GetPipelineTaskName(), pipelinerun, namespace, logger)
+}
+
+// FormatPipelineTaskURL accepts a name of pipelinerun, task, namespace and returns a deep link to the logs of the task.
+// When the task log URL is not configured or no task name is given, the pipelineURL is returned.
+func FormatPipelineTaskURL(pipelinerun string, taskName string, namespace string, logger logr.Logger) string {
+	consoleTaskLogURL := os.Getenv("CONSOLE_URL_TASKLOG")
+	if consoleTaskLogURL == "" || taskName == "" {
+		return FormatPipelineURL(pipelinerun, namespace, logger)
+	}
+
+	return formatTaskLogURL(consoleTaskLogURL, taskName, pipelinerun, namespace, logger)
+}
+
+// formatTaskLogURL renders the given task log URL template
+func formatTaskLogURL(consoleTaskLogURL string, taskName string, pipelinerun string, namespace string, logger logr.Logger) string {
 	buf := bytes.Buffer{}
 	data := TaskLogTemplateData{PipelineRunName: pipelinerun, TaskName: taskName, Namespace: namespace}
 	t := template.Must(template.New("").Parse(consoleTaskLogURL))
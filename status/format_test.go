@@ -17,11 +17,13 @@ limitations under the License.
 package status_test
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/konflux-ci/integration-service/helpers"
+	"github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/integration-service/status"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -212,10 +214,11 @@ var _ = Describe("Formatters", func() {
 	It("can construct a comment", func() {
 		text, err := status.FormatTestsSummary(taskRuns, pipelineRun.Name, pipelineRun.Namespace, logr.Discard())
 		Expect(err).To(Succeed())
-		comment, err := status.FormatComment("example-title", text)
+		comment, err := status.FormatComment("example-title", text, "snapshot-sample", "scenario1")
 		Expect(err).To(BeNil())
 		Expect(comment).To(ContainSubstring("### example-title"))
 		Expect(comment).To(ContainSubstring(expectedSummary))
+		Expect(comment).To(HaveSuffix(status.FormatCommentMarker("snapshot-sample", "scenario1")))
 	})
 
 	It("can construct a taskLogURL", func() {
@@ -223,12 +226,101 @@ var _ = Describe("Formatters", func() {
 		Expect(taskLogUrl).To(Equal(expectedTaskLogURL))
 	})
 
+	It("can construct a deep link to the failed task", func() {
+		url := status.FormatPipelineTaskURL(pipelineRun.Name, "example-task-1", pipelineRun.Namespace, logr.Discard())
+		Expect(url).To(Equal(expectedTaskLogURL))
+	})
+
+	It("falls back to the pipelineRun URL when no failed task is known", func() {
+		url := status.FormatPipelineTaskURL(pipelineRun.Name, "", pipelineRun.Namespace, logr.Discard())
+		Expect(url).To(Equal(status.FormatPipelineURL(pipelineRun.Name, pipelineRun.Namespace, logr.Discard())))
+	})
+
+	It("falls back to the pipelineRun URL when CONSOLE_URL_TASKLOG env var is not set", func() {
+		os.Setenv("CONSOLE_URL_TASKLOG", "")
+		url := status.FormatPipelineTaskURL(pipelineRun.Name, "example-task-1", pipelineRun.Namespace, logr.Discard())
+		Expect(url).To(Equal("https://definetly.not.prod/preview/application-pipeline/ns/default/pipelinerun/pipelinerun-component-sample"))
+	})
+
 	It("can construct a summary", func() {
 		summary, err := status.FormatTestsSummary(taskRuns, pipelineRun.Name, pipelineRun.Namespace, logr.Discard())
 		Expect(err).To(BeNil())
 		Expect(summary).To(Equal(expectedSummary))
 	})
 
+	It("renders and parses the history of previous attempts", func() {
+		startTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		entries := []status.CommentHistoryEntry{}
+		for i := 0; i < 7; i++ {
+			entries = append(entries, status.CommentHistoryEntry{
+				Time:            startTime.Add(time.Duration(i) * time.Minute),
+				Outcome:         "TestFail",
+				PipelineRunName: fmt.Sprintf("pipelinerun-%d", i),
+			})
+		}
+		history, err := status.FormatCommentHistory(entries)
+		Expect(err).To(Succeed())
+		Expect(history).To(ContainSubstring("<summary>History</summary>"))
+		Expect(history).To(ContainSubstring("| 5 | 2024-05-01 10:06:00 UTC | TestFail | pipelinerun-6 |"))
+		Expect(history).NotTo(ContainSubstring("pipelinerun-1 |"))
+
+		parsed, err := status.ParseCommentHistory("### example-title\n\ntext" + history)
+		Expect(err).To(Succeed())
+		Expect(parsed).To(Equal(entries[2:]))
+	})
+
+	It("doesn't render a history table for a single attempt", func() {
+		history, err := status.FormatCommentHistory([]status.CommentHistoryEntry{{Time: time.Now(), Outcome: "TestPassed"}})
+		Expect(err).To(Succeed())
+		Expect(history).To(ContainSubstring("<!-- integration-test-history: "))
+		Expect(history).NotTo(ContainSubstring("<summary>History</summary>"))
+	})
+
+	It("returns no history for a comment without history", func() {
+		parsed, err := status.ParseCommentHistory("### example-title\n\ntext")
+		Expect(err).To(Succeed())
+		Expect(parsed).To(BeEmpty())
+	})
+
+	It("fails to parse a malformed history", func() {
+		_, err := status.ParseCommentHistory("<!-- integration-test-history: [{\"time\": -->")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("can construct retest instructions for a single scenario", func() {
+		Expect(status.FormatRetestInstructions([]string{"scenario1"})).To(Equal(
+			"\n\n**Retest options:** `/retest` reruns all checks; to rerun only the scenario `scenario1` add the label `test.appstudio.openshift.io/run: scenario1`"))
+	})
+
+	It("can construct retest instructions for many scenarios", func() {
+		Expect(status.FormatRetestInstructions([]string{"scenario1", "scenario2"})).To(Equal(
+			"\n\n**Retest options:** `/retest` reruns all checks; to rerun a single scenario add the label `test.appstudio.openshift.io/run: <name>` with one of the scenario names:\n\n- `scenario1`\n- `scenario2`"))
+	})
+
+	It("doesn't construct retest instructions without scenarios", func() {
+		Expect(status.FormatRetestInstructions(nil)).To(BeEmpty())
+	})
+
+	It("adds retest instructions to the comment of a failed test only", func() {
+		report := status.TestReport{
+			Summary:       "Integration test for snapshot snapshot-sample and scenario scenario1 failed",
+			Text:          "details",
+			SnapshotName:  "snapshot-sample",
+			ScenarioName:  "scenario1",
+			Status:        integrationteststatus.IntegrationTestStatusTestFail,
+			ScenarioNames: []string{"scenario1", "scenario2"},
+		}
+		comment, err := status.FormatCommentWithHistory(report, "")
+		Expect(err).To(Succeed())
+		Expect(comment).To(ContainSubstring("details\n\n**Retest options:**"))
+		Expect(comment).To(ContainSubstring("- `scenario2`"))
+
+		report.Status = integrationteststatus.IntegrationTestStatusTestPassed
+		comment, err = status.FormatCommentWithHistory(report, "")
+		Expect(err).To(Succeed())
+		Expect(comment).NotTo(ContainSubstring("Retest options"))
+	})
+
 	When("task TEST_OUTPUT is invalid", func() {
 
 		var taskRun *helpers.TaskRun
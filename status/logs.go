@@ -0,0 +1,194 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/konflux-ci/integration-service/helpers"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// FailedStepLogTailLines is the number of trailing log lines fetched for every failed step
+	FailedStepLogTailLines int64 = 50
+
+	// FailedStepLogLimitBytes caps the size of the log fetched for every failed step
+	FailedStepLogLimitBytes int64 = 8 * 1024
+
+	// FailedStepLogExcerptTailLines is the number of trailing log lines of the first failed step
+	// kept in the snapshot test status
+	FailedStepLogExcerptTailLines int64 = 20
+
+	// FailedStepLogExcerptLimitBytes caps the size of the log excerpt kept in the snapshot test status,
+	// the test status is stored in a snapshot annotation so it must stay small
+	FailedStepLogExcerptLimitBytes = 2 * 1024
+)
+
+// ansiEscapeRegex matches terminal escape sequences (colors, cursor movement) which are commonly present in step logs
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// PodLogClient fetches logs of containers running in a pod.
+type PodLogClient interface {
+	GetContainerLogs(ctx context.Context, namespace, podName, containerName string, tailLines, limitBytes int64) ([]byte, error)
+}
+
+// KubernetesPodLogClient fetches container logs using the pod log API of the cluster.
+type KubernetesPodLogClient struct {
+	clientset kubernetes.Interface
+}
+
+// check if interface has been correctly implemented
+var _ PodLogClient = (*KubernetesPodLogClient)(nil)
+
+// NewPodLogClient returns a PodLogClient using the given clientset
+func NewPodLogClient(clientset kubernetes.Interface) *KubernetesPodLogClient {
+	return &KubernetesPodLogClient{
+		clientset: clientset,
+	}
+}
+
+// GetContainerLogs returns at most tailLines last lines and at most limitBytes bytes of the container log
+func (c *KubernetesPodLogClient) GetContainerLogs(ctx context.Context, namespace, podName, containerName string, tailLines, limitBytes int64) ([]byte, error) {
+	return c.clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  containerName,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+}
+
+// SanitizeLog removes terminal escape sequences and control characters from the log
+// and ensures that it cannot escape from the markdown code block it is rendered in.
+func SanitizeLog(log string) string {
+	log = ansiEscapeRegex.ReplaceAllString(log, "")
+	log = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, log)
+	log = strings.ReplaceAll(log, "```", "'''")
+
+	return strings.TrimRight(log, "\n ")
+}
+
+// FormatFailedStepLogs fetches the tail of the logs of all failed steps of the given TaskRuns and returns them
+// as collapsed markdown sections. Fetching logs is best-effort, if the logs cannot be retrieved
+// (e.g. the pod was already deleted or access is denied) a note is rendered instead.
+func FormatFailedStepLogs(ctx context.Context, podLogClient PodLogClient, taskRuns []*helpers.TaskRun, namespace string, logger logr.Logger) string {
+	sections := []string{}
+	for _, tr := range taskRuns {
+		podName := tr.GetPodName()
+		if podName == "" {
+			continue
+		}
+		for _, step := range tr.GetFailedSteps() {
+			containerName := getStepContainerName(step)
+
+			var body string
+			logs, err := podLogClient.GetContainerLogs(ctx, namespace, podName, containerName, FailedStepLogTailLines, FailedStepLogLimitBytes)
+			switch {
+			case err == nil:
+				body = fmt.Sprintf("```\n%s\n```", SanitizeLog(string(logs)))
+			case errors.IsNotFound(err):
+				body = "(Logs are not available anymore, the pod has already been deleted.)"
+			case errors.IsForbidden(err):
+				body = "(Logs are not available, access to the pod logs was denied.)"
+			default:
+				logger.Error(err, "Failed to fetch logs of failed step", "pod.Name", podName, "container.Name", containerName)
+				body = "(Failed to fetch logs.)"
+			}
+			sections = append(sections, formatFailedStepLogSection(step.Name, tr.GetPipelineTaskName(), body))
+		}
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(sections, "\n") + "\n"
+}
+
+// GetFailedStepLogExcerpt fetches the tail of the log of the first failed step of the earliest failed TaskRun
+// and returns it as a collapsed markdown section, so it can be kept in the snapshot test status and reported
+// after the pipelineRun and its pods are pruned. Fetching logs is best-effort, an empty string is returned
+// when there is no failed step or its logs cannot be retrieved (e.g. the pod was already deleted).
+func GetFailedStepLogExcerpt(ctx context.Context, podLogClient PodLogClient, taskRuns []*helpers.TaskRun, namespace string, logger logr.Logger) string {
+	for _, tr := range taskRuns {
+		podName := tr.GetPodName()
+		failedSteps := tr.GetFailedSteps()
+		if podName == "" || len(failedSteps) == 0 {
+			continue
+		}
+		step := failedSteps[0]
+		containerName := getStepContainerName(step)
+
+		logs, err := podLogClient.GetContainerLogs(ctx, namespace, podName, containerName, FailedStepLogExcerptTailLines, FailedStepLogExcerptLimitBytes)
+		if err != nil {
+			if errors.IsNotFound(err) || errors.IsForbidden(err) {
+				logger.Info("Logs of failed step are not available", "pod.Name", podName, "container.Name", containerName, "reason", err.Error())
+			} else {
+				logger.Error(err, "Failed to fetch logs of failed step", "pod.Name", podName, "container.Name", containerName)
+			}
+			return ""
+		}
+
+		excerpt := truncateLogHead(SanitizeLog(string(logs)), FailedStepLogExcerptLimitBytes)
+		if excerpt == "" {
+			return ""
+		}
+		return formatFailedStepLogSection(step.Name, tr.GetPipelineTaskName(), fmt.Sprintf("```\n%s\n```", excerpt))
+	}
+
+	return ""
+}
+
+// getStepContainerName returns the name of the container running the given step
+func getStepContainerName(step tektonv1.StepState) string {
+	if step.Container != "" {
+		return step.Container
+	}
+	return "step-" + step.Name
+}
+
+// formatFailedStepLogSection renders the log of a failed step as a collapsed markdown section
+func formatFailedStepLogSection(stepName, pipelineTaskName, body string) string {
+	summary := fmt.Sprintf("<summary>Logs of failed step <b>%s</b> in task <b>%s</b></summary>", stepName, pipelineTaskName)
+	return fmt.Sprintf("<details>\n%s\n\n%s\n\n</details>", summary, body)
+}
+
+// truncateLogHead drops the beginning of the log so it doesn't exceed maxBytes, keeping only whole lines
+// of the end of the log where the failure is usually reported
+func truncateLogHead(log string, maxBytes int) string {
+	if len(log) <= maxBytes {
+		return log
+	}
+	truncated := log[len(log)-maxBytes:]
+	if i := strings.Index(truncated, "\n"); i >= 0 && i < len(truncated)-1 {
+		truncated = truncated[i+1:]
+	}
+	return "[...]\n" + strings.ToValidUTF8(truncated, "")
+}
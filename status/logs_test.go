@@ -0,0 +1,159 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/konflux-ci/integration-service/helpers"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+// MockPodLogClient returns predefined logs per container
+type MockPodLogClient struct {
+	logs map[string]string
+	err  error
+}
+
+func (c *MockPodLogClient) GetContainerLogs(ctx context.Context, namespace, podName, containerName string, tailLines, limitBytes int64) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	log, ok := c.logs[fmt.Sprintf("%s/%s", podName, containerName)]
+	if !ok {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "pods"}, podName)
+	}
+	return []byte(log), nil
+}
+
+var _ = Describe("Failed step logs", func() {
+
+	var failedTaskRun *helpers.TaskRun
+
+	BeforeEach(func() {
+		failedTaskRun = helpers.NewTaskRunFromTektonTaskRun("test-task", &tektonv1.TaskRunStatus{
+			TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+				PodName: "test-pod",
+				Steps: []tektonv1.StepState{
+					{
+						Name:      "prepare",
+						Container: "step-prepare",
+						ContainerState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+						},
+					},
+					{
+						Name:      "run-tests",
+						Container: "step-run-tests",
+						ContainerState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	It("sanitizes logs", func() {
+		log := "\x1b[31mFAIL\x1b[0m: test\x07\r\n```\nbreakout\n"
+		Expect(status.SanitizeLog(log)).To(Equal("FAIL: test\n'''\nbreakout"))
+	})
+
+	It("renders logs of the failed steps only", func() {
+		podLogClient := &MockPodLogClient{logs: map[string]string{
+			"test-pod/step-prepare":   "preparing",
+			"test-pod/step-run-tests": "--- FAIL: TestSomething\n",
+		}}
+		text := status.FormatFailedStepLogs(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+		Expect(text).To(Equal("\n<details>\n<summary>Logs of failed step <b>run-tests</b> in task <b>test-task</b></summary>\n\n```\n--- FAIL: TestSomething\n```\n\n</details>\n"))
+	})
+
+	It("renders a note when the pod is gone", func() {
+		podLogClient := &MockPodLogClient{logs: map[string]string{}}
+		text := status.FormatFailedStepLogs(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+		Expect(text).To(ContainSubstring("(Logs are not available anymore, the pod has already been deleted.)"))
+	})
+
+	It("renders a note when access to logs is denied", func() {
+		podLogClient := &MockPodLogClient{err: errors.NewForbidden(schema.GroupResource{Resource: "pods/log"}, "test-pod", fmt.Errorf("denied"))}
+		text := status.FormatFailedStepLogs(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+		Expect(text).To(ContainSubstring("(Logs are not available, access to the pod logs was denied.)"))
+	})
+
+	It("renders nothing when there are no failed steps", func() {
+		passedTaskRun := helpers.NewTaskRunFromTektonTaskRun("test-task", &tektonv1.TaskRunStatus{
+			TaskRunStatusFields: tektonv1.TaskRunStatusFields{PodName: "test-pod"},
+		})
+		text := status.FormatFailedStepLogs(context.Background(), &MockPodLogClient{}, []*helpers.TaskRun{passedTaskRun}, "default", logr.Discard())
+		Expect(text).To(BeEmpty())
+	})
+
+	Context("log excerpt of the first failed step", func() {
+		It("captures the log of the first failed step", func() {
+			podLogClient := &MockPodLogClient{logs: map[string]string{
+				"test-pod/step-run-tests": "\x1b[31m--- FAIL: TestSomething\x1b[0m\n",
+			}}
+			excerpt := status.GetFailedStepLogExcerpt(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+			Expect(excerpt).To(Equal("<details>\n<summary>Logs of failed step <b>run-tests</b> in task <b>test-task</b></summary>\n\n```\n--- FAIL: TestSomething\n```\n\n</details>"))
+		})
+
+		It("captures nothing when the pod is gone", func() {
+			podLogClient := &MockPodLogClient{logs: map[string]string{}}
+			excerpt := status.GetFailedStepLogExcerpt(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+			Expect(excerpt).To(BeEmpty())
+		})
+
+		It("captures nothing when the logs can't be fetched", func() {
+			podLogClient := &MockPodLogClient{err: fmt.Errorf("connection refused")}
+			excerpt := status.GetFailedStepLogExcerpt(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+			Expect(excerpt).To(BeEmpty())
+		})
+
+		It("keeps only the end of oversized logs", func() {
+			lines := []string{}
+			for i := 0; i < 200; i++ {
+				lines = append(lines, fmt.Sprintf("line %03d: %s", i, strings.Repeat("x", 40)))
+			}
+			podLogClient := &MockPodLogClient{logs: map[string]string{
+				"test-pod/step-run-tests": strings.Join(lines, "\n"),
+			}}
+			excerpt := status.GetFailedStepLogExcerpt(context.Background(), podLogClient, []*helpers.TaskRun{failedTaskRun}, "default", logr.Discard())
+			Expect(len(excerpt)).To(BeNumerically("<", status.FailedStepLogExcerptLimitBytes+200))
+			Expect(excerpt).To(ContainSubstring("```\n[...]\nline "))
+			Expect(excerpt).To(ContainSubstring(lines[199] + "\n```"))
+			Expect(excerpt).NotTo(ContainSubstring(lines[0]))
+		})
+
+		It("captures nothing when there are no failed steps", func() {
+			passedTaskRun := helpers.NewTaskRunFromTektonTaskRun("test-task", &tektonv1.TaskRunStatus{
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{PodName: "test-pod"},
+			})
+			excerpt := status.GetFailedStepLogExcerpt(context.Background(), &MockPodLogClient{}, []*helpers.TaskRun{passedTaskRun}, "default", logr.Discard())
+			Expect(excerpt).To(BeEmpty())
+		})
+	})
+})
@@ -33,6 +33,8 @@ type MockK8sClient struct {
 	listInterceptor    func(list client.ObjectList)
 	genericInterceptor func(obj client.Object)
 	err                error
+	// getErr is returned by Get only, unlike err returned by all operations
+	getErr error
 }
 
 func (c *MockK8sClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
@@ -54,6 +56,9 @@ func (c *MockK8sClient) Get(ctx context.Context, key types.NamespacedName, obj c
 	if c.getInterceptor != nil {
 		c.getInterceptor(key, obj)
 	}
+	if c.getErr != nil {
+		return c.getErr
+	}
 	return c.err
 }
 
@@ -40,6 +40,35 @@ func (m *MockStatusInterface) EXPECT() *MockStatusInterfaceMockRecorder {
 	return m.recorder
 }
 
+// EmitTestStatusEvents mocks base method.
+func (m *MockStatusInterface) EmitTestStatusEvents(arg0 context.Context, arg1 *v1alpha1.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitTestStatusEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmitTestStatusEvents indicates an expected call of EmitTestStatusEvents.
+func (mr *MockStatusInterfaceMockRecorder) EmitTestStatusEvents(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitTestStatusEvents", reflect.TypeOf((*MockStatusInterface)(nil).EmitTestStatusEvents), arg0, arg1)
+}
+
+// GetPRMRStateInSnapshot mocks base method.
+func (m *MockStatusInterface) GetPRMRStateInSnapshot(arg0 context.Context, arg1 *v1alpha1.Snapshot) (PRMRState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPRMRStateInSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(PRMRState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPRMRStateInSnapshot indicates an expected call of GetPRMRStateInSnapshot.
+func (mr *MockStatusInterfaceMockRecorder) GetPRMRStateInSnapshot(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPRMRStateInSnapshot", reflect.TypeOf((*MockStatusInterface)(nil).GetPRMRStateInSnapshot), arg0, arg1)
+}
+
 // GetReporter mocks base method.
 func (m *MockStatusInterface) GetReporter(arg0 *v1alpha1.Snapshot) ReporterInterface {
 	m.ctrl.T.Helper()
@@ -54,6 +83,20 @@ func (mr *MockStatusInterfaceMockRecorder) GetReporter(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReporter", reflect.TypeOf((*MockStatusInterface)(nil).GetReporter), arg0)
 }
 
+// GetReporters mocks base method.
+func (m *MockStatusInterface) GetReporters(arg0 *v1alpha1.Snapshot) []ReporterInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReporters", arg0)
+	ret0, _ := ret[0].([]ReporterInterface)
+	return ret0
+}
+
+// GetReporters indicates an expected call of GetReporters.
+func (mr *MockStatusInterfaceMockRecorder) GetReporters(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReporters", reflect.TypeOf((*MockStatusInterface)(nil).GetReporters), arg0)
+}
+
 // ReportSnapshotStatus mocks base method.
 func (m *MockStatusInterface) ReportSnapshotStatus(arg0 context.Context, arg1 ReporterInterface, arg2 *v1alpha1.Snapshot) error {
 	m.ctrl.T.Helper()
@@ -67,3 +110,17 @@ func (mr *MockStatusInterfaceMockRecorder) ReportSnapshotStatus(arg0, arg1, arg2
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSnapshotStatus", reflect.TypeOf((*MockStatusInterface)(nil).ReportSnapshotStatus), arg0, arg1, arg2)
 }
+
+// ReportSnapshotStatusToReporters mocks base method.
+func (m *MockStatusInterface) ReportSnapshotStatusToReporters(arg0 context.Context, arg1 []ReporterInterface, arg2 *v1alpha1.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportSnapshotStatusToReporters", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportSnapshotStatusToReporters indicates an expected call of ReportSnapshotStatusToReporters.
+func (mr *MockStatusInterfaceMockRecorder) ReportSnapshotStatusToReporters(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSnapshotStatusToReporters", reflect.TypeOf((*MockStatusInterface)(nil).ReportSnapshotStatusToReporters), arg0, arg1, arg2)
+}
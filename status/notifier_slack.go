@@ -0,0 +1,211 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/helpers"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+)
+
+const (
+	// SlackWebhookSecretAnnotation contains the name of the Secret in the namespace of the Application
+	// which holds the Slack webhook URL used to notify about failed required scenarios of push snapshots
+	SlackWebhookSecretAnnotation = "test.appstudio.openshift.io/slack-webhook-secret"
+
+	// SlackWebhookURLSecretKey is the key of the Slack webhook URL in the Secret
+	SlackWebhookURLSecretKey = "webhook-url"
+
+	// slackRequestTimeout limits the duration of a single request to the Slack webhook
+	slackRequestTimeout = 30 * time.Second
+)
+
+// slackMessage is the payload of the Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// SlackNotifier notifies Slack about required integration test scenarios failing for push snapshots,
+// i.e. about broken branches which were already merged
+type SlackNotifier struct {
+	logger     logr.Logger
+	client     client.Client
+	httpClient *http.Client
+}
+
+func NewSlackNotifier(logger logr.Logger, client client.Client) *SlackNotifier {
+	return &SlackNotifier{
+		logger:     logger,
+		client:     client,
+		httpClient: &http.Client{Timeout: slackRequestTimeout},
+	}
+}
+
+// NotifyFailedRequiredScenarios posts a Slack message for every required scenario which finished with a failure
+// for the given push snapshot. Only one message is sent per snapshot and scenario, the scenarios which were already
+// notified are recorded in an annotation of the snapshot. Nothing is sent when the Application doesn't reference
+// a Slack webhook Secret.
+func (n *SlackNotifier) NotifyFailedRequiredScenarios(ctx context.Context, application *applicationapiv1alpha1.Application,
+	snapshot *applicationapiv1alpha1.Snapshot, requiredScenarios []string) error {
+	secretName, ok := application.GetAnnotations()[SlackWebhookSecretAnnotation]
+	if !ok || secretName == "" || !gitops.IsSnapshotCreatedByPACPushEvent(snapshot) {
+		return nil
+	}
+
+	testStatuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to get test statuses of snapshot %s: %w", snapshot.Name, err)
+	}
+
+	notifiedScenarios, err := gitops.GetSlackNotifiedScenarios(snapshot)
+	if err != nil {
+		return err
+	}
+
+	failedScenarios := []*intgteststat.IntegrationTestStatusDetail{}
+	for _, detail := range testStatuses.GetStatuses() {
+		if isTerminalFailure(detail.Status) && slices.Contains(requiredScenarios, helpers.GetScenarioNameFromTestName(detail.ScenarioName)) &&
+			!slices.Contains(notifiedScenarios, detail.ScenarioName) {
+			failedScenarios = append(failedScenarios, detail)
+		}
+	}
+	if len(failedScenarios) == 0 {
+		return nil
+	}
+	sort.Slice(failedScenarios, func(i, j int) bool {
+		return failedScenarios[i].ScenarioName < failedScenarios[j].ScenarioName
+	})
+
+	webhookURL, err := n.getWebhookURL(ctx, application.Namespace, secretName)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, detail := range failedScenarios {
+		if err := n.postMessage(ctx, webhookURL, formatSlackFailureMessage(application, snapshot, detail, n.logger)); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to notify slack about failed scenario %s: %w", detail.ScenarioName, err))
+			continue
+		}
+		n.logger.Info("Notified slack about failed required scenario of push snapshot",
+			"snapshot.Name", snapshot.Name, "scenario.Name", detail.ScenarioName)
+		notifiedScenarios = append(notifiedScenarios, detail.ScenarioName)
+	}
+
+	if err := gitops.SetSlackNotifiedScenarios(ctx, n.client, snapshot, notifiedScenarios); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	return errs
+}
+
+// isTerminalFailure returns true when the integration test finished without passing, canceled and skipped tests are not failures
+func isTerminalFailure(status intgteststat.IntegrationTestStatus) bool {
+	return status.IsFinal() && status != intgteststat.IntegrationTestStatusTestPassed &&
+		status != intgteststat.IntegrationTestStatusDeleted && status != intgteststat.IntegrationTestStatusSkipped
+}
+
+// getWebhookURL returns the Slack webhook URL stored in the given Secret
+func (n *SlackNotifier) getWebhookURL(ctx context.Context, namespace, secretName string) (string, error) {
+	secret := &v1.Secret{}
+	if err := n.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return "", fmt.Errorf("failed to get slack webhook secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	webhookURL, ok := secret.Data[SlackWebhookURLSecretKey]
+	if !ok || len(webhookURL) == 0 {
+		return "", fmt.Errorf("slack webhook secret %s/%s doesn't contain the %s key", namespace, secretName, SlackWebhookURLSecretKey)
+	}
+	return strings.TrimSpace(string(webhookURL)), nil
+}
+
+// postMessage sends the message to the Slack webhook
+func (n *SlackNotifier) postMessage(ctx context.Context, webhookURL, text string) error {
+	data, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		// the webhook URL is a credential, don't leak it through the error of the client
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("slack request failed with status code %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// formatSlackFailureMessage returns the text of the Slack message about the failed scenario
+func formatSlackFailureMessage(application *applicationapiv1alpha1.Application, snapshot *applicationapiv1alpha1.Snapshot,
+	detail *intgteststat.IntegrationTestStatusDetail, logger logr.Logger) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":x: Required integration test scenario *%s* failed on a push snapshot with status %s\n", detail.ScenarioName, detail.Status)
+	fmt.Fprintf(&sb, "*Application:* %s\n", application.Name)
+	if component := snapshot.GetLabels()[gitops.SnapshotComponentLabel]; component != "" {
+		fmt.Fprintf(&sb, "*Component:* %s\n", component)
+	}
+	fmt.Fprintf(&sb, "*Scenario:* %s\n", detail.ScenarioName)
+	if sha := getSnapshotCommitSHA(snapshot); sha != "" {
+		fmt.Fprintf(&sb, "*Commit:* `%s`\n", sha)
+	}
+	fmt.Fprintf(&sb, "*Snapshot:* %s\n", snapshot.Name)
+	if detail.TestPipelineRunName != "" {
+		fmt.Fprintf(&sb, "*PipelineRun:* <%s|%s>\n", FormatPipelineURL(detail.TestPipelineRunName, snapshot.Namespace, logger), detail.TestPipelineRunName)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// getSnapshotCommitSHA returns the commit SHA the snapshot was built from, the full SHA of the build annotation is preferred
+func getSnapshotCommitSHA(snapshot *applicationapiv1alpha1.Snapshot) string {
+	if sha := snapshot.GetAnnotations()[gitops.BuildCommitSHAAnnotation]; sha != "" {
+		return sha
+	}
+	return snapshot.GetLabels()[gitops.PipelineAsCodeSHALabel]
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/tonglil/buflogr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+var _ = Describe("SlackNotifier", func() {
+
+	const sha = "12a4a35ccd08194595179815e4646c3a6c08bb77"
+
+	var (
+		hasApp         *applicationapiv1alpha1.Application
+		hasSnapshot    *applicationapiv1alpha1.Snapshot
+		mockK8sClient  *MockK8sClient
+		notifier       *status.SlackNotifier
+		server         *httptest.Server
+		messages       []string
+		responseStatus int
+		patched        int
+		buf            bytes.Buffer
+		log            logr.Logger
+	)
+
+	testStatus := func(scenario, testStatus string) string {
+		return fmt.Sprintf(`{"scenario":"%s","status":"%s","startTime":"2023-07-26T16:57:49+02:00","lastUpdateTime":"2023-07-26T17:57:50+02:00","details":"details","testPipelineRunName":"%s-plr"}`,
+			scenario, testStatus, scenario)
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+		log = buflogr.NewWithBuffer(&buf)
+
+		messages = []string{}
+		responseStatus = http.StatusOK
+		patched = 0
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/services/T000/B000/XXXX"))
+			message := map[string]string{}
+			Expect(json.NewDecoder(r.Body).Decode(&message)).To(Succeed())
+			messages = append(messages, message["text"])
+			rw.WriteHeader(responseStatus)
+		}))
+
+		hasApp = &applicationapiv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "application-sample",
+				Namespace: "default",
+				Annotations: map[string]string{
+					status.SlackWebhookSecretAnnotation: "slack-webhook",
+				},
+			},
+		}
+
+		hasSnapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot-sample",
+				Namespace: "default",
+				Labels: map[string]string{
+					gitops.SnapshotTypeLabel:            gitops.SnapshotComponentType,
+					gitops.SnapshotComponentLabel:       "component-sample",
+					gitops.PipelineAsCodeEventTypeLabel: gitops.PipelineAsCodePushType,
+					gitops.PipelineAsCodeSHALabel:       sha,
+				},
+				Annotations: map[string]string{
+					gitops.SnapshotTestsStatusAnnotation: "[" + testStatus("scenario1", "TestFail") + "]",
+				},
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{
+				Application: "application-sample",
+			},
+		}
+
+		mockK8sClient = &MockK8sClient{
+			getInterceptor: func(key client.ObjectKey, obj client.Object) {
+				if secret, ok := obj.(*v1.Secret); ok {
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "default", Name: "slack-webhook"}))
+					secret.Data = map[string][]byte{status.SlackWebhookURLSecretKey: []byte(server.URL + "/services/T000/B000/XXXX\n")}
+				}
+			},
+			genericInterceptor: func(obj client.Object) {
+				patched++
+			},
+		}
+
+		notifier = status.NewSlackNotifier(log, mockK8sClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts a message about a failed required scenario of a push snapshot", func() {
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})).To(Succeed())
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0]).To(ContainSubstring("*Application:* application-sample"))
+		Expect(messages[0]).To(ContainSubstring("*Component:* component-sample"))
+		Expect(messages[0]).To(ContainSubstring("*Scenario:* scenario1"))
+		Expect(messages[0]).To(ContainSubstring("*Commit:* `" + sha + "`"))
+		Expect(messages[0]).To(ContainSubstring("<" + status.FormatPipelineURL("scenario1-plr", "default", logr.Discard()) + "|scenario1-plr>"))
+		Expect(hasSnapshot.Annotations).To(HaveKeyWithValue(gitops.SlackNotifiedScenariosAnnotation, "scenario1"))
+		Expect(patched).To(Equal(1))
+	})
+
+	It("prefers the full commit SHA of the build annotation", func() {
+		hasSnapshot.Labels[gitops.PipelineAsCodeSHALabel] = "12a4a35"
+		hasSnapshot.Annotations[gitops.BuildCommitSHAAnnotation] = sha
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})).To(Succeed())
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0]).To(ContainSubstring("*Commit:* `" + sha + "`"))
+	})
+
+	It("notifies the same scenario of the snapshot only once", func() {
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1", "scenario2"})).To(Succeed())
+		Expect(messages).To(HaveLen(1))
+
+		hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" + testStatus("scenario1", "TestFail") + "," + testStatus("scenario2", "TestInvalid") + "]"
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1", "scenario2"})).To(Succeed())
+		Expect(messages).To(HaveLen(2))
+		Expect(messages[1]).To(ContainSubstring("*Scenario:* scenario2"))
+		Expect(hasSnapshot.Annotations).To(HaveKeyWithValue(gitops.SlackNotifiedScenariosAnnotation, "scenario1,scenario2"))
+
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1", "scenario2"})).To(Succeed())
+		Expect(messages).To(HaveLen(2))
+		Expect(patched).To(Equal(2))
+	})
+
+	DescribeTable("doesn't notify", func(prepare func()) {
+		prepare()
+		Expect(notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})).To(Succeed())
+		Expect(messages).To(BeEmpty())
+		Expect(patched).To(BeZero())
+	},
+		Entry("pull request snapshots", func() {
+			hasSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodePullRequestType
+		}),
+		Entry("applications without a slack webhook", func() {
+			delete(hasApp.Annotations, status.SlackWebhookSecretAnnotation)
+		}),
+		Entry("optional scenarios", func() {
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" + testStatus("optional-scenario", "TestFail") + "]"
+		}),
+		Entry("passed scenarios", func() {
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" + testStatus("scenario1", "TestPassed") + "]"
+		}),
+		Entry("scenarios in progress", func() {
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" + testStatus("scenario1", "InProgress") + "]"
+		}),
+		Entry("canceled scenarios", func() {
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = "[" + testStatus("scenario1", "Deleted") + "]"
+		}),
+	)
+
+	It("returns an error without recording the scenario when slack rejects the message", func() {
+		responseStatus = http.StatusForbidden
+		err := notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed with status code 403"))
+		Expect(hasSnapshot.Annotations).NotTo(HaveKey(gitops.SlackNotifiedScenariosAnnotation))
+		Expect(patched).To(BeZero())
+	})
+
+	It("doesn't leak the webhook URL when slack can't be reached", func() {
+		server.Close()
+		err := notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).NotTo(ContainSubstring("/services/T000/B000/XXXX"))
+	})
+
+	It("returns an error when the secret doesn't contain the webhook URL", func() {
+		mockK8sClient.getInterceptor = func(key client.ObjectKey, obj client.Object) {
+			if secret, ok := obj.(*v1.Secret); ok {
+				secret.Data = map[string][]byte{"other-key": []byte("value")}
+			}
+		}
+		err := notifier.NotifyFailedRequiredScenarios(context.TODO(), hasApp, hasSnapshot, []string{"scenario1"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("doesn't contain the webhook-url key"))
+		Expect(messages).To(BeEmpty())
+	})
+})
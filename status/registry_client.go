@@ -0,0 +1,346 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const (
+	// OCIManifestMediaType is the media type of OCI image manifests
+	OCIManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+	// OCIEmptyConfigMediaType is the media type of the empty config of OCI artifacts
+	OCIEmptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+
+	// registryRequestTimeout limits the duration of a single request to the container registry
+	registryRequestTimeout = 60 * time.Second
+)
+
+// ociEmptyConfig is the content of the empty config of OCI artifacts
+var ociEmptyConfig = []byte("{}")
+
+// RegistryCredentials are the credentials used to authenticate to the container registry
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// OCIArtifact is an OCI artifact with a single layer
+type OCIArtifact struct {
+	ArtifactType   string
+	LayerMediaType string
+	LayerTitle     string
+	Layer          []byte
+	Annotations    map[string]string
+}
+
+// RegistryAuthError is returned when the container registry rejects the credentials
+type RegistryAuthError struct {
+	Repository string
+	StatusCode int
+}
+
+func (e *RegistryAuthError) Error() string {
+	return fmt.Sprintf("registry rejected the credentials for repository %s with status code %d", e.Repository, e.StatusCode)
+}
+
+// RegistryClient pushes OCI artifacts to container registries
+type RegistryClient interface {
+	// PushArtifact pushes the artifact under the given tag and returns the digest of its manifest,
+	// credentials are optional
+	PushArtifact(ctx context.Context, tag name.Tag, credentials *RegistryCredentials, artifact *OCIArtifact) (string, error)
+}
+
+// ociDescriptor describes content in OCI manifests
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int               `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest of an artifact
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// ociRegistryClient pushes OCI artifacts using the OCI distribution API
+type ociRegistryClient struct {
+	httpClient *http.Client
+}
+
+// check if interface has been correctly implemented
+var _ RegistryClient = (*ociRegistryClient)(nil)
+
+// NewRegistryClient returns a client pushing OCI artifacts using the OCI distribution API
+func NewRegistryClient() RegistryClient {
+	return &ociRegistryClient{
+		httpClient: &http.Client{Timeout: registryRequestTimeout},
+	}
+}
+
+// registrySession holds the authorization of requests to a single repository
+type registrySession struct {
+	client        *ociRegistryClient
+	repository    name.Repository
+	credentials   *RegistryCredentials
+	authorization string
+}
+
+// PushArtifact pushes the config and the layer blobs of the artifact followed by its manifest
+func (c *ociRegistryClient) PushArtifact(ctx context.Context, tag name.Tag, credentials *RegistryCredentials, artifact *OCIArtifact) (string, error) {
+	session := &registrySession{client: c, repository: tag.Context(), credentials: credentials}
+
+	config := ociDescriptor{MediaType: OCIEmptyConfigMediaType, Digest: ociDigest(ociEmptyConfig), Size: len(ociEmptyConfig)}
+	layer := ociDescriptor{MediaType: artifact.LayerMediaType, Digest: ociDigest(artifact.Layer), Size: len(artifact.Layer)}
+	if artifact.LayerTitle != "" {
+		layer.Annotations = map[string]string{"org.opencontainers.image.title": artifact.LayerTitle}
+	}
+
+	if err := session.pushBlob(ctx, ociEmptyConfig, config.Digest); err != nil {
+		return "", err
+	}
+	if err := session.pushBlob(ctx, artifact.Layer, layer.Digest); err != nil {
+		return "", err
+	}
+
+	manifest, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     OCIManifestMediaType,
+		ArtifactType:  artifact.ArtifactType,
+		Config:        config,
+		Layers:        []ociDescriptor{layer},
+		Annotations:   artifact.Annotations,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	resp, err := session.do(ctx, http.MethodPut, session.url("/manifests/"+tag.TagStr()), manifest, OCIManifestMediaType)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", unexpectedRegistryResponse("push manifest", resp)
+	}
+
+	return ociDigest(manifest), nil
+}
+
+// pushBlob uploads the blob unless the repository already contains it
+func (s *registrySession) pushBlob(ctx context.Context, blob []byte, digest string) error {
+	resp, err := s.do(ctx, http.MethodHead, s.url("/blobs/"+digest), nil, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = s.do(ctx, http.MethodPost, s.url("/blobs/uploads/"), nil, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return unexpectedRegistryResponse("start blob upload", resp)
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("registry didn't return the location of blob upload: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = s.do(ctx, http.MethodPut, location.String(), blob, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return unexpectedRegistryResponse("upload blob", resp)
+	}
+	return nil
+}
+
+// url returns the URL of the path in the distribution API of the repository
+func (s *registrySession) url(path string) string {
+	return fmt.Sprintf("%s://%s/v2/%s%s", s.repository.Registry.Scheme(), s.repository.RegistryStr(), s.repository.RepositoryStr(), path)
+}
+
+// do sends the request and authorizes it when the registry requires authorization
+func (s *registrySession) do(ctx context.Context, method, requestURL string, body []byte, contentType string) (*http.Response, error) {
+	resp, err := s.send(ctx, method, requestURL, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && s.authorization == "" {
+		resp.Body.Close()
+		if err := s.authorize(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+		resp, err = s.send(ctx, method, requestURL, body, contentType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, &RegistryAuthError{Repository: s.repository.Name(), StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// send sends a single request to the registry
+func (s *registrySession) send(ctx context.Context, method, requestURL string, body []byte, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request %s %s failed: %w", method, req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// authorize sets the authorization of the following requests according to the challenge of the registry,
+// both basic authentication and bearer tokens are supported
+func (s *registrySession) authorize(ctx context.Context, challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if s.credentials == nil {
+			return &RegistryAuthError{Repository: s.repository.Name(), StatusCode: http.StatusUnauthorized}
+		}
+		s.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.credentials.Username+":"+s.credentials.Password))
+		return nil
+	case "bearer":
+		token, err := s.fetchToken(ctx, params)
+		if err != nil {
+			return err
+		}
+		s.authorization = "Bearer " + token
+		return nil
+	default:
+		return fmt.Errorf("unsupported authentication challenge of the registry: %q", challenge)
+	}
+}
+
+// fetchToken fetches the bearer token allowing to push into the repository from the token service of the registry
+func (s *registrySession) fetchToken(ctx context.Context, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("invalid realm of the registry token service: %q", params["realm"])
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", s.repository.Scope("pull,push"))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if s.credentials != nil {
+		req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+	}
+
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("registry token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", &RegistryAuthError{Repository: s.repository.Name(), StatusCode: resp.StatusCode}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", unexpectedRegistryResponse("fetch token", resp)
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode registry token response: %w", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token service didn't return a token")
+}
+
+// parseAuthChallenge returns the lowercase scheme and the parameters of the WWW-Authenticate header
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for _, param := range strings.Split(rest, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found {
+			params[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// ociDigest returns the sha256 digest of the content
+func ociDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// unexpectedRegistryResponse returns the error describing the unexpected response of the registry
+func unexpectedRegistryResponse(action string, resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("failed to %s, registry responded with status code %d: %s", action, resp.StatusCode, strings.TrimSpace(string(message)))
+}
@@ -20,12 +20,19 @@ package status
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	clienterrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -54,6 +61,10 @@ type TestReport struct {
 	CompletionTime *time.Time
 	// pipelineRun Name
 	TestPipelineRunName string
+	// name of the earliest failed pipeline task (optional)
+	FailedTaskName string
+	// names of all scenarios tested for the snapshot, listed in the retest instructions (optional)
+	ScenarioNames []string
 }
 
 type ReporterInterface interface {
@@ -67,47 +78,300 @@ type ReporterInterface interface {
 	ReportStatus(context.Context, TestReport) error
 }
 
-// GetPACGitProviderToken lookup for configured repo and fetch token from namespace
-func GetPACGitProviderToken(ctx context.Context, k8sClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) (string, error) {
+// PRMRState is the state of the pull request or merge request which triggered the snapshot
+type PRMRState string
+
+const (
+	// PRMRStateOpened is the state of a pull/merge request which can still be updated
+	PRMRStateOpened PRMRState = "opened"
+	// PRMRStateClosed is the state of a pull/merge request which was closed without being merged
+	PRMRStateClosed PRMRState = "closed"
+	// PRMRStateMerged is the state of a pull/merge request which was merged
+	PRMRStateMerged PRMRState = "merged"
+)
+
+// PRMRStateReporter is implemented by the reporters which can get the state of the pull/merge request
+// which triggered the snapshot
+type PRMRStateReporter interface {
+	// Get state of the pull/merge request of the snapshot, the reporter must be initialized
+	GetPRMRState(context.Context, *applicationapiv1alpha1.Snapshot) (PRMRState, error)
+}
+
+// errPACGitProviderSecretNotFound is returned when the snapshot repository has no git provider secret configured
+var errPACGitProviderSecretNotFound = errors.New("git provider secret not found")
+
+// NoCredentialsError is returned when none of the credential sources provides a token for the git provider
+type NoCredentialsError struct {
+	// Tried describes the credential sources which were tried, in order
+	Tried []string
+}
+
+func (e *NoCredentialsError) Error() string {
+	return fmt.Sprintf("no credentials found for the git provider, tried: %s", strings.Join(e.Tried, "; "))
+}
+
+// PACGitProviderCABundleKey is the key of the optional PEM encoded CA bundle in the PaC git provider secret,
+// used to verify the TLS certificate of self-managed git providers
+const PACGitProviderCABundleKey = "ca.crt"
+
+const (
+	// PACGitProviderHTTPProxyKey is the key of the optional proxy URL for HTTP requests in the PaC git provider secret
+	PACGitProviderHTTPProxyKey = "http-proxy"
+
+	// PACGitProviderHTTPSProxyKey is the key of the optional proxy URL for HTTPS requests in the PaC git provider secret
+	PACGitProviderHTTPSProxyKey = "https-proxy"
+
+	// PACGitProviderNoProxyKey is the key of the optional comma-separated list of hosts which aren't reached through the proxy
+	PACGitProviderNoProxyKey = "no-proxy"
+)
+
+// proxyConfig holds the proxy settings used to reach the git provider
+type proxyConfig struct {
+	httpProxy  *url.URL
+	httpsProxy *url.URL
+	noProxy    []string
+}
+
+// proxyURL returns the URL of the proxy to be used for the given request, nil is returned when the request
+// shouldn't be proxied
+func (c *proxyConfig) proxyURL(req *http.Request) (*url.URL, error) {
+	if c.isExcluded(req.URL.Hostname()) {
+		return nil, nil
+	}
+	if req.URL.Scheme == "https" {
+		return c.httpsProxy, nil
+	}
+	return c.httpProxy, nil
+}
+
+// isExcluded returns true if the host matches the no-proxy list, the entries match the host itself and its subdomains
+func (c *proxyConfig) isExcluded(host string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range c.noProxy {
+		if entry == "*" {
+			return true
+		}
+		if entryHost, _, err := net.SplitHostPort(entry); err == nil {
+			entry = entryHost
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// getProxyConfigFromSecret returns the proxy settings stored in the given git provider secret, settings missing
+// in the secret are taken from the standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+// nil is returned when no proxy is configured.
+func getProxyConfigFromSecret(secret *v1.Secret) (*proxyConfig, error) {
+	config := &proxyConfig{}
 	var err error
+	if config.httpProxy, err = parseProxyURL(getProxySetting(secret, PACGitProviderHTTPProxyKey, "HTTP_PROXY")); err != nil {
+		return nil, err
+	}
+	if config.httpsProxy, err = parseProxyURL(getProxySetting(secret, PACGitProviderHTTPSProxyKey, "HTTPS_PROXY")); err != nil {
+		return nil, err
+	}
+	if config.httpProxy == nil && config.httpsProxy == nil {
+		return nil, nil
+	}
 
-	// List all the Repository CRs in the namespace
-	repos := pacv1alpha1.RepositoryList{}
-	if err = k8sClient.List(ctx, &repos, &client.ListOptions{Namespace: snapshot.Namespace}); err != nil {
+	noProxy, _ := getProxySetting(secret, PACGitProviderNoProxyKey, "NO_PROXY")
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			config.noProxy = append(config.noProxy, entry)
+		}
+	}
+	return config, nil
+}
+
+// getProxySetting returns the value of the given key of the secret together with a description of its source,
+// the environment variable is used when the secret doesn't contain the key
+func getProxySetting(secret *v1.Secret, key, envVar string) (string, string) {
+	if value, found := secret.Data[key]; found {
+		return strings.TrimSpace(string(value)), fmt.Sprintf("secret key %s of secret %s", key, secret.Name)
+	}
+	if value := os.Getenv(envVar); value != "" {
+		return value, fmt.Sprintf("environment variable %s", envVar)
+	}
+	lowerEnvVar := strings.ToLower(envVar)
+	return os.Getenv(lowerEnvVar), fmt.Sprintf("environment variable %s", lowerEnvVar)
+}
+
+// parseProxyURL parses the proxy URL read from the given source, nil is returned for an empty value
+func parseProxyURL(value, source string) (*url.URL, error) {
+	if value == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL in %s: %w", source, err)
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5" {
+		return nil, fmt.Errorf("invalid proxy URL in %s: unsupported scheme %q, expected http, https or socks5", source, proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL in %s: host is missing", source)
+	}
+	return proxyURL, nil
+}
+
+// GetPACGitProviderToken lookup for configured repo and fetch token from namespace
+func GetPACGitProviderToken(ctx context.Context, k8sClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) (string, error) {
+	pacSecret, repoSecret, err := getPACGitProviderSecret(ctx, k8sClient, snapshot)
+	if err != nil {
 		return "", err
 	}
 
-	// Get the full repo URL
-	url, found := snapshot.GetAnnotations()[gitops.PipelineAsCodeRepoURLAnnotation]
+	// Get the personal access token from the secret
+	token, found := pacSecret.Data[repoSecret.Key]
 	if !found {
-		return "", fmt.Errorf("object annotation not found %q", gitops.PipelineAsCodeRepoURLAnnotation)
+		return "", fmt.Errorf("failed to find %s secret key", repoSecret.Key)
 	}
 
-	// Find a Repository CR with a matching URL and get its secret details
+	return string(token), nil
+}
+
+// getCABundleFromSecret returns the optional CA bundle stored in the given git provider secret,
+// nil is returned when the secret doesn't provide a CA bundle
+func getCABundleFromSecret(secret *v1.Secret) ([]byte, error) {
+	caBundle, found := secret.Data[PACGitProviderCABundleKey]
+	if !found {
+		return nil, nil
+	}
+	if len(caBundle) == 0 {
+		return nil, fmt.Errorf("secret key %s of secret %s is empty", PACGitProviderCABundleKey, secret.Name)
+	}
+
+	return caBundle, nil
+}
+
+// getPACGitProviderSecret lookup for configured repo and fetch its git provider secret from namespace
+func getPACGitProviderSecret(ctx context.Context, k8sClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) (*v1.Secret, *pacv1alpha1.Secret, error) {
+	// Find the Repository CR of the snapshot and get its secret details, a missing Repository has no secret
+	repo, err := getPACRepository(ctx, k8sClient, snapshot)
+	if err != nil && !clienterrors.IsNotFound(err) {
+		return nil, nil, err
+	}
 	var repoSecret *pacv1alpha1.Secret
-	for _, repo := range repos.Items {
-		if url == repo.Spec.URL {
-			repoSecret = repo.Spec.GitProvider.Secret
-			break
-		}
+	if repo != nil && repo.Spec.GitProvider != nil {
+		repoSecret = repo.Spec.GitProvider.Secret
 	}
 
+	url := snapshot.GetAnnotations()[gitops.PipelineAsCodeRepoURLAnnotation]
 	if repoSecret == nil {
-		return "", fmt.Errorf("failed to find a Repository matching URL: %q", url)
+		return nil, nil, fmt.Errorf("failed to find a Repository with a git provider secret matching URL %q: %w", url, errPACGitProviderSecretNotFound)
 	}
 
 	// Get the pipelines as code secret from the PipelineRun's namespace
 	pacSecret := v1.Secret{}
 	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: repoSecret.Name}, &pacSecret)
+	if clienterrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("failed to get secret %s of the Repository %s: %w", repoSecret.Name, repo.Name, errPACGitProviderSecretNotFound)
+	}
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
-	// Get the personal access token from the secret
-	token, found := pacSecret.Data[repoSecret.Key]
-	if !found {
-		return "", fmt.Errorf("failed to find %s secret key", repoSecret.Key)
+	return &pacSecret, repoSecret, nil
+}
+
+// GetPACRepository lookup for the Pipelines as Code Repository CR of the snapshot in its namespace,
+// nil is returned when no Repository matches the snapshot
+func GetPACRepository(ctx context.Context, k8sClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) (*pacv1alpha1.Repository, error) {
+	repo, err := getPACRepository(ctx, k8sClient, snapshot)
+	if clienterrors.IsNotFound(err) {
+		return nil, nil
 	}
+	return repo, err
+}
 
-	return string(token), nil
+// getPACRepository returns the Repository matching the repo URL annotation of the snapshot, looked up through the
+// URL index of the Repositories. When the annotation is missing or doesn't match any Repository, the Repositories of
+// the namespace are listed to find the Repository by the name, organization and repository labels of the snapshot.
+// A NotFound error is returned when no Repository matches the snapshot.
+func getPACRepository(ctx context.Context, k8sClient client.Client, snapshot *applicationapiv1alpha1.Snapshot) (*pacv1alpha1.Repository, error) {
+	if url, found := snapshot.GetAnnotations()[gitops.PipelineAsCodeRepoURLAnnotation]; found {
+		repo, err := getPACRepositoryForURL(ctx, k8sClient, snapshot.Namespace, url)
+		if !clienterrors.IsNotFound(err) {
+			return repo, err
+		}
+	}
+
+	notFoundErr := clienterrors.NewNotFound(pacv1alpha1.Resource("repositories"), fmt.Sprintf("of snapshot %s", snapshot.Name))
+	labels := snapshot.GetLabels()
+	_, nameFound := labels[gitops.PipelineAsCodeRepositoryLabel]
+	_, orgFound := labels[gitops.PipelineAsCodeURLOrgLabel]
+	_, repositoryFound := labels[gitops.PipelineAsCodeURLRepositoryLabel]
+	if !nameFound && !(orgFound && repositoryFound) {
+		return nil, notFoundErr
+	}
+
+	repos := pacv1alpha1.RepositoryList{}
+	if err := k8sClient.List(ctx, &repos, &client.ListOptions{Namespace: snapshot.Namespace}); err != nil {
+		return nil, err
+	}
+	if repo := findPACRepositoryByLabels(repos.Items, snapshot); repo != nil {
+		return repo, nil
+	}
+	return nil, notFoundErr
+}
+
+// getPACRepositoryForURL returns the Repository of the namespace whose URL matches the given repository URL once both
+// are normalized by gitops.NormalizePacRepositoryURL. The Repositories are looked up through the URL field index
+// registered at the setup of the manager, when several of them match the first one by name is returned.
+// A NotFound error is returned when no Repository matches the URL.
+func getPACRepositoryForURL(ctx context.Context, k8sClient client.Client, namespace, url string) (*pacv1alpha1.Repository, error) {
+	normalizedURL := gitops.NormalizePacRepositoryURL(url)
+	repos := pacv1alpha1.RepositoryList{}
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingFields{gitops.PacRepositoryURLField: normalizedURL},
+	}
+	if err := k8sClient.List(ctx, &repos, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list the Repositories matching URL %q: %w", url, err)
+	}
+
+	var repo *pacv1alpha1.Repository
+	for i := range repos.Items {
+		// the index already matches the URL, it's checked again for the readers ignoring the field selector
+		if gitops.NormalizePacRepositoryURL(repos.Items[i].Spec.URL) != normalizedURL {
+			continue
+		}
+		if repo == nil || repos.Items[i].Name < repo.Name {
+			repo = &repos.Items[i]
+		}
+	}
+	if repo == nil {
+		return nil, clienterrors.NewNotFound(pacv1alpha1.Resource("repositories"), fmt.Sprintf("matching URL %s", normalizedURL))
+	}
+	return repo, nil
+}
+
+// findPACRepositoryByLabels returns the Repository matching the name label of the snapshot or, when it's missing or
+// doesn't match any Repository, the Repository whose URL ends with the organization and repository labels
+func findPACRepositoryByLabels(repos []pacv1alpha1.Repository, snapshot *applicationapiv1alpha1.Snapshot) *pacv1alpha1.Repository {
+	labels := snapshot.GetLabels()
+	if name, found := labels[gitops.PipelineAsCodeRepositoryLabel]; found {
+		for i := range repos {
+			if name == repos[i].Name {
+				return &repos[i]
+			}
+		}
+	}
+
+	org, orgFound := labels[gitops.PipelineAsCodeURLOrgLabel]
+	repository, repositoryFound := labels[gitops.PipelineAsCodeURLRepositoryLabel]
+	if orgFound && repositoryFound {
+		for i := range repos {
+			repoPath := gitops.NormalizePacRepositoryURL(repos[i].Spec.URL)
+			if strings.HasSuffix(repoPath, "/"+org+"/"+repository) {
+				return &repos[i]
+			}
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,355 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/konflux-ci/operator-toolkit/metadata"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+)
+
+// AzureDevOpsCommitState is the state of an Azure Repos commit or pull request status
+type AzureDevOpsCommitState string
+
+const (
+	AzureDevOpsCommitStatePending       AzureDevOpsCommitState = "pending"
+	AzureDevOpsCommitStateSucceeded     AzureDevOpsCommitState = "succeeded"
+	AzureDevOpsCommitStateFailed        AzureDevOpsCommitState = "failed"
+	AzureDevOpsCommitStateError         AzureDevOpsCommitState = "error"
+	AzureDevOpsCommitStateNotApplicable AzureDevOpsCommitState = "notApplicable"
+)
+
+const (
+	// AzureDevOpsStatusGenre is the genre of all statuses reported by integration service. Together with
+	// the name of the status it identifies the status, Azure Repos shows only the latest status of the same genre and name.
+	AzureDevOpsStatusGenre = "konflux-integration-service"
+
+	// azureDevOpsAPIVersion is the version of the Azure DevOps REST API used by the reporter
+	azureDevOpsAPIVersion = "7.1"
+
+	// azureDevOpsRequestTimeout limits the duration of a single request to the Azure DevOps API
+	azureDevOpsRequestTimeout = 30 * time.Second
+)
+
+// azureDevOpsStatusContext identifies a status of a commit or a pull request
+type azureDevOpsStatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre"`
+}
+
+// azureDevOpsStatus is the payload of the Azure Repos commit and pull request statuses API
+type azureDevOpsStatus struct {
+	ID          int64                    `json:"id,omitempty"`
+	State       AzureDevOpsCommitState   `json:"state"`
+	Description string                   `json:"description"`
+	TargetURL   string                   `json:"targetUrl,omitempty"`
+	Context     azureDevOpsStatusContext `json:"context"`
+}
+
+// azureDevOpsStatusList is the response of the Azure Repos API listing statuses
+type azureDevOpsStatusList struct {
+	Count int                 `json:"count"`
+	Value []azureDevOpsStatus `json:"value"`
+}
+
+type AzureDevOpsReporter struct {
+	logger        *logr.Logger
+	k8sClient     client.Client
+	httpClient    *http.Client
+	repositoryURL string
+	token         string
+	sha           string
+	pullRequest   int
+	snapshot      *applicationapiv1alpha1.Snapshot
+	dryRun        bool
+}
+
+// AzureDevOpsReporterOption is used to extend AzureDevOpsReporter with optional parameters.
+type AzureDevOpsReporterOption = func(r *AzureDevOpsReporter)
+
+// WithAzureDevOpsDryRun makes the reporter only log the reports instead of sending them to Azure DevOps
+func WithAzureDevOpsDryRun() AzureDevOpsReporterOption {
+	return func(r *AzureDevOpsReporter) {
+		r.dryRun = true
+	}
+}
+
+func NewAzureDevOpsReporter(logger logr.Logger, k8sClient client.Client, opts ...AzureDevOpsReporterOption) *AzureDevOpsReporter {
+	reporter := AzureDevOpsReporter{
+		logger:    &logger,
+		k8sClient: k8sClient,
+	}
+
+	for _, opt := range opts {
+		opt(&reporter)
+	}
+
+	return &reporter
+}
+
+// check if interface has been correctly implemented
+var _ ReporterInterface = (*AzureDevOpsReporter)(nil)
+
+// Detect if snapshot has been created from azure-devops provider
+func (r *AzureDevOpsReporter) Detect(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotationWithValue(snapshot, gitops.PipelineAsCodeGitProviderAnnotation, gitops.PipelineAsCodeAzureDevOpsProviderType) ||
+		metadata.HasLabelWithValue(snapshot, gitops.PipelineAsCodeGitProviderLabel, gitops.PipelineAsCodeAzureDevOpsProviderType)
+}
+
+// GetReporterName returns the reporter name
+func (r *AzureDevOpsReporter) GetReporterName() string {
+	return "AzureDevOpsReporter"
+}
+
+// Initialize initializes azure devops reporter
+func (r *AzureDevOpsReporter) Initialize(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	annotations := snapshot.GetAnnotations()
+	repoURL, found := annotations[gitops.PipelineAsCodeRepoURLAnnotation]
+	if !found {
+		return fmt.Errorf("repo-url annotation not found %q", gitops.PipelineAsCodeRepoURLAnnotation)
+	}
+	repositoryURL, err := parseAzureDevOpsRepositoryAPIURL(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse repo-url %s: %w", repoURL, err)
+	}
+
+	token, err := GetPACGitProviderToken(ctx, r.k8sClient, snapshot)
+	if err != nil {
+		r.logger.Error(err, "failed to get token from snapshot",
+			"snapshot.NameSpace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
+		return fmt.Errorf("failed to get PAC token for azure-devops provider: %w", err)
+	}
+
+	sha, found := snapshot.GetLabels()[gitops.PipelineAsCodeSHALabel]
+	if !found {
+		return fmt.Errorf("sha label not found %q", gitops.PipelineAsCodeSHALabel)
+	}
+
+	// pull request status is reported only for snapshots created from pull requests
+	pullRequest := 0
+	if pullRequestStr, found := annotations[gitops.PipelineAsCodePullRequestAnnotation]; found {
+		pullRequest, err = strconv.Atoi(pullRequestStr)
+		if err != nil {
+			return fmt.Errorf("failed to convert pull request number '%s' to integer: %w", pullRequestStr, err)
+		}
+	}
+
+	r.httpClient = &http.Client{Timeout: azureDevOpsRequestTimeout}
+	r.repositoryURL = repositoryURL
+	r.token = token
+	r.sha = sha
+	r.pullRequest = pullRequest
+	r.snapshot = snapshot
+	return nil
+}
+
+// parseAzureDevOpsRepositoryAPIURL returns the API URL of the repository given by its clone URL, e.g.
+// https://dev.azure.com/{org}/{project}/_git/{repo} is turned into
+// https://dev.azure.com/{org}/{project}/_apis/git/repositories/{repo}
+func parseAzureDevOpsRepositoryAPIURL(repoURL string) (string, error) {
+	burl, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	if burl.Scheme == "" || burl.Host == "" {
+		return "", fmt.Errorf("repository URL %q has no scheme or host", repoURL)
+	}
+
+	projectPath, repo, found := strings.Cut(strings.Trim(burl.Path, "/"), "/_git/")
+	if !found || projectPath == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", fmt.Errorf("repository URL %q doesn't match the {organization}/{project}/_git/{repository} format", repoURL)
+	}
+
+	return fmt.Sprintf("%s://%s/%s/_apis/git/repositories/%s", burl.Scheme, burl.Host, projectPath, url.PathEscape(repo)), nil
+}
+
+// ReportStatus reports test result to azure devops
+func (r *AzureDevOpsReporter) ReportStatus(ctx context.Context, report TestReport) error {
+	if r.httpClient == nil {
+		return fmt.Errorf("azure devops reporter is not initialized")
+	}
+
+	status, err := r.generateStatus(report)
+	if err != nil {
+		return err
+	}
+
+	if r.dryRun {
+		r.logger.Info("Dry-run: skipping creation of commit status on Azure DevOps",
+			"scenario.Name", report.ScenarioName, "state", status.State, "context.name", status.Context.Name,
+			"description", status.Description, "targetURL", status.TargetURL)
+		return nil
+	}
+
+	commitStatusesPath := fmt.Sprintf("/commits/%s/statuses", url.PathEscape(r.sha))
+	if err := r.setStatus(ctx, commitStatusesPath, status, report); err != nil {
+		return fmt.Errorf("failed to set azure devops commit status: %w", err)
+	}
+
+	if r.pullRequest != 0 {
+		pullRequestStatusesPath := fmt.Sprintf("/pullRequests/%d/statuses", r.pullRequest)
+		if err := r.setStatus(ctx, pullRequestStatusesPath, status, report); err != nil {
+			return fmt.Errorf("failed to set azure devops status of pull-request %d: %w", r.pullRequest, err)
+		}
+	}
+
+	return nil
+}
+
+// generateStatus creates the status reported to Azure Repos for the given report
+func (r *AzureDevOpsReporter) generateStatus(report TestReport) (*azureDevOpsStatus, error) {
+	state, err := GenerateAzureDevOpsCommitState(report.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate azure devops state: %w", err)
+	}
+
+	status := azureDevOpsStatus{
+		State:       state,
+		Description: report.Summary,
+		Context: azureDevOpsStatusContext{
+			Name:  report.FullName,
+			Genre: AzureDevOpsStatusGenre,
+		},
+	}
+	if report.TestPipelineRunName == "" {
+		r.logger.Info("TestPipelineRunName is not set, cannot add URL to message")
+	} else {
+		status.TargetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, r.snapshot.Namespace, *r.logger)
+	}
+
+	return &status, nil
+}
+
+// setStatus posts the status to the given statuses endpoint of the repository unless the latest status
+// of the same genre and name is already identical, so repeated reports don't stack the same status
+func (r *AzureDevOpsReporter) setStatus(ctx context.Context, statusesPath string, status *azureDevOpsStatus, report TestReport) error {
+	existingStatuses := azureDevOpsStatusList{}
+	if err := r.doRequest(ctx, http.MethodGet, statusesPath, nil, &existingStatuses); err != nil {
+		return fmt.Errorf("error while getting existing statuses: %w", err)
+	}
+
+	if latest := getLatestAzureDevOpsStatus(existingStatuses.Value, status.Context); latest != nil &&
+		latest.State == status.State && latest.Description == status.Description && latest.TargetURL == status.TargetURL {
+		r.logger.Info("Found azure devops status with the same state, skipping", "scenario.name", report.ScenarioName,
+			"path", statusesPath, "state", status.State)
+		return nil
+	}
+
+	if err := r.doRequest(ctx, http.MethodPost, statusesPath, status, nil); err != nil {
+		return err
+	}
+
+	r.logger.Info("Created azure devops status", "scenario.name", report.ScenarioName, "path", statusesPath,
+		"state", status.State, "TargetURL", status.TargetURL)
+	return nil
+}
+
+// getLatestAzureDevOpsStatus returns the most recent status of the given context, statuses with a higher ID are newer
+func getLatestAzureDevOpsStatus(statuses []azureDevOpsStatus, statusContext azureDevOpsStatusContext) *azureDevOpsStatus {
+	var latest *azureDevOpsStatus
+	for i, status := range statuses {
+		if status.Context != statusContext {
+			continue
+		}
+		if latest == nil || status.ID > latest.ID {
+			latest = &statuses[i]
+		}
+	}
+	return latest
+}
+
+// doRequest sends a request with the JSON encoded payload to the repository API path and decodes the JSON response
+// into result, both payload and result are optional
+func (r *AzureDevOpsReporter) doRequest(ctx context.Context, method, path string, payload any, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal azure devops request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	requestURL := fmt.Sprintf("%s%s?api-version=%s", r.repositoryURL, path, azureDevOpsAPIVersion)
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create azure devops request: %w", err)
+	}
+	// personal access tokens are sent as the password of basic authentication with an empty user name
+	req.SetBasicAuth("", r.token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure devops request %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("azure devops request %s %s failed with status code %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode azure devops response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// GenerateAzureDevOpsCommitState transforms internal integration test state into Azure DevOps state
+func GenerateAzureDevOpsCommitState(state intgteststat.IntegrationTestStatus) (AzureDevOpsCommitState, error) {
+	azureState := AzureDevOpsCommitStateFailed
+
+	switch state {
+	case intgteststat.IntegrationTestStatusPending, intgteststat.IntegrationTestStatusInProgress:
+		azureState = AzureDevOpsCommitStatePending
+	case intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated,
+		intgteststat.IntegrationTestStatusDeploymentError_Deprecated,
+		intgteststat.IntegrationTestStatusTestInvalid,
+		intgteststat.IntegrationTestStatusTestError:
+		azureState = AzureDevOpsCommitStateError
+	case intgteststat.IntegrationTestStatusDeleted, intgteststat.IntegrationTestStatusSkipped:
+		azureState = AzureDevOpsCommitStateNotApplicable
+	case intgteststat.IntegrationTestStatusTestPassed:
+		azureState = AzureDevOpsCommitStateSucceeded
+	case intgteststat.IntegrationTestStatusTestFail:
+		azureState = AzureDevOpsCommitStateFailed
+	default:
+		return azureState, fmt.Errorf("unknown status %s", state)
+	}
+
+	return azureState, nil
+}
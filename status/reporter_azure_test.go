@@ -0,0 +1,325 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/tonglil/buflogr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+var _ = Describe("AzureDevOpsReporter", func() {
+
+	const (
+		sha         = "12a4a35ccd08194595179815e4646c3a6c08bb77"
+		pullRequest = "7"
+		repoPath    = "/devfile-org/devfile-project/_apis/git/repositories/devfile-sample-go-basic"
+	)
+
+	var (
+		hasSnapshot   *applicationapiv1alpha1.Snapshot
+		mockK8sClient *MockK8sClient
+		reporter      *status.AzureDevOpsReporter
+		mux           *http.ServeMux
+		server        *httptest.Server
+		buf           bytes.Buffer
+		log           logr.Logger
+	)
+
+	BeforeEach(func() {
+		buf.Reset()
+		log = buflogr.NewWithBuffer(&buf)
+
+		mux = http.NewServeMux()
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(BeEmpty())
+			Expect(password).To(Equal("example-personal-access-token"))
+			Expect(r.URL.Query().Get("api-version")).To(Equal("7.1"))
+			mux.ServeHTTP(rw, r)
+		}))
+
+		hasSnapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot-sample",
+				Namespace: "default",
+				Labels: map[string]string{
+					"test.appstudio.openshift.io/type":           "component",
+					"appstudio.openshift.io/component":           "component-sample",
+					"pac.test.appstudio.openshift.io/sha":        sha,
+					"pac.test.appstudio.openshift.io/event-type": "pull_request",
+				},
+				Annotations: map[string]string{
+					"pac.test.appstudio.openshift.io/git-provider": "azure-devops",
+					"pac.test.appstudio.openshift.io/repo-url":     server.URL + "/devfile-org/devfile-project/_git/devfile-sample-go-basic",
+					"pac.test.appstudio.openshift.io/pull-request": pullRequest,
+				},
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{
+				Application: "application-sample",
+			},
+		}
+
+		repo := pacv1alpha1.Repository{
+			Spec: pacv1alpha1.RepositorySpec{
+				URL: server.URL + "/devfile-org/devfile-project/_git/devfile-sample-go-basic",
+				GitProvider: &pacv1alpha1.GitProvider{
+					Secret: &pacv1alpha1.Secret{
+						Name: "example-secret-name",
+						Key:  "example-token",
+					},
+				},
+			},
+		}
+		mockK8sClient = &MockK8sClient{
+			getInterceptor: func(key client.ObjectKey, obj client.Object) {
+				if secret, ok := obj.(*v1.Secret); ok {
+					secret.Data = map[string][]byte{"example-token": []byte("example-personal-access-token")}
+				}
+			},
+			listInterceptor: func(list client.ObjectList) {
+				if repoList, ok := list.(*pacv1alpha1.RepositoryList); ok {
+					repoList.Items = []pacv1alpha1.Repository{repo}
+				}
+			},
+		}
+
+		reporter = status.NewAzureDevOpsReporter(log, mockK8sClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Reporter can return name uninitialized", func() {
+		Expect(reporter.GetReporterName()).To(Equal("AzureDevOpsReporter"))
+	})
+
+	It("can detect if azure devops reporter should be used", func() {
+		Expect(reporter.Detect(hasSnapshot)).To(BeTrue())
+
+		hasSnapshot.Annotations["pac.test.appstudio.openshift.io/git-provider"] = "gitlab"
+		Expect(reporter.Detect(hasSnapshot)).To(BeFalse())
+
+		hasSnapshot.Labels["pac.test.appstudio.openshift.io/git-provider"] = "azure-devops"
+		Expect(reporter.Detect(hasSnapshot)).To(BeTrue())
+	})
+
+	It("is selected for snapshots from azure devops", func() {
+		st := status.NewStatus(log, mockK8sClient)
+		Expect(st.GetReporter(hasSnapshot).GetReporterName()).To(Equal("AzureDevOpsReporter"))
+	})
+
+	It("fails to report when not initialized", func() {
+		Expect(reporter.ReportStatus(context.TODO(), status.TestReport{})).NotTo(Succeed())
+	})
+
+	DescribeTable("test handling of missing labels/annotations", func(missingKey string, isLabel bool) {
+		if isLabel {
+			delete(hasSnapshot.Labels, missingKey)
+		} else {
+			delete(hasSnapshot.Annotations, missingKey)
+		}
+		Expect(reporter.Initialize(context.TODO(), hasSnapshot)).ToNot(Succeed())
+	},
+		Entry("Missing repo_url", gitops.PipelineAsCodeRepoURLAnnotation, false),
+		Entry("Missing SHA", gitops.PipelineAsCodeSHALabel, true),
+	)
+
+	DescribeTable("rejects repo URLs which aren't Azure Repos URLs", func(path string) {
+		hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL + path
+		err := reporter.Initialize(context.TODO(), hasSnapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("doesn't match the {organization}/{project}/_git/{repository} format"))
+	},
+		Entry("without _git", "/devfile-org/devfile-project/devfile-sample-go-basic"),
+		Entry("without project", "/_git/devfile-sample-go-basic"),
+		Entry("with subpath", "/devfile-org/devfile-project/_git/devfile-sample-go-basic/tree"),
+	)
+
+	Context("when initialized", func() {
+
+		var (
+			commitStatusesPath string
+			prStatusesPath     string
+			commitStatuses     []map[string]any
+			prStatuses         []map[string]any
+			existingStatuses   string
+		)
+
+		statusesHandler := func(posted *[]map[string]any) http.HandlerFunc {
+			return func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					fmt.Fprint(rw, existingStatuses)
+					return
+				}
+				Expect(r.Method).To(Equal(http.MethodPost))
+				commitStatus := map[string]any{}
+				Expect(json.NewDecoder(r.Body).Decode(&commitStatus)).To(Succeed())
+				*posted = append(*posted, commitStatus)
+				rw.WriteHeader(http.StatusCreated)
+				fmt.Fprint(rw, "{}")
+			}
+		}
+
+		BeforeEach(func() {
+			commitStatuses = []map[string]any{}
+			prStatuses = []map[string]any{}
+			existingStatuses = `{"count": 0, "value": []}`
+			commitStatusesPath = fmt.Sprintf("%s/commits/%s/statuses", repoPath, sha)
+			prStatusesPath = fmt.Sprintf("%s/pullRequests/%s/statuses", repoPath, pullRequest)
+			mux.HandleFunc(commitStatusesPath, statusesHandler(&commitStatuses))
+			mux.HandleFunc(prStatusesPath, statusesHandler(&prStatuses))
+		})
+
+		It("creates commit and pull request statuses", func() {
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:            "Red Hat Konflux / scenario1",
+				ScenarioName:        "scenario1",
+				SnapshotName:        "snapshot-sample",
+				TestPipelineRunName: "test-pipelinerun",
+				Status:              integrationteststatus.IntegrationTestStatusInProgress,
+				Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress",
+			})).To(Succeed())
+
+			expectedStatus := map[string]any{
+				"state":       "pending",
+				"description": "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress",
+				"targetUrl":   status.FormatPipelineURL("test-pipelinerun", "default", logr.Discard()),
+				"context": map[string]any{
+					"name":  "Red Hat Konflux / scenario1",
+					"genre": status.AzureDevOpsStatusGenre,
+				},
+			}
+			Expect(commitStatuses).To(Equal([]map[string]any{expectedStatus}))
+			Expect(prStatuses).To(Equal([]map[string]any{expectedStatus}))
+		})
+
+		It("creates only the commit status when the snapshot isn't created from a pull request", func() {
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodePullRequestAnnotation)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+				Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has passed",
+			})).To(Succeed())
+			Expect(commitStatuses).To(HaveLen(1))
+			Expect(commitStatuses[0]).To(HaveKeyWithValue("state", "succeeded"))
+			Expect(prStatuses).To(BeEmpty())
+		})
+
+		It("doesn't stack the status when the latest status of the same name is identical", func() {
+			existingStatuses = `{"count": 3, "value": [
+				{"id": 3, "state": "failed", "description": "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+				 "context": {"name": "Red Hat Konflux / scenario1", "genre": "` + status.AzureDevOpsStatusGenre + `"}},
+				{"id": 2, "state": "pending", "description": "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress",
+				 "context": {"name": "Red Hat Konflux / scenario1", "genre": "` + status.AzureDevOpsStatusGenre + `"}},
+				{"id": 1, "state": "failed", "description": "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+				 "context": {"name": "Red Hat Konflux / scenario1", "genre": "other-genre"}}
+			]}`
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			report := status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusTestFail,
+				Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+			}
+
+			Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+			Expect(commitStatuses).To(BeEmpty())
+			Expect(prStatuses).To(BeEmpty())
+			Expect(buf.String()).To(ContainSubstring("Found azure devops status with the same state, skipping"))
+
+			report.Status = integrationteststatus.IntegrationTestStatusTestPassed
+			report.Summary = "Integration test for snapshot snapshot-sample and scenario scenario1 has passed"
+			Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+			Expect(commitStatuses).To(HaveLen(1))
+			Expect(prStatuses).To(HaveLen(1))
+		})
+
+		It("returns an error when azure devops rejects the commit status", func() {
+			hasSnapshot.Labels[gitops.PipelineAsCodeSHALabel] = "unknown"
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			err := reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusInProgress,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed with status code 404"))
+		})
+
+		It("doesn't make any API calls in dry-run mode", func() {
+			reporter = status.NewAzureDevOpsReporter(log, mockK8sClient, status.WithAzureDevOpsDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusTestFail,
+			})).To(Succeed())
+			Expect(commitStatuses).To(BeEmpty())
+			Expect(prStatuses).To(BeEmpty())
+			Expect(buf.String()).To(ContainSubstring("Dry-run: skipping creation of commit status on Azure DevOps"))
+		})
+	})
+
+	DescribeTable("maps integration test statuses to azure devops states", func(testStatus integrationteststatus.IntegrationTestStatus, expectedState status.AzureDevOpsCommitState) {
+		state, err := status.GenerateAzureDevOpsCommitState(testStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(expectedState))
+	},
+		Entry("Pending", integrationteststatus.IntegrationTestStatusPending, status.AzureDevOpsCommitStatePending),
+		Entry("InProgress", integrationteststatus.IntegrationTestStatusInProgress, status.AzureDevOpsCommitStatePending),
+		Entry("EnvironmentProvisionError", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, status.AzureDevOpsCommitStateError),
+		Entry("DeploymentError", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, status.AzureDevOpsCommitStateError),
+		Entry("TestInvalid", integrationteststatus.IntegrationTestStatusTestInvalid, status.AzureDevOpsCommitStateError),
+		Entry("TestError", integrationteststatus.IntegrationTestStatusTestError, status.AzureDevOpsCommitStateError),
+		Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, status.AzureDevOpsCommitStateNotApplicable),
+		Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, status.AzureDevOpsCommitStateNotApplicable),
+		Entry("TestPassed", integrationteststatus.IntegrationTestStatusTestPassed, status.AzureDevOpsCommitStateSucceeded),
+		Entry("TestFail", integrationteststatus.IntegrationTestStatusTestFail, status.AzureDevOpsCommitStateFailed),
+	)
+
+	It("check if all integration tests statuses are supported", func() {
+		for _, teststatus := range integrationteststatus.IntegrationTestStatusValues() {
+			_, err := status.GenerateAzureDevOpsCommitState(teststatus)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,345 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/konflux-ci/operator-toolkit/metadata"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+)
+
+// GiteaCommitState is the state of a Gitea commit status
+type GiteaCommitState string
+
+const (
+	GiteaCommitStatePending GiteaCommitState = "pending"
+	GiteaCommitStateSuccess GiteaCommitState = "success"
+	GiteaCommitStateFailure GiteaCommitState = "failure"
+	GiteaCommitStateWarning GiteaCommitState = "warning"
+	GiteaCommitStateError   GiteaCommitState = "error"
+)
+
+// giteaRequestTimeout limits the duration of a single request to the Gitea API
+const giteaRequestTimeout = 30 * time.Second
+
+// giteaCommitStatus is the payload of the Gitea commit status API
+type giteaCommitStatus struct {
+	State       GiteaCommitState `json:"state"`
+	TargetURL   string           `json:"target_url,omitempty"`
+	Description string           `json:"description"`
+	Context     string           `json:"context"`
+}
+
+// giteaComment is a comment of a Gitea pull request as returned by the issue comments API
+type giteaComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+type GiteaReporter struct {
+	logger      *logr.Logger
+	k8sClient   client.Client
+	httpClient  *http.Client
+	apiURL      string
+	token       string
+	owner       string
+	repo        string
+	sha         string
+	pullRequest int
+	snapshot    *applicationapiv1alpha1.Snapshot
+	dryRun      bool
+}
+
+// GiteaReporterOption is used to extend GiteaReporter with optional parameters.
+type GiteaReporterOption = func(r *GiteaReporter)
+
+// WithGiteaDryRun makes the reporter only log the reports instead of sending them to Gitea
+func WithGiteaDryRun() GiteaReporterOption {
+	return func(r *GiteaReporter) {
+		r.dryRun = true
+	}
+}
+
+func NewGiteaReporter(logger logr.Logger, k8sClient client.Client, opts ...GiteaReporterOption) *GiteaReporter {
+	reporter := GiteaReporter{
+		logger:    &logger,
+		k8sClient: k8sClient,
+	}
+
+	for _, opt := range opts {
+		opt(&reporter)
+	}
+
+	return &reporter
+}
+
+// check if interface has been correctly implemented
+var _ ReporterInterface = (*GiteaReporter)(nil)
+
+// Detect if snapshot has been created from gitea provider, Forgejo uses the same provider type
+func (r *GiteaReporter) Detect(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return metadata.HasAnnotationWithValue(snapshot, gitops.PipelineAsCodeGitProviderAnnotation, gitops.PipelineAsCodeGiteaProviderType) ||
+		metadata.HasLabelWithValue(snapshot, gitops.PipelineAsCodeGitProviderLabel, gitops.PipelineAsCodeGiteaProviderType)
+}
+
+// GetReporterName returns the reporter name
+func (r *GiteaReporter) GetReporterName() string {
+	return "GiteaReporter"
+}
+
+// Initialize initializes gitea reporter
+func (r *GiteaReporter) Initialize(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	token, err := GetPACGitProviderToken(ctx, r.k8sClient, snapshot)
+	if err != nil {
+		r.logger.Error(err, "failed to get token from snapshot",
+			"snapshot.NameSpace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
+		return fmt.Errorf("failed to get PAC token for gitea provider: %w", err)
+	}
+
+	annotations := snapshot.GetAnnotations()
+	repoURL, found := annotations[gitops.PipelineAsCodeRepoURLAnnotation]
+	if !found {
+		return fmt.Errorf("repo-url annotation not found %q", gitops.PipelineAsCodeRepoURLAnnotation)
+	}
+	baseURL, err := parseAPIURL(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse repo-url %s: %w", repoURL, err)
+	}
+
+	labels := snapshot.GetLabels()
+	owner, found := labels[gitops.PipelineAsCodeURLOrgLabel]
+	if !found {
+		return fmt.Errorf("org label not found %q", gitops.PipelineAsCodeURLOrgLabel)
+	}
+	repo, found := labels[gitops.PipelineAsCodeURLRepositoryLabel]
+	if !found {
+		return fmt.Errorf("repository label not found %q", gitops.PipelineAsCodeURLRepositoryLabel)
+	}
+	sha, found := labels[gitops.PipelineAsCodeSHALabel]
+	if !found {
+		return fmt.Errorf("sha label not found %q", gitops.PipelineAsCodeSHALabel)
+	}
+
+	pullRequestStr, found := annotations[gitops.PipelineAsCodePullRequestAnnotation]
+	if !found {
+		return fmt.Errorf("pull-request annotation not found %q", gitops.PipelineAsCodePullRequestAnnotation)
+	}
+	pullRequest, err := strconv.Atoi(pullRequestStr)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number '%s' to integer: %w", pullRequestStr, err)
+	}
+
+	r.httpClient = &http.Client{Timeout: giteaRequestTimeout}
+	r.apiURL = baseURL + "/api/v1"
+	r.token = token
+	r.owner = owner
+	r.repo = repo
+	r.sha = sha
+	r.pullRequest = pullRequest
+	r.snapshot = snapshot
+	return nil
+}
+
+// ReportStatus reports test result to gitea
+func (r *GiteaReporter) ReportStatus(ctx context.Context, report TestReport) error {
+	if r.httpClient == nil {
+		return fmt.Errorf("gitea reporter is not initialized")
+	}
+
+	if r.dryRun {
+		return r.logDryRunReport(report)
+	}
+
+	if err := r.setCommitStatus(ctx, report); err != nil {
+		return fmt.Errorf("failed to set gitea commit status: %w", err)
+	}
+
+	// Create a comment when integration test is neither pending nor inprogress since comment for pending/inprogress is less meaningful
+	if report.Status != intgteststat.IntegrationTestStatusPending && report.Status != intgteststat.IntegrationTestStatusInProgress {
+		if err := r.updateStatusInComment(ctx, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setCommitStatus creates a commit status named after the report, Gitea shows only the latest status of the same context
+func (r *GiteaReporter) setCommitStatus(ctx context.Context, report TestReport) error {
+	state, err := GenerateGiteaCommitState(report.Status)
+	if err != nil {
+		return fmt.Errorf("failed to generate gitea state: %w", err)
+	}
+
+	commitStatus := giteaCommitStatus{
+		State:       state,
+		Description: report.Summary,
+		Context:     report.FullName,
+	}
+	if report.TestPipelineRunName == "" {
+		r.logger.Info("TestPipelineRunName is not set, cannot add URL to message")
+	} else {
+		commitStatus.TargetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, r.snapshot.Namespace, *r.logger)
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/statuses/%s", url.PathEscape(r.owner), url.PathEscape(r.repo), url.PathEscape(r.sha))
+	if err := r.doRequest(ctx, http.MethodPost, path, commitStatus, nil); err != nil {
+		return err
+	}
+
+	r.logger.Info("Created gitea commit status", "scenario.name", report.ScenarioName, "state", state, "TargetURL", commitStatus.TargetURL)
+	return nil
+}
+
+// updateStatusInComment will create/update a comment in the pull request which creates snapshot
+func (r *GiteaReporter) updateStatusInComment(ctx context.Context, report TestReport) error {
+	comment, err := FormatComment(report.Summary, formatReportCommentText(report), report.SnapshotName, report.ScenarioName)
+	if err != nil {
+		return fmt.Errorf("failed to generate comment for pull-request %d: %w", r.pullRequest, err)
+	}
+
+	issuePath := fmt.Sprintf("/repos/%s/%s/issues", url.PathEscape(r.owner), url.PathEscape(r.repo))
+	allComments := []giteaComment{}
+	if err := r.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%d/comments", issuePath, r.pullRequest), nil, &allComments); err != nil {
+		return fmt.Errorf("error while getting all comments for pull-request %d: %w", r.pullRequest, err)
+	}
+
+	body := map[string]string{"body": comment}
+	existingCommentID := getExistingGiteaCommentID(allComments, report.SnapshotName, report.ScenarioName)
+	if existingCommentID == nil {
+		if err := r.doRequest(ctx, http.MethodPost, fmt.Sprintf("%s/%d/comments", issuePath, r.pullRequest), body, nil); err != nil {
+			return fmt.Errorf("error while creating comment for pull-request %d: %w", r.pullRequest, err)
+		}
+		r.logger.Info("Created gitea comment", "scenario.name", report.ScenarioName, "pullRequest", r.pullRequest)
+		return nil
+	}
+
+	if err := r.doRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/comments/%d", issuePath, *existingCommentID), body, nil); err != nil {
+		return fmt.Errorf("error while updating comment %d for pull-request %d: %w", *existingCommentID, r.pullRequest, err)
+	}
+	r.logger.Info("Updated gitea comment", "scenario.name", report.ScenarioName, "comment.ID", *existingCommentID)
+	return nil
+}
+
+// getExistingGiteaCommentID returns the ID of the comment carrying the marker of the given snapshot and scenario
+func getExistingGiteaCommentID(comments []giteaComment, snapshotName, scenarioName string) *int64 {
+	marker := FormatCommentMarker(snapshotName, scenarioName)
+	for _, comment := range comments {
+		if strings.Contains(comment.Body, marker) {
+			return &comment.ID
+		}
+	}
+	return nil
+}
+
+// doRequest sends a request with the JSON encoded payload to the Gitea API and decodes the JSON response into result,
+// both payload and result are optional
+func (r *GiteaReporter) doRequest(ctx context.Context, method, path string, payload any, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal gitea request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.apiURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create gitea request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+r.token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitea request %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gitea request %s %s failed with status code %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode gitea response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// logDryRunReport logs the commit status which would be reported to Gitea
+func (r *GiteaReporter) logDryRunReport(report TestReport) error {
+	state, err := GenerateGiteaCommitState(report.Status)
+	if err != nil {
+		return fmt.Errorf("failed to generate gitea state: %w", err)
+	}
+
+	targetURL := ""
+	if report.TestPipelineRunName != "" {
+		targetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, r.snapshot.Namespace, *r.logger)
+	}
+
+	r.logger.Info("Dry-run: skipping creation of commit status on Gitea",
+		"scenario.Name", report.ScenarioName, "state", state, "context", report.FullName,
+		"description", report.Summary, "targetURL", targetURL)
+	return nil
+}
+
+// GenerateGiteaCommitState transforms internal integration test state into Gitea state
+func GenerateGiteaCommitState(state intgteststat.IntegrationTestStatus) (GiteaCommitState, error) {
+	giteaState := GiteaCommitStateFailure
+
+	switch state {
+	case intgteststat.IntegrationTestStatusPending, intgteststat.IntegrationTestStatusInProgress:
+		giteaState = GiteaCommitStatePending
+	case intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated,
+		intgteststat.IntegrationTestStatusDeploymentError_Deprecated,
+		intgteststat.IntegrationTestStatusTestInvalid,
+		intgteststat.IntegrationTestStatusTestError:
+		giteaState = GiteaCommitStateError
+	case intgteststat.IntegrationTestStatusDeleted:
+		giteaState = GiteaCommitStateWarning
+	case intgteststat.IntegrationTestStatusTestPassed, intgteststat.IntegrationTestStatusSkipped:
+		giteaState = GiteaCommitStateSuccess
+	case intgteststat.IntegrationTestStatusTestFail:
+		giteaState = GiteaCommitStateFailure
+	default:
+		return giteaState, fmt.Errorf("unknown status %s", state)
+	}
+
+	return giteaState, nil
+}
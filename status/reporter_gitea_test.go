@@ -0,0 +1,311 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/tonglil/buflogr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/pkg/integrationteststatus"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+var _ = Describe("GiteaReporter", func() {
+
+	const (
+		sha         = "12a4a35ccd08194595179815e4646c3a6c08bb77"
+		pullRequest = "7"
+	)
+
+	var (
+		hasSnapshot   *applicationapiv1alpha1.Snapshot
+		mockK8sClient *MockK8sClient
+		reporter      *status.GiteaReporter
+		mux           *http.ServeMux
+		server        *httptest.Server
+		buf           bytes.Buffer
+		log           logr.Logger
+	)
+
+	BeforeEach(func() {
+		buf.Reset()
+		log = buflogr.NewWithBuffer(&buf)
+
+		mux = http.NewServeMux()
+		apiHandler := http.NewServeMux()
+		apiHandler.Handle("/api/v1/", http.StripPrefix("/api/v1", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("token example-personal-access-token"))
+			mux.ServeHTTP(rw, r)
+		})))
+		server = httptest.NewServer(apiHandler)
+
+		hasSnapshot = &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot-sample",
+				Namespace: "default",
+				Labels: map[string]string{
+					"test.appstudio.openshift.io/type":               "component",
+					"appstudio.openshift.io/component":               "component-sample",
+					"pac.test.appstudio.openshift.io/url-org":        "devfile-sample",
+					"pac.test.appstudio.openshift.io/url-repository": "devfile-sample-go-basic",
+					"pac.test.appstudio.openshift.io/sha":            sha,
+					"pac.test.appstudio.openshift.io/event-type":     "pull_request",
+				},
+				Annotations: map[string]string{
+					"pac.test.appstudio.openshift.io/git-provider": "gitea",
+					"pac.test.appstudio.openshift.io/repo-url":     server.URL + "/devfile-sample/devfile-sample-go-basic",
+					"pac.test.appstudio.openshift.io/pull-request": pullRequest,
+				},
+			},
+			Spec: applicationapiv1alpha1.SnapshotSpec{
+				Application: "application-sample",
+			},
+		}
+
+		repo := pacv1alpha1.Repository{
+			Spec: pacv1alpha1.RepositorySpec{
+				URL: server.URL + "/devfile-sample/devfile-sample-go-basic",
+				GitProvider: &pacv1alpha1.GitProvider{
+					Secret: &pacv1alpha1.Secret{
+						Name: "example-secret-name",
+						Key:  "example-token",
+					},
+				},
+			},
+		}
+		mockK8sClient = &MockK8sClient{
+			getInterceptor: func(key client.ObjectKey, obj client.Object) {
+				if secret, ok := obj.(*v1.Secret); ok {
+					secret.Data = map[string][]byte{"example-token": []byte("example-personal-access-token")}
+				}
+			},
+			listInterceptor: func(list client.ObjectList) {
+				if repoList, ok := list.(*pacv1alpha1.RepositoryList); ok {
+					repoList.Items = []pacv1alpha1.Repository{repo}
+				}
+			},
+		}
+
+		reporter = status.NewGiteaReporter(log, mockK8sClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Reporter can return name uninitialized", func() {
+		Expect(reporter.GetReporterName()).To(Equal("GiteaReporter"))
+	})
+
+	It("can detect if gitea reporter should be used", func() {
+		Expect(reporter.Detect(hasSnapshot)).To(BeTrue())
+
+		hasSnapshot.Annotations["pac.test.appstudio.openshift.io/git-provider"] = "gitlab"
+		Expect(reporter.Detect(hasSnapshot)).To(BeFalse())
+
+		hasSnapshot.Labels["pac.test.appstudio.openshift.io/git-provider"] = "gitea"
+		Expect(reporter.Detect(hasSnapshot)).To(BeTrue())
+	})
+
+	It("is selected for snapshots from gitea", func() {
+		st := status.NewStatus(log, mockK8sClient)
+		Expect(st.GetReporter(hasSnapshot).GetReporterName()).To(Equal("GiteaReporter"))
+	})
+
+	It("fails to report when not initialized", func() {
+		Expect(reporter.ReportStatus(context.TODO(), status.TestReport{})).NotTo(Succeed())
+	})
+
+	DescribeTable("test handling of missing labels/annotations", func(missingKey string, isLabel bool) {
+		if isLabel {
+			delete(hasSnapshot.Labels, missingKey)
+		} else {
+			delete(hasSnapshot.Annotations, missingKey)
+		}
+		Expect(reporter.Initialize(context.TODO(), hasSnapshot)).ToNot(Succeed())
+	},
+		Entry("Missing repo_url", gitops.PipelineAsCodeRepoURLAnnotation, false),
+		Entry("Missing org", gitops.PipelineAsCodeURLOrgLabel, true),
+		Entry("Missing repository", gitops.PipelineAsCodeURLRepositoryLabel, true),
+		Entry("Missing SHA", gitops.PipelineAsCodeSHALabel, true),
+		Entry("Missing pull request", gitops.PipelineAsCodePullRequestAnnotation, false),
+	)
+
+	Context("when initialized", func() {
+
+		var (
+			statusPath   string
+			commentsPath string
+			statuses     []map[string]string
+		)
+
+		BeforeEach(func() {
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			statuses = []map[string]string{}
+			statusPath = fmt.Sprintf("/repos/devfile-sample/devfile-sample-go-basic/statuses/%s", sha)
+			commentsPath = fmt.Sprintf("/repos/devfile-sample/devfile-sample-go-basic/issues/%s/comments", pullRequest)
+			mux.HandleFunc(statusPath, func(rw http.ResponseWriter, r *http.Request) {
+				commitStatus := map[string]string{}
+				Expect(json.NewDecoder(r.Body).Decode(&commitStatus)).To(Succeed())
+				statuses = append(statuses, commitStatus)
+				rw.WriteHeader(http.StatusCreated)
+				fmt.Fprint(rw, "{}")
+			})
+		})
+
+		It("creates a commit status without a comment for a test in progress", func() {
+			mux.HandleFunc(commentsPath, func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusInternalServerError)
+			})
+
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:            "Red Hat Konflux / scenario1",
+				ScenarioName:        "scenario1",
+				SnapshotName:        "snapshot-sample",
+				TestPipelineRunName: "test-pipelinerun",
+				Status:              integrationteststatus.IntegrationTestStatusInProgress,
+				Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress",
+			})).To(Succeed())
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0]).To(HaveKeyWithValue("state", "pending"))
+			Expect(statuses[0]).To(HaveKeyWithValue("context", "Red Hat Konflux / scenario1"))
+			Expect(statuses[0]).To(HaveKeyWithValue("description", "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress"))
+			Expect(statuses[0]).To(HaveKeyWithValue("target_url", status.FormatPipelineURL("test-pipelinerun", "default", logr.Discard())))
+		})
+
+		It("creates a comment for a finished test", func() {
+			comments := []string{}
+			mux.HandleFunc(commentsPath, func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					bit, _ := io.ReadAll(r.Body)
+					comments = append(comments, string(bit))
+					rw.WriteHeader(http.StatusCreated)
+					fmt.Fprint(rw, "{}")
+					return
+				}
+				fmt.Fprint(rw, `[{"id": 1, "body": "unrelated comment"}]`)
+			})
+
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				SnapshotName: "snapshot-sample",
+				Status:       integrationteststatus.IntegrationTestStatusTestFail,
+				Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+				Text:         "detailed text here",
+			})).To(Succeed())
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0]).To(HaveKeyWithValue("state", "failure"))
+			Expect(comments).To(HaveLen(1))
+			Expect(comments[0]).To(ContainSubstring("detailed text here"))
+			Expect(comments[0]).To(ContainSubstring("integration-test-report: snapshot=snapshot-sample scenario=scenario1"))
+		})
+
+		It("updates the existing comment of the scenario in place", func() {
+			existingComment := fmt.Sprintf("### Integration test for snapshot snapshot-sample and scenario scenario1 has failed\n\n%s",
+				status.FormatCommentMarker("snapshot-sample", "scenario1"))
+			jsonComments, _ := json.Marshal([]map[string]any{{"id": 1, "body": "unrelated comment"}, {"id": 42, "body": existingComment}})
+			mux.HandleFunc(commentsPath, func(rw http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodGet), "a new comment must not be created when a matching comment exists")
+				fmt.Fprint(rw, string(jsonComments))
+			})
+			updatedComment := ""
+			mux.HandleFunc("/repos/devfile-sample/devfile-sample-go-basic/issues/comments/42", func(rw http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodPatch))
+				bit, _ := io.ReadAll(r.Body)
+				updatedComment = string(bit)
+				fmt.Fprint(rw, "{}")
+			})
+
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				SnapshotName: "snapshot-sample",
+				Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+				Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has passed",
+			})).To(Succeed())
+			Expect(updatedComment).To(ContainSubstring("has passed"))
+		})
+
+		It("returns an error when gitea rejects the commit status", func() {
+			hasSnapshot.Labels[gitops.PipelineAsCodeSHALabel] = "unknown"
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			err := reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusInProgress,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed with status code 404"))
+		})
+
+		It("doesn't make any API calls in dry-run mode", func() {
+			reporter = status.NewGiteaReporter(log, mockK8sClient, status.WithGiteaDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			Expect(reporter.ReportStatus(context.TODO(), status.TestReport{
+				FullName:     "Red Hat Konflux / scenario1",
+				ScenarioName: "scenario1",
+				Status:       integrationteststatus.IntegrationTestStatusTestFail,
+			})).To(Succeed())
+			Expect(statuses).To(BeEmpty())
+			Expect(buf.String()).To(ContainSubstring("Dry-run: skipping creation of commit status on Gitea"))
+		})
+	})
+
+	DescribeTable("maps integration test statuses to gitea commit states", func(testStatus integrationteststatus.IntegrationTestStatus, expectedState status.GiteaCommitState) {
+		state, err := status.GenerateGiteaCommitState(testStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(expectedState))
+	},
+		Entry("Pending", integrationteststatus.IntegrationTestStatusPending, status.GiteaCommitStatePending),
+		Entry("InProgress", integrationteststatus.IntegrationTestStatusInProgress, status.GiteaCommitStatePending),
+		Entry("EnvironmentProvisionError", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, status.GiteaCommitStateError),
+		Entry("DeploymentError", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, status.GiteaCommitStateError),
+		Entry("TestInvalid", integrationteststatus.IntegrationTestStatusTestInvalid, status.GiteaCommitStateError),
+		Entry("TestError", integrationteststatus.IntegrationTestStatusTestError, status.GiteaCommitStateError),
+		Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, status.GiteaCommitStateWarning),
+		Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, status.GiteaCommitStateSuccess),
+		Entry("TestPassed", integrationteststatus.IntegrationTestStatusTestPassed, status.GiteaCommitStateSuccess),
+		Entry("TestFail", integrationteststatus.IntegrationTestStatusTestFail, status.GiteaCommitStateFailure),
+	)
+
+	It("check if all integration tests statuses are supported", func() {
+		for _, teststatus := range integrationteststatus.IntegrationTestStatusValues() {
+			_, err := status.GenerateGiteaCommitState(teststatus)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+})
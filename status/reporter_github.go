@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/go-logr/logr"
 	ghapi "github.com/google/go-github/v45/github"
@@ -62,6 +63,8 @@ type CheckRunStatusUpdater struct {
 	snapshot          *applicationapiv1alpha1.Snapshot
 	creds             *appCredentials
 	allCheckRunsCache []*ghapi.CheckRun
+	// mutex guards the cache, status may be updated concurrently
+	mutex sync.Mutex
 }
 
 // NewCheckRunStatusUpdater returns a pointer to initialized CheckRunStatusUpdater
@@ -146,6 +149,8 @@ func (cru *CheckRunStatusUpdater) Authenticate(ctx context.Context, snapshot *ap
 }
 
 func (cru *CheckRunStatusUpdater) getAllCheckRuns(ctx context.Context) ([]*ghapi.CheckRun, error) {
+	cru.mutex.Lock()
+	defer cru.mutex.Unlock()
 	if len(cru.allCheckRunsCache) == 0 {
 		allCheckRuns, err := cru.ghClient.GetAllCheckRunsForRef(ctx, cru.owner, cru.repo, cru.sha, cru.creds.AppID)
 		if err != nil {
@@ -164,7 +169,7 @@ func (cru *CheckRunStatusUpdater) createCheckRunAdapterForSnapshot(report TestRe
 	snapshot := cru.snapshot
 	detailsURL := ""
 
-	conclusion, err := generateCheckRunConclusion(report.Status)
+	conclusion, err := generateCheckRunConclusion(report.Status, gitops.IsSnapshotSuperseded(snapshot))
 	if err != nil {
 		return nil, fmt.Errorf("unknown status %s for integrationTestScenario %s and snapshot %s/%s", report.Status, report.ScenarioName, snapshot.Namespace, snapshot.Name)
 	}
@@ -183,7 +188,14 @@ func (cru *CheckRunStatusUpdater) createCheckRunAdapterForSnapshot(report TestRe
 		cru.logger.Info(" TestPipelineRunName is not set for CheckRun")
 
 	} else {
-		detailsURL = FormatPipelineURL(report.TestPipelineRunName, snapshot.Namespace, *cru.logger)
+		detailsURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, snapshot.Namespace, *cru.logger)
+	}
+
+	summary := report.Summary
+	if conclusion == gitops.IntegrationTestStatusNeutralGithub {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s was canceled because the snapshot was superseded by snapshot %s, "+
+			"the check is neutral since the newer snapshot reports the results of the change",
+			snapshot.Name, report.ScenarioName, snapshot.GetAnnotations()[gitops.SnapshotSupersededByAnnotation])
 	}
 
 	cra := &github.CheckRunAdapter{
@@ -194,7 +206,7 @@ func (cru *CheckRunStatusUpdater) createCheckRunAdapterForSnapshot(report TestRe
 		ExternalID: externalID,
 		Conclusion: conclusion,
 		Title:      title,
-		Summary:    report.Summary,
+		Summary:    summary,
 		Text:       report.Text,
 		DetailsURL: detailsURL,
 	}
@@ -264,6 +276,12 @@ type CommitStatusUpdater struct {
 	sha                    string
 	snapshot               *applicationapiv1alpha1.Snapshot
 	allCommitStatusesCache []*ghapi.RepoStatus
+	// createdCommitStatuses tracks commit statuses created by this updater, which aren't in allCommitStatusesCache
+	createdCommitStatuses map[github.CommitStatusAdapter]bool
+	// pullRequestOpenCache caches the state of the pull request, it's fetched only once per updater
+	pullRequestOpenCache *bool
+	// mutex guards the caches and createdCommitStatuses, status may be updated concurrently
+	mutex sync.Mutex
 }
 
 // NewCommitStatusUpdater returns a pointer to initialized CommitStatusUpdater
@@ -277,17 +295,20 @@ func NewCommitStatusUpdater(
 	snapshot *applicationapiv1alpha1.Snapshot,
 ) *CommitStatusUpdater {
 	return &CommitStatusUpdater{
-		ghClient:  ghClient,
-		k8sClient: k8sClient,
-		logger:    logger,
-		owner:     owner,
-		repo:      repo,
-		sha:       sha,
-		snapshot:  snapshot,
+		ghClient:              ghClient,
+		k8sClient:             k8sClient,
+		logger:                logger,
+		owner:                 owner,
+		repo:                  repo,
+		sha:                   sha,
+		snapshot:              snapshot,
+		createdCommitStatuses: map[github.CommitStatusAdapter]bool{},
 	}
 }
 
 func (csu *CommitStatusUpdater) getAllCommitStatuses(ctx context.Context) ([]*ghapi.RepoStatus, error) {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
 	if len(csu.allCommitStatusesCache) == 0 {
 		allCommitStatuses, err := csu.ghClient.GetAllCommitStatusesForRef(ctx, csu.owner, csu.repo, csu.sha)
 		if err != nil {
@@ -300,6 +321,20 @@ func (csu *CommitStatusUpdater) getAllCommitStatuses(ctx context.Context) ([]*gh
 	return csu.allCommitStatusesCache, nil
 }
 
+// isPullRequestOpen returns true if the pull request which triggered the snapshot is neither closed nor merged
+func (csu *CommitStatusUpdater) isPullRequestOpen(ctx context.Context, issueNumber int) (bool, error) {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+	if csu.pullRequestOpenCache == nil {
+		open, err := csu.ghClient.IsPullRequestOpen(ctx, csu.owner, csu.repo, issueNumber)
+		if err != nil {
+			return false, err
+		}
+		csu.pullRequestOpenCache = &open
+	}
+	return *csu.pullRequestOpenCache, nil
+}
+
 // Authenticate Github Client with token secret ref defined in snapshot
 func (csu *CommitStatusUpdater) Authenticate(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
 	token, err := GetPACGitProviderToken(ctx, csu.k8sClient, snapshot)
@@ -328,7 +363,7 @@ func (csu *CommitStatusUpdater) createCommitStatusAdapterForSnapshot(report Test
 
 		csu.logger.Info("TestPipelineRunName is not set for SommitStatus")
 	} else {
-		targetURL = FormatPipelineURL(report.TestPipelineRunName, snapshot.Namespace, *csu.logger)
+		targetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, snapshot.Namespace, *csu.logger)
 	}
 
 	return &github.CommitStatusAdapter{
@@ -354,9 +389,15 @@ func (csu *CommitStatusUpdater) updateStatusInComment(ctx context.Context, repor
 		return err
 	}
 
-	comment, err := FormatComment(report.Summary, report.Text)
+	open, err := csu.isPullRequestOpen(ctx, issueNumber)
 	if err != nil {
-		return fmt.Errorf("failed to generate comment for pull-request %d: %w", issueNumber, err)
+		return fmt.Errorf("error while getting state of pull-request %s: %w", issueNumberStr, err)
+	}
+	if !open {
+		csu.logger.Info("pull-request is already closed or merged, skipping the comment",
+			"snapshot.NameSpace", csu.snapshot.Namespace, "snapshot.Name", csu.snapshot.Name,
+			"scenarioName", report.ScenarioName, "pullRequest", issueNumber)
+		return nil
 	}
 
 	allComments, err := csu.ghClient.GetAllCommentsForPR(ctx, csu.owner, csu.repo, issueNumber)
@@ -364,6 +405,22 @@ func (csu *CommitStatusUpdater) updateStatusInComment(ctx context.Context, repor
 		return fmt.Errorf("error while getting all comments for pull-request %s: %w", issueNumberStr, err)
 	}
 	existingCommentId := csu.ghClient.GetExistingCommentID(allComments, csu.snapshot.Name, report.ScenarioName)
+
+	// carry over the history of previous attempts from the existing comment
+	previousComment := ""
+	if existingCommentId != nil {
+		for _, c := range allComments {
+			if c.GetID() == *existingCommentId {
+				previousComment = c.GetBody()
+				break
+			}
+		}
+	}
+	comment, err := FormatCommentWithHistory(report, previousComment)
+	if err != nil {
+		return fmt.Errorf("failed to generate comment for pull-request %d: %w", issueNumber, err)
+	}
+
 	if existingCommentId == nil {
 		_, err = csu.ghClient.CreateComment(ctx, csu.owner, csu.repo, issueNumber, comment)
 		if err != nil {
@@ -396,6 +453,12 @@ func (csu *CommitStatusUpdater) UpdateStatus(ctx context.Context, report TestRep
 		return nil
 	}
 
+	if csu.isCommitStatusCreated(commitStatus) {
+		csu.logger.Info("commit status for scenario test status of snapshot was already created, only the comment needs to be updated",
+			"snapshot.NameSpace", csu.snapshot.Namespace, "snapshot.Name", csu.snapshot.Name, "scenarioName", report.ScenarioName)
+		return csu.updateStatusInCommentIfNeeded(ctx, report)
+	}
+
 	commitStatusExist, err := csu.ghClient.CommitStatusExists(allCommitStatuses, commitStatus)
 	if err != nil {
 		return err
@@ -408,31 +471,64 @@ func (csu *CommitStatusUpdater) UpdateStatus(ctx context.Context, report TestRep
 		if err != nil {
 			return err
 		}
-		// Create a comment when integration test is neither pending nor inprogress since comment for pending/inprogress is less meaningful and there is commitStatus for all statuses
-		if report.Status != intgteststat.IntegrationTestStatusPending && report.Status != intgteststat.IntegrationTestStatusInProgress {
-			err = csu.updateStatusInComment(ctx, report)
-			if err != nil {
-				return err
-			}
+		csu.setCommitStatusCreated(commitStatus)
+
+		err = csu.updateStatusInCommentIfNeeded(ctx, report)
+		if err != nil {
+			return err
 		}
 	} else {
+		// the comment may not have been updated when the commit status was created, e.g. because of the secondary
+		// rate limit, the status is then reported again by a new reporter which finds the existing commit status
 		csu.logger.Info("found existing commitStatus for scenario test status of snapshot, no need to create new commit status",
 			"snapshot.NameSpace", csu.snapshot.Namespace, "snapshot.Name", csu.snapshot.Name, "scenarioName", report.ScenarioName)
+		return csu.updateStatusInCommentIfNeeded(ctx, report)
 	}
 
 	return nil
 }
 
+// isCommitStatusCreated returns true if the commit status was already created by this updater
+func (csu *CommitStatusUpdater) isCommitStatusCreated(commitStatus *github.CommitStatusAdapter) bool {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+	return csu.createdCommitStatuses[*commitStatus]
+}
+
+// setCommitStatusCreated records that the commit status was created by this updater
+func (csu *CommitStatusUpdater) setCommitStatusCreated(commitStatus *github.CommitStatusAdapter) {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+	csu.createdCommitStatuses[*commitStatus] = true
+}
+
+// updateStatusInCommentIfNeeded creates/updates a comment when integration test is neither pending nor inprogress
+// since comment for pending/inprogress is less meaningful and there is commitStatus for all statuses.
+// No comment is created when the pull request of the snapshot isn't known, e.g. for mirrors of the repository.
+func (csu *CommitStatusUpdater) updateStatusInCommentIfNeeded(ctx context.Context, report TestReport) error {
+	if report.Status == intgteststat.IntegrationTestStatusPending || report.Status == intgteststat.IntegrationTestStatusInProgress {
+		return nil
+	}
+	if !metadata.HasAnnotation(csu.snapshot, gitops.PipelineAsCodePullRequestAnnotation) {
+		csu.logger.Info("pull-request of the snapshot isn't known, only the commitStatus is reported",
+			"snapshot.NameSpace", csu.snapshot.Namespace, "snapshot.Name", csu.snapshot.Name, "scenarioName", report.ScenarioName)
+		return nil
+	}
+	return csu.updateStatusInComment(ctx, report)
+}
+
 // GitHubReporter reports status back to GitHub for a Snapshot.
 type GitHubReporter struct {
 	logger    *logr.Logger
 	k8sClient client.Client
 	client    github.ClientInterface
 	updater   StatusUpdater
+	dryRun    bool
 }
 
 // check if interface has been correctly implemented
 var _ ReporterInterface = (*GitHubReporter)(nil)
+var _ PRMRStateReporter = (*GitHubReporter)(nil)
 
 // GitHubReporterOption is used to extend GitHubReporter with optional parameters.
 type GitHubReporterOption = func(r *GitHubReporter)
@@ -443,6 +539,13 @@ func WithGitHubClient(client github.ClientInterface) GitHubReporterOption {
 	}
 }
 
+// WithGitHubDryRun makes the reporter only log the reports instead of sending them to GitHub
+func WithGitHubDryRun() GitHubReporterOption {
+	return func(r *GitHubReporter) {
+		r.dryRun = true
+	}
+}
+
 // NewGitHubReporter returns a struct implementing the Reporter interface for GitHub
 func NewGitHubReporter(logger logr.Logger, k8sClient client.Client, opts ...GitHubReporterOption) *GitHubReporter {
 	reporter := GitHubReporter{
@@ -475,10 +578,13 @@ func generateCheckRunTitle(state intgteststat.IntegrationTestStatus) (string, er
 		title = "In Progress"
 	case intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated,
 		intgteststat.IntegrationTestStatusDeploymentError_Deprecated,
-		intgteststat.IntegrationTestStatusTestInvalid:
+		intgteststat.IntegrationTestStatusTestInvalid,
+		intgteststat.IntegrationTestStatusTestError:
 		title = "Errored"
 	case intgteststat.IntegrationTestStatusDeleted:
 		title = "Deleted"
+	case intgteststat.IntegrationTestStatusSkipped:
+		title = "Skipped"
 	case intgteststat.IntegrationTestStatusTestPassed:
 		title = "Succeeded"
 	case intgteststat.IntegrationTestStatusTestFail:
@@ -493,14 +599,24 @@ func generateCheckRunTitle(state intgteststat.IntegrationTestStatus) (string, er
 // generateCheckRunConclusion generate a conclusion as the conclusion of CheckRun
 // Can be one of: action_required, cancelled, failure, neutral, success, skipped, stale, timed_out
 // https://docs.github.com/en/rest/checks/runs?apiVersion=2022-11-28#create-a-check-run
-func generateCheckRunConclusion(state intgteststat.IntegrationTestStatus) (string, error) {
+func generateCheckRunConclusion(state intgteststat.IntegrationTestStatus, superseded bool) (string, error) {
 	var conclusion string
 
 	switch state {
 	case intgteststat.IntegrationTestStatusTestFail, intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated,
-		intgteststat.IntegrationTestStatusDeploymentError_Deprecated, intgteststat.IntegrationTestStatusDeleted,
-		intgteststat.IntegrationTestStatusTestInvalid:
+		intgteststat.IntegrationTestStatusDeploymentError_Deprecated, intgteststat.IntegrationTestStatusTestInvalid,
+		intgteststat.IntegrationTestStatusTestError:
 		conclusion = gitops.IntegrationTestStatusFailureGithub
+	case intgteststat.IntegrationTestStatusDeleted:
+		// the tests of a superseded snapshot didn't fail the change, the newer snapshot reports the results
+		if superseded {
+			conclusion = gitops.IntegrationTestStatusNeutralGithub
+			break
+		}
+		// keep consistent with GitLab which reports the deleted tests as canceled
+		conclusion = gitops.IntegrationTestStatusCancelledGithub
+	case intgteststat.IntegrationTestStatusSkipped:
+		conclusion = gitops.IntegrationTestStatusSkippedGithub
 	case intgteststat.IntegrationTestStatusTestPassed:
 		conclusion = gitops.IntegrationTestStatusSuccessGithub
 	case intgteststat.IntegrationTestStatusPending, intgteststat.IntegrationTestStatusInProgress:
@@ -522,9 +638,9 @@ func generateGithubCommitState(state intgteststat.IntegrationTestStatus) (string
 	case intgteststat.IntegrationTestStatusTestFail:
 		commitState = gitops.IntegrationTestStatusFailureGithub
 	case intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated, intgteststat.IntegrationTestStatusDeploymentError_Deprecated,
-		intgteststat.IntegrationTestStatusDeleted, intgteststat.IntegrationTestStatusTestInvalid:
+		intgteststat.IntegrationTestStatusDeleted, intgteststat.IntegrationTestStatusTestInvalid, intgteststat.IntegrationTestStatusTestError:
 		commitState = gitops.IntegrationTestStatusErrorGithub
-	case intgteststat.IntegrationTestStatusTestPassed:
+	case intgteststat.IntegrationTestStatusTestPassed, intgteststat.IntegrationTestStatusSkipped:
 		commitState = gitops.IntegrationTestStatusSuccessGithub
 	case intgteststat.IntegrationTestStatusPending, intgteststat.IntegrationTestStatusInProgress:
 		commitState = gitops.IntegrationTestStatusPendingGithub
@@ -568,6 +684,14 @@ func (r *GitHubReporter) Initialize(ctx context.Context, snapshot *applicationap
 		r.updater = NewCommitStatusUpdater(r.client, r.k8sClient, r.logger, owner, repo, sha, snapshot)
 	}
 
+	if r.dryRun {
+		// validate that the credentials are available without contacting GitHub
+		if err := r.validateCredentials(ctx, snapshot); err != nil {
+			return fmt.Errorf("credentials validation failed: %w", err)
+		}
+		return nil
+	}
+
 	if err := r.updater.Authenticate(ctx, snapshot); err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
@@ -586,8 +710,84 @@ func (r *GitHubReporter) ReportStatus(ctx context.Context, report TestReport) er
 		return fmt.Errorf("reporter is not initialized")
 	}
 
+	if r.dryRun {
+		return r.logDryRunReport(report)
+	}
+
 	if err := r.updater.UpdateStatus(ctx, report); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 	return nil
 }
+
+// GetPRMRState returns the state of the pull request which triggered the snapshot
+func (r *GitHubReporter) GetPRMRState(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) (PRMRState, error) {
+	labels := snapshot.GetLabels()
+	owner, found := labels[gitops.PipelineAsCodeURLOrgLabel]
+	if !found {
+		return "", fmt.Errorf("org label not found %q", gitops.PipelineAsCodeURLOrgLabel)
+	}
+
+	repo, found := labels[gitops.PipelineAsCodeURLRepositoryLabel]
+	if !found {
+		return "", fmt.Errorf("repository label not found %q", gitops.PipelineAsCodeURLRepositoryLabel)
+	}
+
+	pullRequestStr, found := snapshot.GetAnnotations()[gitops.PipelineAsCodePullRequestAnnotation]
+	if !found {
+		return "", fmt.Errorf("pull-request annotation not found %q", gitops.PipelineAsCodePullRequestAnnotation)
+	}
+	pullRequest, err := strconv.Atoi(pullRequestStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert pull request number '%s' to integer: %w", pullRequestStr, err)
+	}
+
+	state, err := r.client.GetPullRequestState(ctx, owner, repo, pullRequest)
+	if err != nil {
+		return "", err
+	}
+
+	switch state {
+	case "merged":
+		return PRMRStateMerged, nil
+	case "closed":
+		return PRMRStateClosed, nil
+	default:
+		return PRMRStateOpened, nil
+	}
+}
+
+// validateCredentials checks that the credentials required by the updater are present and well-formed
+func (r *GitHubReporter) validateCredentials(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	switch updater := r.updater.(type) {
+	case *CheckRunStatusUpdater:
+		_, err := updater.getAppCredentials(ctx, snapshot)
+		return err
+	default:
+		_, err := GetPACGitProviderToken(ctx, r.k8sClient, snapshot)
+		return err
+	}
+}
+
+// logDryRunReport logs the payload which would be sent to GitHub for the given report
+func (r *GitHubReporter) logDryRunReport(report TestReport) error {
+	switch updater := r.updater.(type) {
+	case *CheckRunStatusUpdater:
+		checkRun, err := updater.createCheckRunAdapterForSnapshot(report)
+		if err != nil {
+			return err
+		}
+		r.logger.Info("Dry-run: skipping creation of checkRun on GitHub",
+			"scenario.Name", report.ScenarioName, "conclusion", checkRun.Conclusion, "name", checkRun.Name,
+			"title", checkRun.Title, "summary", checkRun.Summary, "detailsURL", checkRun.DetailsURL)
+	case *CommitStatusUpdater:
+		commitStatus, err := updater.createCommitStatusAdapterForSnapshot(report)
+		if err != nil {
+			return err
+		}
+		r.logger.Info("Dry-run: skipping creation of commitStatus on GitHub",
+			"scenario.Name", report.ScenarioName, "state", commitStatus.State, "context", commitStatus.Context,
+			"description", commitStatus.Description, "targetURL", commitStatus.TargetURL)
+	}
+	return nil
+}
@@ -19,6 +19,10 @@ package status_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -83,6 +87,24 @@ type CreateCommitStatusResult struct {
 	description   string
 	statusContext string
 	targetURL     string
+	calls         int
+	// created are the commit statuses returned by GetAllCommitStatusesForRef after their creation
+	created []*ghapi.RepoStatus
+}
+
+type GetAllCommentsForPRResult struct {
+	comments []*ghapi.IssueComment
+}
+
+type IsPullRequestOpenResult struct {
+	closed bool
+	Error  error
+	calls  int
+}
+
+type GetPullRequestStateResult struct {
+	state string
+	Error error
 }
 
 type MockGitHubClient struct {
@@ -94,6 +116,9 @@ type MockGitHubClient struct {
 	CreateCommentResult
 	CreateCommitStatusResult
 	EditCommentResult
+	GetAllCommentsForPRResult
+	IsPullRequestOpenResult
+	GetPullRequestStateResult
 }
 
 func (c *MockGitHubClient) CreateAppInstallationToken(ctx context.Context, appID int64, installationID int64, privateKey []byte) (string, error) {
@@ -124,8 +149,6 @@ func (c *MockGitHubClient) CommitStatusExists(res []*ghapi.RepoStatus, commitSta
 	for _, cs := range res {
 		if *cs.State == commitStatus.State && *cs.Description == commitStatus.Description && *cs.Context == commitStatus.Context {
 			return true, nil
-		} else {
-			return false, nil
 		}
 	}
 	return false, nil
@@ -143,13 +166,30 @@ func (c *MockGitHubClient) EditComment(ctx context.Context, owner string, repo s
 	return c.EditCommentResult.ID, c.EditCommentResult.Error
 }
 
+func (c *MockGitHubClient) IsPullRequestOpen(ctx context.Context, owner string, repo string, number int) (bool, error) {
+	c.IsPullRequestOpenResult.calls++
+	return !c.IsPullRequestOpenResult.closed, c.IsPullRequestOpenResult.Error
+}
+
+func (c *MockGitHubClient) GetPullRequestState(ctx context.Context, owner string, repo string, number int) (string, error) {
+	return c.GetPullRequestStateResult.state, c.GetPullRequestStateResult.Error
+}
+
 func (c *MockGitHubClient) GetAllCommentsForPR(ctx context.Context, owner string, repo string, pr int) ([]*ghapi.IssueComment, error) {
+	if c.GetAllCommentsForPRResult.comments != nil {
+		return c.GetAllCommentsForPRResult.comments, nil
+	}
 	var id int64 = 20
 	comments := []*ghapi.IssueComment{{ID: &id}}
 	return comments, nil
 }
 
 func (c *MockGitHubClient) GetExistingCommentID(comments []*ghapi.IssueComment, snapshotName, scenarioName string) *int64 {
+	for _, comment := range comments {
+		if comment.Body != nil && strings.Contains(*comment.Body, snapshotName) && strings.Contains(*comment.Body, scenarioName) {
+			return comment.ID
+		}
+	}
 	return nil
 }
 
@@ -160,6 +200,9 @@ func (c *MockGitHubClient) CreateCommitStatus(ctx context.Context, owner string,
 	c.CreateCommitStatusResult.description = description
 	c.CreateCommitStatusResult.statusContext = statusContext
 	c.CreateCommitStatusResult.targetURL = targetURL
+	c.CreateCommitStatusResult.calls++
+	c.CreateCommitStatusResult.created = append(c.CreateCommitStatusResult.created,
+		&ghapi.RepoStatus{ID: &id, State: &state, Context: &statusContext, Description: &description})
 	return c.CreateCommitStatusResult.ID, c.CreateCommitStatusResult.Error
 }
 
@@ -179,7 +222,7 @@ func (c *MockGitHubClient) GetAllCommitStatusesForRef(
 	var description = "Integration test for snapshot snapshot-sample and scenario scenario2 is pending"
 	var statusContext = "test/scenario2"
 	repoStatus := &ghapi.RepoStatus{ID: &id, State: &state, Context: &statusContext, Description: &description}
-	return []*ghapi.RepoStatus{repoStatus}, nil
+	return append([]*ghapi.RepoStatus{repoStatus}, c.CreateCommitStatusResult.created...), nil
 }
 
 var _ = Describe("GitHubReporter", func() {
@@ -295,9 +338,36 @@ var _ = Describe("GitHubReporter", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("only logs the checkRun in dry-run mode", func() {
+			mockGitHubClient = &MockGitHubClient{
+				CreateAppInstallationTokenResult: CreateAppInstallationTokenResult{Error: errors.New("must not be called")},
+			}
+			reporter = status.NewGitHubReporter(logr.Discard(), mockK8sClient, status.WithGitHubClient(mockGitHubClient), status.WithGitHubDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 failed",
+				})).To(Succeed())
+			Expect(mockGitHubClient.CreateCheckRunResult.cra).To(BeNil())
+			Expect(mockGitHubClient.UpdateCheckRunResult.cra).To(BeNil())
+
+			// credentials are still validated
+			delete(secretData, "github-private-key")
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).ToNot(Succeed())
+		})
+
 		DescribeTable(
 			"reports correct github title and conclusion from test statuses",
-			func(teststatus integrationteststatus.IntegrationTestStatus, title string, conclusion string) {
+			func(teststatus integrationteststatus.IntegrationTestStatus, superseded bool, title string, conclusion string) {
+				if superseded {
+					hasSnapshot.Annotations[gitops.SnapshotSupersededByAnnotation] = "snapshot-newer"
+					defer delete(hasSnapshot.Annotations, gitops.SnapshotSupersededByAnnotation)
+				}
 
 				Expect(reporter.ReportStatus(
 					context.TODO(),
@@ -308,16 +378,21 @@ var _ = Describe("GitHubReporter", func() {
 				Expect(mockGitHubClient.CreateCheckRunResult.cra).NotTo(BeNil())
 				Expect(mockGitHubClient.CreateCheckRunResult.cra.Title).To(Equal(title))
 				Expect(mockGitHubClient.CreateCheckRunResult.cra.Conclusion).To(Equal(conclusion))
-
+				if superseded {
+					Expect(mockGitHubClient.CreateCheckRunResult.cra.Summary).To(ContainSubstring("superseded by snapshot snapshot-newer"))
+				}
 			},
-			Entry("Provision error", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, "Errored", gitops.IntegrationTestStatusFailureGithub),
-			Entry("Deployment error", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, "Errored", gitops.IntegrationTestStatusFailureGithub),
-			Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, "Deleted", gitops.IntegrationTestStatusFailureGithub),
-			Entry("Success", integrationteststatus.IntegrationTestStatusTestPassed, "Succeeded", gitops.IntegrationTestStatusSuccessGithub),
-			Entry("Test failure", integrationteststatus.IntegrationTestStatusTestFail, "Failed", gitops.IntegrationTestStatusFailureGithub),
-			Entry("In progress", integrationteststatus.IntegrationTestStatusInProgress, "In Progress", ""),
-			Entry("Pending", integrationteststatus.IntegrationTestStatusPending, "Pending", ""),
-			Entry("Invalid", integrationteststatus.IntegrationTestStatusTestInvalid, "Errored", gitops.IntegrationTestStatusFailureGithub),
+			Entry("Provision error", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, false, "Errored", gitops.IntegrationTestStatusFailureGithub),
+			Entry("Deployment error", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, false, "Errored", gitops.IntegrationTestStatusFailureGithub),
+			Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, false, "Deleted", gitops.IntegrationTestStatusCancelledGithub),
+			Entry("Deleted for a superseded snapshot", integrationteststatus.IntegrationTestStatusDeleted, true, "Deleted", gitops.IntegrationTestStatusNeutralGithub),
+			Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, false, "Skipped", gitops.IntegrationTestStatusSkippedGithub),
+			Entry("Success", integrationteststatus.IntegrationTestStatusTestPassed, false, "Succeeded", gitops.IntegrationTestStatusSuccessGithub),
+			Entry("Test failure", integrationteststatus.IntegrationTestStatusTestFail, false, "Failed", gitops.IntegrationTestStatusFailureGithub),
+			Entry("In progress", integrationteststatus.IntegrationTestStatusInProgress, false, "In Progress", ""),
+			Entry("Pending", integrationteststatus.IntegrationTestStatusPending, false, "Pending", ""),
+			Entry("Invalid", integrationteststatus.IntegrationTestStatusTestInvalid, false, "Errored", gitops.IntegrationTestStatusFailureGithub),
+			Entry("Configuration error", integrationteststatus.IntegrationTestStatusTestError, false, "Errored", gitops.IntegrationTestStatusFailureGithub),
 		)
 
 		It("check if all integration tests statuses are supported", func() {
@@ -358,6 +433,27 @@ var _ = Describe("GitHubReporter", func() {
 			Expect(mockGitHubClient.CreateCheckRunResult.cra.CompletionTime.IsZero()).To(BeFalse())
 		})
 
+		It("links the CheckRun to the logs of the failed task", func() {
+			os.Setenv("CONSOLE_URL", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}")
+			os.Setenv("CONSOLE_URL_TASKLOG", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}/logs/{{ .TaskName }}")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL_TASKLOG")
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:            "test-name",
+					ScenarioName:        "scenario1",
+					SnapshotName:        "snapshot-sample",
+					ComponentName:       "component-sample",
+					Status:              integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+					TestPipelineRunName: "test-pipelinerun",
+					FailedTaskName:      "task1",
+				})).To(Succeed())
+			Expect(mockGitHubClient.CreateCheckRunResult.cra.DetailsURL).To(Equal("https://console.example.com/ns/default/pipelinerun/test-pipelinerun/logs/task1"))
+		})
+
 		It("reports all details of snapshot tests status via CheckRuns for a Snapshot without a component", func() {
 			now := time.Now()
 
@@ -482,7 +578,177 @@ var _ = Describe("GitHubReporter", func() {
 			Expect(mockGitHubClient.CreateCommitStatusResult.state).To(Equal(gitops.IntegrationTestStatusErrorGithub))
 			Expect(mockGitHubClient.CreateCommitStatusResult.description).To(Equal("Integration test for snapshot snapshot-sample and scenario scenario1 failed"))
 			Expect(mockGitHubClient.CreateCommitStatusResult.statusContext).To(Equal("fullname/scenario1"))
-			Expect(mockGitHubClient.CreateCommentResult.body).To(Equal("### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\ndetailed text here"))
+			Expect(mockGitHubClient.CreateCommentResult.body).To(HavePrefix("### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\ndetailed text here"))
+		})
+
+		It("links the commit status to the logs of the failed task", func() {
+			os.Setenv("CONSOLE_URL", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}")
+			os.Setenv("CONSOLE_URL_TASKLOG", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}/logs/{{ .TaskName }}")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL_TASKLOG")
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:            "fullname/scenario1",
+					ScenarioName:        "scenario1",
+					SnapshotName:        "snapshot-sample",
+					ComponentName:       "component-sample",
+					Status:              integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+					TestPipelineRunName: "test-pipelinerun",
+					FailedTaskName:      "task1",
+				})).To(Succeed())
+			Expect(mockGitHubClient.CreateCommitStatusResult.targetURL).To(Equal("https://console.example.com/ns/default/pipelinerun/test-pipelinerun/logs/task1"))
+		})
+
+		It("doesn't create the commit status again when retrying a comment rejected by the secondary rate limit", func() {
+			testReport := status.TestReport{
+				FullName:      "fullname/scenario1",
+				ScenarioName:  "scenario1",
+				SnapshotName:  "snapshot-sample",
+				ComponentName: "component-sample",
+				Status:        integrationteststatus.IntegrationTestStatusTestFail,
+				Summary:       "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+				Text:          "detailed text here",
+			}
+			mockGitHubClient.CreateCommentResult.Error = &github.SecondaryRateLimitError{RetryAfter: 30 * time.Second}
+
+			err := reporter.ReportStatus(context.TODO(), testReport)
+			var rateLimitErr *github.SecondaryRateLimitError
+			Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+			Expect(rateLimitErr.RetryAfter).To(Equal(30 * time.Second))
+			Expect(mockGitHubClient.CreateCommitStatusResult.calls).To(Equal(1))
+
+			mockGitHubClient.CreateCommentResult.Error = nil
+			Expect(reporter.ReportStatus(context.TODO(), testReport)).To(Succeed())
+			Expect(mockGitHubClient.CreateCommitStatusResult.calls).To(Equal(1))
+			Expect(mockGitHubClient.CreateCommentResult.body).To(HavePrefix("### Integration test for snapshot snapshot-sample and scenario scenario1 has failed\n\ndetailed text here"))
+		})
+
+		It("updates the comment when a new reporter retries the report after the secondary rate limit", func() {
+			testReport := status.TestReport{
+				FullName:      "fullname/scenario1",
+				ScenarioName:  "scenario1",
+				SnapshotName:  "snapshot-sample",
+				ComponentName: "component-sample",
+				Status:        integrationteststatus.IntegrationTestStatusTestFail,
+				Summary:       "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+				Text:          "detailed text here",
+			}
+			mockGitHubClient.CreateCommentResult.Error = &github.SecondaryRateLimitError{RetryAfter: 30 * time.Second}
+
+			err := reporter.ReportStatus(context.TODO(), testReport)
+			var rateLimitErr *github.SecondaryRateLimitError
+			Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+			Expect(mockGitHubClient.CreateCommitStatusResult.calls).To(Equal(1))
+
+			// reporters are created for each reconciliation, the retry finds the commit status created by the failed attempt
+			mockGitHubClient.CreateCommentResult.Error = nil
+			mockGitHubClient.CreateCommentResult.body = ""
+			retryReporter := status.NewGitHubReporter(log, mockK8sClient, status.WithGitHubClient(mockGitHubClient))
+			Expect(retryReporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(retryReporter.ReportStatus(context.TODO(), testReport)).To(Succeed())
+			Expect(mockGitHubClient.CreateCommitStatusResult.calls).To(Equal(1))
+			Expect(mockGitHubClient.CreateCommentResult.body).To(HavePrefix("### Integration test for snapshot snapshot-sample and scenario scenario1 has failed\n\ndetailed text here"))
+		})
+
+		It("keeps the history of previous attempts when editing the existing comment", func() {
+			var commentID int64 = 20
+			startTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			for i, testStatus := range []integrationteststatus.IntegrationTestStatus{
+				integrationteststatus.IntegrationTestStatusTestFail,
+				integrationteststatus.IntegrationTestStatusTestPassed,
+				integrationteststatus.IntegrationTestStatusTestFail,
+			} {
+				completionTime := startTime.Add(time.Duration(i) * time.Hour)
+				Expect(reporter.ReportStatus(
+					context.TODO(),
+					status.TestReport{
+						FullName:            "fullname/scenario1",
+						ScenarioName:        "scenario1",
+						SnapshotName:        "snapshot-sample",
+						ComponentName:       "component-sample",
+						Status:              testStatus,
+						Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 " + testStatus.String(),
+						TestPipelineRunName: fmt.Sprintf("test-pipelinerun-%d", i+1),
+						CompletionTime:      &completionTime,
+					})).To(Succeed())
+
+				// the posted comment is the existing comment of the next attempt
+				body := mockGitHubClient.CreateCommentResult.body
+				if i > 0 {
+					Expect(mockGitHubClient.EditCommentResult.ID).To(Equal(commentID))
+					body = mockGitHubClient.EditCommentResult.body
+				}
+				mockGitHubClient.GetAllCommentsForPRResult.comments = []*ghapi.IssueComment{{ID: &commentID, Body: &body}}
+			}
+
+			body := mockGitHubClient.EditCommentResult.body
+			Expect(body).To(HavePrefix("### Integration test for snapshot snapshot-sample and scenario scenario1 TestFail"))
+			Expect(body).To(ContainSubstring("| 1 | 2024-05-01 10:00:00 UTC | TestFail | test-pipelinerun-1 |"))
+			Expect(body).To(ContainSubstring("| 2 | 2024-05-01 11:00:00 UTC | TestPassed | test-pipelinerun-2 |"))
+			Expect(body).To(ContainSubstring("| 3 | 2024-05-01 12:00:00 UTC | TestFail | test-pipelinerun-3 |"))
+			history, err := status.ParseCommentHistory(body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+		})
+
+		It("creates a commit status but skips the comment for a closed pull request", func() {
+			mockGitHubClient.IsPullRequestOpenResult.closed = true
+			for _, scenarioName := range []string{"scenario1", "scenario2"} {
+				Expect(reporter.ReportStatus(
+					context.TODO(),
+					status.TestReport{
+						FullName:     "fullname/" + scenarioName,
+						ScenarioName: scenarioName,
+						SnapshotName: "snapshot-sample",
+						Status:       integrationteststatus.IntegrationTestStatusTestFail,
+						Summary:      "Integration test for snapshot snapshot-sample and scenario " + scenarioName + " has failed",
+						Text:         "detailed text here",
+					})).To(Succeed())
+			}
+			Expect(mockGitHubClient.CreateCommitStatusResult.calls).To(Equal(2))
+			Expect(mockGitHubClient.CreateCommentResult.body).To(BeEmpty())
+			// state of the pull request is fetched only once per reporter
+			Expect(mockGitHubClient.IsPullRequestOpenResult.calls).To(Equal(1))
+			Expect(buf.String()).To(ContainSubstring("pull-request is already closed or merged, skipping the comment"))
+		})
+
+		It("gets the state of the pull request", func() {
+			for githubState, expectedState := range map[string]status.PRMRState{
+				"open":   status.PRMRStateOpened,
+				"closed": status.PRMRStateClosed,
+				"merged": status.PRMRStateMerged,
+			} {
+				mockGitHubClient.GetPullRequestStateResult.state = githubState
+				state, err := reporter.GetPRMRState(context.TODO(), hasSnapshot)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state).To(Equal(expectedState))
+			}
+
+			mockGitHubClient.GetPullRequestStateResult.Error = errors.New("unavailable")
+			_, err := reporter.GetPRMRState(context.TODO(), hasSnapshot)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("only logs the commit status in dry-run mode", func() {
+			reporter = status.NewGitHubReporter(log, mockK8sClient, status.WithGitHubClient(mockGitHubClient), status.WithGitHubDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 failed",
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(mockGitHubClient.CreateCommitStatusResult.state).To(BeEmpty())
+			Expect(mockGitHubClient.CreateCommentResult.body).To(BeEmpty())
+			Expect(buf.String()).To(ContainSubstring("Dry-run: skipping creation of commitStatus on GitHub"))
+			Expect(buf.String()).To(ContainSubstring("state failure"))
 		})
 
 		DescribeTable(
@@ -500,11 +766,13 @@ var _ = Describe("GitHubReporter", func() {
 			Entry("Provision error", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, gitops.IntegrationTestStatusErrorGithub),
 			Entry("Deployment error", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, gitops.IntegrationTestStatusErrorGithub),
 			Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, gitops.IntegrationTestStatusErrorGithub),
+			Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, gitops.IntegrationTestStatusSuccessGithub),
 			Entry("Success", integrationteststatus.IntegrationTestStatusTestPassed, gitops.IntegrationTestStatusSuccessGithub),
 			Entry("Test failure", integrationteststatus.IntegrationTestStatusTestFail, gitops.IntegrationTestStatusFailureGithub),
 			Entry("In progress", integrationteststatus.IntegrationTestStatusInProgress, gitops.IntegrationTestStatusPendingGithub),
 			Entry("Pending", integrationteststatus.IntegrationTestStatusPending, gitops.IntegrationTestStatusPendingGithub),
 			Entry("Invalid", integrationteststatus.IntegrationTestStatusTestInvalid, gitops.IntegrationTestStatusErrorGithub),
+			Entry("Configuration error", integrationteststatus.IntegrationTestStatusTestError, gitops.IntegrationTestStatusErrorGithub),
 		)
 
 		It("check if all integration tests statuses are supported", func() {
@@ -18,41 +18,132 @@ package status
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	gitlab "github.com/xanzy/go-gitlab"
+	v1 "k8s.io/api/core/v1"
+	clienterrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/konflux-ci/integration-service/gitops"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 )
 
+const (
+	// GitLabGroupTokenSecretName is the name of the namespace-level secret holding a GitLab group access token,
+	// used when the PaC Repository of the snapshot doesn't provide a token
+	GitLabGroupTokenSecretName = "gitlab-group-token"
+
+	// GitLabGroupTokenSecretKey is the key of the token in the GitLabGroupTokenSecretName secret
+	GitLabGroupTokenSecretKey = "token"
+)
+
+// mergeTrainWarningMarkerFormat identifies the note warning about the merge train for a snapshot
+const mergeTrainWarningMarkerFormat = "<!-- integration-test-merge-train-warning: snapshot=%s -->"
+
+// forkedMergeRequestNotice is added to the notes of merge requests from forks, which don't get commit statuses
+const forkedMergeRequestNotice = "> [!note]\n> Commit statuses are not available for merge requests from forked projects, this comment is the only report of the integration test.\n\n"
+
+// gitLabListPageSize is the number of items requested per page when listing commit statuses and discussions
+const gitLabListPageSize = 100
+
+// maxGitLabListPages caps the number of pages fetched when listing commit statuses and discussions
+const maxGitLabListPages = 20
+
+// DefaultGitLabRateLimitRetryAfter is used when a rate limited GitLab response doesn't tell when to retry
+const DefaultGitLabRateLimitRetryAfter = time.Minute
+
+// GitLabRateLimitError is returned when a GitLab request was rejected by the rate limit of the instance
+type GitLabRateLimitError struct {
+	// StatusCode of the rejected request
+	StatusCode int
+	// RetryAfter is the delay requested by GitLab before the next request
+	RetryAfter time.Duration
+	err        error
+}
+
+func (e *GitLabRateLimitError) Error() string {
+	return fmt.Sprintf("gitlab rate limit exceeded with status code %d, retry after %s: %v", e.StatusCode, e.RetryAfter, e.err)
+}
+
+func (e *GitLabRateLimitError) Unwrap() error {
+	return e.err
+}
+
+// GitLabCredentialsError is returned when the token of the reporter was rejected by GitLab while initializing the reporter
+type GitLabCredentialsError struct {
+	// StatusCode of the rejected request
+	StatusCode int
+	// Reason describes the problem of the credentials in a human-readable way
+	Reason string
+	err    error
+}
+
+func (e *GitLabCredentialsError) Error() string {
+	return fmt.Sprintf("gitlab rejected the credentials with status code %d, %s: %v", e.StatusCode, e.Reason, e.err)
+}
+
+func (e *GitLabCredentialsError) Unwrap() error {
+	return e.err
+}
+
 type GitLabReporter struct {
 	logger          *logr.Logger
 	k8sClient       client.Client
 	client          *gitlab.Client
 	sha             string
+	ref             string
 	sourceProjectID int
 	targetProjectID int
 	mergeRequest    int
 	snapshot        *applicationapiv1alpha1.Snapshot
+	dryRun          bool
+	// externalStatusChecksUnavailable is set once GitLab reports that external status checks aren't supported
+	externalStatusChecksUnavailable atomic.Bool
+	// mergeTrainWarningMutex serializes the merge train warnings of the merge request, reports are sent concurrently
+	mergeTrainWarningMutex sync.Mutex
 }
 
-func NewGitLabReporter(logger logr.Logger, k8sClient client.Client) *GitLabReporter {
-	return &GitLabReporter{
+// GitLabReporterOption is used to extend GitLabReporter with optional parameters.
+type GitLabReporterOption = func(r *GitLabReporter)
+
+// WithGitLabDryRun makes the reporter only log the reports instead of sending them to GitLab
+func WithGitLabDryRun() GitLabReporterOption {
+	return func(r *GitLabReporter) {
+		r.dryRun = true
+	}
+}
+
+func NewGitLabReporter(logger logr.Logger, k8sClient client.Client, opts ...GitLabReporterOption) *GitLabReporter {
+	reporter := GitLabReporter{
 		logger:    &logger,
 		k8sClient: k8sClient,
 	}
+
+	for _, opt := range opts {
+		opt(&reporter)
+	}
+
+	return &reporter
 }
 
 // check if interface has been correctly implemented
 var _ ReporterInterface = (*GitLabReporter)(nil)
+var _ PRMRStateReporter = (*GitLabReporter)(nil)
 
 // Detect if snapshot has been created from gitlab provider
 func (r *GitLabReporter) Detect(snapshot *applicationapiv1alpha1.Snapshot) bool {
@@ -67,68 +158,283 @@ func (r *GitLabReporter) GetReporterName() string {
 
 // Initialize initializes gitlab reporter
 func (r *GitLabReporter) Initialize(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
-	token, err := GetPACGitProviderToken(ctx, r.k8sClient, snapshot)
+	credentialsSecret, tokenKey, err := r.getCredentialsSecret(ctx, snapshot)
 	if err != nil {
 		r.logger.Error(err, "failed to get token from snapshot",
 			"snapshot.NameSpace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
 		return fmt.Errorf("failed to get PAC token for gitlab provider: %w", err)
 	}
+	token := string(credentialsSecret.Data[tokenKey])
 
 	annotations := snapshot.GetAnnotations()
-	repoUrl, ok := annotations[gitops.PipelineAsCodeRepoURLAnnotation]
-	if !ok {
-		return fmt.Errorf("failed to get value of %s annotation from the snapshot %s", gitops.PipelineAsCodeRepoURLAnnotation, snapshot.Name)
+	apiURL, err := r.getAPIURL(ctx, snapshot)
+	if err != nil {
+		return err
 	}
 
-	burl, err := url.Parse(repoUrl)
+	clientOptions := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(apiURL), gitlab.WithCustomRetry(retryGitLabHTTPCheck)}
+	caBundle, err := getCABundleFromSecret(credentialsSecret)
+	if err != nil {
+		return fmt.Errorf("failed to get CA bundle for gitlab provider: %w", err)
+	}
+	proxy, err := getProxyConfigFromSecret(credentialsSecret)
 	if err != nil {
-		return fmt.Errorf("failed to parse repo-url: %w", err)
+		return fmt.Errorf("failed to get proxy configuration for gitlab provider: %w", err)
+	}
+	if caBundle != nil || proxy != nil {
+		httpClient, err := newGitLabHTTPClient(caBundle, proxy)
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP client for gitlab provider: %w", err)
+		}
+		clientOptions = append(clientOptions, gitlab.WithHTTPClient(httpClient))
 	}
-	apiURL := fmt.Sprintf("%s://%s", burl.Scheme, burl.Host)
 
-	r.client, err = gitlab.NewClient(token, gitlab.WithBaseURL(apiURL))
+	r.client, err = gitlab.NewClient(token, clientOptions...)
 	if err != nil {
 		return fmt.Errorf("failed to create gitlab client: %w", err)
 	}
 
-	labels := snapshot.GetLabels()
-	sha, found := labels[gitops.PipelineAsCodeSHALabel]
-	if !found {
-		return fmt.Errorf("sha label not found %q", gitops.PipelineAsCodeSHALabel)
+	r.sha, err = r.getCommitSHA(snapshot)
+	if err != nil {
+		return err
 	}
-	r.sha = sha
+	r.ref = getCommitStatusRef(snapshot)
 
-	targetProjectIDstr, found := annotations[gitops.PipelineAsCodeTargetProjectIDAnnotation]
-	if !found {
-		return fmt.Errorf("target project ID annotation not found %q", gitops.PipelineAsCodeTargetProjectIDAnnotation)
+	r.targetProjectID, r.sourceProjectID, err = r.getProjectIDs(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// the merge request isn't known for mirrors of the repository, only the commit statuses are reported then
+	r.mergeRequest = 0
+	if mergeRequestStr, found := annotations[gitops.PipelineAsCodePullRequestAnnotation]; found {
+		r.mergeRequest, err = strconv.Atoi(mergeRequestStr)
+		if err != nil {
+			return fmt.Errorf("failed to convert merge request number '%s' to integer: %w", mergeRequestStr, err)
+		}
+	}
+
+	r.snapshot = snapshot
+
+	if !r.dryRun {
+		if err := r.checkCredentials(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	r.targetProjectID, err = strconv.Atoi(targetProjectIDstr)
+// GetPRMRState returns the state of the merge request which triggered the snapshot, locked merge requests
+// are being merged and are considered opened
+func (r *GitLabReporter) GetPRMRState(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) (PRMRState, error) {
+	if r.client == nil {
+		return "", fmt.Errorf("reporter is not initialized")
+	}
+	if !r.hasMergeRequest() {
+		return "", fmt.Errorf("pull-request annotation not found %q", gitops.PipelineAsCodePullRequestAnnotation)
+	}
+
+	mergeRequest, resp, err := r.client.MergeRequests.GetMergeRequest(r.targetProjectID, r.mergeRequest, nil)
 	if err != nil {
-		return fmt.Errorf("failed to convert project ID '%s' to integer: %w", targetProjectIDstr, err)
+		return "", fmt.Errorf("failed to get merge request %d of project %d: %w", r.mergeRequest, r.targetProjectID, wrapGitLabRateLimitError(resp, err))
 	}
 
-	sourceProjectIDstr, found := annotations[gitops.PipelineAsCodeSourceProjectIDAnnotation]
-	if !found {
-		return fmt.Errorf("source project ID annotation not found %q", gitops.PipelineAsCodeSourceProjectIDAnnotation)
+	switch mergeRequest.State {
+	case "merged":
+		return PRMRStateMerged, nil
+	case "closed":
+		return PRMRStateClosed, nil
+	default:
+		return PRMRStateOpened, nil
 	}
+}
 
-	r.sourceProjectID, err = strconv.Atoi(sourceProjectIDstr)
+// checkCredentials verifies that the token can access the target project, so misconfigured credentials are reported
+// early with an actionable error instead of failing later while reporting. Failures which aren't caused by the
+// credentials, like rate limits, are only logged and left to the actual reporting requests.
+func (r *GitLabReporter) checkCredentials() error {
+	_, resp, err := r.client.Projects.GetProject(r.targetProjectID, nil)
+	if err == nil {
+		return nil
+	}
+	if resp == nil || resp.Response == nil {
+		r.logger.Info("failed to check gitlab credentials, continuing", "error", err.Error())
+		return nil
+	}
+
+	var reason string
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		reason = "the token is invalid, expired or revoked"
+	case http.StatusForbidden:
+		reason = "the token lacks the api scope or a role allowing to report commit statuses and comments"
+	case http.StatusNotFound:
+		reason = fmt.Sprintf("the project %d is not visible to the token", r.targetProjectID)
+	default:
+		r.logger.Info("failed to check gitlab credentials, continuing", "statusCode", resp.StatusCode, "error", err.Error())
+		return nil
+	}
+	return &GitLabCredentialsError{StatusCode: resp.StatusCode, Reason: reason, err: err}
+}
+
+// isGitLabRateLimitResponse returns true if the GitLab response rejected the request because of rate limiting,
+// 503 responses are considered rate limited only when they tell when to retry
+func isGitLabRateLimitResponse(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "")
+}
+
+// retryGitLabHTTPCheck retries server errors like the default policy of the gitlab client, but rate limited
+// requests are not retried so the reconciliation can be requeued after the requested delay instead of blocking
+func retryGitLabHTTPCheck(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
 	if err != nil {
-		return fmt.Errorf("failed to convert project ID '%s' to integer: %w", sourceProjectIDstr, err)
+		return false, err
+	}
+	if isGitLabRateLimitResponse(resp) {
+		return false, nil
 	}
+	return resp.StatusCode >= http.StatusInternalServerError, nil
+}
 
-	mergeRequestStr, found := annotations[gitops.PipelineAsCodePullRequestAnnotation]
-	if !found {
-		return fmt.Errorf("pull-request annotation not found %q", gitops.PipelineAsCodePullRequestAnnotation)
+// wrapGitLabRateLimitError wraps the error of a GitLab request rejected by the rate limit into GitLabRateLimitError
+func wrapGitLabRateLimitError(resp *gitlab.Response, err error) error {
+	if resp == nil || resp.Response == nil || !isGitLabRateLimitResponse(resp.Response) {
+		return err
 	}
-	r.mergeRequest, err = strconv.Atoi(mergeRequestStr)
+	return &GitLabRateLimitError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		err:        err,
+	}
+}
+
+// parseRetryAfter parses the value of the Retry-After header given either in seconds or as HTTP date
+func parseRetryAfter(retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if retryTime, err := http.ParseTime(retryAfter); err == nil {
+		if delay := time.Until(retryTime); delay > 0 {
+			return delay
+		}
+	}
+	return DefaultGitLabRateLimitRetryAfter
+}
+
+// getCommitStatusRef returns the branch the commit statuses of the snapshot belong to,
+// the pushed branch for push events and the source branch of the merge request otherwise
+func getCommitStatusRef(snapshot *applicationapiv1alpha1.Snapshot) string {
+	branchAnnotation := gitops.PipelineAsCodeSourceBranchAnnotation
+	if gitops.IsSnapshotCreatedByPACPushEvent(snapshot) {
+		branchAnnotation = gitops.PipelineAsCodeBranchAnnotation
+	}
+	return strings.TrimPrefix(snapshot.GetAnnotations()[branchAnnotation], "refs/heads/")
+}
+
+// newGitLabHTTPClient returns an HTTP client trusting the CAs of the given PEM bundle alongside the system roots
+// and sending the requests through the given proxy. The defaults are kept for nil CA bundle or proxy.
+func newGitLabHTTPClient(caBundle []byte, proxy *proxyConfig) (*http.Client, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("failed to configure the CA bundle and the proxy, the default HTTP transport %T can't be customized", http.DefaultTransport)
+	}
+	transport := defaultTransport.Clone()
+	if caBundle != nil {
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to use CA bundle from secret key %s: no valid PEM encoded certificate found", PACGitProviderCABundleKey)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	if proxy != nil {
+		transport.Proxy = proxy.proxyURL
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// getAPIURL returns the base URL of the GitLab API derived from the repo URL annotation of the snapshot.
+// The URL of the PaC Repository is used when the annotation is missing or malformed.
+func (r *GitLabReporter) getAPIURL(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) (string, error) {
+	repoURL, ok := snapshot.GetAnnotations()[gitops.PipelineAsCodeRepoURLAnnotation]
+	if ok {
+		if apiURL, err := parseAPIURL(repoURL); err == nil {
+			r.logger.Info("using repo-url annotation of the snapshot for gitlab API URL", "apiURL", apiURL)
+			return apiURL, nil
+		}
+	}
+
+	repo, err := GetPACRepository(ctx, r.k8sClient, snapshot)
 	if err != nil {
-		return fmt.Errorf("failed to convert merge request number '%s' to integer: %w", mergeRequestStr, err)
+		return "", fmt.Errorf("failed to get Repository of the snapshot %s: %w", snapshot.Name, err)
+	}
+	if repo != nil {
+		if apiURL, err := parseAPIURL(repo.Spec.URL); err == nil {
+			r.logger.Info("using URL of the Repository for gitlab API URL", "repository.Name", repo.Name, "apiURL", apiURL)
+			return apiURL, nil
+		}
 	}
 
-	r.snapshot = snapshot
-	return nil
+	if ok {
+		return "", fmt.Errorf("failed to parse repo-url %q of the snapshot %s", repoURL, snapshot.Name)
+	}
+	return "", fmt.Errorf("failed to get value of %s annotation from the snapshot %s", gitops.PipelineAsCodeRepoURLAnnotation, snapshot.Name)
+}
+
+// parseAPIURL returns the scheme and host of the given repository URL
+func parseAPIURL(repoURL string) (string, error) {
+	burl, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	if burl.Scheme == "" || burl.Host == "" {
+		return "", fmt.Errorf("repository URL %q has no scheme or host", repoURL)
+	}
+	return fmt.Sprintf("%s://%s", burl.Scheme, burl.Host), nil
+}
+
+// getCredentialsSecret returns the secret holding the GitLab token together with the key of the token.
+// The git provider secret of the PaC Repository is preferred, the namespace-level group token secret
+// is used when the Repository doesn't provide a token.
+func (r *GitLabReporter) getCredentialsSecret(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) (*v1.Secret, string, error) {
+	tried := []string{}
+
+	pacSecret, repoSecret, err := getPACGitProviderSecret(ctx, r.k8sClient, snapshot)
+	switch {
+	case errors.Is(err, errPACGitProviderSecretNotFound):
+		tried = append(tried, err.Error())
+	case err != nil:
+		return nil, "", err
+	case len(pacSecret.Data[repoSecret.Key]) == 0:
+		tried = append(tried, fmt.Sprintf("secret key %s of the Repository secret %s is missing", repoSecret.Key, repoSecret.Name))
+	default:
+		r.logger.Info("using token from the Repository secret for gitlab provider", "secret.Name", repoSecret.Name)
+		return pacSecret, repoSecret.Key, nil
+	}
+
+	groupSecret := &v1.Secret{}
+	err = r.k8sClient.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: GitLabGroupTokenSecretName}, groupSecret)
+	switch {
+	case clienterrors.IsNotFound(err):
+		tried = append(tried, fmt.Sprintf("group token secret %s not found", GitLabGroupTokenSecretName))
+	case err != nil:
+		return nil, "", err
+	case len(groupSecret.Data[GitLabGroupTokenSecretKey]) == 0:
+		tried = append(tried, fmt.Sprintf("secret key %s of the group token secret %s is missing", GitLabGroupTokenSecretKey, GitLabGroupTokenSecretName))
+	default:
+		r.logger.Info("using group token for gitlab provider", "secret.Name", GitLabGroupTokenSecretName)
+		return groupSecret, GitLabGroupTokenSecretKey, nil
+	}
+
+	return nil, "", &NoCredentialsError{Tried: tried}
 }
 
 // setCommitStatus sets commit status to be shown as pipeline run in gitlab view
@@ -144,65 +450,270 @@ func (r *GitLabReporter) setCommitStatus(report TestReport) error {
 		Description: gitlab.Ptr(report.Summary),
 	}
 
+	// attach the commit status to the branch, so GitLab groups it with the other statuses of the branch,
+	// pipeline_id is left unset so the status row is matched by sha, ref and name when reported again
+	if r.ref != "" {
+		opt.Ref = gitlab.Ptr(r.ref)
+	}
+
+	targetURL := ""
 	if report.TestPipelineRunName == "" {
 		r.logger.Info("TestPipelineRunName is not set, cannot add URL to message")
 	} else {
-		url := FormatPipelineURL(report.TestPipelineRunName, r.snapshot.Namespace, *r.logger)
-		opt.TargetURL = gitlab.Ptr(url)
+		targetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, r.snapshot.Namespace, *r.logger)
+		opt.TargetURL = gitlab.Ptr(targetURL)
 	}
 
-	// Special case for gitLab `running` state because of a bug where it can't be updated to the same state again
-	if glState == gitlab.Running {
-		allCommitStatuses, _, err := r.client.Commits.GetCommitStatuses(r.sourceProjectID, r.sha, nil)
-		if err != nil {
-			return fmt.Errorf("error while getting all commitStatuses for sha %s: %w", r.sha, err)
-		}
-		existingCommitStatus := r.GetExistingCommitStatus(allCommitStatuses, report.FullName)
-		if existingCommitStatus != nil && existingCommitStatus.Status == string(gitlab.Running) {
+	allCommitStatuses, err := r.listAllCommitStatuses()
+	if err != nil {
+		return fmt.Errorf("error while getting all commitStatuses for sha %s: %w", r.sha, err)
+	}
+	existingCommitStatus := r.GetExistingCommitStatus(allCommitStatuses, report.FullName)
+	if existingCommitStatus != nil {
+		// Special case for gitLab `running` state because of a bug where it can't be updated to the same state again
+		if glState == gitlab.Running && existingCommitStatus.Status == string(gitlab.Running) {
 			r.logger.Info("Will not update the existing commit status from `running` to `running`",
 				"scenario.name", report.ScenarioName, "commitStatus.ID", existingCommitStatus.ID)
 			return nil
 		}
+		if existingCommitStatus.Status == string(glState) &&
+			existingCommitStatus.Description == report.Summary &&
+			existingCommitStatus.TargetURL == targetURL {
+			r.logger.V(1).Info("existing commit status is up to date, skipping the update",
+				"scenario.name", report.ScenarioName, "commitStatus.ID", existingCommitStatus.ID)
+			return nil
+		}
 	}
 
 	r.logger.Info("creating commit status for scenario test status of snapshot",
 		"scenarioName", report.ScenarioName)
 
-	commitStatus, _, err := r.client.Commits.SetCommitStatus(r.sourceProjectID, r.sha, &opt)
+	commitStatus, resp, err := r.client.Commits.SetCommitStatus(r.sourceProjectID, r.sha, &opt)
 	if err != nil {
-		return fmt.Errorf("failed to set commit status: %w", err)
+		return fmt.Errorf("failed to set commit status: %w", wrapGitLabRateLimitError(resp, err))
 	}
 
 	r.logger.Info("Created gitlab commit status", "scenario.name", report.ScenarioName, "commitStatus.ID", commitStatus.ID, "TargetURL", opt.TargetURL)
 	return nil
 }
 
+// listAllCommitStatuses returns the commit statuses of the reported commit from all pages, up to maxGitLabListPages
+func (r *GitLabReporter) listAllCommitStatuses() ([]*gitlab.CommitStatus, error) {
+	allCommitStatuses := []*gitlab.CommitStatus{}
+	opt := &gitlab.GetCommitStatusesOptions{ListOptions: gitlab.ListOptions{PerPage: gitLabListPageSize, Page: 1}}
+	for page := 0; page < maxGitLabListPages; page++ {
+		commitStatuses, resp, err := r.client.Commits.GetCommitStatuses(r.sourceProjectID, r.sha, opt)
+		if err != nil {
+			return nil, wrapGitLabRateLimitError(resp, err)
+		}
+		allCommitStatuses = append(allCommitStatuses, commitStatuses...)
+		if resp.NextPage == 0 {
+			return allCommitStatuses, nil
+		}
+		opt.Page = resp.NextPage
+	}
+
+	r.logger.Info("reached the maximum number of pages while listing commit statuses", "sha", r.sha, "maxPages", maxGitLabListPages)
+	return allCommitStatuses, nil
+}
+
+// listAllDiscussions returns the discussions of the merge request from all pages, up to maxGitLabListPages
+func (r *GitLabReporter) listAllDiscussions() ([]*gitlab.Discussion, error) {
+	allDiscussions := []*gitlab.Discussion{}
+	opt := &gitlab.ListMergeRequestDiscussionsOptions{PerPage: gitLabListPageSize, Page: 1}
+	for page := 0; page < maxGitLabListPages; page++ {
+		discussions, resp, err := r.client.Discussions.ListMergeRequestDiscussions(r.targetProjectID, r.mergeRequest, opt)
+		if err != nil {
+			return nil, wrapGitLabRateLimitError(resp, err)
+		}
+		allDiscussions = append(allDiscussions, discussions...)
+		if resp.NextPage == 0 {
+			return allDiscussions, nil
+		}
+		opt.Page = resp.NextPage
+	}
+
+	r.logger.Info("reached the maximum number of pages while listing discussions", "mergeRequest", r.mergeRequest, "maxPages", maxGitLabListPages)
+	return allDiscussions, nil
+}
+
+// getProjectIDs returns the IDs of the target and source projects of the merge request from the annotations of the snapshot.
+// Older PaC versions don't set the annotations, the target project is then looked up by the path of the repo URL and
+// used as the source project as well. The annotation is preferred when it doesn't match the looked up project.
+// The project isn't looked up in dry-run mode, the missing IDs are left unset then.
+func (r *GitLabReporter) getProjectIDs(snapshot *applicationapiv1alpha1.Snapshot) (int, int, error) {
+	annotations := snapshot.GetAnnotations()
+	targetProjectID, targetFound, err := parseProjectIDAnnotation(annotations, gitops.PipelineAsCodeTargetProjectIDAnnotation)
+	if err != nil {
+		return 0, 0, err
+	}
+	sourceProjectID, sourceFound, err := parseProjectIDAnnotation(annotations, gitops.PipelineAsCodeSourceProjectIDAnnotation)
+	if err != nil {
+		return 0, 0, err
+	}
+	if targetFound && sourceFound {
+		return targetProjectID, sourceProjectID, nil
+	}
+
+	projectPath, err := getProjectPath(annotations[gitops.PipelineAsCodeRepoURLAnnotation])
+	if err != nil {
+		return 0, 0, fmt.Errorf("project ID annotations %q and %q not found and the project can't be derived from the repo-url annotation: %w",
+			gitops.PipelineAsCodeTargetProjectIDAnnotation, gitops.PipelineAsCodeSourceProjectIDAnnotation, err)
+	}
+	if r.dryRun {
+		// dry-run makes no calls to GitLab, the reports don't need the project IDs
+		r.logger.Info("Dry-run: skipping the lookup of the gitlab project, project ID annotations are missing",
+			"projectPath", projectPath)
+		if !sourceFound {
+			sourceProjectID = targetProjectID
+		}
+		return targetProjectID, sourceProjectID, nil
+	}
+	project, resp, err := r.client.Projects.GetProject(projectPath, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to look up gitlab project %s: %w", projectPath, wrapGitLabRateLimitError(resp, err))
+	}
+	r.logger.Info("using project looked up by the path of the repo URL, project ID annotations are missing",
+		"projectPath", projectPath, "project.ID", project.ID)
+
+	if !targetFound {
+		targetProjectID = project.ID
+	} else if targetProjectID != project.ID {
+		r.logger.Info("target project ID annotation doesn't match the project of the repo URL, using the annotation",
+			"projectPath", projectPath, "project.ID", project.ID, "targetProjectID", targetProjectID)
+	}
+	if !sourceFound {
+		sourceProjectID = targetProjectID
+	}
+	return targetProjectID, sourceProjectID, nil
+}
+
+// parseProjectIDAnnotation returns the project ID stored in the given annotation and whether the annotation was found
+func parseProjectIDAnnotation(annotations map[string]string, annotation string) (int, bool, error) {
+	projectIDstr, found := annotations[annotation]
+	if !found {
+		return 0, false, nil
+	}
+	projectID, err := strconv.Atoi(projectIDstr)
+	if err != nil {
+		return 0, true, fmt.Errorf("failed to convert project ID '%s' to integer: %w", projectIDstr, err)
+	}
+	return projectID, true, nil
+}
+
+// getProjectPath returns the path of the project, e.g. group/subgroup/project, from the given repo URL
+func getProjectPath(repoURL string) (string, error) {
+	burl, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	projectPath := strings.TrimSuffix(strings.Trim(burl.Path, "/"), ".git")
+	if !strings.Contains(projectPath, "/") {
+		return "", fmt.Errorf("repository URL %q has no project path", repoURL)
+	}
+	return projectPath, nil
+}
+
+// getCommitSHA returns the commit SHA to report the statuses for. The full-length SHA from the commit_sha annotation
+// is preferred over the sha label, which may be stale, a warning is logged when they don't match.
+func (r *GitLabReporter) getCommitSHA(snapshot *applicationapiv1alpha1.Snapshot) (string, error) {
+	labelSHA := snapshot.GetLabels()[gitops.PipelineAsCodeSHALabel]
+	annotationSHA := snapshot.GetAnnotations()[gitops.BuildCommitSHAAnnotation]
+	if annotationSHA == "" {
+		if labelSHA == "" {
+			return "", fmt.Errorf("neither commit sha annotation %q nor sha label %q found",
+				gitops.BuildCommitSHAAnnotation, gitops.PipelineAsCodeSHALabel)
+		}
+		return labelSHA, nil
+	}
+
+	// label values are limited to 63 characters, so the label may hold only a prefix of the annotation
+	if labelSHA != "" && !strings.HasPrefix(annotationSHA, labelSHA) {
+		r.logger.Info("sha label of the snapshot doesn't match the commit sha annotation, using the annotation",
+			"snapshot.Name", snapshot.Name, "label", labelSHA, "annotation", annotationSHA)
+	}
+	return annotationSHA, nil
+}
+
+// hasMergeRequest returns true if the merge request of the snapshot is known, it isn't known e.g. for mirrors
+func (r *GitLabReporter) hasMergeRequest() bool {
+	return r.mergeRequest != 0
+}
+
+// isForkedMergeRequest returns true if the merge request comes from a fork of the target project
+func (r *GitLabReporter) isForkedMergeRequest() bool {
+	return r.sourceProjectID != r.targetProjectID
+}
+
 // updateStatusInComment will create/update a comment in the MR which creates snapshot
 func (r *GitLabReporter) updateStatusInComment(report TestReport) error {
-	comment, err := FormatComment(report.Summary, report.Text)
+	text := formatReportCommentText(report)
+	if r.isForkedMergeRequest() {
+		text = forkedMergeRequestNotice + text
+	}
+	comment, err := FormatComment(report.Summary, text, report.SnapshotName, report.ScenarioName)
 	if err != nil {
 		return fmt.Errorf("failed to generate comment for merge-request %d: %w", r.mergeRequest, err)
 	}
 
-	allNotes, _, err := r.client.Notes.ListMergeRequestNotes(r.targetProjectID, r.mergeRequest, nil)
+	allDiscussions, err := r.listAllDiscussions()
 	if err != nil {
 		return fmt.Errorf("error while getting all comments for merge-request %d: %w", r.mergeRequest, err)
 	}
-	existingCommentId := r.GetExistingNoteID(allNotes, report.ScenarioName, report.SnapshotName)
-	if existingCommentId == nil {
-		noteOptions := gitlab.CreateMergeRequestNoteOptions{Body: &comment}
-		_, _, err := r.client.Notes.CreateMergeRequestNote(r.targetProjectID, r.mergeRequest, &noteOptions)
-		if err != nil {
-			return fmt.Errorf("error while creating comment for merge-request %d: %w", r.mergeRequest, err)
-		}
-	} else {
-		noteOptions := gitlab.UpdateMergeRequestNoteOptions{Body: &comment}
-		_, _, err := r.client.Notes.UpdateMergeRequestNote(r.targetProjectID, r.mergeRequest, *existingCommentId, &noteOptions)
+	existingDiscussion, existingNote := r.getExistingDiscussion(allDiscussions, report.ScenarioName, report.SnapshotName)
+	if existingNote == nil {
+		return r.createComment(report, comment)
+	}
+
+	noteOptions := gitlab.UpdateMergeRequestNoteOptions{Body: &comment}
+	_, resp, err := r.client.Notes.UpdateMergeRequestNote(r.targetProjectID, r.mergeRequest, existingNote.ID, &noteOptions)
+	if err != nil {
+		return fmt.Errorf("error while updating comment %d for merge-request %d: %w", existingNote.ID, r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+
+	if report.Status == intgteststat.IntegrationTestStatusTestPassed && existingNote.Resolvable && !existingNote.Resolved {
+		return r.resolveFailureDiscussion(report, existingDiscussion.ID)
+	}
+
+	return nil
+}
+
+// createComment creates a new comment in the MR, failures are reported as resolvable discussions
+// so they can be resolved once the scenario passes
+func (r *GitLabReporter) createComment(report TestReport, comment string) error {
+	if isFailedReport(report) {
+		discussionOptions := gitlab.CreateMergeRequestDiscussionOptions{Body: &comment}
+		_, resp, err := r.client.Discussions.CreateMergeRequestDiscussion(r.targetProjectID, r.mergeRequest, &discussionOptions)
 		if err != nil {
-			return fmt.Errorf("error while creating comment for merge-request %d: %w", r.mergeRequest, err)
+			return fmt.Errorf("error while creating discussion for merge-request %d: %w", r.mergeRequest, wrapGitLabRateLimitError(resp, err))
 		}
+		return nil
 	}
 
+	noteOptions := gitlab.CreateMergeRequestNoteOptions{Body: &comment}
+	_, resp, err := r.client.Notes.CreateMergeRequestNote(r.targetProjectID, r.mergeRequest, &noteOptions)
+	if err != nil {
+		return fmt.Errorf("error while creating comment for merge-request %d: %w", r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+	return nil
+}
+
+// resolveFailureDiscussion replies to the discussion of a previous failure of the scenario and resolves it
+func (r *GitLabReporter) resolveFailureDiscussion(report TestReport, discussionID string) error {
+	reply := fmt.Sprintf("Integration test scenario %s is now passing.", report.ScenarioName)
+	replyOptions := gitlab.AddMergeRequestDiscussionNoteOptions{Body: &reply}
+	_, resp, err := r.client.Discussions.AddMergeRequestDiscussionNote(r.targetProjectID, r.mergeRequest, discussionID, &replyOptions)
+	if err != nil {
+		return fmt.Errorf("error while replying to discussion %s for merge-request %d: %w", discussionID, r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+
+	resolveOptions := gitlab.ResolveMergeRequestDiscussionOptions{Resolved: gitlab.Ptr(true)}
+	_, resp, err = r.client.Discussions.ResolveMergeRequestDiscussion(r.targetProjectID, r.mergeRequest, discussionID, &resolveOptions)
+	if err != nil {
+		return fmt.Errorf("error while resolving discussion %s for merge-request %d: %w", discussionID, r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+
+	r.logger.Info("resolved discussion of the previous failure", "scenarioName", report.ScenarioName, "discussionID", discussionID)
 	return nil
 }
 
@@ -219,16 +730,43 @@ func (r *GitLabReporter) GetExistingCommitStatus(commitStatuses []*gitlab.Commit
 	return nil
 }
 
-// GetExistingNoteID returns existing GitLab note for the scenario of ref.
-func (r *GitLabReporter) GetExistingNoteID(notes []*gitlab.Note, scenarioName, snapshotName string) *int {
-	for _, note := range notes {
+// GetExistingNoteID returns the ID of the existing GitLab note for the scenario of ref together with
+// the ID of the discussion started by the note.
+func (r *GitLabReporter) GetExistingNoteID(discussions []*gitlab.Discussion, scenarioName, snapshotName string) (*int, string) {
+	discussion, note := r.getExistingDiscussion(discussions, scenarioName, snapshotName)
+	if note == nil {
+		return nil, ""
+	}
+	return &note.ID, discussion.ID
+}
+
+// getExistingDiscussion returns the existing GitLab discussion for the scenario of ref and the note starting it.
+// Notes carrying the hidden marker of the scenario and snapshot are preferred, notes created
+// before the marker was introduced are matched by the scenario and snapshot names in their body.
+func (r *GitLabReporter) getExistingDiscussion(discussions []*gitlab.Discussion, scenarioName, snapshotName string) (*gitlab.Discussion, *gitlab.Note) {
+	marker := FormatCommentMarker(snapshotName, scenarioName)
+	for _, discussion := range discussions {
+		if len(discussion.Notes) > 0 && strings.Contains(discussion.Notes[0].Body, marker) {
+			r.logger.Info("found note ID with a matching marker", "scenarioName", scenarioName, "noteID", discussion.Notes[0].ID)
+			return discussion, discussion.Notes[0]
+		}
+	}
+	for _, discussion := range discussions {
+		if len(discussion.Notes) == 0 {
+			continue
+		}
+		note := discussion.Notes[0]
+		if strings.Contains(note.Body, commentReportMarkerPrefix) {
+			// the note belongs to another scenario or snapshot
+			continue
+		}
 		if strings.Contains(note.Body, snapshotName) && strings.Contains(note.Body, scenarioName) {
-			r.logger.Info("found note ID with a matching scenarioName", "scenarioName", scenarioName, "noteID", &note.ID)
-			return &note.ID
+			r.logger.Info("found note ID with a matching scenarioName", "scenarioName", scenarioName, "noteID", note.ID)
+			return discussion, note
 		}
 	}
 	r.logger.Info("found no note with a matching scenarioName", "scenarioName", scenarioName)
-	return nil
+	return nil, nil
 }
 
 // ReportStatus reports test result to gitlab
@@ -237,10 +775,41 @@ func (r *GitLabReporter) ReportStatus(ctx context.Context, report TestReport) er
 		return fmt.Errorf("gitlab reporter is not initialized")
 	}
 
-	if err := r.setCommitStatus(report); err != nil {
+	if r.dryRun {
+		return r.logDryRunReport(report)
+	}
+
+	if r.isForkedMergeRequest() {
+		// the bot token usually can't access the source project of a fork, the note on the target project is the only feedback
+		r.logger.Info("Won't create/update commitStatus due to the access limitation for forked repo",
+			"sourceProjectID", r.sourceProjectID, "targetProjectID", r.targetProjectID, "scenarioName", report.ScenarioName)
+	} else if err := r.setCommitStatus(report); err != nil {
 		return fmt.Errorf("failed to set gitlab commit status: %w", err)
 	}
 
+	if !r.hasMergeRequest() {
+		r.logger.Info("merge request of the snapshot isn't known, only the commitStatus is reported", "scenarioName", report.ScenarioName)
+		return nil
+	}
+
+	var mergeTrain *gitlab.MergeTrain
+	if r.isMergeTrainCheckEnabled() && isFailedReport(report) {
+		var err error
+		mergeTrain, err = r.getMergeTrain()
+		if err != nil {
+			return err
+		}
+		if mergeTrain != nil {
+			report.Text = fmt.Sprintf("%s\n\n%s", report.Text, formatMergeTrainStatus(mergeTrain))
+		}
+	}
+
+	if r.isExternalStatusCheckEnabled() {
+		if err := r.setExternalStatusCheck(report); err != nil {
+			return fmt.Errorf("failed to set gitlab external status check: %w", err)
+		}
+	}
+
 	// Create a note when integration test is neither pending nor inprogress since comment for pending/inprogress is less meaningful
 	if report.Status != intgteststat.IntegrationTestStatusPending && report.Status != intgteststat.IntegrationTestStatusInProgress {
 		err := r.updateStatusInComment(report)
@@ -249,6 +818,155 @@ func (r *GitLabReporter) ReportStatus(ctx context.Context, report TestReport) er
 		}
 	}
 
+	if mergeTrain != nil {
+		return r.warnAboutMergeTrain(report, mergeTrain)
+	}
+
+	return nil
+}
+
+// isExternalStatusCheckEnabled returns true if external status checks of the merge request should be responded to
+func (r *GitLabReporter) isExternalStatusCheckEnabled() bool {
+	return metadata.HasAnnotationWithValue(r.snapshot, gitops.ExternalStatusCheckAnnotation, "true") &&
+		!r.externalStatusChecksUnavailable.Load()
+}
+
+// setExternalStatusCheck responds to the external status check of the merge request named after the report
+// once the scenario reaches a terminal state. External status checks are disabled for the reporter when GitLab
+// doesn't support them, e.g. for instances without the Ultimate tier.
+func (r *GitLabReporter) setExternalStatusCheck(report TestReport) error {
+	glState, err := GenerateGitlabCommitState(report.Status)
+	if err != nil {
+		return err
+	}
+	var checkStatus string
+	switch glState {
+	case gitlab.Success:
+		checkStatus = "passed"
+	case gitlab.Failed:
+		checkStatus = "failed"
+	default:
+		return nil
+	}
+
+	statusChecks, resp, err := r.client.ExternalStatusChecks.ListMergeStatusChecks(r.targetProjectID, r.mergeRequest, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			r.logger.Info("external status checks are not available for the project, disabling them",
+				"targetProjectID", r.targetProjectID)
+			r.externalStatusChecksUnavailable.Store(true)
+			return nil
+		}
+		return fmt.Errorf("error while getting external status checks for merge-request %d: %w", r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+
+	for _, statusCheck := range statusChecks {
+		if statusCheck.Name != report.FullName {
+			continue
+		}
+		opt := gitlab.SetExternalStatusCheckStatusOptions{
+			SHA:                   &r.sha,
+			ExternalStatusCheckID: &statusCheck.ID,
+			Status:                &checkStatus,
+		}
+		if resp, err := r.client.ExternalStatusChecks.SetExternalStatusCheckStatus(r.targetProjectID, r.mergeRequest, &opt); err != nil {
+			return fmt.Errorf("error while setting external status check %d for merge-request %d: %w", statusCheck.ID, r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+		}
+		r.logger.Info("Set gitlab external status check", "scenario.name", report.ScenarioName,
+			"externalStatusCheck.ID", statusCheck.ID, "status", checkStatus)
+		return nil
+	}
+
+	r.logger.V(1).Info("no external status check of the merge request matches the report", "name", report.FullName)
+	return nil
+}
+
+// isFailedReport returns true if the report is shown as failure on GitLab
+func isFailedReport(report TestReport) bool {
+	glState, _ := GenerateGitlabCommitState(report.Status)
+	return glState == gitlab.Failed
+}
+
+// isMergeTrainCheckEnabled returns true if failures of the snapshot should be checked against GitLab merge trains,
+// only composite snapshots are checked as they detect cross-component failures
+func (r *GitLabReporter) isMergeTrainCheckEnabled() bool {
+	return metadata.HasAnnotationWithValue(r.snapshot, gitops.MergeTrainCheckAnnotation, "true") &&
+		metadata.HasLabelWithValue(r.snapshot, gitops.SnapshotTypeLabel, gitops.SnapshotCompositeType)
+}
+
+// getMergeTrain returns the merge train the merge request is on, nil is returned when the merge request
+// isn't on a merge train or merge trains aren't available on the GitLab instance
+func (r *GitLabReporter) getMergeTrain() (*gitlab.MergeTrain, error) {
+	mergeTrain, resp, err := r.client.MergeTrains.GetMergeRequestOnAMergeTrain(r.targetProjectID, r.mergeRequest)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			r.logger.Info("merge trains are not available for the project, skipping the merge train check",
+				"targetProjectID", r.targetProjectID, "mergeRequest", r.mergeRequest)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error while getting merge train of merge-request %d: %w", r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+	if mergeTrain.MergedAt != nil {
+		return nil, nil
+	}
+	return mergeTrain, nil
+}
+
+// formatMergeTrainStatus returns the status of the merge train to be shown in the report text
+func formatMergeTrainStatus(mergeTrain *gitlab.MergeTrain) string {
+	return fmt.Sprintf("**Merge train**: the merge request is on the merge train of branch `%s` with status `%s`.",
+		mergeTrain.TargetBranch, mergeTrain.Status)
+}
+
+// warnAboutMergeTrain posts a note warning that the merge train should be stopped, the note is posted only once for the snapshot
+func (r *GitLabReporter) warnAboutMergeTrain(report TestReport, mergeTrain *gitlab.MergeTrain) error {
+	// the check for an existing warning and its creation must not interleave with another failed report
+	r.mergeTrainWarningMutex.Lock()
+	defer r.mergeTrainWarningMutex.Unlock()
+
+	marker := fmt.Sprintf(mergeTrainWarningMarkerFormat, r.snapshot.Name)
+	allDiscussions, err := r.listAllDiscussions()
+	if err != nil {
+		return fmt.Errorf("error while getting all comments for merge-request %d: %w", r.mergeRequest, err)
+	}
+	for _, discussion := range allDiscussions {
+		if len(discussion.Notes) > 0 && strings.Contains(discussion.Notes[0].Body, marker) {
+			r.logger.Info("merge train warning was already posted", "snapshot.Name", r.snapshot.Name)
+			return nil
+		}
+	}
+
+	warning := fmt.Sprintf("### :warning: The merge train should be stopped\n\n"+
+		"Integration test scenario %s failed for the composite snapshot %s while the merge request is on the merge train of branch `%s`. "+
+		"Remove the merge request from the merge train to avoid merging a cross-component failure.\n\n%s",
+		report.ScenarioName, r.snapshot.Name, mergeTrain.TargetBranch, marker)
+	noteOptions := gitlab.CreateMergeRequestNoteOptions{Body: &warning}
+	_, resp, err := r.client.Notes.CreateMergeRequestNote(r.targetProjectID, r.mergeRequest, &noteOptions)
+	if err != nil {
+		return fmt.Errorf("error while creating merge train warning for merge-request %d: %w", r.mergeRequest, wrapGitLabRateLimitError(resp, err))
+	}
+	r.logger.Info("posted merge train warning", "snapshot.Name", r.snapshot.Name, "scenarioName", report.ScenarioName)
+	return nil
+}
+
+// logDryRunReport logs the payload which would be sent to GitLab for the given report
+func (r *GitLabReporter) logDryRunReport(report TestReport) error {
+	glState, err := GenerateGitlabCommitState(report.Status)
+	if err != nil {
+		return fmt.Errorf("failed to generate gitlab state: %w", err)
+	}
+
+	targetURL := ""
+	if report.TestPipelineRunName != "" {
+		targetURL = FormatPipelineTaskURL(report.TestPipelineRunName, report.FailedTaskName, r.snapshot.Namespace, *r.logger)
+	}
+
+	r.logger.Info("Dry-run: skipping creation of commit status on GitLab",
+		"scenario.Name", report.ScenarioName, "state", glState, "name", report.FullName,
+		"description", report.Summary, "targetURL", targetURL)
 	return nil
 }
 
@@ -263,10 +981,13 @@ func GenerateGitlabCommitState(state intgteststat.IntegrationTestStatus) (gitlab
 		glState = gitlab.Running
 	case intgteststat.IntegrationTestStatusEnvironmentProvisionError_Deprecated,
 		intgteststat.IntegrationTestStatusDeploymentError_Deprecated,
-		intgteststat.IntegrationTestStatusTestInvalid:
+		intgteststat.IntegrationTestStatusTestInvalid,
+		intgteststat.IntegrationTestStatusTestError:
 		glState = gitlab.Failed
 	case intgteststat.IntegrationTestStatusDeleted:
 		glState = gitlab.Canceled
+	case intgteststat.IntegrationTestStatusSkipped:
+		glState = gitlab.Skipped
 	case intgteststat.IntegrationTestStatusTestPassed:
 		glState = gitlab.Success
 	case intgteststat.IntegrationTestStatusTestFail:
@@ -20,10 +20,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -46,8 +53,9 @@ var _ = Describe("GitLabReporter", func() {
 	const (
 		repoUrl         = "https://gitlab.com/example/example"
 		digest          = "12a4a35ccd08194595179815e4646c3a6c08bb77"
-		sourceProjectID = "123"
+		sourceProjectID = "456"
 		targetProjectID = "456"
+		forkProjectID   = "123"
 		mergeRequest    = "45"
 	)
 
@@ -82,6 +90,8 @@ var _ = Describe("GitLabReporter", func() {
 					"pac.test.appstudio.openshift.io/target-project-id": targetProjectID,
 					"pac.test.appstudio.openshift.io/source-project-id": sourceProjectID,
 					"pac.test.appstudio.openshift.io/pull-request":      mergeRequest,
+					"pac.test.appstudio.openshift.io/source-branch":     "feature-branch",
+					"pac.test.appstudio.openshift.io/branch":            "main",
 				},
 			},
 			Spec: applicationapiv1alpha1.SnapshotSpec{
@@ -127,12 +137,13 @@ var _ = Describe("GitLabReporter", func() {
 	Context("when provided Gitlab webhook integration credentials", func() {
 
 		var (
-			secretData    map[string][]byte
-			repo          pacv1alpha1.Repository
-			reporter      *status.GitLabReporter
-			defaultAPIURL = "/api/v4"
-			mux           *http.ServeMux
-			server        *httptest.Server
+			secretData        map[string][]byte
+			repo              pacv1alpha1.Repository
+			reporter          *status.GitLabReporter
+			defaultAPIURL     = "/api/v4"
+			mux               *http.ServeMux
+			server            *httptest.Server
+			projectStatusCode int
 		)
 
 		BeforeEach(func() {
@@ -175,6 +186,12 @@ var _ = Describe("GitLabReporter", func() {
 				"example-token": []byte("example-personal-access-token"),
 			}
 
+			projectStatusCode = http.StatusOK
+			mux.HandleFunc(fmt.Sprintf("/projects/%s", targetProjectID), func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(projectStatusCode)
+				fmt.Fprintf(rw, "{}")
+			})
+
 			reporter = status.NewGitLabReporter(log, mockK8sClient)
 
 			err := reporter.Initialize(context.TODO(), hasSnapshot)
@@ -195,17 +212,299 @@ var _ = Describe("GitLabReporter", func() {
 			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).ToNot(Succeed())
 		},
 			Entry("Missing repo_url", gitops.PipelineAsCodeRepoURLAnnotation, false),
-			Entry("Missing SHA", gitops.PipelineAsCodeSHALabel, true),
 			Entry("Missing target project ID", gitops.PipelineAsCodeTargetProjectIDAnnotation, false),
 			Entry("Missing source project ID", gitops.PipelineAsCodeSourceProjectIDAnnotation, false),
 		)
 
+		DescribeTable("fails to initialize with an actionable error when GitLab rejects the credentials", func(statusCode int, reason string) {
+			projectStatusCode = statusCode
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			var credentialsErr *status.GitLabCredentialsError
+			Expect(errors.As(err, &credentialsErr)).To(BeTrue())
+			Expect(credentialsErr.StatusCode).To(Equal(statusCode))
+			Expect(credentialsErr.Reason).To(Equal(reason))
+		},
+			Entry("bad token", http.StatusUnauthorized, "the token is invalid, expired or revoked"),
+			Entry("insufficient scope", http.StatusForbidden, "the token lacks the api scope or a role allowing to report commit statuses and comments"),
+			Entry("project not visible", http.StatusNotFound, "the project 456 is not visible to the token"),
+		)
+
+		DescribeTable("gets the state of the merge request", func(gitlabState string, expectedState status.PRMRState) {
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(rw, `{"iid": %s, "state": "%s"}`, mergeRequest, gitlabState)
+			})
+			state, err := reporter.GetPRMRState(context.TODO(), hasSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(expectedState))
+		},
+			Entry("opened", "opened", status.PRMRStateOpened),
+			Entry("locked while being merged", "locked", status.PRMRStateOpened),
+			Entry("closed", "closed", status.PRMRStateClosed),
+			Entry("merged", "merged", status.PRMRStateMerged),
+		)
+
+		It("initializes when the credentials check is rate limited", func() {
+			buf.Reset()
+			projectStatusCode = http.StatusTooManyRequests
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("failed to check gitlab credentials, continuing"))
+		})
+
+		It("reports for the project looked up by the repo URL when the project ID annotations are missing", func() {
+			buf.Reset()
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeTargetProjectIDAnnotation)
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeSourceProjectIDAnnotation)
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL + "/example-group/example-subgroup/example-project.git"
+			repo.Spec.URL = hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation]
+			projectLookups := 0
+			mux.HandleFunc("/projects/example-group/example-subgroup/example-project", func(rw http.ResponseWriter, r *http.Request) {
+				projectLookups++
+				fmt.Fprintf(rw, `{"id": %s}`, targetProjectID)
+			})
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(projectLookups).To(Equal(1))
+			Expect(buf.String()).To(ContainSubstring("using project looked up by the path of the repo URL, project ID annotations are missing"))
+
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 has failed"
+			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, summary)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      summary,
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(projectLookups).To(Equal(1))
+			Expect(buf.String()).NotTo(ContainSubstring("Won't create/update commitStatus due to the access limitation for forked repo"))
+		})
+
+		It("prefers the target project ID annotation over the project looked up by the repo URL", func() {
+			buf.Reset()
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeSourceProjectIDAnnotation)
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL + "/example-group/example-project"
+			repo.Spec.URL = hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation]
+			mux.HandleFunc("/projects/example-group/example-project", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(rw, `{"id": 789}`)
+			})
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("target project ID annotation doesn't match the project of the repo URL, using the annotation"))
+
+			muxCommitStatusPost(mux, targetProjectID, digest, "")
+			muxCommitStatusesGet(mux, targetProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("fails to initialize when the commit SHA is missing", func() {
+			delete(hasSnapshot.Labels, gitops.PipelineAsCodeSHALabel)
+			delete(hasSnapshot.Annotations, gitops.BuildCommitSHAAnnotation)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).ToNot(Succeed())
+		})
+
+		It("uses the sha label when the commit sha annotation is missing", func() {
+			delete(hasSnapshot.Annotations, gitops.BuildCommitSHAAnnotation)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("reports for the commit sha annotation when the sha label doesn't match it", func() {
+			buf.Reset()
+			hasSnapshot.Labels[gitops.PipelineAsCodeSHALabel] = "0000000000000000000000000000000000000000"
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("sha label of the snapshot doesn't match the commit sha annotation, using the annotation"))
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("falls back to the Repository named by the snapshot when the repo-url annotation is missing", func() {
+			buf.Reset()
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeRepoURLAnnotation)
+			hasSnapshot.Labels[gitops.PipelineAsCodeRepositoryLabel] = "example-repository"
+			repo.Name = "example-repository"
+
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using URL of the Repository for gitlab API URL"))
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("falls back to the Repository matching the organization and repository when the repo-url annotation is malformed", func() {
+			buf.Reset()
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = "not a URL"
+			repo.Spec.URL = server.URL + "/devfile-sample/devfile-sample-go-basic"
+
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using URL of the Repository for gitlab API URL"))
+		})
+
+		It("sends the requests through the proxy from the secret", func() {
+			proxiedRequests := []string{}
+			proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				proxiedRequests = append(proxiedRequests, r.Method+" "+r.URL.String())
+				// requests for a proxy use the absolute-form, forward them to the origin server
+				r.RequestURI = ""
+				resp, err := http.DefaultTransport.RoundTrip(r)
+				if err != nil {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				defer resp.Body.Close()
+				for key, values := range resp.Header {
+					rw.Header()[key] = values
+				}
+				rw.WriteHeader(resp.StatusCode)
+				_, _ = io.Copy(rw, resp.Body)
+			}))
+			defer proxy.Close()
+
+			secretData[status.PACGitProviderHTTPProxyKey] = []byte(proxy.URL)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+			Expect(proxiedRequests).To(ContainElement(
+				HavePrefix(fmt.Sprintf("POST %s/api/v4/projects/%s/statuses/%s", server.URL, sourceProjectID, digest))))
+		})
+
+		It("doesn't send the requests through the proxy for hosts excluded by no-proxy", func() {
+			proxyCalled := false
+			proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				proxyCalled = true
+				rw.WriteHeader(http.StatusBadGateway)
+			}))
+			defer proxy.Close()
+
+			secretData[status.PACGitProviderHTTPProxyKey] = []byte(proxy.URL)
+			secretData[status.PACGitProviderNoProxyKey] = []byte("example.com, 127.0.0.1")
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+			Expect(proxyCalled).To(BeFalse())
+		})
+
+		It("fails to initialize with a misconfigured proxy URL", func() {
+			secretData[status.PACGitProviderHTTPSProxyKey] = []byte("proxy.example.com:3128")
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid proxy URL in secret key https-proxy"))
+			Expect(err.Error()).NotTo(ContainSubstring("CA bundle"))
+		})
+
+		It("doesn't make any API calls in dry-run mode", func() {
+			apiCalled := false
+			mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+				apiCalled = true
+			})
+
+			reporter = status.NewGitLabReporter(log, mockK8sClient, status.WithGitLabDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			for _, teststatus := range []integrationteststatus.IntegrationTestStatus{
+				integrationteststatus.IntegrationTestStatusInProgress,
+				integrationteststatus.IntegrationTestStatusTestFail,
+			} {
+				Expect(reporter.ReportStatus(
+					context.TODO(),
+					status.TestReport{
+						FullName:            "fullname/scenario1",
+						ScenarioName:        "scenario1",
+						TestPipelineRunName: "TestPipeline",
+						Status:              teststatus,
+						Summary:             "summary",
+						Text:                "detailed text here",
+					})).To(Succeed())
+			}
+			Expect(apiCalled).To(BeFalse())
+			Expect(buf.String()).To(ContainSubstring("Dry-run: skipping creation of commit status on GitLab"))
+		})
+
+		It("doesn't look up the project in dry-run mode when the project ID annotations are missing", func() {
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeTargetProjectIDAnnotation)
+			delete(hasSnapshot.Annotations, gitops.PipelineAsCodeSourceProjectIDAnnotation)
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL + "/example-group/example-project.git"
+			repo.Spec.URL = hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation]
+			apiCalled := false
+			mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+				apiCalled = true
+			})
+
+			reporter = status.NewGitLabReporter(log, mockK8sClient, status.WithGitLabDryRun())
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "summary",
+				})).To(Succeed())
+			Expect(apiCalled).To(BeFalse())
+			Expect(buf.String()).To(ContainSubstring("Dry-run: skipping the lookup of the gitlab project"))
+		})
+
 		It("creates a commit status for snapshot with correct textual data", func() {
 
 			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 failed"
 
 			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
 			muxMergeNotes(mux, targetProjectID, mergeRequest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
 
 			Expect(reporter.ReportStatus(
 				context.TODO(),
@@ -218,6 +517,519 @@ var _ = Describe("GitLabReporter", func() {
 				})).To(Succeed())
 		})
 
+		It("attaches the commit status to the source branch of the merge request", func() {
+			muxCommitStatusPost(mux, sourceProjectID, digest, `"ref":"feature-branch"`)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("attaches the commit status to the pushed branch for push events", func() {
+			hasSnapshot.Labels[gitops.PipelineAsCodeEventTypeLabel] = gitops.PipelineAsCodeGLPushType
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, `"ref":"main"`)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      "summary",
+				})).To(Succeed())
+		})
+
+		It("returns a rate limit error when GitLab rejects the commit status with 429", func() {
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/statuses/%s", sourceProjectID, digest), func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Retry-After", "20")
+				rw.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprintf(rw, `{"message": "429 Too Many Requests"}`)
+			})
+
+			err := reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "summary",
+				})
+			var rateLimitErr *status.GitLabRateLimitError
+			Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+			Expect(rateLimitErr.StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(rateLimitErr.RetryAfter).To(Equal(20 * time.Second))
+		})
+
+		It("returns a rate limit error when GitLab rejects the note with 503 and Retry-After", func() {
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Retry-After", "90")
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			err := reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "summary",
+				})
+			var rateLimitErr *status.GitLabRateLimitError
+			Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+			Expect(rateLimitErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(rateLimitErr.RetryAfter).To(Equal(90 * time.Second))
+		})
+
+		It("posts a note but no commit status for a merge request from a fork", func() {
+			hasSnapshot.Annotations[gitops.PipelineAsCodeSourceProjectIDAnnotation] = forkProjectID
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			statusPosted := false
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/statuses/%s", forkProjectID, digest), func(rw http.ResponseWriter, r *http.Request) {
+				statusPosted = true
+				fmt.Fprintf(rw, "{}")
+			})
+			notePosted := false
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" {
+					bit, _ := io.ReadAll(r.Body)
+					Expect(string(bit)).To(ContainSubstring("Commit statuses are not available for merge requests from forked projects"))
+					notePosted = true
+					fmt.Fprintf(rw, "{}")
+				} else {
+					fmt.Fprintf(rw, "[]")
+				}
+			})
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(notePosted).To(BeTrue())
+			Expect(statusPosted).To(BeFalse())
+			Expect(buf.String()).To(ContainSubstring("Won't create/update commitStatus due to the access limitation for forked repo"))
+		})
+
+		It("fails to report for a merge request from a fork when the note can't be posted", func() {
+			hasSnapshot.Annotations[gitops.PipelineAsCodeSourceProjectIDAnnotation] = forkProjectID
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusForbidden)
+				fmt.Fprintf(rw, `{"message": "403 Forbidden"}`)
+			})
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "summary",
+				})).NotTo(Succeed())
+		})
+
+		It("doesn't post an identical commit status again", func() {
+			report := status.TestReport{
+				FullName:            "fullname/scenario1",
+				ScenarioName:        "scenario1",
+				TestPipelineRunName: "TestPipeline",
+				Status:              integrationteststatus.IntegrationTestStatusTestPassed,
+				Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 has passed",
+			}
+			postedStatuses := []gitlab.CommitStatus{}
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/statuses/%s", sourceProjectID, digest), func(rw http.ResponseWriter, r *http.Request) {
+				opt := gitlab.SetCommitStatusOptions{}
+				Expect(json.NewDecoder(r.Body).Decode(&opt)).To(Succeed())
+				postedStatuses = append(postedStatuses, gitlab.CommitStatus{
+					ID:          len(postedStatuses) + 1,
+					Name:        *opt.Name,
+					Status:      string(opt.State),
+					Description: *opt.Description,
+					TargetURL:   *opt.TargetURL,
+				})
+				fmt.Fprintf(rw, "{}")
+			})
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/repository/commits/%s/statuses", sourceProjectID, digest), func(rw http.ResponseWriter, r *http.Request) {
+				jsonStatuses, _ := json.Marshal(postedStatuses)
+				fmt.Fprint(rw, string(jsonStatuses))
+			})
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+
+			Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+			Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+			Expect(postedStatuses).To(HaveLen(1))
+
+			report.Summary = "Integration test for snapshot snapshot-sample and scenario scenario1 has passed again"
+			Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+			Expect(postedStatuses).To(HaveLen(2))
+		})
+
+		It("finds an existing commit status on the second page", func() {
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress"
+			statusPosted := false
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/statuses/%s", sourceProjectID, digest), func(rw http.ResponseWriter, r *http.Request) {
+				statusPosted = true
+				fmt.Fprintf(rw, "{}")
+			})
+			muxPaginatedGet(mux, fmt.Sprintf("/projects/%s/repository/commits/%s/statuses", sourceProjectID, digest),
+				[]gitlab.CommitStatus{{ID: 1, Name: "other-pipeline", Status: string(gitlab.Success)}},
+				[]gitlab.CommitStatus{{ID: 2, Name: "fullname/scenario1", Status: string(gitlab.Running), Description: summary}},
+			)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					Status:       integrationteststatus.IntegrationTestStatusInProgress,
+					Summary:      summary,
+				})).To(Succeed())
+			Expect(statusPosted).To(BeFalse())
+		})
+
+		It("finds an existing mergeRequest note on the second page", func() {
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 has passed"
+			existingNote := gitlab.Note{ID: 42, Body: "### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\n" +
+				status.FormatCommentMarker("snapshot-sample", "scenario1")}
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxPaginatedGet(mux, fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest),
+				[]gitlab.Discussion{{ID: "discussion-1", Notes: []*gitlab.Note{{ID: 1, Body: "unrelated comment"}}}},
+				[]gitlab.Discussion{{ID: "discussion-2", Notes: []*gitlab.Note{&existingNote}}},
+			)
+			noteUpdated := false
+			mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", targetProjectID, mergeRequest, existingNote.ID), func(rw http.ResponseWriter, r *http.Request) {
+				noteUpdated = r.Method == "PUT"
+				fmt.Fprintf(rw, "{}")
+			})
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+					Summary:      summary,
+				})).To(Succeed())
+			Expect(noteUpdated).To(BeTrue())
+		})
+
+		When("the merge train check is enabled for a composite snapshot", func() {
+
+			var (
+				mergeTrainPath string
+				failureReport  status.TestReport
+			)
+
+			BeforeEach(func() {
+				buf.Reset()
+				hasSnapshot.Labels[gitops.SnapshotTypeLabel] = gitops.SnapshotCompositeType
+				hasSnapshot.Annotations[gitops.MergeTrainCheckAnnotation] = "true"
+				Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+				mergeTrainPath = fmt.Sprintf("/projects/%s/merge_trains/merge_requests/%s", targetProjectID, mergeRequest)
+				failureReport = status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has failed",
+					Text:         "detailed text here",
+				}
+				muxCommitStatusPost(mux, sourceProjectID, digest, "")
+				muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			})
+
+			It("warns that the merge train should be stopped when the merge request is on a merge train", func() {
+				mux.HandleFunc(mergeTrainPath, func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"id": 1, "status": "fresh", "target_branch": "main"}`)
+				})
+				notes := []string{}
+				discussions := []string{}
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+					bit, _ := io.ReadAll(r.Body)
+					notes = append(notes, string(bit))
+					fmt.Fprintf(rw, "{}")
+				})
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+					if r.Method == "POST" {
+						bit, _ := io.ReadAll(r.Body)
+						discussions = append(discussions, string(bit))
+						fmt.Fprintf(rw, "{}")
+						return
+					}
+					fmt.Fprintf(rw, "[]")
+				})
+
+				Expect(reporter.ReportStatus(context.TODO(), failureReport)).To(Succeed())
+				Expect(discussions).To(HaveLen(1))
+				Expect(discussions[0]).To(ContainSubstring("the merge request is on the merge train of branch `main` with status `fresh`"))
+				Expect(notes).To(HaveLen(1))
+				Expect(notes[0]).To(ContainSubstring("The merge train should be stopped"))
+			})
+
+			It("warns only once when failed reports are sent concurrently", func() {
+				mux.HandleFunc(mergeTrainPath, func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"id": 1, "status": "fresh", "target_branch": "main"}`)
+				})
+				var lock sync.Mutex
+				notes := []string{}
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+					noteOptions := gitlab.CreateMergeRequestNoteOptions{}
+					Expect(json.NewDecoder(r.Body).Decode(&noteOptions)).To(Succeed())
+					lock.Lock()
+					notes = append(notes, *noteOptions.Body)
+					lock.Unlock()
+					fmt.Fprintf(rw, "{}")
+				})
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", targetProjectID, mergeRequest), func(rw http.ResponseWriter, r *http.Request) {
+					if r.Method == "POST" {
+						fmt.Fprintf(rw, "{}")
+						return
+					}
+					lock.Lock()
+					defer lock.Unlock()
+					discussions := []*gitlab.Discussion{}
+					for _, note := range notes {
+						discussions = append(discussions, &gitlab.Discussion{Notes: []*gitlab.Note{{Body: note}}})
+					}
+					Expect(json.NewEncoder(rw).Encode(discussions)).To(Succeed())
+				})
+
+				var wg sync.WaitGroup
+				for i := 1; i <= 5; i++ {
+					wg.Add(1)
+					go func(report status.TestReport) {
+						defer GinkgoRecover()
+						defer wg.Done()
+						Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+					}(status.TestReport{
+						FullName:     fmt.Sprintf("fullname/scenario%d", i),
+						ScenarioName: fmt.Sprintf("scenario%d", i),
+						SnapshotName: failureReport.SnapshotName,
+						Status:       failureReport.Status,
+						Summary:      failureReport.Summary,
+						Text:         failureReport.Text,
+					})
+				}
+				wg.Wait()
+
+				warnings := 0
+				for _, note := range notes {
+					if strings.Contains(note, "The merge train should be stopped") {
+						warnings++
+					}
+				}
+				Expect(warnings).To(Equal(1))
+			})
+
+			It("doesn't warn when merge trains aren't available", func() {
+				mux.HandleFunc(mergeTrainPath, func(rw http.ResponseWriter, r *http.Request) {
+					rw.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(rw, `{"message": "403 Forbidden"}`)
+				})
+				muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+
+				Expect(reporter.ReportStatus(context.TODO(), failureReport)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring("merge trains are not available for the project, skipping the merge train check"))
+				Expect(buf.String()).NotTo(ContainSubstring("posted merge train warning"))
+			})
+
+			It("doesn't check the merge train when the scenario passes", func() {
+				mergeTrainChecked := false
+				mux.HandleFunc(mergeTrainPath, func(rw http.ResponseWriter, r *http.Request) {
+					mergeTrainChecked = true
+					fmt.Fprint(rw, `{"id": 1, "status": "fresh", "target_branch": "main"}`)
+				})
+				muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+
+				failureReport.Status = integrationteststatus.IntegrationTestStatusTestPassed
+				Expect(reporter.ReportStatus(context.TODO(), failureReport)).To(Succeed())
+				Expect(mergeTrainChecked).To(BeFalse())
+			})
+		})
+
+		When("external status checks are enabled", func() {
+			var (
+				statusChecksPath    string
+				statusResponsesPath string
+				statusChecksFound   bool
+				statusChecksListed  int
+				report              status.TestReport
+			)
+
+			BeforeEach(func() {
+				buf.Reset()
+				hasSnapshot.Annotations[gitops.ExternalStatusCheckAnnotation] = "true"
+				Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+
+				statusChecksPath = fmt.Sprintf("/projects/%s/merge_requests/%s/status_checks", targetProjectID, mergeRequest)
+				statusResponsesPath = fmt.Sprintf("/projects/%s/merge_requests/%s/status_check_responses", targetProjectID, mergeRequest)
+				report = status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+					Summary:      "Integration test for snapshot snapshot-sample and scenario scenario1 has passed",
+				}
+				muxCommitStatusPost(mux, sourceProjectID, digest, "")
+				muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+				muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+				statusChecksFound = true
+				statusChecksListed = 0
+				mux.HandleFunc(statusChecksPath, func(rw http.ResponseWriter, r *http.Request) {
+					statusChecksListed++
+					if !statusChecksFound {
+						rw.WriteHeader(http.StatusNotFound)
+						fmt.Fprint(rw, `{"message": "404 Not Found"}`)
+						return
+					}
+					fmt.Fprint(rw, `[{"id": 7, "name": "other-check", "status": "pending"}, {"id": 8, "name": "fullname/scenario1", "status": "pending"}]`)
+				})
+			})
+
+			DescribeTable("responds to the matching external status check once the scenario finishes", func(testStatus integrationteststatus.IntegrationTestStatus, expectedStatus string) {
+				responses := []gitlab.SetExternalStatusCheckStatusOptions{}
+				mux.HandleFunc(statusResponsesPath, func(rw http.ResponseWriter, r *http.Request) {
+					opt := gitlab.SetExternalStatusCheckStatusOptions{}
+					Expect(json.NewDecoder(r.Body).Decode(&opt)).To(Succeed())
+					responses = append(responses, opt)
+					fmt.Fprintf(rw, "{}")
+				})
+
+				report.Status = testStatus
+				Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+				Expect(responses).To(HaveLen(1))
+				Expect(*responses[0].ExternalStatusCheckID).To(Equal(8))
+				Expect(*responses[0].SHA).To(Equal(digest))
+				Expect(*responses[0].Status).To(Equal(expectedStatus))
+			},
+				Entry("passed", integrationteststatus.IntegrationTestStatusTestPassed, "passed"),
+				Entry("failed", integrationteststatus.IntegrationTestStatusTestFail, "failed"),
+			)
+
+			It("doesn't respond to the external status check while the scenario is in progress", func() {
+				responded := false
+				mux.HandleFunc(statusResponsesPath, func(rw http.ResponseWriter, r *http.Request) {
+					responded = true
+					fmt.Fprintf(rw, "{}")
+				})
+
+				report.Status = integrationteststatus.IntegrationTestStatusInProgress
+				Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+				Expect(responded).To(BeFalse())
+			})
+
+			It("disables external status checks when GitLab doesn't support them", func() {
+				statusChecksFound = false
+
+				Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+				Expect(reporter.ReportStatus(context.TODO(), report)).To(Succeed())
+				Expect(statusChecksListed).To(Equal(1))
+				Expect(buf.String()).To(ContainSubstring("external status checks are not available for the project, disabling them"))
+			})
+		})
+
+		It("reports canceled commit statuses for unfinished tests of a superseded snapshot", func() {
+			sits, err := integrationteststatus.NewSnapshotIntegrationTestStatuses("")
+			Expect(err).To(Succeed())
+			sits.UpdateTestStatusIfChanged("scenario1", integrationteststatus.IntegrationTestStatusInProgress, "Test in progress")
+			statusAnnotation, err := json.Marshal(sits)
+			Expect(err).To(Succeed())
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = string(statusAnnotation)
+
+			Expect(gitops.MarkSnapshotAsSuperseded(context.TODO(), mockK8sClient, hasSnapshot, "snapshot-newer")).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, `"state":"canceled"`)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "was canceled because the snapshot was superseded by snapshot snapshot-newer")
+
+			st := status.NewStatus(logr.Discard(), mockK8sClient)
+			Expect(st.ReportSnapshotStatus(context.TODO(), reporter, hasSnapshot)).To(Succeed())
+		})
+
+		It("reports canceled commit statuses for unfinished tests of a snapshot obsoleted by an override snapshot", func() {
+			sits, err := integrationteststatus.NewSnapshotIntegrationTestStatuses("")
+			Expect(err).To(Succeed())
+			sits.UpdateTestStatusIfChanged("scenario1", integrationteststatus.IntegrationTestStatusInProgress, "Test in progress")
+			statusAnnotation, err := json.Marshal(sits)
+			Expect(err).To(Succeed())
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = string(statusAnnotation)
+
+			Expect(gitops.MarkSnapshotAsObsoletedByOverride(context.TODO(), mockK8sClient, hasSnapshot, "snapshot-override")).To(Succeed())
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, `"state":"canceled"`)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "was canceled because the snapshot was obsoleted by override snapshot snapshot-override")
+
+			st := status.NewStatus(logr.Discard(), mockK8sClient)
+			Expect(st.ReportSnapshotStatus(context.TODO(), reporter, hasSnapshot)).To(Succeed())
+		})
+
+		It("reports canceled commit statuses for unfinished tests of a snapshot whose merge request was closed", func() {
+			sits, err := integrationteststatus.NewSnapshotIntegrationTestStatuses("")
+			Expect(err).To(Succeed())
+			sits.UpdateTestStatusIfChanged("scenario1", integrationteststatus.IntegrationTestStatusDeleted,
+				"Integration test was canceled because the pull request was closed")
+			statusAnnotation, err := json.Marshal(sits)
+			Expect(err).To(Succeed())
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = string(statusAnnotation)
+			hasSnapshot.Annotations[gitops.SnapshotCanceledByPRMRAnnotation] = string(status.PRMRStateClosed)
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, `"state":"canceled"`)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "was canceled because the pull request was closed")
+
+			st := status.NewStatus(logr.Discard(), mockK8sClient)
+			Expect(st.ReportSnapshotStatus(context.TODO(), reporter, hasSnapshot)).To(Succeed())
+		})
+
+		It("reports the test result reused from a snapshot with identical components with a note about the reuse", func() {
+			sits, err := integrationteststatus.NewSnapshotIntegrationTestStatuses("")
+			Expect(err).To(Succeed())
+			sits.UpdateTestStatusIfChanged("scenario1", integrationteststatus.IntegrationTestStatusPending, "Pending")
+			Expect(sits.ReuseTestResult("scenario1", &integrationteststatus.IntegrationTestStatusDetail{
+				ScenarioName:        "scenario1",
+				Status:              integrationteststatus.IntegrationTestStatusTestPassed,
+				Details:             "Integration test passed",
+				TestPipelineRunName: "pipelinerun-reused",
+			}, "snapshot-reused")).To(Succeed())
+			statusAnnotation, err := json.Marshal(sits)
+			Expect(err).To(Succeed())
+			hasSnapshot.Annotations[gitops.SnapshotTestsStatusAnnotation] = string(statusAnnotation)
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "result reused from snapshot snapshot-reused")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "its result was reused from snapshot snapshot-reused with identical components")
+
+			st := status.NewStatus(logr.Discard(), mockK8sClient)
+			Expect(st.ReportSnapshotStatus(context.TODO(), reporter, hasSnapshot)).To(Succeed())
+		})
+
 		It("creates a commit status for snapshot with TargetURL in CommitStatus", func() {
 
 			PipelineRunName := "TestPipeline"
@@ -239,6 +1051,29 @@ var _ = Describe("GitLabReporter", func() {
 				})).To(Succeed())
 		})
 
+		It("creates a commit status for snapshot with TargetURL linking to the failed task", func() {
+			os.Setenv("CONSOLE_URL", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}")
+			os.Setenv("CONSOLE_URL_TASKLOG", "https://console.example.com/ns/{{ .Namespace }}/pipelinerun/{{ .PipelineRunName }}/logs/{{ .TaskName }}")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL")
+			DeferCleanup(os.Unsetenv, "CONSOLE_URL_TASKLOG")
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "https://console.example.com/ns/default/pipelinerun/TestPipeline/logs/task1")
+			muxMergeNotes(mux, targetProjectID, mergeRequest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:            "fullname/scenario1",
+					ScenarioName:        "scenario1",
+					TestPipelineRunName: "TestPipeline",
+					FailedTaskName:      "task1",
+					Status:              integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:             "summary",
+					Text:                "detailed text here",
+				})).To(Succeed())
+		})
+
 		It("does not create a commit status or comment for snapshot with existing matching checkRun in running state", func() {
 			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 is running"
 
@@ -282,6 +1117,20 @@ var _ = Describe("GitLabReporter", func() {
 			Expect(existingCommitStatus.Status).To(Equal(commitStatus.Status))
 		})
 
+		It("can get an existing commitStatus that matches the report with a check prefix", func() {
+			unprefixedCommitStatus := gitlab.CommitStatus{ID: 123, Name: "Red Hat Konflux / scenario1"}
+			prefixedCommitStatus := gitlab.CommitStatus{ID: 456, Name: "konflux-prod/Red Hat Konflux / scenario1"}
+			commitStatuses := []*gitlab.CommitStatus{&unprefixedCommitStatus, &prefixedCommitStatus}
+
+			existingCommitStatus := reporter.GetExistingCommitStatus(commitStatuses, "konflux-prod/Red Hat Konflux / scenario1")
+			Expect(existingCommitStatus).NotTo(BeNil())
+			Expect(existingCommitStatus.ID).To(Equal(456))
+
+			existingCommitStatus = reporter.GetExistingCommitStatus(commitStatuses, "Red Hat Konflux / scenario1")
+			Expect(existingCommitStatus).NotTo(BeNil())
+			Expect(existingCommitStatus.ID).To(Equal(123))
+		})
+
 		It("can get an existing mergeRequest note that matches the report", func() {
 			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 failed"
 			report := status.TestReport{
@@ -292,21 +1141,324 @@ var _ = Describe("GitLabReporter", func() {
 				Summary:      summary,
 				Text:         "detailed text here",
 			}
-			comment, err := status.FormatComment(report.Summary, report.Text)
+			comment, err := status.FormatComment(report.Summary, report.Text, report.SnapshotName, report.ScenarioName)
 			Expect(err).ToNot(HaveOccurred())
 
 			note := gitlab.Note{}
 			note.ID = 123
 			note.Body = comment
 
-			notes := []*gitlab.Note{
-				&note,
+			discussions := []*gitlab.Discussion{
+				{ID: "discussion-1", Notes: []*gitlab.Note{&note}},
 			}
-			existingNoteID := reporter.GetExistingNoteID(notes, report.ScenarioName, report.SnapshotName)
+			existingNoteID, discussionID := reporter.GetExistingNoteID(discussions, report.ScenarioName, report.SnapshotName)
+
+			Expect(*existingNoteID).To(Equal(note.ID))
+			Expect(discussionID).To(Equal("discussion-1"))
+		})
 
+		It("can get an existing mergeRequest note by its marker regardless of the summary wording", func() {
+			otherNote := gitlab.Note{ID: 100, Body: "### Integration test for snapshot snapshot-sample and scenario scenario10 failed\n\n" +
+				status.FormatCommentMarker("snapshot-sample", "scenario10")}
+			note := gitlab.Note{ID: 123, Body: "### Tests have passed\n\n" + status.FormatCommentMarker("snapshot-sample", "scenario1")}
+
+			otherDiscussion := gitlab.Discussion{ID: "discussion-1", Notes: []*gitlab.Note{&otherNote}}
+			discussion := gitlab.Discussion{ID: "discussion-2", Notes: []*gitlab.Note{&note}}
+
+			existingNoteID, discussionID := reporter.GetExistingNoteID([]*gitlab.Discussion{&otherDiscussion, &discussion}, "scenario1", "snapshot-sample")
+			Expect(existingNoteID).NotTo(BeNil())
 			Expect(*existingNoteID).To(Equal(note.ID))
+			Expect(discussionID).To(Equal("discussion-2"))
+
+			existingNoteID, discussionID = reporter.GetExistingNoteID([]*gitlab.Discussion{&otherDiscussion}, "scenario1", "snapshot-sample")
+			Expect(existingNoteID).To(BeNil())
+			Expect(discussionID).To(BeEmpty())
+		})
+
+		It("updates the existing mergeRequest note instead of creating a new one", func() {
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 has passed"
+			existingNote := gitlab.Note{ID: 42, Body: "### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\n" +
+				status.FormatCommentMarker("snapshot-sample", "scenario1")}
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			noteUpdated := muxExistingMergeNote(mux, targetProjectID, mergeRequest, existingNote, summary)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+					Summary:      summary,
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(*noteUpdated).To(BeTrue())
+		})
+
+		It("resolves the discussion of a previous failure when the scenario passes", func() {
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 has passed"
+			failureNote := gitlab.Note{ID: 42, Resolvable: true, Body: "### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\n" +
+				status.FormatCommentMarker("snapshot-sample", "scenario1")}
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			discussionMock := muxExistingDiscussion(mux, targetProjectID, mergeRequest, "discussion-1", failureNote)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestPassed,
+					Summary:      summary,
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(discussionMock.noteUpdated).To(BeTrue())
+			Expect(discussionMock.reply).To(ContainSubstring("Integration test scenario scenario1 is now passing."))
+			Expect(discussionMock.resolved).To(BeTrue())
+		})
+
+		It("doesn't resolve the discussion of a previous failure when the scenario fails again", func() {
+			summary := "Integration test for snapshot snapshot-sample and scenario scenario1 has failed"
+			failureNote := gitlab.Note{ID: 42, Resolvable: true, Body: "### Integration test for snapshot snapshot-sample and scenario scenario1 failed\n\n" +
+				status.FormatCommentMarker("snapshot-sample", "scenario1")}
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, summary)
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+			discussionMock := muxExistingDiscussion(mux, targetProjectID, mergeRequest, "discussion-1", failureNote)
+
+			Expect(reporter.ReportStatus(
+				context.TODO(),
+				status.TestReport{
+					FullName:     "fullname/scenario1",
+					ScenarioName: "scenario1",
+					SnapshotName: "snapshot-sample",
+					Status:       integrationteststatus.IntegrationTestStatusTestFail,
+					Summary:      summary,
+					Text:         "detailed text here",
+				})).To(Succeed())
+			Expect(discussionMock.noteUpdated).To(BeTrue())
+			Expect(discussionMock.reply).To(BeEmpty())
+			Expect(discussionMock.resolved).To(BeFalse())
+		})
+	})
+
+	Context("when looking up the GitLab token", func() {
+
+		var (
+			repo    pacv1alpha1.Repository
+			secrets map[string]map[string][]byte
+			server  *httptest.Server
+		)
+
+		BeforeEach(func() {
+			buf.Reset()
+			mux := http.NewServeMux()
+			mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s", targetProjectID), func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(rw, "{}")
+			})
+			server = httptest.NewServer(mux)
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL
+
+			repo = pacv1alpha1.Repository{
+				Spec: pacv1alpha1.RepositorySpec{
+					URL: server.URL,
+					GitProvider: &pacv1alpha1.GitProvider{
+						Secret: &pacv1alpha1.Secret{
+							Name: "example-secret-name",
+							Key:  "example-token",
+						},
+					},
+				},
+			}
+			secrets = map[string]map[string][]byte{
+				"example-secret-name": {"example-token": []byte("example-personal-access-token")},
+				"gitlab-group-token":  {"token": []byte("example-group-access-token")},
+			}
+
+			mockK8sClient = &MockK8sClient{
+				getInterceptor: func(key client.ObjectKey, obj client.Object) {
+					if secret, ok := obj.(*v1.Secret); ok {
+						secret.Name = key.Name
+						secret.Data = secrets[key.Name]
+					}
+				},
+				listInterceptor: func(list client.ObjectList) {
+					if repoList, ok := list.(*pacv1alpha1.RepositoryList); ok {
+						repoList.Items = []pacv1alpha1.Repository{repo}
+					}
+				},
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("uses the token from the Repository secret", func() {
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using token from the Repository secret for gitlab provider"))
+			Expect(buf.String()).NotTo(ContainSubstring("example-personal-access-token"))
+		})
+
+		It("uses the token from the Repository whose URL differs by a .git suffix and trailing slash", func() {
+			repo.Spec.URL = server.URL + ".git/"
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using token from the Repository secret for gitlab provider"))
+		})
+
+		It("falls back to the group token when no Repository matches the repo URL", func() {
+			repo.Spec.URL = "https://gitlab.com/another-group/another-project"
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using group token for gitlab provider"))
+		})
+
+		It("falls back to the group token when the Repository has no secret", func() {
+			repo.Spec.GitProvider.Secret = nil
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using group token for gitlab provider"))
+			Expect(buf.String()).NotTo(ContainSubstring("example-group-access-token"))
 		})
 
+		It("falls back to the group token when the Repository secret has no token", func() {
+			secrets["example-secret-name"] = map[string][]byte{}
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("using group token for gitlab provider"))
+		})
+
+		It("fails with a no credentials error listing the tried sources", func() {
+			repo.Spec.GitProvider.Secret = nil
+			secrets["gitlab-group-token"] = map[string][]byte{}
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			var noCredentialsErr *status.NoCredentialsError
+			Expect(errors.As(err, &noCredentialsErr)).To(BeTrue())
+			Expect(noCredentialsErr.Tried).To(HaveLen(2))
+			Expect(err.Error()).To(ContainSubstring("failed to find a Repository with a git provider secret matching URL"))
+			Expect(err.Error()).To(ContainSubstring("secret key token of the group token secret gitlab-group-token is missing"))
+		})
+
+		It("doesn't fall back when the secret can't be fetched", func() {
+			mockK8sClient.err = fmt.Errorf("connection refused")
+
+			reporter := status.NewGitLabReporter(log, mockK8sClient)
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			Expect(err).To(HaveOccurred())
+			var noCredentialsErr *status.NoCredentialsError
+			Expect(errors.As(err, &noCredentialsErr)).To(BeFalse())
+		})
+	})
+
+	Context("when provided a CA bundle for a self-managed GitLab", func() {
+
+		var (
+			secretData map[string][]byte
+			reporter   *status.GitLabReporter
+			mux        *http.ServeMux
+			server     *httptest.Server
+		)
+
+		BeforeEach(func() {
+			mux = http.NewServeMux()
+			apiHandler := http.NewServeMux()
+			apiHandler.Handle("/api/v4/", http.StripPrefix("/api/v4", mux))
+			mux.HandleFunc(fmt.Sprintf("/projects/%s", targetProjectID), func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(rw, "{}")
+			})
+
+			// server is a test HTTPS server with a self-signed certificate
+			server = httptest.NewTLSServer(apiHandler)
+			hasSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = server.URL
+
+			repo := pacv1alpha1.Repository{
+				Spec: pacv1alpha1.RepositorySpec{
+					URL: server.URL,
+					GitProvider: &pacv1alpha1.GitProvider{
+						Secret: &pacv1alpha1.Secret{
+							Name: "example-secret-name",
+							Key:  "example-token",
+						},
+					},
+				},
+			}
+
+			mockK8sClient = &MockK8sClient{
+				getInterceptor: func(key client.ObjectKey, obj client.Object) {
+					if secret, ok := obj.(*v1.Secret); ok {
+						secret.Data = secretData
+					}
+				},
+				listInterceptor: func(list client.ObjectList) {
+					if repoList, ok := list.(*pacv1alpha1.RepositoryList); ok {
+						repoList.Items = []pacv1alpha1.Repository{repo}
+					}
+				},
+			}
+
+			secretData = map[string][]byte{
+				"example-token": []byte("example-personal-access-token"),
+			}
+
+			reporter = status.NewGitLabReporter(log, mockK8sClient)
+
+			muxCommitStatusPost(mux, sourceProjectID, digest, "")
+			muxCommitStatusesGet(mux, sourceProjectID, digest, nil)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		testReport := status.TestReport{
+			FullName:     "fullname/scenario1",
+			ScenarioName: "scenario1",
+			Status:       integrationteststatus.IntegrationTestStatusInProgress,
+			Summary:      "summary",
+		}
+
+		It("trusts the CA bundle from the git provider secret", func() {
+			secretData[status.PACGitProviderCABundleKey] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			Expect(reporter.ReportStatus(context.TODO(), testReport)).To(Succeed())
+		})
+
+		It("fails to verify the certificate without the CA bundle", func() {
+			Expect(reporter.Initialize(context.TODO(), hasSnapshot)).To(Succeed())
+			err := reporter.ReportStatus(context.TODO(), testReport)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("certificate"))
+		})
+
+		It("fails to initialize with an invalid CA bundle", func() {
+			secretData[status.PACGitProviderCABundleKey] = []byte("not a certificate")
+
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to use CA bundle from secret key ca.crt"))
+			Expect(err.Error()).NotTo(ContainSubstring("proxy"))
+		})
+
+		It("fails to initialize with an empty CA bundle", func() {
+			secretData[status.PACGitProviderCABundleKey] = []byte{}
+
+			err := reporter.Initialize(context.TODO(), hasSnapshot)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("secret key ca.crt of secret"))
+		})
 	})
 
 	Describe("Test helper functions", func() {
@@ -322,11 +1474,13 @@ var _ = Describe("GitLabReporter", func() {
 			Entry("Provision error", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, gitlab.Failed),
 			Entry("Deployment error", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, gitlab.Failed),
 			Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, gitlab.Canceled),
+			Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, gitlab.Skipped),
 			Entry("Success", integrationteststatus.IntegrationTestStatusTestPassed, gitlab.Success),
 			Entry("Test failure", integrationteststatus.IntegrationTestStatusTestFail, gitlab.Failed),
 			Entry("In progress", integrationteststatus.IntegrationTestStatusInProgress, gitlab.Running),
 			Entry("Pending", integrationteststatus.IntegrationTestStatusPending, gitlab.Pending),
 			Entry("Invalid", integrationteststatus.IntegrationTestStatusTestInvalid, gitlab.Failed),
+			Entry("Configuration error", integrationteststatus.IntegrationTestStatusTestError, gitlab.Failed),
 		)
 
 		It("check if all integration tests statuses are supported", func() {
@@ -372,10 +1526,31 @@ func muxCommitStatusesGet(mux *http.ServeMux, pid string, sha string, report *st
 	})
 }
 
-// muxMergeNotes mocks merge request notes GET and POST requests, if catchStr is non-empty POST request must contain such substring
-func muxMergeNotes(mux *http.ServeMux, pid string, mr string, catchStr string) {
-	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", pid, mr)
+// muxPaginatedGet mocks a GET request listing items, each of the given pages is served as a separate page of the list
+// linked by the X-Next-Page header. Requests with another method fail with 405 Method Not Allowed.
+func muxPaginatedGet(mux *http.ServeMux, path string, pages ...any) {
 	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > len(pages) {
+			fmt.Fprint(rw, "[]")
+			return
+		}
+		if page < len(pages) {
+			rw.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		jsonPage, _ := json.Marshal(pages[page-1])
+		fmt.Fprint(rw, string(jsonPage))
+	})
+}
+
+// muxMergeNotes mocks merge request discussions GET request and the POST requests creating notes and discussions,
+// if catchStr is non-empty POST request must contain such substring
+func muxMergeNotes(mux *http.ServeMux, pid string, mr string, catchStr string) {
+	createHandler := func(rw http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
 			bit, _ := io.ReadAll(r.Body)
 			s := string(bit)
@@ -386,5 +1561,71 @@ func muxMergeNotes(mux *http.ServeMux, pid string, mr string, catchStr string) {
 		} else {
 			fmt.Fprintf(rw, "[]")
 		}
+	}
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes", pid, mr), createHandler)
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", pid, mr), createHandler)
+}
+
+// muxExistingMergeNote mocks merge request discussions GET request returning a discussion started by the given note
+// and the PUT request updating the note, a new note must not be created. If catchStr is non-empty PUT request must
+// contain such substring. The returned value reports whether the note was updated.
+func muxExistingMergeNote(mux *http.ServeMux, pid string, mr string, note gitlab.Note, catchStr string) *bool {
+	updated := false
+	muxExistingDiscussionList(mux, pid, mr, "discussion-1", note)
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", pid, mr, note.ID), func(rw http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).To(Equal("PUT"))
+		bit, _ := io.ReadAll(r.Body)
+		if catchStr != "" {
+			Expect(string(bit)).To(ContainSubstring(catchStr))
+		}
+		updated = true
+		fmt.Fprintf(rw, "{}")
 	})
+	return &updated
+}
+
+// discussionMock records the requests received by the mocked merge request discussion
+type discussionMock struct {
+	noteUpdated bool
+	reply       string
+	resolved    bool
+}
+
+// muxExistingDiscussion mocks merge request discussions GET request returning a discussion started by the given note
+// together with the requests updating the note, replying to the discussion and resolving it
+func muxExistingDiscussion(mux *http.ServeMux, pid string, mr string, discussionID string, note gitlab.Note) *discussionMock {
+	mock := &discussionMock{}
+	muxExistingDiscussionList(mux, pid, mr, discussionID, note)
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", pid, mr, note.ID), func(rw http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).To(Equal("PUT"))
+		mock.noteUpdated = true
+		fmt.Fprintf(rw, "{}")
+	})
+	discussionPath := fmt.Sprintf("/projects/%s/merge_requests/%s/discussions/%s", pid, mr, discussionID)
+	mux.HandleFunc(discussionPath+"/notes", func(rw http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).To(Equal("POST"))
+		bit, _ := io.ReadAll(r.Body)
+		mock.reply = string(bit)
+		fmt.Fprintf(rw, "{}")
+	})
+	mux.HandleFunc(discussionPath, func(rw http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).To(Equal("PUT"))
+		bit, _ := io.ReadAll(r.Body)
+		Expect(string(bit)).To(ContainSubstring(`"resolved":true`))
+		mock.resolved = true
+		fmt.Fprintf(rw, "{}")
+	})
+	return mock
+}
+
+// muxExistingDiscussionList mocks merge request discussions GET request returning a discussion started by the given
+// note, new notes and discussions must not be created
+func muxExistingDiscussionList(mux *http.ServeMux, pid string, mr string, discussionID string, note gitlab.Note) {
+	noCreateHandler := func(rw http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).NotTo(Equal("POST"), "a new note must not be created when a matching note exists")
+		jsonDiscussions, _ := json.Marshal([]gitlab.Discussion{{ID: discussionID, Notes: []*gitlab.Note{&note}}})
+		fmt.Fprint(rw, string(jsonDiscussions))
+	}
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/notes", pid, mr), noCreateHandler)
+	mux.HandleFunc(fmt.Sprintf("/projects/%s/merge_requests/%s/discussions", pid, mr), noCreateHandler)
 }
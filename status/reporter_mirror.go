@@ -0,0 +1,163 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+
+	"github.com/konflux-ci/integration-service/gitops"
+)
+
+// mirrorSnapshotAnnotations are the annotations which describe the pull/merge request of the snapshot on its git provider,
+// they don't apply to the mirrors of the repository
+var mirrorSnapshotAnnotations = []string{
+	gitops.PipelineAsCodePullRequestAnnotation,
+	gitops.PipelineAsCodeInstallationIDAnnotation,
+	gitops.PipelineAsCodeSourceProjectIDAnnotation,
+	gitops.PipelineAsCodeTargetProjectIDAnnotation,
+	gitops.ExternalStatusCheckAnnotation,
+	gitops.MergeTrainCheckAnnotation,
+}
+
+// mirrorReporter reports the status of the snapshot to a mirror of its repository hosted by a secondary git provider.
+// The wrapped reporter is initialized with a copy of the snapshot pointing to the mirror, so it uses the organization,
+// repository and the PaC Repository credentials of the mirror. The pull/merge request of the mirror isn't known,
+// only commit statuses are reported.
+type mirrorReporter struct {
+	reporter    ReporterInterface
+	gitProvider string
+	mirrorURL   string
+}
+
+// check if interface has been correctly implemented
+var _ ReporterInterface = (*mirrorReporter)(nil)
+
+// newMirrorReporter returns a reporter reporting to the repository of the given URL using the given reporter
+func newMirrorReporter(reporter ReporterInterface, gitProvider, mirrorURL string) *mirrorReporter {
+	return &mirrorReporter{
+		reporter:    reporter,
+		gitProvider: gitProvider,
+		mirrorURL:   mirrorURL,
+	}
+}
+
+// Detect if the wrapped reporter can be used with the mirror of the snapshot repository
+func (r *mirrorReporter) Detect(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	mirrorSnapshot, err := r.getMirrorSnapshot(snapshot)
+	if err != nil {
+		return false
+	}
+	return r.reporter.Detect(mirrorSnapshot)
+}
+
+// Initialize the wrapped reporter for the mirror of the snapshot repository
+func (r *mirrorReporter) Initialize(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) error {
+	mirrorSnapshot, err := r.getMirrorSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	return r.reporter.Initialize(ctx, mirrorSnapshot)
+}
+
+// GetReporterName returns the name of the wrapped reporter together with the mirror URL, so the report status
+// of each mirror is tracked separately
+func (r *mirrorReporter) GetReporterName() string {
+	return fmt.Sprintf("%s(%s)", r.reporter.GetReporterName(), r.mirrorURL)
+}
+
+// ReportStatus reports the status using the wrapped reporter
+func (r *mirrorReporter) ReportStatus(ctx context.Context, report TestReport) error {
+	return r.reporter.ReportStatus(ctx, report)
+}
+
+// getMirrorSnapshot returns a copy of the snapshot whose git provider, repo URL, organization and repository point to
+// the mirror. The annotations describing the pull/merge request are dropped as they don't apply to the mirror.
+func (r *mirrorReporter) getMirrorSnapshot(snapshot *applicationapiv1alpha1.Snapshot) (*applicationapiv1alpha1.Snapshot, error) {
+	org, repository, err := parseMirrorURL(r.mirrorURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorSnapshot := snapshot.DeepCopy()
+	if mirrorSnapshot.Labels == nil {
+		mirrorSnapshot.Labels = map[string]string{}
+	}
+	if mirrorSnapshot.Annotations == nil {
+		mirrorSnapshot.Annotations = map[string]string{}
+	}
+
+	// the Repository label names the PaC Repository of the primary git provider
+	delete(mirrorSnapshot.Labels, gitops.PipelineAsCodeRepositoryLabel)
+	mirrorSnapshot.Labels[gitops.PipelineAsCodeURLOrgLabel] = org
+	mirrorSnapshot.Labels[gitops.PipelineAsCodeURLRepositoryLabel] = repository
+	mirrorSnapshot.Labels[gitops.PipelineAsCodeGitProviderLabel] = r.gitProvider
+
+	for _, annotation := range mirrorSnapshotAnnotations {
+		delete(mirrorSnapshot.Annotations, annotation)
+	}
+	mirrorSnapshot.Annotations[gitops.PipelineAsCodeRepoURLAnnotation] = r.mirrorURL
+	mirrorSnapshot.Annotations[gitops.PipelineAsCodeGitProviderAnnotation] = r.gitProvider
+
+	return mirrorSnapshot, nil
+}
+
+// parseMirrorURL returns the organization and the repository of the given repository URL, the organization of
+// repositories in nested groups contains all the groups
+func parseMirrorURL(mirrorURL string) (string, string, error) {
+	burl, err := url.Parse(mirrorURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse mirror URL %q: %w", mirrorURL, err)
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(burl.Path, "/"), ".git")
+	separator := strings.LastIndex(repoPath, "/")
+	if burl.Host == "" || separator <= 0 {
+		return "", "", fmt.Errorf("mirror URL %q has no host, organization or repository", mirrorURL)
+	}
+	return repoPath[:separator], repoPath[separator+1:], nil
+}
+
+// mirror is a mirror of the snapshot repository listed in the also-report-to annotation
+type mirror struct {
+	gitProvider string
+	url         string
+}
+
+// parseAlsoReportTo returns the mirrors listed in the given value of the also-report-to annotation,
+// malformed entries are returned separately
+func parseAlsoReportTo(alsoReportTo string) ([]mirror, []string) {
+	mirrors := []mirror{}
+	malformed := []string{}
+	for _, entry := range strings.Split(alsoReportTo, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		gitProvider, mirrorURL, found := strings.Cut(entry, "=")
+		gitProvider, mirrorURL = strings.TrimSpace(gitProvider), strings.TrimSpace(mirrorURL)
+		if !found || gitProvider == "" || mirrorURL == "" {
+			malformed = append(malformed, entry)
+			continue
+		}
+		mirrors = append(mirrors, mirror{gitProvider: gitProvider, url: mirrorURL})
+	}
+	return mirrors, malformed
+}
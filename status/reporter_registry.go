@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/konflux-ci/integration-service/gitops"
+)
+
+// ReporterFactory creates a reporter which isn't initialized yet
+type ReporterFactory = func(logr.Logger, client.Client) ReporterInterface
+
+// reporterFactory creates a reporter which only logs the reports when dryRun is true
+type reporterFactory = func(logger logr.Logger, k8sClient client.Client, dryRun bool) ReporterInterface
+
+// registeredReporter is a reporter factory registered for a git provider
+type registeredReporter struct {
+	name    string
+	factory reporterFactory
+}
+
+var (
+	reporterRegistryMutex sync.RWMutex
+	// reporterRegistry holds the reporters in the order in which they are consulted by GetReporter
+	reporterRegistry = []registeredReporter{}
+)
+
+func init() {
+	registerReporter(gitops.PipelineAsCodeGitHubProviderType, func(logger logr.Logger, k8sClient client.Client, dryRun bool) ReporterInterface {
+		opts := []GitHubReporterOption{}
+		if dryRun {
+			opts = append(opts, WithGitHubDryRun())
+		}
+		return NewGitHubReporter(logger, k8sClient, opts...)
+	})
+	registerReporter(gitops.PipelineAsCodeGitLabProviderType, func(logger logr.Logger, k8sClient client.Client, dryRun bool) ReporterInterface {
+		opts := []GitLabReporterOption{}
+		if dryRun {
+			opts = append(opts, WithGitLabDryRun())
+		}
+		return NewGitLabReporter(logger, k8sClient, opts...)
+	})
+	registerReporter(gitops.PipelineAsCodeGiteaProviderType, func(logger logr.Logger, k8sClient client.Client, dryRun bool) ReporterInterface {
+		opts := []GiteaReporterOption{}
+		if dryRun {
+			opts = append(opts, WithGiteaDryRun())
+		}
+		return NewGiteaReporter(logger, k8sClient, opts...)
+	})
+	registerReporter(gitops.PipelineAsCodeAzureDevOpsProviderType, func(logger logr.Logger, k8sClient client.Client, dryRun bool) ReporterInterface {
+		opts := []AzureDevOpsReporterOption{}
+		if dryRun {
+			opts = append(opts, WithAzureDevOpsDryRun())
+		}
+		return NewAzureDevOpsReporter(logger, k8sClient, opts...)
+	})
+}
+
+// RegisterReporter registers the factory of a reporter for the git provider of the given name, GetReporter
+// returns the first registered reporter which detects the snapshot. Reporters are consulted in the order
+// of their registration after the built-in ones, registering a reporter under an already registered name
+// replaces the factory while keeping its position. The dry-run setting of Status isn't passed to the
+// reporters registered this way.
+func RegisterReporter(name string, factory ReporterFactory) {
+	registerReporter(name, func(logger logr.Logger, k8sClient client.Client, _ bool) ReporterInterface {
+		return factory(logger, k8sClient)
+	})
+}
+
+// UnregisterReporter removes the reporter registered for the git provider of the given name,
+// unknown names are ignored
+func UnregisterReporter(name string) {
+	reporterRegistryMutex.Lock()
+	defer reporterRegistryMutex.Unlock()
+
+	reporterRegistry = slices.DeleteFunc(reporterRegistry, func(reporter registeredReporter) bool {
+		return reporter.name == name
+	})
+}
+
+// registerReporter adds the reporter factory into the registry or replaces the factory of the same name
+func registerReporter(name string, factory reporterFactory) {
+	reporterRegistryMutex.Lock()
+	defer reporterRegistryMutex.Unlock()
+
+	for i := range reporterRegistry {
+		if reporterRegistry[i].name == name {
+			reporterRegistry[i].factory = factory
+			return
+		}
+	}
+	reporterRegistry = append(reporterRegistry, registeredReporter{name: name, factory: factory})
+}
+
+// getRegisteredReporters returns a copy of the registry, so reporters can be created without holding the lock
+func getRegisteredReporters() []registeredReporter {
+	reporterRegistryMutex.RLock()
+	defer reporterRegistryMutex.RUnlock()
+
+	return append([]registeredReporter{}, reporterRegistry...)
+}
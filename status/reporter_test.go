@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/konflux-ci/integration-service/cache"
+	"github.com/konflux-ci/integration-service/gitops"
+	"github.com/konflux-ci/integration-service/status"
+)
+
+var _ = Describe("PaC Repository lookup", func() {
+	var fakeClient client.Client
+
+	newRepository := func(name, namespace, url string) *pacv1alpha1.Repository {
+		return &pacv1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       pacv1alpha1.RepositorySpec{URL: url},
+		}
+	}
+	newSnapshot := func(url string) *applicationapiv1alpha1.Snapshot {
+		return &applicationapiv1alpha1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "snapshot-sample",
+				Namespace:   "default",
+				Annotations: map[string]string{gitops.PipelineAsCodeRepoURLAnnotation: url},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(pacv1alpha1.AddToScheme(scheme)).To(Succeed())
+		// the lookup fails when the index registered at the setup of the manager is missing
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(
+				newRepository("repo-b", "default", "https://github.com/org/repo.git"),
+				newRepository("repo-a", "default", "https://github.com/org/repo/"),
+				newRepository("other-repo", "default", "https://github.com/org/other-repo"),
+				newRepository("repo-elsewhere", "other-namespace", "https://github.com/org/elsewhere"),
+			).
+			WithIndex(&pacv1alpha1.Repository{}, gitops.PacRepositoryURLField, cache.PacRepositoryURLIndexFunc).
+			Build()
+	})
+
+	DescribeTable("finds the Repository matching the normalized URL",
+		func(url, expectedName string) {
+			repository, err := status.GetPACRepository(context.Background(), fakeClient, newSnapshot(url))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repository).NotTo(BeNil())
+			Expect(repository.Name).To(Equal(expectedName))
+		},
+		Entry("plain URL", "https://github.com/org/other-repo", "other-repo"),
+		Entry("URL with a trailing slash", "https://github.com/org/other-repo/", "other-repo"),
+		Entry("URL with a .git suffix", "https://github.com/org/other-repo.git", "other-repo"),
+		Entry("URL with a .git suffix and a trailing slash", "https://github.com/org/other-repo.git/", "other-repo"),
+		Entry("URL matching several Repositories", "https://github.com/org/repo", "repo-a"),
+	)
+
+	It("returns no Repository when none matches the URL", func() {
+		repository, err := status.GetPACRepository(context.Background(), fakeClient, newSnapshot("https://github.com/org/missing"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository).To(BeNil())
+	})
+
+	It("doesn't find the Repositories of other namespaces", func() {
+		repository, err := status.GetPACRepository(context.Background(), fakeClient, newSnapshot("https://github.com/org/elsewhere"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repository).To(BeNil())
+	})
+})
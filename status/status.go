@@ -21,17 +21,25 @@ package status
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/konflux-ci/integration-service/git/github"
 	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/helpers"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	clienterrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -39,33 +47,132 @@ import (
 // NamePrefix is a common name prefix for this service.
 const NamePrefix = "Red Hat Konflux"
 
+// NoIntegrationTestsReportName is the name of the report sent for the snapshots of applications without
+// IntegrationTestScenarios, in place of the names of the scenarios
+const NoIntegrationTestsReportName = "integration-tests"
+
+// IntegrationTestsDocumentationURL links to the documentation explaining how to configure integration tests
+const IntegrationTestsDocumentationURL = "https://konflux-ci.dev/docs/testing/integration/"
+
+// MaxCheckPrefixLength is the maximum length of the check name prefix configured for an Application
+const MaxCheckPrefixLength = 32
+
+// checkPrefixIllegalCharsRegex matches characters which are not allowed in the check name prefix
+var checkPrefixIllegalCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// MaxConcurrentReports is the maximum number of test reports sent to the git provider at the same time
+const MaxConcurrentReports = 4
+
+const (
+	// DryRunEnvVar enables dry-run reporting to git providers for the whole cluster when set to "true"
+	DryRunEnvVar = "GIT_REPORTING_DRY_RUN"
+
+	// DryRunAnnotation overrides the cluster-wide dry-run setting for an Application when set to "true" or "false"
+	DryRunAnnotation = "test.appstudio.openshift.io/git-reporting-dry-run"
+)
+
+const (
+	// ReportRetryBaseDelay is the delay before the report of a scenario is retried after its first failed attempt,
+	// the delay doubles with each consecutive failed attempt
+	ReportRetryBaseDelay = 5 * time.Second
+
+	// ReportRetryMaxDelay caps the delay between the attempts to report a scenario whose reports keep failing
+	ReportRetryMaxDelay = 5 * time.Minute
+)
+
+// ReportRetryError is returned when some of the test reports failed or were deferred after their failed attempts,
+// RetryAfter is the delay after which all of them can be retried, derived from the worst of the failures
+type ReportRetryError struct {
+	RetryAfter time.Duration
+	err        error
+}
+
+func (e *ReportRetryError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.err, e.RetryAfter)
+}
+
+func (e *ReportRetryError) Unwrap() error {
+	return e.err
+}
+
+// GetReportRetryBackoff returns the delay before the next attempt to report a scenario after the given number
+// of consecutive failed attempts
+func GetReportRetryBackoff(failedAttempts int) time.Duration {
+	if failedAttempts <= 0 {
+		return 0
+	}
+	backoff := ReportRetryBaseDelay
+	for i := 1; i < failedAttempts && backoff < ReportRetryMaxDelay; i++ {
+		backoff *= 2
+	}
+	return min(backoff, ReportRetryMaxDelay)
+}
+
+// GetRateLimitRetryAfter returns the delay requested by the git provider when the given error
+// was caused by its rate limit
+func GetRateLimitRetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *github.SecondaryRateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+	var gitLabRateLimitErr *GitLabRateLimitError
+	if errors.As(err, &gitLabRateLimitErr) {
+		return gitLabRateLimitErr.RetryAfter, true
+	}
+	return 0, false
+}
+
 // ScenarioReportStatus keep report status of git provider for the particular scenario
 type ScenarioReportStatus struct {
 	LastUpdateTime *time.Time `json:"lastUpdateTime"`
+	// LastAttemptTime is the time of the last attempt to report the scenario, successful or not
+	LastAttemptTime *time.Time `json:"lastAttemptTime,omitempty"`
+	// FailedAttempts is the number of consecutive failed attempts to report the scenario
+	FailedAttempts int `json:"failedAttempts,omitempty"`
+}
+
+// ReporterReportStatus keeps report status of a secondary reporter for the snapshot
+type ReporterReportStatus struct {
+	Scenarios map[string]*ScenarioReportStatus `json:"scenarios"`
 }
 
 // SnapshotReportStatus keep report status of git provider for the snapshot
 type SnapshotReportStatus struct {
 	Scenarios map[string]*ScenarioReportStatus `json:"scenarios"`
+	// Reporters keeps report status of the secondary reporters by their names, the primary reporter uses Scenarios
+	Reporters map[string]*ReporterReportStatus `json:"reporters,omitempty"`
 	dirty     bool
 }
 
 // SetLastUpdateTime updates the last udpate time of the given scenario to the given time
 func (srs *SnapshotReportStatus) SetLastUpdateTime(scenarioName string, t time.Time) {
+	srs.SetReporterLastUpdateTime("", scenarioName, t)
+}
+
+// IsNewer returns true if given scenario has newer time than the last updated
+func (srs *SnapshotReportStatus) IsNewer(scenarioName string, t time.Time) bool {
+	return srs.IsNewerForReporter("", scenarioName, t)
+}
+
+// SetReporterLastUpdateTime updates the last udpate time of the given scenario reported by the given secondary reporter,
+// empty reporter name stands for the primary reporter
+func (srs *SnapshotReportStatus) SetReporterLastUpdateTime(reporterName, scenarioName string, t time.Time) {
 	srs.dirty = true
-	if scenario, ok := srs.Scenarios[scenarioName]; ok {
+	scenarios := srs.getScenarios(reporterName)
+	if scenario, ok := scenarios[scenarioName]; ok {
 		scenario.LastUpdateTime = &t
 		return
 	}
 
-	srs.Scenarios[scenarioName] = &ScenarioReportStatus{
+	scenarios[scenarioName] = &ScenarioReportStatus{
 		LastUpdateTime: &t,
 	}
 }
 
-// IsNewer returns true if given scenario has newer time than the last updated
-func (srs *SnapshotReportStatus) IsNewer(scenarioName string, t time.Time) bool {
-	if scenario, ok := srs.Scenarios[scenarioName]; ok {
+// IsNewerForReporter returns true if given scenario has newer time than the last one reported by the given secondary
+// reporter, empty reporter name stands for the primary reporter
+func (srs *SnapshotReportStatus) IsNewerForReporter(reporterName, scenarioName string, t time.Time) bool {
+	if scenario, ok := srs.getScenarios(reporterName)[scenarioName]; ok && scenario.LastUpdateTime != nil {
 		return scenario.LastUpdateTime.Before(t)
 	}
 
@@ -73,6 +180,52 @@ func (srs *SnapshotReportStatus) IsNewer(scenarioName string, t time.Time) bool
 	return true
 }
 
+// RecordReporterAttempt records the attempt to report the given scenario by the given reporter at the given time,
+// empty reporter name stands for the primary reporter. It returns the number of consecutive failed attempts.
+func (srs *SnapshotReportStatus) RecordReporterAttempt(reporterName, scenarioName string, t time.Time, failed bool) int {
+	srs.dirty = true
+	scenarios := srs.getScenarios(reporterName)
+	scenario, ok := scenarios[scenarioName]
+	if !ok {
+		scenario = &ScenarioReportStatus{}
+		scenarios[scenarioName] = scenario
+	}
+	scenario.LastAttemptTime = &t
+	if failed {
+		scenario.FailedAttempts++
+	} else {
+		scenario.FailedAttempts = 0
+	}
+	return scenario.FailedAttempts
+}
+
+// GetReporterRetryDelay returns how long the report of the given scenario by the given reporter has to be deferred
+// after its failed attempts, zero when it can be reported right away
+func (srs *SnapshotReportStatus) GetReporterRetryDelay(reporterName, scenarioName string, now time.Time) time.Duration {
+	scenario, ok := srs.getScenarios(reporterName)[scenarioName]
+	if !ok || scenario.FailedAttempts == 0 || scenario.LastAttemptTime == nil {
+		return 0
+	}
+	return max(scenario.LastAttemptTime.Add(GetReportRetryBackoff(scenario.FailedAttempts)).Sub(now), 0)
+}
+
+// getScenarios returns report status of scenarios of the reporter, empty reporter name stands for the primary reporter
+func (srs *SnapshotReportStatus) getScenarios(reporterName string) map[string]*ScenarioReportStatus {
+	if reporterName == "" {
+		return srs.Scenarios
+	}
+
+	if srs.Reporters == nil {
+		srs.Reporters = map[string]*ReporterReportStatus{}
+	}
+	reporter, ok := srs.Reporters[reporterName]
+	if !ok || reporter.Scenarios == nil {
+		reporter = &ReporterReportStatus{Scenarios: map[string]*ScenarioReportStatus{}}
+		srs.Reporters[reporterName] = reporter
+	}
+	return reporter.Scenarios
+}
+
 // ToAnnotationString exports data in format for annotation
 func (srs *SnapshotReportStatus) ToAnnotationString() (string, error) {
 	byteVar, err := json.Marshal(srs)
@@ -181,42 +334,160 @@ func MigrateSnapshotToReportStatus(s *applicationapiv1alpha1.Snapshot, testStatu
 }
 
 type StatusInterface interface {
+	EmitTestStatusEvents(context.Context, *applicationapiv1alpha1.Snapshot) error
 	GetReporter(*applicationapiv1alpha1.Snapshot) ReporterInterface
+	GetReporters(*applicationapiv1alpha1.Snapshot) []ReporterInterface
 	ReportSnapshotStatus(context.Context, ReporterInterface, *applicationapiv1alpha1.Snapshot) error
+	ReportSnapshotStatusToReporters(context.Context, []ReporterInterface, *applicationapiv1alpha1.Snapshot) error
+	GetPRMRStateInSnapshot(context.Context, *applicationapiv1alpha1.Snapshot) (PRMRState, error)
 }
 
 type Status struct {
-	logger logr.Logger
-	client client.Client
+	logger       logr.Logger
+	client       client.Client
+	podLogClient PodLogClient
+	eventEmitter *EventEmitter
+	dryRun       bool
 }
 
 // check if interface has been implemented correctly
 var _ StatusInterface = (*Status)(nil)
 
-func NewStatus(logger logr.Logger, client client.Client) *Status {
-	return &Status{
+// StatusOption is used to extend Status with optional parameters.
+type StatusOption = func(s *Status)
+
+// WithPodLogClient sets the client used to fetch logs of failed steps which are attached to the failure reports
+func WithPodLogClient(podLogClient PodLogClient) StatusOption {
+	return func(s *Status) {
+		s.podLogClient = podLogClient
+	}
+}
+
+// WithEventEmitter sets the emitter of events about integration test lifecycle transitions
+func WithEventEmitter(eventEmitter *EventEmitter) StatusOption {
+	return func(s *Status) {
+		s.eventEmitter = eventEmitter
+	}
+}
+
+// WithDryRun makes reporters only log the reports they would send to the git provider
+func WithDryRun(dryRun bool) StatusOption {
+	return func(s *Status) {
+		s.dryRun = dryRun
+	}
+}
+
+func NewStatus(logger logr.Logger, client client.Client, opts ...StatusOption) *Status {
+	status := Status{
 		logger: logger,
 		client: client,
 	}
+
+	for _, opt := range opts {
+		opt(&status)
+	}
+
+	return &status
 }
 
-// GetReporter returns reporter to process snapshot using the right git provider, nil means no suitable reporter found
+// GetReporter returns reporter to process snapshot using the right git provider, nil means no suitable reporter found.
+// The registered reporters are consulted in the order of their registration, see RegisterReporter.
 func (s *Status) GetReporter(snapshot *applicationapiv1alpha1.Snapshot) ReporterInterface {
-	githubReporter := NewGitHubReporter(s.logger, s.client)
-	if githubReporter.Detect(snapshot) {
-		return githubReporter
+	for _, registered := range getRegisteredReporters() {
+		reporter := registered.factory(s.logger, s.client, s.dryRun)
+		if reporter.Detect(snapshot) {
+			return reporter
+		}
 	}
 
-	gitlabReporter := NewGitLabReporter(s.logger, s.client)
-	if gitlabReporter.Detect(snapshot) {
-		return gitlabReporter
+	return nil
+}
+
+// GetReporters returns the reporter of the git provider of the snapshot followed by the reporters of the mirrors
+// of its repository listed in the test.appstudio.openshift.io/also-report-to annotation. The reporters of the mirrors
+// use the organization, repository and credentials of the mirror and report only commit statuses, as the pull/merge
+// request of the mirror isn't known. Secondary reporters are returned only together with the primary one, malformed
+// entries and unknown git providers are skipped.
+func (s *Status) GetReporters(snapshot *applicationapiv1alpha1.Snapshot) []ReporterInterface {
+	primary := s.GetReporter(snapshot)
+	if primary == nil {
+		return []ReporterInterface{}
 	}
 
-	return nil
+	reporters := []ReporterInterface{primary}
+	alsoReportTo, ok := snapshot.GetAnnotations()[gitops.AlsoReportToAnnotation]
+	if !ok {
+		return reporters
+	}
+
+	mirrors, malformed := parseAlsoReportTo(alsoReportTo)
+	for _, entry := range malformed {
+		s.logger.Info("Mirror isn't in the <git provider>=<repository URL> format, skipping it",
+			"snapshot.Namespace", snapshot.Namespace, "snapshot.Name", snapshot.Name, "mirror", entry)
+	}
+
+	registered := getRegisteredReporters()
+	for _, m := range mirrors {
+		index := slices.IndexFunc(registered, func(r registeredReporter) bool {
+			return r.name == m.gitProvider
+		})
+		if index < 0 {
+			s.logger.Info("No reporter is registered for the git provider of the mirror, skipping it",
+				"snapshot.Namespace", snapshot.Namespace, "snapshot.Name", snapshot.Name, "gitProvider", m.gitProvider, "mirrorURL", m.url)
+			continue
+		}
+		reporter := newMirrorReporter(registered[index].factory(s.logger, s.client, s.dryRun), m.gitProvider, m.url)
+		if !containsReporter(reporters, reporter.GetReporterName()) {
+			reporters = append(reporters, reporter)
+		}
+	}
+
+	return reporters
+}
+
+// containsReporter returns true if the list contains a reporter of the given name
+func containsReporter(reporters []ReporterInterface, reporterName string) bool {
+	for _, reporter := range reporters {
+		if reporter.GetReporterName() == reporterName {
+			return true
+		}
+	}
+	return false
+}
+
+// ReportSnapshotStatusToReporters reports status of all integration tests using all the given reporters, the first
+// reporter is the primary one and the rest are secondary reporters. Report status is tracked for each reporter
+// separately, so a failure of one of them doesn't make the other ones report the same status again.
+func (s *Status) ReportSnapshotStatusToReporters(ctx context.Context, reporters []ReporterInterface, snapshot *applicationapiv1alpha1.Snapshot) error {
+	var errs error
+	var retryAfter time.Duration
+	for i, reporter := range reporters {
+		reporterName := ""
+		if i > 0 {
+			reporterName = reporter.GetReporterName()
+		}
+		if err := s.reportSnapshotStatus(ctx, reporter, reporterName, snapshot); err != nil {
+			var retryErr *ReportRetryError
+			if errors.As(err, &retryErr) {
+				retryAfter = max(retryAfter, retryErr.RetryAfter)
+			}
+			errs = errors.Join(errs, fmt.Errorf("failed to report status using %s: %w", reporter.GetReporterName(), err))
+		}
+	}
+	if errs != nil && retryAfter > 0 {
+		return &ReportRetryError{RetryAfter: retryAfter, err: errs}
+	}
+	return errs
 }
 
 // ReportSnapshotStatus reports status of all integration tests into Pull Request
 func (s *Status) ReportSnapshotStatus(ctx context.Context, reporter ReporterInterface, snapshot *applicationapiv1alpha1.Snapshot) error {
+	return s.reportSnapshotStatus(ctx, reporter, "", snapshot)
+}
+
+// reportSnapshotStatus reports status of all integration tests which were updated since they were last reported
+// by the reporter, empty reporter name stands for the primary reporter
+func (s *Status) reportSnapshotStatus(ctx context.Context, reporter ReporterInterface, reporterName string, snapshot *applicationapiv1alpha1.Snapshot) error {
 
 	statuses, err := gitops.NewSnapshotIntegrationTestStatusesFromSnapshot(snapshot)
 	if err != nil {
@@ -226,6 +497,9 @@ func (s *Status) ReportSnapshotStatus(ctx context.Context, reporter ReporterInte
 	}
 
 	integrationTestStatusDetails := statuses.GetStatuses()
+	if len(integrationTestStatusDetails) == 0 && gitops.IsSnapshotWithoutIntegrationTests(snapshot) {
+		return s.reportNoIntegrationTests(ctx, reporter, reporterName, snapshot)
+	}
 	if len(integrationTestStatusDetails) == 0 {
 		// no tests to report, skip
 		s.logger.Info("No test result to report to GitHub, skipping",
@@ -237,7 +511,7 @@ func (s *Status) ReportSnapshotStatus(ctx context.Context, reporter ReporterInte
 		s.logger.Error(err, "Failed to initialize reporter", "reporter", reporter.GetReporterName())
 		return fmt.Errorf("failed to initialize reporter: %w", err)
 	}
-	s.logger.Info("Reporter initialized", "reporter", reporter.GetReporterName())
+	s.logger.Info("Reporter initialized", "reporter", reporter.GetReporterName(), "dryRun", s.dryRun)
 
 	MigrateSnapshotToReportStatus(snapshot, integrationTestStatusDetails)
 
@@ -248,35 +522,202 @@ func (s *Status) ReportSnapshotStatus(ctx context.Context, reporter ReporterInte
 		srs, _ = NewSnapshotReportStatus("")
 	}
 
+	// all scenarios of the snapshot can be retested, list them in the reports; the matrix combinations
+	// of a scenario are retested together
+	scenarioNames := []string{}
 	for _, integrationTestStatusDetail := range integrationTestStatusDetails {
-		if srs.IsNewer(integrationTestStatusDetail.ScenarioName, integrationTestStatusDetail.LastUpdateTime) {
+		scenarioNames = append(scenarioNames, helpers.GetScenarioNameFromTestName(integrationTestStatusDetail.ScenarioName))
+	}
+	sort.Strings(scenarioNames)
+	scenarioNames = slices.Compact(scenarioNames)
+
+	// collect all reports first, so they can be sent using the same reporter at once
+	var errs error
+	var retryAfter time.Duration
+	now := time.Now()
+	pendingDetails := []*intgteststat.IntegrationTestStatusDetail{}
+	pendingReports := []*TestReport{}
+	for _, integrationTestStatusDetail := range integrationTestStatusDetails {
+		if integrationTestStatusDetail.Scheduled {
+			s.logger.Info("Integration Test was triggered by the schedule of the scenario, skipping its report",
+				"scenario.Name", integrationTestStatusDetail.ScenarioName)
+			continue
+		}
+		if srs.IsNewerForReporter(reporterName, integrationTestStatusDetail.ScenarioName, integrationTestStatusDetail.LastUpdateTime) {
 			s.logger.Info("Integration Test contains new status updates", "scenario.Name", integrationTestStatusDetail.ScenarioName)
 		} else {
 			//integration test contains no changes
 			continue
 		}
+		// don't hot-loop on a report which keeps failing, it's retried once the backoff of its failed attempts passes
+		if delay := srs.GetReporterRetryDelay(reporterName, integrationTestStatusDetail.ScenarioName, now); delay > 0 {
+			s.logger.Info("Previous attempts to report the Integration Test failed, deferring the report",
+				"scenario.Name", integrationTestStatusDetail.ScenarioName, "retryAfter", delay)
+			errs = errors.Join(errs, fmt.Errorf("report of scenario %s was deferred after its failed attempts", integrationTestStatusDetail.ScenarioName))
+			retryAfter = max(retryAfter, delay)
+			continue
+		}
 		testReport, err := s.generateTestReport(ctx, *integrationTestStatusDetail, snapshot)
 		if err != nil {
-			_ = WriteSnapshotReportStatus(ctx, s.client, snapshot, srs) // try to write what was already written
-			return fmt.Errorf("failed to generate test report: %w", err)
-		}
-		if err := reporter.ReportStatus(ctx, *testReport); err != nil {
-			_ = WriteSnapshotReportStatus(ctx, s.client, snapshot, srs) // try to write what was already written
-			return fmt.Errorf("failed to update status: %w", err)
+			errs = errors.Join(errs, fmt.Errorf("failed to generate test report for scenario %s: %w", integrationTestStatusDetail.ScenarioName, err))
+			continue
 		}
-		srs.SetLastUpdateTime(integrationTestStatusDetail.ScenarioName, integrationTestStatusDetail.LastUpdateTime)
+		testReport.ScenarioNames = scenarioNames
+		pendingDetails = append(pendingDetails, integrationTestStatusDetail)
+		pendingReports = append(pendingReports, testReport)
+	}
+
+	// failure of a single report doesn't prevent sending the rest of them
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, MaxConcurrentReports)
+	for i := range pendingReports {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(detail *intgteststat.IntegrationTestStatusDetail, testReport *TestReport) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := reporter.ReportStatus(ctx, *testReport)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to update status of scenario %s: %w", detail.ScenarioName, err))
+				failedAttempts := srs.RecordReporterAttempt(reporterName, detail.ScenarioName, now, true)
+				retryAfter = max(retryAfter, GetReportRetryBackoff(failedAttempts))
+				if rateLimitRetryAfter, ok := GetRateLimitRetryAfter(err); ok {
+					retryAfter = max(retryAfter, rateLimitRetryAfter)
+				}
+				return
+			}
+			srs.RecordReporterAttempt(reporterName, detail.ScenarioName, now, false)
+			srs.SetReporterLastUpdateTime(reporterName, detail.ScenarioName, detail.LastUpdateTime)
+		}(pendingDetails[i], pendingReports[i])
+	}
+	wg.Wait()
+
+	// write what was already reported, even if some of the reports failed
+	if err := WriteSnapshotReportStatus(ctx, s.client, snapshot, srs); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("failed to write snapshot report status metadata: %w", err))
+	}
+
+	if errs != nil && retryAfter > 0 {
+		return &ReportRetryError{RetryAfter: retryAfter, err: errs}
+	}
+	return errs
+}
+
+// reportNoIntegrationTests reports that no integration tests are configured for the application of the snapshot,
+// so the pull/merge request doesn't look like its tests passed silently. The report is sent once per reporter.
+func (s *Status) reportNoIntegrationTests(ctx context.Context, reporter ReporterInterface, reporterName string, snapshot *applicationapiv1alpha1.Snapshot) error {
+	srs, err := NewSnapshotReportStatusFromSnapshot(snapshot)
+	if err != nil {
+		s.logger.Error(err, "failed to get latest snapshot write metadata annotation for snapshot",
+			"snapshot.NameSpace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
+		srs, _ = NewSnapshotReportStatus("")
+	}
+
+	updateTime := snapshot.GetCreationTimestamp().Time
+	if !srs.IsNewerForReporter(reporterName, NoIntegrationTestsReportName, updateTime) {
+		return nil
+	}
+	now := time.Now()
+	if delay := srs.GetReporterRetryDelay(reporterName, NoIntegrationTestsReportName, now); delay > 0 {
+		s.logger.Info("Previous attempts to report that no integration tests are configured failed, deferring the report",
+			"retryAfter", delay)
+		return &ReportRetryError{RetryAfter: delay, err: fmt.Errorf("report of missing integration tests was deferred after its failed attempts")}
+	}
 
+	if err := reporter.Initialize(ctx, snapshot); err != nil {
+		s.logger.Error(err, "Failed to initialize reporter", "reporter", reporter.GetReporterName())
+		return fmt.Errorf("failed to initialize reporter: %w", err)
 	}
+	s.logger.Info("Reporter initialized", "reporter", reporter.GetReporterName(), "dryRun", s.dryRun)
+
+	var errs error
+	var retryAfter time.Duration
+	if err := reporter.ReportStatus(ctx, GenerateNoIntegrationTestsReport(snapshot)); err != nil {
+		errs = fmt.Errorf("failed to report that no integration tests are configured: %w", err)
+		failedAttempts := srs.RecordReporterAttempt(reporterName, NoIntegrationTestsReportName, now, true)
+		retryAfter = GetReportRetryBackoff(failedAttempts)
+		if rateLimitRetryAfter, ok := GetRateLimitRetryAfter(err); ok {
+			retryAfter = max(retryAfter, rateLimitRetryAfter)
+		}
+	} else {
+		srs.RecordReporterAttempt(reporterName, NoIntegrationTestsReportName, now, false)
+		srs.SetReporterLastUpdateTime(reporterName, NoIntegrationTestsReportName, updateTime)
+	}
+
 	if err := WriteSnapshotReportStatus(ctx, s.client, snapshot, srs); err != nil {
-		return fmt.Errorf("failed to write snapshot report status metadata: %w", err)
+		errs = errors.Join(errs, fmt.Errorf("failed to write snapshot report status metadata: %w", err))
 	}
 
-	return nil
+	if errs != nil && retryAfter > 0 {
+		return &ReportRetryError{RetryAfter: retryAfter, err: errs}
+	}
+	return errs
+}
+
+// GenerateNoIntegrationTestsReport generates the TestReport stating that no integration tests are configured for
+// the application of the snapshot. It's reported as skipped, so it neither blocks nor passes the pull/merge request.
+func GenerateNoIntegrationTestsReport(snapshot *applicationapiv1alpha1.Snapshot) TestReport {
+	creationTime := snapshot.GetCreationTimestamp().Time
+	return TestReport{
+		Text: fmt.Sprintf("%s\n\nThe snapshot was marked as passed without testing. See %s to learn how to configure integration tests.",
+			snapshot.GetAnnotations()[gitops.SnapshotNoIntegrationTestsAnnotation], IntegrationTestsDocumentationURL),
+		FullName:       formatReportFullName(NoIntegrationTestsReportName, snapshot),
+		ScenarioName:   NoIntegrationTestsReportName,
+		SnapshotName:   snapshot.Name,
+		ComponentName:  snapshot.Labels[gitops.SnapshotComponentLabel],
+		Status:         intgteststat.IntegrationTestStatusSkipped,
+		Summary:        fmt.Sprintf("No integration tests are configured for snapshot %s", snapshot.Name),
+		StartTime:      &creationTime,
+		CompletionTime: &creationTime,
+	}
+}
+
+// GetPRMRStateInSnapshot returns the state of the pull/merge request which triggered the snapshot. The pull/merge
+// request is considered opened when the git provider of the snapshot has no reporter or its reporter can't get the state.
+func (s *Status) GetPRMRStateInSnapshot(ctx context.Context, snapshot *applicationapiv1alpha1.Snapshot) (PRMRState, error) {
+	reporter := s.GetReporter(snapshot)
+	if reporter == nil {
+		return PRMRStateOpened, nil
+	}
+	stateReporter, ok := reporter.(PRMRStateReporter)
+	if !ok {
+		s.logger.Info("Reporter can't get the state of the pull/merge request, considering it opened",
+			"reporter", reporter.GetReporterName(), "snapshot.Namespace", snapshot.Namespace, "snapshot.Name", snapshot.Name)
+		return PRMRStateOpened, nil
+	}
+
+	if err := reporter.Initialize(ctx, snapshot); err != nil {
+		return "", fmt.Errorf("failed to initialize reporter: %w", err)
+	}
+
+	state, err := stateReporter.GetPRMRState(ctx, snapshot)
+	if err != nil {
+		return "", fmt.Errorf("failed to get state of the pull/merge request using %s: %w", reporter.GetReporterName(), err)
+	}
+	return state, nil
+}
+
+// IsDryRunEnabled returns true if reporting to git providers should only be logged for the given Application.
+// The cluster-wide setting from the GIT_REPORTING_DRY_RUN environment variable can be overridden by
+// the test.appstudio.openshift.io/git-reporting-dry-run annotation of the Application.
+func IsDryRunEnabled(application *applicationapiv1alpha1.Application) bool {
+	if application != nil {
+		if value, ok := application.GetAnnotations()[DryRunAnnotation]; ok {
+			return value == "true"
+		}
+	}
+
+	return os.Getenv(DryRunEnvVar) == "true"
 }
 
 // generateTestReport generates TestReport to be used by all reporters
 func (s *Status) generateTestReport(ctx context.Context, detail intgteststat.IntegrationTestStatusDetail, snapshot *applicationapiv1alpha1.Snapshot) (*TestReport, error) {
-	text, err := s.generateText(ctx, detail, snapshot.Namespace)
+	text, failedTaskName, err := s.generateText(ctx, detail, snapshot.Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate text message: %w", err)
 	}
@@ -285,15 +726,39 @@ func (s *Status) generateTestReport(ctx context.Context, detail intgteststat.Int
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate summary message: %w", err)
 	}
-
-	fullName := fmt.Sprintf("%s / %s", NamePrefix, detail.ScenarioName)
-	if snapshot.Labels[gitops.SnapshotComponentLabel] != "" {
-		fullName = fmt.Sprintf("%s / %s", fullName, snapshot.Labels[gitops.SnapshotComponentLabel])
+	if detail.Status == intgteststat.IntegrationTestStatusTestFail && detail.TimedOutAfter != "" {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s timed out after %s",
+			snapshot.Name, detail.ScenarioName, detail.TimedOutAfter)
+	}
+	if detail.Status == intgteststat.IntegrationTestStatusTestFail && detail.WatchdogTimedOutAfter != "" {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s was failed by the watchdog timeout after being in progress longer than %s",
+			snapshot.Name, detail.ScenarioName, detail.WatchdogTimedOutAfter)
+	}
+	if infrastructureFailure, ok := helpers.GetInfrastructureFailureFromDetails(detail.Details); ok && detail.Status == intgteststat.IntegrationTestStatusTestFail {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s failed because of an infrastructure failure: %s",
+			snapshot.Name, detail.ScenarioName, infrastructureFailure)
+	}
+	if detail.Status == intgteststat.IntegrationTestStatusDeleted && gitops.IsSnapshotSuperseded(snapshot) {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s was canceled because the snapshot was superseded by snapshot %s",
+			snapshot.Name, detail.ScenarioName, snapshot.GetAnnotations()[gitops.SnapshotSupersededByAnnotation])
+	}
+	if detail.Status == intgteststat.IntegrationTestStatusDeleted && gitops.IsSnapshotObsoletedByOverride(snapshot) {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s was canceled because the snapshot was obsoleted by override snapshot %s",
+			snapshot.Name, detail.ScenarioName, snapshot.GetAnnotations()[gitops.SnapshotObsoletedByOverrideAnnotation])
+	}
+	if detail.Status == intgteststat.IntegrationTestStatusDeleted && gitops.IsSnapshotCanceledByPRMR(snapshot) {
+		summary = fmt.Sprintf("Integration test for snapshot %s and scenario %s was canceled because the pull request was %s",
+			snapshot.Name, detail.ScenarioName, snapshot.GetAnnotations()[gitops.SnapshotCanceledByPRMRAnnotation])
+	}
+	if detail.ReusedFrom != "" {
+		summary = fmt.Sprintf("%s (result reused from snapshot %s)", summary, detail.ReusedFrom)
+		text = fmt.Sprintf("The test didn't run again, its result was reused from snapshot %s with identical components.\n\n%s",
+			detail.ReusedFrom, text)
 	}
 
 	report := TestReport{
 		Text:                text,
-		FullName:            fullName,
+		FullName:            formatReportFullName(detail.ScenarioName, snapshot),
 		ScenarioName:        detail.ScenarioName,
 		SnapshotName:        snapshot.Name,
 		ComponentName:       snapshot.Labels[gitops.SnapshotComponentLabel],
@@ -302,12 +767,37 @@ func (s *Status) generateTestReport(ctx context.Context, detail intgteststat.Int
 		StartTime:           detail.StartTime,
 		CompletionTime:      detail.CompletionTime,
 		TestPipelineRunName: detail.TestPipelineRunName,
+		FailedTaskName:      failedTaskName,
 	}
 	return &report, nil
 }
 
-// generateText generates a text with details for the given state
-func (s *Status) generateText(ctx context.Context, integrationTestStatusDetail intgteststat.IntegrationTestStatusDetail, namespace string) (string, error) {
+// formatReportFullName returns the name of the report of the given scenario shown by the git provider, it includes
+// the component of the snapshot and the check prefix configured for the snapshot
+func formatReportFullName(scenarioName string, snapshot *applicationapiv1alpha1.Snapshot) string {
+	fullName := fmt.Sprintf("%s / %s", NamePrefix, scenarioName)
+	if snapshot.Labels[gitops.SnapshotComponentLabel] != "" {
+		fullName = fmt.Sprintf("%s / %s", fullName, snapshot.Labels[gitops.SnapshotComponentLabel])
+	}
+	if checkPrefix := SanitizeCheckPrefix(snapshot.GetAnnotations()[gitops.CheckPrefixAnnotation]); checkPrefix != "" {
+		fullName = fmt.Sprintf("%s/%s", checkPrefix, fullName)
+	}
+	return fullName
+}
+
+// SanitizeCheckPrefix replaces characters which are not allowed in the check name prefix
+// and caps the length of the prefix to MaxCheckPrefixLength
+func SanitizeCheckPrefix(checkPrefix string) string {
+	checkPrefix = checkPrefixIllegalCharsRegex.ReplaceAllString(checkPrefix, "-")
+	if len(checkPrefix) > MaxCheckPrefixLength {
+		checkPrefix = checkPrefix[:MaxCheckPrefixLength]
+	}
+	return strings.Trim(checkPrefix, "-")
+}
+
+// generateText generates a text with details for the given state. For failed tests the name of the earliest
+// failing pipeline task is returned as well, so the report can link directly to its logs.
+func (s *Status) generateText(ctx context.Context, integrationTestStatusDetail intgteststat.IntegrationTestStatusDetail, namespace string) (string, string, error) {
 	if integrationTestStatusDetail.Status == intgteststat.IntegrationTestStatusTestPassed || integrationTestStatusDetail.Status == intgteststat.IntegrationTestStatusTestFail {
 		pipelineRunName := integrationTestStatusDetail.TestPipelineRunName
 		pipelineRun := &tektonv1.PipelineRun{}
@@ -317,28 +807,54 @@ func (s *Status) generateText(ctx context.Context, integrationTestStatusDetail i
 		}, pipelineRun)
 
 		if err != nil {
-			if errors.IsNotFound(err) {
+			if clienterrors.IsNotFound(err) {
 				s.logger.Error(err, "Failed to fetch pipelineRun", "pipelineRun.Name", pipelineRunName)
 				text := fmt.Sprintf("%s\n\n\n(Failed to fetch test result details.)", integrationTestStatusDetail.Details)
-				return text, nil
+				// the log excerpt of the failed step kept in the test status survives the pruning of the pipelineRun
+				if integrationTestStatusDetail.FailedStepLog != "" {
+					text += "\n" + integrationTestStatusDetail.FailedStepLog + "\n"
+				}
+				return text, "", nil
 			}
 
-			return "", fmt.Errorf("error while getting the pipelineRun %s: %w", pipelineRunName, err)
+			return "", "", fmt.Errorf("error while getting the pipelineRun %s: %w", pipelineRunName, err)
 		}
 
 		taskRuns, err := helpers.GetAllChildTaskRunsForPipelineRun(ctx, s.client, pipelineRun)
 		if err != nil {
-			return "", fmt.Errorf("error while getting all child taskRuns from pipelineRun %s: %w", pipelineRunName, err)
+			return "", "", fmt.Errorf("error while getting all child taskRuns from pipelineRun %s: %w", pipelineRunName, err)
 		}
 		text, err := FormatTestsSummary(taskRuns, pipelineRunName, namespace, s.logger)
 		if err != nil {
-			return "", err
+			return "", "", err
+		}
+		if integrationTestStatusDetail.Status == intgteststat.IntegrationTestStatusTestFail {
+			if s.podLogClient != nil {
+				text += FormatFailedStepLogs(ctx, s.podLogClient, taskRuns, namespace, s.logger)
+			} else if integrationTestStatusDetail.FailedStepLog != "" {
+				text += "\n" + integrationTestStatusDetail.FailedStepLog + "\n"
+			}
 		}
-		return text, nil
+		failedTaskName := ""
+		if integrationTestStatusDetail.Status == intgteststat.IntegrationTestStatusTestFail {
+			failedTaskName = getEarliestFailedTaskName(taskRuns)
+		}
+		return text, failedTaskName, nil
 	} else {
 		text := integrationTestStatusDetail.Details
-		return text, nil
+		return text, "", nil
+	}
+}
+
+// getEarliestFailedTaskName returns the name of the pipeline task which failed first.
+// The taskRuns are expected to be sorted by their start time.
+func getEarliestFailedTaskName(taskRuns []*helpers.TaskRun) string {
+	for _, tr := range taskRuns {
+		if tr.HasFailed() {
+			return tr.GetPipelineTaskName()
+		}
 	}
+	return ""
 }
 
 // GenerateSummary returns summary for the given state, snapshotName and scenarioName
@@ -363,6 +879,10 @@ func GenerateSummary(state intgteststat.IntegrationTestStatus, snapshotName, sce
 		statusDesc = "has failed"
 	case intgteststat.IntegrationTestStatusTestInvalid:
 		statusDesc = "is invalid"
+	case intgteststat.IntegrationTestStatusSkipped:
+		statusDesc = "was skipped"
+	case intgteststat.IntegrationTestStatusTestError:
+		statusDesc = "couldn't run because of a configuration error"
 	default:
 		return summary, fmt.Errorf("unknown status")
 	}
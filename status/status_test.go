@@ -18,19 +18,29 @@ package status_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/konflux-ci/integration-service/gitops"
 	"github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	"github.com/konflux-ci/integration-service/status"
 )
@@ -59,6 +69,78 @@ func HasSummary(value string) gomock.Matcher {
 	return hasSummary{expectedSummary: value}
 }
 
+// Custom matcher for gomock, to match expected substring of text in TestReport
+type hasTextContaining struct {
+	expectedSubstring string
+}
+
+// Matches checks if TestResult.Text contains the expected substring
+func (m hasTextContaining) Matches(arg interface{}) bool {
+	report, ok := arg.(status.TestReport)
+	if !ok {
+		return false
+	}
+	return strings.Contains(report.Text, m.expectedSubstring)
+}
+
+// String prints what we expected
+func (m hasTextContaining) String() string {
+	return fmt.Sprintf("TestReport.Text containing: \"%s\"", m.expectedSubstring)
+}
+
+// HasTextContaining matches if TestReport.Text contains the expected value
+func HasTextContaining(value string) gomock.Matcher {
+	return hasTextContaining{expectedSubstring: value}
+}
+
+// Custom matcher for gomock, to match FailedTaskName in TestReport
+type hasFailedTaskName struct {
+	expectedFailedTaskName string
+}
+
+// Matches checks if TestResult.FailedTaskName is equal to the expected value
+func (m hasFailedTaskName) Matches(arg interface{}) bool {
+	report, ok := arg.(status.TestReport)
+	if !ok {
+		return false
+	}
+	return report.FailedTaskName == m.expectedFailedTaskName
+}
+
+// String prints what we expected
+func (m hasFailedTaskName) String() string {
+	return fmt.Sprintf("TestReport.FailedTaskName = \"%s\"", m.expectedFailedTaskName)
+}
+
+// HasFailedTaskName matches if TestReport.FailedTaskName is equal to the expected value
+func HasFailedTaskName(value string) gomock.Matcher {
+	return hasFailedTaskName{expectedFailedTaskName: value}
+}
+
+// Custom matcher for gomock, to match FullName in TestReport
+type hasFullName struct {
+	expectedFullName string
+}
+
+// Matches checks if TestResult.FullName is equal to the expected value
+func (m hasFullName) Matches(arg interface{}) bool {
+	report, ok := arg.(status.TestReport)
+	if !ok {
+		return false
+	}
+	return report.FullName == m.expectedFullName
+}
+
+// String prints what we expected
+func (m hasFullName) String() string {
+	return fmt.Sprintf("TestReport.FullName = \"%s\"", m.expectedFullName)
+}
+
+// HasFullName matches if TestReport.FullName is equal to the expected value
+func HasFullName(value string) gomock.Matcher {
+	return hasFullName{expectedFullName: value}
+}
+
 var _ = Describe("Status Adapter", func() {
 
 	var (
@@ -332,6 +414,39 @@ var _ = Describe("Status Adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("reports once that no integration tests are configured for the application", func() {
+		hasSnapshot.Annotations[gitops.SnapshotNoIntegrationTestsAnnotation] = "No IntegrationTestScenarios are configured for application application-sample"
+
+		var report status.TestReport
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, testReport status.TestReport) error {
+			report = testReport
+			return nil
+		}).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		Expect(st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)).To(Succeed())
+		Expect(report.FullName).To(Equal("Red Hat Konflux / integration-tests / component-sample"))
+		Expect(report.Status).To(Equal(integrationteststatus.IntegrationTestStatusSkipped))
+
+		// already reported, the report isn't sent again
+		Expect(st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)).To(Succeed())
+	})
+
+	It("generates the report stating that no integration tests are configured", func() {
+		hasSnapshot.Annotations[gitops.SnapshotNoIntegrationTestsAnnotation] = "No IntegrationTestScenarios are configured for application application-sample"
+		hasSnapshot.Annotations[gitops.CheckPrefixAnnotation] = "konflux-prod"
+
+		report := status.GenerateNoIntegrationTestsReport(hasSnapshot)
+		Expect(report.FullName).To(Equal("konflux-prod/Red Hat Konflux / integration-tests / component-sample"))
+		Expect(report.ScenarioName).To(Equal(status.NoIntegrationTestsReportName))
+		Expect(report.Status).To(Equal(integrationteststatus.IntegrationTestStatusSkipped))
+		Expect(report.Summary).To(Equal("No integration tests are configured for snapshot " + hasSnapshot.Name))
+		Expect(report.Text).To(ContainSubstring("No IntegrationTestScenarios are configured for application application-sample"))
+		Expect(report.Text).To(ContainSubstring(status.IntegrationTestsDocumentationURL))
+		Expect(report.TestPipelineRunName).To(BeEmpty())
+	})
+
 	It("doesn't report anything when data are older", func() {
 
 		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
@@ -368,6 +483,280 @@ var _ = Describe("Status Adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("doesn't report the status of scheduled runs", func() {
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Times(0) // scheduled runs aren't reported to the git provider
+
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\",\"scheduled\":true}]"
+		hasSnapshot.Annotations["test.appstudio.openshift.io/git-reporter-status"] = "{\"scenarios\":{\"scenario1\":{\"lastUpdateTime\":\"2023-08-26T17:57:49+02:00\"}}}"
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports status using secondary reporters and tracks the report status of each reporter", func() {
+		secondaryReporter := status.NewMockReporterInterface(gomock.NewController(GinkgoT()))
+		secondaryReporter.EXPECT().GetReporterName().Return("secondary-reporter").AnyTimes()
+		secondaryReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(2)
+		gomock.InOrder(
+			secondaryReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Return(fmt.Errorf("mirror is unavailable")),
+			secondaryReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Return(nil),
+		)
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(2)
+		// the primary reporter reports only once, the failure of the secondary reporter doesn't make it report again
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Times(1)
+
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		reporters := []status.ReporterInterface{mockReporter, secondaryReporter}
+
+		err := st.ReportSnapshotStatusToReporters(context.Background(), reporters, hasSnapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to report status using secondary-reporter"))
+		srs, err := status.NewSnapshotReportStatusFromSnapshot(hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srs.Scenarios).To(HaveKey("scenario1"))
+		Expect(srs.IsNewerForReporter("secondary-reporter", "scenario1", time.Now())).To(BeTrue())
+
+		// the failed report is retried once its backoff passed
+		backdateReportAttempts(hasSnapshot, status.ReportRetryBaseDelay)
+		Expect(st.ReportSnapshotStatusToReporters(context.Background(), reporters, hasSnapshot)).To(Succeed())
+		srs, err = status.NewSnapshotReportStatusFromSnapshot(hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srs.Reporters).To(HaveKey("secondary-reporter"))
+		Expect(srs.Reporters["secondary-reporter"].Scenarios).To(HaveKey("scenario1"))
+	})
+
+	It("returns the reporters of the mirrors after the reporter of the git provider", func() {
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		reporters := st.GetReporters(githubSnapshot)
+		Expect(reporters).To(HaveLen(1))
+		Expect(reporters[0].GetReporterName()).To(Equal("GithubReporter"))
+
+		githubSnapshot.Annotations = map[string]string{
+			gitops.AlsoReportToAnnotation: "gitlab=https://gitlab.com/group/repo, github, unknown-provider=https://example.com/org/repo, github=https://github.com/org/mirror, gitlab=https://gitlab.com/group/repo",
+		}
+		reporters = st.GetReporters(githubSnapshot)
+		Expect(reporters).To(HaveLen(3))
+		Expect(reporters[0].GetReporterName()).To(Equal("GithubReporter"))
+		Expect(reporters[1].GetReporterName()).To(Equal("GitlabReporter(https://gitlab.com/group/repo)"))
+		Expect(reporters[2].GetReporterName()).To(Equal("GithubReporter(https://github.com/org/mirror)"))
+
+		githubSnapshot.Labels[gitops.PipelineAsCodeGitProviderLabel] = "unknown-provider"
+		Expect(st.GetReporters(githubSnapshot)).To(BeEmpty())
+	})
+
+	It("reports only commit statuses to a mirror using the coordinates and credentials of the mirror", func() {
+		const mirrorProjectID = 789
+		mux := http.NewServeMux()
+		server := httptest.NewServer(http.StripPrefix("/api/v4", mux))
+		defer server.Close()
+		mirrorURL := server.URL + "/mirror-group/mirror-repo"
+
+		var mutex sync.Mutex
+		tokens := map[string]bool{}
+		requests := []string{}
+		record := func(r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			tokens[r.Header.Get("PRIVATE-TOKEN")] = true
+			requests = append(requests, r.Method+" "+r.URL.Path)
+		}
+		mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+			record(r)
+			rw.WriteHeader(http.StatusNotFound)
+		})
+		mux.HandleFunc("/projects/mirror-group/mirror-repo", func(rw http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprintf(rw, "{\"id\": %d}", mirrorProjectID)
+		})
+		mux.HandleFunc(fmt.Sprintf("/projects/%d", mirrorProjectID), func(rw http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprintf(rw, "{\"id\": %d}", mirrorProjectID)
+		})
+		mux.HandleFunc(fmt.Sprintf("/projects/%d/repository/commits/6c65b2fcaea3e1a0a92476c8b5dc89e92a85f025/statuses", mirrorProjectID), func(rw http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprintf(rw, "[]")
+		})
+		mux.HandleFunc(fmt.Sprintf("/projects/%d/statuses/6c65b2fcaea3e1a0a92476c8b5dc89e92a85f025", mirrorProjectID), func(rw http.ResponseWriter, r *http.Request) {
+			record(r)
+			fmt.Fprintf(rw, "{}")
+		})
+
+		k8sClient := &MockK8sClient{
+			getInterceptor: func(key client.ObjectKey, obj client.Object) {
+				if secret, ok := obj.(*corev1.Secret); ok {
+					secret.Data = map[string][]byte{"token": []byte(key.Name + "-token")}
+				}
+			},
+			listInterceptor: func(list client.ObjectList) {
+				if repoList, ok := list.(*pacv1alpha1.RepositoryList); ok {
+					repoList.Items = []pacv1alpha1.Repository{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "primary", Namespace: "default"},
+							Spec: pacv1alpha1.RepositorySpec{
+								URL:         "https://github.com/devfile-sample/devfile-sample-go-basic",
+								GitProvider: &pacv1alpha1.GitProvider{Secret: &pacv1alpha1.Secret{Name: "primary", Key: "token"}},
+							},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "mirror", Namespace: "default"},
+							Spec: pacv1alpha1.RepositorySpec{
+								URL:         mirrorURL,
+								GitProvider: &pacv1alpha1.GitProvider{Secret: &pacv1alpha1.Secret{Name: "mirror", Key: "token"}},
+							},
+						},
+					}
+				}
+			},
+		}
+
+		hasSnapshot.Labels[gitops.PipelineAsCodeRepositoryLabel] = "primary"
+		hasSnapshot.Annotations[gitops.PipelineAsCodePullRequestAnnotation] = "45"
+		hasSnapshot.Annotations[gitops.AlsoReportToAnnotation] = "gitlab=" + mirrorURL
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"failed\"}]"
+
+		st := status.NewStatus(logr.Discard(), k8sClient)
+		reporters := st.GetReporters(hasSnapshot)
+		Expect(reporters).To(HaveLen(2))
+		Expect(reporters[1].GetReporterName()).To(Equal("GitlabReporter(" + mirrorURL + ")"))
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Times(1)
+		Expect(st.ReportSnapshotStatusToReporters(context.Background(), []status.ReporterInterface{mockReporter, reporters[1]}, hasSnapshot)).To(Succeed())
+
+		// the mirror project is looked up by the path of the mirror URL and only its commit status is set,
+		// the merge request of the primary git provider isn't commented on the mirror
+		Expect(requests).To(ContainElement(fmt.Sprintf("POST /projects/%d/statuses/6c65b2fcaea3e1a0a92476c8b5dc89e92a85f025", mirrorProjectID)))
+		for _, request := range requests {
+			Expect(request).NotTo(ContainSubstring("merge_requests"))
+			Expect(request).NotTo(ContainSubstring("devfile-sample"))
+		}
+		Expect(tokens).To(Equal(map[string]bool{"mirror-token": true}))
+		// the snapshot itself still points to the primary git provider
+		Expect(hasSnapshot.Annotations).To(HaveKeyWithValue(gitops.PipelineAsCodePullRequestAnnotation, "45"))
+		Expect(hasSnapshot.Labels).To(HaveKeyWithValue(gitops.PipelineAsCodeRepositoryLabel, "primary"))
+	})
+
+	It("reports all scenarios of snapshot and writes the report status annotation once", func() {
+		const scenarioCount = 20
+		details := []string{}
+		for i := 0; i < scenarioCount; i++ {
+			details = append(details, fmt.Sprintf("{\"scenario\":\"scenario%d\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}", i))
+		}
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[" + strings.Join(details, ",") + "]"
+
+		var mutex sync.Mutex
+		reportedScenarios := map[string]bool{}
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, report status.TestReport) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reportedScenarios[report.ScenarioName] = true
+			return nil
+		}).Times(scenarioCount)
+
+		annotationWrites := 0
+		mockK8sClient.genericInterceptor = func(obj client.Object) {
+			annotationWrites++
+		}
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reportedScenarios).To(HaveLen(scenarioCount))
+		Expect(annotationWrites).To(Equal(1))
+
+		srs, err := status.NewSnapshotReportStatusFromSnapshot(hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srs.Scenarios).To(HaveLen(scenarioCount))
+	})
+
+	It("reports remaining scenarios when reporting of one scenario fails", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[" +
+			"{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}," +
+			"{\"scenario\":\"scenario2\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}," +
+			"{\"scenario\":\"scenario3\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, report status.TestReport) error {
+			if report.ScenarioName == "scenario1" {
+				return fmt.Errorf("failed to report: 502 Bad Gateway")
+			}
+			return nil
+		}).Times(3)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to update status of scenario scenario1"))
+		var retryErr *status.ReportRetryError
+		Expect(errors.As(err, &retryErr)).To(BeTrue())
+		Expect(retryErr.RetryAfter).To(Equal(status.ReportRetryBaseDelay))
+
+		srs, err := status.NewSnapshotReportStatusFromSnapshot(hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srs.Scenarios["scenario2"].LastUpdateTime).NotTo(BeNil())
+		Expect(srs.Scenarios["scenario3"].LastUpdateTime).NotTo(BeNil())
+		Expect(srs.Scenarios["scenario1"].LastUpdateTime).To(BeNil())
+		Expect(srs.Scenarios["scenario1"].LastAttemptTime).NotTo(BeNil())
+		Expect(srs.Scenarios["scenario1"].FailedAttempts).To(Equal(1))
+		Expect(srs.IsNewer("scenario1", time.Now())).To(BeTrue())
+	})
+
+	It("defers the report of a scenario which keeps failing until its backoff passes", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(3)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Return(fmt.Errorf("failed to report")).Times(2)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		Expect(st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)).NotTo(Succeed())
+
+		// the report isn't attempted again before its backoff passes
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("report of scenario scenario1 was deferred"))
+		var retryErr *status.ReportRetryError
+		Expect(errors.As(err, &retryErr)).To(BeTrue())
+		Expect(retryErr.RetryAfter).To(BeNumerically(">", 0))
+		Expect(retryErr.RetryAfter).To(BeNumerically("<=", status.ReportRetryBaseDelay))
+
+		// the backoff doubles with the next failed attempt
+		backdateReportAttempts(hasSnapshot, status.ReportRetryBaseDelay)
+		err = st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(errors.As(err, &retryErr)).To(BeTrue())
+		Expect(retryErr.RetryAfter).To(Equal(2 * status.ReportRetryBaseDelay))
+	})
+
+	It("respects the delay requested by the rate limit of the git provider", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).Return(
+			fmt.Errorf("failed to update status: %w", &status.GitLabRateLimitError{StatusCode: 429, RetryAfter: 45 * time.Second})).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatusToReporters(context.Background(), []status.ReporterInterface{mockReporter}, hasSnapshot)
+		var retryErr *status.ReportRetryError
+		Expect(errors.As(err, &retryErr)).To(BeTrue())
+		Expect(retryErr.RetryAfter).To(Equal(45 * time.Second))
+		retryAfter, rateLimited := status.GetRateLimitRetryAfter(err)
+		Expect(rateLimited).To(BeTrue())
+		Expect(retryAfter).To(Equal(45 * time.Second))
+	})
+
+	DescribeTable("computes the backoff of the failed reports",
+		func(failedAttempts int, expectedBackoff time.Duration) {
+			Expect(status.GetReportRetryBackoff(failedAttempts)).To(Equal(expectedBackoff))
+		},
+		Entry("no failed attempt", 0, time.Duration(0)),
+		Entry("first failed attempt", 1, status.ReportRetryBaseDelay),
+		Entry("third failed attempt", 3, 4*status.ReportRetryBaseDelay),
+		Entry("capped backoff", 100, status.ReportRetryMaxDelay),
+	)
+
 	It("Report new status if it was updated (old way - migration test)", func() {
 
 		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
@@ -394,6 +783,7 @@ var _ = Describe("Status Adapter", func() {
 			Status:              integrationteststatus.IntegrationTestStatusInProgress,
 			StartTime:           &t,
 			TestPipelineRunName: "test-pipelinerun",
+			ScenarioNames:       []string{"scenario1"},
 		}
 		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
 		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Eq(expectedTestReport)).Times(1)
@@ -403,6 +793,104 @@ var _ = Describe("Status Adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("report expected textual data for compressed test status annotation", func() {
+		statuses, err := integrationteststatus.NewSnapshotIntegrationTestStatuses("[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]")
+		Expect(err).NotTo(HaveOccurred())
+		gitops.SnapshotTestsStatusCompressionThreshold = 0
+		defer func() { gitops.SnapshotTestsStatusCompressionThreshold = 32 * 1024 }()
+		value, err := gitops.EncodeIntegrationTestStatuses(statuses)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(HavePrefix(gitops.SnapshotTestsStatusCompressedPrefix))
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = value
+
+		t, err := time.Parse(time.RFC3339, "2023-07-26T16:57:49+02:00")
+		Expect(err).NotTo(HaveOccurred())
+		expectedTestReport := status.TestReport{
+			FullName:            "Red Hat Konflux / scenario1 / component-sample",
+			ScenarioName:        "scenario1",
+			SnapshotName:        "snapshot-sample",
+			ComponentName:       "component-sample",
+			Text:                "Test in progress",
+			Summary:             "Integration test for snapshot snapshot-sample and scenario scenario1 is in progress",
+			Status:              integrationteststatus.IntegrationTestStatusInProgress,
+			StartTime:           &t,
+			TestPipelineRunName: "test-pipelinerun",
+			ScenarioNames:       []string{"scenario1"},
+		}
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Eq(expectedTestReport)).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err = st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("report full name without check prefix", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasFullName("Red Hat Konflux / scenario1 / component-sample")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports each matrix combination of the scenario as a separate check", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[" +
+			"{\"scenario\":\"scenario1[ocp=4.15]\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}," +
+			"{\"scenario\":\"scenario1[ocp=4.16]\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+
+		var lock sync.Mutex
+		reports := []status.TestReport{}
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, report status.TestReport) error {
+			// the reports are sent concurrently
+			lock.Lock()
+			defer lock.Unlock()
+			reports = append(reports, report)
+			return nil
+		}).Times(2)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+
+		fullNames := []string{}
+		for _, report := range reports {
+			fullNames = append(fullNames, report.FullName)
+			Expect(report.Summary).To(ContainSubstring("scenario " + report.ScenarioName))
+			// the combinations are retested together with their scenario
+			Expect(report.ScenarioNames).To(Equal([]string{"scenario1"}))
+		}
+		Expect(fullNames).To(ConsistOf(
+			"Red Hat Konflux / scenario1[ocp=4.15] / component-sample",
+			"Red Hat Konflux / scenario1[ocp=4.16] / component-sample",
+		))
+	})
+
+	It("report full name with check prefix", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"InProgress\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:50+02:00\",\"details\":\"Test in progress\"}]"
+		hasSnapshot.Annotations[gitops.CheckPrefixAnnotation] = "konflux-prod"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasFullName("konflux-prod/Red Hat Konflux / scenario1 / component-sample")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("sanitizes check prefix",
+		func(checkPrefix, expected string) {
+			Expect(status.SanitizeCheckPrefix(checkPrefix)).To(Equal(expected))
+		},
+		Entry("empty", "", ""),
+		Entry("valid", "konflux-prod", "konflux-prod"),
+		Entry("illegal characters", "konflux prod/#1", "konflux-prod-1"),
+		Entry("too long", strings.Repeat("a", 40), strings.Repeat("a", status.MaxCheckPrefixLength)),
+	)
+
 	It("report status for TestPassed test scenario", func() {
 		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestPassed\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"failed\"}]"
 		delete(hasSnapshot.Labels, "appstudio.openshift.io/component")
@@ -432,6 +920,7 @@ var _ = Describe("Status Adapter", func() {
 			StartTime:           &ts,
 			CompletionTime:      &tc,
 			TestPipelineRunName: "test-pipelinerun",
+			ScenarioNames:       []string{"scenario1"},
 		}
 		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
 		mockReporter.EXPECT().ReportStatus(gomock.Any(), gomock.Eq(expectedTestReport)).Times(1)
@@ -441,6 +930,121 @@ var _ = Describe("Status Adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("report failed step logs for TestFail test scenario", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"failed\"}]"
+		failedTaskRun.Status.PodName = "test-taskrun-fail-pod"
+		failedTaskRun.Status.Steps = []tektonv1.StepState{
+			{
+				Name:      "run-tests",
+				Container: "step-run-tests",
+				ContainerState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+				},
+			},
+		}
+		pipelineRun.Status.ChildReferences = append(pipelineRun.Status.ChildReferences, tektonv1.ChildStatusReference{
+			Name:             failedTaskRun.Name,
+			PipelineTaskName: "pipeline1-task3",
+		})
+		podLogClient := &MockPodLogClient{logs: map[string]string{
+			"test-taskrun-fail-pod/step-run-tests": "--- FAIL: TestSomething (0.00s)\n",
+		}}
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasTextContaining(
+			"<summary>Logs of failed step <b>run-tests</b> in task <b>pipeline1-task3</b></summary>\n\n```\n--- FAIL: TestSomething (0.00s)\n```")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient, status.WithPodLogClient(podLogClient))
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("report a note instead of failed step logs when the pod is gone", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"failed\"}]"
+		failedTaskRun.Status.PodName = "test-taskrun-fail-pod"
+		failedTaskRun.Status.Steps = []tektonv1.StepState{
+			{
+				Name:      "run-tests",
+				Container: "step-run-tests",
+				ContainerState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+				},
+			},
+		}
+		pipelineRun.Status.ChildReferences = append(pipelineRun.Status.ChildReferences, tektonv1.ChildStatusReference{
+			Name:             failedTaskRun.Name,
+			PipelineTaskName: "pipeline1-task3",
+		})
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasTextContaining("(Logs are not available anymore, the pod has already been deleted.)")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient, status.WithPodLogClient(&MockPodLogClient{}))
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("report the earliest failed task for TestFail test scenario", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"failed\"}]"
+		pipelineRun.Status.ChildReferences = append(pipelineRun.Status.ChildReferences, tektonv1.ChildStatusReference{
+			Name:             failedTaskRun.Name,
+			PipelineTaskName: "pipeline1-task3",
+		})
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasFailedTaskName("pipeline1-task3")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("report the failed step log kept in the test status when the pipelineRun was pruned", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"failed\",\"failedStepLog\":\"<details>\\n<summary>Logs of failed step <b>run-tests</b> in task <b>pipeline1-task3</b></summary>\\n\\n```\\n--- FAIL: TestSomething\\n```\\n\\n</details>\"}]"
+		mockK8sClient.getErr = k8serrors.NewNotFound(schema.GroupResource{Group: "tekton.dev", Resource: "pipelineruns"}, "test-pipelinerun")
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasTextContaining(
+			"(Failed to fetch test result details.)\n<details>\n<summary>Logs of failed step <b>run-tests</b> in task <b>pipeline1-task3</b></summary>\n\n```\n--- FAIL: TestSomething\n```")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient, status.WithPodLogClient(&MockPodLogClient{}))
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports the timeout of TestFail test scenario in the summary", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T18:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T18:57:55+02:00\",\"details\":\"Integration test timed out after 2h0m0s\",\"timedOutAfter\":\"2h0m0s\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasSummary("Integration test for snapshot snapshot-sample and scenario scenario1 timed out after 2h0m0s")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports the watchdog timeout of TestFail test scenario stuck in progress in the summary", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-27T16:57:49+02:00\",\"lastUpdateTime\":\"2023-07-27T16:57:55+02:00\",\"details\":\"Integration test was in progress longer than the hard deadline of 24h0m0s without its pipeline run progressing, it was failed by the watchdog timeout\",\"watchdogTimedOutAfter\":\"24h0m0s\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasSummary("Integration test for snapshot snapshot-sample and scenario scenario1 was failed by the watchdog timeout after being in progress longer than 24h0m0s")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports the infrastructure failure of TestFail test scenario in the summary", func() {
+		hasSnapshot.Annotations["test.appstudio.openshift.io/status"] = "[{\"scenario\":\"scenario1\",\"status\":\"TestFail\",\"testPipelineRunName\":\"test-pipelinerun\",\"startTime\":\"2023-07-26T16:57:49+02:00\",\"completionTime\":\"2023-07-26T17:57:49+02:00\",\"lastUpdateTime\":\"2023-08-26T17:57:55+02:00\",\"details\":\"infrastructure failure: OOMKilled (step run-tests of task pipeline1-task3); Integration test failed\"}]"
+
+		mockReporter.EXPECT().Initialize(gomock.Any(), gomock.Any()).Times(1)
+		mockReporter.EXPECT().ReportStatus(gomock.Any(), HasSummary("Integration test for snapshot snapshot-sample and scenario scenario1 failed because of an infrastructure failure: OOMKilled (step run-tests of task pipeline1-task3)")).Times(1)
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		err := st.ReportSnapshotStatus(context.Background(), mockReporter, hasSnapshot)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	DescribeTable(
 		"report right summary per status",
 		func(expectedScenarioStatus integrationteststatus.IntegrationTestStatus, expectedTextEnding string) {
@@ -461,9 +1065,74 @@ var _ = Describe("Status Adapter", func() {
 		Entry("Provisioning error", integrationteststatus.IntegrationTestStatusEnvironmentProvisionError_Deprecated, "experienced an error when provisioning environment"),
 		Entry("Deployment error", integrationteststatus.IntegrationTestStatusDeploymentError_Deprecated, "experienced an error when deploying snapshotEnvironmentBinding"),
 		Entry("Deleted", integrationteststatus.IntegrationTestStatusDeleted, "was deleted before the pipelineRun could finish"),
+		Entry("Skipped", integrationteststatus.IntegrationTestStatusSkipped, "was skipped"),
 		Entry("Pending", integrationteststatus.IntegrationTestStatusPending, "is pending"),
 		Entry("In progress", integrationteststatus.IntegrationTestStatusInProgress, "is in progress"),
 		Entry("Invalid", integrationteststatus.IntegrationTestStatusTestInvalid, "is invalid"),
+		Entry("Configuration error", integrationteststatus.IntegrationTestStatusTestError, "couldn't run because of a configuration error"),
+	)
+
+	It("passes dry-run mode to the reporters", func() {
+		st := status.NewStatus(logr.Discard(), mockK8sClient, status.WithDryRun(true))
+		reporter := st.GetReporter(githubSnapshot)
+		Expect(reporter).To(BeAssignableToTypeOf(&status.GitHubReporter{}))
+		Expect(reporter).To(Equal(status.NewGitHubReporter(logr.Discard(), mockK8sClient, status.WithGitHubDryRun())))
+	})
+
+	It("selects a registered reporter for its git provider", func() {
+		status.RegisterReporter("fake-provider", func(logger logr.Logger, k8sClient client.Client) status.ReporterInterface {
+			return &fakeProviderReporter{name: "FakeReporter"}
+		})
+		// the registry is global, the reporter mustn't leak into the other specs
+		DeferCleanup(status.UnregisterReporter, "fake-provider")
+		fakeSnapshot := githubSnapshot.DeepCopy()
+		fakeSnapshot.Labels[gitops.PipelineAsCodeGitProviderLabel] = "fake-provider"
+
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		Expect(st.GetReporter(fakeSnapshot).GetReporterName()).To(Equal("FakeReporter"))
+		// built-in reporters are still consulted first
+		Expect(st.GetReporter(githubSnapshot).GetReporterName()).To(Equal("GithubReporter"))
+
+		// registering the same name again replaces the reporter
+		status.RegisterReporter("fake-provider", func(logger logr.Logger, k8sClient client.Client) status.ReporterInterface {
+			return &fakeProviderReporter{name: "ReplacedFakeReporter"}
+		})
+		Expect(st.GetReporter(fakeSnapshot).GetReporterName()).To(Equal("ReplacedFakeReporter"))
+
+		fakeSnapshot.Labels[gitops.PipelineAsCodeGitProviderLabel] = "unknown-provider"
+		Expect(st.GetReporter(fakeSnapshot)).To(BeNil())
+	})
+
+	It("doesn't select an unregistered reporter", func() {
+		status.RegisterReporter("fake-provider", func(logger logr.Logger, k8sClient client.Client) status.ReporterInterface {
+			return &fakeProviderReporter{name: "FakeReporter"}
+		})
+		fakeSnapshot := githubSnapshot.DeepCopy()
+		fakeSnapshot.Labels[gitops.PipelineAsCodeGitProviderLabel] = "fake-provider"
+
+		status.UnregisterReporter("fake-provider")
+		st := status.NewStatus(logr.Discard(), mockK8sClient)
+		Expect(st.GetReporter(fakeSnapshot)).To(BeNil())
+		// the built-in reporters are kept
+		Expect(st.GetReporter(githubSnapshot).GetReporterName()).To(Equal("GithubReporter"))
+		// unknown names are ignored
+		status.UnregisterReporter("unknown-provider")
+	})
+
+	DescribeTable("resolves dry-run mode from the environment and the application annotation",
+		func(envValue string, annotations map[string]string, expected bool) {
+			os.Setenv(status.DryRunEnvVar, envValue)
+			defer os.Unsetenv(status.DryRunEnvVar)
+
+			application := &applicationapiv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			}
+			Expect(status.IsDryRunEnabled(application)).To(Equal(expected))
+		},
+		Entry("Disabled by default", "", nil, false),
+		Entry("Enabled cluster-wide", "true", nil, true),
+		Entry("Enabled for the application", "", map[string]string{status.DryRunAnnotation: "true"}, true),
+		Entry("Disabled for the application", "true", map[string]string{status.DryRunAnnotation: "false"}, false),
 	)
 
 	It("check if GenerateSummary supports all integration test statuses", func() {
@@ -582,3 +1251,46 @@ var _ = Describe("Status Adapter", func() {
 	})
 
 })
+
+// fakeProviderReporter is a reporter detecting snapshots of the fake-provider git provider
+type fakeProviderReporter struct {
+	name string
+}
+
+func (r *fakeProviderReporter) Detect(snapshot *applicationapiv1alpha1.Snapshot) bool {
+	return snapshot.GetLabels()[gitops.PipelineAsCodeGitProviderLabel] == "fake-provider"
+}
+
+func (r *fakeProviderReporter) Initialize(context.Context, *applicationapiv1alpha1.Snapshot) error {
+	return nil
+}
+
+func (r *fakeProviderReporter) GetReporterName() string {
+	return r.name
+}
+
+func (r *fakeProviderReporter) ReportStatus(context.Context, status.TestReport) error {
+	return nil
+}
+
+// backdateReportAttempts moves the report attempts recorded in the snapshot back by the given duration,
+// as if the time passed since them
+func backdateReportAttempts(snapshot *applicationapiv1alpha1.Snapshot, d time.Duration) {
+	srs, err := status.NewSnapshotReportStatusFromSnapshot(snapshot)
+	Expect(err).NotTo(HaveOccurred())
+	scenarios := []map[string]*status.ScenarioReportStatus{srs.Scenarios}
+	for _, reporter := range srs.Reporters {
+		scenarios = append(scenarios, reporter.Scenarios)
+	}
+	for _, reporterScenarios := range scenarios {
+		for _, scenario := range reporterScenarios {
+			if scenario.LastAttemptTime != nil {
+				backdated := scenario.LastAttemptTime.Add(-d)
+				scenario.LastAttemptTime = &backdated
+			}
+		}
+	}
+	value, err := srs.ToAnnotationString()
+	Expect(err).NotTo(HaveOccurred())
+	snapshot.Annotations[gitops.SnapshotStatusReportAnnotation] = value
+}
@@ -20,18 +20,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/helpers"
 	"github.com/konflux-ci/operator-toolkit/metadata"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -47,8 +52,61 @@ const (
 
 	// PipelineTypeTest is the type for PipelineRuns created to run an integration Pipeline
 	PipelineTypeTest = "test"
+
+	// SnapshotParamName is the name of the param containing the Snapshot which is injected into the Integration PipelineRun
+	SnapshotParamName = "SNAPSHOT"
+
+	// ComponentsParamName is the name of the param containing the compact JSON list of the Snapshot components
+	// which is injected into the Integration PipelineRun
+	ComponentsParamName = "COMPONENTS"
+
+	// MaxComponentsParamSize is the size in bytes of the COMPONENTS param above which
+	// the git metadata of the components is omitted from it
+	MaxComponentsParamSize = 8192
+
+	// NamespaceParamName is the name of the param containing the default namespace of the DeploymentTarget
+	// which is injected into the Integration PipelineRun
+	NamespaceParamName = "NAMESPACE"
+
+	// GitSourceBranchParamName is the name of the param containing the source branch of the git event
+	// which triggered the Snapshot, it's injected into the Integration PipelineRun
+	GitSourceBranchParamName = "GIT_SOURCE_BRANCH"
+
+	// GitTargetBranchParamName is the name of the param containing the target branch of the pull request,
+	// or the pushed branch, of the git event which triggered the Snapshot
+	GitTargetBranchParamName = "GIT_TARGET_BRANCH"
+
+	// EventTypeParamName is the name of the param containing the type of the git event which triggered the Snapshot,
+	// e.g. pull_request or push
+	EventTypeParamName = "EVENT_TYPE"
+
+	// PullRequestNumberParamName is the name of the param containing the number of the pull request
+	// which triggered the Snapshot, it's empty for the other events
+	PullRequestNumberParamName = "PULL_REQUEST_NUMBER"
+
+	// PropagatedLabelAnnotationPrefix is the prefix of the IntegrationTestScenario annotations which are added
+	// as labels to the Integration PipelineRuns, and thus to their pods, e.g. the annotation
+	// propagate.test.appstudio.openshift.io/cost-center: "1234" adds the label cost-center: "1234"
+	PropagatedLabelAnnotationPrefix = "propagate.test.appstudio.openshift.io/"
+)
+
+// The Pipelines as Code metadata of the Snapshots the git event params are populated from. The keys mirror
+// the gitops constants, which can't be imported here since the gitops package depends on this one.
+const (
+	pacEventTypeLabel         = "pac.test.appstudio.openshift.io/event-type"
+	pacSourceBranchAnnotation = "pac.test.appstudio.openshift.io/source-branch"
+	pacBranchAnnotation       = "pac.test.appstudio.openshift.io/branch"
+	pacPullRequestAnnotation  = "pac.test.appstudio.openshift.io/pull-request"
+	buildTargetBranchLabel    = "build.appstudio.redhat.com/target_branch"
 )
 
+// ReservedLabelDomains are the domains of the labels owned by the integration service and the build and
+// Tekton services, labels propagated from the IntegrationTestScenario annotations can't use them
+var ReservedLabelDomains = []string{ResourceLabelSuffix, "appstudio.redhat.com", "tekton.dev"}
+
+// labelIllegalCharsRegex matches the characters which are not allowed in label names and values
+var labelIllegalCharsRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
 var (
 	// PipelinesTypeLabel is the label used to describe the type of pipeline
 	PipelinesTypeLabel = fmt.Sprintf("%s/%s", PipelinesLabelPrefix, "type")
@@ -73,6 +131,14 @@ var (
 
 	// OptionalLabel is the label used to specify if an IntegrationTestScenario is allowed to fail
 	OptionalLabel = fmt.Sprintf("%s/%s", TestLabelPrefix, "optional")
+
+	// ScheduledRunLabel is the label marking the PipelineRuns triggered by the schedule of the IntegrationTestScenario,
+	// their results aren't reported to the git provider
+	ScheduledRunLabel = fmt.Sprintf("%s/%s", TestLabelPrefix, "scheduled-run")
+
+	// TestStatusNameAnnotation is the annotation containing the name of the Snapshot test status the PipelineRun
+	// reports to, it's set on the PipelineRuns of the matrix combinations of the IntegrationTestScenario
+	TestStatusNameAnnotation = fmt.Sprintf("%s/%s", TestLabelPrefix, "test-status-name")
 )
 
 // IntegrationPipelineRun is a PipelineRun alias, so we can add new methods to it in this file.
@@ -86,7 +152,8 @@ func (r *IntegrationPipelineRun) AsPipelineRun() *tektonv1.PipelineRun {
 }
 
 // NewIntegrationPipelineRun creates an empty PipelineRun in the given namespace. The name will be autogenerated,
-// using the prefix passed as an argument to the function.
+// using the prefix passed as an argument to the function. The PipelineRun holds the integration PipelineRun finalizer
+// which is only removed once its outcome is recorded in the Snapshot, so it can't be deleted before that.
 func NewIntegrationPipelineRun(prefix, namespace string, integrationTestScenario v1beta2.IntegrationTestScenario) *IntegrationPipelineRun {
 	resolverParams := []tektonv1.Param{}
 
@@ -105,6 +172,7 @@ func NewIntegrationPipelineRun(prefix, namespace string, integrationTestScenario
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: prefix + "-",
 			Namespace:    namespace,
+			Finalizers:   []string{helpers.IntegrationPipelineRunFinalizer},
 		},
 		Spec: tektonv1.PipelineRunSpec{
 			PipelineRef: &tektonv1.PipelineRef{
@@ -136,9 +204,15 @@ func (r *IntegrationPipelineRun) WithExtraParam(name string, value tektonv1.Para
 	return r
 }
 
-// WithExtraParams adds all provided parameters to the Integration PipelineRun.
-func (r *IntegrationPipelineRun) WithExtraParams(params []v1beta2.PipelineParameter) *IntegrationPipelineRun {
+// WithExtraParams adds all provided parameters to the Integration PipelineRun. The parameters colliding with
+// the reserved params injected by the integration service are dropped with a warning.
+func (r *IntegrationPipelineRun) WithExtraParams(params []v1beta2.PipelineParameter, logger logr.Logger) *IntegrationPipelineRun {
 	for _, param := range params {
+		if slices.Contains(v1beta2.ReservedParams, param.Name) {
+			logger.Info("Dropping the IntegrationTestScenario param colliding with a reserved param of the integration PipelineRun",
+				"param.Name", param.Name)
+			continue
+		}
 		var value tektonv1.ParamValue
 		switch {
 		case param.Value != "":
@@ -156,18 +230,22 @@ func (r *IntegrationPipelineRun) WithExtraParams(params []v1beta2.PipelineParame
 	return r
 }
 
-// WithSnapshot adds a param containing the Snapshot as a json string
-// to the integration PipelineRun.
+// WithSnapshot adds a param containing the Snapshot as a json string and a param containing
+// the compact list of its components to the integration PipelineRun.
 func (r *IntegrationPipelineRun) WithSnapshot(snapshot *applicationapiv1alpha1.Snapshot) *IntegrationPipelineRun {
 	// We ignore the error here because none should be raised when marshalling the spec of a CRD.
 	// If we end up deciding it is useful, we will need to pass the errors through the chain and
 	// add something like a `Complete` function that returns the final object and error.
 	snapshotString, _ := json.Marshal(snapshot.Spec)
 
-	r.WithExtraParam("SNAPSHOT", tektonv1.ParamValue{
+	r.WithExtraParam(SnapshotParamName, tektonv1.ParamValue{
 		Type:      tektonv1.ParamTypeString,
 		StringVal: string(snapshotString),
 	})
+	r.WithExtraParam(ComponentsParamName, tektonv1.ParamValue{
+		Type:      tektonv1.ParamTypeString,
+		StringVal: getComponentsParamValue(snapshot),
+	})
 
 	if r.ObjectMeta.Labels == nil {
 		r.ObjectMeta.Labels = map[string]string{}
@@ -177,6 +255,80 @@ func (r *IntegrationPipelineRun) WithSnapshot(snapshot *applicationapiv1alpha1.S
 	return r
 }
 
+// WithGitEventParams adds the params describing the git event which triggered the Snapshot to the integration
+// PipelineRun: its source and target branches, its type and the number of its pull request. The values are taken
+// from the Pipelines as Code labels and annotations of the Snapshot, the params are empty when the Snapshot
+// doesn't carry them, e.g. for the Snapshots created manually.
+func (r *IntegrationPipelineRun) WithGitEventParams(snapshot *applicationapiv1alpha1.Snapshot) *IntegrationPipelineRun {
+	targetBranch := getSnapshotMetadata(snapshot, pacBranchAnnotation)
+	if targetBranch == "" {
+		targetBranch = getSnapshotMetadata(snapshot, buildTargetBranchLabel)
+	}
+
+	for _, param := range []struct{ name, value string }{
+		{GitSourceBranchParamName, getSnapshotMetadata(snapshot, pacSourceBranchAnnotation)},
+		{GitTargetBranchParamName, targetBranch},
+		{EventTypeParamName, getSnapshotMetadata(snapshot, pacEventTypeLabel)},
+		{PullRequestNumberParamName, getSnapshotMetadata(snapshot, pacPullRequestAnnotation)},
+	} {
+		r.WithExtraParam(param.name, tektonv1.ParamValue{
+			Type:      tektonv1.ParamTypeString,
+			StringVal: param.value,
+		})
+	}
+
+	return r
+}
+
+// getSnapshotMetadata returns the value of the given label of the Snapshot, falling back to the annotation
+// with the same key, since Pipelines as Code metadata is propagated to the Snapshots as either of them
+func getSnapshotMetadata(snapshot *applicationapiv1alpha1.Snapshot, key string) string {
+	if value, ok := snapshot.GetLabels()[key]; ok {
+		return value
+	}
+	return snapshot.GetAnnotations()[key]
+}
+
+// SnapshotComponentParam is the representation of a Snapshot component within the COMPONENTS param
+type SnapshotComponentParam struct {
+	Name           string `json:"name"`
+	ContainerImage string `json:"containerImage"`
+	GitURL         string `json:"gitURL,omitempty"`
+	Revision       string `json:"revision,omitempty"`
+	// Truncated is set when the git metadata of the component was omitted to keep the param under MaxComponentsParamSize
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// getComponentsParamValue returns the compact JSON list of the Snapshot components, the git metadata
+// of the components is omitted when the list would exceed MaxComponentsParamSize
+func getComponentsParamValue(snapshot *applicationapiv1alpha1.Snapshot) string {
+	components := make([]SnapshotComponentParam, 0, len(snapshot.Spec.Components))
+	for _, snapshotComponent := range snapshot.Spec.Components {
+		component := SnapshotComponentParam{
+			Name:           snapshotComponent.Name,
+			ContainerImage: snapshotComponent.ContainerImage,
+		}
+		if snapshotComponent.Source.GitSource != nil {
+			component.GitURL = snapshotComponent.Source.GitSource.URL
+			component.Revision = snapshotComponent.Source.GitSource.Revision
+		}
+		components = append(components, component)
+	}
+
+	// Marshalling a list of plain structs can't fail
+	componentsString, _ := json.Marshal(components)
+	if len(componentsString) > MaxComponentsParamSize {
+		for i := range components {
+			components[i].GitURL = ""
+			components[i].Revision = ""
+			components[i].Truncated = true
+		}
+		componentsString, _ = json.Marshal(components)
+	}
+
+	return string(componentsString)
+}
+
 // WithIntegrationLabels adds the type, optional flag and IntegrationTestScenario name as labels to the Integration PipelineRun.
 func (r *IntegrationPipelineRun) WithIntegrationLabels(integrationTestScenario *v1beta2.IntegrationTestScenario) *IntegrationPipelineRun {
 	if r.ObjectMeta.Labels == nil {
@@ -207,6 +359,62 @@ func (r *IntegrationPipelineRun) WithIntegrationAnnotations(its *v1beta2.Integra
 	return r
 }
 
+// WithPropagatedLabels adds the values of the IntegrationTestScenario annotations prefixed with
+// PropagatedLabelAnnotationPrefix as labels to the Integration PipelineRun. The label names and values are
+// sanitized to the label syntax, the labels colliding with the labels of the integration service are dropped with a warning.
+func (r *IntegrationPipelineRun) WithPropagatedLabels(integrationTestScenario *v1beta2.IntegrationTestScenario, logger logr.Logger) *IntegrationPipelineRun {
+	for annotation, value := range integrationTestScenario.GetAnnotations() {
+		key, found := strings.CutPrefix(annotation, PropagatedLabelAnnotationPrefix)
+		if !found {
+			continue
+		}
+		key, value = sanitizeLabelKey(key), sanitizeLabelValue(value)
+		if errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...); len(errs) > 0 {
+			logger.Info("Dropping the IntegrationTestScenario annotation which can't be propagated as a label of the integration PipelineRun",
+				"annotation", annotation, "errors", errs)
+			continue
+		}
+		if isReservedLabel(key) || metadata.HasLabel(r, key) {
+			logger.Info("Dropping the IntegrationTestScenario annotation colliding with a label of the integration PipelineRun",
+				"annotation", annotation, "label", key)
+			continue
+		}
+		if r.ObjectMeta.Labels == nil {
+			r.ObjectMeta.Labels = map[string]string{}
+		}
+		r.ObjectMeta.Labels[key] = value
+	}
+
+	return r
+}
+
+// WithScenarioTest adds the matrix params of the IntegrationTestScenario test to the Integration PipelineRun
+// and annotates it with the name of the test status of the matrix combination.
+func (r *IntegrationPipelineRun) WithScenarioTest(test helpers.ScenarioTest) *IntegrationPipelineRun {
+	if len(test.MatrixParams) == 0 {
+		return r
+	}
+	for _, param := range test.MatrixParams {
+		r.WithExtraParam(param.Name, tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: param.Value})
+	}
+	if err := metadata.SetAnnotation(r, TestStatusNameAnnotation, test.Name); err != nil {
+		// this will only happen if we pass IntegrationPipelineRun as nil
+		panic(err)
+	}
+
+	return r
+}
+
+// WithScheduledRunLabel marks the Integration PipelineRun as triggered by the schedule of its IntegrationTestScenario.
+func (r *IntegrationPipelineRun) WithScheduledRunLabel() *IntegrationPipelineRun {
+	if r.ObjectMeta.Labels == nil {
+		r.ObjectMeta.Labels = map[string]string{}
+	}
+	r.ObjectMeta.Labels[ScheduledRunLabel] = "true"
+
+	return r
+}
+
 // WithApplicationAndComponent adds the name of both application and component as lables to the Integration PipelineRun.
 func (r *IntegrationPipelineRun) WithApplicationAndComponent(application *applicationapiv1alpha1.Application, component *applicationapiv1alpha1.Component) *IntegrationPipelineRun {
 	if r.ObjectMeta.Labels == nil {
@@ -225,7 +433,7 @@ func (r *IntegrationPipelineRun) WithApplicationAndComponent(application *applic
 func (r *IntegrationPipelineRun) WithEnvironmentAndDeploymentTarget(dt *applicationapiv1alpha1.DeploymentTarget, environmentName string) *IntegrationPipelineRun {
 	if !reflect.ValueOf(dt.Spec.KubernetesClusterCredentials).IsZero() {
 		// Add the NAMESPACE parameter to the pipeline
-		r.WithExtraParam("NAMESPACE", tektonv1.ParamValue{
+		r.WithExtraParam(NamespaceParamName, tektonv1.ParamValue{
 			Type:      tektonv1.ParamTypeString,
 			StringVal: dt.Spec.KubernetesClusterCredentials.DefaultNamespace,
 		})
@@ -251,6 +459,68 @@ func (r *IntegrationPipelineRun) WithEnvironmentAndDeploymentTarget(dt *applicat
 	return r
 }
 
+// WithWorkspaces adds the workspaces defined in the given IntegrationTestScenario to the integration PipelineRun.
+func (r *IntegrationPipelineRun) WithWorkspaces(integrationTestScenario *v1beta2.IntegrationTestScenario) *IntegrationPipelineRun {
+	for _, workspace := range integrationTestScenario.Spec.Workspaces {
+		binding := tektonv1.WorkspaceBinding{
+			Name:                workspace.Name,
+			VolumeClaimTemplate: workspace.VolumeClaimTemplate.DeepCopy(),
+			Secret:              workspace.Secret.DeepCopy(),
+			ConfigMap:           workspace.ConfigMap.DeepCopy(),
+		}
+		if workspace.EmptyDir {
+			binding.EmptyDir = &corev1.EmptyDirVolumeSource{}
+		}
+		r.Spec.Workspaces = append(r.Spec.Workspaces, binding)
+	}
+
+	return r
+}
+
+// WithPodTemplate sets the pod template defined in the given IntegrationTestScenario on the TaskRuns of
+// the integration PipelineRun. The PipelineRun is left untouched when the scenario has no pod template.
+func (r *IntegrationPipelineRun) WithPodTemplate(integrationTestScenario *v1beta2.IntegrationTestScenario) *IntegrationPipelineRun {
+	if integrationTestScenario.Spec.PodTemplate == nil {
+		return r
+	}
+
+	podTemplate := integrationTestScenario.Spec.PodTemplate.DeepCopy()
+	template := &pod.Template{
+		NodeSelector: podTemplate.NodeSelector,
+		Tolerations:  podTemplate.Tolerations,
+	}
+	if securityContext := podTemplate.SecurityContext; securityContext != nil {
+		template.SecurityContext = &corev1.PodSecurityContext{
+			RunAsNonRoot: securityContext.RunAsNonRoot,
+			RunAsUser:    securityContext.RunAsUser,
+			RunAsGroup:   securityContext.RunAsGroup,
+			FSGroup:      securityContext.FSGroup,
+		}
+	}
+	r.Spec.TaskRunTemplate.PodTemplate = template
+
+	return r
+}
+
+// WithTaskRunSpecs adds the taskRunSpecs defined in the given IntegrationTestScenario to the integration PipelineRun.
+func (r *IntegrationPipelineRun) WithTaskRunSpecs(integrationTestScenario *v1beta2.IntegrationTestScenario) *IntegrationPipelineRun {
+	for _, taskRunSpec := range integrationTestScenario.Spec.TaskRunSpecs {
+		pipelineTaskRunSpec := tektonv1.PipelineTaskRunSpec{
+			PipelineTaskName: taskRunSpec.PipelineTaskName,
+			ComputeResources: taskRunSpec.ComputeResources.DeepCopy(),
+		}
+		for _, stepSpec := range taskRunSpec.StepSpecs {
+			pipelineTaskRunSpec.StepSpecs = append(pipelineTaskRunSpec.StepSpecs, tektonv1.TaskRunStepSpec{
+				Name:             stepSpec.Name,
+				ComputeResources: *stepSpec.ComputeResources.DeepCopy(),
+			})
+		}
+		r.Spec.TaskRunSpecs = append(r.Spec.TaskRunSpecs, pipelineTaskRunSpec)
+	}
+
+	return r
+}
+
 // WithDefaultIntegrationTimeouts fetches the default Integration timeouts from the environment variables and adds them
 // to the integration PipelineRun.
 func (r *IntegrationPipelineRun) WithDefaultIntegrationTimeouts(logger logr.Logger) *IntegrationPipelineRun {
@@ -285,3 +555,109 @@ func (r *IntegrationPipelineRun) WithDefaultIntegrationTimeouts(logger logr.Logg
 
 	return r
 }
+
+// WithIntegrationTimeouts adds the timeouts defined in the given IntegrationTestScenario to the integration PipelineRun,
+// overriding the default timeouts. Tekton requires the tasks and finally timeouts to fit into the pipeline timeout,
+// so the timeouts which aren't defined in the IntegrationTestScenario are derived from the defined ones:
+// the default tasks and finally timeouts are dropped when the pipeline timeout is defined, one of them is then
+// derived from the pipeline timeout and the other one, and the default pipeline timeout is extended when the
+// defined tasks and finally timeouts don't fit into it.
+func (r *IntegrationPipelineRun) WithIntegrationTimeouts(integrationTestScenario *v1beta2.IntegrationTestScenario, logger logr.Logger) *IntegrationPipelineRun {
+	timeouts := integrationTestScenario.Spec.Timeouts
+	if timeouts == nil {
+		return r
+	}
+	pipelineTimeout := parseScenarioTimeout(integrationTestScenario, "pipeline", timeouts.Pipeline, logger)
+	taskTimeout := parseScenarioTimeout(integrationTestScenario, "tasks", timeouts.Tasks, logger)
+	finallyTimeout := parseScenarioTimeout(integrationTestScenario, "finally", timeouts.Finally, logger)
+	if pipelineTimeout == nil && taskTimeout == nil && finallyTimeout == nil {
+		return r
+	}
+	if r.Spec.Timeouts == nil {
+		r.Spec.Timeouts = &tektonv1.TimeoutFields{}
+	}
+
+	if pipelineTimeout != nil {
+		r.Spec.Timeouts.Pipeline = pipelineTimeout
+		r.Spec.Timeouts.Tasks = taskTimeout
+		r.Spec.Timeouts.Finally = finallyTimeout
+		// a zero pipeline timeout means no timeout, the tasks and finally timeouts aren't bounded then
+		if pipelineTimeout.Duration > 0 {
+			if taskTimeout != nil && finallyTimeout == nil {
+				r.Spec.Timeouts.Finally = &metav1.Duration{Duration: max(pipelineTimeout.Duration-taskTimeout.Duration, 0)}
+			} else if finallyTimeout != nil && taskTimeout == nil {
+				r.Spec.Timeouts.Tasks = &metav1.Duration{Duration: max(pipelineTimeout.Duration-finallyTimeout.Duration, 0)}
+			}
+		}
+		return r
+	}
+
+	if taskTimeout != nil {
+		r.Spec.Timeouts.Tasks = taskTimeout
+	}
+	if finallyTimeout != nil {
+		r.Spec.Timeouts.Finally = finallyTimeout
+	}
+	requiredDuration := time.Duration(0)
+	for _, timeout := range []*metav1.Duration{r.Spec.Timeouts.Tasks, r.Spec.Timeouts.Finally} {
+		if timeout != nil {
+			requiredDuration += timeout.Duration
+		}
+	}
+	if defaultTimeout := r.Spec.Timeouts.Pipeline; defaultTimeout != nil && defaultTimeout.Duration > 0 && defaultTimeout.Duration < requiredDuration {
+		logger.Info("extending the default pipeline timeout to fit the tasks and finally timeouts of the IntegrationTestScenario",
+			"integrationTestScenario.Name", integrationTestScenario.Name, "pipelineTimeout", requiredDuration.String())
+		r.Spec.Timeouts.Pipeline = &metav1.Duration{Duration: requiredDuration}
+	}
+
+	return r
+}
+
+// parseScenarioTimeout returns the given timeout of the IntegrationTestScenario, nil is returned when the timeout
+// isn't defined or can't be parsed
+func parseScenarioTimeout(integrationTestScenario *v1beta2.IntegrationTestScenario, name, value string, logger logr.Logger) *metav1.Duration {
+	if value == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to parse the %s timeout of the IntegrationTestScenario", name),
+			"integrationTestScenario.Name", integrationTestScenario.Name)
+		return nil
+	}
+	return &metav1.Duration{Duration: timeout}
+}
+
+// sanitizeLabelKey replaces the characters which are not allowed in the name part of the label key and caps
+// its length, the prefix part of the key is only lowercased
+func sanitizeLabelKey(key string) string {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		return sanitizeLabelValue(key)
+	}
+	return strings.ToLower(prefix) + "/" + sanitizeLabelValue(name)
+}
+
+// sanitizeLabelValue replaces the characters which are not allowed in label values and caps
+// the length of the value to validation.LabelValueMaxLength
+func sanitizeLabelValue(value string) string {
+	value = labelIllegalCharsRegex.ReplaceAllString(value, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(value, "-_.")
+}
+
+// isReservedLabel checks if the prefix of the label key belongs to any of the ReservedLabelDomains
+func isReservedLabel(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	for _, domain := range ReservedLabelDomains {
+		if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,18 +18,24 @@ package tekton_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
 	"github.com/konflux-ci/integration-service/api/v1beta2"
 	"github.com/konflux-ci/integration-service/helpers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/tonglil/buflogr"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/konflux-ci/integration-service/gitops"
 	tekton "github.com/konflux-ci/integration-service/tekton"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -302,7 +308,7 @@ var _ = Describe("Integration pipeline", func() {
 			WithIntegrationLabels(enterpriseContractTestScenario).
 			WithIntegrationAnnotations(enterpriseContractTestScenario).
 			WithSnapshot(hasSnapshot).
-			WithExtraParams(enterpriseContractTestScenario.Spec.Params).
+			WithExtraParams(enterpriseContractTestScenario.Spec.Params, logr.Discard()).
 			WithApplicationAndComponent(hasApp, hasComp)
 		Expect(k8sClient.Create(ctx, enterpriseContractPipelineRun.AsPipelineRun())).Should(Succeed())
 
@@ -374,12 +380,220 @@ var _ = Describe("Integration pipeline", func() {
 			Expect(buf.String()).Should(ContainSubstring(expectedLogEntryPrefix + " FINALLY_TIMEOUT"))
 		})
 
+		It("can bind an emptyDir workspace of the IntegrationTestScenario to the IntegrationPipelineRun", func() {
+			integrationTestScenarioGit.Spec.Workspaces = []v1beta2.TestWorkspace{
+				{Name: "cache", EmptyDir: true},
+			}
+			newIntegrationPipelineRun.WithWorkspaces(integrationTestScenarioGit)
+
+			Expect(newIntegrationPipelineRun.Spec.Workspaces).To(HaveLen(1))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].Name).To(Equal("cache"))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].EmptyDir).NotTo(BeNil())
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].VolumeClaimTemplate).To(BeNil())
+		})
+
+		It("can bind a volumeClaimTemplate workspace of the IntegrationTestScenario to the IntegrationPipelineRun", func() {
+			integrationTestScenarioGit.Spec.Workspaces = []v1beta2.TestWorkspace{
+				{
+					Name: "artifacts",
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+							},
+						},
+					},
+				},
+			}
+			newIntegrationPipelineRun.WithWorkspaces(integrationTestScenarioGit)
+
+			Expect(newIntegrationPipelineRun.Spec.Workspaces).To(HaveLen(1))
+			workspace := newIntegrationPipelineRun.Spec.Workspaces[0]
+			Expect(workspace.Name).To(Equal("artifacts"))
+			Expect(workspace.EmptyDir).To(BeNil())
+			Expect(workspace.VolumeClaimTemplate).NotTo(BeNil())
+			Expect(workspace.VolumeClaimTemplate.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+			Expect(workspace.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		})
+
+		It("can bind secret and configMap workspaces of the IntegrationTestScenario to the IntegrationPipelineRun", func() {
+			integrationTestScenarioGit.Spec.Workspaces = []v1beta2.TestWorkspace{
+				{Name: "credentials", Secret: &corev1.SecretVolumeSource{SecretName: "test-credentials"}},
+				{
+					Name: "config",
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "test-config"},
+					},
+				},
+			}
+			newIntegrationPipelineRun.WithWorkspaces(integrationTestScenarioGit)
+
+			Expect(newIntegrationPipelineRun.Spec.Workspaces).To(HaveLen(2))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].Name).To(Equal("credentials"))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].Secret.SecretName).To(Equal("test-credentials"))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[0].ConfigMap).To(BeNil())
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[1].Name).To(Equal("config"))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[1].ConfigMap.Name).To(Equal("test-config"))
+			Expect(newIntegrationPipelineRun.Spec.Workspaces[1].Secret).To(BeNil())
+		})
+
+		It("can add the taskRunSpecs of the IntegrationTestScenario to the IntegrationPipelineRun", func() {
+			integrationTestScenarioGit.Spec.TaskRunSpecs = []v1beta2.TaskRunSpec{
+				{
+					PipelineTaskName: "build",
+					ComputeResources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				},
+				{
+					PipelineTaskName: "test",
+					StepSpecs: []v1beta2.TaskRunStepSpec{
+						{
+							Name: "run-tests",
+							ComputeResources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+							},
+						},
+					},
+				},
+			}
+			newIntegrationPipelineRun.WithTaskRunSpecs(integrationTestScenarioGit)
+
+			Expect(newIntegrationPipelineRun.Spec.TaskRunSpecs).To(HaveLen(2))
+			buildSpec := newIntegrationPipelineRun.Spec.TaskRunSpecs[0]
+			Expect(buildSpec.PipelineTaskName).To(Equal("build"))
+			Expect(buildSpec.ComputeResources.Requests.Memory().String()).To(Equal("2Gi"))
+			Expect(buildSpec.ComputeResources.Limits.Memory().String()).To(Equal("4Gi"))
+			Expect(buildSpec.StepSpecs).To(BeEmpty())
+			testSpec := newIntegrationPipelineRun.Spec.TaskRunSpecs[1]
+			Expect(testSpec.PipelineTaskName).To(Equal("test"))
+			Expect(testSpec.ComputeResources).To(BeNil())
+			Expect(testSpec.StepSpecs).To(HaveLen(1))
+			Expect(testSpec.StepSpecs[0].Name).To(Equal("run-tests"))
+			Expect(testSpec.StepSpecs[0].ComputeResources.Requests.Cpu().String()).To(Equal("500m"))
+
+			// the PipelineRun doesn't share the resources of the IntegrationTestScenario
+			integrationTestScenarioGit.Spec.TaskRunSpecs[0].ComputeResources.Requests[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			Expect(buildSpec.ComputeResources.Requests.Memory().String()).To(Equal("2Gi"))
+		})
+
+		It("can add the pod template of the IntegrationTestScenario to the IntegrationPipelineRun", func() {
+			runAsNonRoot := true
+			runAsUser := int64(1001)
+			integrationTestScenarioGit.Spec.PodTemplate = &v1beta2.PodTemplate{
+				NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"},
+				Tolerations: []corev1.Toleration{
+					{
+						Key:      "nvidia.com/gpu",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				},
+				SecurityContext: &v1beta2.PodSecurityContext{
+					RunAsNonRoot: &runAsNonRoot,
+					RunAsUser:    &runAsUser,
+				},
+			}
+			newIntegrationPipelineRun.WithPodTemplate(integrationTestScenarioGit)
+
+			podTemplate := newIntegrationPipelineRun.Spec.TaskRunTemplate.PodTemplate
+			Expect(podTemplate).NotTo(BeNil())
+			Expect(podTemplate.NodeSelector).To(Equal(map[string]string{"nvidia.com/gpu.present": "true"}))
+			Expect(podTemplate.Tolerations).To(Equal(integrationTestScenarioGit.Spec.PodTemplate.Tolerations))
+			Expect(podTemplate.SecurityContext).NotTo(BeNil())
+			Expect(*podTemplate.SecurityContext.RunAsNonRoot).To(BeTrue())
+			Expect(*podTemplate.SecurityContext.RunAsUser).To(Equal(int64(1001)))
+			Expect(podTemplate.SecurityContext.RunAsGroup).To(BeNil())
+
+			// the PipelineRun doesn't share the pod template of the IntegrationTestScenario
+			integrationTestScenarioGit.Spec.PodTemplate.NodeSelector["nvidia.com/gpu.present"] = "false"
+			*integrationTestScenarioGit.Spec.PodTemplate.SecurityContext.RunAsUser = 0
+			Expect(podTemplate.NodeSelector["nvidia.com/gpu.present"]).To(Equal("true"))
+			Expect(*podTemplate.SecurityContext.RunAsUser).To(Equal(int64(1001)))
+		})
+
+		It("doesn't set a pod template on the IntegrationPipelineRun when the IntegrationTestScenario has none", func() {
+			integrationTestScenarioGit.Spec.PodTemplate = nil
+			newIntegrationPipelineRun.WithPodTemplate(integrationTestScenarioGit)
+
+			Expect(newIntegrationPipelineRun.Spec.TaskRunTemplate.PodTemplate).To(BeNil())
+		})
+
+		It("can override the default timeouts of the IntegrationPipelineRun with the IntegrationTestScenario timeouts", func() {
+			var buf bytes.Buffer
+			defaultDuration, _ := time.ParseDuration("2h")
+			integrationTestScenarioGit.Spec.Timeouts = &v1beta2.TestTimeouts{
+				Pipeline: "4h",
+				Tasks:    "3h30m",
+			}
+			newIntegrationPipelineRun.WithDefaultIntegrationTimeouts(buflogr.NewWithBuffer(&buf)).
+				WithIntegrationTimeouts(integrationTestScenarioGit, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(4 * time.Hour))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Tasks.Duration).To(Equal(3*time.Hour + 30*time.Minute))
+			// the finally timeout is derived from the pipeline and tasks timeouts
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Finally.Duration).To(Equal(30 * time.Minute))
+
+			// The pipelineRun timeouts shouldn't be changed if the IntegrationTestScenario doesn't define them
+			integrationTestScenarioGit.Spec.Timeouts = nil
+			newIntegrationPipelineRun.WithDefaultIntegrationTimeouts(buflogr.NewWithBuffer(&buf)).
+				WithIntegrationTimeouts(integrationTestScenarioGit, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(defaultDuration))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Tasks.Duration).To(Equal(defaultDuration))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Finally.Duration).To(Equal(defaultDuration))
+		})
+
+		It("drops the default tasks and finally timeouts when the IntegrationTestScenario only defines the pipeline timeout", func() {
+			var buf bytes.Buffer
+			integrationTestScenarioGit.Spec.Timeouts = &v1beta2.TestTimeouts{
+				Pipeline: "1h",
+			}
+			newIntegrationPipelineRun.WithDefaultIntegrationTimeouts(buflogr.NewWithBuffer(&buf)).
+				WithIntegrationTimeouts(integrationTestScenarioGit, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(time.Hour))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Tasks).To(BeNil())
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Finally).To(BeNil())
+
+			integrationTestScenarioGit.Spec.Timeouts = &v1beta2.TestTimeouts{
+				Pipeline: "1h",
+				Finally:  "15m",
+			}
+			newIntegrationPipelineRun.WithDefaultIntegrationTimeouts(buflogr.NewWithBuffer(&buf)).
+				WithIntegrationTimeouts(integrationTestScenarioGit, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(time.Hour))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Tasks.Duration).To(Equal(45 * time.Minute))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Finally.Duration).To(Equal(15 * time.Minute))
+		})
+
+		It("extends the default pipeline timeout to fit the tasks timeout of the IntegrationTestScenario", func() {
+			var buf bytes.Buffer
+			integrationTestScenarioGit.Spec.Timeouts = &v1beta2.TestTimeouts{
+				Tasks: "3h",
+			}
+			newIntegrationPipelineRun.WithDefaultIntegrationTimeouts(buflogr.NewWithBuffer(&buf)).
+				WithIntegrationTimeouts(integrationTestScenarioGit, buflogr.NewWithBuffer(&buf))
+
+			// the default finally timeout is 2h
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(5 * time.Hour))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Tasks.Duration).To(Equal(3 * time.Hour))
+			Expect(newIntegrationPipelineRun.Spec.Timeouts.Finally.Duration).To(Equal(2 * time.Hour))
+			Expect(buf.String()).To(ContainSubstring("extending the default pipeline timeout"))
+		})
+
 		It("can add and remove finalizer from IntegrationPipelineRun", func() {
 			var buf bytes.Buffer
 			logEntry := "Removed Finalizer from the PipelineRun"
 
+			// the integration PipelineRun holds the finalizer since its creation
+			Expect(newIntegrationPipelineRun.Finalizers).To(Equal([]string{helpers.IntegrationPipelineRunFinalizer}))
+
 			newIntegrationPipelineRun.WithFinalizer(helpers.IntegrationPipelineRunFinalizer)
-			Expect(newIntegrationPipelineRun.Finalizers).To(ContainElement(ContainSubstring(helpers.IntegrationPipelineRunFinalizer)))
+			Expect(newIntegrationPipelineRun.Finalizers).To(Equal([]string{helpers.IntegrationPipelineRunFinalizer}))
 
 			// calling RemoveFinalizerFromPipelineRun() when the PipelineRun contains the finalizer
 			log := helpers.IntegrationLogger{Logger: buflogr.NewWithBuffer(&buf)}
@@ -414,12 +628,195 @@ var _ = Describe("Integration pipeline", func() {
 				To(Equal(integrationTestScenarioGit.Namespace))
 		})
 
+		It("can mark the IntegrationPipelineRun as triggered by the schedule of the IntegrationTestScenario", func() {
+			Expect(newIntegrationPipelineRun.Labels).NotTo(HaveKey(tekton.ScheduledRunLabel))
+			newIntegrationPipelineRun.WithScheduledRunLabel()
+			Expect(newIntegrationPipelineRun.Labels["test.appstudio.openshift.io/scheduled-run"]).To(Equal("true"))
+		})
+
+		It("can propagate the annotations of the IntegrationTestScenario as sanitized labels of the IntegrationPipelineRun", func() {
+			its := v1beta2.IntegrationTestScenario{}
+			its.Annotations = map[string]string{
+				"unrelated": "unrelated",
+				tekton.PropagatedLabelAnnotationPrefix + "team":                    "integration",
+				tekton.PropagatedLabelAnnotationPrefix + "Example.com/cost-center": "Cost center #1234",
+				tekton.PropagatedLabelAnnotationPrefix + "owner":                   strings.Repeat("a", 70),
+			}
+
+			ipr := tekton.IntegrationPipelineRun{}
+			ipr.WithPropagatedLabels(&its, logr.Discard())
+
+			Expect(ipr.Labels).To(Equal(map[string]string{
+				"team":                    "integration",
+				"example.com/cost-center": "Cost-center-1234",
+				"owner":                   strings.Repeat("a", 63),
+			}))
+		})
+
+		It("refuses to propagate the annotations of the IntegrationTestScenario colliding with the integration labels", func() {
+			its := v1beta2.IntegrationTestScenario{}
+			its.Annotations = map[string]string{
+				tekton.PropagatedLabelAnnotationPrefix + "test.appstudio.openshift.io/scenario":  "other-scenario",
+				tekton.PropagatedLabelAnnotationPrefix + "appstudio.openshift.io/application":    "other-application",
+				tekton.PropagatedLabelAnnotationPrefix + "build.appstudio.redhat.com/commit_sha": "other-sha",
+				tekton.PropagatedLabelAnnotationPrefix + "pipelinesascode.tekton.dev/event-type": "push",
+				tekton.PropagatedLabelAnnotationPrefix + "team":                                  "integration",
+				tekton.PropagatedLabelAnnotationPrefix + "invalid.domain_/name":                  "value",
+			}
+
+			var buf bytes.Buffer
+			newIntegrationPipelineRun.WithIntegrationLabels(integrationTestScenarioGit).
+				WithPropagatedLabels(&its, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Labels["test.appstudio.openshift.io/scenario"]).To(Equal(integrationTestScenarioGit.Name))
+			Expect(newIntegrationPipelineRun.Labels["team"]).To(Equal("integration"))
+			Expect(newIntegrationPipelineRun.Labels).NotTo(HaveKey("appstudio.openshift.io/application"))
+			Expect(newIntegrationPipelineRun.Labels).NotTo(HaveKey("build.appstudio.redhat.com/commit_sha"))
+			Expect(newIntegrationPipelineRun.Labels).NotTo(HaveKey("pipelinesascode.tekton.dev/event-type"))
+			Expect(newIntegrationPipelineRun.Labels).NotTo(HaveKey("invalid.domain_/name"))
+			Expect(buf.String()).To(ContainSubstring("colliding with a label of the integration PipelineRun"))
+			Expect(buf.String()).To(ContainSubstring("can't be propagated as a label"))
+		})
+
+		It("can add the matrix params of the IntegrationTestScenario test to the IntegrationPipelineRun", func() {
+			newIntegrationPipelineRun.WithIntegrationLabels(integrationTestScenarioGit).
+				WithScenarioTest(helpers.ScenarioTest{Name: integrationTestScenarioGit.Name})
+			Expect(newIntegrationPipelineRun.Annotations).NotTo(HaveKey(tekton.TestStatusNameAnnotation))
+			Expect(tekton.GetTestStatusName(newIntegrationPipelineRun.AsPipelineRun())).To(Equal(integrationTestScenarioGit.Name))
+
+			newIntegrationPipelineRun.WithScenarioTest(helpers.ScenarioTest{
+				Name: integrationTestScenarioGit.Name + "[ocp=4.15,arch=arm64]",
+				MatrixParams: []v1beta2.PipelineParameter{
+					{Name: "ocp", Value: "4.15"},
+					{Name: "arch", Value: "arm64"},
+				},
+			})
+			Expect(newIntegrationPipelineRun.Annotations[tekton.TestStatusNameAnnotation]).To(Equal(integrationTestScenarioGit.Name + "[ocp=4.15,arch=arm64]"))
+			Expect(tekton.GetTestStatusName(newIntegrationPipelineRun.AsPipelineRun())).To(Equal(integrationTestScenarioGit.Name + "[ocp=4.15,arch=arm64]"))
+			Expect(newIntegrationPipelineRun.Spec.Params).To(ContainElements(
+				tektonv1.Param{Name: "ocp", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "4.15"}},
+				tektonv1.Param{Name: "arch", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "arm64"}},
+			))
+		})
+
 		It("can append labels that comes from Snapshot to IntegrationPipelineRun and make sure that label value matches the snapshot name", func() {
 			newIntegrationPipelineRun.WithSnapshot(hasSnapshot)
 			Expect(newIntegrationPipelineRun.Labels["appstudio.openshift.io/snapshot"]).
 				To(Equal(hasSnapshot.Name))
 		})
 
+		It("can append the components of the Snapshot as a param to IntegrationPipelineRun", func() {
+			snapshot := hasSnapshot.DeepCopy()
+			snapshot.Spec.Components = append(snapshot.Spec.Components, applicationapiv1alpha1.SnapshotComponent{
+				Name:           "component-git",
+				ContainerImage: "quay.io/redhat-appstudio/component-git@sha256:0a1b2c",
+				Source: applicationapiv1alpha1.ComponentSource{
+					ComponentSourceUnion: applicationapiv1alpha1.ComponentSourceUnion{
+						GitSource: &applicationapiv1alpha1.GitSource{
+							URL:      SampleRepoLink,
+							Revision: "a2ba645d50e471d5f084b",
+						},
+					},
+				},
+			})
+			newIntegrationPipelineRun.WithSnapshot(snapshot)
+
+			Expect(newIntegrationPipelineRun.Spec.Params).To(HaveLen(2))
+			Expect(newIntegrationPipelineRun.Spec.Params[1].Name).To(Equal(tekton.ComponentsParamName))
+			Expect(newIntegrationPipelineRun.Spec.Params[1].Value.Type).To(Equal(tektonv1.ParamTypeString))
+			var components []tekton.SnapshotComponentParam
+			Expect(json.Unmarshal([]byte(newIntegrationPipelineRun.Spec.Params[1].Value.StringVal), &components)).To(Succeed())
+			Expect(components).To(Equal([]tekton.SnapshotComponentParam{
+				{Name: "component-sample", ContainerImage: "testimage"},
+				{Name: "component-git", ContainerImage: "quay.io/redhat-appstudio/component-git@sha256:0a1b2c", GitURL: SampleRepoLink, Revision: "a2ba645d50e471d5f084b"},
+			}))
+		})
+
+		It("omits the git metadata of the components from the param when the Snapshot has too many components", func() {
+			snapshot := hasSnapshot.DeepCopy()
+			snapshot.Spec.Components = nil
+			for i := 0; i < 60; i++ {
+				snapshot.Spec.Components = append(snapshot.Spec.Components, applicationapiv1alpha1.SnapshotComponent{
+					Name:           fmt.Sprintf("component-%d", i),
+					ContainerImage: fmt.Sprintf("quay.io/redhat-appstudio/component-%d@sha256:0a1b2c", i),
+					Source: applicationapiv1alpha1.ComponentSource{
+						ComponentSourceUnion: applicationapiv1alpha1.ComponentSourceUnion{
+							GitSource: &applicationapiv1alpha1.GitSource{
+								URL:      fmt.Sprintf("https://github.com/redhat-appstudio/component-%d", i),
+								Revision: "a2ba645d50e471d5f084b",
+							},
+						},
+					},
+				})
+			}
+			newIntegrationPipelineRun.WithSnapshot(snapshot)
+
+			componentsParam := newIntegrationPipelineRun.Spec.Params[1].Value.StringVal
+			Expect(len(componentsParam)).To(BeNumerically("<=", tekton.MaxComponentsParamSize))
+			var components []tekton.SnapshotComponentParam
+			Expect(json.Unmarshal([]byte(componentsParam), &components)).To(Succeed())
+			Expect(components).To(HaveLen(60))
+			for i, component := range components {
+				Expect(component).To(Equal(tekton.SnapshotComponentParam{
+					Name:           fmt.Sprintf("component-%d", i),
+					ContainerImage: fmt.Sprintf("quay.io/redhat-appstudio/component-%d@sha256:0a1b2c", i),
+					Truncated:      true,
+				}))
+			}
+		})
+
+		DescribeTable("can append the params describing the git event of the Snapshot to IntegrationPipelineRun",
+			func(labels, annotations, expectedParams map[string]string) {
+				snapshot := hasSnapshot.DeepCopy()
+				snapshot.Labels = labels
+				snapshot.Annotations = annotations
+				newIntegrationPipelineRun.WithGitEventParams(snapshot)
+
+				params := map[string]string{}
+				for _, param := range newIntegrationPipelineRun.Spec.Params {
+					Expect(param.Value.Type).To(Equal(tektonv1.ParamTypeString))
+					params[param.Name] = param.Value.StringVal
+				}
+				Expect(params).To(Equal(expectedParams))
+			},
+			Entry("pull request snapshot",
+				map[string]string{
+					"pac.test.appstudio.openshift.io/event-type":   "pull_request",
+					"pac.test.appstudio.openshift.io/pull-request": "42",
+				},
+				map[string]string{
+					"pac.test.appstudio.openshift.io/source-branch": "feature",
+					"pac.test.appstudio.openshift.io/branch":        "main",
+				},
+				map[string]string{
+					tekton.GitSourceBranchParamName:   "feature",
+					tekton.GitTargetBranchParamName:   "main",
+					tekton.EventTypeParamName:         "pull_request",
+					tekton.PullRequestNumberParamName: "42",
+				}),
+			Entry("push snapshot",
+				map[string]string{
+					"pac.test.appstudio.openshift.io/event-type": "push",
+					"build.appstudio.redhat.com/target_branch":   "release-1.0",
+				},
+				map[string]string{
+					"pac.test.appstudio.openshift.io/source-branch": "release-1.0",
+				},
+				map[string]string{
+					tekton.GitSourceBranchParamName:   "release-1.0",
+					tekton.GitTargetBranchParamName:   "release-1.0",
+					tekton.EventTypeParamName:         "push",
+					tekton.PullRequestNumberParamName: "",
+				}),
+			Entry("manually created snapshot", nil, nil,
+				map[string]string{
+					tekton.GitSourceBranchParamName:   "",
+					tekton.GitTargetBranchParamName:   "",
+					tekton.EventTypeParamName:         "",
+					tekton.PullRequestNumberParamName: "",
+				}),
+		)
+
 		It("can append labels coming from Application and Component to IntegrationPipelineRun and making sure that label values matches application and component names", func() {
 			newIntegrationPipelineRun.WithApplicationAndComponent(hasApp, hasComp)
 			Expect(newIntegrationPipelineRun.Labels["appstudio.openshift.io/component"]).
@@ -457,13 +854,38 @@ var _ = Describe("Integration pipeline", func() {
 				},
 			}
 
-			newIntegrationPipelineRun.WithExtraParams(scenarioParams)
+			newIntegrationPipelineRun.WithExtraParams(scenarioParams, logr.Discard())
 			Expect(newIntegrationPipelineRun.Spec.Params[0].Name).To(Equal(scenarioParams[0].Name))
 			Expect(newIntegrationPipelineRun.Spec.Params[0].Value.StringVal).To(Equal(scenarioParams[0].Value))
 			Expect(newIntegrationPipelineRun.Spec.Params[1].Name).To(Equal(scenarioParams[1].Name))
 			Expect(newIntegrationPipelineRun.Spec.Params[1].Value.ArrayVal).To(Equal(scenarioParams[1].Values))
 		})
 
+		It("drops the IntegrationTestScenario parameters colliding with the reserved parameters", func() {
+			var buf bytes.Buffer
+			newIntegrationPipelineRun.WithSnapshot(hasSnapshot).
+				WithExtraParams([]v1beta2.PipelineParameter{
+					{Name: tekton.SnapshotParamName, Value: "custom snapshot"},
+					{Name: tekton.ComponentsParamName, Value: "custom components"},
+					{Name: tekton.NamespaceParamName, Value: "custom namespace"},
+					{Name: "ADDITIONAL_PARAMETER", Value: "custom value"},
+				}, buflogr.NewWithBuffer(&buf))
+
+			Expect(newIntegrationPipelineRun.Spec.Params).To(HaveLen(3))
+			Expect(newIntegrationPipelineRun.Spec.Params[0].Name).To(Equal(tekton.SnapshotParamName))
+			Expect(newIntegrationPipelineRun.Spec.Params[0].Value.StringVal).NotTo(Equal("custom snapshot"))
+			Expect(newIntegrationPipelineRun.Spec.Params[1].Name).To(Equal(tekton.ComponentsParamName))
+			Expect(newIntegrationPipelineRun.Spec.Params[1].Value.StringVal).NotTo(Equal("custom components"))
+			Expect(newIntegrationPipelineRun.Spec.Params[2].Name).To(Equal("ADDITIONAL_PARAMETER"))
+			Expect(buf.String()).To(ContainSubstring("Dropping the IntegrationTestScenario param colliding with a reserved param"))
+			Expect(buf.String()).To(ContainSubstring(tekton.NamespaceParamName))
+		})
+
+		It("ensures the params injected into the pipelineRun are reserved", func() {
+			Expect(v1beta2.ReservedParams).To(ConsistOf(tekton.SnapshotParamName, tekton.ComponentsParamName, tekton.NamespaceParamName,
+				tekton.GitSourceBranchParamName, tekton.GitTargetBranchParamName, tekton.EventTypeParamName, tekton.PullRequestNumberParamName))
+		})
+
 	})
 
 	Context("When managing a new pipelineRun from a bundle-based IntegrationTestScenario", func() {
@@ -501,8 +923,9 @@ var _ = Describe("Integration pipeline", func() {
 			Expect(enterpriseContractPipelineRun.Spec.PipelineRef.ResolverRef.Params).To(HaveLen(3))
 
 			Expect(enterpriseContractPipelineRun.Spec.Params[0].Name).To(Equal("SNAPSHOT"))
-			Expect(enterpriseContractPipelineRun.Spec.Params[1].Name).To(Equal("POLICY_CONFIGURATION"))
-			Expect(enterpriseContractPipelineRun.Spec.Params[1].Value.StringVal).To(Equal("default/default"))
+			Expect(enterpriseContractPipelineRun.Spec.Params[1].Name).To(Equal("COMPONENTS"))
+			Expect(enterpriseContractPipelineRun.Spec.Params[2].Name).To(Equal("POLICY_CONFIGURATION"))
+			Expect(enterpriseContractPipelineRun.Spec.Params[2].Value.StringVal).To(Equal("default/default"))
 		})
 
 		It("copies the annotations", func() {
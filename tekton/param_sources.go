@@ -0,0 +1,131 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ParamValueSourceError is returned when the Secret or ConfigMap referenced by the param of
+// an IntegrationTestScenario doesn't exist or doesn't contain the referenced key
+type ParamValueSourceError struct {
+	Param   string
+	Message string
+}
+
+func (e *ParamValueSourceError) Error() string {
+	return fmt.Sprintf("failed to resolve the value of param %s: %s", e.Param, e.Message)
+}
+
+// IsParamValueSourceError returns true if the given error or any error it wraps is a ParamValueSourceError
+func IsParamValueSourceError(err error) bool {
+	var sourceErr *ParamValueSourceError
+	return errors.As(err, &sourceErr)
+}
+
+// ResolveParamValueSources returns a copy of the given params with the values of the params sourced from
+// Secrets or ConfigMaps read from the given namespace. The params referencing a missing optional key are
+// dropped. A ParamValueSourceError is returned if a required Secret, ConfigMap or key doesn't exist.
+// The resolved values are secret, they must never be logged.
+func ResolveParamValueSources(ctx context.Context, c client.Reader, namespace string, params []v1beta2.PipelineParameter) ([]v1beta2.PipelineParameter, error) {
+	resolvedParams := make([]v1beta2.PipelineParameter, 0, len(params))
+	for _, param := range params {
+		if param.ValueFrom == nil {
+			resolvedParams = append(resolvedParams, param)
+			continue
+		}
+
+		value, found, err := resolveParamValueSource(ctx, c, namespace, param)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			resolvedParams = append(resolvedParams, v1beta2.PipelineParameter{Name: param.Name, Value: value})
+		}
+	}
+
+	return resolvedParams, nil
+}
+
+// resolveParamValueSource returns the value of the key referenced by the valueFrom of the given param and
+// whether it was found. A missing key is only reported as an error when the reference isn't optional.
+func resolveParamValueSource(ctx context.Context, c client.Reader, namespace string, param v1beta2.PipelineParameter) (string, bool, error) {
+	var kind, name, key string
+	var optional *bool
+	var data map[string][]byte
+	switch {
+	case param.ValueFrom.SecretKeyRef != nil:
+		kind, name, key, optional = "secret", param.ValueFrom.SecretKeyRef.Name, param.ValueFrom.SecretKeyRef.Key, param.ValueFrom.SecretKeyRef.Optional
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+		if err != nil {
+			return missingParamValueSource(param.Name, kind, name, namespace, optional, err)
+		}
+		data = secret.Data
+	case param.ValueFrom.ConfigMapKeyRef != nil:
+		kind, name, key, optional = "configmap", param.ValueFrom.ConfigMapKeyRef.Name, param.ValueFrom.ConfigMapKeyRef.Key, param.ValueFrom.ConfigMapKeyRef.Optional
+		configMap := &corev1.ConfigMap{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, configMap)
+		if err != nil {
+			return missingParamValueSource(param.Name, kind, name, namespace, optional, err)
+		}
+		data = make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for k, v := range configMap.BinaryData {
+			data[k] = v
+		}
+		for k, v := range configMap.Data {
+			data[k] = []byte(v)
+		}
+	default:
+		return "", false, &ParamValueSourceError{Param: param.Name, Message: "valueFrom doesn't reference a secret or a configmap"}
+	}
+
+	value, ok := data[key]
+	if ok {
+		return string(value), true, nil
+	}
+	if optional != nil && *optional {
+		return "", false, nil
+	}
+	return "", false, &ParamValueSourceError{
+		Param:   param.Name,
+		Message: fmt.Sprintf("key %s of %s %s doesn't exist in namespace %s", key, kind, name, namespace),
+	}
+}
+
+// missingParamValueSource handles the failure to get the Secret or ConfigMap referenced by the given param.
+// A missing optional object makes the param be dropped, a missing required one is a ParamValueSourceError.
+func missingParamValueSource(param, kind, name, namespace string, optional *bool, err error) (string, bool, error) {
+	if !k8serrors.IsNotFound(err) {
+		return "", false, fmt.Errorf("failed to get %s %s for param %s: %w", kind, name, param, err)
+	}
+	if optional != nil && *optional {
+		return "", false, nil
+	}
+	return "", false, &ParamValueSourceError{
+		Param:   param,
+		Message: fmt.Sprintf("%s %s doesn't exist in namespace %s", kind, name, namespace),
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/tekton"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Param value sources", func() {
+	var (
+		mockClient client.Client
+		optional   = true
+	)
+
+	secretParam := func(name, secretName, key string) v1beta2.PipelineParameter {
+		return v1beta2.PipelineParameter{
+			Name: name,
+			ValueFrom: &v1beta2.ParamValueSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	configMapParam := func(name, configMapName, key string) v1beta2.PipelineParameter {
+		return v1beta2.PipelineParameter{
+			Name: name,
+			ValueFrom: &v1beta2.ParamValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mockClient = fake.NewClientBuilder().WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-farm", Namespace: "default"},
+				Data:       map[string][]byte{"api-key": []byte("secret-api-key")},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-settings", Namespace: "default"},
+				Data:       map[string]string{"region": "eu-west-1"},
+				BinaryData: map[string][]byte{"ca": []byte("ca-bundle")},
+			},
+		).Build()
+	})
+
+	It("resolves the params sourced from secrets and configmaps together with the literal params", func() {
+		params, err := tekton.ResolveParamValueSources(context.Background(), mockClient, "default", []v1beta2.PipelineParameter{
+			{Name: "literal", Value: "literal-value"},
+			secretParam("api-key", "test-farm", "api-key"),
+			{Name: "array", Values: []string{"value1", "value2"}},
+			configMapParam("region", "test-settings", "region"),
+			configMapParam("ca", "test-settings", "ca"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]v1beta2.PipelineParameter{
+			{Name: "literal", Value: "literal-value"},
+			{Name: "api-key", Value: "secret-api-key"},
+			{Name: "array", Values: []string{"value1", "value2"}},
+			{Name: "region", Value: "eu-west-1"},
+			{Name: "ca", Value: "ca-bundle"},
+		}))
+	})
+
+	It("drops the params referencing missing optional keys", func() {
+		missingKey := secretParam("missing-key", "test-farm", "missing")
+		missingKey.ValueFrom.SecretKeyRef.Optional = &optional
+		missingConfigMap := configMapParam("missing-configmap", "missing", "region")
+		missingConfigMap.ValueFrom.ConfigMapKeyRef.Optional = &optional
+
+		params, err := tekton.ResolveParamValueSources(context.Background(), mockClient, "default", []v1beta2.PipelineParameter{
+			{Name: "literal", Value: "literal-value"},
+			missingKey,
+			missingConfigMap,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]v1beta2.PipelineParameter{{Name: "literal", Value: "literal-value"}}))
+	})
+
+	DescribeTable("fails to resolve the params referencing missing required keys",
+		func(param v1beta2.PipelineParameter, expectedError string) {
+			params, err := tekton.ResolveParamValueSources(context.Background(), mockClient, "default", []v1beta2.PipelineParameter{
+				{Name: "literal", Value: "literal-value"},
+				param,
+			})
+			Expect(params).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(tekton.IsParamValueSourceError(err)).To(BeTrue())
+			Expect(tekton.IsParamValueSourceError(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+			Expect(err.Error()).To(Equal(expectedError))
+			Expect(err.Error()).NotTo(ContainSubstring("secret-api-key"))
+		},
+		Entry("missing secret", secretParam("api-key", "missing", "api-key"),
+			"failed to resolve the value of param api-key: secret missing doesn't exist in namespace default"),
+		Entry("missing secret key", secretParam("api-key", "test-farm", "missing"),
+			"failed to resolve the value of param api-key: key missing of secret test-farm doesn't exist in namespace default"),
+		Entry("missing configmap", configMapParam("region", "missing", "region"),
+			"failed to resolve the value of param region: configmap missing doesn't exist in namespace default"),
+		Entry("missing configmap key", configMapParam("region", "test-settings", "missing"),
+			"failed to resolve the value of param region: key missing of configmap test-settings doesn't exist in namespace default"),
+	)
+
+	It("reads the secrets only from the given namespace", func() {
+		_, err := tekton.ResolveParamValueSources(context.Background(), mockClient, "other", []v1beta2.PipelineParameter{
+			secretParam("api-key", "test-farm", "api-key"),
+		})
+		Expect(tekton.IsParamValueSourceError(err)).To(BeTrue())
+	})
+
+	It("doesn't consider other errors to be param value source errors", func() {
+		Expect(tekton.IsParamValueSourceError(fmt.Errorf("failed to get secret"))).To(BeFalse())
+		Expect(tekton.IsParamTemplateError(&tekton.ParamValueSourceError{})).To(BeFalse())
+	})
+})
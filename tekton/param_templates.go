@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+)
+
+const (
+	// paramTemplateStart opens a placeholder in the value of an IntegrationTestScenario param
+	paramTemplateStart = "{{"
+
+	// paramTemplateEnd closes a placeholder in the value of an IntegrationTestScenario param
+	paramTemplateEnd = "}}"
+
+	// paramTemplateEscape prefixes a literal "{{" in the value of an IntegrationTestScenario param
+	paramTemplateEscape = `\`
+)
+
+// componentPlaceholderRegex matches the placeholders referencing a field of a Snapshot component,
+// e.g. component["component-sample"].containerImage
+var componentPlaceholderRegex = regexp.MustCompile(`^component\["([^"]+)"\]\.(.+)$`)
+
+// resultPlaceholderRegex matches the placeholders referencing a result exported by a scenario the tested
+// scenario depends on, e.g. results.provision-cluster.CLUSTER_URL
+var resultPlaceholderRegex = regexp.MustCompile(`^results\.(.+)\.([^.]+)$`)
+
+// ParamTemplateEvent contains the details of the event which triggered the testing of the Snapshot
+type ParamTemplateEvent struct {
+	Type         string
+	TargetBranch string
+	SourceBranch string
+	SHA          string
+	PullRequest  string
+}
+
+// ParamTemplateData contains the values which can be referenced by the placeholders
+// in the param values of an IntegrationTestScenario. The supported placeholders are:
+//
+//	{{ snapshot.name }}, {{ snapshot.namespace }}, {{ application.name }},
+//	{{ component["<name>"].containerImage }}, {{ component["<name>"].source.git.url }},
+//	{{ component["<name>"].source.git.revision }}, {{ event.type }}, {{ event.targetBranch }},
+//	{{ event.sourceBranch }}, {{ event.sha }}, {{ event.pullRequest }}, {{ results.<scenario>.<result> }}
+//
+// A literal "{{" can be written as "\{{".
+type ParamTemplateData struct {
+	Snapshot    *applicationapiv1alpha1.Snapshot
+	Application *applicationapiv1alpha1.Application
+	Event       ParamTemplateEvent
+	// Results are the results exported by the passed tests of the Snapshot, keyed by the name of the test
+	// and the name of the result
+	Results map[string]map[string]string
+}
+
+// ParamTemplateError is returned when the param values of an IntegrationTestScenario can't be expanded
+type ParamTemplateError struct {
+	Param   string
+	Message string
+}
+
+func (e *ParamTemplateError) Error() string {
+	return fmt.Sprintf("failed to expand the value of param %s: %s", e.Param, e.Message)
+}
+
+// IsParamTemplateError returns true if the given error or any error it wraps is a ParamTemplateError
+func IsParamTemplateError(err error) bool {
+	var templateErr *ParamTemplateError
+	return errors.As(err, &templateErr)
+}
+
+// ExpandParamTemplates returns a copy of the given params with the placeholders in their values replaced
+// by the values from the given ParamTemplateData. An error is returned if any of the values contains
+// an unknown or malformed placeholder.
+func ExpandParamTemplates(params []v1beta2.PipelineParameter, data *ParamTemplateData) ([]v1beta2.PipelineParameter, error) {
+	expandedParams := make([]v1beta2.PipelineParameter, 0, len(params))
+	for _, param := range params {
+		expandedParam := v1beta2.PipelineParameter{Name: param.Name, ValueFrom: param.ValueFrom}
+		value, err := expandParamTemplate(param.Value, data)
+		if err != nil {
+			return nil, &ParamTemplateError{Param: param.Name, Message: err.Error()}
+		}
+		expandedParam.Value = value
+		for _, arrayValue := range param.Values {
+			value, err := expandParamTemplate(arrayValue, data)
+			if err != nil {
+				return nil, &ParamTemplateError{Param: param.Name, Message: err.Error()}
+			}
+			expandedParam.Values = append(expandedParam.Values, value)
+		}
+		expandedParams = append(expandedParams, expandedParam)
+	}
+
+	return expandedParams, nil
+}
+
+// expandParamTemplate replaces the placeholders in the given value with the values from the given ParamTemplateData
+func expandParamTemplate(value string, data *ParamTemplateData) (string, error) {
+	var expanded strings.Builder
+	for {
+		start := strings.Index(value, paramTemplateStart)
+		if start < 0 {
+			expanded.WriteString(value)
+			return expanded.String(), nil
+		}
+		if strings.HasSuffix(value[:start], paramTemplateEscape) {
+			expanded.WriteString(value[:start-len(paramTemplateEscape)] + paramTemplateStart)
+			value = value[start+len(paramTemplateStart):]
+			continue
+		}
+		expanded.WriteString(value[:start])
+
+		end := strings.Index(value[start:], paramTemplateEnd)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder %q", value[start:])
+		}
+		placeholder := value[start : start+end+len(paramTemplateEnd)]
+		resolved, err := resolvePlaceholder(strings.TrimSpace(placeholder[len(paramTemplateStart):end]), data)
+		if err != nil {
+			return "", fmt.Errorf("%s in placeholder %q", err, placeholder)
+		}
+		expanded.WriteString(resolved)
+		value = value[start+end+len(paramTemplateEnd):]
+	}
+}
+
+// resolvePlaceholder returns the value referenced by the given placeholder expression
+func resolvePlaceholder(expression string, data *ParamTemplateData) (string, error) {
+	switch expression {
+	case "snapshot.name":
+		return data.Snapshot.Name, nil
+	case "snapshot.namespace":
+		return data.Snapshot.Namespace, nil
+	case "application.name":
+		return data.Application.Name, nil
+	case "event.type":
+		return data.Event.Type, nil
+	case "event.targetBranch":
+		return data.Event.TargetBranch, nil
+	case "event.sourceBranch":
+		return data.Event.SourceBranch, nil
+	case "event.sha":
+		return data.Event.SHA, nil
+	case "event.pullRequest":
+		return data.Event.PullRequest, nil
+	}
+
+	if match := resultPlaceholderRegex.FindStringSubmatch(expression); match != nil {
+		scenarioName, resultName := match[1], match[2]
+		result, ok := data.Results[scenarioName][resultName]
+		if !ok {
+			return "", fmt.Errorf("result %s wasn't exported by scenario %s", resultName, scenarioName)
+		}
+		return result, nil
+	}
+
+	match := componentPlaceholderRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return "", errors.New("unknown placeholder")
+	}
+	componentName, componentField := match[1], match[2]
+	for _, component := range data.Snapshot.Spec.Components {
+		if component.Name != componentName {
+			continue
+		}
+		gitSource := component.Source.GitSource
+		switch componentField {
+		case "containerImage":
+			return component.ContainerImage, nil
+		case "source.git.url", "source.git.revision":
+			if gitSource == nil {
+				return "", fmt.Errorf("component %s has no git source", componentName)
+			}
+			if componentField == "source.git.url" {
+				return gitSource.URL, nil
+			}
+			return gitSource.Revision, nil
+		default:
+			return "", fmt.Errorf("unknown component field %s", componentField)
+		}
+	}
+
+	return "", fmt.Errorf("component %s is not part of the snapshot", componentName)
+}
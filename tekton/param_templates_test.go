@@ -0,0 +1,151 @@
+/*
+Copyright 2024 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton_test
+
+import (
+	"fmt"
+
+	"github.com/konflux-ci/integration-service/api/v1beta2"
+	"github.com/konflux-ci/integration-service/tekton"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Param templates", func() {
+	var data *tekton.ParamTemplateData
+
+	BeforeEach(func() {
+		data = &tekton.ParamTemplateData{
+			Snapshot: &applicationapiv1alpha1.Snapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "snapshot-sample",
+					Namespace: "default",
+				},
+				Spec: applicationapiv1alpha1.SnapshotSpec{
+					Application: "application-sample",
+					Components: []applicationapiv1alpha1.SnapshotComponent{
+						{
+							Name:           "component-sample",
+							ContainerImage: "quay.io/redhat-appstudio/sample-image@sha256:841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40227c1",
+							Source: applicationapiv1alpha1.ComponentSource{
+								ComponentSourceUnion: applicationapiv1alpha1.ComponentSourceUnion{
+									GitSource: &applicationapiv1alpha1.GitSource{
+										URL:      "https://github.com/devfile-samples/devfile-sample-java-springboot-basic",
+										Revision: "a2ba645d50e471d5f084b",
+									},
+								},
+							},
+						},
+						{
+							Name:           "component-without-source",
+							ContainerImage: "quay.io/redhat-appstudio/other-image:latest",
+						},
+					},
+				},
+			},
+			Application: &applicationapiv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "application-sample",
+					Namespace: "default",
+				},
+			},
+			Event: tekton.ParamTemplateEvent{
+				Type:         "pull_request",
+				TargetBranch: "main",
+				SourceBranch: "feature",
+				SHA:          "a2ba645d50e471d5f084b",
+				PullRequest:  "303",
+			},
+			Results: map[string]map[string]string{
+				"provision-cluster": {"CLUSTER_URL": "https://api.cluster.example.com:6443"},
+			},
+		}
+	})
+
+	DescribeTable("expands the placeholders in the param values",
+		func(value, expected string) {
+			params, err := tekton.ExpandParamTemplates([]v1beta2.PipelineParameter{{Name: "param", Value: value}}, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params).To(HaveLen(1))
+			Expect(params[0].Name).To(Equal("param"))
+			Expect(params[0].Value).To(Equal(expected))
+		},
+		Entry("without placeholders", "static-value", "static-value"),
+		Entry("snapshot name", "{{ snapshot.name }}", "snapshot-sample"),
+		Entry("snapshot namespace", "{{snapshot.namespace}}", "default"),
+		Entry("application name", "{{ application.name }}", "application-sample"),
+		Entry("component image", `{{ component["component-sample"].containerImage }}`,
+			"quay.io/redhat-appstudio/sample-image@sha256:841328df1b9f8c4087adbdcfec6cc99ac8308805dea83f6d415d6fb8d40227c1"),
+		Entry("component git url", `{{ component["component-sample"].source.git.url }}`,
+			"https://github.com/devfile-samples/devfile-sample-java-springboot-basic"),
+		Entry("component git revision", `{{ component["component-sample"].source.git.revision }}`, "a2ba645d50e471d5f084b"),
+		Entry("event type", "{{ event.type }}", "pull_request"),
+		Entry("event target branch", "{{ event.targetBranch }}", "main"),
+		Entry("event source branch", "{{ event.sourceBranch }}", "feature"),
+		Entry("event sha", "{{ event.sha }}", "a2ba645d50e471d5f084b"),
+		Entry("event pull request", "{{ event.pullRequest }}", "303"),
+		Entry("exported result", "{{ results.provision-cluster.CLUSTER_URL }}", "https://api.cluster.example.com:6443"),
+		Entry("multiple placeholders", "{{ application.name }}/{{ snapshot.name }}@{{ event.targetBranch }}",
+			"application-sample/snapshot-sample@main"),
+		Entry("escaped braces", `\{{ snapshot.name }}`, "{{ snapshot.name }}"),
+		Entry("escaped and unescaped braces", `\{{ literal }} {{ snapshot.name }}`, "{{ literal }} snapshot-sample"),
+		Entry("closing braces without placeholder", "}} {", "}} {"),
+	)
+
+	It("expands the placeholders in array param values", func() {
+		params, err := tekton.ExpandParamTemplates([]v1beta2.PipelineParameter{
+			{Name: "array-param", Values: []string{"{{ snapshot.name }}", "{{ event.sha }}"}},
+		}, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(HaveLen(1))
+		Expect(params[0].Value).To(BeEmpty())
+		Expect(params[0].Values).To(Equal([]string{"snapshot-sample", "a2ba645d50e471d5f084b"}))
+	})
+
+	DescribeTable("fails to expand invalid placeholders",
+		func(value, expectedError string) {
+			params, err := tekton.ExpandParamTemplates([]v1beta2.PipelineParameter{
+				{Name: "valid", Value: "{{ snapshot.name }}"},
+				{Name: "invalid", Values: []string{"valid", value}},
+			}, data)
+			Expect(params).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(tekton.IsParamTemplateError(err)).To(BeTrue())
+			Expect(tekton.IsParamTemplateError(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+			Expect(err.Error()).To(HavePrefix("failed to expand the value of param invalid"))
+			Expect(err.Error()).To(ContainSubstring(expectedError))
+		},
+		Entry("unknown placeholder", "{{ snapshot.unknown }}", `unknown placeholder in placeholder "{{ snapshot.unknown }}"`),
+		Entry("empty placeholder", "{{}}", "unknown placeholder"),
+		Entry("unterminated placeholder", "{{ snapshot.name", `unterminated placeholder "{{ snapshot.name"`),
+		Entry("unknown component", `{{ component["missing"].containerImage }}`, "component missing is not part of the snapshot"),
+		Entry("unknown component field", `{{ component["component-sample"].name }}`, "unknown component field name"),
+		Entry("result not exported", "{{ results.provision-cluster.KUBECONFIG }}",
+			"result KUBECONFIG wasn't exported by scenario provision-cluster"),
+		Entry("result of scenario without exports", "{{ results.smoke.CLUSTER_URL }}",
+			"result CLUSTER_URL wasn't exported by scenario smoke"),
+		Entry("component without git source", `{{ component["component-without-source"].source.git.url }}`,
+			"component component-without-source has no git source"),
+	)
+
+	It("doesn't consider other errors to be param template errors", func() {
+		Expect(tekton.IsParamTemplateError(fmt.Errorf("failed to create pipelineRun"))).To(BeFalse())
+		Expect(tekton.IsParamTemplateError(nil)).To(BeFalse())
+	})
+})
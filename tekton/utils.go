@@ -147,6 +147,15 @@ func GetTypeFromPipelineRun(object client.Object) (string, error) {
 	return "", fmt.Errorf("the pipelineRun has no type associated with it")
 }
 
+// GetTestStatusName returns the name of the Snapshot test status the integration PipelineRun reports to, the name
+// of the matrix combination of the PipelineRun or the name of its IntegrationTestScenario otherwise.
+func GetTestStatusName(pipelineRun *tektonv1.PipelineRun) string {
+	if testStatusName, found := pipelineRun.GetAnnotations()[TestStatusNameAnnotation]; found {
+		return testStatusName
+	}
+	return pipelineRun.GetLabels()[ScenarioNameLabel]
+}
+
 // GetOutputImage returns a string containing the output-image parameter value from a given PipelineRun.
 func GetOutputImage(object client.Object) (string, error) {
 	pipelineRun, ok := object.(*tektonv1.PipelineRun)